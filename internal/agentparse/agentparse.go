@@ -0,0 +1,129 @@
+// Package agentparse is the registry of pane parsers gt top reads agents'
+// tmux panes with. gt top has parsers built in for Claude Code and
+// OpenCode; another agent gets its own by implementing PaneParser and
+// registering it from an init function:
+//
+//	func init() {
+//		agentparse.Register("aider", aiderParser{})
+//	}
+//
+// A registered parser is tried before the built-in ones, both to recognize
+// a pane and to parse it, so it can also replace a built-in parser by
+// registering under its type ("claude", "opencode").
+package agentparse
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Status is what a pane parser read from one capture of an agent's pane.
+// Zero values mean the pane didn't show it.
+type Status struct {
+	Status        string // one-line status, e.g. "Thinking… (12s)"
+	Tool          string // tool running now, e.g. "Bash(go test ./...)"
+	Busy          bool   // the pane shows the agent working, whatever the timestamps say
+	WaitingReason string // what it waits on a human for, e.g. "permission"; "" when not waiting
+	RateLimited   bool   // retrying after a temporary API rate limit
+	APIError      bool   // the last request failed with an API error
+	HitLimit      bool   // stopped at a usage or billing cap
+	LimitReset    string // when the cap resets, as the agent put it
+	ContextLeft   int    // percent of the context window left before compaction, 0 when unknown
+	SessionLimit  int    // percent of the session usage limit used, 0 when unknown
+	SessionReset  string // when the session limit resets, as the agent put it
+}
+
+// PaneParser reads one kind of agent's state from its pane. Lines are the
+// captured pane, top to bottom. Both methods may be called from several
+// goroutines and must not keep the lines.
+type PaneParser interface {
+	// Detect reports whether the pane is this kind of agent's. It's asked
+	// on a session's first poll unless the session's tmux environment names
+	// its agent (GT_AGENT); the type it settles on sticks for the session.
+	Detect(lines []string) bool
+	// Parse reads the agent's state from the pane.
+	Parse(lines []string) Status
+}
+
+// DepthParser is a PaneParser that reads more (or less) scrollback above
+// the visible pane than gt top's default of 10 lines, e.g. for a sidebar or
+// limit notice that scrolls up. gt top caps it at 100.
+type DepthParser interface {
+	PaneParser
+	CaptureDepth() int
+}
+
+type entry struct {
+	agentType string
+	parser    PaneParser
+}
+
+var (
+	mu      sync.RWMutex
+	parsers []entry // in registration order
+)
+
+// Register makes p the parser for agentType panes, replacing any registered
+// before. It panics on an empty type or a nil parser.
+func Register(agentType string, p PaneParser) {
+	if agentType == "" || p == nil {
+		panic(fmt.Sprintf("agentparse: Register(%q, %v): need an agent type and a parser", agentType, p))
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for i, e := range parsers {
+		if e.agentType == agentType {
+			parsers[i].parser = p
+			return
+		}
+	}
+	parsers = append(parsers, entry{agentType, p})
+}
+
+// Unregister removes the parser for agentType, if any.
+func Unregister(agentType string) {
+	mu.Lock()
+	defer mu.Unlock()
+	for i, e := range parsers {
+		if e.agentType == agentType {
+			parsers = append(parsers[:i:i], parsers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Lookup returns the parser registered for agentType.
+func Lookup(agentType string) (PaneParser, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, e := range parsers {
+		if e.agentType == agentType {
+			return e.parser, true
+		}
+	}
+	return nil, false
+}
+
+// Detect returns the type of the first registered parser, in registration
+// order, that recognizes the pane, or "" if none does.
+func Detect(lines []string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, e := range parsers {
+		if e.parser.Detect(lines) {
+			return e.agentType
+		}
+	}
+	return ""
+}
+
+// Types lists the registered agent types in registration order.
+func Types() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	types := make([]string, len(parsers))
+	for i, e := range parsers {
+		types[i] = e.agentType
+	}
+	return types
+}
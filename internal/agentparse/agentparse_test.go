@@ -0,0 +1,70 @@
+package agentparse
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+type markerParser struct {
+	marker string
+	status string
+}
+
+func (p markerParser) Detect(lines []string) bool {
+	return slices.ContainsFunc(lines, func(l string) bool { return strings.Contains(l, p.marker) })
+}
+
+func (p markerParser) Parse([]string) Status { return Status{Status: p.status} }
+
+func TestRegistry(t *testing.T) {
+	t.Cleanup(func() {
+		Unregister("aider")
+		Unregister("goose")
+	})
+	Register("aider", markerParser{"aider v0", "first"})
+	Register("goose", markerParser{"goose>", "goose"})
+	Register("aider", markerParser{"aider v0", "replaced"})
+
+	if got := Types(); !slices.Equal(got, []string{"aider", "goose"}) {
+		t.Errorf("Types() = %v, want registration order with the replacement in place", got)
+	}
+	p, ok := Lookup("aider")
+	if !ok || p.Parse(nil).Status != "replaced" {
+		t.Errorf("Lookup(aider) = %v, %v; want the replacement", p, ok)
+	}
+	if _, ok := Lookup("claude"); ok {
+		t.Error("Lookup found an unregistered type")
+	}
+
+	if got := Detect([]string{"$ goose>", "aider v0.80"}); got != "aider" {
+		t.Errorf("Detect = %q, want the first registered match", got)
+	}
+	if got := Detect([]string{"goose> hi"}); got != "goose" {
+		t.Errorf("Detect = %q, want goose", got)
+	}
+	if got := Detect([]string{"plain shell"}); got != "" {
+		t.Errorf("Detect = %q for a pane no parser knows", got)
+	}
+
+	Unregister("aider")
+	if got := Types(); !slices.Equal(got, []string{"goose"}) {
+		t.Errorf("after Unregister, Types() = %v", got)
+	}
+}
+
+func TestRegister_Invalid(t *testing.T) {
+	for _, tt := range []struct {
+		agentType string
+		parser    PaneParser
+	}{{"", markerParser{}}, {"aider", nil}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%q, %v) didn't panic", tt.agentType, tt.parser)
+				}
+			}()
+			Register(tt.agentType, tt.parser)
+		}()
+	}
+}
@@ -182,7 +182,8 @@ type Issue struct {
 	Blocks      []string `json:"blocks,omitempty"`
 	BlockedBy   []string `json:"blocked_by,omitempty"`
 	Labels      []string `json:"labels,omitempty"`
-	Ephemeral   bool     `json:"ephemeral,omitempty"` // Wisp/ephemeral issues, not synced to git
+	Ephemeral   bool     `json:"ephemeral,omitempty"`    // Wisp/ephemeral issues, not synced to git
+	ExternalRef string   `json:"external_ref,omitempty"` // Link to an external tracker, e.g. a GitHub issue URL
 
 	// Content fields (parsed from bd show --json)
 	AcceptanceCriteria string `json:"acceptance_criteria,omitempty"`
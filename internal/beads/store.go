@@ -111,6 +111,9 @@ func sdkIssueToIssue(si *beadsdk.Issue) *Issue {
 	if si.ClosedAt != nil {
 		issue.ClosedAt = si.ClosedAt.Format(time.RFC3339)
 	}
+	if si.ExternalRef != nil {
+		issue.ExternalRef = *si.ExternalRef
+	}
 
 	// Populate dependency-derived fields from the SDK issue's Dependencies.
 	// The SDK issue may have Dependencies populated (from show) or not (from list).
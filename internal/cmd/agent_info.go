@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tui/activity"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// agentInfoTailMax bounds how much of the events file is searched for the
+// agent's recent events.
+const agentInfoTailMax = 4 * 1024 * 1024
+
+var (
+	agentInfoJSON   bool
+	agentInfoEvents int
+)
+
+var agentsInfoCmd = &cobra.Command{
+	Use:   "info <session>",
+	Short: "Show everything known about one agent",
+	Long: `Show everything known about one agent: its role and agent type, activity
+level, running tool, hooked bead, usage limits, worktree and branch, uptime,
+and its most recent events.
+
+The agent is named by tmux session or agent ID. Level, tool, limits and
+context come from a running gt top (its .runtime/top-status.json); without
+one, only what tmux knows is shown.
+
+Examples:
+  gt agent info gt-gastown-Toast
+  gt agent info gastown/witness --json | jq .level`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSessionNames,
+	RunE:              runAgentsInfo,
+}
+
+func init() {
+	agentsInfoCmd.Flags().BoolVar(&agentInfoJSON, "json", false, "Output as JSON")
+	agentsInfoCmd.Flags().IntVar(&agentInfoEvents, "events", 10, "How many recent events to show")
+	agentsCmd.AddCommand(agentsInfoCmd)
+}
+
+// agentInfo is the gt agent info report.
+type agentInfo struct {
+	activity.AgentStatus
+	Worktree     string         `json:"worktree,omitempty"`
+	Branch       string         `json:"branch,omitempty"`
+	Uptime       string         `json:"uptime,omitempty"`
+	RecentEvents []events.Event `json:"recent_events"`
+}
+
+func runAgentsInfo(cmd *cobra.Command, args []string) error {
+	status, err := loadAgentStatus()
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+	st := findAgentStatus(status, args[0])
+	if st == nil {
+		return fmt.Errorf("no agent session %q", args[0])
+	}
+
+	info := agentInfo{AgentStatus: *st, RecentEvents: []events.Event{}}
+	t := tmux.NewTmux()
+	if info.AgentType == "" {
+		info.AgentType, _ = t.GetEnvironment(st.Session, "GT_AGENT")
+	}
+	if dir, err := t.GetPaneWorkDir(st.Session); err == nil {
+		info.Worktree = dir
+		info.Branch, _ = git.NewGit(dir).CurrentBranch()
+	}
+	if !st.StartedAt.IsZero() {
+		info.Uptime = formatDuration(time.Since(st.StartedAt).Truncate(time.Second))
+	}
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		info.RecentEvents = recentAgentEvents(townRoot, st, agentInfoEvents)
+	}
+
+	if agentInfoJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+	printAgentInfo(os.Stdout, info, time.Now())
+	return nil
+}
+
+// findAgentStatus returns the agent with the given session name or agent
+// ID, or nil.
+func findAgentStatus(s *activity.Status, name string) *activity.AgentStatus {
+	name = strings.TrimSuffix(name, "/")
+	for i := range s.Agents {
+		if s.Agents[i].Session == name || s.Agents[i].AgentID == name {
+			return &s.Agents[i]
+		}
+	}
+	return nil
+}
+
+// agentAddresses returns the addresses events may use for an agent: its
+// session, its agent ID, and the <rig>/<name> short form.
+func agentAddresses(st *activity.AgentStatus) []string {
+	addrs := []string{st.Session}
+	if st.AgentID != "" {
+		addrs = append(addrs, st.AgentID)
+		if parts := strings.Split(st.AgentID, "/"); len(parts) == 3 {
+			addrs = append(addrs, parts[0]+"/"+parts[2])
+		}
+	}
+	return addrs
+}
+
+// eventConcerns reports whether an event was raised by the agent or names
+// it in its payload (e.g. a nudge's target or a sling's polecat).
+func eventConcerns(ev *events.Event, addrs []string, rig, name string) bool {
+	matches := func(v string) bool {
+		v = strings.TrimSuffix(v, "/")
+		for _, a := range addrs {
+			if v == a {
+				return true
+			}
+		}
+		return false
+	}
+	if matches(ev.Actor) {
+		return true
+	}
+	evRig, _ := ev.Payload["rig"].(string)
+	for _, key := range []string{"session", "target", "polecat", "agent", "agent_id"} {
+		v, _ := ev.Payload[key].(string)
+		if v == "" {
+			continue
+		}
+		if matches(v) || (name != "" && v == name && evRig == rig) {
+			return true
+		}
+	}
+	return false
+}
+
+// recentAgentEvents returns the last n events concerning the agent, oldest
+// first, from the tail of the town's events file. Events that fail
+// signature verification are skipped.
+func recentAgentEvents(townRoot string, st *activity.AgentStatus, n int) []events.Event {
+	found := []events.Event{}
+	if n <= 0 {
+		return found
+	}
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		return found
+	}
+	defer f.Close()
+	skipFirst := false
+	if info, err := f.Stat(); err == nil && info.Size() > agentInfoTailMax {
+		if _, err := f.Seek(info.Size()-agentInfoTailMax, io.SeekStart); err != nil {
+			return found
+		}
+		skipFirst = true // starts mid-line
+	}
+
+	addrs := agentAddresses(st)
+	var name string
+	if i := strings.LastIndex(st.AgentID, "/"); i >= 0 {
+		name = st.AgentID[i+1:]
+	}
+	verify := events.NewVerifier(townRoot)
+	scanner := events.NewScanner(f)
+	for scanner.Scan() {
+		if skipFirst {
+			skipFirst = false
+			continue
+		}
+		var ev events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil || !eventConcerns(&ev, addrs, st.Rig, name) || !verify.Valid(scanner.Bytes()) {
+			continue
+		}
+		found = append(found, ev)
+		if len(found) > n {
+			found = found[1:]
+		}
+	}
+	return found
+}
+
+// printAgentInfo renders the human-readable report.
+func printAgentInfo(w io.Writer, info agentInfo, now time.Time) {
+	title := info.Session
+	if info.AgentID != "" {
+		title += "  " + style.Dim.Render("("+info.AgentID+")")
+	}
+	fmt.Fprintln(w, style.Bold.Render(title))
+
+	field := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "  %-10s %s\n", label+":", value)
+		}
+	}
+	role := info.Role
+	if info.Rig != "" {
+		role += " in " + info.Rig
+	}
+	field("Role", role)
+	field("Agent", info.AgentType)
+	level := info.Level
+	if level == "" {
+		level = "- (gt top not running)"
+	}
+	switch {
+	case info.Muted:
+		level += " (muted)"
+	case info.ScheduledOff:
+		level += " (scheduled off)"
+	}
+	field("Level", level)
+	if !info.LastActivity.IsZero() {
+		field("Activity", formatDuration(now.Sub(info.LastActivity).Truncate(time.Second))+" ago")
+	}
+	field("Tool", info.Tool)
+	field("Bead", info.WorkBead)
+	limit := info.Limit
+	if limit != "" && info.LimitReset != "" {
+		limit += ", " + info.LimitReset
+	}
+	field("Limit", limit)
+	if info.ContextUsed > 0 {
+		field("Context", fmt.Sprintf("%d%% used", info.ContextUsed))
+	}
+	field("Repo", info.Repo)
+	field("Worktree", info.Worktree)
+	field("Branch", info.Branch)
+	field("Uptime", info.Uptime)
+
+	if len(info.RecentEvents) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s\n", style.Bold.Render("Recent events"))
+	for _, ev := range info.RecentEvents {
+		at := ev.Timestamp
+		if ts, err := time.Parse(time.RFC3339, ev.Timestamp); err == nil {
+			at = ts.Local().Format("01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "  %s  %-18s %s\n", style.Dim.Render(at), ev.Type, ev.Actor)
+	}
+}
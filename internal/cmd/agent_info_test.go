@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/tui/activity"
+)
+
+func TestFindAgentStatus(t *testing.T) {
+	s := &activity.Status{Agents: []activity.AgentStatus{
+		{Session: "gt-gastown-witness", AgentID: "gastown/witness"},
+		{Session: "gt-gastown-Toast", AgentID: "gastown/polecats/Toast"},
+	}}
+	if a := findAgentStatus(s, "gt-gastown-Toast"); a == nil || a.AgentID != "gastown/polecats/Toast" {
+		t.Errorf("by session: %+v", a)
+	}
+	if a := findAgentStatus(s, "gastown/witness/"); a == nil || a.Session != "gt-gastown-witness" {
+		t.Errorf("by agent ID: %+v", a)
+	}
+	if a := findAgentStatus(s, "gt-gastown-Nux"); a != nil {
+		t.Errorf("unknown session found %+v", a)
+	}
+}
+
+func TestRecentAgentEvents(t *testing.T) {
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, events.EventsFile)
+	appendEvents(t, path,
+		events.Event{Type: "sling", Actor: "mayor", Payload: map[string]interface{}{"rig": "gastown", "polecat": "Toast"}},
+		events.Event{Type: "done", Actor: "gastown/polecats/Nux"},
+		events.Event{Type: "polecat_nudged", Actor: "gastown/witness", Payload: map[string]interface{}{"rig": "gastown", "target": "Toast"}},
+		events.Event{Type: "done", Actor: "gastown/Toast"},
+		events.Event{Type: "agent_restarted", Actor: "overseer", Payload: map[string]interface{}{"session": "gt-gastown-Toast"}},
+	)
+	st := &activity.AgentStatus{Session: "gt-gastown-Toast", AgentID: "gastown/polecats/Toast", Rig: "gastown"}
+
+	var types []string
+	for _, ev := range recentAgentEvents(townRoot, st, 3) {
+		types = append(types, ev.Type)
+	}
+	if got := strings.Join(types, ","); got != "polecat_nudged,done,agent_restarted" {
+		t.Errorf("recent events = %s, want the last three about Toast", got)
+	}
+	if evs := recentAgentEvents(t.TempDir(), st, 3); evs == nil || len(evs) != 0 {
+		t.Errorf("missing file: %v, want an empty list", evs)
+	}
+}
+
+func TestPrintAgentInfo(t *testing.T) {
+	now := time.Now()
+	info := agentInfo{AgentStatus: activity.AgentStatus{
+		Session: "gt-gastown-Toast", AgentID: "gastown/polecats/Toast", Rig: "gastown", Role: "polecat",
+		Level: "hit_limit", LastActivity: now.Add(-90 * time.Second), Limit: "usage_limit", LimitReset: "resets 2pm",
+		ContextUsed: 40,
+	}, Branch: "polecat/Toast"}
+
+	var buf bytes.Buffer
+	printAgentInfo(&buf, info, now)
+	out := buf.String()
+	for _, want := range []string{"polecat in gastown", "hit_limit", "1m 30s ago", "usage_limit, resets 2pm", "40% used", "polecat/Toast"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Tool:") || strings.Contains(out, "Recent events") {
+		t.Errorf("empty fields shown:\n%s", out)
+	}
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tui/activity"
+)
+
+var (
+	agentRestartGrace    time.Duration
+	agentRestartContinue bool
+	agentRestartDryRun   bool
+)
+
+var agentsRestartCmd = &cobra.Command{
+	Use:   "restart <session>",
+	Short: "Restart an agent inside its existing tmux session",
+	Long: `Restart the agent running in a tmux session without killing the session.
+
+The agent is asked to exit (Ctrl-C twice) and given --grace to do so; any
+processes still running after that are killed. The pane is then respawned
+with the agent's role configuration: the same working directory, GT_ROLE,
+and agent (GT_AGENT from the session environment, else the role's
+configured agent). Attached clients, window layout, and the session's
+environment survive.
+
+An agent_restarted event is emitted so gt top drops the agent's sticky
+state (needs-human, limits, loop history) at once.
+
+Examples:
+  gt agents restart gt-gastown-crew-max
+  gt agents restart hq-deacon --continue   # resume the previous conversation`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentsRestart,
+}
+
+func init() {
+	agentsRestartCmd.Flags().DurationVar(&agentRestartGrace, "grace", 5*time.Second, "How long to wait for the agent to exit before killing it")
+	agentsRestartCmd.Flags().BoolVar(&agentRestartContinue, "continue", false, "Resume the agent's previous conversation instead of starting fresh")
+	agentsRestartCmd.Flags().BoolVarP(&agentRestartDryRun, "dry-run", "n", false, "Show what would be done without restarting")
+	agentsCmd.AddCommand(agentsRestartCmd)
+}
+
+func runAgentsRestart(cmd *cobra.Command, args []string) error {
+	sessionName := args[0]
+	t := tmux.NewTmux()
+
+	exists, err := t.HasSession(sessionName)
+	if err != nil {
+		return fmt.Errorf("checking session: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("session '%s' not found", sessionName)
+	}
+
+	restartCmd, err := buildRestartCommandWithOpts(sessionName, buildRestartCommandOpts{
+		ContinueSession: agentRestartContinue,
+		ContinuePrompt:  "You were restarted from gt agents restart. Continue your previous task.",
+		Topic:           "restart",
+		SessionAgent:    true,
+	})
+	if err != nil {
+		return err
+	}
+
+	pane, err := getSessionPane(sessionName)
+	if err != nil {
+		return fmt.Errorf("getting pane: %w", err)
+	}
+
+	if agentRestartDryRun {
+		fmt.Printf("Would stop the agent in %s (pane %s), waiting up to %s\n", sessionName, pane, agentRestartGrace)
+		fmt.Printf("Would execute: tmux respawn-pane -k -t %s %s\n", pane, restartCmd)
+		return nil
+	}
+
+	fmt.Printf("%s Restarting %s...\n", style.Bold.Render("↻"), sessionName)
+
+	// Keep the pane (and so the session) alive while its process is gone.
+	if err := t.SetRemainOnExit(pane, true); err != nil {
+		style.PrintWarning("could not set remain-on-exit: %v", err)
+	}
+	if !stopAgentGracefully(t, sessionName, agentRestartGrace) {
+		fmt.Printf("  agent did not exit within %s; killing it\n", agentRestartGrace)
+	}
+	// Also reaps children the agent left behind on a clean exit.
+	if err := t.KillPaneProcesses(pane); err != nil {
+		style.PrintWarning("could not kill pane processes: %v", err)
+	}
+	if err := t.ClearHistory(pane); err != nil {
+		style.PrintWarning("could not clear history: %v", err)
+	}
+	if err := t.RespawnPane(pane, restartCmd); err != nil {
+		return fmt.Errorf("respawning pane: %w", err)
+	}
+
+	emitAgentRestarted(t, sessionName)
+	fmt.Printf("%s Restarted %s\n", style.Bold.Render("✓"), sessionName)
+	return nil
+}
+
+// stopAgentGracefully asks the agent to exit the way a human would (Ctrl-C
+// twice, which quits Claude Code and most TUIs) and waits up to grace for it
+// to go. It reports whether the agent exited on its own.
+func stopAgentGracefully(t *tmux.Tmux, sessionName string, grace time.Duration) bool {
+	for i := 0; i < 2; i++ {
+		_ = t.SendKeysRaw(sessionName, "C-c")
+		time.Sleep(200 * time.Millisecond)
+	}
+	deadline := time.Now().Add(grace)
+	for {
+		if !t.IsAgentAlive(sessionName) {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+// emitAgentRestarted logs agent_restarted for the session, matched by gt top
+// on the session's GT_AGENT_ID, and wakes running gt top instances.
+func emitAgentRestarted(t *tmux.Tmux, sessionName string) {
+	townRoot := detectTownRootFromCwd()
+	if townRoot == "" {
+		return
+	}
+	agentID, _ := t.GetEnvironment(sessionName, "GT_AGENT_ID")
+	payload := events.AgentRestartedPayload(sessionName, agentID)
+	if err := events.LogInTown(townRoot, "gt", events.TypeAgentRestarted, detectActor(), payload, events.VisibilityFeed); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: emitting agent_restarted: %v\n", err)
+	}
+	activity.SignalWake()
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestAgentRestartCmd_Registered(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"agents", "restart"})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if cmd != agentsRestartCmd {
+		t.Fatalf("found %q, want agents restart", cmd.CommandPath())
+	}
+	if err := cmd.Args(cmd, nil); err == nil {
+		t.Error("restart without a session should be rejected")
+	}
+}
+
+func TestAgentRestartedPayload(t *testing.T) {
+	p := events.AgentRestartedPayload("gt-gastown-crew-max", "gastown/crew/max")
+	if p["session"] != "gt-gastown-crew-max" || p["agent_id"] != "gastown/crew/max" {
+		t.Errorf("payload = %v", p)
+	}
+	if _, ok := events.AgentRestartedPayload("s", "")["agent_id"]; ok {
+		t.Error("empty agent ID should be omitted")
+	}
+}
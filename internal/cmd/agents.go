@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/constants"
@@ -15,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tui/activity"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -82,7 +84,33 @@ var agentsCmd = &cobra.Command{
 Shows Mayor, Deacon, Witnesses, Refineries, and Crew workers.
 Polecats are hidden (use 'gt polecat list' to see them).
 
-Use 'gt agents menu' for an interactive tmux popup menu.`,
+Use 'gt agents menu' for an interactive tmux popup menu.
+
+With --stale, lists agents (polecats included) with no output for at least
+the given duration instead, longest idle first, one per line:
+
+  <agent-id> <TAB> <idle> <TAB> <level> <TAB> <session>
+
+Levels come from a running gt top (its .runtime/top-status.json); without
+one, activity is read from tmux and the level is "-". Agents gt top sees as
+waiting for a human or at a usage limit are omitted, since a nudge can't
+unblock them, and so are agents muted in gt top or waiting on the merge
+queue. For witness patrols:
+
+  gt agents --stale 10m --rig gastown
+
+With --attention, lists the agents that need someone to act, for the
+mayor to reassign work away from, most urgent first:
+
+  <agent-id> <TAB> <level> <TAB> <reason> <TAB> <session>
+
+That is, agents whose process exited, at a usage limit, waiting for a
+human (with what for), or stuck: cold in gt top, or idle 30m without one.
+Muted and scheduled-off agents are left out, and an idle agent whose work
+waits in the merge queue isn't stuck. --json prints the full status
+entries with a "reason" field:
+
+  gt agents --attention --json`,
 	RunE: runAgentsList,
 }
 
@@ -134,11 +162,21 @@ For collisions with live processes, you must manually:
 var (
 	agentsAllFlag   bool
 	agentsCheckJSON bool
+	agentsStale     time.Duration
+	agentsStaleRig  string
+	agentsAttention bool
+	agentsJSON      bool
 )
 
 func init() {
 	agentsCmd.PersistentFlags().BoolVarP(&agentsAllFlag, "all", "a", false, "Include polecats in the menu")
 	agentsCheckCmd.Flags().BoolVar(&agentsCheckJSON, "json", false, "Output as JSON")
+	agentsCmd.PersistentFlags().DurationVar(&agentsStale, "stale", 0, "List agents idle at least this long (e.g. 10m)")
+	agentsCmd.PersistentFlags().StringVar(&agentsStaleRig, "rig", "", "With --stale or --attention, only list agents in this rig")
+	agentsCmd.Flags().BoolVar(&agentsAttention, "attention", false, "List agents needing attention (dead, limited, waiting, stuck) with reasons")
+	agentsCmd.Flags().BoolVar(&agentsJSON, "json", false, "With --attention, output as JSON")
+
+	_ = agentsCmd.RegisterFlagCompletionFunc("rig", completeRigNames)
 
 	agentsCmd.AddCommand(agentsListCmd)
 	agentsCmd.AddCommand(agentsMenuCmd)
@@ -538,6 +576,12 @@ func runAgents(cmd *cobra.Command, args []string) error {
 }
 
 func runAgentsList(cmd *cobra.Command, args []string) error {
+	if agentsStale > 0 {
+		return runAgentsStale()
+	}
+	if agentsAttention {
+		return runAgentsAttention()
+	}
 	agents, err := getAgentSessions(agentsAllFlag)
 	if err != nil {
 		return fmt.Errorf("listing sessions: %w", err)
@@ -579,6 +623,84 @@ func runAgentsList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runAgentsStale prints agents idle for at least agentsStale, preferring
+// gt top's status file over raw tmux activity.
+func runAgentsStale() error {
+	status, err := loadAgentStatus()
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+	for _, a := range status.Stale(agentsStale, time.Now()) {
+		if agentsStaleRig != "" && a.Rig != agentsStaleRig {
+			continue
+		}
+		fmt.Println(formatStaleAgent(a, time.Now()))
+	}
+	return nil
+}
+
+// runAgentsAttention prints the agents needing attention, preferring gt
+// top's status file over raw tmux activity.
+func runAgentsAttention() error {
+	status, err := loadAgentStatus()
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+	list := []activity.Attention{}
+	for _, a := range status.Attention(time.Now()) {
+		if agentsStaleRig == "" || a.Rig == agentsStaleRig {
+			list = append(list, a)
+		}
+	}
+	if agentsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+	for _, a := range list {
+		fmt.Println(formatAttention(a))
+	}
+	return nil
+}
+
+// formatAttention renders one tab-separated --attention line.
+func formatAttention(a activity.Attention) string {
+	id := a.AgentID
+	if id == "" {
+		id = a.Session
+	}
+	level := a.Level
+	if level == "" {
+		level = "-"
+	}
+	return strings.Join([]string{id, level, a.Reason, a.Session}, "\t")
+}
+
+// loadAgentStatus returns gt top's published status if it is fresh,
+// otherwise a status read directly from tmux.
+func loadAgentStatus() (*activity.Status, error) {
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if s, err := activity.ReadStatus(townRoot); err == nil && time.Since(s.UpdatedAt) < activity.StatusMaxAge {
+			return s, nil
+		}
+	}
+	return activity.TmuxStatus()
+}
+
+// formatStaleAgent renders one tab-separated --stale line.
+func formatStaleAgent(a activity.AgentStatus, now time.Time) string {
+	id := a.AgentID
+	if id == "" {
+		id = a.Session
+	}
+	level := a.Level
+	if level == "" {
+		level = "-"
+	}
+	idle := now.Sub(a.LastActivity).Truncate(time.Second)
+	return strings.Join([]string{id, idle.String(), level, a.Session}, "\t")
+}
+
 // CollisionReport holds the results of a collision check.
 type CollisionReport struct {
 	TotalSessions int                       `json:"total_sessions"`
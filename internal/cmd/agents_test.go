@@ -9,9 +9,11 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tui/activity"
 )
 
 func TestAgentsCmd_DefaultRunE(t *testing.T) {
@@ -629,3 +631,30 @@ func TestGuessSessionFromWorkerDir(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatStaleAgent(t *testing.T) {
+	now := time.Now()
+	got := formatStaleAgent(activity.AgentStatus{
+		Session: "gt-gastown-Toast", AgentID: "gastown/polecats/Toast",
+		Level: "cold", LastActivity: now.Add(-12*time.Minute - 300*time.Millisecond),
+	}, now)
+	if want := "gastown/polecats/Toast\t12m0s\tcold\tgt-gastown-Toast"; got != want {
+		t.Errorf("formatStaleAgent = %q, want %q", got, want)
+	}
+
+	// Without an agent ID or gt top level, fall back to the session and "-".
+	got = formatStaleAgent(activity.AgentStatus{Session: "hq-mayor", LastActivity: now.Add(-time.Hour)}, now)
+	if want := "hq-mayor\t1h0m0s\t-\thq-mayor"; got != want {
+		t.Errorf("formatStaleAgent = %q, want %q", got, want)
+	}
+}
+
+func TestFormatAttention(t *testing.T) {
+	got := formatAttention(activity.Attention{
+		AgentStatus: activity.AgentStatus{Session: "gt-gastown-Toast", AgentID: "gastown/polecats/Toast", Level: "hit_limit"},
+		Reason:      "usage limit, resets 2pm",
+	})
+	if want := "gastown/polecats/Toast\thit_limit\tusage limit, resets 2pm\tgt-gastown-Toast"; got != want {
+		t.Errorf("formatAttention = %q, want %q", got, want)
+	}
+}
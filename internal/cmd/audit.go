@@ -13,6 +13,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/townlog"
@@ -25,6 +26,8 @@ var (
 	auditSince string
 	auditLimit int
 	auditJSON  bool
+
+	auditVerifyEvents bool
 )
 
 var auditCmd = &cobra.Command{
@@ -46,7 +49,13 @@ Examples:
   gt audit --actor=mayor                  # Show mayor's activity
   gt audit --since=24h                    # Show all activity in last 24h
   gt audit --actor=joe --since=1h         # Combined filters
-  gt audit --json                         # Output as JSON`,
+  gt audit --json                         # Output as JSON
+  gt audit --verify-events                # Check event signatures
+
+When the town signs events (town settings event_signing), events entries
+with a missing or bad signature are marked in the timeline, and
+--verify-events checks every line of the events log, listing forged or
+altered events. It exits 1 when any are found.`,
 	RunE: runAudit,
 }
 
@@ -55,6 +64,7 @@ func init() {
 	auditCmd.Flags().StringVar(&auditSince, "since", "", "Show events since duration (e.g., 1h, 24h, 7d)")
 	auditCmd.Flags().IntVarP(&auditLimit, "limit", "n", 50, "Maximum number of entries to show")
 	auditCmd.Flags().BoolVar(&auditJSON, "json", false, "Output as JSON")
+	auditCmd.Flags().BoolVar(&auditVerifyEvents, "verify-events", false, "Check every event's signature and list forged or altered events")
 
 	rootCmd.AddCommand(auditCmd)
 }
@@ -75,6 +85,9 @@ func runAudit(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
+	if auditVerifyEvents {
+		return runVerifyEvents(townRoot)
+	}
 
 	// Parse since duration if provided
 	var sinceTime time.Time
@@ -415,12 +428,17 @@ func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, e
 	}
 	defer file.Close()
 
+	verify := events.NewVerifier(townRoot)
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		var e events.Event
 		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
 			continue // Skip malformed lines
 		}
+		var details string
+		if !verify.Valid(scanner.Bytes()) {
+			details = "UNVERIFIED: missing or bad signature"
+		}
 
 		// Apply actor filter
 		if actor != "" && !matchesActor(e.Actor, actor) {
@@ -441,12 +459,58 @@ func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, e
 			Type:      e.Type,
 			Actor:     e.Actor,
 			Summary:   formatFeedSummary(e),
+			Details:   details,
 		})
 	}
 
 	return entries, nil
 }
 
+// runVerifyEvents checks the signature of every line of the events log and
+// reports the forged or altered ones by line number.
+func runVerifyEvents(townRoot string) error {
+	verify := events.NewVerifier(townRoot)
+	if verify == nil {
+		return fmt.Errorf("events are not signed: set event_signing.key in %s", config.TownSettingsPath(townRoot))
+	}
+	file, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		return fmt.Errorf("opening events file: %w", err)
+	}
+	defer file.Close()
+
+	var valid, unsigned int
+	var bad []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		switch verify.Check(scanner.Bytes()) {
+		case events.SigValid:
+			valid++
+		case events.SigUnsigned:
+			unsigned++
+			if !verify.Valid(scanner.Bytes()) {
+				bad = append(bad, fmt.Sprintf("%d (unsigned)", n))
+			}
+		default:
+			bad = append(bad, fmt.Sprint(n))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading events file: %w", err)
+	}
+
+	fmt.Printf("%s %d signed events verified\n", style.SuccessPrefix, valid)
+	if unsigned > 0 {
+		fmt.Printf("%s %d unsigned events (logged before signing was turned on, or without the key)\n", style.Dim.Render("○"), unsigned)
+	}
+	if len(bad) == 0 {
+		return nil
+	}
+	fmt.Printf("%s %d events failed verification, lines: %s\n", style.ErrorPrefix, len(bad), strings.Join(bad, ", "))
+	return NewSilentExit(1)
+}
+
 // formatFeedSummary creates a readable summary from a feed event.
 func formatFeedSummary(e events.Event) string {
 	switch e.Type {
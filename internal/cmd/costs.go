@@ -63,7 +63,8 @@ Examples:
 
 Subcommands:
   gt costs record       # Record session cost to local log file (Stop hook)
-  gt costs digest       # Aggregate log entries into daily digest bead (Deacon patrol)`,
+  gt costs digest       # Aggregate log entries into daily digest bead (Deacon patrol)
+  gt costs budget       # Per-rig daily budgets (enforced by the daemon)`,
 	RunE: runCosts,
 }
 
@@ -820,8 +821,7 @@ func extractCostFromWorkDir(workDir string) (float64, error) {
 
 // getTmuxSessionWorkDir gets the current working directory of a tmux session.
 func getTmuxSessionWorkDir(session string) (string, error) {
-	cmd := tmux.BuildCommand("display-message", "-t", session, "-p", "#{pane_current_path}")
-	output, err := cmd.Output()
+	output, err := tmux.Output(0, "display-message", "-t", session, "-p", "#{pane_current_path}")
 	if err != nil {
 		return "", err
 	}
@@ -1095,8 +1095,7 @@ func detectCurrentTmuxSession() string {
 	if pane == "" {
 		return ""
 	}
-	cmd := tmux.BuildCommand("display-message", "-t", pane, "-p", "#S")
-	output, err := cmd.Output()
+	output, err := tmux.Output(0, "display-message", "-t", pane, "-p", "#S")
 	if err != nil {
 		return ""
 	}
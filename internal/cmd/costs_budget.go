@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/wisp"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Wisp config keys for the budget guardrail. Both hold a date (YYYY-MM-DD).
+const (
+	// budgetPausedKey records that the guardrail parked the rig, and when.
+	budgetPausedKey = "budget_paused"
+	// budgetOverrideKey records the day an operator let the rig run over
+	// budget.
+	budgetOverrideKey = "budget_override"
+)
+
+var (
+	budgetEnforce bool
+	budgetJSON    bool
+)
+
+var costsBudgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Show or enforce per-rig daily spend budgets",
+	Long: `Show each rig's spend today against its daily budget.
+
+A rig's budget is set in its settings (<rig>/settings/config.json):
+
+  "budget": {"daily_usd": 50}
+
+Spend is what 'gt costs record' logged today for the rig's sessions.
+
+With --enforce (run by the daemon on every heartbeat), a rig whose spend
+reaches its budget is paused:
+  - Sets status=parked, so nothing new is slung and the daemon won't restart agents
+  - Freezes the rig's polecat sessions (SIGTSTP), like 'gt estop'
+  - Emits a budget_exceeded event
+
+The rig stays paused, across midnight too, until an operator lets it run
+with 'gt costs budget override <rig>', which resumes it and ignores the
+budget for the rest of the day. A rig unparked by hand while still over
+budget is paused again on the next heartbeat.
+
+Examples:
+  gt costs budget
+  gt costs budget --enforce
+  gt costs budget override gastown`,
+	RunE: runCostsBudget,
+}
+
+var costsBudgetOverrideCmd = &cobra.Command{
+	Use:   "override <rig>...",
+	Short: "Let rigs run over their budget for the rest of today",
+	Long: `Let rigs run over their daily budget for the rest of today.
+
+If the guardrail paused the rig, it is unparked and its polecats are
+resumed. The budget applies again tomorrow.
+
+Examples:
+  gt costs budget override gastown`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCostsBudgetOverride,
+}
+
+func init() {
+	costsBudgetCmd.Flags().BoolVar(&budgetEnforce, "enforce", false, "Pause rigs that reached their budget (quiet unless one is paused)")
+	costsBudgetCmd.Flags().BoolVar(&budgetJSON, "json", false, "Output as JSON")
+	costsBudgetCmd.ValidArgsFunction = cobra.NoFileCompletions
+	costsBudgetOverrideCmd.ValidArgsFunction = completeRigNames
+	costsBudgetCmd.AddCommand(costsBudgetOverrideCmd)
+	costsCmd.AddCommand(costsBudgetCmd)
+}
+
+// rigBudgetStatus is one budgeted rig's standing for the day.
+type rigBudgetStatus struct {
+	Rig        string  `json:"rig"`
+	BudgetUSD  float64 `json:"budget_usd"`
+	SpentUSD   float64 `json:"spent_usd"`
+	Paused     bool    `json:"paused,omitempty"`     // parked by the guardrail
+	Overridden bool    `json:"overridden,omitempty"` // let run over budget today
+}
+
+// over reports whether the rig has reached its budget.
+func (s rigBudgetStatus) over() bool {
+	return s.SpentUSD >= s.BudgetUSD
+}
+
+// needsPause reports whether the guardrail should pause the rig now.
+func (s rigBudgetStatus) needsPause() bool {
+	return s.over() && !s.Paused && !s.Overridden
+}
+
+func runCostsBudget(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	now := time.Now()
+	entries, err := querySessionCostEntries(now)
+	if err != nil {
+		return fmt.Errorf("querying session cost entries: %w", err)
+	}
+	statuses := rigBudgetStatuses(townRoot, discoverRigs(townRoot), rigSpend(entries), now)
+
+	if budgetEnforce {
+		for _, s := range statuses {
+			if s.needsPause() {
+				pauseRigForBudget(townRoot, s, now)
+			}
+		}
+		return nil
+	}
+
+	if budgetJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+	if len(statuses) == 0 {
+		fmt.Println(style.Dim.Render("No rig has a budget. Set \"budget\": {\"daily_usd\": N} in <rig>/settings/config.json."))
+		return nil
+	}
+	fmt.Printf("\n%s Rig budgets today\n\n", style.Bold.Render("💰"))
+	for _, s := range statuses {
+		line := fmt.Sprintf("  %-20s $%8.2f / $%.2f", s.Rig, s.SpentUSD, s.BudgetUSD)
+		switch {
+		case s.Paused:
+			fmt.Printf("%s  %s\n", line, style.Error.Render("⏸ paused"))
+		case s.Overridden && s.over():
+			fmt.Printf("%s  %s\n", line, style.Warning.Render("over (overridden)"))
+		case s.over():
+			fmt.Printf("%s  %s\n", line, style.Warning.Render("over"))
+		default:
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
+
+// rigBudgetStatuses returns the standing of each rig with a daily budget,
+// sorted by rig name.
+func rigBudgetStatuses(townRoot string, rigs []string, spend map[string]float64, now time.Time) []rigBudgetStatus {
+	today := now.Format("2006-01-02")
+	var statuses []rigBudgetStatus
+	for _, rigName := range rigs {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rigName)))
+		if err != nil || settings.Budget == nil || settings.Budget.DailyUSD <= 0 {
+			continue
+		}
+		w := wisp.NewConfig(townRoot, rigName)
+		statuses = append(statuses, rigBudgetStatus{
+			Rig:        rigName,
+			BudgetUSD:  settings.Budget.DailyUSD,
+			SpentUSD:   spend[rigName],
+			Paused:     w.GetString(budgetPausedKey) != "" && w.GetString(RigStatusKey) == RigStatusParked,
+			Overridden: w.GetString(budgetOverrideKey) == today,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Rig < statuses[j].Rig })
+	return statuses
+}
+
+// rigSpend totals the day's spend per rig from cost log entries. The Stop
+// hook records a session's running total on every turn, so each session
+// counts the growth between its entries; a total that drops is a new
+// session reusing the name, and counts from zero.
+func rigSpend(entries []CostEntry) map[string]float64 {
+	spend := make(map[string]float64)
+	last := make(map[string]float64)
+	for _, e := range entries {
+		if e.Rig == "" {
+			continue
+		}
+		prev, seen := last[e.SessionID]
+		switch {
+		case !seen || e.CostUSD < prev:
+			spend[e.Rig] += e.CostUSD
+		default:
+			spend[e.Rig] += e.CostUSD - prev
+		}
+		last[e.SessionID] = e.CostUSD
+	}
+	return spend
+}
+
+// pauseRigForBudget parks a rig that reached its budget, freezes its
+// polecats and emits budget_exceeded. Best-effort past the park: a polecat
+// that can't be frozen is reported and skipped.
+func pauseRigForBudget(townRoot string, s rigBudgetStatus, now time.Time) {
+	w := wisp.NewConfig(townRoot, s.Rig)
+	if err := w.Set(RigStatusKey, RigStatusParked); err != nil {
+		fmt.Printf("%s %s: parking: %v\n", style.Error.Render("✗"), s.Rig, err)
+		return
+	}
+	_ = w.Set(budgetPausedKey, now.Format("2006-01-02"))
+
+	fmt.Printf("%s Rig %s reached its budget ($%.2f of $%.2f): parked\n",
+		style.Error.Render("⏸"), style.Bold.Render(s.Rig), s.SpentUSD, s.BudgetUSD)
+	t := tmux.NewTmux()
+	var paused []string
+	for _, sess := range rigPolecatSessions(t, townRoot, s.Rig) {
+		if err := signalSessionGroup(t, sess, sigFreeze); err != nil {
+			fmt.Printf("   %s %s: %v\n", style.Warning.Render("!"), sess, err)
+			continue
+		}
+		fmt.Printf("   %s %s\n", style.Error.Render("⏸"), sess)
+		paused = append(paused, sess)
+	}
+	_ = events.LogInTown(townRoot, "gt", events.TypeBudgetExceeded, "daemon",
+		events.BudgetExceededPayload(s.Rig, s.SpentUSD, s.BudgetUSD, paused), events.VisibilityFeed)
+	fmt.Printf("   Resume with: %s\n", style.Bold.Render("gt costs budget override "+s.Rig))
+}
+
+// rigPolecatSessions returns the running polecat sessions of a rig.
+func rigPolecatSessions(t *tmux.Tmux, townRoot, rigName string) []string {
+	prefix := session.PrefixFor(rigName)
+	var sessions []string
+	for _, sess := range collectGTSessions(t, townRoot) {
+		if !isRigSession(sess, prefix) {
+			continue
+		}
+		if role, _, _ := parseSessionName(sess); role == constants.RolePolecat {
+			sessions = append(sessions, sess)
+		}
+	}
+	return sessions
+}
+
+func runCostsBudgetOverride(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	today := time.Now().Format("2006-01-02")
+	t := tmux.NewTmux()
+	for _, rigName := range args {
+		w := wisp.NewConfig(townRoot, rigName)
+		if err := w.Set(budgetOverrideKey, today); err != nil {
+			return fmt.Errorf("%s: %w", rigName, err)
+		}
+		if w.GetString(budgetPausedKey) == "" {
+			fmt.Printf("%s Rig %s may run over budget today\n", style.Success.Render("✓"), rigName)
+			continue
+		}
+		_ = w.Unset(budgetPausedKey)
+		if err := w.Unset(RigStatusKey); err != nil {
+			return fmt.Errorf("%s: clearing parked status: %w", rigName, err)
+		}
+		resumed := 0
+		for _, sess := range rigPolecatSessions(t, townRoot, rigName) {
+			if err := signalSessionGroup(t, sess, sigThaw); err == nil {
+				resumed++
+			}
+		}
+		fmt.Printf("%s Rig %s unparked, %d polecat(s) resumed; may run over budget today\n",
+			style.Success.Render("✓"), rigName, resumed)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+func TestRigSpend(t *testing.T) {
+	entries := []CostEntry{
+		{SessionID: "gt-Toast", Rig: "gastown", CostUSD: 1.00},
+		{SessionID: "gt-Toast", Rig: "gastown", CostUSD: 2.50}, // running total grew by 1.50
+		{SessionID: "gt-Nux", Rig: "gastown", CostUSD: 0.50},
+		{SessionID: "gt-Toast", Rig: "gastown", CostUSD: 0.25}, // a new Toast session
+		{SessionID: "bd-Ace", Rig: "beads", CostUSD: 3.00},
+		{SessionID: "hq-mayor", CostUSD: 9.00}, // town-level: no rig
+	}
+	spend := rigSpend(entries)
+	if got := spend["gastown"]; got != 3.25 {
+		t.Errorf("gastown spend = %.2f, want 3.25", got)
+	}
+	if got := spend["beads"]; got != 3.00 {
+		t.Errorf("beads spend = %.2f, want 3.00", got)
+	}
+	if len(spend) != 2 {
+		t.Errorf("spend = %v, want only the two rigs", spend)
+	}
+}
+
+func TestRigBudgetStatuses(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.Local)
+	for rig, budget := range map[string]float64{"gastown": 10, "beads": 5} {
+		path := config.RigSettingsPath(filepath.Join(townRoot, rig))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		settings := config.NewRigSettings()
+		settings.Budget = &config.BudgetConfig{DailyUSD: budget}
+		if err := config.SaveRigSettings(path, settings); err != nil {
+			t.Fatal(err)
+		}
+	}
+	spend := map[string]float64{"gastown": 12, "beads": 1, "other": 99}
+
+	statuses := rigBudgetStatuses(townRoot, []string{"gastown", "beads", "other"}, spend, now)
+	if len(statuses) != 2 || statuses[0].Rig != "beads" || statuses[1].Rig != "gastown" {
+		t.Fatalf("statuses = %+v, want beads and gastown only", statuses)
+	}
+	if statuses[0].needsPause() || !statuses[1].needsPause() {
+		t.Errorf("needsPause: beads %v gastown %v, want only gastown", statuses[0].needsPause(), statuses[1].needsPause())
+	}
+
+	// Overridden today: left running. Yesterday's override doesn't count.
+	w := wisp.NewConfig(townRoot, "gastown")
+	if err := w.Set(budgetOverrideKey, "2026-03-03"); err != nil {
+		t.Fatal(err)
+	}
+	if s := rigBudgetStatuses(townRoot, []string{"gastown"}, spend, now)[0]; !s.needsPause() {
+		t.Error("yesterday's override kept the rig running")
+	}
+	if err := w.Set(budgetOverrideKey, "2026-03-04"); err != nil {
+		t.Fatal(err)
+	}
+	if s := rigBudgetStatuses(townRoot, []string{"gastown"}, spend, now)[0]; s.needsPause() {
+		t.Error("overridden rig paused")
+	}
+
+	// Already paused: not paused again, unless someone unparked it by hand.
+	if err := w.Unset(budgetOverrideKey); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Set(budgetPausedKey, "2026-03-04")
+	_ = w.Set(RigStatusKey, RigStatusParked)
+	if s := rigBudgetStatuses(townRoot, []string{"gastown"}, spend, now)[0]; !s.Paused || s.needsPause() {
+		t.Errorf("paused rig = %+v", s)
+	}
+	_ = w.Unset(RigStatusKey)
+	if s := rigBudgetStatuses(townRoot, []string{"gastown"}, spend, now)[0]; !s.needsPause() {
+		t.Error("rig unparked by hand while over budget wasn't paused again")
+	}
+}
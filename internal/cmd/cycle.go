@@ -194,7 +194,7 @@ func cycleInGroup(direction int, currentSession string, sessions []string) error
 		args = append(args, "-c", cycleClientTarget)
 	}
 	args = append(args, "-t", sessions[targetIdx])
-	return tmux.BuildCommand(args...).Run()
+	return tmux.Run(0, args...)
 }
 
 // cycleRigOpsSession cycles between witness, refinery, and polecat sessions for a rig.
@@ -216,7 +216,7 @@ func cycleRigOpsSession(direction int, currentSession, rig string) error {
 
 // listTmuxSessions returns all tmux session names.
 func listTmuxSessions() ([]string, error) {
-	out, err := tmux.BuildCommand("list-sessions", "-F", "#{session_name}").Output()
+	out, err := tmux.Output(0, "list-sessions", "-F", "#{session_name}")
 	if err != nil {
 		return nil, err
 	}
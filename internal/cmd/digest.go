@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	digestSince  string
+	digestFormat string
+	digestRig    string
+)
+
+func init() {
+	rootCmd.AddCommand(digestCmd)
+
+	digestCmd.Flags().StringVar(&digestSince, "since", "8h", "Summarize events from this far back (e.g., 8h, 24h)")
+	digestCmd.Flags().StringVar(&digestFormat, "format", feed.DigestFormatMarkdown, "Output format: md, slack or html")
+	digestCmd.Flags().StringVar(&digestRig, "rig", "", "Only include this rig")
+}
+
+var digestCmd = &cobra.Command{
+	Use:     "digest",
+	GroupID: GroupDiag,
+	Short:   "Summarize recent town activity per rig",
+	Long: `Summarize the event feed into a short per-rig narrative.
+
+Reads .events.jsonl and reports, for each rig:
+  - Work dispatched (sling, scheduler) and completed (gt done)
+  - Merges landed and failed (refinery)
+  - Escalations sent
+  - Agents that hit rate limits or usage caps
+
+Mayor, deacon, and dog activity is reported under "town". The output is
+meant to be posted as a morning summary; the feed-digest plugin runs it
+daily and mails the result to the overseer; gt report schedules it (with
+rig SLA/SLO health) for delivery by e-mail or a command hook.
+
+Examples:
+  gt digest                          # Last 8 hours, Markdown
+  gt digest --since 24h              # Last day
+  gt digest --format slack           # Slack mrkdwn for pasting into a channel
+  gt digest --format html            # HTML page, e.g. for mail
+  gt digest --rig gastown            # One rig only`,
+	RunE: runDigest,
+}
+
+func runDigest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	window, err := time.ParseDuration(digestSince)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("invalid --since duration %q", digestSince)
+	}
+
+	until := time.Now()
+	since := until.Add(-window)
+	evts, err := feed.ReadDigestEvents(townRoot, since)
+	if err != nil {
+		return err
+	}
+
+	d := feed.BuildDigest(evts, since, until)
+	if digestRig != "" {
+		d.FilterRig(digestRig)
+	}
+
+	out, err := d.Render(digestFormat)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
@@ -165,6 +165,7 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 
 	// Log to activity feed
 	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
+	payload["escalation_id"] = issue.ID // matched by escalation_acked/closed for SLA timing
 	payload["severity"] = severity
 	payload["actions"] = strings.Join(actions, ",")
 	if escalateSource != "" {
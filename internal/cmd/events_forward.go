@@ -0,0 +1,415 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// forwardPollInterval is how often gt events forward checks the events file
+// for new lines.
+const forwardPollInterval = 500 * time.Millisecond
+
+// forwardMaxBackoff caps the wait between retries of a failed POST.
+const forwardMaxBackoff = 30 * time.Second
+
+var (
+	eventsForwardURL       string
+	eventsForwardTypes     []string
+	eventsForwardAudit     bool
+	eventsForwardBatch     int
+	eventsForwardFlush     time.Duration
+	eventsForwardRetries   int
+	eventsForwardFromStart bool
+)
+
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	GroupID: GroupDiag,
+	Short:   "Work with the town's event log",
+	Long: `Work with the town's event log (.events.jsonl).
+
+Commands:
+  forward   Forward matching events to an HTTP endpoint`,
+	RunE: requireSubcommand,
+}
+
+var eventsForwardCmd = &cobra.Command{
+	Use:   "forward --url <endpoint>",
+	Short: "Forward matching events to an HTTP endpoint",
+	Long: `Follow the town's event log and POST matching events to an HTTP endpoint,
+so external systems can integrate without reading JSONL from disk.
+
+Runs until interrupted. Events are sent in batches as JSON:
+
+  {"events": [{"ts": "...", "type": "merge_failed", "actor": "...", "payload": {...}}, ...]}
+
+A batch goes out when it holds --batch events or its oldest event has
+waited --flush. A failed POST (an error or a non-2xx status) is retried
+with exponential backoff, up to --retries times, before the batch is
+dropped with a warning.
+
+Only feed events are forwarded unless --audit is given. Events that fail
+the town's signature check are never forwarded.
+
+Progress is saved in .runtime/, per endpoint and type filter, so a restart
+picks up where the last run stopped. A first run starts at the end of the
+log, or with --from-start at its beginning.
+
+Examples:
+  gt events forward --url https://hooks.example.com/gt --type escalation_sent --type merge_failed
+  gt events forward --url http://localhost:9000/events --batch 100 --flush 10s`,
+	Args: cobra.NoArgs,
+	RunE: runEventsForward,
+}
+
+func init() {
+	eventsForwardCmd.Flags().StringVar(&eventsForwardURL, "url", "", "Endpoint to POST events to (required)")
+	eventsForwardCmd.Flags().StringSliceVar(&eventsForwardTypes, "type", nil, "Forward only these event types (repeatable or comma-separated; default all)")
+	eventsForwardCmd.Flags().BoolVar(&eventsForwardAudit, "audit", false, "Also forward audit-only events")
+	eventsForwardCmd.Flags().IntVar(&eventsForwardBatch, "batch", 20, "Most events per POST")
+	eventsForwardCmd.Flags().DurationVar(&eventsForwardFlush, "flush", 2*time.Second, "Longest an event waits for its batch to fill")
+	eventsForwardCmd.Flags().IntVar(&eventsForwardRetries, "retries", 5, "Retries of a failed POST before its batch is dropped")
+	eventsForwardCmd.Flags().BoolVar(&eventsForwardFromStart, "from-start", false, "On a first run, forward the log from its beginning")
+	_ = eventsForwardCmd.MarkFlagRequired("url")
+	_ = eventsForwardCmd.RegisterFlagCompletionFunc("type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeEventType(cmd, nil, toComplete)
+	})
+
+	eventsCmd.AddCommand(eventsForwardCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func runEventsForward(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if u, err := url.Parse(eventsForwardURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("--url must be an http or https URL")
+	}
+	if eventsForwardBatch < 1 {
+		return fmt.Errorf("--batch must be at least 1")
+	}
+
+	f := newEventForwarder(townRoot, eventsForwardURL, eventsForwardTypes)
+	f.audit = eventsForwardAudit
+	f.batch = eventsForwardBatch
+	f.flush = eventsForwardFlush
+	f.retries = eventsForwardRetries
+	if err := f.start(eventsForwardFromStart); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	filter := "all events"
+	if len(f.types) > 0 {
+		filter = strings.Join(sortedKeys(f.types), ", ")
+	}
+	fmt.Fprintf(os.Stderr, "gt events forward: %s → %s\n", filter, redactURL(eventsForwardURL))
+	return f.run(ctx)
+}
+
+// eventForwarder tails the events file from a saved offset and POSTs
+// matching events in batches. The offset only moves past an event once its
+// batch is sent (or dropped), so a restart neither loses nor repeats events.
+type eventForwarder struct {
+	eventsPath string
+	statePath  string
+	url        string
+	types      map[string]bool // empty: every type
+	audit      bool            // also audit-only events
+	batch      int
+	flush      time.Duration
+	retries    int
+	client     *http.Client
+	verify     *events.Verifier
+	sleep      func(ctx context.Context, d time.Duration) error // waits between retries
+
+	offset  int64             // committed: everything before it is sent or skipped
+	readPos int64             // how far the file has been read
+	pending []json.RawMessage // read but not yet sent
+	oldest  time.Time         // when the first pending event was read
+	dedup   events.Dedup      // keys already forwarded: a retry goes out once
+}
+
+// forwardState is the progress file of one forwarder.
+type forwardState struct {
+	URL    string   `json:"url"`
+	Types  []string `json:"types,omitempty"`
+	Offset int64    `json:"offset"`
+}
+
+func newEventForwarder(townRoot, endpoint string, types []string) *eventForwarder {
+	f := &eventForwarder{
+		eventsPath: filepath.Join(townRoot, events.EventsFile),
+		url:        endpoint,
+		types:      make(map[string]bool),
+		batch:      20,
+		flush:      2 * time.Second,
+		retries:    5,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		verify:     events.NewVerifier(townRoot),
+		sleep:      sleepCtx,
+	}
+	for _, t := range types {
+		if t = strings.TrimSpace(t); t != "" {
+			f.types[t] = true
+		}
+	}
+	sum := sha256.Sum256([]byte(endpoint + "\n" + strings.Join(sortedKeys(f.types), ",")))
+	f.statePath = filepath.Join(constants.TownRuntimePath(townRoot), "events-forward-"+hex.EncodeToString(sum[:6])+".json")
+	return f
+}
+
+// start positions the forwarder: at the saved offset, else at the start of
+// the log (fromStart) or its end.
+func (f *eventForwarder) start(fromStart bool) error {
+	if data, err := os.ReadFile(f.statePath); err == nil {
+		var st forwardState
+		if err := json.Unmarshal(data, &st); err == nil {
+			f.offset = st.Offset
+			f.readPos = st.Offset
+			return nil
+		}
+	}
+	if fromStart {
+		return nil
+	}
+	info, err := os.Stat(f.eventsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	f.offset, f.readPos = info.Size(), info.Size()
+	return nil
+}
+
+// run forwards events until ctx is done, then tries once to send what's
+// pending.
+func (f *eventForwarder) run(ctx context.Context) error {
+	tick := time.NewTicker(forwardPollInterval)
+	defer tick.Stop()
+	for {
+		f.forward(ctx)
+		select {
+		case <-ctx.Done():
+			f.sendFinal()
+			return nil
+		case <-tick.C:
+		}
+	}
+}
+
+// forward reads and sends what's due. Full batches go out back to back, so
+// a backlog drains without waiting a poll per batch.
+func (f *eventForwarder) forward(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := f.read(); err != nil {
+			fmt.Fprintf(os.Stderr, "gt events forward: reading events: %v\n", err)
+			return
+		}
+		switch {
+		case len(f.pending) >= f.batch:
+			f.send(ctx)
+		case len(f.pending) > 0 && time.Since(f.oldest) >= f.flush:
+			f.send(ctx)
+			return
+		default:
+			return
+		}
+	}
+}
+
+// read reads complete lines past readPos, queueing matching events until a
+// batch is full. A line still being written (no newline yet) is left for
+// the next read. A log that shrank was rotated: it is read from the start.
+func (f *eventForwarder) read() error {
+	file, err := os.Open(f.eventsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < f.readPos {
+		f.offset, f.readPos, f.pending = 0, 0, nil
+	}
+	if _, err := file.Seek(f.readPos, io.SeekStart); err != nil {
+		return err
+	}
+	tail := events.NewTailReader(file, f.readPos)
+	for len(f.pending) < f.batch {
+		line, end, ok := tail.Next()
+		if !ok {
+			break // EOF, or a partial line to finish next time
+		}
+		f.readPos = end
+		if ev := f.match(line); ev != nil {
+			if len(f.pending) == 0 {
+				f.oldest = time.Now()
+			}
+			f.pending = append(f.pending, ev)
+		}
+	}
+	if len(f.pending) == 0 && f.offset != f.readPos {
+		f.commit()
+	}
+	return tail.Err()
+}
+
+// match returns the event on a line if it should be forwarded, else nil.
+// Lines longer than events.MaxLineSize never get here: the reader skips
+// them.
+func (f *eventForwarder) match(line []byte) json.RawMessage {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || !f.verify.Valid(line) {
+		return nil
+	}
+	var ev events.Event
+	if err := json.Unmarshal(line, &ev); err != nil {
+		return nil
+	}
+	if len(f.types) > 0 && !f.types[ev.Type] {
+		return nil
+	}
+	if ev.Visibility == events.VisibilityAudit && !f.audit {
+		return nil
+	}
+	if f.dedup.Seen(ev.IdempotencyKey) {
+		return nil
+	}
+	return json.RawMessage(append([]byte(nil), line...))
+}
+
+// send POSTs the pending events, retrying with backoff, and commits past
+// them whether they went out or were dropped.
+func (f *eventForwarder) send(ctx context.Context) {
+	body, err := json.Marshal(map[string][]json.RawMessage{"events": f.pending})
+	if err == nil {
+		for attempt := 0; ; attempt++ {
+			if err = f.post(body); err == nil || attempt >= f.retries {
+				break
+			}
+			backoff := time.Second << attempt
+			if backoff > forwardMaxBackoff || backoff <= 0 {
+				backoff = forwardMaxBackoff
+			}
+			if f.sleep(ctx, backoff) != nil {
+				return // shutting down: keep them pending for the final send
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gt events forward: dropping %d event(s): %v\n", len(f.pending), err)
+	}
+	f.pending = nil
+	f.commit()
+}
+
+// sendFinal makes one attempt to POST the pending events on shutdown. The
+// offset is only committed if it succeeds, so a failed batch is sent again
+// by the next forwarder rather than lost.
+func (f *eventForwarder) sendFinal() {
+	if len(f.pending) == 0 {
+		return
+	}
+	body, err := json.Marshal(map[string][]json.RawMessage{"events": f.pending})
+	if err == nil {
+		err = f.post(body)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gt events forward: %d event(s) left unsent: %v\n", len(f.pending), err)
+		return
+	}
+	f.pending = nil
+	f.commit()
+}
+
+// post sends one batch.
+func (f *eventForwarder) post(body []byte) error {
+	resp, err := f.client.Post(f.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return uerr.Err // drop the URL: it may embed a secret
+		}
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// commit moves the offset up to what has been read and saves it.
+func (f *eventForwarder) commit() {
+	f.offset = f.readPos
+	st := forwardState{URL: redactURL(f.url), Types: sortedKeys(f.types), Offset: f.offset}
+	if err := util.EnsureDirAndWriteJSON(f.statePath, st); err != nil {
+		fmt.Fprintf(os.Stderr, "gt events forward: saving progress: %v\n", err)
+	}
+}
+
+// sleepCtx waits for d, or returns ctx's error if it's done first.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// redactURL drops the query and user info from a URL, which may hold a
+// token, for messages and the progress file.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	u.User, u.RawQuery = nil, ""
+	return u.String()
+}
+
+// sortedKeys returns a set's keys in order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// forwardSink is an endpoint recording the batches it receives. The first
+// failures requests fail with a 503.
+type forwardSink struct {
+	mu       sync.Mutex
+	batches  [][]events.Event
+	failures int
+}
+
+func (s *forwardSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		http.Error(w, "down", http.StatusServiceUnavailable)
+		return
+	}
+	var body struct {
+		Events []events.Event `json:"events"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.batches = append(s.batches, body.Events)
+}
+
+func appendEvents(t *testing.T, path string, evs ...events.Event) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, ev := range evs {
+		data, _ := json.Marshal(ev)
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newTestForwarder(t *testing.T, endpoint string, types ...string) (*eventForwarder, string) {
+	t.Helper()
+	townRoot := t.TempDir()
+	f := newEventForwarder(townRoot, endpoint, types)
+	f.sleep = func(context.Context, time.Duration) error { return nil }
+	return f, filepath.Join(townRoot, events.EventsFile)
+}
+
+func TestEventForwarderFiltersAndBatches(t *testing.T) {
+	sink := &forwardSink{}
+	srv := httptest.NewServer(sink)
+	defer srv.Close()
+
+	f, path := newTestForwarder(t, srv.URL, "merge_failed", "escalation_sent")
+	f.batch = 2
+	if err := f.start(true); err != nil {
+		t.Fatal(err)
+	}
+	appendEvents(t, path,
+		events.Event{Type: "merge_failed", Actor: "refinery", Visibility: events.VisibilityFeed},
+		events.Event{Type: "sling", Actor: "mayor", Visibility: events.VisibilityFeed},         // type filtered out
+		events.Event{Type: "merge_failed", Actor: "audit", Visibility: events.VisibilityAudit}, // audit only
+		events.Event{Type: "escalation_sent", Actor: "witness", Visibility: events.VisibilityBoth},
+		events.Event{Type: "merge_failed", Actor: "late", Visibility: events.VisibilityFeed},
+	)
+
+	f.forward(context.Background())
+	if len(sink.batches) != 1 || len(sink.batches[0]) != 2 {
+		t.Fatalf("batches = %v, want one full batch of 2", sink.batches)
+	}
+	if sink.batches[0][0].Actor != "refinery" || sink.batches[0][1].Actor != "witness" {
+		t.Errorf("batch = %+v, want the refinery and witness events", sink.batches[0])
+	}
+	if len(f.pending) != 1 {
+		t.Fatalf("pending = %d, want the last event waiting for its batch to fill", len(f.pending))
+	}
+
+	// The partial batch goes out once its oldest event has waited --flush.
+	f.oldest = time.Now().Add(-time.Hour)
+	f.forward(context.Background())
+	if len(sink.batches) != 2 || sink.batches[1][0].Actor != "late" {
+		t.Fatalf("batches = %v, want the late event flushed", sink.batches)
+	}
+}
+
+func TestEventForwarderResumesFromSavedOffset(t *testing.T) {
+	sink := &forwardSink{}
+	srv := httptest.NewServer(sink)
+	defer srv.Close()
+
+	f, path := newTestForwarder(t, srv.URL)
+	f.flush = 0
+	appendEvents(t, path, events.Event{Type: "done", Actor: "old", Visibility: events.VisibilityFeed})
+	if err := f.start(false); err != nil { // first run: starts at the end
+		t.Fatal(err)
+	}
+	appendEvents(t, path, events.Event{Type: "done", Actor: "first", Visibility: events.VisibilityFeed})
+	f.forward(context.Background())
+
+	// A half-written line isn't read until it's finished.
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = file.WriteString(`{"type":"done","actor":"partial",`)
+	_ = file.Close()
+	f.forward(context.Background())
+
+	// A restarted forwarder for the same endpoint picks up where it stopped.
+	again := newEventForwarder(filepath.Dir(path), srv.URL, nil)
+	again.flush = 0
+	if err := again.start(false); err != nil {
+		t.Fatal(err)
+	}
+	file, _ = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = file.WriteString(`"visibility":"feed"}` + "\n")
+	_ = file.Close()
+	again.forward(context.Background())
+
+	var actors []string
+	for _, b := range sink.batches {
+		for _, ev := range b {
+			actors = append(actors, ev.Actor)
+		}
+	}
+	if len(actors) != 2 || actors[0] != "first" || actors[1] != "partial" {
+		t.Errorf("forwarded %v, want [first partial]", actors)
+	}
+}
+
+func TestEventForwarderRetries(t *testing.T) {
+	sink := &forwardSink{failures: 2}
+	srv := httptest.NewServer(sink)
+	defer srv.Close()
+
+	f, path := newTestForwarder(t, srv.URL)
+	f.flush = 0
+	f.retries = 2
+	_ = f.start(true)
+	appendEvents(t, path, events.Event{Type: "done", Actor: "a", Visibility: events.VisibilityFeed})
+	f.forward(context.Background())
+	if len(sink.batches) != 1 {
+		t.Fatalf("batches = %d, want the event delivered on the third try", len(sink.batches))
+	}
+
+	// Past its retries, a batch is dropped and the forwarder moves on.
+	sink.failures = 5
+	appendEvents(t, path, events.Event{Type: "done", Actor: "b", Visibility: events.VisibilityFeed})
+	f.forward(context.Background())
+	if len(f.pending) != 0 || f.offset != f.readPos {
+		t.Errorf("after dropping: pending %d, offset %d of %d", len(f.pending), f.offset, f.readPos)
+	}
+}
+
+func TestEventForwarderShutdownKeepsFailedBatch(t *testing.T) {
+	sink := &forwardSink{}
+	srv := httptest.NewServer(sink)
+	defer srv.Close()
+
+	f, path := newTestForwarder(t, srv.URL)
+	f.flush = 0
+	_ = f.start(true)
+	appendEvents(t, path, events.Event{Type: "done", Actor: "a", Visibility: events.VisibilityFeed})
+	f.forward(context.Background())
+
+	// Queued when shutdown comes, and the final send fails.
+	f.flush = time.Hour
+	appendEvents(t, path, events.Event{Type: "done", Actor: "b", Visibility: events.VisibilityFeed})
+	f.forward(context.Background())
+	sink.failures = 1
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = f.run(ctx)
+	if len(sink.batches) != 1 || f.offset == f.readPos {
+		t.Fatalf("after a failed final send: batches %d, offset %d of %d", len(sink.batches), f.offset, f.readPos)
+	}
+
+	// The next forwarder for the endpoint sends it.
+	again := newEventForwarder(filepath.Dir(path), srv.URL, nil)
+	again.flush = 0
+	if err := again.start(false); err != nil {
+		t.Fatal(err)
+	}
+	again.forward(context.Background())
+	if len(sink.batches) != 2 || sink.batches[1][0].Actor != "b" {
+		t.Errorf("batches = %v, want the unsent event delivered", sink.batches)
+	}
+}
+
+func TestEventForwarderSkipsRetriesAndOverlongLines(t *testing.T) {
+	sink := &forwardSink{}
+	srv := httptest.NewServer(sink)
+	defer srv.Close()
+
+	f, path := newTestForwarder(t, srv.URL)
+	f.flush = 0
+	_ = f.start(true)
+	retried := events.Event{Type: "done", Actor: "a", Visibility: events.VisibilityFeed, IdempotencyKey: "done-a"}
+	huge := events.Event{Type: "done", Actor: "huge", Visibility: events.VisibilityFeed,
+		Payload: map[string]interface{}{"blob": strings.Repeat("x", events.MaxLineSize)}}
+	appendEvents(t, path, retried, huge, retried, events.Event{Type: "done", Actor: "b", Visibility: events.VisibilityFeed})
+	f.forward(context.Background())
+
+	var actors []string
+	for _, b := range sink.batches {
+		for _, ev := range b {
+			actors = append(actors, ev.Actor)
+		}
+	}
+	if strings.Join(actors, ",") != "a,b" {
+		t.Errorf("forwarded %v, want [a b]: the retry and the overlong line skipped", actors)
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	if got := redactURL("https://user:pw@hooks.example.com/x?token=s3cret"); got != "https://hooks.example.com/x" {
+		t.Errorf("redactURL = %q", got)
+	}
+}
@@ -354,8 +354,7 @@ func runFeedInWindow(workDir string, bdArgs []string) error {
 // windowExists checks if a window with the given name exists in the session.
 // Note: getCurrentTmuxSession is defined in handoff.go
 func windowExists(_ *tmux.Tmux, session, windowName string) (bool, error) { // t unused: direct exec for simplicity
-	cmd := tmux.BuildCommand("list-windows", "-t", session, "-F", "#{window_name}")
-	out, err := cmd.Output()
+	out, err := tmux.Output(0, "list-windows", "-t", session, "-F", "#{window_name}")
 	if err != nil {
 		return false, err
 	}
@@ -371,12 +370,10 @@ func windowExists(_ *tmux.Tmux, session, windowName string) (bool, error) { // t
 // createWindow creates a new tmux window with the given name and command.
 func createWindow(_ *tmux.Tmux, session, windowName, workDir, command string) error { // t unused: direct exec for simplicity
 	args := []string{"new-window", "-t", session, "-n", windowName, "-c", workDir, command}
-	cmd := tmux.BuildCommand(args...)
-	return cmd.Run()
+	return tmux.Run(0, args...)
 }
 
 // selectWindow switches to the specified window.
 func selectWindow(_ *tmux.Tmux, target string) error { // t unused: direct exec for simplicity
-	cmd := tmux.BuildCommand("select-window", "-t", target)
-	return cmd.Run()
+	return tmux.Run(0, "select-window", "-t", target)
 }
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tui/activity"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	forensicsAt      string
+	forensicsChanges int
+	forensicsJSON    bool
+)
+
+var forensicsCmd = &cobra.Command{
+	Use:     "forensics <agent>",
+	GroupID: GroupDiag,
+	Short:   "Reconstruct an agent's status at a past time",
+	Long: `Rebuild what gt top showed for an agent at a past time, from the status
+ledger gt top keeps in <town>/.runtime/top-ledger.jsonl.
+
+Every poll, gt top records the fields that changed for each agent: level,
+status, tool, needs-human reason, limits, context, work bead, startup
+phase. Pane content is never recorded. gt forensics replays those changes
+up to --at and prints each field with the time it took its value, followed
+by the agent's last changes before that time.
+
+The agent is a session name or an agent ID. --at accepts
+"2006-01-02 15:04[:05]" (local time), RFC 3339, or a duration meaning that
+long ago. The ledger only covers times when a gt top was running; changes
+older than town settings top.ledger_retention (default 168h) are folded
+into one snapshot per agent.
+
+Examples:
+  gt forensics gt-gastown-crew-max --at "2025-01-10 03:00"
+  gt forensics gastown/crew/max --at 2h
+  gt forensics hq-deacon --at "2025-01-10 03:00" --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runForensics,
+}
+
+func init() {
+	forensicsCmd.Flags().StringVar(&forensicsAt, "at", "", "Time to reconstruct (default: now)")
+	forensicsCmd.Flags().IntVar(&forensicsChanges, "changes", 10, "How many of the agent's preceding changes to list (0 for none)")
+	forensicsCmd.Flags().BoolVar(&forensicsJSON, "json", false, "Output the reconstructed state as JSON")
+	rootCmd.AddCommand(forensicsCmd)
+}
+
+func runForensics(cmd *cobra.Command, args []string) error {
+	agent := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	at, err := parseForensicsTime(forensicsAt, time.Now())
+	if err != nil {
+		return err
+	}
+
+	entries, err := activity.ReadLedger(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading status ledger: %w", err)
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no status ledger at %s (it is written while gt top runs)", activity.LedgerPath(townRoot))
+	}
+	st := activity.ReconstructAt(entries, agent, at)
+	if st == nil {
+		return fmt.Errorf("no ledger entries for %s at or before %s", agent, at.Format("2006-01-02 15:04:05"))
+	}
+
+	if forensicsChanges >= 0 && len(st.Changes) > forensicsChanges {
+		st.Changes = st.Changes[len(st.Changes)-forensicsChanges:]
+	}
+	if forensicsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(st)
+	}
+	printForensics(st)
+	return nil
+}
+
+// parseForensicsTime parses --at: a local "2006-01-02 15:04[:05]" or
+// "2006-01-02", RFC 3339, or a duration before now. Empty means now.
+func parseForensicsTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return now, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --at %q (want \"2006-01-02 15:04\", RFC 3339, or a duration like 2h)", s)
+}
+
+func printForensics(st *activity.LedgerState) {
+	name := st.Session
+	if id := st.Fields["agent_id"]; id != "" {
+		name = id + " (" + st.Session + ")"
+	}
+	fmt.Printf("%s at %s\n", style.Bold.Render(name), st.At.Format("2006-01-02 15:04:05"))
+
+	if st.Gone {
+		fmt.Printf("  %s\n", style.Dim.Render("session ended "+forensicsAgo(st.At, st.Updated)+" before"))
+	} else {
+		keys := make([]string, 0, len(st.Fields))
+		width := 0
+		for k := range st.Fields {
+			if k == "agent_id" {
+				continue
+			}
+			keys = append(keys, k)
+			width = max(width, len(k))
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			since := st.Since[k]
+			fmt.Printf("  %-*s  %s  %s\n", width, k, st.Fields[k],
+				style.Dim.Render("since "+since.Format("15:04:05")+" ("+forensicsAgo(st.At, since)+" before)"))
+		}
+	}
+
+	if len(st.Changes) == 0 {
+		return
+	}
+	fmt.Printf("\n%s\n", style.Bold.Render("Changes"))
+	for _, e := range st.Changes {
+		fmt.Printf("  %s  %s\n", e.Time.Format("2006-01-02 15:04:05"), forensicsChange(e))
+	}
+}
+
+// forensicsChange summarizes a ledger entry, e.g. `level=waiting tool=-`.
+func forensicsChange(e activity.LedgerEntry) string {
+	if e.Gone {
+		return "session ended"
+	}
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := e.Fields[k]
+		switch {
+		case v == "":
+			v = "-"
+		case strings.ContainsAny(v, " \t"):
+			v = fmt.Sprintf("%q", v)
+		}
+		parts = append(parts, k+"="+v)
+	}
+	s := strings.Join(parts, " ")
+	if e.Full {
+		s = style.Dim.Render("snapshot ") + s
+	}
+	return s
+}
+
+func forensicsAgo(at, t time.Time) string {
+	return at.Sub(t).Round(time.Second).String()
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tui/activity"
+)
+
+func TestParseForensicsTime(t *testing.T) {
+	now := time.Date(2025, 1, 10, 5, 0, 0, 0, time.Local)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"", now},
+		{"2h", now.Add(-2 * time.Hour)},
+		{"2025-01-10 03:00", time.Date(2025, 1, 10, 3, 0, 0, 0, time.Local)},
+		{"2025-01-10 03:00:15", time.Date(2025, 1, 10, 3, 0, 15, 0, time.Local)},
+		{"2025-01-09", time.Date(2025, 1, 9, 0, 0, 0, 0, time.Local)},
+		{"2025-01-10T03:00:00Z", time.Date(2025, 1, 10, 3, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseForensicsTime(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseForensicsTime(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := parseForensicsTime("yesterday", now); err == nil {
+		t.Error("parseForensicsTime(yesterday) succeeded, want error")
+	}
+}
+
+func TestForensicsChange(t *testing.T) {
+	e := activity.LedgerEntry{Fields: map[string]string{"level": "waiting", "tool": "", "waiting": "Permission prompt"}}
+	if got, want := forensicsChange(e), `level=waiting tool=- waiting="Permission prompt"`; got != want {
+		t.Errorf("forensicsChange = %q, want %q", got, want)
+	}
+	if got := forensicsChange(activity.LedgerEntry{Gone: true}); got != "session ended" {
+		t.Errorf("gone entry = %q", got)
+	}
+	if got := forensicsChange(activity.LedgerEntry{Full: true, Fields: map[string]string{"level": "cold"}}); !strings.Contains(got, "snapshot") {
+		t.Errorf("full entry = %q, want snapshot marker", got)
+	}
+}
@@ -68,6 +68,7 @@ const HQGitignore = `# Gas Town HQ .gitignore
 **/heartbeat.json
 **/activity.json
 .events.jsonl
+.events-ratelimit.json
 .feed.jsonl
 **/audit.log
 **/last-touched
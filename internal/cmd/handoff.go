@@ -612,7 +612,7 @@ func getCurrentTmuxSession() (string, error) {
 	if pane == "" {
 		return "", fmt.Errorf("TMUX_PANE not set")
 	}
-	out, err := tmux.BuildCommand("display-message", "-t", pane, "-p", "#{session_name}").Output()
+	out, err := tmux.Output(0, "display-message", "-t", pane, "-p", "#{session_name}")
 	if err != nil {
 		return "", err
 	}
@@ -781,6 +781,12 @@ type buildRestartCommandOpts struct {
 	// ContinueSession is true. If empty, falls back to a generic
 	// continuation message.
 	ContinuePrompt string
+	// Topic is the startup beacon topic (default "handoff").
+	Topic string
+	// SessionAgent reads GT_AGENT only from the target session's tmux
+	// environment. Set when restarting another session, whose agent may
+	// differ from the caller's.
+	SessionAgent bool
 }
 
 func buildRestartCommand(sessionName string) (string, error) {
@@ -836,10 +842,14 @@ func buildRestartCommandWithOpts(sessionName string, opts buildRestartCommandOpt
 			Topic:     "patrol",
 		}, "Run `"+cli.Name()+" prime --hook` and begin patrol.")
 	} else {
+		topic := opts.Topic
+		if topic == "" {
+			topic = "handoff"
+		}
 		beacon = session.FormatStartupBeacon(session.BeaconConfig{
 			Recipient: identity.BeaconAddress(),
 			Sender:    "self",
-			Topic:     "handoff",
+			Topic:     topic,
 		})
 	}
 
@@ -855,6 +865,9 @@ func buildRestartCommandWithOpts(sessionName string, opts buildRestartCommandOpt
 	// Fall back to tmux session environment if process env doesn't have it,
 	// since exec env vars may not propagate through all agent runtimes.
 	currentAgent, agentInEnv := os.LookupEnv("GT_AGENT")
+	if opts.SessionAgent {
+		currentAgent, agentInEnv = "", false
+	}
 	if !agentInEnv {
 		// GT_AGENT not in process env at all — try tmux session environment
 		// as fallback, since exec env vars may not propagate through all runtimes.
@@ -934,16 +947,25 @@ func buildRestartCommandWithOpts(sessionName string, opts buildRestartCommandOpt
 	// Without this, custom agents that shadow built-in presets (e.g., custom
 	// "codex" running "opencode") would revert to GT_AGENT-based lookup after
 	// handoff, causing false liveness failures.
-	if processNames := os.Getenv("GT_PROCESS_NAMES"); processNames != "" {
+	processNames := os.Getenv("GT_PROCESS_NAMES")
+	if opts.SessionAgent {
+		processNames, _ = tmux.NewTmux().GetEnvironment(sessionName, "GT_PROCESS_NAMES")
+	}
+	if processNames != "" {
 		envMap["GT_PROCESS_NAMES"] = processNames
 	} else if currentAgent != "" {
 		resolved := config.ResolveProcessNames(currentAgent, "")
 		envMap["GT_PROCESS_NAMES"] = strings.Join(resolved, ",")
 	}
 
-	// Add Claude-related env vars from current environment
+	// Add Claude-related env vars from current environment, or from the
+	// target session's when restarting another session's agent.
 	for _, name := range claudeEnvVars {
-		if val := os.Getenv(name); val != "" {
+		val := os.Getenv(name)
+		if opts.SessionAgent {
+			val, _ = tmux.NewTmux().GetEnvironment(sessionName, name)
+		}
+		if val != "" {
 			envMap[name] = val
 		}
 	}
@@ -1219,7 +1241,7 @@ func handoffRemoteSession(t *tmux.Tmux, targetSession, restartCmd string) error
 	if handoffWatch {
 		fmt.Printf("Switching to %s...\n", targetSession)
 		// Use tmux switch-client to move our view to the target session
-		if err := tmux.BuildCommand("switch-client", "-t", targetSession).Run(); err != nil {
+		if err := tmux.Run(0, "switch-client", "-t", targetSession); err != nil {
 			// Non-fatal - they can manually switch
 			fmt.Printf("Note: Could not auto-switch (use: tmux switch-client -t %s)\n", targetSession)
 		}
@@ -1231,7 +1253,7 @@ func handoffRemoteSession(t *tmux.Tmux, targetSession, restartCmd string) error
 // getSessionPane returns the pane identifier for a session's main pane.
 func getSessionPane(sessionName string) (string, error) {
 	// Get the pane ID for the first pane in the session
-	out, err := tmux.BuildCommand("list-panes", "-t", sessionName, "-F", "#{pane_id}").Output()
+	out, err := tmux.Output(0, "list-panes", "-t", sessionName, "-F", "#{pane_id}")
 	if err != nil {
 		return "", err
 	}
@@ -91,8 +91,7 @@ func isInTmuxSession(targetSession string) bool {
 	}
 
 	// Get current session name, targeting our specific pane
-	cmd := tmux.BuildCommand("display-message", "-t", pane, "-p", "#{session_name}")
-	out, err := cmd.Output()
+	out, err := tmux.Output(0, "display-message", "-t", pane, "-p", "#{session_name}")
 	if err != nil {
 		return false
 	}
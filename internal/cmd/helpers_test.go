@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
 )
 
 func TestParseRigSlashName(t *testing.T) {
@@ -104,3 +109,41 @@ func TestIsShellCommand(t *testing.T) {
 		})
 	}
 }
+
+// signedTestTown returns a town root whose settings sign events.
+func signedTestTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	ts := config.NewTownSettings()
+	ts.EventSigning = &config.EventSigningConfig{Key: "test-key"}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), ts); err != nil {
+		t.Fatalf("saving town settings: %v", err)
+	}
+	return townRoot
+}
+
+// forgedEventLine returns ev as an events file line carrying a signature
+// that wasn't made with any town's key.
+func forgedEventLine(t *testing.T, ev events.Event) string {
+	t.Helper()
+	data, err := json.Marshal(ev)
+	if err != nil {
+		t.Fatalf("marshaling event: %v", err)
+	}
+	return string(data[:len(data)-1]) + `,"sig":"` + strings.Repeat("0", 64) + `"}` + "\n"
+}
+
+// appendEventLines appends raw lines to the events file at path.
+func appendEventLines(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, line := range lines {
+		if _, err := f.WriteString(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
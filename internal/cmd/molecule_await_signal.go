@@ -365,12 +365,13 @@ func calculateEffectiveTimeout(idleCycles int) (time.Duration, error) {
 // <townRoot>/.events.jsonl. Returns immediately when a new event line is
 // appended, or when context is canceled.
 func waitForActivitySignal(ctx context.Context, townRoot string) (*AwaitSignalResult, error) {
-	return waitForEventsFile(ctx, filepath.Join(townRoot, events.EventsFile))
+	return waitForEventsFile(ctx, filepath.Join(townRoot, events.EventsFile), events.NewVerifier(townRoot))
 }
 
-// waitForEventsFile tails the events file for new lines.
+// waitForEventsFile tails the events file for new lines, ignoring lines
+// verify rejects so a forged event can't wake the caller.
 // This replaces the former bd activity --follow subprocess approach.
-func waitForEventsFile(ctx context.Context, eventsPath string) (*AwaitSignalResult, error) {
+func waitForEventsFile(ctx context.Context, eventsPath string, verify *events.Verifier) (*AwaitSignalResult, error) {
 
 	f, err := os.OpenFile(eventsPath, os.O_RDONLY|os.O_CREATE, 0644)
 	if err != nil {
@@ -379,17 +380,19 @@ func waitForEventsFile(ctx context.Context, eventsPath string) (*AwaitSignalResu
 	defer f.Close()
 
 	// Seek to end — we only want new events, not historical ones
-	if _, err := f.Seek(0, 2); err != nil {
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
 		return nil, fmt.Errorf("seeking to end of events file: %w", err)
 	}
 
 	// Poll for new lines using bufio.Reader (not Scanner, which doesn't
 	// resume after EOF). Reader.ReadString properly retries the underlying
-	// file reader, picking up appended data between polls.
+	// file reader, picking up appended data between polls. A line read
+	// before its newline is written is held until the rest arrives.
 	reader := bufio.NewReader(f)
 	ticker := time.NewTicker(200 * time.Millisecond)
 	defer ticker.Stop()
 
+	var partial string
 	for {
 		select {
 		case <-ctx.Done():
@@ -397,16 +400,23 @@ func waitForEventsFile(ctx context.Context, eventsPath string) (*AwaitSignalResu
 				Reason: "timeout",
 			}, nil
 		case <-ticker.C:
-			line, err := reader.ReadString('\n')
-			if err == nil && line != "" {
-				return &AwaitSignalResult{
-					Reason: "signal",
-					Signal: strings.TrimRight(line, "\n"),
-				}, nil
-			}
-			// io.EOF means no new data yet — keep polling
-			if err != nil && err != io.EOF {
-				return nil, fmt.Errorf("reading events file: %w", err)
+			for {
+				line, err := reader.ReadString('\n')
+				if err == io.EOF {
+					// No complete line yet — keep polling
+					partial += line
+					break
+				}
+				if err != nil {
+					return nil, fmt.Errorf("reading events file: %w", err)
+				}
+				line, partial = strings.TrimRight(partial+line, "\n"), ""
+				if line != "" && verify.Valid([]byte(line)) {
+					return &AwaitSignalResult{
+						Reason: "signal",
+						Signal: line,
+					}, nil
+				}
 			}
 		}
 	}
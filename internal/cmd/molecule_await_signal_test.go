@@ -4,8 +4,11 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
 )
 
 func TestCalculateEffectiveTimeout(t *testing.T) {
@@ -142,7 +145,7 @@ func TestWaitForEventsFile_MissingFile(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
 	defer cancel()
 
-	result, err := waitForEventsFile(ctx, filepath.Join(t.TempDir(), "nonexistent.jsonl"))
+	result, err := waitForEventsFile(ctx, filepath.Join(t.TempDir(), "nonexistent.jsonl"), nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -161,7 +164,7 @@ func TestWaitForEventsFile_Timeout(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
 	defer cancel()
 
-	result, err := waitForEventsFile(ctx, eventsPath)
+	result, err := waitForEventsFile(ctx, eventsPath, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -192,7 +195,7 @@ func TestWaitForEventsFile_Signal(t *testing.T) {
 		_, _ = f.WriteString(`{"ts":"new","type":"sling","actor":"test"}` + "\n")
 	}()
 
-	result, err := waitForEventsFile(ctx, eventsPath)
+	result, err := waitForEventsFile(ctx, eventsPath, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -204,6 +207,37 @@ func TestWaitForEventsFile_Signal(t *testing.T) {
 	}
 }
 
+func TestWaitForEventsFile_IgnoresForged(t *testing.T) {
+	// In a signing town, a forged line must not wake the waiter; the
+	// signed event after it does.
+	townRoot := signedTestTown(t)
+	eventsPath := filepath.Join(townRoot, events.EventsFile)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	forged := forgedEventLine(t, events.Event{Type: "forged", Actor: "test"})
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return
+		}
+		_, _ = f.WriteString(forged)
+		_ = f.Close()
+		time.Sleep(300 * time.Millisecond)
+		_ = events.LogInTown(townRoot, "gt", events.TypeSling, "test", nil, events.VisibilityFeed)
+	}()
+
+	result, err := waitForActivitySignal(ctx, townRoot)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Reason != "signal" || !strings.Contains(result.Signal, `"type":"sling"`) {
+		t.Errorf("result = %+v, want a signal from the signed sling event", result)
+	}
+}
+
 func TestWaitForActivitySignal_PathWiring(t *testing.T) {
 	// Verify waitForActivitySignal constructs the correct events path from
 	// townRoot. The events file should be at <townRoot>/.events.jsonl.
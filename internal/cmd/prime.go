@@ -1184,7 +1184,11 @@ func setTmuxWorkContext(workRig, workBead, workMol string) {
 	if os.Getenv("TMUX") == "" {
 		return
 	}
-	out, err := exec.Command("tmux", "display-message", "-p", "#{session_name}").Output()
+	// The current session's server ($TMUX), not the town socket; bounded so a
+	// wedged server can't hang prime.
+	ctx, cancel := context.WithTimeout(context.Background(), tmux.CommandTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "tmux", "display-message", "-p", "#{session_name}").Output()
 	if err != nil {
 		return
 	}
@@ -1194,9 +1198,9 @@ func setTmuxWorkContext(workRig, workBead, workMol string) {
 	}
 	setOrUnset := func(key, value string) {
 		if value != "" {
-			_ = exec.Command("tmux", "set-environment", "-t", session, key, value).Run()
+			_ = exec.CommandContext(ctx, "tmux", "set-environment", "-t", session, key, value).Run()
 		} else {
-			_ = exec.Command("tmux", "set-environment", "-u", "-t", session, key).Run()
+			_ = exec.CommandContext(ctx, "tmux", "set-environment", "-u", "-t", session, key).Run()
 		}
 	}
 	setOrUnset("GT_WORK_RIG", workRig)
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/report"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	reportName    string
+	reportFormat  string
+	reportSince   string
+	reportRig     string
+	reportMail    []string
+	reportCommand string
+	reportPrint   bool
+)
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportScheduleCmd, reportListCmd, reportRemoveCmd, reportRunCmd)
+
+	reportScheduleCmd.Flags().StringVar(&reportName, "name", "daily", "Schedule name (replaces an existing schedule of the same name)")
+	reportScheduleCmd.Flags().StringVar(&reportFormat, "format", feed.DigestFormatMarkdown, "Report format: md, slack or html")
+	reportScheduleCmd.Flags().StringVar(&reportSince, "since", "24h", "How far back each report looks")
+	reportScheduleCmd.Flags().StringVar(&reportRig, "rig", "", "Only report on this rig")
+	reportScheduleCmd.Flags().StringArrayVar(&reportMail, "mail", nil, "E-mail recipient (repeatable)")
+	reportScheduleCmd.Flags().StringVar(&reportCommand, "command", "", "Shell command to run with the report on stdin")
+
+	reportRunCmd.Flags().BoolVar(&reportPrint, "print", false, "Print the report instead of delivering it")
+}
+
+var reportCmd = &cobra.Command{
+	Use:     "report",
+	GroupID: GroupDiag,
+	Short:   "Schedule town health reports by e-mail or command hook",
+	RunE:    requireSubcommand,
+	Long: `Schedule town health reports for teams that want a daily summary
+without watching gt top or the dashboard.
+
+A report is the gt digest for its window (work dispatched and done, merges,
+escalations, limits hit, beads moved) plus each rig's SLA breaches and SLO
+burns when rig_slas is configured. The daemon checks schedules every 30
+seconds and delivers each report when its cron expression fires (local
+time). A report missed while the daemon was down is sent once when it
+comes back.
+
+Delivery:
+  --mail      Sent through the SMTP server in settings/escalation.json
+              (contacts.smtp_host, smtp_port, smtp_from, smtp_user, smtp_pass)
+  --command   Run with sh -c in the town root, report on stdin, and
+              GT_REPORT_NAME, GT_REPORT_SUBJECT, GT_REPORT_FORMAT set
+
+Commands:
+  gt report schedule <cron>   Add or replace a schedule
+  gt report list              Show schedules and their next run
+  gt report remove <name>     Remove a schedule
+  gt report run <name>        Build and deliver a report now`,
+}
+
+var reportScheduleCmd = &cobra.Command{
+	Use:   "schedule <cron>",
+	Short: "Add or replace a report schedule",
+	Long: `Add a report schedule, or replace the one with the same --name.
+
+The cron expression has five fields (minute hour day month weekday) and
+supports *, lists, ranges and steps, plus @hourly, @daily and @weekly.
+
+Examples:
+  gt report schedule "0 9 * * *" --format html --mail ops@example.com
+  gt report schedule "0 9 * * 1-5" --name standup --format slack \
+      --command 'curl -sf -X POST -H "Content-Type: text/plain" --data-binary @- "$WEBHOOK"'
+  gt report schedule @weekly --name weekly --since 168h --mail lead@example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReportSchedule,
+}
+
+var reportListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List report schedules",
+	Args:  cobra.NoArgs,
+	RunE:  runReportList,
+}
+
+var reportRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a report schedule",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runReportRemove,
+}
+
+var reportRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Build and deliver a scheduled report now",
+	Long: `Build a scheduled report and deliver it immediately, e.g. to check
+the SMTP settings or command hook. With --print the report is written to
+stdout instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReportRun,
+}
+
+// loadReportSettings loads the town settings that hold the report schedules.
+func loadReportSettings() (townRoot, path string, settings *config.TownSettings, err error) {
+	townRoot, err = workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	path = config.TownSettingsPath(townRoot)
+	settings, err = config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("loading settings: %w", err)
+	}
+	return townRoot, path, settings, nil
+}
+
+// findReport returns the index of the named schedule, or -1.
+func findReport(reports []config.ReportSchedule, name string) int {
+	for i, rs := range reports {
+		if rs.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func runReportSchedule(cmd *cobra.Command, args []string) error {
+	if _, err := report.ParseCron(args[0]); err != nil {
+		return err
+	}
+	if reportName == "" {
+		return fmt.Errorf("--name must not be empty")
+	}
+	if len(reportMail) == 0 && reportCommand == "" {
+		return fmt.Errorf("nowhere to deliver the report: set --mail and/or --command")
+	}
+	switch reportFormat {
+	case feed.DigestFormatMarkdown, feed.DigestFormatSlack, feed.DigestFormatHTML:
+	default:
+		return fmt.Errorf("invalid --format %q (valid: md, slack, html)", reportFormat)
+	}
+	if d, err := time.ParseDuration(reportSince); err != nil || d <= 0 {
+		return fmt.Errorf("invalid --since duration %q", reportSince)
+	}
+
+	_, path, settings, err := loadReportSettings()
+	if err != nil {
+		return err
+	}
+	rs := config.ReportSchedule{
+		Name:    reportName,
+		Cron:    args[0],
+		Format:  reportFormat,
+		Since:   reportSince,
+		Rig:     reportRig,
+		Mail:    reportMail,
+		Command: reportCommand,
+	}
+	verb := "Added"
+	if i := findReport(settings.Reports, rs.Name); i >= 0 {
+		settings.Reports[i] = rs
+		verb = "Replaced"
+	} else {
+		settings.Reports = append(settings.Reports, rs)
+	}
+	if err := config.SaveTownSettings(path, settings); err != nil {
+		return fmt.Errorf("saving settings: %w", err)
+	}
+
+	fmt.Printf("%s %s report schedule %s (%s)\n", style.Success.Render("✓"), verb, style.Bold.Render(rs.Name), rs.Cron)
+	fmt.Printf("  Delivered by the daemon; check with: gt report run %s\n", rs.Name)
+	return nil
+}
+
+func runReportList(cmd *cobra.Command, args []string) error {
+	_, _, settings, err := loadReportSettings()
+	if err != nil {
+		return err
+	}
+	if len(settings.Reports) == 0 {
+		fmt.Println("No report schedules. Add one with: gt report schedule \"0 9 * * *\" --mail you@example.com")
+		return nil
+	}
+
+	now := time.Now()
+	for _, rs := range settings.Reports {
+		next := "invalid cron"
+		if s, err := report.ParseCron(rs.Cron); err == nil {
+			next = "never"
+			if t := s.Next(now); !t.IsZero() {
+				next = t.Format("Mon Jan 2 15:04")
+			}
+		}
+		fmt.Printf("%s  %s  %s\n", style.Bold.Render(rs.Name), rs.Cron, style.Dim.Render("next: "+next))
+		fmt.Printf("  %s\n", describeReport(rs))
+	}
+	return nil
+}
+
+// describeReport summarizes a schedule's content and targets.
+func describeReport(rs config.ReportSchedule) string {
+	format, since := rs.Format, rs.Since
+	if format == "" {
+		format = feed.DigestFormatMarkdown
+	}
+	if since == "" {
+		since = report.DefaultSince.String()
+	}
+	parts := []string{format + ", last " + since}
+	if rs.Rig != "" {
+		parts = append(parts, "rig "+rs.Rig)
+	}
+	if len(rs.Mail) > 0 {
+		parts = append(parts, "mail "+strings.Join(rs.Mail, ", "))
+	}
+	if rs.Command != "" {
+		parts = append(parts, "command "+rs.Command)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func runReportRemove(cmd *cobra.Command, args []string) error {
+	_, path, settings, err := loadReportSettings()
+	if err != nil {
+		return err
+	}
+	i := findReport(settings.Reports, args[0])
+	if i < 0 {
+		return fmt.Errorf("no report schedule named %q", args[0])
+	}
+	settings.Reports = append(settings.Reports[:i], settings.Reports[i+1:]...)
+	if err := config.SaveTownSettings(path, settings); err != nil {
+		return fmt.Errorf("saving settings: %w", err)
+	}
+	fmt.Printf("%s Removed report schedule %s\n", style.Success.Render("✓"), args[0])
+	return nil
+}
+
+func runReportRun(cmd *cobra.Command, args []string) error {
+	townRoot, _, settings, err := loadReportSettings()
+	if err != nil {
+		return err
+	}
+	i := findReport(settings.Reports, args[0])
+	if i < 0 {
+		return fmt.Errorf("no report schedule named %q", args[0])
+	}
+	rs := settings.Reports[i]
+
+	r, err := report.Build(townRoot, rs, time.Now())
+	if err != nil {
+		return err
+	}
+	if reportPrint {
+		fmt.Print(r.Body)
+		return nil
+	}
+	if err := report.Deliver(townRoot, rs, r); err != nil {
+		return fmt.Errorf("delivering report %s: %w", rs.Name, err)
+	}
+	fmt.Printf("%s Delivered report %s: %s\n", style.Success.Render("✓"), rs.Name, describeReport(rs))
+	return nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// setReportFlags sets the gt report schedule flags for one call.
+func setReportFlags(t *testing.T, name string, mail []string, command string) {
+	t.Helper()
+	reportName, reportFormat, reportSince, reportRig = name, "html", "24h", ""
+	reportMail, reportCommand = mail, command
+	t.Cleanup(func() {
+		reportName, reportFormat, reportSince, reportRig, reportMail, reportCommand = "daily", "md", "24h", "", nil, ""
+	})
+}
+
+func TestReportSchedule_AddReplaceRemove(t *testing.T) {
+	townRoot, _ := setupTestTownForAccount(t)
+	t.Chdir(townRoot)
+	path := config.TownSettingsPath(townRoot)
+
+	setReportFlags(t, "daily", []string{"ops@example.com"}, "")
+	if err := runReportSchedule(nil, []string{"0 9 * * *"}); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	setReportFlags(t, "daily", nil, "cat > report.html")
+	if err := runReportSchedule(nil, []string{"30 8 * * 1-5"}); err != nil {
+		t.Fatalf("reschedule: %v", err)
+	}
+
+	settings, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(settings.Reports) != 1 {
+		t.Fatalf("reports = %+v, want the schedule replaced", settings.Reports)
+	}
+	if rs := settings.Reports[0]; rs.Cron != "30 8 * * 1-5" || rs.Format != "html" || rs.Command != "cat > report.html" || len(rs.Mail) != 0 {
+		t.Errorf("saved schedule = %+v", rs)
+	}
+
+	if err := runReportRun(nil, []string{"daily"}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got, err := os.ReadFile(filepath.Join(townRoot, "report.html")); err != nil || !strings.Contains(string(got), "<h1>Gas Town digest") {
+		t.Errorf("hook output = %q (%v)", got, err)
+	}
+
+	if err := runReportRemove(nil, []string{"daily"}); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := runReportRemove(nil, []string{"daily"}); err == nil {
+		t.Error("removing a missing schedule succeeded")
+	}
+	settings, _ = config.LoadOrCreateTownSettings(path)
+	if len(settings.Reports) != 0 {
+		t.Errorf("reports after remove = %+v", settings.Reports)
+	}
+}
+
+func TestReportSchedule_Validation(t *testing.T) {
+	townRoot, _ := setupTestTownForAccount(t)
+	t.Chdir(townRoot)
+
+	setReportFlags(t, "daily", nil, "")
+	if err := runReportSchedule(nil, []string{"0 9 * * *"}); err == nil || !strings.Contains(err.Error(), "nowhere to deliver") {
+		t.Errorf("no target err = %v", err)
+	}
+	setReportFlags(t, "daily", []string{"ops@example.com"}, "")
+	if err := runReportSchedule(nil, []string{"0 25 * * *"}); err == nil {
+		t.Error("invalid cron accepted")
+	}
+	reportFormat = "pdf"
+	if err := runReportSchedule(nil, []string{"0 9 * * *"}); err == nil {
+		t.Error("invalid format accepted")
+	}
+}
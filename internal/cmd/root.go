@@ -70,6 +70,8 @@ var beadsExemptCommands = map[string]bool{
 	"top":                 true, // Blinkenlights TUI reads tmux directly
 	"signal":              true, // Hook signal handlers must be fast, handle beads internally
 	"metrics":             true, // Metrics reads local JSONL, no beads needed
+	"digest":              true, // Digest reads local JSONL, no beads needed
+	"events":              true, // Forwarding reads local JSONL, no beads needed
 	"krc":                 true, // KRC doesn't require beads
 	"run-migration":       true, // Migration orchestrator handles its own beads checks
 	"health":              true, // Health check doesn't require beads
@@ -487,8 +487,7 @@ func beadsSearchDirs(townRoot string) []string {
 // tmux sessions under the persistent polecat model. For capacity gating, use
 // countWorkingPolecats which excludes idle sessions.
 func countActivePolecats() int {
-	listCmd := tmux.BuildCommand("list-sessions", "-F", "#{session_name}")
-	out, err := listCmd.Output()
+	out, err := tmux.Output(0, "list-sessions", "-F", "#{session_name}")
 	if err != nil {
 		return 0
 	}
@@ -519,8 +518,7 @@ func countWorkingPolecats() int {
 		return countActivePolecats() // Fallback to total count
 	}
 
-	listCmd := tmux.BuildCommand("list-sessions", "-F", "#{session_name}")
-	out, err := listCmd.Output()
+	out, err := tmux.Output(0, "list-sessions", "-F", "#{session_name}")
 	if err != nil {
 		return 0
 	}
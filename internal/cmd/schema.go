@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/schema"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:     "schema",
+	GroupID: GroupDiag,
+	Short:   "Print the JSON Schemas of Gas Town's published files",
+	RunE:    requireSubcommand,
+	Long: `Print the versioned JSON Schemas (draft 2020-12) of the files Gas Town
+writes for other programs, so external consumers can generate clients.
+
+Schemas:
+  agent-status  gt top's status file, <town>/.runtime/top-status.json
+  event         one line of the events log, <town>/.events.jsonl
+
+Within a version, changes are additive only: new optional properties and
+new enum values. Consumers should ignore properties they don't know.
+
+Commands:
+  gt schema list           List the schemas and their IDs
+  gt schema print <name>   Print a schema`,
+}
+
+var schemaListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the published schemas",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, s := range schema.All() {
+			fmt.Printf("%-14s %s  %s\n", s.Name, style.Dim.Render(s.ID()), s.Title)
+		}
+		return nil
+	},
+}
+
+var schemaPrintCmd = &cobra.Command{
+	Use:   "print <name>",
+	Short: "Print a JSON Schema",
+	Long: `Print a published JSON Schema.
+
+Examples:
+  gt schema print agent-status
+  gt schema print event > event.schema.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSchemaPrint,
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaListCmd, schemaPrintCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchemaPrint(cmd *cobra.Command, args []string) error {
+	s, err := schema.Get(args[0])
+	if err != nil {
+		return err
+	}
+	data, err := s.Published()
+	if err != nil {
+		return fmt.Errorf("reading published schema: %w", err)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
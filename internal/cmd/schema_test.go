@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSchemaPrint(t *testing.T) {
+	out := captureStdout(t, func() {
+		if err := runSchemaPrint(schemaPrintCmd, []string{"agent-status"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if doc["$id"] != "urn:gastown:schema:agent-status:v1" {
+		t.Errorf("$id = %v", doc["$id"])
+	}
+
+	if err := runSchemaPrint(schemaPrintCmd, []string{"nope"}); err == nil {
+		t.Error("unknown schema should fail")
+	}
+}
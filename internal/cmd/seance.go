@@ -303,7 +303,8 @@ func clearClaudeCodeEnv(environ []string) []string {
 	return filtered
 }
 
-// discoverSessions reads session_start events from our event stream.
+// discoverSessions reads session_start events from our event stream,
+// skipping any that fail signature verification.
 func discoverSessions(townRoot string) ([]sessionEvent, error) {
 	eventsPath := filepath.Join(townRoot, events.EventsFile)
 
@@ -317,6 +318,7 @@ func discoverSessions(townRoot string) ([]sessionEvent, error) {
 	defer file.Close()
 
 	var sessions []sessionEvent
+	verify := events.NewVerifier(townRoot)
 	scanner := bufio.NewScanner(file)
 
 	// Increase buffer for large lines
@@ -329,7 +331,7 @@ func discoverSessions(townRoot string) ([]sessionEvent, error) {
 			continue
 		}
 
-		if event.Type == events.TypeSessionStart {
+		if event.Type == events.TypeSessionStart && verify.Valid(scanner.Bytes()) {
 			sessions = append(sessions, event)
 		}
 	}
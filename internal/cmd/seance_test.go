@@ -506,3 +506,25 @@ func TestResolveSessionPrefix(t *testing.T) {
 		}
 	})
 }
+
+func TestDiscoverSessionsSkipsForged(t *testing.T) {
+	townRoot := signedTestTown(t)
+	payload := map[string]interface{}{"session_id": "real-session", "topic": "test"}
+	if err := events.LogInTown(townRoot, "gt", events.TypeSessionStart, "test/agent", payload, events.VisibilityAudit); err != nil {
+		t.Fatalf("LogInTown: %v", err)
+	}
+	appendEventLines(t, filepath.Join(townRoot, events.EventsFile), forgedEventLine(t, events.Event{
+		Timestamp: "2026-01-22T01:00:00Z",
+		Type:      events.TypeSessionStart,
+		Actor:     "test/agent",
+		Payload:   map[string]interface{}{"session_id": "forged-session", "topic": "test"},
+	}))
+
+	sessions, err := discoverSessions(townRoot)
+	if err != nil {
+		t.Fatalf("discoverSessions: %v", err)
+	}
+	if len(sessions) != 1 || getPayloadString(sessions[0].Payload, "session_id") != "real-session" {
+		t.Errorf("sessions = %+v, want only the signed real-session", sessions)
+	}
+}
@@ -411,8 +411,7 @@ func injectStartPrompt(pane, beadID, subject, args string) error {
 func getSessionFromPane(pane string) string {
 	if strings.HasPrefix(pane, "%") {
 		// Pane ID format - query tmux for the session
-		cmd := tmux.BuildCommand("display-message", "-t", pane, "-p", "#{session_name}")
-		out, err := cmd.Output()
+		out, err := tmux.Output(0, "display-message", "-t", pane, "-p", "#{session_name}")
 		if err != nil {
 			return ""
 		}
@@ -489,7 +488,7 @@ func ensureAgentReady(sessionName string) error {
 
 // isSessionYoung returns true if the tmux session was created less than maxAge ago.
 func isSessionYoung(sessionName string, maxAge time.Duration) bool {
-	out, err := tmux.BuildCommand("display-message", "-t", sessionName, "-p", "#{session_created}").Output()
+	out, err := tmux.Output(0, "display-message", "-t", sessionName, "-p", "#{session_created}")
 	if err != nil {
 		return false
 	}
@@ -1,30 +1,77 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/tui/activity"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
 )
 
 // Activity emit command flags
 var (
-	activityEventType string
-	activityActor     string
-	activityRig       string
-	activityPolecat   string
-	activityTarget    string
-	activityReason    string
-	activityMessage   string
-	activityStatus    string
-	activityIssue     string
-	activityTo        string
-	activityCount     int
-	activityInterval  float64 // poll interval in seconds for gt top
+	activityEventType   string
+	activityActor       string
+	activityRig         string
+	activityPolecat     string
+	activityTarget      string
+	activityReason      string
+	activityMessage     string
+	activityStatus      string
+	activityIssue       string
+	activityTo          string
+	activityAgentID     string
+	activityKey         string
+	activityFields      []string // extra payload fields, key=value
+	activityProgress    string   // agent-reported progress, e.g. "60% through test suite"
+	activityCount       int
+	activityInterval    float64 // poll interval in seconds for gt top
+	activityTown        string  // explicit town root (--town), overrides $GT_TOWN_ROOT
+	activityInclude     []string
+	activityExclude     []string
+	activityViewRig     string // gt top --rig: show only this rig's agents
+	activityViewRole    string
+	activityViewLevel   string
+	activityViewConvoy  string // gt top --convoy: show only agents working on this convoy or epic
+	activityByConvoy    bool
+	activityBurndown    string // gt top --burndown: burn-down panel for this convoy or epic
+	activityTranscripts bool   // read Claude Code transcripts instead of scraping panes only
+	activityRedact      bool   // mask bead titles, paths and command arguments for screen sharing
+	activityDocker      bool   // also monitor agents in labelled Docker containers
+	activityKubernetes  bool   // also monitor agents in Kubernetes pods
+	activityReport      string // status report file rewritten every poll
+	activityTheme       string
+	activityLight       bool   // draw for a light terminal background, remembered
+	activityDark        bool   // draw for a dark terminal background, remembered
+	activityBackground  string // light, dark or auto (forget --light/--dark)
+	activityTicker      bool   // scrolling event ticker under the panels
+	activityRigHistory  bool   // per-rig history chart under each rig header
+	activityScreensaver time.Duration
+	activityExitAfter   time.Duration
+	activityCalm        string // steady lights: auto, on, off
+	activityNoAnimate   bool   // no blinking lights or sparkle
+	activityColorBy     string // light colors: level, rig
+	activitySource      string // what marks an agent active: window, pane
+	activityParserGap   bool   // log agents whose output the parsers derive nothing from
+	activityWeb         string // address for the web sink
+	activityMetrics     string // address for the Prometheus sink
+	activityJSONOut     string // file the JSON sink rewrites
+	activityEvents      bool   // log agent_observation events on level changes
+	activityUI          bool   // run the TUI (false: sinks only)
+	activityJSON        bool   // poll once and print the status as JSON
+	activityOnce        bool   // poll once and print the screen
 )
 
 var activityCmd = &cobra.Command{
@@ -36,10 +83,416 @@ var activityCmd = &cobra.Command{
 
 Shows live status including:
   • Current tool/command execution
+  • Recent tool failures (✗N badge, cleared on the next success)
+  • Pushed work (↑ badge, ↑#123 with the PR number; bright once the agent
+    is idle, its work ready for review)
+  • Retry loops (same tool started 4+ times in 5m)
   • Context remaining before auto-compact
+  • Estimated token spend (tok/m per agent over 5m, town total in the stats bar)
   • Activity levels (LED indicators)
-  • Rate limits and billing caps
+  • Rate limits and billing caps, with a live countdown to
+    the reset; the stats bar lists capped agents soonest reset first
   • Agents blocked waiting for human
+  • Agents still booting (◐ starting · loading MCP servers) in their first 2m
+  • Progress agents report on long tasks (▰▰▰▱▱ 60% through test suite),
+    from agent_progress events (gt top emit agent_progress --progress ...)
+  • Non-Claude agents idle at their prompt (✓ ready for work), from the
+    agent_idle events their plugins emit, rather than guessed from the pane
+  • Who mailed, nudged, slung, or escalated to whom in the last 10m (hover)
+  • When a human last attached to or typed into each session, and who (hover)
+
+The row under the header is a minimap: one braille dot per agent in rig
+order, colored like its LED (denser dots = more recent activity). Hover a
+dot for the agent's details; click it to highlight the agent's row.
+
+On wide terminals (180+ columns) rig panels are laid out in two or three
+columns side by side, so a large town fits on one wall-mounted screen.
+
+Keyboard selection: ↑/↓ (or k/j) select an agent row without the mouse,
+e.g. over SSH without mouse reporting. The selected row is highlighted, its
+detail line replaces the help bar, and everything below that acts on the
+hovered agent acts on it. Enter attaches, like a double-click; esc clears
+the selection (a second esc quits).
+
+Press f while hovering an agent to pin it to a section at the top of the
+screen (press f again to unpin). Pinned agents stay visible regardless of
+how long the rest of the list grows.
+
+Alert ladders: town settings top.alerts escalate a condition (a level such
+as waiting, hit_limit, or cold, or looping) while it persists
+unacknowledged, e.g. a desktop notification at once, Slack after 15m, and a
+phone after 1h:
+  "top": {"alerts": [{"condition": "waiting", "ladder": [
+    {"notify": "desktop"}, {"after": "15m", "notify": "slack"},
+    {"after": "1h", "notify": "sms"}, {"after": "1h", "notify": "pagerduty:<key>"}]}]}
+Slack and SMS use the contacts in settings/escalation.json; webhook:<url>
+posts JSON anywhere. Press a while hovering an agent (or A for a filtered
+set) to acknowledge and stop the ladder; it re-arms if the condition recurs.
+
+Sticky values (context remaining, session-limit warnings) are kept between
+sightings in the pane, but expire after town settings top.context_ttl
+(default 15m) and top.session_limit_ttl (default 2h). All sticky state is
+cleared when a session restarts or an agent_restarted event arrives.
+
+Press $ (or s) while hovering an idle Claude agent to ask it for /cost (or
+/status): gt top types the command at its empty prompt, reads the answer
+from the pane, and shows spend or model info in an overlay (any key
+closes it). The /status dialog is dismissed again afterwards. Busy agents,
+and prompts with text typed into them, are left alone.
+
+Press R while hovering an agent to restart it in place (gt agents restart,
+after a y/n confirmation): the agent is stopped and relaunched with its role
+configuration, while its tmux session, and anyone attached to it, stays.
+
+Missing roles: when the mayor, the deacon, or a rig's witness or refinery
+has no session, its rig panel shows a dark placeholder LED. Press S while
+hovering one to launch it with the role's start command (gt witness start
+<rig>, ...) after a y/n confirmation, so gt top doubles as the recovery
+console after a partial crash. Parked rigs get no placeholders.
+
+Press r (or F5) while hovering an agent to force-refresh it: sticky state
+(needs-human, limits, loop history, work context) is dropped, the agent
+type is re-detected, and its pane is re-captured immediately.
+
+Copy actions: while hovering an agent, y copies its session name, Y the
+tmux command that attaches to it, and c its work bead ID. The first of
+pbcopy, wl-copy, xclip, xsel, or clip.exe found is used; without one
+(e.g. over SSH) the text goes to the tmux paste buffer, which tmux passes
+on to your terminal's clipboard when set-clipboard is on.
+
+Press F1 for a guided tour: a callout for each part of the screen (header
+and minimap, rig panels, agent lines, lights, stats, keys), describing this
+town's own rigs and agents, with the part it explains highlighted. It opens
+by itself the first time you run gt top (recorded in
+~/.local/state/gastown/top-tour-seen).
+
+Rig SLAs: when town settings define rig_slas, each rig header shows SLA
+badges computed from the events feed (e.g., "merge≤30m ✓  ack≤10m ✗2").
+  merge  time from gt done to the refinery merging the branch
+  ack    time from an escalation being sent to it being acked or closed
+Breaches are counted over the last 24h (rig_slas.window overrides):
+  "rig_slas": {"default": {"merge_queue_drain": "30m", "escalation_ack": "10m"},
+               "rigs": {"beads": {"merge_queue_drain": "2h"}}}
+
+SLO burn: rig_slas targets merge_failure_rate and escalation_rate (e.g.
+"20%") cap rolling failure rates over the last hour (rig_slas.slo_window
+overrides), shown as header badges (e.g., "merge-fail≤20% ✗35%"):
+  merge-fail  merge_failed events out of all refinery merge attempts
+  escalate    escalations out of finished work (gt done) plus escalations
+A rate past its target on at least 5 attempts raises an "SLO burn" banner
+naming the rig, and drives top.alerts ladders with condition slo_burn.
+
+The hq panel header shows orchestration health: the deacon's heartbeat
+(cycle and age, yellow after 5m and red after 20m, or "paused"), agents it
+last saw unhealthy, beads waiting in the scheduler (enqueued in the last
+24h and not yet dispatched), and nudges queued for the mayor and deacon
+(✉). Hover the deacon for its last patrol action, or the mayor for the
+scheduler's last dispatch.
+
+Each poll gt top publishes every agent's level and last activity to
+<town>/.runtime/top-status.json. Witness patrols read it through
+gt agents --stale 10m to find genuinely cold agents, and the mayor through
+gt agents --attention to find work stranded on dead or blocked agents.
+
+View filters: --rig, --role, and --level narrow the panels, e.g.
+gt top --rig greenplace --role crew --level cold. While a filter is active,
+N nudges, A acknowledges (clears error and loop badges), and K kills every
+matching agent, after a confirmation naming the count ("nudge all 7 cold
+crew in greenplace?"). Nudges are queued for delivery at the next turn.
+
+Press / to edit the filter in place: type rig, role and level names
+("greenplace polecat waiting") or key=value terms ("level=cold,cool") and
+the panels narrow as you type. Tab completes a name, enter keeps the
+filter, esc puts back the previous one, and ctrl+u clears it.
+
+Convoys: each agent is tagged with the convoy tracking its hooked bead (the
+bead's convoy_id), else the epic the bead belongs to. --convoy hq-cv-abc
+shows only the agents working on that convoy or epic; --by-convoy (or the g
+key) groups panels by convoy/epic instead of rig, each headed by its title
+and progress ("convoy hq-cv-abc: Auth rewrite · 3/7 done"), so a convoy
+spread over several polecats can be watched as one unit.
+
+Burn-down: --burndown hq-cv-abc (or b on a hovered agent) adds a panel
+charting the convoy's or epic's beads open vs closed since it was created,
+from the bead_created and bead_closed events, with an ETA at the close rate
+so far and the agents working on it. b again hides it.
+
+Session filters: --exclude 'gt-scratch*' drops helper sessions that carry
+a rig prefix from the panels and stats; --include 'gt-*' monitors only
+matching sessions. Both are repeatable globs and add to town settings
+top.include and top.exclude.
+
+Transcripts: --transcripts (or town settings top.transcripts) reads each
+Claude agent's session transcript from ~/.claude/projects (or
+$CLAUDE_CONFIG_DIR) for its in-progress todo, pending tool call, and
+context token count. Agents without a transcript, and everything else on
+the line, still come from the tmux pane.
+
+Screen sharing: --redact (or town settings top.redact) masks bead and
+convoy titles, todo and pane text, and tool arguments, leaving agent
+names, bead IDs, tool names ("Bash", not "Bash(make deploy)") and levels.
+The $ and s probes are off, since their output is shown verbatim.
+
+Finding an agent: ctrl+f opens a fuzzy finder over agent names, sessions,
+agent IDs and work beads (ID and title). Space-separated terms must all
+match, so "toast parser" finds Toast's parser bead. Enter selects the match
+as if hovered and highlights its row; ctrl+o also attaches to it.
+
+Attention inbox: i lists only the agents needing someone, most urgent
+first: waiting on a human (longest waiting first), at a usage limit
+(soonest reset first), then stuck (longest quiet first). Muted agents,
+agents scheduled off and agents waiting on the merge queue are left out.
+Keys act on the selected item: enter selects it, o attaches, n nudges,
+a acknowledges (dropping it until its state changes), m mutes, R restarts.
+
+Comparing agents: v on one agent, then v on another, opens an overlay with
+their last hour side by side (levels, share of time active, tools run,
+tokens and beads closed) and the difference between them, read from the
+status ledger and the events file. Useful when trying out a new agent
+configuration or model against the current one. Any key closes it.
+
+Health score: the header shows a 0-100 town score. Each agent holds an
+equal share and loses part of it while waiting for a human, at its usage
+limit, stuck, or rate-limited (in that order of weight), from half its
+weight rising to full over 30m. The score is written to the status file
+(health_score) and, with telemetry enabled, exported as the
+gastown.town.health_score gauge.
+
+Provider outages: when 3 or more agents of one provider, across 2 or more
+rigs, start showing API errors (5xx, overloaded) or rate limits within 5m,
+a banner reads e.g. "Anthropic API degraded — 9 agents affected since
+14:02". The affected agents' rate_limited alerts are held back until fewer
+than 3 are still failing.
+
+Health events: the daemon watches agents the same way and emits
+loop_detected, limit_hit and provider_outage (one per outage, in place of
+the affected agents' limit_hit events) for the witness and the feed, so
+each is reported once however many gt top instances are open. gt top
+itself writes none of them.
+
+Container agents: --docker (or town settings top.docker) also shows agents
+running in Docker containers labelled gastown.session=<session name>, e.g.
+gastown.session=gt-gastown-Toast, which places them by rig and role like
+the tmux session of that name would (a tmux session of the same name wins).
+Their status is parsed from the tail of "docker logs" instead of a pane. A
+gastown.heartbeat=<path> label names a heartbeat file inside the container
+(as written by gt heartbeat), read with docker exec for the agent's
+self-reported state; gastown.agent=<type> sets the agent type. Y copies and
+double-click runs "docker attach"; kill and restart stop and restart the
+container. Without a tmux server only container agents are shown.
+
+Pod agents: --kubernetes (or town settings top.kubernetes, with selector,
+namespace and context) lists running pods matching the label selector
+(default app.kubernetes.io/part-of=gastown) with kubectl. Pods are placed by
+a gastown.session annotation, or by gastown.role with gastown.rig and
+gastown.name (e.g. role=polecat, rig=gastown, name=Toast). Status comes
+from "kubectl logs", and from a gastown.heartbeat-state annotation holding
+the heartbeat JSON, else the gastown.heartbeat file read via kubectl exec
+every 30s; gastown.container picks the agent container in multi-container
+pods.
+Restart deletes the pod for its controller to replace; kill is refused.
+
+OpenCode agents: when a session's tmux environment sets GT_OPENCODE_URL
+(e.g. http://127.0.0.1:4096 for "opencode --port 4096"), gt top asks that
+server for the running tool and token usage instead of reading them off the
+pane. An unreachable server is retried after 30s; until then, and for
+sessions without the variable, the pane parser is used.
+
+Agent chrome: status is read by skipping each agent's TUI chrome (prompt,
+status bar, key hints), which changes between Claude Code and OpenCode
+versions. gt top detects the version from the pane (Claude Code's welcome
+banner, OpenCode's bottom bar) and picks the matching pattern set. Town
+settings top.chrome adds sets for newer versions without a gt release;
+patterns are regular expressions added to the set they extend:
+  "top": {"chrome": [{"agent": "claude", "min_version": "2.1.0",
+    "chrome": ["ctrl\\+o to expand"], "interrupt": "esc to interrupt"}]}
+
+Other agents: gt top reads Claude Code and OpenCode panes itself and treats
+any other agent's pane as Claude Code's. A build of gt can teach it another
+agent by registering a pane parser (internal/agentparse) that recognizes the
+agent's pane and reads its status, tool, waits and limits from it.
+
+Capture depth: each pane parser reads as much scrollback as its signals
+need, so limit banners that scroll just above the prompt are still seen:
+10 lines for Claude Code, 30 for OpenCode, 100 for the hover detail, never
+more than 100. New sessions are captured at 10 lines until their agent type
+is known.
+
+Split panes: in a session with several panes, gt top reads the agent's pane
+rather than whichever is active, so splitting one to poke around doesn't
+change the agent's status. The agent pane is the one titled gt-agent
+(tmux select-pane -T gt-agent), else the one recorded at startup
+(GT_PANE_ID), else the largest. Such agents show ⧉N (their pane count), and
+hovering names the pane being watched.
+
+Wedged sessions: each session's pane is captured by its own worker, so one
+that hangs (a wedged pane or a stuck tmux client) doesn't hold up the rest.
+A poll waits up to 1.5s for captures, then shows that agent's last good
+capture marked ⌛; hovering says how long it has been stalled. Every tmux
+call has a timeout, so if the tmux server itself stops answering, a banner
+says so and the last known state stays on screen.
+
+Status ledger: each poll's per-agent changes (level, status, tool,
+needs-human, limits, work bead; never pane content) are appended to
+<town>/.runtime/top-ledger.jsonl. gt forensics <agent> --at "2025-01-10 03:00"
+rebuilds an agent's state at any past time. Changes older than town settings
+top.ledger_retention (default 168h) are folded into one snapshot per agent.
+
+Human touch: the last time someone attached to a session or typed into it
+(from tmux), or attached, probed, or nudged it from gt top, is kept in
+<town>/.runtime/top-human-touch.json and shared by every gt top watching the
+town, so a team can see which agents nobody has looked at lately.
+
+Event ticker: --ticker (or town settings top.ticker) scrolls a one-line
+ticker of the last 15 minutes of feed events under the panels, the latest
+event of each second, like a stock ticker for town activity.
+
+Rig history: --rig-history (or town settings top.rig_history) draws a small
+stacked bar chart under each rig header, one column per minute for the last
+30 minutes: problem agents (stuck, waiting, at a limit) in red at the
+bottom, idle in amber, active in green on top. It starts empty and fills as
+gt top runs.
+
+Idle screens: --screensaver 15m (town settings top.screensaver) dims gt top
+to a one-line summary (health, agent count, and how many need a human)
+after 15 minutes without a key press or mouse movement; any input wakes it.
+--exit-after 8h (top.exit_after) quits after 8 idle hours to free the
+terminal; the next gt top in the town restores the filters, grouping,
+burn-down, pins and selection it left, unless given view flags of its own.
+
+Calm lights: only lines that change are redrawn, but with rig panels side by
+side a blink changes nearly every line. --calm (town settings top.calm) holds
+the lights steady except those that need a human, cutting flicker and SSH
+bandwidth. It turns on by itself at 40 agents over SSH or 100 locally;
+--calm=off keeps every light blinking.
+
+Animation: the lights blink and the header sparkle turns once a second
+whatever the --interval, so a slower poll makes the data less fresh without
+freezing the display. --no-animate holds everything still, for clean
+asciinema recordings and screenshots.
+
+Background: the palette has light and dark variants. gt top asks the
+terminal for its background color at startup, which works inside tmux
+where the generic detection guesses dark. Where the answer is wrong or
+missing, --light or --dark picks the variant and is remembered for later
+launches; --background=auto forgets it. GT_THEME and town settings
+cli_theme (gt theme) are honored too: GT_THEME over a remembered choice,
+cli_theme under it.
+
+Wall displays: gt wall shows the same lights as a kiosk, one rig per page
+with large lights and rotating pages, no chrome, and it restarts instead
+of exiting on errors.
+
+Rig colors: --color-by rig (town settings top.color_by, H to toggle) gives
+each rig its own hue, with brightness showing activity, so a rig gone
+entirely dark stands out. Lights that need attention keep their colors.
+
+Activity source: a light follows tmux's window_activity, which also moves
+when a human attaches, types or scrolls. --activity-source pane (or town
+settings top.activity_source) follows changes in the captured pane content
+instead, so only agent output lights it.
+
+Merge queue: an idle agent whose gt done branch is still in the rig's
+refinery queue shows "waiting on merge, queue position N" and doesn't count
+as stuck. The queue is rebuilt from done and merge_* events.
+
+Multi-repo rigs: each agent is mapped to the git repo its pane's working
+directory is in, named after the origin remote. When a rig's agents span
+more than one repo, their status leads with the repo name and a footer
+under the rig's panel counts agents, pushes and merge queue entries per repo.
+
+Town control: T opens the town's declared topology next to what is
+running, one row per role: the mayor and deacon, and each rig's witness,
+refinery and crew (one per workspace under <rig>/crew). Polecats and dogs
+are started on demand and show only what is running; parked rigs expect
+nothing. S starts what the selected role is missing, x stops it, and U
+reconciles, starting every missing session in the town; each asks first.
+S starts in the main view too (a hovered placeholder), and R always means
+restart.
+
+Macros: town settings top.macros defines named keystroke sequences for
+common interventions, and M on a hovered agent opens a menu of them. Each
+step is text typed and submitted with Enter, a tmux key in angle brackets
+sent on its own, or a pause:
+
+  "macros": [
+    {"name": "approve-and-continue", "steps": ["<Enter>", "<wait 1s>", "continue"]},
+    {"name": "run tests", "steps": ["run the tests and fix any failures"]},
+    {"name": "compact now", "steps": ["<Escape>", "/compact"]}
+  ]
+
+Degraded sources: when a data source fails (tmux listing, the events file,
+a rig's beads, docker or kubectl) or pane captures go stale, a footer above
+the help line names each one, its last error, and when it was last read
+successfully, until it recovers. The rest of the screen keeps showing what
+the working sources say.
+
+Parser gaps: --parser-gap-check (or town settings top.parser_gap_check)
+watches for agents that keep producing output while no status is parsed
+from their pane for 10 minutes, usually an agent TUI version the parsers
+don't know. Each gap is appended to .runtime/top-parser-gaps.jsonl with
+the agent type and version and the pane's shape with all text masked,
+ready to attach to a bug report. Nothing is sent anywhere.
+
+Double-click: attaches to crew and polecats, but opens the rig's merge
+queue for a refinery and gt feed's problems view for a witness or deacon,
+in a new terminal. Town settings top.double_click sets it per role
+("attach", "queue", "patrol" or "none"); alt+double-click always attaches.
+
+Muting: m on an agent left broken on purpose mutes it for an hour (press
+again for 8 hours, then until unmuted, then to unmute). It stays on screen,
+dimmed, but sends no alerts and emits no events for the witness to act on.
+gt top mute and gt top unmute do the same from the shell.
+
+Scheduled downtime: town settings top.offline lists windows when agents are
+expected to be down, by rig or session glob, e.g. {"rig": "gastown",
+"from": "22:00", "to": "06:30", "days": ["mon", "tue"]}. Inside one, quiet
+agents show as "scheduled off" instead of stuck or not running, and send
+no alerts, limit_hit or loop_detected events.
+
+Status report: --report STATUS.md (or town settings top.report, relative to
+the town root, e.g. ".gastown/STATUS.md") rewrites a file on every poll with
+the stats line and each rig's agent table, for wikis, Obsidian vaults, or the
+mayor to read. A path ending in .org is written as org-mode tables.
+
+Outputs: one gt top can feed several outputs from a single tmux poll,
+alongside the TUI or, with --ui=false, on its own:
+  --web[=addr]       an agent table at / and the status at /status.json
+                     (default localhost:7878)
+  --metrics[=addr]   Prometheus metrics at /metrics (default localhost:9464)
+  --json-out FILE    the status as JSON, rewritten every poll
+  --events           an agent_observation event whenever an agent's level changes
+e.g. gt top --ui=false --web --metrics for a headless monitor.
+
+One-shot: gt top --json polls once, with the same pane parsing as the TUI,
+prints every agent's state (name, rig, role, level, tool, context and
+session limit used, limits) as JSON and exits; --rig, --role, --level and
+--convoy narrow it. gt top --once prints the screen as the TUI would draw
+it. Both fail if tmux can't be listed, after printing what they have.
+
+Parser bugs: gt top parse-check <session> prints what the pane parser
+reads from a live session, with the pane lines behind each field (--json
+for bug reports).
+
+Town root: --town selects the town for both gt top and gt top emit, so
+cron jobs, plugins, and remote shells work from any directory. Without it,
+the town is found from the cwd, else from $GT_TOWN_ROOT.
+
+Tmux-only mode: when no town root is found (not run inside the town, no
+--town, no GT_TOWN_ROOT), gt top still shows hq- sessions from tmux but
+prints a banner listing what is missing: rig agents, events, beads, SLAs.
+
+Instant wake: gt top also waits on the tmux channel gt-top-wake. Agent
+events sent through "gt top emit" signal it, so activity shows up within
+~250ms rather than on the next poll. While wake signals are working, no
+agent is active and every agent running signals (OpenCode does; Claude Code
+doesn't), the regular poll slows to 3x the interval. Any script can
+force a refresh with: tmux -L <town socket> wait-for -S gt-top-wake
+
+Slow hosts: when polling tmux and redrawing take over a second per cycle
+(smoothed), gt top stops blinking, ignores wake signals, and doubles the
+poll interval each cycle (up to 8x) until cycles fall under half a second,
+showing a "degraded (slow tmux)" banner meanwhile.
 
 LED Indicators:
   ████  green = active (producing output)
@@ -50,14 +503,23 @@ LED Indicators:
    ··   dark = stuck (5m+)
   ‼‼‼‼  red = needs human (blocked)
 
+LED themes: --theme (or town settings top.theme) picks the glyphs: circles
+(default), blocks (█ ▓ ▒ ░ ·), braille, or hearts (emoji, double width).
+Blocks and braille also give warm and cool different shapes, not just
+colors. top.glyphs overrides single states for fonts that render a glyph
+at the wrong width, e.g. "glyphs": {"warm": "◍", "cool": "◌"}.
+
 Subcommands:
   emit    Emit an activity event
 
 Examples:
   gt top             # Launch the monitor (3s update interval)
   gt top -n 1        # Update every second
+  gt top --town ~/gt # Monitor a town from outside its directory
+  GT_TOWN_ROOT=~/gt gt top emit patrol_started --rig greenplace  # e.g., from cron
   gt blink           # Legacy alias`,
-	RunE: runActivityWatch,
+	RunE:         runActivityWatch,
+	SilenceUsage: true,
 }
 
 var activityEmitCmd = &cobra.Command{
@@ -82,12 +544,52 @@ Supported event types for agent activity (emitted by agent plugins for gt top):
   tool_started     - Agent began executing a tool (--status=tool info, --message=session)
   tool_finished    - Agent finished executing a tool (--status=tool name, --message=session)
   agent_idle       - Agent is idle, waiting for prompt (--message=session)
+  agent_progress   - How far through a long task the agent is, drawn as a bar
+                     on its gt top line (--progress="60% through test suite"
+                     or "3/5 shards", --message=session). tool_started and
+                     tool_finished take --progress too. The bar clears when
+                     the agent goes idle or reports nothing for 10m
+  agent_restarted  - Agent restarted in its existing session; gt top drops
+                     sticky state such as context% and limits (--message=session).
+                     Relaunched under a new session name, add
+                     --field previous_session=<old> (or set --agent-id) and gt top
+                     credits events still sent under the old name to the new one
+
+tool_started is rate limited per actor and session (bursts of 20, then 5 a
+second), and one repeating the tool already running is dropped for 10s.
+tool_finished and agent_idle count against the same limit but are never
+dropped, so the agent's tool and idle state always catch up. The next event
+that gets through after a drop is preceded by an events_dropped event
+counting what was dropped.
+
+Event types emitted by the daemon's agent watch (consumed by the witness):
+  loop_detected    - Agent repeated the same tool invocation (payload: session, tool, count)
+  limit_hit        - Agent hit a rate limit or usage cap (payload: session, kind, reset)
+  provider_outage  - Many agents failing against one provider's API at once
+                     (payload: provider, status started|resolved, agents, rigs, since)
+
+Custom event types are declared in town settings under event_types, each
+with the payload fields it requires and the icon and color gt feed and the
+gt top ticker draw it with:
+
+  "event_types": {
+    "deploy": {"required": ["env", "version"], "icon": "🚢", "color": "39"}
+  }
+
+An event of a custom type missing a required field is refused. Pass payload
+fields with --field key=value.
 
 Common options:
   --actor    Who is emitting the event (e.g., greenplace/witness)
   --rig      Which rig the event is about
   --message  Human-readable message (or tmux session name for agent events)
   --status   Status info (or tool name/args for agent events)
+  --agent-id Stable agent identity (defaults to $GT_AGENT_ID)
+  --town     Town root to write to (defaults to the cwd's town, else $GT_TOWN_ROOT)
+  --idempotency-key  Drop this event if one with the same key was logged
+             recently (plugins pass the tool call ID so retries don't double-count)
+  --field    Extra payload field as key=value (repeatable)
+  --progress Percent done and what of, for agent events ("60% through test suite")
 
 Examples:
   gt activity emit patrol_started --rig greenplace --count 3
@@ -96,9 +598,12 @@ Examples:
   gt activity emit escalation_sent --rig greenplace --target Toast --to mayor --reason "unresponsive"
   gt activity emit patrol_complete --rig greenplace --count 3 --message "All polecats healthy"
   gt activity emit tool_started --actor polecat --status "Bash(git status)" --message "gt-gastown-Toast"
-  gt activity emit tool_finished --actor polecat --status "Bash" --message "gt-gastown-Toast"`,
-	Args: cobra.ExactArgs(1),
-	RunE: runActivityEmit,
+  gt activity emit tool_finished --actor polecat --status "Bash" --message "gt-gastown-Toast"
+  gt activity emit agent_progress --progress "60% through test suite" --message "gt-gastown-Toast"
+  gt activity emit deploy --field env=prod --field version=1.4.2`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeEventType,
+	RunE:              runActivityEmit,
 }
 
 func init() {
@@ -113,8 +618,62 @@ func init() {
 	activityEmitCmd.Flags().StringVar(&activityIssue, "issue", "", "Issue ID (for polecat_checked)")
 	activityEmitCmd.Flags().StringVar(&activityTo, "to", "", "Escalation target (for escalation_sent: mayor, deacon)")
 	activityEmitCmd.Flags().IntVar(&activityCount, "count", 0, "Polecat count (for patrol events)")
+	activityEmitCmd.Flags().StringVar(&activityAgentID, "agent-id", "", "Stable agent identity for gt top matching (defaults to $GT_AGENT_ID)")
+	activityEmitCmd.Flags().StringVar(&activityKey, "idempotency-key", "", "Skip the event if one with this key was logged recently (retry-safe emitters)")
+	activityEmitCmd.Flags().StringArrayVar(&activityFields, "field", nil, "Extra payload field as key=value (repeatable)")
+	activityEmitCmd.Flags().StringVar(&activityProgress, "progress", "", "Percent done and what of (for agent_progress and tool events, e.g. \"60% through test suite\")")
 
 	activityCmd.Flags().Float64VarP(&activityInterval, "interval", "n", 3, "Update interval in seconds")
+	activityCmd.Flags().StringArrayVar(&activityInclude, "include", nil, "Only monitor sessions matching this glob (repeatable)")
+	activityCmd.Flags().StringVar(&activityViewRig, "rig", "", "Show only agents in this rig")
+	activityCmd.Flags().StringVar(&activityViewRole, "role", "", "Show only agents with this role (crew, polecat, witness, ...)")
+	activityCmd.Flags().StringVar(&activityViewLevel, "level", "", "Show only agents at these levels (comma-separated, e.g. cold,cool)")
+	activityCmd.Flags().StringVar(&activityViewConvoy, "convoy", "", "Show only agents working on this convoy or epic")
+	activityCmd.Flags().BoolVar(&activityByConvoy, "by-convoy", false, "Group agents by convoy/epic instead of by rig (toggle with c)")
+	activityCmd.Flags().StringVar(&activityBurndown, "burndown", "", "Show a burn-down panel for this convoy or epic (toggle with b)")
+	activityCmd.Flags().StringArrayVar(&activityExclude, "exclude", nil, "Don't monitor sessions matching this glob (repeatable, e.g. 'gt-scratch*')")
+	activityCmd.Flags().StringVar(&activityTheme, "theme", "", "LED glyph theme: circles, blocks, braille, hearts (default from town settings top.theme)")
+	activityCmd.Flags().BoolVar(&activityTranscripts, "transcripts", false, "Read Claude Code session transcripts for task, tool, and token usage")
+	activityCmd.Flags().BoolVar(&activityRedact, "redact", false, "Mask bead titles, file paths, and command arguments (for screen sharing)")
+	activityCmd.Flags().BoolVar(&activityDocker, "docker", false, "Also monitor agents in Docker containers labelled gastown.session")
+	activityCmd.Flags().DurationVar(&activityScreensaver, "screensaver", 0, "Dim to a summary after this long without input (default from town settings top.screensaver)")
+	activityCmd.Flags().DurationVar(&activityExitAfter, "exit-after", 0, "Exit after this long without input, restoring the view on the next launch (default from town settings top.exit_after)")
+	activityCmd.Flags().BoolVar(&activityTicker, "ticker", false, "Scroll a one-line ticker of recent town events under the panels")
+	activityCmd.Flags().BoolVar(&activityRigHistory, "rig-history", false, "Chart each rig's active, idle and problem agents over the last 30m under its header")
+	activityCmd.Flags().StringVar(&activityCalm, "calm", "", "Hold the lights steady except those that need a human: auto, on, off (default from town settings top.calm)")
+	activityCmd.Flags().Lookup("calm").NoOptDefVal = "on"
+	activityCmd.Flags().BoolVar(&activityLight, "light", false, "Draw for a light terminal background, and remember it for later launches")
+	activityCmd.Flags().BoolVar(&activityDark, "dark", false, "Draw for a dark terminal background, and remember it for later launches")
+	activityCmd.Flags().StringVar(&activityBackground, "background", "", "Terminal background: light, dark, or auto to forget --light/--dark and ask the terminal")
+	activityCmd.MarkFlagsMutuallyExclusive("light", "dark", "background")
+	activityCmd.Flags().BoolVar(&activityNoAnimate, "no-animate", false, "Hold the lights steady and the sparkle still, e.g. for asciinema recordings")
+	activityCmd.Flags().StringVar(&activityColorBy, "color-by", "", "Color the lights by level or rig (default from town settings top.color_by)")
+	activityCmd.Flags().StringVar(&activitySource, "activity-source", "", "What marks an agent active: window (tmux window_activity) or pane (pane content changes)")
+	activityCmd.Flags().BoolVar(&activityParserGap, "parser-gap-check", false, "Log agents showing output with no status parsed for 10m to .runtime/top-parser-gaps.jsonl")
+	activityCmd.Flags().BoolVar(&activityKubernetes, "kubernetes", false, "Also monitor agents in Kubernetes pods (selector from town settings top.kubernetes)")
+	activityCmd.Flags().StringVar(&activityWeb, "web", "", "Serve an agent table and /status.json on this address")
+	activityCmd.Flags().Lookup("web").NoOptDefVal = "localhost:7878"
+	activityCmd.Flags().StringVar(&activityMetrics, "metrics", "", "Serve Prometheus metrics at /metrics on this address")
+	activityCmd.Flags().Lookup("metrics").NoOptDefVal = "localhost:9464"
+	activityCmd.Flags().StringVar(&activityJSONOut, "json-out", "", "Rewrite the status as JSON at this path every poll")
+	activityCmd.Flags().BoolVar(&activityEvents, "events", false, "Log an agent_observation event whenever an agent's level changes")
+	activityCmd.Flags().BoolVar(&activityUI, "ui", true, "Run the TUI (--ui=false feeds the other outputs only)")
+	activityCmd.Flags().BoolVar(&activityJSON, "json", false, "Poll once and print every agent's state as JSON, then exit")
+	activityCmd.Flags().BoolVar(&activityOnce, "once", false, "Poll once and print the screen, then exit")
+	activityCmd.Flags().StringVar(&activityReport, "report", "", "Rewrite a Markdown (or .org) status report at this path every poll (default from town settings top.report)")
+	activityCmd.PersistentFlags().StringVar(&activityTown, "town", "", "Town root (default: detect from cwd, else $GT_TOWN_ROOT)")
+
+	_ = activityCmd.RegisterFlagCompletionFunc("rig", completeRigNames)
+	_ = activityCmd.RegisterFlagCompletionFunc("role", completeRoles)
+	_ = activityCmd.RegisterFlagCompletionFunc("level", completeLevels)
+	_ = activityCmd.RegisterFlagCompletionFunc("include", completeSessionNames)
+	_ = activityCmd.RegisterFlagCompletionFunc("exclude", completeSessionNames)
+	_ = activityCmd.RegisterFlagCompletionFunc("calm", cobra.FixedCompletions([]string{"auto", "on", "off"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = activityCmd.RegisterFlagCompletionFunc("background", cobra.FixedCompletions([]string{"light", "dark", "auto"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = activityCmd.RegisterFlagCompletionFunc("color-by", cobra.FixedCompletions([]string{"level", "rig"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = activityCmd.RegisterFlagCompletionFunc("activity-source", cobra.FixedCompletions([]string{"window", "pane"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = activityEmitCmd.RegisterFlagCompletionFunc("rig", completeRigNames)
+	_ = activityEmitCmd.RegisterFlagCompletionFunc("to", cobra.FixedCompletions([]string{"mayor", "deacon"}, cobra.ShellCompDirectiveNoFileComp))
 	activityCmd.AddCommand(activityEmitCmd)
 	rootCmd.AddCommand(activityCmd)
 }
@@ -122,10 +681,21 @@ func init() {
 func runActivityEmit(cmd *cobra.Command, args []string) error {
 	eventType := args[0]
 
-	// Validate we're in a Gas Town workspace
-	_, err := workspace.FindFromCwdOrError()
+	// An explicit town (--town or $GT_TOWN_ROOT) lets cron jobs and remote shells
+	// emit from anywhere; otherwise we must be inside the workspace.
+	townRoot, err := resolveActivityTown()
 	if err != nil {
-		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		return err
+	}
+	if townRoot == "" {
+		townRoot, err = workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace (use --town or GT_TOWN_ROOT): %w", err)
+		}
+	} else {
+		// persistentPreRun only initialized the registry from cwd; the wake
+		// signal below must reach this town's tmux socket.
+		_ = session.InitRegistry(townRoot)
 	}
 
 	// Auto-detect actor if not provided
@@ -134,6 +704,13 @@ func runActivityEmit(cmd *cobra.Command, args []string) error {
 		actor = detectActor()
 	}
 
+	// Stable agent identity — lets gt top match agent events by ID instead
+	// of session-name/actor heuristics. Set by session launchers.
+	agentID := activityAgentID
+	if agentID == "" {
+		agentID = os.Getenv("GT_AGENT_ID")
+	}
+
 	// Build payload based on event type
 	var payload map[string]interface{}
 
@@ -177,8 +754,22 @@ func runActivityEmit(cmd *cobra.Command, args []string) error {
 			payload["session"] = activityMessage
 		}
 
-	case events.TypeAgentIdle:
+	case events.TypeAgentProgress:
+		// Agent progress — how far through a long task the agent is.
+		// --progress carries the percentage and label, --message the
+		// tmux session name for agent matching.
+		if activityProgress == "" {
+			return fmt.Errorf("--progress is required for agent_progress events")
+		}
+		pct, label, err := events.ParseProgress(activityProgress)
+		if err != nil {
+			return err
+		}
+		payload = events.AgentProgressPayload(activityMessage, pct, label)
+
+	case events.TypeAgentIdle, events.TypeAgentRestarted:
 		// Agent idle event — signals the agent is waiting for a prompt.
+		// Agent restarted — the agent restarted inside its existing session.
 		// --message carries the tmux session name for agent matching.
 		payload = make(map[string]interface{})
 		if activityMessage != "" {
@@ -241,24 +832,276 @@ func runActivityEmit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	for _, f := range activityFields {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("--field %q: want key=value", f)
+		}
+		payload[key] = value
+	}
+	if activityProgress != "" && eventType != events.TypeAgentProgress {
+		switch eventType {
+		case events.TypeToolStarted, events.TypeToolFinished:
+			pct, label, err := events.ParseProgress(activityProgress)
+			if err != nil {
+				return err
+			}
+			payload["progress"] = pct
+			if label != "" {
+				payload["progress_label"] = label
+			}
+		default:
+			return fmt.Errorf("--progress is only for agent_progress, tool_started and tool_finished events")
+		}
+	}
+	if err := events.LoadRegistry(townRoot).Validate(eventType, payload); err != nil {
+		return err
+	}
+
+	if agentID != "" {
+		switch eventType {
+		case events.TypeToolStarted, events.TypeToolFinished, events.TypeAgentIdle, events.TypeAgentProgress,
+			events.TypeAgentRestarted, events.TypeCompactionStarted, events.TypeCompactionFinished:
+			payload["agent_id"] = agentID
+		}
+	}
+
 	// Emit the event (silent on success — output would pollute agent tmux panes
 	// since gastown.js plugin calls this from within the agent's session).
-	if err := events.LogFeed(eventType, actor, payload); err != nil {
+	if err := events.LogInTownOnce(townRoot, activityKey, "gt", eventType, actor, payload, events.VisibilityFeed); err != nil {
 		return fmt.Errorf("emitting event: %w", err)
 	}
+	events.FlushRateLimits()
+
+	// Agent activity wakes running gt top instances so the LED lights
+	// immediately instead of on the next poll.
+	switch eventType {
+	case events.TypeToolStarted, events.TypeToolFinished, events.TypeAgentIdle, events.TypeAgentProgress,
+		events.TypeAgentRestarted, events.TypeCompactionStarted, events.TypeCompactionFinished:
+		activity.SignalWake()
+	}
 
 	return nil
 }
 
+// resolveActivityTown returns the town root given by --town, or "" when
+// it isn't set and the caller should fall back to its usual discovery (the
+// cwd, then $GT_TOWN_ROOT). An explicit root must be a Gas Town workspace.
+func resolveActivityTown() (string, error) {
+	if activityTown == "" {
+		return "", nil
+	}
+	abs, err := filepath.Abs(activityTown)
+	if err != nil {
+		return "", fmt.Errorf("resolving --town: %w", err)
+	}
+	if ok, _ := workspace.IsWorkspace(abs); !ok {
+		return "", fmt.Errorf("--town %s is not a Gas Town workspace (no mayor/town.json or mayor/)", activityTown)
+	}
+	return abs, nil
+}
+
 // runActivityWatch launches the blinkenlights TUI.
 func runActivityWatch(cmd *cobra.Command, args []string) error {
 	interval := time.Duration(activityInterval * float64(time.Second))
-	m := activity.NewModel(interval)
+
+	townRoot, err := resolveActivityTown()
+	if err != nil {
+		return err
+	}
+
+	m := activity.NewModel(interval, townRoot)
+	if err := m.SetSessionFilter(activityInclude, activityExclude); err != nil {
+		return err
+	}
+	if err := m.SetAgentFilter(activityViewRig, activityViewRole, activityViewLevel); err != nil {
+		return err
+	}
+	m.SetConvoyFilter(activityViewConvoy)
+	m.SetGroupByConvoy(activityByConvoy)
+	m.SetBurndown(activityBurndown)
+	if activityTranscripts {
+		m.EnableTranscripts()
+	}
+	if activityRedact {
+		m.SetRedact(true)
+	}
+	if activityParserGap {
+		m.SetParserGapCheck(true)
+	}
+	if activityDocker {
+		m.EnableDocker()
+	}
+	if activityKubernetes {
+		m.EnableKubernetes(nil)
+	}
+	if activityTicker {
+		m.EnableTicker()
+	}
+	if activityRigHistory {
+		m.EnableRigHistory()
+	}
+	if cmd.Flags().Changed("screensaver") {
+		m.SetScreensaver(activityScreensaver)
+	}
+	if cmd.Flags().Changed("exit-after") {
+		m.SetExitAfter(activityExitAfter)
+	}
+	if cmd.Flags().Changed("calm") {
+		if err := m.SetCalm(activityCalm); err != nil {
+			return err
+		}
+	}
+	if activityNoAnimate {
+		m.DisableAnimation()
+	}
+	if cmd.Flags().Changed("color-by") {
+		if err := m.SetColorBy(activityColorBy); err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("activity-source") {
+		if err := m.SetActivitySource(activitySource); err != nil {
+			return err
+		}
+	}
+	// A view left by a gt top that exited while idle comes back, unless
+	// this launch asks for a view of its own.
+	if !activityJSON && !activityOnce && !cmd.Flags().Changed("rig") && !cmd.Flags().Changed("role") && !cmd.Flags().Changed("level") &&
+		!cmd.Flags().Changed("convoy") && !cmd.Flags().Changed("by-convoy") && !cmd.Flags().Changed("burndown") {
+		m.RestoreView()
+	}
+	if activityReport != "" {
+		// Relative to the cwd, unlike top.report which is relative to the town.
+		path, err := filepath.Abs(activityReport)
+		if err != nil {
+			return fmt.Errorf("resolving --report: %w", err)
+		}
+		m.SetReportPath(path)
+	}
+	if err := m.SetTheme(activityTheme, nil); err != nil {
+		return err
+	}
+	if !activityJSON && activityUI {
+		background := activityBackground
+		if activityLight {
+			background = "light"
+		} else if activityDark {
+			background = "dark"
+		}
+		if err := activity.SetBackground(background); err != nil {
+			return err
+		}
+	}
+	if activityJSON || activityOnce {
+		return runActivityOnce(m)
+	}
+	if err := addActivitySinks(m, townRoot); err != nil {
+		return err
+	}
+	defer m.CloseSinks()
+	if !activityUI {
+		return runActivityHeadless(m, interval)
+	}
+	m.StartTourOnFirstRun()
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("running activity TUI: %w", err)
 	}
+	if m.AutoExited() {
+		fmt.Println("gt top exited after going unused; run it again to pick up where it left off.")
+	}
 	return nil
 }
 
+// addActivitySinks adds the outputs asked for on the command line.
+func addActivitySinks(m *activity.Model, townRoot string) error {
+	if activityWeb != "" {
+		s, err := activity.NewWebSink(activityWeb)
+		if err != nil {
+			return err
+		}
+		m.AddSink(s)
+	}
+	if activityMetrics != "" {
+		s, err := activity.NewMetricsSink(activityMetrics)
+		if err != nil {
+			m.CloseSinks()
+			return err
+		}
+		m.AddSink(s)
+	}
+	if activityJSONOut != "" {
+		path, err := filepath.Abs(activityJSONOut)
+		if err != nil {
+			m.CloseSinks()
+			return fmt.Errorf("resolving --json-out: %w", err)
+		}
+		m.AddSink(activity.NewJSONFileSink(path))
+	}
+	if activityEvents {
+		if townRoot == "" {
+			m.CloseSinks()
+			return fmt.Errorf("--events needs a town (--town, $GT_TOWN_ROOT, or run inside one)")
+		}
+		m.AddSink(activity.NewEventsSink(townRoot))
+	}
+	if !activityUI && activityWeb == "" && activityMetrics == "" && activityJSONOut == "" && !activityEvents {
+		return fmt.Errorf("--ui=false needs an output: --web, --metrics, --json-out or --events")
+	}
+	return nil
+}
+
+// runActivityOnce polls once and prints what it saw: the status as JSON
+// (--json), the same agent state the TUI draws, or the screen itself
+// (--once).
+func runActivityOnce(m *activity.Model) error {
+	m.SetEmbedded()
+	// No tmux server is a town with no agents running, not a failure. Any
+	// other error is reported before anything is printed, so a script reading
+	// stdout gets a whole snapshot or nothing.
+	if err := m.Poll(); err != nil && !tmux.IsNoServer(err) {
+		return fmt.Errorf("listing tmux sessions: %w", err)
+	}
+	if activityJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(m.ViewSnapshot(time.Now())); err != nil {
+			return err
+		}
+	} else {
+		width, height, err := term.GetSize(int(os.Stdout.Fd()))
+		if err != nil || width <= 0 {
+			width, height = 120, 40
+		}
+		m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+		fmt.Println(m.View())
+	}
+	return nil
+}
+
+// runActivityHeadless polls without the TUI, feeding the sinks, until
+// interrupted.
+func runActivityHeadless(m *activity.Model, interval time.Duration) error {
+	m.SetEmbedded()
+	for _, name := range m.SinkNames() {
+		fmt.Fprintf(os.Stderr, "gt top: %s\n", name)
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		if err := m.Poll(); err != nil {
+			fmt.Fprintf(os.Stderr, "gt top: %v\n", err)
+		}
+		select {
+		case <-sigs:
+			return nil
+		case <-tick.C:
+		}
+	}
+}
+
 // Note: detectActor is defined in sling.go and reused here
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tui/activity"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Dynamic shell completion for gt top, gt activity emit and gt agents.
+// Completion runs without persistentPreRun, so each function finds the town
+// itself, and failures just mean no candidates.
+
+// completionTown returns the town to complete against: --town, else the
+// workspace containing the cwd, else $GT_TOWN_ROOT. Empty when there is none.
+func completionTown() string {
+	if root, err := resolveActivityTown(); err == nil && root != "" {
+		return root
+	}
+	root, _ := workspace.FindFromCwdOrError()
+	return root
+}
+
+// completeRigNames completes the town's registered rigs.
+func completeRigNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	townRoot := completionTown()
+	if townRoot == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSessionNames completes live tmux session names, limited to the
+// town's agents when run inside a town.
+func completeSessionNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	townRoot := completionTown()
+	if townRoot != "" {
+		_ = session.InitRegistry(townRoot) // also selects the town's tmux socket
+	}
+	out, err := tmux.Output(0, "list-sessions", "-F", "#{session_name}")
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, name := range strings.Fields(string(out)) {
+		if townRoot == "" || session.IsKnownSession(name) {
+			names = append(names, name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRoles completes the agent roles gt top can filter on.
+func completeRoles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{
+		constants.RoleMayor, constants.RoleDeacon, constants.RoleDog, constants.RoleWitness,
+		constants.RoleRefinery, constants.RoleCrew, constants.RolePolecat,
+	}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeLevels completes the last name in a comma-separated level list,
+// e.g. "cold,co" -> "cold,cool".
+func completeLevels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix := toComplete[:strings.LastIndex(toComplete, ",")+1]
+	var out []string
+	for _, name := range activity.LevelNames() {
+		if !strings.Contains(","+prefix, ","+name+",") {
+			out = append(out, prefix+name)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeEventType completes gt activity emit's event type argument,
+// including the town's custom types.
+func completeEventType(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return events.LoadRegistry(completionTown()).Types(), cobra.ShellCompDirectiveNoFileComp
+}
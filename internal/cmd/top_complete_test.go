@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCompleteLevels(t *testing.T) {
+	got, _ := completeLevels(nil, nil, "")
+	if !slices.Contains(got, "cold") || !slices.Contains(got, "waiting") {
+		t.Errorf("completeLevels(\"\") = %v", got)
+	}
+	got, _ = completeLevels(nil, nil, "cold,co")
+	if !slices.Contains(got, "cold,cool") || slices.Contains(got, "cold,cold") {
+		t.Errorf("completeLevels(\"cold,co\") = %v, want cool after cold and no repeat", got)
+	}
+}
+
+func TestCompleteRigNames(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigs := `{"version": 1, "rigs": {"gastown": {}, "beads": {}}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(rigs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	oldTown := activityTown
+	t.Cleanup(func() { activityTown = oldTown })
+	activityTown = townRoot
+
+	got, _ := completeRigNames(nil, nil, "")
+	if strings.Join(got, ",") != "beads,gastown" {
+		t.Errorf("completeRigNames = %v, want beads,gastown", got)
+	}
+}
+
+func TestCompleteEventType(t *testing.T) {
+	if got, _ := completeEventType(nil, nil, ""); !slices.Contains(got, "sling") {
+		t.Errorf("completeEventType = %v, want sling among them", got)
+	}
+	if got, _ := completeEventType(nil, []string{"sling"}, ""); len(got) != 0 {
+		t.Errorf("second argument completed to %v", got)
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tui/activity"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var activityMuteFor time.Duration
+
+var activityMuteCmd = &cobra.Command{
+	Use:   "mute <session>...",
+	Short: "Silence gt top's alerts and escalations for agents",
+	Long: `Mute agents left broken on purpose, e.g. while debugging them. A muted
+agent stays in gt top, dimmed, but sets off no alerts, emits no
+loop_detected or limit_hit events for the witness, and is left out of
+gt agents --stale.
+
+Mutes are shared by every gt top watching the town. In gt top, m mutes the
+hovered agent for an hour; pressing it again extends to 8 hours, then until
+unmuted, then unmutes.
+
+Without --for, the mute lasts until gt top unmute.
+
+Examples:
+  gt top mute gt-gastown-Toast --for 2h
+  gt top mute gt-gastown-Toast gt-gastown-Nux
+  gt top unmute gt-gastown-Toast`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeSessionNames,
+	RunE:              runActivityMute,
+}
+
+var activityUnmuteCmd = &cobra.Command{
+	Use:               "unmute <session>...",
+	Short:             "Unmute agents muted in gt top",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeSessionNames,
+	RunE:              runActivityUnmute,
+}
+
+func init() {
+	activityMuteCmd.Flags().DurationVar(&activityMuteFor, "for", 0, "Unmute automatically after this long (e.g. 2h)")
+	activityCmd.AddCommand(activityMuteCmd)
+	activityCmd.AddCommand(activityUnmuteCmd)
+}
+
+// muteTown returns the town to mute in: --town, else the cwd's or $GT_TOWN_ROOT.
+func muteTown() (string, error) {
+	townRoot, err := resolveActivityTown()
+	if err != nil || townRoot != "" {
+		return townRoot, err
+	}
+	townRoot, err = workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", fmt.Errorf("not in a Gas Town workspace (use --town or GT_TOWN_ROOT): %w", err)
+	}
+	return townRoot, nil
+}
+
+func runActivityMute(cmd *cobra.Command, args []string) error {
+	if activityMuteFor < 0 {
+		return fmt.Errorf("--for must not be negative")
+	}
+	townRoot, err := muteTown()
+	if err != nil {
+		return err
+	}
+	until := "until unmuted"
+	if activityMuteFor > 0 {
+		until = "for " + activityMuteFor.String()
+	}
+	for _, session := range args {
+		if err := activity.SetMute(townRoot, session, activityMuteFor); err != nil {
+			return fmt.Errorf("muting %s: %w", session, err)
+		}
+		fmt.Printf("%s Muted %s %s\n", style.Bold.Render("✓"), session, until)
+	}
+	return nil
+}
+
+func runActivityUnmute(cmd *cobra.Command, args []string) error {
+	townRoot, err := muteTown()
+	if err != nil {
+		return err
+	}
+	for _, session := range args {
+		if err := activity.Unmute(townRoot, session); err != nil {
+			return fmt.Errorf("unmuting %s: %w", session, err)
+		}
+		fmt.Printf("%s Unmuted %s\n", style.Bold.Render("✓"), session)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tui/activity"
+)
+
+var activityParseCheckJSON bool
+
+var activityParseCheckCmd = &cobra.Command{
+	Use:   "parse-check [session]",
+	Short: "Show what gt top's pane parser reads from a live session",
+	Long: `Capture a session's pane exactly as gt top does, run it through agent
+detection and the pane parser, and print every derived field (status, tool,
+needs-human, limits, context, startup phase, ...) with the pane lines that
+produced it.
+
+A line is credited with a field when leaving the line out changes the
+field. Lines gt top skips as TUI chrome are dimmed. When gt top shows an
+agent wrong, attach this output (or --json) to the bug report.
+
+Without a session, every monitored session is summarized, one per line.
+
+Examples:
+  gt top parse-check gt-gastown-crew-max
+  gt top parse-check hq-deacon --json > parse.json
+  gt top parse-check`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runActivityParseCheck,
+}
+
+func init() {
+	activityParseCheckCmd.Flags().BoolVar(&activityParseCheckJSON, "json", false, "Output the full report as JSON")
+	activityCmd.AddCommand(activityParseCheckCmd)
+}
+
+func runActivityParseCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := resolveActivityTown()
+	if err != nil {
+		return err
+	}
+	session := ""
+	if len(args) > 0 {
+		session = args[0]
+	}
+
+	m := activity.NewModel(time.Second, townRoot) // town settings: top.chrome, top.include/exclude
+	reports, err := m.ParseCheck(session)
+	if err != nil {
+		return err
+	}
+
+	if activityParseCheckJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reports)
+	}
+	if len(reports) == 0 {
+		fmt.Println("No agent sessions running.")
+		return nil
+	}
+	if session == "" {
+		for _, r := range reports {
+			fmt.Printf("%-28s %-16s %s\n", r.Session, parseCheckAgent(r), parseCheckSummary(r.Fields))
+		}
+		return nil
+	}
+	printParseReport(reports[0])
+	return nil
+}
+
+// printParseReport prints a report's fields, then the pane with the lines
+// behind each field highlighted.
+func printParseReport(r *activity.ParseReport) {
+	fmt.Printf("%s  %s\n\n", style.Bold.Render(r.Session), style.Dim.Render(parseCheckAgent(r)))
+
+	if len(r.Fields) == 0 {
+		fmt.Println(style.Dim.Render("  (no fields derived: gt top shows the agent as idle)"))
+	}
+	width := 0
+	for _, f := range r.Fields {
+		width = max(width, len(f.Name))
+	}
+	for _, f := range r.Fields {
+		fmt.Printf("  %-*s  %s\n", width, f.Name, f.Value)
+	}
+
+	fmt.Println()
+	for i, l := range r.Lines {
+		num := fmt.Sprintf("%3d │ ", i+1)
+		switch {
+		case len(l.Fields) > 0:
+			fmt.Printf("%s%s  %s\n", num, style.Warning.Render(l.Text), style.Info.Render("← "+strings.Join(l.Fields, ", ")))
+		case l.Chrome:
+			fmt.Println(style.Dim.Render(num + l.Text))
+		default:
+			fmt.Println(num + l.Text)
+		}
+	}
+}
+
+// parseCheckAgent renders the detected agent, e.g. "claude 2.0.76".
+func parseCheckAgent(r *activity.ParseReport) string {
+	return strings.TrimSpace(r.AgentType + " " + r.AgentVersion)
+}
+
+// parseCheckSummary renders fields on one line, e.g. "status=Thinking… tool=Bash(ls)".
+func parseCheckSummary(fields []activity.ParseField) string {
+	if len(fields) == 0 {
+		return style.Dim.Render("idle")
+	}
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = f.Name + "=" + f.Value
+	}
+	return strings.Join(parts, " ")
+}
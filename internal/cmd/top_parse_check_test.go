@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/tui/activity"
+)
+
+func TestParseCheckSummary(t *testing.T) {
+	got := parseCheckSummary([]activity.ParseField{{Name: "status", Value: "Thinking…"}, {Name: "tool", Value: "Bash(ls)"}})
+	if got != "status=Thinking… tool=Bash(ls)" {
+		t.Errorf("parseCheckSummary = %q", got)
+	}
+	if got := parseCheckAgent(&activity.ParseReport{AgentType: "opencode"}); got != "opencode" {
+		t.Errorf("parseCheckAgent = %q", got)
+	}
+}
+
+func TestParseCheckCmd_Registered(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"top", "parse-check"})
+	if err != nil || cmd != activityParseCheckCmd {
+		t.Fatalf("Find = %v, %v", cmd, err)
+	}
+}
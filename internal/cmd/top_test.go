@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveActivityTown(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	notTown := t.TempDir()
+
+	oldTown := activityTown
+	t.Cleanup(func() { activityTown = oldTown })
+
+	t.Run("unset", func(t *testing.T) {
+		activityTown = ""
+		t.Setenv("GT_TOWN_ROOT", town)
+		got, err := resolveActivityTown()
+		if err != nil || got != "" {
+			t.Errorf("got %q, %v; want empty, nil (left to workspace lookup)", got, err)
+		}
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		activityTown = town
+		got, err := resolveActivityTown()
+		if err != nil || got != town {
+			t.Errorf("got %q, %v; want %q", got, err, town)
+		}
+	})
+
+	t.Run("not a workspace", func(t *testing.T) {
+		activityTown = notTown
+		_, err := resolveActivityTown()
+		if err == nil || !strings.Contains(err.Error(), "--town") {
+			t.Errorf("err = %v, want --town workspace error", err)
+		}
+	})
+}
@@ -355,7 +355,7 @@ func runTrailHooks(cmd *cobra.Command, args []string) error {
 		since = time.Now().Add(-duration)
 	}
 
-	entries, err := readHookTrailEntries(filepath.Join(townRoot, events.EventsFile), since, trailLimit)
+	entries, err := readHookTrailEntries(filepath.Join(townRoot, events.EventsFile), events.NewVerifier(townRoot), since, trailLimit)
 	if err != nil {
 		return err
 	}
@@ -396,7 +396,9 @@ func runTrailHooks(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func readHookTrailEntries(eventsPath string, since time.Time, limit int) ([]HookEntry, error) {
+// readHookTrailEntries returns the newest hook and unhook events, skipping
+// lines verify rejects.
+func readHookTrailEntries(eventsPath string, verify *events.Verifier, since time.Time, limit int) ([]HookEntry, error) {
 	if limit <= 0 {
 		return []HookEntry{}, nil
 	}
@@ -422,7 +424,7 @@ func readHookTrailEntries(eventsPath string, since time.Time, limit int) ([]Hook
 	entries := make([]HookEntry, 0, entryCap)
 	for i := len(lines) - 1; i >= 0; i-- {
 		line := strings.TrimSpace(lines[i])
-		if line == "" {
+		if line == "" || !verify.Valid([]byte(line)) {
 			continue
 		}
 
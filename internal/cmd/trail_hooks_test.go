@@ -34,7 +34,7 @@ func TestReadHookTrailEntriesMissingFile(t *testing.T) {
 	tmp := t.TempDir()
 	path := filepath.Join(tmp, ".events.jsonl")
 
-	got, err := readHookTrailEntries(path, time.Time{}, 20)
+	got, err := readHookTrailEntries(path, nil, time.Time{}, 20)
 	if err != nil {
 		t.Fatalf("readHookTrailEntries() error = %v", err)
 	}
@@ -75,7 +75,7 @@ func TestReadHookTrailEntriesFiltersAndOrders(t *testing.T) {
 		},
 	})
 
-	got, err := readHookTrailEntries(path, time.Time{}, 10)
+	got, err := readHookTrailEntries(path, nil, time.Time{}, 10)
 	if err != nil {
 		t.Fatalf("readHookTrailEntries() error = %v", err)
 	}
@@ -121,7 +121,7 @@ func TestReadHookTrailEntriesSinceAndLimit(t *testing.T) {
 	})
 
 	since := base.Add(-90 * time.Minute)
-	got, err := readHookTrailEntries(path, since, 1)
+	got, err := readHookTrailEntries(path, nil, since, 1)
 	if err != nil {
 		t.Fatalf("readHookTrailEntries() error = %v", err)
 	}
@@ -132,3 +132,27 @@ func TestReadHookTrailEntriesSinceAndLimit(t *testing.T) {
 		t.Fatalf("entry = %+v, want newest hook gt-203", got[0])
 	}
 }
+
+func TestReadHookTrailEntriesSkipsForged(t *testing.T) {
+	townRoot := signedTestTown(t)
+	path := filepath.Join(townRoot, events.EventsFile)
+	if err := events.LogInTown(townRoot, "gt", events.TypeHook, "rig/polecats/a", map[string]interface{}{"bead": "gt-301"}, events.VisibilityFeed); err != nil {
+		t.Fatalf("LogInTown: %v", err)
+	}
+	forged := events.Event{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Type:      events.TypeHook,
+		Actor:     "rig/polecats/b",
+		Payload:   map[string]interface{}{"bead": "gt-302"},
+	}
+	unsigned, _ := json.Marshal(forged)
+	appendEventLines(t, path, forgedEventLine(t, forged), string(unsigned)+"\n")
+
+	got, err := readHookTrailEntries(path, events.NewVerifier(townRoot), time.Time{}, 10)
+	if err != nil {
+		t.Fatalf("readHookTrailEntries() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Bead != "gt-301" {
+		t.Fatalf("entries = %+v, want only the signed hook gt-301", got)
+	}
+}
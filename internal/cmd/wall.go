@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/tui/activity"
+	"golang.org/x/term"
+)
+
+var (
+	wallRotate   time.Duration // gt wall --rotate: time on each rig page
+	wallInterval float64       // gt wall --interval: poll interval in seconds
+	wallRig      string        // gt wall --rig: show only this rig
+)
+
+// wallRetryDelay is how long gt wall waits before restarting the display
+// after an error.
+const wallRetryDelay = 5 * time.Second
+
+var wallCmd = &cobra.Command{
+	Use:          "wall",
+	GroupID:      GroupDiag,
+	Short:        "Kiosk view of gt top for a wall display",
+	SilenceUsage: true,
+	Long: `Show the town's agents full screen for a display nobody sits at.
+
+gt wall is gt top read from across the room: one rig per page, each agent
+a large light with its name and state under it, scaled up to fill the
+screen. Pages rotate every --rotate; a rig too big for one page gets
+several. The header shows the town, the rig, the score and the clock;
+warnings (tmux not answering, sources down) and a dot per page are at the
+bottom.
+
+There's no help line, hover or mouse, no screensaver and no idle exit.
+←/→ turn the page by hand and q quits; nothing else does. An error
+restarts the display after a few seconds instead of exiting, so the wall
+recovers on its own from a tmux restart or a crash.
+
+Lights:
+  green (blinking)   working
+  blue               recently active, starting or ready
+  amber, gray        going quiet
+  red (blinking)     waiting for a human
+  orange (blinking)  rate limited, or stopped at a usage limit
+  purple             compacting
+  dark gray          idle, muted, scheduled off or not running
+
+Examples:
+  gt wall                    # every rig, 15s a page
+  gt wall --rotate 30s       # slower rotation
+  gt wall --rotate 0         # turn pages with ←/→ only
+  gt wall --rig gastown      # one rig`,
+	RunE: runWall,
+}
+
+func init() {
+	wallCmd.Flags().DurationVar(&wallRotate, "rotate", 15*time.Second, "Time on each rig page (0 = turn pages by hand)")
+	wallCmd.Flags().Float64VarP(&wallInterval, "interval", "n", 3.0, "Refresh interval in seconds")
+	wallCmd.Flags().StringVar(&wallRig, "rig", "", "Show only this rig")
+	wallCmd.Flags().StringVar(&activityTown, "town", "", "Town root (default: detect from cwd, else $GT_TOWN_ROOT)")
+	_ = wallCmd.RegisterFlagCompletionFunc("rig", completeRigNames)
+	rootCmd.AddCommand(wallCmd)
+}
+
+func runWall(cmd *cobra.Command, args []string) error {
+	if wallRotate < 0 {
+		return fmt.Errorf("--rotate must not be negative")
+	}
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return fmt.Errorf("gt wall needs a terminal")
+	}
+	townRoot, err := resolveActivityTown()
+	if err != nil {
+		return err
+	}
+	if err := activity.SetBackground(""); err != nil {
+		return err
+	}
+	for {
+		m := activity.NewModel(time.Duration(wallInterval*float64(time.Second)), townRoot)
+		if err := m.SetAgentFilter(wallRig, "", ""); err != nil {
+			return err
+		}
+		m.EnableWall(wallRotate)
+		err := runWallOnce(m)
+		switch {
+		case err == nil:
+			return nil // q
+		case errors.Is(err, tea.ErrProgramPanic): // also "killed"; restart it
+		case errors.Is(err, tea.ErrProgramKilled):
+			return nil
+		}
+		fmt.Fprintf(os.Stderr, "gt wall: %v; restarting in %s\n", err, wallRetryDelay)
+		time.Sleep(wallRetryDelay)
+	}
+}
+
+// runWallOnce runs the wall display until it quits or fails. A panic
+// outside the program's own recovery comes back as an error.
+func runWallOnce(m *activity.Model) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", tea.ErrProgramPanic, r)
+		}
+	}()
+	_, err = tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
@@ -20,7 +20,7 @@ import (
 // See GH#3006.
 var IdentityEnvVars = []string{
 	"GT_ROLE", "GT_RIG", "GT_CREW", "GT_POLECAT", "GT_DOG_NAME",
-	"GT_SESSION", "GT_AGENT", "BD_ACTOR", "GIT_AUTHOR_NAME", "BEADS_AGENT_NAME",
+	"GT_SESSION", "GT_AGENT", "GT_AGENT_ID", "BD_ACTOR", "GIT_AUTHOR_NAME", "BEADS_AGENT_NAME",
 }
 
 // AgentEnvConfig specifies the configuration for generating agent environment variables.
@@ -143,6 +143,14 @@ func AgentEnv(cfg AgentEnvConfig) map[string]string {
 		}
 	}
 
+	// GT_AGENT_ID is the stable agent identity used to correlate events,
+	// heartbeats, and gt top state with a specific agent. It uses the same
+	// address format as BD_ACTOR but, unlike the tmux session name, survives
+	// session renames and prefix changes.
+	if actor := env["BD_ACTOR"]; actor != "" {
+		env["GT_AGENT_ID"] = actor
+	}
+
 	// Only set GT_ROOT if provided
 	// Empty values would override tmux session environment
 	if cfg.TownRoot != "" {
@@ -57,6 +57,7 @@ func TestAgentEnv_Polecat(t *testing.T) {
 	assertEnv(t, env, "BD_DOLT_AUTO_COMMIT", "off") // gt-5cc2p: prevent manifest contention
 	assertEnv(t, env, "NODE_OPTIONS", "")            // cleared to prevent debugger inheritance
 	assertEnv(t, env, "CLAUDECODE", "")              // cleared to prevent nested session detection
+	assertEnv(t, env, "GT_AGENT_ID", "myrig/polecats/Toast")
 }
 
 func TestAgentEnv_Crew(t *testing.T) {
@@ -130,6 +131,7 @@ func TestAgentEnv_Dog(t *testing.T) {
 	assertEnv(t, env, "GT_DOG_NAME", "alpha")
 	assertEnv(t, env, "BD_ACTOR", "deacon/dogs/alpha")
 	assertEnv(t, env, "GIT_AUTHOR_NAME", "alpha")
+	assertEnv(t, env, "GT_AGENT_ID", "deacon/dogs/alpha")
 	assertEnv(t, env, "GT_ROOT", "/town")
 	assertNotSet(t, env, "GT_RIG")
 }
@@ -146,8 +148,8 @@ func TestIdentityEnvVars_CoversAgentEnvOutput(t *testing.T) {
 	identityKeys := map[string]bool{
 		"GT_ROLE": true, "GT_RIG": true, "GT_CREW": true,
 		"GT_POLECAT": true, "GT_DOG_NAME": true, "GT_SESSION": true,
-		"GT_AGENT": true, "BD_ACTOR": true, "GIT_AUTHOR_NAME": true,
-		"BEADS_AGENT_NAME": true,
+		"GT_AGENT": true, "GT_AGENT_ID": true, "BD_ACTOR": true,
+		"GIT_AUTHOR_NAME": true, "BEADS_AGENT_NAME": true,
 	}
 
 	have := make(map[string]bool, len(IdentityEnvVars))
@@ -88,9 +88,38 @@ type TownSettings struct {
 	// FeedCurator configures event deduplication and aggregation windows.
 	FeedCurator *FeedCuratorConfig `json:"feed_curator,omitempty"`
 
+	// EventSigning turns on HMAC signing of the events log, so readers can
+	// detect forged or tampered events. Nil means events are not signed.
+	EventSigning *EventSigningConfig `json:"event_signing,omitempty"`
+
+	// EventBus has the daemon publish every event written to the events log
+	// to a NATS subject or Redis stream, so hosts without the town's
+	// filesystem can subscribe. Nil means the events file only.
+	EventBus *EventBusConfig `json:"event_bus,omitempty"`
+
+	// GitHubComments has the daemon post selected events as comments on the
+	// GitHub issue or pull request linked to the event's bead. Nil means off.
+	GitHubComments *GitHubCommentsConfig `json:"github_comments,omitempty"`
+
+	// EventTypes declares the town's custom event types by name, for
+	// domain-specific events (deploys, incidents, ...) beside the built-in
+	// ones. Names of built-in types are ignored.
+	EventTypes map[string]*EventTypeConfig `json:"event_types,omitempty"`
+
 	// Convoy configures convoy behavior settings.
 	Convoy *ConvoyConfig `json:"convoy,omitempty"`
 
+	// RigSLAs defines per-rig service-level targets evaluated from the event
+	// feed and shown as badges in gt top. Nil means no SLAs.
+	RigSLAs *RigSLAConfig `json:"rig_slas,omitempty"`
+
+	// Top configures the gt top agent monitor. Nil uses built-in defaults.
+	Top *TopConfig `json:"top,omitempty"`
+
+	// Reports are scheduled town health reports (digest plus SLA/SLO state)
+	// the daemon delivers by mail or command hook. Managed with gt report.
+	Reports []ReportSchedule `json:"reports,omitempty"`
+
 	// CostTier tracks which cost tier preset was applied (informational).
 	// Actual model assignments live in RoleAgents and Agents.
 	// Values: "standard", "economy", "budget", or empty for custom configs.
@@ -193,6 +222,77 @@ type FeedCuratorConfig struct {
 	MinAggregateCount int `json:"min_aggregate_count,omitempty"`
 }
 
+// EventSigningConfig configures HMAC-SHA256 signing of the events log for
+// towns on shared hosts. Every event written gets a signature over its JSON
+// line; readers drop events whose signature doesn't match. The key is a
+// shared secret: keep the settings file readable by the town's user only.
+type EventSigningConfig struct {
+	// Key is the HMAC key. Events are signed and verified while it is set:
+	// readers drop events with a bad signature and unsigned ones, which
+	// anyone who can append to the log could have written.
+	Key string `json:"key"`
+	// AllowUnsigned makes readers accept unsigned events, for a town turning
+	// signing on whose log still has recent events logged before. Turn it
+	// off once those have aged out of the windows that matter.
+	AllowUnsigned bool `json:"allow_unsigned,omitempty"`
+}
+
+// TopMacro is a named sequence of keystrokes gt top sends to an agent's
+// session.
+type TopMacro struct {
+	// Name is shown in the macro menu, e.g. "compact now".
+	Name string `json:"name"`
+	// Steps are sent in order. A step is text typed at the prompt and
+	// submitted with Enter ("/compact"), a tmux key name in angle brackets
+	// sent on its own ("<Escape>", "<C-c>", "<Enter>", "<Down>"), or a pause
+	// ("<wait 500ms>").
+	Steps []string `json:"steps"`
+}
+
+// EventBusConfig configures publishing events to a network event bus
+// alongside the events file. The daemon tails the file and publishes, so
+// writing an event never waits on the bus, and events written while it's
+// unreachable are published once it's back.
+type EventBusConfig struct {
+	// URL is the bus to publish to: "nats://[user:pass@]host[:4222]" or
+	// "redis://[:password@]host[:6379][/db]" ("rediss://" for TLS).
+	URL string `json:"url"`
+	// Subject is the NATS subject or Redis stream key events go to.
+	// Default: "gastown.events".
+	Subject string `json:"subject,omitempty"`
+	// MaxLen caps a Redis stream at about this many events (XADD MAXLEN ~).
+	// Default: 0, uncapped.
+	MaxLen int `json:"max_len,omitempty"`
+	// Timeout bounds connecting and each publish, e.g. "2s". Default: "1s".
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// GitHubCommentsConfig configures posting events to GitHub. A bead links to
+// an issue or pull request through its external_ref (as bd's GitHub sync
+// sets it, e.g. "https://github.com/acme/widgets/issues/42"); merge requests
+// and escalations link through their source issue and related bead.
+// Comments are posted with the gh CLI, so it must be installed and logged in
+// where the daemon runs. {} turns it on with the default events.
+type GitHubCommentsConfig struct {
+	// Events lists the event types posted.
+	// Default: ["merge_failed", "escalation_sent", "bead_closed"].
+	Events []string `json:"events,omitempty"`
+}
+
+// EventTypeConfig is a custom event type: what gt activity emit requires of
+// its payload, and how gt feed and the gt top ticker draw it.
+type EventTypeConfig struct {
+	// Description says what the event means, for people reading settings.
+	Description string `json:"description,omitempty"`
+	// Required lists payload fields every event of the type must carry,
+	// e.g. ["env", "version"].
+	Required []string `json:"required,omitempty"`
+	// Icon is the symbol drawn for the event, e.g. "🚢".
+	Icon string `json:"icon,omitempty"`
+	// Color is the icon's color: an ANSI number ("208") or hex ("#ff8700").
+	Color string `json:"color,omitempty"`
+}
+
 // DefaultFeedCuratorConfig returns a FeedCuratorConfig with sensible defaults.
 func DefaultFeedCuratorConfig() *FeedCuratorConfig {
 	return &FeedCuratorConfig{
@@ -202,6 +302,298 @@ func DefaultFeedCuratorConfig() *FeedCuratorConfig {
 	}
 }
 
+// RigSLAConfig defines service-level targets for rigs. Targets in Default
+// apply to every rig; entries in Rigs override them field by field.
+type RigSLAConfig struct {
+	// Default targets for all rigs.
+	Default *RigSLATargets `json:"default,omitempty"`
+	// Rigs overrides targets per rig name.
+	Rigs map[string]*RigSLATargets `json:"rigs,omitempty"`
+	// Window is how far back breaches are counted. Default: "24h".
+	Window string `json:"window,omitempty"`
+	// SLOWindow is the rolling window failure-rate SLOs are measured over.
+	// Default: "1h".
+	SLOWindow string `json:"slo_window,omitempty"`
+}
+
+// RigSLATargets holds the individual SLA targets. Empty fields have no SLA.
+type RigSLATargets struct {
+	// MergeQueueDrain is the max time from gt done to the refinery merging
+	// the branch (e.g., "30m").
+	MergeQueueDrain string `json:"merge_queue_drain,omitempty"`
+	// EscalationAck is the max time from an escalation being sent to it
+	// being acknowledged or closed (e.g., "10m").
+	EscalationAck string `json:"escalation_ack,omitempty"`
+	// MergeFailureRate is the max share of the refinery's merge attempts
+	// that may fail within the SLO window (e.g., "20%").
+	MergeFailureRate string `json:"merge_failure_rate,omitempty"`
+	// EscalationRate is the max share of finished work (gt done) that may
+	// be escalated within the SLO window (e.g., "10%").
+	EscalationRate string `json:"escalation_rate,omitempty"`
+}
+
+// TargetsFor returns the effective targets for a rig: the rig's overrides
+// on top of Default. Returns an empty RigSLATargets when c is nil.
+func (c *RigSLAConfig) TargetsFor(rig string) RigSLATargets {
+	var t RigSLATargets
+	if c == nil {
+		return t
+	}
+	if c.Default != nil {
+		t = *c.Default
+	}
+	if o := c.Rigs[rig]; o != nil {
+		if o.MergeQueueDrain != "" {
+			t.MergeQueueDrain = o.MergeQueueDrain
+		}
+		if o.EscalationAck != "" {
+			t.EscalationAck = o.EscalationAck
+		}
+		if o.MergeFailureRate != "" {
+			t.MergeFailureRate = o.MergeFailureRate
+		}
+		if o.EscalationRate != "" {
+			t.EscalationRate = o.EscalationRate
+		}
+	}
+	return t
+}
+
+// ReportSchedule is one scheduled town health report.
+type ReportSchedule struct {
+	// Name identifies the schedule (e.g., "daily").
+	Name string `json:"name"`
+
+	// Cron is a five-field cron expression in local time
+	// (e.g., "0 9 * * *" for 09:00 daily).
+	Cron string `json:"cron"`
+
+	// Format is the digest format: "md", "slack" or "html" (default "md").
+	Format string `json:"format,omitempty"`
+
+	// Since is how far back the report looks (default "24h").
+	Since string `json:"since,omitempty"`
+
+	// Rig limits the report to one rig.
+	Rig string `json:"rig,omitempty"`
+
+	// Mail lists e-mail recipients. Sent through the SMTP settings in the
+	// escalation config's contacts.
+	Mail []string `json:"mail,omitempty"`
+
+	// Command is a shell command run with the report on stdin, for
+	// delivering anywhere mail can't (chat webhooks, file drops).
+	Command string `json:"command,omitempty"`
+}
+
+// TopKubernetesConfig configures gt top's Kubernetes agent discovery.
+type TopKubernetesConfig struct {
+	// Selector is the pod label selector
+	// (default "app.kubernetes.io/part-of=gastown").
+	Selector string `json:"selector,omitempty"`
+
+	// Namespace to list pods in: empty for the kubeconfig context's
+	// namespace, "*" for all namespaces.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Context is the kubeconfig context (default: the current one).
+	Context string `json:"context,omitempty"`
+}
+
+// TopConfig configures the gt top agent monitor.
+type TopConfig struct {
+	// ContextTTL is how long a parsed context-remaining percentage is kept
+	// after the pane stops showing it (default "15m").
+	ContextTTL string `json:"context_ttl,omitempty"`
+
+	// SessionLimitTTL is how long a parsed session-limit warning
+	// ("used 95% of your session limit") is kept after it scrolls away
+	// (default "2h").
+	SessionLimitTTL string `json:"session_limit_ttl,omitempty"`
+
+	// Include, when set, limits monitoring to sessions matching one of these
+	// globs (e.g. ["gt-*", "hq-*"]).
+	Include []string `json:"include,omitempty"`
+
+	// Exclude drops sessions matching any of these globs from monitoring and
+	// stats, e.g. helper sessions like "gt-scratch*".
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Transcripts makes gt top read Claude Code session transcripts
+	// (~/.claude/projects) for the current task, tool, and token usage,
+	// falling back to pane parsing when no transcript is found.
+	Transcripts bool `json:"transcripts,omitempty"`
+
+	// Redact masks bead titles, file paths and command arguments on screen,
+	// for monitors that are screen-shared or wall-mounted.
+	Redact bool `json:"redact,omitempty"`
+
+	// Docker makes gt top also monitor agents running in Docker containers
+	// labelled gastown.session=<session name>, reading their log stream
+	// and heartbeat file instead of a tmux pane.
+	Docker bool `json:"docker,omitempty"`
+
+	// Kubernetes makes gt top also monitor agents running in Kubernetes
+	// pods, found with kubectl. Nil disables it.
+	Kubernetes *TopKubernetesConfig `json:"kubernetes,omitempty"`
+
+	// Alerts are escalation ladders for agent conditions, e.g. a NEEDS
+	// HUMAN that goes from a desktop notification to Slack to a phone.
+	Alerts []TopAlertRule `json:"alerts,omitempty"`
+
+	// Theme selects the LED glyphs: "circles" (default), "blocks",
+	// "braille", or "hearts".
+	Theme string `json:"theme,omitempty"`
+
+	// Glyphs overrides the theme's glyph per state: active, recent, warm,
+	// cool, cold, or alarm (e.g. {"warm": "◍", "cool": "◌"}).
+	Glyphs map[string]string `json:"glyphs,omitempty"`
+
+	// Chrome describes the TUI chrome of agent versions newer than gt knows
+	// about, so a Claude Code or OpenCode UI change can be handled without
+	// a gt release.
+	Chrome []TopChromePatterns `json:"chrome,omitempty"`
+
+	// LedgerRetention is how long gt top's status ledger keeps every change
+	// for gt forensics (default "168h"). Older changes are folded into one
+	// snapshot per agent.
+	LedgerRetention string `json:"ledger_retention,omitempty"`
+
+	// Report is a file gt top rewrites on every poll with the agent table
+	// it renders, as Markdown (or org-mode for a .org path), e.g.
+	// ".gastown/STATUS.md". Relative paths are from the town root.
+	Report string `json:"report,omitempty"`
+
+	// Ticker adds a scrolling one-line ticker of recent town events under
+	// the agent panels.
+	Ticker bool `json:"ticker,omitempty"`
+
+	// Screensaver dims gt top to a one-line summary after this long without
+	// a key press or mouse movement, e.g. "15m". Empty never dims.
+	Screensaver string `json:"screensaver,omitempty"`
+
+	// ExitAfter quits gt top after this long without input, e.g. "8h",
+	// freeing the terminal; the next launch restores the view it left.
+	// Empty never exits.
+	ExitAfter string `json:"exit_after,omitempty"`
+
+	// Calm holds the blinking lights steady, except those that need a
+	// human, to cut redraws on big towns and slow links: "on", "off", or
+	// "auto" (default) for 40+ agents over SSH or 100+ locally.
+	Calm string `json:"calm,omitempty"`
+
+	// ColorBy colors the lights by "level" (default) or by "rig": each rig
+	// its own hue, brightness showing activity.
+	ColorBy string `json:"color_by,omitempty"`
+
+	// ActivitySource is what marks an agent active: "window" (default), tmux's
+	// window_activity, which also moves on a human attaching or scrolling;
+	// or "pane", changes in the captured pane content, so only agent output
+	// counts.
+	ActivitySource string `json:"activity_source,omitempty"`
+
+	// ParserGapCheck logs agents that keep producing output with no status
+	// parsed from their pane for 10 minutes to .runtime/top-parser-gaps.jsonl,
+	// with an anonymized fingerprint of the pane, for reporting agent TUI
+	// versions the parsers don't support. Local only.
+	ParserGapCheck bool `json:"parser_gap_check,omitempty"`
+
+	// RigHistory adds a two-row stacked bar chart under each rig header of
+	// its active, idle and problem agent counts each minute of the last 30
+	// minutes.
+	RigHistory bool `json:"rig_history,omitempty"`
+
+	// DoubleClick sets what double-clicking an agent does, per role:
+	// "attach", "queue" (the rig's merge queue), "patrol" (gt feed's
+	// problems view) or "none", e.g. {"crew": "attach", "refinery":
+	// "queue"}. Roles not listed attach, except the refinery (queue) and
+	// the witness and deacon (patrol).
+	DoubleClick map[string]string `json:"double_click,omitempty"`
+
+	// Macros are named operator interventions offered by the M key on the
+	// hovered agent, e.g. "approve-and-continue", "run tests", "compact
+	// now". Each is a sequence of tmux send-keys steps.
+	Macros []TopMacro `json:"macros,omitempty"`
+
+	// Offline are windows when agents are expected to be down, such as a
+	// rig paused every night. During one gt top shows them as "scheduled
+	// off" rather than stuck or not running, and sends no alerts for them.
+	Offline []TopOfflineWindow `json:"offline,omitempty"`
+}
+
+// TopOfflineWindow is a recurring window of expected downtime for a rig's
+// agents or for sessions matching a glob.
+type TopOfflineWindow struct {
+	// Rig names the rig whose agents the window covers.
+	Rig string `json:"rig,omitempty"`
+
+	// Session is a glob of session names the window covers (e.g.
+	// "gt-gastown-*"), instead of or as well as Rig.
+	Session string `json:"session,omitempty"`
+
+	// From and To are local times of day ("22:00", "06:30"). A window that
+	// ends before it starts runs past midnight.
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Days limits the window to the days it starts on ("mon" ... "sun").
+	// Empty is every day.
+	Days []string `json:"days,omitempty"`
+}
+
+// TopChromePatterns is the TUI chrome of an agent from MinVersion on. It
+// extends the chrome gt top would otherwise use for that version.
+type TopChromePatterns struct {
+	// Agent is "claude" or "opencode".
+	Agent string `json:"agent"`
+
+	// MinVersion is the first agent version these patterns apply to
+	// (e.g. "2.1.0"). Empty applies to every version.
+	MinVersion string `json:"min_version,omitempty"`
+
+	// Chrome are regular expressions for status-free lines (prompts,
+	// status bars, key hints), matched against the trimmed line.
+	Chrome []string `json:"chrome,omitempty"`
+
+	// Interrupt is the status-bar hint shown while the agent is working
+	// (default "esc to interrupt" for Claude, "esc interrupt" for OpenCode).
+	Interrupt string `json:"interrupt,omitempty"`
+
+	// PermissionModes are the Claude status-bar segments that precede the
+	// task name (default "bypass permissions on", "auto-accept edits on",
+	// "auto-accept all on").
+	PermissionModes []string `json:"permission_modes,omitempty"`
+}
+
+// TopAlertRule escalates an agent condition through a ladder of
+// notifications while it persists unacknowledged.
+type TopAlertRule struct {
+	// Condition is an activity level ("waiting", "hit_limit", "cold",
+	// "rate_limited", ...), "looping", or "slo_burn" (a rig's rig_slas
+	// failure rate past target).
+	Condition string `json:"condition"`
+
+	// Ladder steps fire in order, each once the condition has lasted
+	// its After. Acknowledging in gt top (a, or A for a filter) or the
+	// condition clearing stops the ladder.
+	Ladder []TopAlertStep `json:"ladder"`
+}
+
+// TopAlertStep is one rung of an alert ladder.
+type TopAlertStep struct {
+	// After is how long the condition must persist first (e.g. "15m").
+	// Default: fire as soon as the condition is seen.
+	After string `json:"after,omitempty"`
+
+	// Notify is the channel:
+	//   - "desktop"             → local desktop notification
+	//   - "slack"               → contacts.slack_webhook (settings/escalation.json)
+	//   - "sms"                 → contacts.sms_webhook to contacts.human_sms
+	//   - "pagerduty:<key>"     → PagerDuty Events API v2 with this routing key
+	//   - "webhook:<url>"       → POST a JSON description of the alert
+	Notify string `json:"notify"`
+}
+
 // OperationalConfig groups operational thresholds that were previously hardcoded
 // as Go constants. All fields are optional — omitted values use compiled-in defaults.
 // This enables per-town tuning without code changes (ZFC: Zero Fixed Constants).
@@ -645,6 +1037,7 @@ type RigSettings struct {
 	Crew       *CrewConfig       `json:"crew,omitempty"`        // crew startup settings
 	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
 	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
+	Budget     *BudgetConfig     `json:"budget,omitempty"`      // daily spend guardrail
 
 	// Agent selects which agent preset to use for this rig.
 	// Can be a built-in preset ("claude", "gemini", "codex", "cursor", "auggie", "amp", "opencode", "copilot")
@@ -672,6 +1065,13 @@ type RigSettings struct {
 	WorkerAgents map[string]string `json:"worker_agents,omitempty"`
 }
 
+// BudgetConfig is a rig's spend guardrail (gt costs budget). Once the rig's
+// recorded spend for the day reaches DailyUSD, the daemon parks the rig and
+// freezes its polecats until an operator overrides it.
+type BudgetConfig struct {
+	DailyUSD float64 `json:"daily_usd"` // 0 = no budget
+}
+
 // CrewConfig represents crew workspace settings for a rig.
 type CrewConfig struct {
 	// Startup is a natural language instruction for which crew to start on boot.
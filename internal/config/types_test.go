@@ -583,6 +583,49 @@ func TestTownSettings_DisabledPatrols_OmitemptyWhenNil(t *testing.T) {
 	}
 }
 
+func TestRigSLAConfig_TargetsFor(t *testing.T) {
+	t.Parallel()
+	var nilCfg *RigSLAConfig
+	if got := nilCfg.TargetsFor("gastown"); got != (RigSLATargets{}) {
+		t.Errorf("nil config TargetsFor = %+v, want empty", got)
+	}
+
+	cfg := &RigSLAConfig{
+		Default: &RigSLATargets{MergeQueueDrain: "30m", EscalationAck: "10m"},
+		Rigs: map[string]*RigSLATargets{
+			"beads": {MergeQueueDrain: "1h"},
+		},
+	}
+	if got := cfg.TargetsFor("gastown"); got.MergeQueueDrain != "30m" || got.EscalationAck != "10m" {
+		t.Errorf("gastown targets = %+v, want defaults", got)
+	}
+	if got := cfg.TargetsFor("beads"); got.MergeQueueDrain != "1h" || got.EscalationAck != "10m" {
+		t.Errorf("beads targets = %+v, want merge override with default ack", got)
+	}
+}
+
+func TestTownSettings_RigSLAs_RoundTrip(t *testing.T) {
+	t.Parallel()
+	settingsPath := filepath.Join(t.TempDir(), "config.json")
+
+	original := NewTownSettings()
+	original.RigSLAs = &RigSLAConfig{
+		Default: &RigSLATargets{MergeQueueDrain: "30m"},
+		Rigs:    map[string]*RigSLATargets{"gastown": {EscalationAck: "10m"}},
+	}
+	if err := SaveTownSettings(settingsPath, original); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+
+	loaded, err := LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		t.Fatalf("LoadOrCreateTownSettings: %v", err)
+	}
+	if loaded.RigSLAs == nil || loaded.RigSLAs.TargetsFor("gastown") != (RigSLATargets{MergeQueueDrain: "30m", EscalationAck: "10m"}) {
+		t.Errorf("RigSLAs did not round-trip: %+v", loaded.RigSLAs)
+	}
+}
+
 // --- Edge cases for config values ---
 
 func TestParseDurationOrDefault_AllWebTimeoutDefaults(t *testing.T) {
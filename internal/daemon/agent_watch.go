@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tui/activity"
+)
+
+// agentWatchInterval is how often agent sessions are polled. Loop detection
+// for Claude agents infers tool starts from the pane, so it needs to see most
+// tool calls.
+const agentWatchInterval = 5 * time.Second
+
+// AgentWatcher polls the town's agent sessions the way gt top does and
+// reports their health for the witness and the feed: limit_hit when an agent
+// hits a rate limit or usage cap, loop_detected when it keeps re-running the
+// same tool, and provider_outage. Detection lives here rather than in gt top
+// so each is reported once, whether zero or many gt top instances are open.
+type AgentWatcher struct {
+	logger func(format string, args ...interface{})
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	model   *activity.Model
+	failing bool // the last poll failed; logged once until one succeeds
+}
+
+// NewAgentWatcher creates a watcher for the town's agents. Follows the
+// GitHubCommenter pattern.
+func NewAgentWatcher(townRoot string, logger func(format string, args ...interface{})) *AgentWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := activity.NewModel(agentWatchInterval, townRoot)
+	m.SetEmbedded()
+	m.SetReporter(events.AgentWatchActor)
+	return &AgentWatcher{
+		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+		model:  m,
+	}
+}
+
+// Start begins the watcher goroutine.
+func (w *AgentWatcher) Start() error {
+	w.wg.Add(1)
+	go w.run()
+	return nil
+}
+
+// Stop gracefully stops the watcher.
+func (w *AgentWatcher) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *AgentWatcher) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(agentWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		w.poll()
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll runs one poll. No tmux server just means no agents are running.
+func (w *AgentWatcher) poll() {
+	err := w.model.Poll()
+	if err != nil && !tmux.IsNoServer(err) {
+		if !w.failing {
+			w.logger("agent watch: %v", err)
+		}
+		w.failing = true
+		return
+	}
+	w.failing = false
+}
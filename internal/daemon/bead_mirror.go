@@ -0,0 +1,104 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+
+	beadsdk "github.com/steveyegge/beads"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// beadMirrorActor attributes mirrored transitions whose beads event has no actor.
+const beadMirrorActor = "daemon/beads"
+
+// BeadEventMirror copies bead lifecycle transitions from the beads stores'
+// event history into the town events log as bead_created, bead_in_progress
+// and bead_closed, so the feed, digest and gt top see issues move even when
+// nothing in gt was involved (e.g. a human running bd directly).
+//
+// It piggybacks on the convoy manager's event poll rather than polling the
+// stores a second time. Events are attributed to the beads event's actor,
+// which bd sets from BD_ACTOR: the agent address for agent sessions.
+type BeadEventMirror struct {
+	townRoot string
+	logger   func(format string, args ...interface{})
+
+	// emit writes an event; defaults to events.LogInTown. Swapped in tests.
+	emit func(townRoot, source, eventType, actor string, payload map[string]interface{}, visibility string) error
+
+	// last is the last status mirrored per issue ID. The poll re-reads a 1s
+	// overlap and a close can arrive both as "closed" and as a status change,
+	// so a transition is only mirrored when it differs from the last one.
+	mu   sync.Mutex
+	last map[string]string
+}
+
+// NewBeadEventMirror creates a mirror writing to the town's events log.
+func NewBeadEventMirror(townRoot string, logger func(format string, args ...interface{})) *BeadEventMirror {
+	return &BeadEventMirror{
+		townRoot: townRoot,
+		logger:   logger,
+		emit:     events.LogInTown,
+		last:     make(map[string]string),
+	}
+}
+
+// beadTransition maps a beads event to the gt event type it mirrors as and
+// the issue's status after it. eventType is empty for events that only move
+// the status (e.g. reopen, blocked) or don't change it at all.
+func beadTransition(e *beadsdk.Event) (eventType, status string) {
+	switch {
+	case e.EventType == beadsdk.EventCreated:
+		return events.TypeBeadCreated, "open"
+	case isCloseEvent(e):
+		return events.TypeBeadClosed, "closed"
+	case e.EventType == beadsdk.EventReopened:
+		return "", "open"
+	case e.EventType == beadsdk.EventStatusChanged && e.NewValue != nil:
+		if *e.NewValue == "in_progress" {
+			return events.TypeBeadInProgress, *e.NewValue
+		}
+		return "", *e.NewValue
+	}
+	return "", ""
+}
+
+// Observe mirrors one beads event from the named store. store may be nil;
+// it is only used to look up the bead's title.
+func (b *BeadEventMirror) Observe(ctx context.Context, storeName string, store beadsdk.Storage, e *beadsdk.Event) {
+	if e == nil || e.IssueID == "" {
+		return
+	}
+	eventType, status := beadTransition(e)
+	if status == "" {
+		return
+	}
+
+	b.mu.Lock()
+	from, known := b.last[e.IssueID]
+	b.last[e.IssueID] = status
+	b.mu.Unlock()
+	if eventType == "" || (known && from == status) {
+		return
+	}
+	if eventType == events.TypeBeadCreated && known {
+		return // already seen moving, so this is a replayed create
+	}
+	if eventType != events.TypeBeadCreated && e.OldValue != nil {
+		from = *e.OldValue
+	}
+
+	var title string
+	if store != nil {
+		if issue, err := store.GetIssue(ctx, e.IssueID); err == nil && issue != nil {
+			title = issue.Title
+		}
+	}
+	actor := e.Actor
+	if actor == "" {
+		actor = beadMirrorActor
+	}
+	if err := b.emit(b.townRoot, "beads", eventType, actor, events.BeadTransitionPayload(e.IssueID, title, storeName, from), events.VisibilityFeed); err != nil {
+		b.logger("Beads: mirroring %s %s: %v", eventType, e.IssueID, err)
+	}
+}
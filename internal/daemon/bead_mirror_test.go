@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+
+	beadsdk "github.com/steveyegge/beads"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+type mirroredEvent struct {
+	eventType, actor string
+	payload          map[string]interface{}
+}
+
+func newTestBeadMirror() (*BeadEventMirror, *[]mirroredEvent) {
+	var got []mirroredEvent
+	b := NewBeadEventMirror("/town", func(string, ...interface{}) {})
+	b.emit = func(townRoot, source, eventType, actor string, payload map[string]interface{}, visibility string) error {
+		got = append(got, mirroredEvent{eventType, actor, payload})
+		return nil
+	}
+	return b, &got
+}
+
+func beadEvent(issue string, typ beadsdk.EventType, actor, from, to string) *beadsdk.Event {
+	e := &beadsdk.Event{IssueID: issue, EventType: typ, Actor: actor}
+	if from != "" {
+		e.OldValue = &from
+	}
+	if to != "" {
+		e.NewValue = &to
+	}
+	return e
+}
+
+func TestBeadEventMirror_Lifecycle(t *testing.T) {
+	b, got := newTestBeadMirror()
+	ctx := context.Background()
+	toast := "gastown/polecats/Toast"
+	for _, e := range []*beadsdk.Event{
+		beadEvent("gt-a", beadsdk.EventCreated, "mayor", "", ""),
+		beadEvent("gt-a", beadsdk.EventCreated, "mayor", "", ""), // poll overlap replay
+		beadEvent("gt-a", beadsdk.EventStatusChanged, toast, "open", "in_progress"),
+		beadEvent("gt-a", beadsdk.EventUpdated, toast, "", ""), // not a transition
+		beadEvent("gt-a", beadsdk.EventStatusChanged, toast, "in_progress", "closed"),
+		beadEvent("gt-a", beadsdk.EventClosed, toast, "", ""), // same close, other event type
+	} {
+		b.Observe(ctx, "gastown", nil, e)
+	}
+
+	want := []string{events.TypeBeadCreated, events.TypeBeadInProgress, events.TypeBeadClosed}
+	if len(*got) != len(want) {
+		t.Fatalf("mirrored %d events (%+v), want %v", len(*got), *got, want)
+	}
+	for i, w := range want {
+		if (*got)[i].eventType != w {
+			t.Errorf("event %d = %s, want %s", i, (*got)[i].eventType, w)
+		}
+	}
+	closed := (*got)[2]
+	if closed.actor != toast || closed.payload["bead"] != "gt-a" || closed.payload["rig"] != "gastown" || closed.payload["from"] != "in_progress" {
+		t.Errorf("closed event = %+v", closed)
+	}
+}
+
+func TestBeadEventMirror_ReopenAndCloseAgain(t *testing.T) {
+	b, got := newTestBeadMirror()
+	ctx := context.Background()
+	for _, e := range []*beadsdk.Event{
+		beadEvent("hq-x", beadsdk.EventClosed, "", "", ""),
+		beadEvent("hq-x", beadsdk.EventReopened, "steve", "", ""),
+		beadEvent("hq-x", beadsdk.EventStatusChanged, "steve", "open", "closed"),
+	} {
+		b.Observe(ctx, "hq", nil, e)
+	}
+
+	if len(*got) != 2 {
+		t.Fatalf("mirrored %+v, want two closes", *got)
+	}
+	first := (*got)[0]
+	if first.actor != beadMirrorActor {
+		t.Errorf("actorless close attributed to %q, want %q", first.actor, beadMirrorActor)
+	}
+	if _, ok := first.payload["rig"]; ok {
+		t.Errorf("hq bead payload has a rig: %+v", first.payload)
+	}
+}
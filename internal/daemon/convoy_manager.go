@@ -112,6 +112,10 @@ type ConvoyManager struct {
 	// been handled. This allows the 1s overlap window above without replaying
 	// the same lifecycle events on every poll.
 	processedLifecycleEvents sync.Map // map[string]bool
+
+	// beadMirror, when set, is shown every polled event so bead transitions
+	// reach the town events log. Set before Start; nil disables mirroring.
+	beadMirror *BeadEventMirror
 }
 
 // NewConvoyManager creates a new convoy manager.
@@ -332,6 +336,12 @@ func (m *ConvoyManager) pollStore(name string, store beadsdk.Storage, stores map
 		return nil
 	}
 
+	if m.beadMirror != nil {
+		for _, e := range events {
+			m.beadMirror.Observe(m.ctx, name, store, e)
+		}
+	}
+
 	// Use hq store for convoy lookups (convoys are hq-* prefixed)
 	hqStore := stores["hq"]
 	if hqStore == nil {
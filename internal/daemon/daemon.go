@@ -62,6 +62,10 @@ type Daemon struct {
 	doltServer    *DoltServerManager
 	krcPruner     *KRCPruner
 	observer      *TmuxObserver
+	reports       *ReportScheduler
+	ghComments    *GitHubCommenter
+	eventBus      *EventBusPublisher
+	agentWatch    *AgentWatcher
 
 	// disabledPatrols is loaded from town settings (disabled_patrols field).
 	// Provides a simple way to disable individual patrol dogs without editing
@@ -423,6 +427,7 @@ func (d *Daemon) Run() (err error) {
 		}
 	}
 	d.convoyManager = NewConvoyManager(d.config.TownRoot, d.logger.Printf, d.gtPath, 0, d.beadsStores, storeOpener, isRigParked)
+	d.convoyManager.beadMirror = NewBeadEventMirror(d.config.TownRoot, d.logger.Printf)
 	if err := d.convoyManager.Start(); err != nil {
 		d.logger.Printf("Warning: failed to start convoy manager: %v", err)
 	} else {
@@ -462,6 +467,38 @@ func (d *Daemon) Run() (err error) {
 		d.logger.Println("Tmux observer started")
 	}
 
+	// Start report scheduler for gt report schedules
+	d.reports = NewReportScheduler(d.config.TownRoot, d.logger.Printf)
+	if err := d.reports.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start report scheduler: %v", err)
+	} else {
+		d.logger.Println("Report scheduler started")
+	}
+
+	// Start GitHub commenter for town settings github_comments
+	d.ghComments = NewGitHubCommenter(d.config.TownRoot, d.logger.Printf)
+	if err := d.ghComments.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start GitHub commenter: %v", err)
+	} else {
+		d.logger.Println("GitHub commenter started")
+	}
+
+	// Start event bus publisher for town settings event_bus
+	d.eventBus = NewEventBusPublisher(d.config.TownRoot, d.logger.Printf)
+	if err := d.eventBus.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start event bus publisher: %v", err)
+	} else {
+		d.logger.Println("Event bus publisher started")
+	}
+
+	// Start agent watch: limit_hit, loop_detected and provider_outage events
+	d.agentWatch = NewAgentWatcher(d.config.TownRoot, d.logger.Printf)
+	if err := d.agentWatch.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start agent watch: %v", err)
+	} else {
+		d.logger.Println("Agent watch started")
+	}
+
 	// Start dedicated Dolt health check ticker if Dolt server is configured.
 	// This runs at a much higher frequency (default 30s) than the general
 	// heartbeat (3 min) so Dolt crashes are detected quickly.
@@ -874,6 +911,10 @@ func (d *Daemon) heartbeat(state *State) {
 		d.dispatchQueuedWork()
 	}
 
+	// 14b. Enforce per-rig daily spend budgets: park rigs over budget and
+	// freeze their polecats. Shells out to `gt costs budget --enforce`.
+	d.enforceBudgets()
+
 	// 15. Rotate oversized Dolt logs (copytruncate for child process fds).
 	// daemon.log uses lumberjack for automatic rotation; this handles Dolt server logs.
 	d.rotateOversizedLogs()
@@ -1839,10 +1880,9 @@ func (d *Daemon) getPatrolRigs(patrol string) []string {
 // Returns false (with reason) if the rig is parked, docked, or has auto_restart blocked/disabled.
 //
 // TODO(#2120): This duplicates parked/docked checking logic from
-// cmd.IsRigParkedOrDocked and cmd.hasRigBeadLabel. Consolidating into a
-// shared package (e.g. internal/rig) would eliminate the third implementation
-// and reduce drift risk. Not done here due to circular import constraints
-// (daemon cannot import cmd).
+// rig.IsParkedOrDocked, which it can't call as is: unlike dispatch, the
+// daemon fails safe, treating a rig whose bead can't be read as not
+// operational.
 func (d *Daemon) isRigOperational(rigName string) (bool, string) {
 	cfg := wisp.NewConfig(d.config.TownRoot, rigName)
 
@@ -1946,6 +1986,30 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("Tmux observer stopped")
 	}
 
+	// Stop report scheduler
+	if d.reports != nil {
+		d.reports.Stop()
+		d.logger.Println("Report scheduler stopped")
+	}
+
+	// Stop GitHub commenter
+	if d.ghComments != nil {
+		d.ghComments.Stop()
+		d.logger.Println("GitHub commenter stopped")
+	}
+
+	// Stop event bus publisher
+	if d.eventBus != nil {
+		d.eventBus.Stop()
+		d.logger.Println("Event bus publisher stopped")
+	}
+
+	// Stop agent watch
+	if d.agentWatch != nil {
+		d.agentWatch.Stop()
+		d.logger.Println("Agent watch stopped")
+	}
+
 	// Push Dolt remotes before stopping the server (if patrol is enabled)
 	d.pushDoltRemotes()
 
@@ -2714,6 +2778,39 @@ func (d *Daemon) pruneStaleBranches() {
 	pruneInDir(d.config.TownRoot, "town-root")
 }
 
+// enforceBudgets shells out to `gt costs budget --enforce`, which pauses
+// rigs that reached their daily spend budget. It prints only when it pauses
+// one, so the log stays quiet otherwise. Towns without budgets skip the
+// cost query altogether.
+func (d *Daemon) enforceBudgets() {
+	if !d.hasRigBudgets() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, "costs", "budget", "--enforce") //nolint:gosec // G204: args are constructed internally
+	setSysProcAttr(cmd)
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = append(os.Environ(), "GT_DAEMON=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("Budget enforcement failed: %v (output: %s)", err, string(out))
+	} else if len(out) > 0 {
+		d.logger.Printf("Budget enforcement: %s", string(out))
+	}
+}
+
+// hasRigBudgets reports whether any known rig sets a daily spend budget.
+func (d *Daemon) hasRigBudgets() bool {
+	for _, rigName := range d.getKnownRigs() {
+		settings, err := agentconfig.LoadRigSettings(agentconfig.RigSettingsPath(filepath.Join(d.config.TownRoot, rigName)))
+		if err == nil && settings.Budget != nil && settings.Budget.DailyUSD > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // dispatchQueuedWork shells out to `gt scheduler run` to dispatch scheduled beads.
 // This avoids circular import between the daemon and cmd packages.
 // Uses a 5m timeout to allow multi-bead dispatch with formula cooking and hook retries.
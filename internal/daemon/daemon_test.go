@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/gofrs/flock"
+	agentconfig "github.com/steveyegge/gastown/internal/config"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -494,6 +495,31 @@ func TestIsRunningFromPID_LiveProcess(t *testing.T) {
 	}
 }
 
+func TestHasRigBudgets(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigs := `{"rigs": {"gastown": {}, "beads": {}}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "mayor", "rigs.json"), []byte(rigs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d := &Daemon{config: &Config{TownRoot: tmpDir}}
+
+	if d.hasRigBudgets() {
+		t.Error("expected false when no rig sets a budget")
+	}
+
+	settings := agentconfig.NewRigSettings()
+	settings.Budget = &agentconfig.BudgetConfig{DailyUSD: 50}
+	if err := agentconfig.SaveRigSettings(agentconfig.RigSettingsPath(filepath.Join(tmpDir, "beads")), settings); err != nil {
+		t.Fatal(err)
+	}
+	if !d.hasRigBudgets() {
+		t.Error("expected true once a rig sets a daily budget")
+	}
+}
+
 func TestHasPendingEvents_EmptyDir(t *testing.T) {
 	tmpDir := t.TempDir()
 	eventDir := filepath.Join(tmpDir, "events", "refinery")
@@ -0,0 +1,190 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+const (
+	// eventBusInterval is how often the events log is checked for lines to
+	// publish. Checking is a stat while nothing's new.
+	eventBusInterval = time.Second
+
+	// eventBusBatch caps the lines sent in one publish, so catching up after
+	// an outage goes out in bounded chunks, each saved once it's accepted.
+	eventBusBatch = 500
+
+	// eventBusMaxBackoff caps the wait between attempts while the bus is
+	// unreachable.
+	eventBusMaxBackoff = time.Minute
+)
+
+// EventBusPublisher publishes the events log to the town's event bus (town
+// settings event_bus). gt processes only append to the log, which is the
+// spool: the publisher tails it from where it left off and publishes over
+// one connection it keeps open. Emitting an event never waits on the bus,
+// and what's written while the bus is down goes out once it's back. On
+// first start it begins at the end, so history isn't replayed.
+type EventBusPublisher struct {
+	townRoot string
+	logger   func(format string, args ...interface{})
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	tailer eventsTail
+
+	// dial is swapped in tests.
+	dial func(cfg *config.EventBusConfig) (events.Bus, error)
+
+	bus     events.Bus
+	busCfg  config.EventBusConfig // what bus was dialed with
+	failing bool                  // the last attempt failed; logged once per outage
+	backoff time.Duration
+	retryAt time.Time
+}
+
+// NewEventBusPublisher creates a publisher. Follows the GitHubCommenter
+// pattern.
+func NewEventBusPublisher(townRoot string, logger func(format string, args ...interface{})) *EventBusPublisher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &EventBusPublisher{
+		townRoot: townRoot,
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+		tailer:   eventsTail{townRoot: townRoot, state: "event_bus_state.json", prefix: "event bus", logger: logger},
+		dial:     events.DialBus,
+	}
+}
+
+// Start begins the publisher goroutine.
+func (p *EventBusPublisher) Start() error {
+	p.wg.Add(1)
+	go p.run()
+	return nil
+}
+
+// Stop gracefully stops the publisher and closes the bus connection.
+func (p *EventBusPublisher) Stop() {
+	p.cancel()
+	p.wg.Wait()
+	p.disconnect()
+}
+
+func (p *EventBusPublisher) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(eventBusInterval)
+	defer ticker.Stop()
+
+	for {
+		p.check(time.Now())
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check publishes the lines written since the last check, in batches,
+// saving the offset after each batch the bus accepts.
+func (p *EventBusPublisher) check(now time.Time) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(p.townRoot))
+	if err != nil {
+		p.logger("event bus: loading town settings: %v", err)
+		return
+	}
+	cfg := settings.EventBus
+	if cfg == nil || cfg.URL == "" {
+		p.disconnect()
+		return
+	}
+	if now.Before(p.retryAt) {
+		return
+	}
+
+	f, tail, offset, ok := p.tailer.open()
+	if !ok {
+		return
+	}
+	defer f.Close()
+
+	for {
+		var lines [][]byte
+		next := offset
+		for len(lines) < eventBusBatch {
+			line, end, ok := tail.Next()
+			if !ok {
+				break
+			}
+			next = end
+			if len(line) > 0 {
+				lines = append(lines, line)
+			}
+		}
+		if err := tail.Err(); err != nil {
+			p.logger("event bus: reading events: %v", err)
+		}
+		if next == offset {
+			return
+		}
+		if len(lines) > 0 {
+			if err := p.publish(cfg, lines); err != nil {
+				p.fail(now, err)
+				return
+			}
+		}
+		offset = next
+		p.tailer.save(offset)
+		if len(lines) < eventBusBatch {
+			return
+		}
+	}
+}
+
+// publish sends lines over the open connection, dialing (or redialing
+// after a settings change) first.
+func (p *EventBusPublisher) publish(cfg *config.EventBusConfig, lines [][]byte) error {
+	if p.bus != nil && p.busCfg != *cfg {
+		p.disconnect()
+	}
+	if p.bus == nil {
+		bus, err := p.dial(cfg)
+		if err != nil {
+			return err
+		}
+		p.bus, p.busCfg = bus, *cfg
+	}
+	if err := p.bus.Publish(lines); err != nil {
+		p.disconnect() // the connection's state is unknown; start afresh
+		return err
+	}
+	if p.failing {
+		p.logger("event bus: publishing again")
+	}
+	p.failing, p.backoff, p.retryAt = false, 0, time.Time{}
+	return nil
+}
+
+// fail logs a failed publish, once per outage, and backs off the retries.
+func (p *EventBusPublisher) fail(now time.Time, err error) {
+	if !p.failing {
+		p.logger("event bus: %v; events wait in the log and go out once it's back", err)
+	}
+	p.failing = true
+	p.backoff = min(max(2*p.backoff, eventBusInterval), eventBusMaxBackoff)
+	p.retryAt = now.Add(p.backoff)
+}
+
+func (p *EventBusPublisher) disconnect() {
+	if p.bus != nil {
+		_ = p.bus.Close()
+		p.bus = nil
+	}
+}
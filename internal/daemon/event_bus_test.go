@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// recordingBus records what's published, failing while down is set.
+type recordingBus struct {
+	published *[]string
+	down      *bool
+	closed    bool
+}
+
+func (b *recordingBus) Publish(lines [][]byte) error {
+	if *b.down {
+		return errors.New("connection refused")
+	}
+	for _, l := range lines {
+		*b.published = append(*b.published, string(l))
+	}
+	return nil
+}
+
+func (b *recordingBus) Close() error { b.closed = true; return nil }
+
+func newTestEventBusPublisher(t *testing.T) (p *EventBusPublisher, town string, published *[]string, down *bool, dials *int, logs *[]string) {
+	t.Helper()
+	town = t.TempDir()
+	writeTownSettings(t, town, func(s *config.TownSettings) {
+		s.EventBus = &config.EventBusConfig{URL: "nats://bus.example:4222"}
+	})
+
+	published, down, dials, logs = new([]string), new(bool), new(int), new([]string)
+	p = NewEventBusPublisher(town, func(format string, args ...interface{}) {
+		*logs = append(*logs, fmt.Sprintf(format, args...))
+	})
+	p.dial = func(*config.EventBusConfig) (events.Bus, error) {
+		*dials++
+		return &recordingBus{published: published, down: down}, nil
+	}
+	return p, town, published, down, dials, logs
+}
+
+func TestEventBusPublisher(t *testing.T) {
+	p, town, published, down, dials, logs := newTestEventBusPublisher(t)
+	now := time.Now()
+
+	// First run starts at the end: history isn't replayed.
+	appendTestEvent(t, town, events.TypeDone, "gastown/polecats/Toast", nil)
+	p.check(now)
+	if len(*published) != 0 {
+		t.Fatalf("replayed history: %q", *published)
+	}
+
+	appendTestEvent(t, town, events.TypeSling, "mayor", nil)
+	appendTestEvent(t, town, events.TypeMail, "mayor", nil)
+	p.check(now)
+	if len(*published) != 2 || !strings.Contains((*published)[0], `"type":"sling"`) {
+		t.Fatalf("published %q, want sling then mail", *published)
+	}
+
+	// The connection is kept for the next check.
+	appendTestEvent(t, town, events.TypeDone, "gastown/polecats/Nux", nil)
+	p.check(now)
+	if len(*published) != 3 || *dials != 1 {
+		t.Errorf("published %d over %d dials, want 3 over one connection", len(*published), *dials)
+	}
+
+	// While the bus is down events wait in the log, logged once, retried
+	// after a backoff; nothing's lost.
+	*down = true
+	appendTestEvent(t, town, events.TypeMerged, "gastown/refinery", nil)
+	p.check(now)
+	p.check(now.Add(p.backoff))
+	if len(*logs) != 1 || !strings.Contains((*logs)[0], "connection refused") {
+		t.Errorf("logs = %q, want the outage logged once", *logs)
+	}
+	appendTestEvent(t, town, events.TypeDone, "gastown/polecats/Rictus", nil)
+	*down = false
+	p.check(now.Add(time.Millisecond)) // still backing off
+	if len(*published) != 3 {
+		t.Fatalf("published during backoff: %q", *published)
+	}
+	p.check(now.Add(eventBusMaxBackoff))
+	if len(*published) != 5 || !strings.Contains((*published)[3], `"type":"merged"`) {
+		t.Errorf("after recovery published %q, want the two that waited", *published)
+	}
+	if last := (*logs)[len(*logs)-1]; last != "event bus: publishing again" {
+		t.Errorf("recovery not logged: %q", *logs)
+	}
+}
+
+func TestEventBusPublisher_PartialLine(t *testing.T) {
+	p, town, published, _, _, _ := newTestEventBusPublisher(t)
+	p.check(time.Now())
+
+	path := filepath.Join(town, events.EventsFile)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(`{"type":"done"}` + "\n" + `{"type":"sl`); err != nil {
+		t.Fatal(err)
+	}
+	p.check(time.Now())
+	if _, err := f.WriteString(`ing"}` + "\n"); err != nil {
+		t.Fatal(err)
+	}
+	p.check(time.Now())
+	if strings.Join(*published, "|") != `{"type":"done"}|{"type":"sling"}` {
+		t.Errorf("published %q, want each line once, whole", *published)
+	}
+}
+
+func TestEventBusPublisher_Unconfigured(t *testing.T) {
+	p, town, published, _, dials, _ := newTestEventBusPublisher(t)
+	if err := config.SaveTownSettings(config.TownSettingsPath(town), config.NewTownSettings()); err != nil {
+		t.Fatal(err)
+	}
+	appendTestEvent(t, town, events.TypeDone, "gastown/polecats/Toast", nil)
+	p.check(time.Now())
+	p.check(time.Now())
+	if len(*published) != 0 || *dials != 0 {
+		t.Errorf("published %q over %d dials without an event_bus", *published, *dials)
+	}
+}
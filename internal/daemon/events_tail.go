@@ -0,0 +1,115 @@
+package daemon
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// eventsTail follows the events log for a daemon consumer (the event bus
+// publisher, the GitHub commenter), saving how far the consumer got to a
+// state file under <town>/daemon so a restart picks up where it left off.
+// On first start it begins at the end, so history isn't replayed.
+type eventsTail struct {
+	townRoot string
+	state    string // state file name under <town>/daemon
+	prefix   string // log prefix, e.g. "event bus"
+	logger   func(format string, args ...interface{})
+}
+
+// eventsTailState records how far into the events log has been consumed.
+type eventsTailState struct {
+	Offset int64 `json:"offset"`
+}
+
+// stateFile returns the path to the consumed-offset state file.
+func (t *eventsTail) stateFile() string {
+	return filepath.Join(t.townRoot, "daemon", t.state)
+}
+
+func (t *eventsTail) load() (int64, bool) {
+	var state eventsTailState
+	data, err := os.ReadFile(t.stateFile())
+	if err != nil {
+		return 0, false
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.logger("%s: ignoring unreadable %s: %v", t.prefix, t.stateFile(), err)
+		return 0, false
+	}
+	return state.Offset, true
+}
+
+// save records that the log has been consumed up to offset.
+func (t *eventsTail) save(offset int64) {
+	data, err := json.MarshalIndent(eventsTailState{Offset: offset}, "", "  ")
+	if err == nil {
+		err = os.WriteFile(t.stateFile(), data, 0600)
+	}
+	if err != nil {
+		t.logger("%s: saving state: %v", t.prefix, err)
+	}
+}
+
+// open returns a reader over the lines written since the saved offset, and
+// that offset. ok is false when there's nothing new to read; otherwise the
+// caller closes f once done with the reader.
+func (t *eventsTail) open() (f *os.File, tail *events.TailReader, offset int64, ok bool) {
+	f, err := os.Open(filepath.Join(t.townRoot, events.EventsFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			t.logger("%s: %v", t.prefix, err)
+		} else if _, started := t.load(); !started {
+			t.save(0) // no history to skip: the log's first line is new
+		}
+		return nil, nil, 0, false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		t.logger("%s: %v", t.prefix, err)
+		_ = f.Close()
+		return nil, nil, 0, false
+	}
+
+	offset, ok = t.load()
+	if !ok {
+		// First run: start from now rather than replaying history.
+		t.save(info.Size())
+		_ = f.Close()
+		return nil, nil, 0, false
+	}
+	if offset > info.Size() {
+		offset = 0 // the log was rotated
+	}
+	if offset == info.Size() {
+		_ = f.Close()
+		return nil, nil, 0, false
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		t.logger("%s: %v", t.prefix, err)
+		_ = f.Close()
+		return nil, nil, 0, false
+	}
+	tail = events.NewTailReader(f, offset)
+	if !atLineStart(f, offset) {
+		// Started while a line was being written: skip the rest of it.
+		if _, offset, ok = tail.Next(); !ok {
+			_ = f.Close()
+			return nil, nil, 0, false
+		}
+	}
+	return f, tail, offset, true
+}
+
+// atLineStart reports whether offset begins a line of f.
+func atLineStart(f *os.File, offset int64) bool {
+	if offset == 0 {
+		return true
+	}
+	var b [1]byte
+	_, err := f.ReadAt(b[:], offset-1)
+	return err != nil || b[0] == '\n'
+}
@@ -0,0 +1,291 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+const (
+	// githubCommentInterval is how often the events log is checked for new
+	// events to post.
+	githubCommentInterval = 30 * time.Second
+
+	// githubCommentsPerCheck caps the comments posted per check, so a burst
+	// of events (or a stalled daemon catching up) trickles out instead of
+	// tripping GitHub's secondary rate limits.
+	githubCommentsPerCheck = 20
+
+	// githubLinkHops bounds following merge requests and escalations to the
+	// bead that links to GitHub.
+	githubLinkHops = 3
+)
+
+// defaultGitHubCommentEvents are the event types posted when
+// github_comments.events is unset.
+var defaultGitHubCommentEvents = []string{events.TypeMergeFailed, events.TypeEscalationSent, events.TypeBeadClosed}
+
+// GitHubCommenter posts selected events (town settings github_comments) as
+// comments on the GitHub issue or pull request linked to each event's bead,
+// so stakeholders who live in GitHub see merges fail, escalations go out and
+// work close without a terminal. It tails the events log from where it left
+// off; on first start it begins at the end, so history isn't replayed.
+type GitHubCommenter struct {
+	townRoot string
+	logger   func(format string, args ...interface{})
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	tailer eventsTail
+	dedup  events.Dedup // a retried event is posted once
+
+	// lookup and post are swapped in tests.
+	lookup func(townRoot, id string) (*beads.Issue, error)
+	post   func(ctx context.Context, target githubTarget, body string) error
+}
+
+// githubTarget is an issue or pull request to comment on.
+type githubTarget struct {
+	host, owner, repo string
+	number            int
+}
+
+func (t githubTarget) String() string {
+	return fmt.Sprintf("%s/%s#%d", t.owner, t.repo, t.number)
+}
+
+// NewGitHubCommenter creates a commenter. Follows the ReportScheduler pattern.
+func NewGitHubCommenter(townRoot string, logger func(format string, args ...interface{})) *GitHubCommenter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &GitHubCommenter{
+		townRoot: townRoot,
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+		tailer:   eventsTail{townRoot: townRoot, state: "github_comments_state.json", prefix: "github comments", logger: logger},
+		lookup: func(townRoot, id string) (*beads.Issue, error) {
+			return beads.New(townRoot).Show(id)
+		},
+		post: postGitHubComment,
+	}
+}
+
+// Start begins the commenter goroutine.
+func (c *GitHubCommenter) Start() error {
+	c.wg.Add(1)
+	go c.run()
+	return nil
+}
+
+// Stop gracefully stops the commenter.
+func (c *GitHubCommenter) Stop() {
+	c.cancel()
+	c.wg.Wait()
+}
+
+func (c *GitHubCommenter) run() {
+	defer c.wg.Done()
+
+	c.check()
+
+	ticker := time.NewTicker(githubCommentInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			c.check()
+		}
+	}
+}
+
+// check posts the selected events written since the last check.
+func (c *GitHubCommenter) check() {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(c.townRoot))
+	if err != nil {
+		c.logger("github comments: loading town settings: %v", err)
+		return
+	}
+	cfg := settings.GitHubComments
+	if cfg == nil {
+		return
+	}
+	selected := cfg.Events
+	if len(selected) == 0 {
+		selected = defaultGitHubCommentEvents
+	}
+
+	f, tail, offset, ok := c.tailer.open()
+	if !ok {
+		return
+	}
+	defer f.Close()
+
+	verify := events.NewVerifier(c.townRoot)
+	posted := 0
+	for posted < githubCommentsPerCheck {
+		line, end, ok := tail.Next()
+		if !ok {
+			break // caught up, or a partly written line; read it whole next check
+		}
+		offset = end
+
+		var evt events.Event
+		if json.Unmarshal(line, &evt) != nil || !slices.Contains(selected, evt.Type) || !verify.Valid(line) || c.dedup.Seen(evt.IdempotencyKey) {
+			continue
+		}
+		beadID := eventBead(evt)
+		if beadID == "" {
+			continue
+		}
+		target, ok := c.resolveTarget(beadID)
+		if !ok {
+			continue
+		}
+		// Move on even if posting fails: retrying every check would spam
+		// the issue once GitHub recovers.
+		posted++
+		ctx, cancel := context.WithTimeout(c.ctx, 30*time.Second)
+		err := c.post(ctx, target, githubCommentBody(evt, beadID))
+		cancel()
+		if err != nil {
+			c.logger("github comments: %s on %s: %v", evt.Type, target, err)
+			continue
+		}
+		c.logger("github comments: posted %s for %s on %s", evt.Type, beadID, target)
+	}
+	if err := tail.Err(); err != nil {
+		c.logger("github comments: reading events: %v", err)
+	}
+	c.tailer.save(offset)
+}
+
+// eventBead returns the bead an event is about, "" if none.
+func eventBead(evt events.Event) string {
+	for _, key := range []string{"bead", "issue", "mr", "escalation_id", "id"} {
+		if s, ok := evt.Payload[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// resolveTarget finds the GitHub issue or pull request a bead links to:
+// its own external_ref, or else a merge request's source issue's or an
+// escalation's related bead's.
+func (c *GitHubCommenter) resolveTarget(id string) (githubTarget, bool) {
+	for hop := 0; hop < githubLinkHops && id != ""; hop++ {
+		issue, err := c.lookup(c.townRoot, id)
+		if err != nil || issue == nil {
+			return githubTarget{}, false
+		}
+		if t, ok := parseGitHubRef(issue.ExternalRef); ok {
+			return t, true
+		}
+		id = ""
+		if mr := beads.ParseMRFields(issue); mr != nil && mr.SourceIssue != "" {
+			id = mr.SourceIssue
+		} else if esc := beads.ParseEscalationFields(issue.Description); esc.RelatedBead != "" {
+			id = esc.RelatedBead
+		}
+	}
+	return githubTarget{}, false
+}
+
+// parseGitHubRef parses an issue or pull request URL, e.g.
+// "https://github.com/acme/widgets/issues/42" or ".../pull/7". GitHub
+// Enterprise hosts work too.
+func parseGitHubRef(ref string) (githubTarget, bool) {
+	u, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return githubTarget{}, false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || (parts[2] != "issues" && parts[2] != "pull") {
+		return githubTarget{}, false
+	}
+	n, err := strconv.Atoi(parts[3])
+	if err != nil || n <= 0 {
+		return githubTarget{}, false
+	}
+	return githubTarget{host: u.Host, owner: parts[0], repo: parts[1], number: n}, true
+}
+
+// githubCommentBody renders an event as a Markdown comment.
+func githubCommentBody(evt events.Event, beadID string) string {
+	str := func(key string) string {
+		s, _ := evt.Payload[key].(string)
+		return s
+	}
+	var b strings.Builder
+	switch evt.Type {
+	case events.TypeMergeFailed:
+		fmt.Fprintf(&b, "❌ **Merge failed** for `%s`", beadID)
+		if branch := str("branch"); branch != "" {
+			fmt.Fprintf(&b, " (branch `%s`)", branch)
+		}
+	case events.TypeEscalationSent:
+		b.WriteString("🚨 **Escalated**")
+		if sev := str("severity"); sev != "" {
+			fmt.Fprintf(&b, " (%s)", sev)
+		}
+		if evt.Actor != "" {
+			fmt.Fprintf(&b, " by %s", evt.Actor)
+		}
+	case events.TypeBeadClosed:
+		fmt.Fprintf(&b, "✅ **`%s` closed**", beadID)
+		if evt.Actor != "" {
+			fmt.Fprintf(&b, " by %s", evt.Actor)
+		}
+	default:
+		fmt.Fprintf(&b, "**%s** for `%s`", evt.Type, beadID)
+		if evt.Actor != "" {
+			fmt.Fprintf(&b, " by %s", evt.Actor)
+		}
+	}
+	for _, key := range []string{"reason", "message"} {
+		if s := str(key); s != "" {
+			b.WriteString("\n\n> " + strings.ReplaceAll(s, "\n", "\n> "))
+			break
+		}
+	}
+	b.WriteString("\n\n<sub>Posted by Gas Town from the town events log")
+	if evt.Timestamp != "" {
+		b.WriteString(" · " + evt.Timestamp)
+	}
+	b.WriteString("</sub>")
+	return b.String()
+}
+
+// postGitHubComment posts a comment with the gh CLI. Pull requests take
+// comments through the issues API too.
+func postGitHubComment(ctx context.Context, t githubTarget, body string) error {
+	args := []string{"api", "--method", "POST",
+		fmt.Sprintf("repos/%s/%s/issues/%d/comments", t.owner, t.repo, t.number),
+		"-f", "body=" + body}
+	if t.host != "github.com" {
+		args = append(args, "--hostname", t.host)
+	}
+	out, err := exec.CommandContext(ctx, "gh", args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+type postedComment struct {
+	target githubTarget
+	body   string
+}
+
+func newTestGitHubCommenter(t *testing.T, cfg *config.GitHubCommentsConfig, issues map[string]*beads.Issue) (*GitHubCommenter, *[]postedComment) {
+	t.Helper()
+	town := t.TempDir()
+	writeTownSettings(t, town, func(s *config.TownSettings) { s.GitHubComments = cfg })
+
+	var posted []postedComment
+	c := NewGitHubCommenter(town, func(string, ...interface{}) {})
+	c.lookup = func(_, id string) (*beads.Issue, error) {
+		if issue, ok := issues[id]; ok {
+			return issue, nil
+		}
+		return nil, errors.New("not found")
+	}
+	c.post = func(_ context.Context, target githubTarget, body string) error {
+		posted = append(posted, postedComment{target, body})
+		return nil
+	}
+	return c, &posted
+}
+
+func appendTestEvent(t *testing.T, town, typ, actor string, payload map[string]interface{}) {
+	t.Helper()
+	line, err := json.Marshal(events.Event{Timestamp: "2026-10-15T09:00:00Z", Source: "gt", Type: typ, Actor: actor, Payload: payload})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(filepath.Join(town, events.EventsFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseGitHubRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want githubTarget
+		ok   bool
+	}{
+		{"https://github.com/acme/widgets/issues/42", githubTarget{"github.com", "acme", "widgets", 42}, true},
+		{"https://github.com/acme/widgets/pull/7/files", githubTarget{"github.com", "acme", "widgets", 7}, true},
+		{"https://ghe.example.com/team/app/issues/3", githubTarget{"ghe.example.com", "team", "app", 3}, true},
+		{"gh-42", githubTarget{}, false},
+		{"https://github.com/acme/widgets", githubTarget{}, false},
+		{"https://github.com/acme/widgets/discussions/5", githubTarget{}, false},
+		{"https://github.com/acme/widgets/issues/new", githubTarget{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseGitHubRef(tt.ref)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseGitHubRef(%q) = %+v, %v; want %+v, %v", tt.ref, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGitHubCommenter_PostsSelectedEvents(t *testing.T) {
+	issues := map[string]*beads.Issue{
+		"gt-abc":  {ID: "gt-abc", ExternalRef: "https://github.com/acme/widgets/issues/42"},
+		"gt-mr1":  {ID: "gt-mr1", Description: "branch: polecat/Toast\nsource_issue: gt-abc"},
+		"hq-esc1": {ID: "hq-esc1", Description: "severity: high\nrelated_bead: gt-mr1"},
+		"gt-xyz":  {ID: "gt-xyz"},
+	}
+	c, posted := newTestGitHubCommenter(t, &config.GitHubCommentsConfig{}, issues)
+
+	appendTestEvent(t, c.townRoot, events.TypeBeadClosed, "gastown/polecats/Toast", map[string]interface{}{"bead": "gt-abc"})
+	c.check() // first run: starts at the end of the log
+	if len(*posted) != 0 {
+		t.Fatalf("first check replayed history: %+v", *posted)
+	}
+
+	appendTestEvent(t, c.townRoot, events.TypeMergeFailed, "gastown/refinery", map[string]interface{}{"mr": "gt-mr1", "branch": "polecat/Toast", "reason": "tests failed"})
+	appendTestEvent(t, c.townRoot, events.TypeEscalationSent, "gastown/witness", map[string]interface{}{"escalation_id": "hq-esc1", "severity": "high", "reason": "stuck"})
+	appendTestEvent(t, c.townRoot, events.TypeBeadClosed, "mayor", map[string]interface{}{"bead": "gt-xyz"}) // no link
+	appendTestEvent(t, c.townRoot, events.TypeSling, "mayor", map[string]interface{}{"bead": "gt-abc"})      // not selected
+	appendTestEvent(t, c.townRoot, events.TypeBeadClosed, "gastown/polecats/Toast", map[string]interface{}{"bead": "gt-abc"})
+	c.check()
+
+	if len(*posted) != 3 {
+		t.Fatalf("posted %d comments, want 3: %+v", len(*posted), *posted)
+	}
+	want := githubTarget{"github.com", "acme", "widgets", 42}
+	for i, sub := range []string{"Merge failed", "Escalated", "closed"} {
+		p := (*posted)[i]
+		if p.target != want || !strings.Contains(p.body, sub) {
+			t.Errorf("comment %d on %s = %q, want %q on %s", i, p.target, p.body, sub, want)
+		}
+	}
+	if !strings.Contains((*posted)[0].body, "> tests failed") {
+		t.Errorf("merge failure comment missing the reason: %q", (*posted)[0].body)
+	}
+
+	c.check()
+	if len(*posted) != 3 {
+		t.Errorf("second check reposted: %d comments", len(*posted))
+	}
+}
+
+func TestGitHubCommenter_SkipsRetries(t *testing.T) {
+	issues := map[string]*beads.Issue{"gt-abc": {ID: "gt-abc", ExternalRef: "https://github.com/acme/widgets/issues/42"}}
+	c, posted := newTestGitHubCommenter(t, &config.GitHubCommentsConfig{}, issues)
+	c.check()
+
+	// A retry that reached the log anyway, under the same key.
+	evt := events.Event{Type: events.TypeBeadClosed, Actor: "mayor", Payload: map[string]interface{}{"bead": "gt-abc"}, IdempotencyKey: "close-gt-abc"}
+	line, err := json.Marshal(evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(c.townRoot, events.EventsFile), append(append(line, '\n'), append(line, '\n')...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c.check()
+	if len(*posted) != 1 {
+		t.Errorf("posted %d comments for one retried event, want 1", len(*posted))
+	}
+}
+
+func TestGitHubCommenter_Disabled(t *testing.T) {
+	issues := map[string]*beads.Issue{"gt-abc": {ID: "gt-abc", ExternalRef: "https://github.com/acme/widgets/issues/42"}}
+	c, posted := newTestGitHubCommenter(t, nil, issues)
+	appendTestEvent(t, c.townRoot, events.TypeBeadClosed, "mayor", map[string]interface{}{"bead": "gt-abc"})
+	c.check()
+	appendTestEvent(t, c.townRoot, events.TypeBeadClosed, "mayor", map[string]interface{}{"bead": "gt-abc"})
+	c.check()
+	if len(*posted) != 0 {
+		t.Errorf("posted %+v without github_comments configured", *posted)
+	}
+}
@@ -0,0 +1,163 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/report"
+)
+
+// reportCheckInterval is how often report schedules are checked. Schedules
+// have minute resolution, so this only needs to land in each minute.
+const reportCheckInterval = 30 * time.Second
+
+// ReportScheduler delivers the scheduled town health reports configured in
+// town settings (gt report schedule). Schedules are re-read every check, so
+// changes take effect without restarting the daemon.
+type ReportScheduler struct {
+	townRoot string
+	logger   func(format string, args ...interface{})
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	// now, build and deliver are swapped in tests.
+	now     func() time.Time
+	build   func(townRoot string, rs config.ReportSchedule, now time.Time) (*report.Report, error)
+	deliver func(townRoot string, rs config.ReportSchedule, r *report.Report) error
+}
+
+// reportRun records a schedule's last delivery. Cron is kept so a changed
+// schedule starts afresh instead of catching up under its old expression.
+type reportRun struct {
+	Cron    string    `json:"cron"`
+	LastRun time.Time `json:"last_run"`
+}
+
+// NewReportScheduler creates a report scheduler. Follows the KRCPruner pattern.
+func NewReportScheduler(townRoot string, logger func(format string, args ...interface{})) *ReportScheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ReportScheduler{
+		townRoot: townRoot,
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+		now:      time.Now,
+		build:    report.Build,
+		deliver:  report.Deliver,
+	}
+}
+
+// Start begins the scheduler goroutine.
+func (s *ReportScheduler) Start() error {
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+// Stop gracefully stops the scheduler.
+func (s *ReportScheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *ReportScheduler) run() {
+	defer s.wg.Done()
+
+	s.check()
+
+	ticker := time.NewTicker(reportCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.check()
+		}
+	}
+}
+
+// stateFile returns the path to the last-run state file.
+func (s *ReportScheduler) stateFile() string {
+	return filepath.Join(s.townRoot, "daemon", "report_state.json")
+}
+
+func (s *ReportScheduler) loadState() map[string]reportRun {
+	state := make(map[string]reportRun)
+	if data, err := os.ReadFile(s.stateFile()); err == nil {
+		if err := json.Unmarshal(data, &state); err != nil {
+			s.logger("reports: ignoring unreadable %s: %v", s.stateFile(), err)
+		}
+	}
+	return state
+}
+
+func (s *ReportScheduler) saveState(state map[string]reportRun) {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err == nil {
+		err = os.WriteFile(s.stateFile(), data, 0600)
+	}
+	if err != nil {
+		s.logger("reports: saving state: %v", err)
+	}
+}
+
+// check delivers every report whose schedule fired since its last run.
+// A schedule seen for the first time starts counting from now.
+func (s *ReportScheduler) check() {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(s.townRoot))
+	if err != nil {
+		s.logger("reports: loading town settings: %v", err)
+		return
+	}
+	if len(settings.Reports) == 0 {
+		return
+	}
+
+	now := s.now()
+	state := s.loadState()
+	live := make(map[string]reportRun, len(settings.Reports))
+	changed := false
+	for _, rs := range settings.Reports {
+		prev, ok := state[rs.Name]
+		if !ok || prev.Cron != rs.Cron {
+			live[rs.Name] = reportRun{Cron: rs.Cron, LastRun: now}
+			changed = true
+			continue
+		}
+		live[rs.Name] = prev
+
+		sched, err := report.ParseCron(rs.Cron)
+		if err != nil {
+			s.logger("reports: %s: %v", rs.Name, err)
+			continue
+		}
+		if !report.Due(sched, prev.LastRun, now) {
+			continue
+		}
+
+		// Record the run even if delivery fails: retrying every check would
+		// resend to the targets that did work.
+		live[rs.Name] = reportRun{Cron: rs.Cron, LastRun: now}
+		changed = true
+		r, err := s.build(s.townRoot, rs, now)
+		if err == nil {
+			err = s.deliver(s.townRoot, rs, r)
+		}
+		if err != nil {
+			s.logger("reports: %s: %v", rs.Name, err)
+			continue
+		}
+		s.logger("reports: delivered %s", rs.Name)
+	}
+	if changed || len(live) != len(state) {
+		s.saveState(live)
+	}
+}
@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/report"
+)
+
+func newTestReportScheduler(t *testing.T, reports ...config.ReportSchedule) (*ReportScheduler, *time.Time, *[]string) {
+	t.Helper()
+	town := t.TempDir()
+	writeTownSettings(t, town, func(s *config.TownSettings) { s.Reports = reports })
+
+	now := time.Date(2026, 10, 15, 8, 59, 40, 0, time.Local)
+	var delivered []string
+	s := NewReportScheduler(town, func(string, ...interface{}) {})
+	s.now = func() time.Time { return now }
+	s.build = func(_ string, rs config.ReportSchedule, _ time.Time) (*report.Report, error) {
+		return &report.Report{Name: rs.Name}, nil
+	}
+	s.deliver = func(_ string, rs config.ReportSchedule, _ *report.Report) error {
+		delivered = append(delivered, rs.Name)
+		return nil
+	}
+	return s, &now, &delivered
+}
+
+// writeTownSettings saves town settings as configure leaves them, and
+// creates the daemon directory the schedulers keep their state in.
+func writeTownSettings(t *testing.T, town string, configure func(*config.TownSettings)) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(town, "daemon"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.NewTownSettings()
+	configure(settings)
+	if err := config.SaveTownSettings(config.TownSettingsPath(town), settings); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReportScheduler_DeliversOncePerFire(t *testing.T) {
+	s, now, delivered := newTestReportScheduler(t, config.ReportSchedule{Name: "daily", Cron: "0 9 * * *"})
+
+	s.check() // first sight: starts counting, delivers nothing
+	*now = now.Add(10 * time.Second)
+	s.check()
+	if len(*delivered) != 0 {
+		t.Fatalf("delivered %v before 09:00", *delivered)
+	}
+
+	*now = now.Add(20 * time.Second) // 09:00:10
+	s.check()
+	*now = now.Add(30 * time.Second)
+	s.check()
+	if len(*delivered) != 1 {
+		t.Fatalf("delivered %v, want one report at 09:00", *delivered)
+	}
+
+	// A restarted scheduler picks its last run up from disk.
+	restarted := NewReportScheduler(s.townRoot, s.logger)
+	restarted.now, restarted.build, restarted.deliver = s.now, s.build, s.deliver
+	*now = now.Add(time.Hour)
+	restarted.check()
+	if len(*delivered) != 1 {
+		t.Errorf("restart resent the report: %v", *delivered)
+	}
+
+	*now = now.AddDate(0, 0, 1)
+	restarted.check()
+	if len(*delivered) != 2 {
+		t.Errorf("delivered %v, want the next day's report", *delivered)
+	}
+}
+
+func TestReportScheduler_FailedDeliveryIsNotRetried(t *testing.T) {
+	s, now, _ := newTestReportScheduler(t, config.ReportSchedule{Name: "daily", Cron: "* * * * *"})
+	attempts := 0
+	s.deliver = func(string, config.ReportSchedule, *report.Report) error {
+		attempts++
+		return errors.New("smtp down")
+	}
+
+	s.check()
+	*now = now.Add(time.Minute)
+	s.check()
+	s.check()
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1 per fire", attempts)
+	}
+}
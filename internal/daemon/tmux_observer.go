@@ -155,7 +155,7 @@ func (o *TmuxObserver) pollSessions() ([]sessionPoll, error) {
 
 	// Get activity timestamps for all GT sessions in a single tmux call.
 	// We use list-sessions with a richer format to avoid N subprocess calls.
-	out, err := tmux.BuildCommand("list-sessions", "-F", "#{session_name}|#{window_activity}|#{session_created}").Output()
+	out, err := tmux.Output(0, "list-sessions", "-F", "#{session_name}|#{window_activity}|#{session_created}")
 	if err != nil {
 		// Fallback: return sessions without activity data
 		return nil, fmt.Errorf("list-sessions with activity: %w", err)
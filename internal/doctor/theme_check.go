@@ -101,8 +101,7 @@ func (c *ThemeCheck) Fix(ctx *CheckContext) error {
 
 // getSessionStatusLeft retrieves the status-left setting for a tmux session.
 func getSessionStatusLeft(session string) (string, error) {
-	cmd := tmux.BuildCommand("show-options", "-t", session, "status-left")
-	output, err := cmd.Output()
+	output, err := tmux.Output(0, "show-options", "-t", session, "status-left")
 	if err != nil {
 		return "", err
 	}
@@ -136,7 +136,7 @@ func (c *LinkedPaneCheck) getSessionPanes(session string) ([]string, error) {
 	// Get pane IDs using tmux list-panes with format
 	// Using #{pane_id} which gives us the unique pane identifier like %123
 	// Note: -s flag lists all panes in all windows of this session (not -a which is global)
-	out, err := tmux.BuildCommand("list-panes", "-t", session, "-s", "-F", "#{pane_id}").Output()
+	out, err := tmux.Output(0, "list-panes", "-t", session, "-s", "-F", "#{pane_id}")
 	if err != nil {
 		return nil, err
 	}
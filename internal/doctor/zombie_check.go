@@ -132,8 +132,8 @@ func (c *ZombieSessionCheck) Fix(ctx *CheckContext) error {
 		}
 
 		// Log pre-death event for audit trail
-		_ = events.LogFeed(events.TypeSessionDeath, sess,
-			events.SessionDeathPayload(sess, "unknown", "zombie cleanup", "gt doctor"))
+		_ = events.LogInTown(ctx.TownRoot, "gt", events.TypeSessionDeath, sess,
+			events.SessionDeathPayload(sess, "unknown", "zombie cleanup", "gt doctor"), events.VisibilityFeed)
 
 		// Use KillSessionWithProcesses to ensure all descendant processes are killed.
 		if err := t.KillSessionWithProcesses(sess); err != nil {
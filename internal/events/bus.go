@@ -0,0 +1,258 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Event bus publication (town settings event_bus): every event line written
+// to the events file is also published to a NATS subject or a Redis stream,
+// so multi-host towns and dashboards can subscribe without sharing the
+// town's filesystem. The lines are published exactly as written, signature
+// included, so subscribers can verify them like file readers do.
+//
+// Writing an event never touches the bus: the events file is the spool.
+// The daemon tails it and publishes over one connection it keeps open
+// (internal/daemon/event_bus.go), so a slow or unreachable bus costs an
+// emit nothing, and events written while it's down go out once it's back.
+
+const (
+	// DefaultBusSubject is the NATS subject or Redis stream key events are
+	// published to when event_bus.subject is unset.
+	DefaultBusSubject = "gastown.events"
+
+	defaultBusTimeout = time.Second
+)
+
+// Bus publishes event lines to a network event bus. A connection can be
+// kept open between publishes; each one is bounded by the timeout.
+type Bus interface {
+	// Publish sends event lines (JSON objects, without trailing newlines),
+	// one message each, returning once the bus has accepted them.
+	Publish(lines [][]byte) error
+	// Close closes the connection.
+	Close() error
+}
+
+// DialBus connects to the bus an event_bus setting names.
+func DialBus(cfg *config.EventBusConfig) (Bus, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("event bus url: %w", err)
+	}
+	subject := cfg.Subject
+	if subject == "" {
+		subject = DefaultBusSubject
+	}
+	timeout := config.ParseDurationOrDefault(cfg.Timeout, defaultBusTimeout)
+	switch u.Scheme {
+	case "nats":
+		return dialNATS(u, subject, timeout)
+	case "redis", "rediss":
+		return dialRedis(u, subject, cfg.MaxLen, timeout)
+	}
+	return nil, fmt.Errorf("event bus url %q: scheme must be nats, redis or rediss", cfg.URL)
+}
+
+// dialBusConn opens the bus's TCP (or TLS) connection, with the handshake
+// bounded by timeout. Publish sets a fresh deadline each time.
+func dialBusConn(u *url.URL, defaultPort string, useTLS bool, timeout time.Duration) (net.Conn, error) {
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), defaultPort)
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	if useTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: u.Hostname()})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("event bus: %w", err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	return conn, nil
+}
+
+// natsBus publishes over the NATS client protocol.
+type natsBus struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	subject string
+	timeout time.Duration
+}
+
+func dialNATS(u *url.URL, subject string, timeout time.Duration) (*natsBus, error) {
+	conn, err := dialBusConn(u, "4222", false, timeout)
+	if err != nil {
+		return nil, err
+	}
+	b := &natsBus{conn: conn, r: bufio.NewReader(conn), subject: subject, timeout: timeout}
+	info, err := b.r.ReadString('\n')
+	if err != nil || !strings.HasPrefix(info, "INFO ") {
+		conn.Close()
+		return nil, fmt.Errorf("event bus: no NATS INFO from %s", u.Host)
+	}
+	var server struct {
+		TLSRequired bool `json:"tls_required"`
+	}
+	if json.Unmarshal([]byte(strings.TrimPrefix(strings.TrimSpace(info), "INFO ")), &server) == nil && server.TLSRequired {
+		conn.Close()
+		return nil, fmt.Errorf("event bus: NATS server %s requires TLS, which isn't supported", u.Host)
+	}
+
+	opts := map[string]interface{}{"verbose": false, "pedantic": false, "name": "gt", "lang": "go"}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			opts["user"], opts["pass"] = u.User.Username(), pass
+		} else {
+			opts["auth_token"] = u.User.Username()
+		}
+	}
+	connect, _ := json.Marshal(opts)
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\n", connect); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("event bus: %w", err)
+	}
+	return b, nil
+}
+
+// Publish sends each line with PUB, then waits for the PONG to a PING, so
+// an error the server reports (e.g. a bad login) surfaces here. PINGs the
+// server sent an idle connection are answered on the way.
+func (b *natsBus) Publish(lines [][]byte) error {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		fmt.Fprintf(&buf, "PUB %s %d\r\n", b.subject, len(line))
+		buf.Write(line)
+		buf.WriteString("\r\n")
+	}
+	buf.WriteString("PING\r\n")
+	_ = b.conn.SetDeadline(time.Now().Add(b.timeout))
+	if _, err := b.conn.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("event bus: %w", err)
+	}
+	for {
+		reply, err := b.r.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("event bus: %w", err)
+		}
+		switch reply = strings.TrimSpace(reply); {
+		case reply == "PONG":
+			return nil
+		case strings.HasPrefix(reply, "-ERR"):
+			return fmt.Errorf("event bus: %s", strings.TrimSpace(strings.TrimPrefix(reply, "-ERR")))
+		case reply == "PING":
+			if _, err := b.conn.Write([]byte("PONG\r\n")); err != nil {
+				return fmt.Errorf("event bus: %w", err)
+			}
+		}
+	}
+}
+
+func (b *natsBus) Close() error { return b.conn.Close() }
+
+// redisBus appends to a Redis stream with XADD.
+type redisBus struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	stream  string
+	maxLen  int
+	timeout time.Duration
+}
+
+func dialRedis(u *url.URL, stream string, maxLen int, timeout time.Duration) (*redisBus, error) {
+	conn, err := dialBusConn(u, "6379", u.Scheme == "rediss", timeout)
+	if err != nil {
+		return nil, err
+	}
+	b := &redisBus{conn: conn, r: bufio.NewReader(conn), stream: stream, maxLen: maxLen, timeout: timeout}
+	if u.User != nil {
+		args := []string{"AUTH"}
+		if pass, ok := u.User.Password(); ok {
+			if u.User.Username() != "" {
+				args = append(args, u.User.Username()) // Redis 6 ACL user
+			}
+			args = append(args, pass)
+		} else {
+			args = append(args, u.User.Username())
+		}
+		if err := b.command(args...); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if _, err := strconv.Atoi(db); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("event bus: redis database %q isn't a number", db)
+		}
+		if err := b.command("SELECT", db); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// Publish adds each line to the stream as an entry with one field, "event".
+func (b *redisBus) Publish(lines [][]byte) error {
+	for _, line := range lines {
+		args := []string{"XADD", b.stream}
+		if b.maxLen > 0 {
+			args = append(args, "MAXLEN", "~", strconv.Itoa(b.maxLen))
+		}
+		if err := b.command(append(args, "*", "event", string(line))...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// command sends one command and reads its reply, returning the error reply
+// if there is one.
+func (b *redisBus) command(args ...string) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&buf, "$%d\r\n%s\r\n", len(a), a)
+	}
+	_ = b.conn.SetDeadline(time.Now().Add(b.timeout))
+	if _, err := b.conn.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("event bus: %w", err)
+	}
+	reply, err := b.r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("event bus: %w", err)
+	}
+	reply = strings.TrimSpace(reply)
+	switch {
+	case strings.HasPrefix(reply, "-"):
+		return fmt.Errorf("event bus: %s", reply[1:])
+	case strings.HasPrefix(reply, "$"): // bulk string, e.g. XADD's entry ID
+		n, err := strconv.Atoi(reply[1:])
+		if err != nil {
+			return fmt.Errorf("event bus: bad reply %q", reply)
+		}
+		if n >= 0 {
+			if _, err := b.r.Discard(n + 2); err != nil {
+				return fmt.Errorf("event bus: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *redisBus) Close() error { return b.conn.Close() }
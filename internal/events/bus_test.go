@@ -0,0 +1,187 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// fakeBus listens on a local port and runs serve on each connection, sending
+// what serve reports on got.
+func fakeBus(t *testing.T, serve func(conn net.Conn, got chan<- string)) (addr string, got <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("can't listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	ch := make(chan string, 16)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				serve(conn, ch)
+			}()
+		}
+	}()
+	return ln.Addr().String(), ch
+}
+
+// serveNATS speaks enough of the NATS protocol for a publisher: INFO,
+// CONNECT, PUB and PING. A CONNECT with the wrong password gets -ERR.
+func serveNATS(conn net.Conn, got chan<- string) {
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "CONNECT "):
+			var opts map[string]interface{}
+			_ = json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &opts)
+			if pass, ok := opts["pass"]; ok && pass != "secret" {
+				fmt.Fprint(conn, "-ERR 'Authorization Violation'\r\n")
+				return
+			}
+		case strings.HasPrefix(line, "PUB "):
+			fields := strings.Fields(line)
+			n, _ := strconv.Atoi(fields[len(fields)-1])
+			payload := make([]byte, n+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+			got <- fields[1] + " " + string(payload[:n])
+		case line == "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		}
+	}
+}
+
+// serveRedis answers RESP commands: AUTH and SELECT with +OK, XADD with an
+// entry ID, reporting each command's arguments.
+func serveRedis(conn net.Conn, got chan<- string) {
+	r := bufio.NewReader(conn)
+	for {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(header)[1:])
+		var args []string
+		for i := 0; i < n; i++ {
+			sizeLine, _ := r.ReadString('\n')
+			size, _ := strconv.Atoi(strings.TrimSpace(sizeLine)[1:])
+			arg := make([]byte, size+2)
+			if _, err := io.ReadFull(r, arg); err != nil {
+				return
+			}
+			args = append(args, string(arg[:size]))
+		}
+		got <- strings.Join(args, " ")
+		switch args[0] {
+		case "AUTH":
+			if args[len(args)-1] != "secret" {
+				fmt.Fprint(conn, "-WRONGPASS invalid username-password pair\r\n")
+				continue
+			}
+			fmt.Fprint(conn, "+OK\r\n")
+		case "XADD":
+			fmt.Fprint(conn, "$15\r\n1700000000000-0\r\n")
+		default:
+			fmt.Fprint(conn, "+OK\r\n")
+		}
+	}
+}
+
+func TestNATSBus(t *testing.T) {
+	addr, got := fakeBus(t, serveNATS)
+	bus, err := DialBus(&config.EventBusConfig{URL: "nats://gt:secret@" + addr, Subject: "town.events"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bus.Close()
+	if err := bus.Publish([][]byte{[]byte(`{"type":"done"}`), []byte(`{"type":"merged"}`)}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`town.events {"type":"done"}`, `town.events {"type":"merged"}`} {
+		if msg := <-got; msg != want {
+			t.Errorf("published %q, want %q", msg, want)
+		}
+	}
+
+	bad, err := DialBus(&config.EventBusConfig{URL: "nats://gt:wrong@" + addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bad.Close()
+	if err := bad.Publish([][]byte{[]byte(`{}`)}); err == nil || !strings.Contains(err.Error(), "Authorization Violation") {
+		t.Errorf("bad password: err = %v, want the server's error", err)
+	}
+}
+
+func TestRedisBus(t *testing.T) {
+	addr, got := fakeBus(t, serveRedis)
+	bus, err := DialBus(&config.EventBusConfig{URL: "redis://:secret@" + addr + "/2", MaxLen: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bus.Close()
+	if err := bus.Publish([][]byte{[]byte(`{"type":"done"}`)}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"AUTH secret", "SELECT 2", `XADD gastown.events MAXLEN ~ 1000 * event {"type":"done"}`} {
+		if msg := <-got; msg != want {
+			t.Errorf("sent %q, want %q", msg, want)
+		}
+	}
+
+	if _, err := DialBus(&config.EventBusConfig{URL: "redis://:wrong@" + addr}); err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("bad password: err = %v, want the server's error", err)
+	}
+}
+
+func TestDialBusBadScheme(t *testing.T) {
+	if _, err := DialBus(&config.EventBusConfig{URL: "kafka://localhost:9092"}); err == nil {
+		t.Error("kafka:// accepted")
+	}
+}
+
+// TestBusReusesConnection checks a kept-open connection publishes again
+// after sitting idle longer than the timeout, as the daemon's does.
+func TestBusReusesConnection(t *testing.T) {
+	for _, tt := range []struct {
+		scheme string
+		serve  func(net.Conn, chan<- string)
+	}{{"nats", serveNATS}, {"redis", serveRedis}} {
+		addr, got := fakeBus(t, tt.serve)
+		bus, err := DialBus(&config.EventBusConfig{URL: tt.scheme + "://" + addr, Timeout: "100ms"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if err := bus.Publish([][]byte{[]byte(`{"type":"done"}`)}); err != nil {
+				t.Fatalf("%s publish %d: %v", tt.scheme, i, err)
+			}
+			if msg := <-got; !strings.Contains(msg, `{"type":"done"}`) {
+				t.Errorf("%s published %q", tt.scheme, msg)
+			}
+			time.Sleep(150 * time.Millisecond)
+		}
+		bus.Close()
+	}
+}
@@ -0,0 +1,149 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// dedupTailSize is how far back from EOF an append looks for an earlier event
+// with the same idempotency key. Retries happen within seconds, so 256KB
+// (several minutes of events) is ample.
+const dedupTailSize int64 = 256 * 1024
+
+// dedupMaxKeys bounds how many keys a Dedup remembers, so long-running
+// readers (feed tails) don't grow without limit.
+const dedupMaxKeys = 4096
+
+// LogInTownOnce is like LogInTown but tags the event with an idempotency key.
+// If an event with the same key was appended recently, the event is dropped.
+// Emitters that retry (agent plugins re-running a hook) use this so a retry
+// doesn't double-count. An empty key behaves exactly like LogInTown.
+func LogInTownOnce(townRoot, key, source, eventType, actor string, payload map[string]interface{}, visibility string) error {
+	if townRoot == "" {
+		return nil
+	}
+	event := Event{
+		Timestamp:      time.Now().UTC().Format(time.RFC3339),
+		Source:         source,
+		Type:           eventType,
+		Actor:          actor,
+		Payload:        payload,
+		Visibility:     visibility,
+		IdempotencyKey: key,
+	}
+	return writeTo(townRoot, event)
+}
+
+// recentKeys caches, per events file, the idempotency keys near its end, so
+// a keyed append reads only what other writers appended since the last one
+// rather than the whole tail.
+var recentKeys = struct {
+	sync.Mutex
+	byFile map[string]*keyTail
+}{byFile: make(map[string]*keyTail)}
+
+// keyTail is one events file's recent keys, read up to offset.
+type keyTail struct {
+	keys   Dedup
+	offset int64
+}
+
+// keyInTail reports whether an event with the given idempotency key was
+// appended recently, recording the key if not. The first call for a file
+// seeds the keys from its last dedupTailSize bytes; later calls read only
+// the bytes appended since, and start over from the tail when the file
+// shrank or grew by more than that. Must be called with the events file lock
+// held. Missing or unreadable files report false (fail open).
+func keyInTail(eventsPath, key string) bool {
+	recentKeys.Lock()
+	defer recentKeys.Unlock()
+
+	var size int64
+	if info, err := os.Stat(eventsPath); err == nil {
+		size = info.Size()
+	} else if !os.IsNotExist(err) {
+		return false
+	}
+
+	kt := recentKeys.byFile[eventsPath]
+	from, cut := int64(0), false
+	if kt != nil && size >= kt.offset && size-kt.offset <= dedupTailSize {
+		from = kt.offset
+	} else {
+		kt = &keyTail{}
+		recentKeys.byFile[eventsPath] = kt
+		if size > dedupTailSize {
+			from, cut = size-dedupTailSize, true
+		}
+	}
+	if size > from {
+		if err := scanKeys(eventsPath, from, size, cut, &kt.keys); err != nil {
+			delete(recentKeys.byFile, eventsPath)
+			return false
+		}
+	}
+	kt.offset = size
+	return kt.keys.Seen(key)
+}
+
+// scanKeys records the idempotency keys of the events in [from, to) of the
+// file. When cut, from falls mid-line and that partial line is skipped.
+func scanKeys(eventsPath string, from, to int64, cut bool, keys *Dedup) error {
+	f, err := os.Open(eventsPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := NewScanner(io.NewSectionReader(f, from, to-from))
+	if cut {
+		sc.Scan()
+	}
+	// Match the field name before parsing, so unkeyed lines are skipped.
+	field := []byte(`"idempotency_key":`)
+	for sc.Scan() {
+		if !bytes.Contains(sc.Bytes(), field) {
+			continue
+		}
+		var e struct {
+			Key string `json:"idempotency_key"`
+		}
+		if json.Unmarshal(sc.Bytes(), &e) == nil {
+			keys.Seen(e.Key)
+		}
+	}
+	return sc.Err()
+}
+
+// Dedup drops repeated events in readers, for duplicates that reached the
+// file anyway (concurrent retries, or emitters writing without the append
+// check). Events without a key are never duplicates. The zero value is ready
+// to use; it is not safe for concurrent use.
+type Dedup struct {
+	seen  map[string]struct{}
+	order []string
+}
+
+// Seen reports whether key was already seen, recording it if not.
+func (d *Dedup) Seen(key string) bool {
+	if key == "" {
+		return false
+	}
+	if _, ok := d.seen[key]; ok {
+		return true
+	}
+	if d.seen == nil {
+		d.seen = make(map[string]struct{})
+	}
+	if len(d.order) >= dedupMaxKeys {
+		delete(d.seen, d.order[0])
+		d.order = d.order[1:]
+	}
+	d.seen[key] = struct{}{}
+	d.order = append(d.order, key)
+	return false
+}
@@ -0,0 +1,133 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readEventsFile(t *testing.T, townRoot string) []Event {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(townRoot, EventsFile))
+	if err != nil {
+		t.Fatalf("reading events file: %v", err)
+	}
+	var out []Event
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("bad line %q: %v", line, err)
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func TestLogInTownOnce_DropsRetries(t *testing.T) {
+	town := t.TempDir()
+	withoutRateLimit(t)
+	payload := map[string]interface{}{"tool": "Bash"}
+
+	for i := 0; i < 3; i++ {
+		if err := LogInTownOnce(town, "started:call-1", "gt", TypeToolStarted, "polecat", payload, VisibilityFeed); err != nil {
+			t.Fatalf("LogInTownOnce: %v", err)
+		}
+	}
+	if err := LogInTownOnce(town, "started:call-2", "gt", TypeToolStarted, "polecat", payload, VisibilityFeed); err != nil {
+		t.Fatalf("LogInTownOnce: %v", err)
+	}
+	// No key: never deduplicated
+	for i := 0; i < 2; i++ {
+		if err := LogInTownOnce(town, "", "gt", TypeToolStarted, "polecat", payload, VisibilityFeed); err != nil {
+			t.Fatalf("LogInTownOnce: %v", err)
+		}
+	}
+
+	got := readEventsFile(t, town)
+	if len(got) != 4 {
+		t.Fatalf("got %d events, want 4 (call-1, call-2, two unkeyed)", len(got))
+	}
+	if got[0].IdempotencyKey != "started:call-1" || got[1].IdempotencyKey != "started:call-2" {
+		t.Errorf("keys = %q, %q", got[0].IdempotencyKey, got[1].IdempotencyKey)
+	}
+}
+
+func TestLogInTownOnce_KeyOutsideTailIsLoggedAgain(t *testing.T) {
+	town := t.TempDir()
+	withoutRateLimit(t)
+	if err := LogInTownOnce(town, "k", "gt", TypeAgentIdle, "polecat", nil, VisibilityFeed); err != nil {
+		t.Fatal(err)
+	}
+	// Push the first event out of the dedup tail.
+	filler := strings.Repeat("x", 1024)
+	for i := int64(0); i <= dedupTailSize/1024; i++ {
+		if err := LogInTown(town, "gt", TypeAgentIdle, "polecat", map[string]interface{}{"pad": filler}, VisibilityAudit); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := LogInTownOnce(town, "k", "gt", TypeAgentIdle, "polecat", nil, VisibilityFeed); err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	for _, e := range readEventsFile(t, town) {
+		if e.IdempotencyKey == "k" {
+			n++
+		}
+	}
+	if n != 2 {
+		t.Errorf("keyed events = %d, want 2 (old key beyond the tail)", n)
+	}
+}
+
+func TestDedup(t *testing.T) {
+	var d Dedup
+	if d.Seen("") || d.Seen("") {
+		t.Error("empty keys are never duplicates")
+	}
+	if d.Seen("a") {
+		t.Error("first sighting reported as seen")
+	}
+	if !d.Seen("a") {
+		t.Error("second sighting not reported")
+	}
+
+	// Bounded: the oldest key is forgotten once the cap is reached.
+	for i := 0; i < dedupMaxKeys; i++ {
+		d.Seen(fmt.Sprintf("k%d", i))
+	}
+	if d.Seen("a") {
+		t.Error("oldest key should have been evicted")
+	}
+	if len(d.seen) != dedupMaxKeys || len(d.order) != dedupMaxKeys {
+		t.Errorf("size = %d/%d, want %d", len(d.seen), len(d.order), dedupMaxKeys)
+	}
+}
+
+func TestLogInTownOnce_SeesOtherWritersKeys(t *testing.T) {
+	town := t.TempDir()
+	withoutRateLimit(t)
+	if err := LogInTownOnce(town, "a", "gt", TypeAgentIdle, "polecat", nil, VisibilityFeed); err != nil {
+		t.Fatal(err)
+	}
+	// Another process appends a keyed event behind this one's cached keys.
+	line, _ := json.Marshal(Event{Type: TypeAgentIdle, Actor: "polecat", IdempotencyKey: "b"})
+	f, err := os.OpenFile(filepath.Join(town, EventsFile), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.Write(append(line, '\n'))
+	_ = f.Close()
+
+	for _, key := range []string{"a", "b"} {
+		if err := LogInTownOnce(town, key, "gt", TypeAgentIdle, "polecat", nil, VisibilityFeed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := readEventsFile(t, town); len(got) != 2 {
+		t.Errorf("got %d events, want 2 (retries of a and b dropped)", len(got))
+	}
+}
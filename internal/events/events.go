@@ -5,10 +5,11 @@
 package events
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofrs/flock"
@@ -23,6 +24,15 @@ type Event struct {
 	Actor      string                 `json:"actor"`
 	Payload    map[string]interface{} `json:"payload,omitempty"`
 	Visibility string                 `json:"visibility"`
+
+	// IdempotencyKey is set by emitters that may retry. Appends skip an event
+	// whose key is already in the recent log, and readers drop repeats.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// Signature is the HMAC of the rest of the line, when the town signs
+	// events (see signing.go). Writers append it; readers check it with a
+	// Verifier.
+	Signature string `json:"sig,omitempty"`
 }
 
 // Visibility levels for events.
@@ -80,20 +90,107 @@ const (
 	TypeToolFinished = "tool_finished" // Agent finished executing a tool
 	TypeAgentIdle    = "agent_idle"    // Agent is idle (waiting for prompt)
 
+	// TypeAgentRestarted tells gt top an agent restarted in place (same tmux
+	// session), so sticky pane-derived state must be dropped.
+	TypeAgentRestarted = "agent_restarted"
+
+	// TypeAgentProgress reports how far through a long task an agent is
+	// ("60% through test suite"); gt top draws it as a bar on the agent's
+	// line. Tool events may carry the same progress fields.
+	TypeAgentProgress = "agent_progress"
+
+	// Agent health events (emitted by the daemon's agent watch for the witness)
+	TypeLoopDetected = "loop_detected" // Agent is repeating the same tool invocation
+	TypeLimitHit     = "limit_hit"     // Agent hit a rate limit or usage cap
+
+	// AgentWatchActor is the actor of the agent health events: the daemon's
+	// agent watch, their one reporter.
+	AgentWatchActor = "daemon/agents"
+
+	// TypeProviderOutage marks the start and end of a probable provider
+	// outage: many agents across rigs hitting API errors or rate limits from
+	// the same provider at once. The daemon reports it once, in place of a
+	// limit_hit per agent.
+	TypeProviderOutage = "provider_outage"
+
+	// TypeBudgetExceeded marks a rig reaching its daily spend budget; the
+	// guardrail (gt costs budget --enforce) parked it and froze its polecats.
+	TypeBudgetExceeded = "budget_exceeded"
+
 	// Compaction events (emitted by OpenCode plugin for gt top)
 	TypeCompactionStarted  = "compaction_started"  // Agent context compaction began
 	TypeCompactionFinished = "compaction_finished" // Agent context compaction finished
 
-	// Bead lifecycle events (emitted by bd CLI)
-	TypeBeadCreated = "bead_created" // Issue created via bd create
-	TypeBeadUpdated = "bead_updated" // Issue updated via bd update
-	TypeBeadClosed  = "bead_closed"  // Issue closed via bd close
+	// Bead lifecycle events (emitted by bd CLI, or mirrored from beads stores by the daemon)
+	TypeBeadCreated    = "bead_created"     // Issue created via bd create
+	TypeBeadUpdated    = "bead_updated"     // Issue updated via bd update
+	TypeBeadInProgress = "bead_in_progress" // Issue moved to in_progress
+	TypeBeadClosed     = "bead_closed"      // Issue closed via bd close
 
 	// Observation events (emitted by daemon tmux observer)
 	TypeAgentObservation = "agent_observation" // Per-agent activity snapshot
 	TypeStateSnapshot    = "state_snapshot"    // Full agent roster checkpoint
+
+	// TypeEventsDropped counts an emitter's events the rate limiter dropped
+	// or coalesced since its last one got through (see ratelimit.go).
+	TypeEventsDropped = "events_dropped"
 )
 
+// KnownTypes returns every event type above, in declaration order. It is
+// the type enum of the published event schema (internal/schema).
+func KnownTypes() []string {
+	return []string{
+		TypeSling,
+		TypeHook,
+		TypeUnhook,
+		TypeHandoff,
+		TypeDone,
+		TypeMail,
+		TypeSpawn,
+		TypeKill,
+		TypeNudge,
+		TypeBoot,
+		TypeHalt,
+		TypeSessionStart,
+		TypeSessionEnd,
+		TypeSessionDeath,
+		TypeMassDeath,
+		TypePatrolStarted,
+		TypePolecatChecked,
+		TypePolecatNudged,
+		TypeEscalationSent,
+		TypeEscalationAcked,
+		TypeEscalationClosed,
+		TypePatrolComplete,
+		TypeMergeStarted,
+		TypeMerged,
+		TypeMergeFailed,
+		TypeMergeSkipped,
+		TypeSchedulerEnqueue,
+		TypeSchedulerDispatch,
+		TypeSchedulerDispatchFailed,
+		TypeSchedulerCloseRetry,
+		TypeToolStarted,
+		TypeToolFinished,
+		TypeAgentIdle,
+		TypeAgentRestarted,
+		TypeAgentProgress,
+		TypeLoopDetected,
+		TypeLimitHit,
+		TypeProviderOutage,
+		TypeBudgetExceeded,
+		TypeCompactionStarted,
+		TypeCompactionFinished,
+		TypeBeadCreated,
+		TypeBeadUpdated,
+		TypeBeadInProgress,
+		TypeBeadClosed,
+		TypeAgentObservation,
+		TypeStateSnapshot,
+		TypeEventsDropped,
+	}
+}
+
 // EventsFile is the name of the raw events log.
 const EventsFile = ".events.jsonl"
 
@@ -126,6 +223,24 @@ func LogWithSource(source, eventType, actor string, payload map[string]interface
 	return write(event)
 }
 
+// LogInTown writes an event to the events log of an explicit town root.
+// Used by long-running consumers like gt top that may run outside the town
+// tree, where cwd-based workspace discovery would silently drop the event.
+func LogInTown(townRoot, source, eventType, actor string, payload map[string]interface{}, visibility string) error {
+	if townRoot == "" {
+		return nil
+	}
+	event := Event{
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Source:     source,
+		Type:       eventType,
+		Actor:      actor,
+		Payload:    payload,
+		Visibility: visibility,
+	}
+	return writeTo(townRoot, event)
+}
+
 // LogFeed is a convenience wrapper for feed-visible events.
 func LogFeed(eventType, actor string, payload map[string]interface{}) error {
 	return Log(eventType, actor, payload, VisibilityFeed)
@@ -146,15 +261,23 @@ func write(event Event) error {
 		// Silently ignore - we're not in a Gas Town workspace
 		return nil
 	}
+	return writeTo(townRoot, event)
+}
 
+// writeTo appends an event to the events file under townRoot.
+// High-frequency agent events are rate limited per emitter (see admit), and
+// events are signed when the town has a signing key.
+func writeTo(townRoot string, event Event) error {
 	eventsPath := filepath.Join(townRoot, EventsFile)
+	key, err := signingKey(townRoot)
+	if err != nil {
+		return err
+	}
 
-	// Marshal event to JSON
-	data, err := json.Marshal(event)
+	data, err := marshalLines(key, event)
 	if err != nil {
-		return fmt.Errorf("marshaling event: %w", err)
+		return err
 	}
-	data = append(data, '\n')
 
 	// Acquire cross-process file lock
 	fl := flock.New(eventsPath + ".lock")
@@ -163,6 +286,25 @@ func write(event Event) error {
 	}
 	defer fl.Unlock() //nolint:errcheck // best-effort unlock
 
+	if event.IdempotencyKey != "" && keyInTail(eventsPath, event.IdempotencyKey) {
+		return nil // retry of an event already logged
+	}
+
+	allowed, counters := admit(townRoot, event)
+	if !allowed {
+		data = nil
+	}
+	if len(counters) > 0 {
+		lead, err := marshalLines(key, counters...)
+		if err != nil {
+			return err
+		}
+		data = append(lead, data...)
+	}
+	if len(data) == 0 {
+		return nil // dropped by the rate limiter
+	}
+
 	f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
 	if err != nil {
 		return fmt.Errorf("opening events file: %w", err)
@@ -424,6 +566,118 @@ func AgentIdlePayload(session string) map[string]interface{} {
 	return p
 }
 
+// AgentProgressPayload creates a payload for agent_progress events.
+// session: tmux session name for matching to agent light
+// pct: percent done, 0-100
+// label: what the agent is working through, may be empty
+func AgentProgressPayload(session string, pct int, label string) map[string]interface{} {
+	p := AgentIdlePayload(session)
+	p["progress"] = pct
+	if label != "" {
+		p["progress_label"] = label
+	}
+	return p
+}
+
+// ParseProgress parses progress as an agent reports it: a percentage or a
+// count of a total, then what it's working through ("60", "60% through test
+// suite", "3/5 shards"). The label is returned as given.
+func ParseProgress(s string) (pct int, label string, err error) {
+	s = strings.TrimSpace(s)
+	head, label, _ := strings.Cut(s, " ")
+	label = strings.TrimSpace(label)
+	if done, total, ok := strings.Cut(head, "/"); ok {
+		d, err1 := strconv.Atoi(done)
+		t, err2 := strconv.Atoi(total)
+		if err1 != nil || err2 != nil || t <= 0 || d < 0 || d > t {
+			return 0, "", fmt.Errorf("progress %q: want N/M with 0 <= N <= M", s)
+		}
+		return d * 100 / t, label, nil
+	}
+	pct, err = strconv.Atoi(strings.TrimSuffix(head, "%"))
+	if err != nil || pct < 0 || pct > 100 {
+		return 0, "", fmt.Errorf("progress %q: want a percentage from 0 to 100, e.g. \"60%% through test suite\"", s)
+	}
+	return pct, label, nil
+}
+
+// AgentRestartedPayload creates a payload for agent_restarted events.
+// session: tmux session name for matching to agent light
+// agentID: stable agent identity (GT_AGENT_ID), may be empty
+func AgentRestartedPayload(session, agentID string) map[string]interface{} {
+	p := AgentIdlePayload(session)
+	if agentID != "" {
+		p["agent_id"] = agentID
+	}
+	return p
+}
+
+// LoopDetectedPayload creates a payload for loop_detected events.
+// session: tmux session name of the looping agent
+// agentID: stable agent identity (GT_AGENT_ID), may be empty
+// tool: the repeated tool invocation, e.g., "Bash(go test ./...)"
+// count: how many times it started within the window
+// window: detection window (e.g., "5m0s")
+func LoopDetectedPayload(session, agentID, tool string, count int, window string) map[string]interface{} {
+	p := map[string]interface{}{
+		"session": session,
+		"tool":    tool,
+		"count":   count,
+		"window":  window,
+	}
+	if agentID != "" {
+		p["agent_id"] = agentID
+	}
+	return p
+}
+
+// LimitHitPayload creates a payload for limit_hit events.
+// session: tmux session name of the limited agent
+// agentID: stable agent identity (GT_AGENT_ID), may be empty
+// kind: "rate_limit" (transient) or "usage_limit" (dead until reset)
+// reset: reset info parsed from the pane (e.g., "resets 2pm"), may be empty
+func LimitHitPayload(session, agentID, kind, reset string) map[string]interface{} {
+	p := map[string]interface{}{
+		"session": session,
+		"kind":    kind,
+	}
+	if agentID != "" {
+		p["agent_id"] = agentID
+	}
+	if reset != "" {
+		p["reset"] = reset
+	}
+	return p
+}
+
+// ProviderOutagePayload creates a payload for provider_outage events.
+// provider: the API provider (e.g., "Anthropic"), or the agent type when unknown
+// status: "started" or "resolved"
+// agents, rigs: how many agents, across how many rigs, were affected
+// since: when the first affected agent started failing
+func ProviderOutagePayload(provider, status string, agents, rigs int, since time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"provider": provider,
+		"status":   status,
+		"agents":   agents,
+		"rigs":     rigs,
+		"since":    since.UTC().Format(time.RFC3339),
+	}
+}
+
+// BudgetExceededPayload creates a payload for budget_exceeded events.
+// rig: the rig over budget
+// spent, budget: the day's recorded spend and the rig's daily budget, in USD
+// paused: the polecat sessions frozen
+func BudgetExceededPayload(rig string, spent, budget float64, paused []string) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":        rig,
+		"spent_usd":  spent,
+		"budget_usd": budget,
+		"paused":     paused,
+	}
+}
+
 // BeadPayload creates a payload for bead lifecycle events (created/updated/closed).
 // id: bead ID (e.g., "wp-abc123")
 // title: bead title
@@ -441,6 +695,29 @@ func BeadPayload(id, title, beadType string, priority int, status string) map[st
 	return p
 }
 
+// BeadTransitionPayload creates a payload for bead lifecycle events the daemon
+// mirrors from a beads store's event history.
+// bead: bead ID
+// title: bead title (empty if it couldn't be looked up)
+// store: the beads store it moved in ("hq" or a rig name)
+// from: previous status (empty for created)
+func BeadTransitionPayload(bead, title, store, from string) map[string]interface{} {
+	p := map[string]interface{}{
+		"bead":  bead,
+		"store": store,
+	}
+	if store != "hq" {
+		p["rig"] = store
+	}
+	if title != "" {
+		p["title"] = title
+	}
+	if from != "" {
+		p["from"] = from
+	}
+	return p
+}
+
 // AgentObservationPayload creates a payload for agent observation events.
 // session: tmux session name
 // name: agent display name
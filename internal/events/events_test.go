@@ -1,6 +1,9 @@
 package events
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -257,3 +260,95 @@ func TestSessionPayload_Minimal(t *testing.T) {
 		t.Error("expected no cwd key when empty")
 	}
 }
+
+func TestLoopDetectedPayload(t *testing.T) {
+	p := LoopDetectedPayload("gt-Toast", "gastown/polecats/Toast", "Bash(make)", 4, "5m0s")
+	if p["tool"] != "Bash(make)" {
+		t.Errorf("tool = %v, want Bash(make)", p["tool"])
+	}
+	if p["count"] != 4 {
+		t.Errorf("count = %v, want 4", p["count"])
+	}
+	if p["agent_id"] != "gastown/polecats/Toast" {
+		t.Errorf("agent_id = %v, want gastown/polecats/Toast", p["agent_id"])
+	}
+
+	p = LoopDetectedPayload("gt-Toast", "", "Bash(make)", 4, "5m0s")
+	if _, ok := p["agent_id"]; ok {
+		t.Error("expected no agent_id key when empty")
+	}
+}
+
+func TestLimitHitPayload(t *testing.T) {
+	p := LimitHitPayload("gt-Toast", "gastown/polecats/Toast", "usage_limit", "resets 2pm")
+	if p["kind"] != "usage_limit" {
+		t.Errorf("kind = %v, want usage_limit", p["kind"])
+	}
+	if p["reset"] != "resets 2pm" {
+		t.Errorf("reset = %v, want resets 2pm", p["reset"])
+	}
+
+	p = LimitHitPayload("gt-Toast", "", "rate_limit", "")
+	if _, ok := p["agent_id"]; ok {
+		t.Error("expected no agent_id key when empty")
+	}
+	if _, ok := p["reset"]; ok {
+		t.Error("expected no reset key when empty")
+	}
+}
+
+func TestLogInTown(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := LogInTown(townRoot, "gt-top", TypeLoopDetected, "gt-top", map[string]interface{}{"tool": "Bash"}, VisibilityFeed); err != nil {
+		t.Fatalf("LogInTown: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(townRoot, EventsFile))
+	if err != nil {
+		t.Fatalf("reading events file: %v", err)
+	}
+	var evt Event
+	if err := json.Unmarshal(data, &evt); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if evt.Type != TypeLoopDetected || evt.Source != "gt-top" {
+		t.Errorf("event = %+v, want type %s source gt-top", evt, TypeLoopDetected)
+	}
+}
+
+func TestParseProgress(t *testing.T) {
+	tests := []struct {
+		in    string
+		pct   int
+		label string
+		ok    bool
+	}{
+		{"60", 60, "", true},
+		{"60% through test suite", 60, "through test suite", true},
+		{"  100%  done ", 100, "done", true},
+		{"3/5 shards", 60, "shards", true},
+		{"0/4", 0, "", true},
+		{"120%", 0, "", false},
+		{"-5", 0, "", false},
+		{"6/5", 0, "", false},
+		{"3/0", 0, "", false},
+		{"halfway", 0, "", false},
+		{"", 0, "", false},
+	}
+	for _, tt := range tests {
+		pct, label, err := ParseProgress(tt.in)
+		if (err == nil) != tt.ok || pct != tt.pct || label != tt.label {
+			t.Errorf("ParseProgress(%q) = %d, %q, %v; want %d, %q, ok=%v", tt.in, pct, label, err, tt.pct, tt.label, tt.ok)
+		}
+	}
+}
+
+func TestAgentProgressPayload(t *testing.T) {
+	p := AgentProgressPayload("gt-gastown-Toast", 60, "through test suite")
+	if p["session"] != "gt-gastown-Toast" || p["progress"] != 60 || p["progress_label"] != "through test suite" {
+		t.Errorf("AgentProgressPayload = %v", p)
+	}
+	if _, ok := AgentProgressPayload("s", 0, "")["progress_label"]; ok {
+		t.Error("empty label should be omitted")
+	}
+}
@@ -0,0 +1,302 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// RateLimitFile holds the emission token buckets, next to the events file.
+// Each gt top emit is its own process, so the buckets live on disk and are
+// read and written under the events file lock.
+const RateLimitFile = ".events-ratelimit.json"
+
+// rateSaveInterval is how long a process keeps limiter state that only
+// spent or refilled tokens before writing it back. Drop counts are written
+// straight away, so an events_dropped counter is never lost.
+const rateSaveInterval = time.Second
+
+// Token-bucket limits per emitter (actor plus session). Plugins emit a
+// tool_started/tool_finished pair per tool call; a burst of 20 with 5 per
+// second refill covers agents running tools back to back while stopping a
+// runaway hook from flooding the feed.
+const (
+	rateBurst     = 20
+	ratePerSecond = 5.0
+
+	// coalesceWindow is how long a repeated tool_started for the tool already
+	// running is dropped. gt top only looks back 15s, so a repeat after that
+	// refreshes the agent's current tool and goes through.
+	coalesceWindow = 10 * time.Second
+
+	// rateIdleExpiry is how long an emitter's bucket is kept after its last
+	// event. Its drop count is flushed when the bucket expires.
+	rateIdleExpiry = 10 * time.Minute
+)
+
+// rateLimitedTypes are the high-frequency event types the limiter applies
+// to. Lifecycle events (done, mail, bead transitions, ...) are never dropped.
+var rateLimitedTypes = map[string]bool{
+	TypeToolStarted:  true,
+	TypeToolFinished: true,
+	TypeAgentIdle:    true,
+}
+
+// closingTypes end the state an earlier event opened (the running tool, a
+// busy agent). They spend a token when one is left but are never dropped:
+// dropping one would leave gt top showing a tool that already finished.
+var closingTypes = map[string]bool{
+	TypeToolFinished: true,
+	TypeAgentIdle:    true,
+}
+
+// rateLimits caches each town's buckets for the process, so a process
+// emitting many events (the daemon, a test) reads and writes the state file
+// at most once per rateSaveInterval rather than per event.
+var rateLimits = struct {
+	sync.Mutex
+	byTown map[string]*townBuckets
+}{byTown: make(map[string]*townBuckets)}
+
+// townBuckets is one town's cached buckets. modTime is the state file's
+// modification time when last read or written: when another process has
+// written it since, the file is read again.
+type townBuckets struct {
+	buckets map[string]*rateBucket
+	modTime time.Time
+	dirty   bool
+	timer   *time.Timer
+}
+
+// rateBucket is one emitter's token bucket, the last event it got through,
+// and how many of its events were dropped since the last counter event.
+type rateBucket struct {
+	Tokens    float64   `json:"tokens"`
+	Updated   time.Time `json:"updated"`
+	LastType  string    `json:"last_type,omitempty"`
+	LastTool  string    `json:"last_tool,omitempty"`
+	LastAt    time.Time `json:"last_at"`
+	Actor     string    `json:"actor"`
+	Session   string    `json:"session,omitempty"`
+	Coalesced int       `json:"coalesced,omitempty"`
+	Limited   int       `json:"limited,omitempty"`
+}
+
+// dropped reports whether the bucket has drops to report.
+func (b *rateBucket) dropped() bool {
+	return b.Coalesced+b.Limited > 0
+}
+
+// droppedEvent is the counter event for a bucket's drops since its last one.
+func (b *rateBucket) droppedEvent(now time.Time) Event {
+	payload := map[string]interface{}{
+		"count":     b.Coalesced + b.Limited,
+		"coalesced": b.Coalesced,
+		"limited":   b.Limited,
+	}
+	if b.Session != "" {
+		payload["session"] = b.Session
+	}
+	return Event{
+		Timestamp:  now.UTC().Format(time.RFC3339),
+		Source:     "gt",
+		Type:       TypeEventsDropped,
+		Actor:      b.Actor,
+		Payload:    payload,
+		Visibility: VisibilityAudit,
+	}
+}
+
+// rateNow is time.Now, swapped in tests.
+var rateNow = time.Now
+
+// payloadString returns a string payload field, or "".
+func payloadString(e Event, key string) string {
+	s, _ := e.Payload[key].(string)
+	return s
+}
+
+// admit applies the rate limit to an event about to be appended under
+// townRoot. It reports whether the event should be written, and returns
+// the events_dropped counters due before it: this emitter's, once one of
+// its events gets through again, and those of emitters gone idle. Must be
+// called with the events file lock held. An unreadable state file starts
+// the buckets afresh (fail open).
+func admit(townRoot string, event Event) (bool, []Event) {
+	if !rateLimitedTypes[event.Type] {
+		return true, nil
+	}
+	now := rateNow()
+	rateLimits.Lock()
+	defer rateLimits.Unlock()
+	tb := loadBuckets(townRoot)
+	buckets := tb.buckets
+
+	session := payloadString(event, "session")
+	key := event.Actor + "\x00" + session
+	b := buckets[key]
+	if b == nil {
+		b = &rateBucket{Tokens: rateBurst, Updated: now, Actor: event.Actor, Session: session}
+		buckets[key] = b
+	}
+	elapsed := now.Sub(b.Updated).Seconds()
+	if elapsed > 0 {
+		b.Tokens = math.Min(rateBurst, b.Tokens+elapsed*ratePerSecond)
+	}
+	b.Updated = now
+
+	tool := payloadString(event, "tool")
+	allowed := true
+	switch {
+	case event.Type == TypeToolStarted && b.LastType == TypeToolStarted && b.LastTool == tool && now.Sub(b.LastAt) < coalesceWindow:
+		b.Coalesced++
+		allowed = false
+	case b.Tokens < 1 && !closingTypes[event.Type]:
+		b.Limited++
+		allowed = false
+	default:
+		b.Tokens = math.Max(0, b.Tokens-1)
+		b.LastType, b.LastTool, b.LastAt = event.Type, tool, now
+	}
+
+	var counters []Event
+	if allowed && b.dropped() {
+		counters = append(counters, b.droppedEvent(now))
+		b.Coalesced, b.Limited = 0, 0
+	}
+	keys := make([]string, 0, len(buckets))
+	for k := range buckets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if o := buckets[k]; o != b && now.Sub(o.Updated) > rateIdleExpiry {
+			if o.dropped() {
+				counters = append(counters, o.droppedEvent(now))
+			}
+			delete(buckets, k)
+		}
+	}
+
+	tb.dirty = true
+	if !allowed || len(counters) > 0 {
+		saveBuckets(townRoot, tb)
+	} else if tb.timer == nil {
+		tb.timer = time.AfterFunc(rateSaveInterval, func() { flushBuckets(townRoot) })
+	}
+	return allowed, counters
+}
+
+// loadBuckets returns the town's cached buckets, reading the state file
+// when this process hasn't yet or another process has written it since.
+// Must be called with rateLimits locked.
+func loadBuckets(townRoot string) *townBuckets {
+	path := filepath.Join(townRoot, RateLimitFile)
+	var modTime time.Time
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime()
+	}
+	tb := rateLimits.byTown[townRoot]
+	if tb != nil && tb.modTime.Equal(modTime) {
+		return tb
+	}
+	if tb != nil && tb.timer != nil {
+		tb.timer.Stop() // its unsaved tokens give way to the newer file
+	}
+	tb = &townBuckets{buckets: make(map[string]*rateBucket), modTime: modTime}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &tb.buckets)
+	}
+	rateLimits.byTown[townRoot] = tb
+	return tb
+}
+
+// saveBuckets writes the town's buckets to the state file if they changed.
+// Must be called with rateLimits locked and the events file lock held.
+func saveBuckets(townRoot string, tb *townBuckets) {
+	if tb.timer != nil {
+		tb.timer.Stop()
+		tb.timer = nil
+	}
+	if !tb.dirty {
+		return
+	}
+	tb.dirty = false
+	path := filepath.Join(townRoot, RateLimitFile)
+	data, err := json.Marshal(tb.buckets)
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: limiter state is non-sensitive
+		return
+	}
+	if info, err := os.Stat(path); err == nil {
+		tb.modTime = info.ModTime()
+	}
+}
+
+// flushBuckets writes the town's unsaved limiter state, taking the events
+// file lock.
+func flushBuckets(townRoot string) {
+	fl := flock.New(filepath.Join(townRoot, EventsFile) + ".lock")
+	if err := fl.Lock(); err != nil {
+		return
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	rateLimits.Lock()
+	defer rateLimits.Unlock()
+	tb := rateLimits.byTown[townRoot]
+	if tb == nil {
+		return
+	}
+	// Another process wrote the file since: its state wins, and the next
+	// event reads it.
+	if info, err := os.Stat(filepath.Join(townRoot, RateLimitFile)); err == nil && !info.ModTime().Equal(tb.modTime) {
+		delete(rateLimits.byTown, townRoot)
+		return
+	}
+	saveBuckets(townRoot, tb)
+}
+
+// FlushRateLimits writes limiter state still waiting on its save timer.
+// Short-lived emitters (gt top emit) call it before exiting so the next
+// process sees the tokens they spent.
+func FlushRateLimits() {
+	rateLimits.Lock()
+	towns := make([]string, 0, len(rateLimits.byTown))
+	for townRoot, tb := range rateLimits.byTown {
+		if tb.dirty {
+			towns = append(towns, townRoot)
+		}
+	}
+	rateLimits.Unlock()
+	for _, townRoot := range towns {
+		flushBuckets(townRoot)
+	}
+}
+
+// marshalLines renders events as JSONL, signing each line with key when
+// it is non-nil.
+func marshalLines(key []byte, evts ...Event) ([]byte, error) {
+	var out []byte
+	for _, e := range evts {
+		e.Signature = ""
+		data, err := json.Marshal(e)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling event: %w", err)
+		}
+		if key != nil {
+			data = sign(key, data)
+		}
+		out = append(append(out, data...), '\n')
+	}
+	return out, nil
+}
@@ -0,0 +1,189 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withRateClock pins the limiter's clock; advance moves it forward.
+func withRateClock(t *testing.T) (advance func(time.Duration)) {
+	t.Helper()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	rateNow = func() time.Time { return now }
+	t.Cleanup(func() { rateNow = time.Now })
+	t.Cleanup(FlushRateLimits) // before the temp town is removed
+	return func(d time.Duration) { now = now.Add(d) }
+}
+
+// withoutRateLimit turns the limiter off, for tests of other write paths
+// that emit tool events in bulk.
+func withoutRateLimit(t *testing.T) {
+	t.Helper()
+	saved := rateLimitedTypes
+	rateLimitedTypes = nil
+	t.Cleanup(func() { rateLimitedTypes = saved })
+}
+
+func toolEvent(typ, tool, session string) (string, map[string]interface{}) {
+	return typ, map[string]interface{}{"tool": tool, "session": session}
+}
+
+func countTypes(evts []Event) map[string]int {
+	n := make(map[string]int)
+	for _, e := range evts {
+		n[e.Type]++
+	}
+	return n
+}
+
+func TestRateLimit_CoalescesRepeatedToolStarted(t *testing.T) {
+	town := t.TempDir()
+	advance := withRateClock(t)
+
+	for i := 0; i < 3; i++ {
+		typ, p := toolEvent(TypeToolStarted, "Bash(make)", "gt-gastown-Toast")
+		if err := LogInTown(town, "gt", typ, "polecat", p, VisibilityFeed); err != nil {
+			t.Fatal(err)
+		}
+		advance(time.Second)
+	}
+	typ, p := toolEvent(TypeToolFinished, "Bash(make)", "gt-gastown-Toast")
+	if err := LogInTown(town, "gt", typ, "polecat", p, VisibilityFeed); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readEventsFile(t, town)
+	n := countTypes(got)
+	if n[TypeToolStarted] != 1 || n[TypeToolFinished] != 1 {
+		t.Fatalf("events = %v, want one tool_started and one tool_finished", n)
+	}
+	if n[TypeEventsDropped] != 1 {
+		t.Fatalf("events = %v, want one events_dropped", n)
+	}
+	var dropped Event
+	for _, e := range got {
+		if e.Type == TypeEventsDropped {
+			dropped = e
+		}
+	}
+	if dropped.Payload["coalesced"] != float64(2) || dropped.Payload["session"] != "gt-gastown-Toast" {
+		t.Errorf("events_dropped payload = %v, want 2 coalesced for gt-gastown-Toast", dropped.Payload)
+	}
+	if got[len(got)-1].Type != TypeToolFinished {
+		t.Errorf("counter event should precede the event that got through, got order %v", got)
+	}
+}
+
+func TestRateLimit_TokenBucket(t *testing.T) {
+	town := t.TempDir()
+	advance := withRateClock(t)
+
+	// Distinct tools so nothing coalesces: only the bucket limits.
+	for i := 0; i < rateBurst+10; i++ {
+		typ, p := toolEvent(TypeToolStarted, string(rune('a'+i)), "gt-gastown-Toast")
+		_ = LogInTown(town, "gt", typ, "polecat", p, VisibilityFeed)
+	}
+	if n := countTypes(readEventsFile(t, town)); n[TypeToolStarted] != rateBurst || n[TypeEventsDropped] != 0 {
+		t.Fatalf("after a burst: %v, want %d tool_started and no counter yet", n, rateBurst)
+	}
+
+	// Another emitter has its own bucket.
+	typ, p := toolEvent(TypeToolStarted, "Read", "gt-gastown-Nux")
+	_ = LogInTown(town, "gt", typ, "polecat", p, VisibilityFeed)
+
+	advance(time.Second) // refills 5 tokens
+	typ, p = toolEvent(TypeToolStarted, "Edit", "gt-gastown-Toast")
+	_ = LogInTown(town, "gt", typ, "polecat", p, VisibilityFeed)
+
+	got := readEventsFile(t, town)
+	n := countTypes(got)
+	if n[TypeToolStarted] != rateBurst+2 || n[TypeEventsDropped] != 1 {
+		t.Fatalf("after refill: %v, want %d tool_started and one counter", n, rateBurst+2)
+	}
+	for _, e := range got {
+		if e.Type == TypeEventsDropped && e.Payload["limited"] != float64(10) {
+			t.Errorf("events_dropped payload = %v, want 10 limited", e.Payload)
+		}
+	}
+}
+
+func TestRateLimit_LifecycleEventsNeverDropped(t *testing.T) {
+	town := t.TempDir()
+	withRateClock(t)
+
+	for i := 0; i < rateBurst+5; i++ {
+		_ = LogInTown(town, "gt", TypeDone, "gastown/polecats/Toast", DonePayload("gt-1", "polecat/Toast"), VisibilityFeed)
+	}
+	if n := countTypes(readEventsFile(t, town)); n[TypeDone] != rateBurst+5 {
+		t.Errorf("done events = %d, want all %d", n[TypeDone], rateBurst+5)
+	}
+}
+
+func TestRateLimit_IdleEmitterFlushesCounter(t *testing.T) {
+	town := t.TempDir()
+	advance := withRateClock(t)
+
+	for i := 0; i < 2; i++ {
+		typ, p := toolEvent(TypeToolStarted, "Bash", "gt-gastown-Toast")
+		_ = LogInTown(town, "gt", typ, "polecat", p, VisibilityFeed)
+	}
+	advance(rateIdleExpiry + time.Minute)
+	typ, p := toolEvent(TypeAgentIdle, "", "gt-gastown-Nux")
+	_ = LogInTown(town, "gt", typ, "polecat", p, VisibilityFeed)
+
+	if n := countTypes(readEventsFile(t, town)); n[TypeEventsDropped] != 1 {
+		t.Errorf("events = %v, want the idle emitter's counter flushed", n)
+	}
+}
+
+func TestRateLimit_ClosingEventsNeverDropped(t *testing.T) {
+	town := t.TempDir()
+	withRateClock(t)
+
+	for i := 0; i < rateBurst+5; i++ {
+		typ, p := toolEvent(TypeToolStarted, string(rune('a'+i)), "gt-gastown-Toast")
+		_ = LogInTown(town, "gt", typ, "polecat", p, VisibilityFeed)
+	}
+	typ, p := toolEvent(TypeToolFinished, "y", "gt-gastown-Toast")
+	_ = LogInTown(town, "gt", typ, "polecat", p, VisibilityFeed)
+	typ, p = toolEvent(TypeAgentIdle, "", "gt-gastown-Toast")
+	_ = LogInTown(town, "gt", typ, "polecat", p, VisibilityFeed)
+
+	n := countTypes(readEventsFile(t, town))
+	if n[TypeToolFinished] != 1 || n[TypeAgentIdle] != 1 {
+		t.Errorf("events = %v, want tool_finished and agent_idle through an empty bucket", n)
+	}
+	if n[TypeEventsDropped] != 1 {
+		t.Errorf("events = %v, want the dropped tool_started counted once", n)
+	}
+}
+
+func TestRateLimit_StateSavedOnFlush(t *testing.T) {
+	town := t.TempDir()
+	withRateClock(t)
+	statePath := filepath.Join(town, RateLimitFile)
+
+	typ, p := toolEvent(TypeToolStarted, "Bash", "gt-gastown-Toast")
+	_ = LogInTown(town, "gt", typ, "polecat", p, VisibilityFeed)
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Fatalf("state written on an admitted event (stat err %v), want it left to the save timer", err)
+	}
+
+	FlushRateLimits()
+	data, err := os.ReadFile(statePath)
+	if err != nil || !strings.Contains(string(data), `"last_tool":"Bash"`) {
+		t.Fatalf("state after flush: %v\n%s", err, data)
+	}
+
+	// A fresh process reads the flushed state: the repeat still coalesces.
+	rateLimits.Lock()
+	delete(rateLimits.byTown, town)
+	rateLimits.Unlock()
+	_ = LogInTown(town, "gt", typ, "polecat", p, VisibilityFeed)
+	if n := countTypes(readEventsFile(t, town)); n[TypeToolStarted] != 1 {
+		t.Errorf("events = %v, want the repeat coalesced across processes", n)
+	}
+}
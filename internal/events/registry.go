@@ -0,0 +1,83 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Registry is the event types a town knows: the built-in ones (KnownTypes)
+// and the custom types declared in its settings (event_types). A nil
+// Registry knows only the built-in types.
+type Registry struct {
+	custom map[string]config.EventTypeConfig
+}
+
+// LoadRegistry loads a town's event types. With no town or no custom types
+// it returns nil.
+func LoadRegistry(townRoot string) *Registry {
+	if townRoot == "" {
+		return nil
+	}
+	ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || len(ts.EventTypes) == 0 {
+		return nil
+	}
+	builtin := make(map[string]bool)
+	for _, t := range KnownTypes() {
+		builtin[t] = true
+	}
+	r := &Registry{custom: make(map[string]config.EventTypeConfig)}
+	for name, t := range ts.EventTypes {
+		if name == "" || builtin[name] || t == nil {
+			continue
+		}
+		r.custom[name] = *t
+	}
+	return r
+}
+
+// Custom returns the declaration of a custom event type.
+func (r *Registry) Custom(eventType string) (config.EventTypeConfig, bool) {
+	if r == nil {
+		return config.EventTypeConfig{}, false
+	}
+	t, ok := r.custom[eventType]
+	return t, ok
+}
+
+// Types returns every known event type: the built-in ones in declaration
+// order, then the custom ones sorted.
+func (r *Registry) Types() []string {
+	types := KnownTypes()
+	if r == nil {
+		return types
+	}
+	custom := make([]string, 0, len(r.custom))
+	for name := range r.custom {
+		custom = append(custom, name)
+	}
+	sort.Strings(custom)
+	return append(types, custom...)
+}
+
+// Validate checks an event's payload against its type's declaration: a
+// custom type's required fields must be present and non-empty. Other types
+// pass.
+func (r *Registry) Validate(eventType string, payload map[string]interface{}) error {
+	t, ok := r.Custom(eventType)
+	if !ok {
+		return nil
+	}
+	var missing []string
+	for _, field := range t.Required {
+		if v, ok := payload[field]; !ok || v == nil || v == "" {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s events require payload fields %v (missing %v)", eventType, t.Required, missing)
+	}
+	return nil
+}
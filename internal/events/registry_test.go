@@ -0,0 +1,56 @@
+package events
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestRegistry(t *testing.T) {
+	townRoot := t.TempDir()
+	ts := config.NewTownSettings()
+	ts.EventTypes = map[string]*config.EventTypeConfig{
+		"deploy":  {Required: []string{"env", "version"}, Icon: "🚢"},
+		TypeSling: {Icon: "x"}, // built-in: ignored
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), ts); err != nil {
+		t.Fatalf("saving town settings: %v", err)
+	}
+	r := LoadRegistry(townRoot)
+
+	if d, ok := r.Custom("deploy"); !ok || d.Icon != "🚢" {
+		t.Errorf("Custom(deploy) = %+v, %v", d, ok)
+	}
+	if _, ok := r.Custom(TypeSling); ok {
+		t.Error("a built-in type was redeclared")
+	}
+	if types := r.Types(); types[len(types)-1] != "deploy" || !slices.Contains(types, TypeSling) {
+		t.Errorf("Types() = %v, want the built-ins then deploy", types)
+	}
+
+	err := r.Validate("deploy", map[string]interface{}{"env": "prod"})
+	if err == nil || !strings.Contains(err.Error(), "missing [version]") {
+		t.Errorf("Validate without version = %v", err)
+	}
+	if err := r.Validate("deploy", map[string]interface{}{"env": "prod", "version": "1.4.2"}); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+	if err := r.Validate("whatever", nil); err != nil {
+		t.Errorf("undeclared type: %v", err)
+	}
+}
+
+func TestRegistryNoTown(t *testing.T) {
+	r := LoadRegistry("")
+	if r != nil {
+		t.Fatalf("LoadRegistry(\"\") = %+v, want nil", r)
+	}
+	if got := r.Types(); !slices.Equal(got, KnownTypes()) {
+		t.Errorf("nil Types() = %v", got)
+	}
+	if err := r.Validate("deploy", nil); err != nil {
+		t.Errorf("nil Validate: %v", err)
+	}
+}
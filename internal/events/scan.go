@@ -0,0 +1,98 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// MaxLineSize is the longest events file line NewScanner returns. An event
+// this big is an agent logging a whole file into a payload; it is skipped
+// rather than read into memory and parsed on every poll.
+const MaxLineSize = 1024 * 1024
+
+// NewScanner returns a line scanner for an events file (or any JSONL log).
+// Unlike a plain bufio.Scanner, which stops at the first line over its
+// buffer and loses everything after it, it skips lines longer than
+// MaxLineSize and carries on with the next one.
+func NewScanner(r io.Reader) *bufio.Scanner {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), MaxLineSize)
+	sc.Split(skipLongLines(MaxLineSize))
+	return sc
+}
+
+// skipLongLines is bufio.ScanLines, except that a line of max bytes or more
+// is dropped, up to and including its newline.
+func skipLongLines(max int) bufio.SplitFunc {
+	skipping := false
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if skipping {
+			if i := bytes.IndexByte(data, '\n'); i >= 0 {
+				skipping = false
+				return i + 1, nil, nil
+			}
+			return len(data), nil, nil
+		}
+		if len(data) >= max && bytes.IndexByte(data, '\n') < 0 {
+			skipping = true
+			return len(data), nil, nil
+		}
+		return bufio.ScanLines(data, atEOF)
+	}
+}
+
+// TailReader reads an events file's lines from an offset, for a tailer that
+// saves how far it got: each line comes with the offset just past it, so
+// the tailer can advance only once it's done with the line. A partly
+// written last line isn't returned until it's whole, and lines longer than
+// MaxLineSize are skipped, as NewScanner does. Memory stays bounded by the
+// longest line, however far behind the tailer is.
+type TailReader struct {
+	r      *bufio.Reader
+	offset int64
+	err    error
+}
+
+// NewTailReader returns a TailReader for r, which is positioned at offset.
+func NewTailReader(r io.Reader, offset int64) *TailReader {
+	return &TailReader{r: bufio.NewReaderSize(r, 64*1024), offset: offset}
+}
+
+// Next returns the next whole line, without its newline, and the offset
+// just past it. ok is false at the end of what's been written, or on a
+// read error (see Err); the reader is done then, and the next tail starts
+// again from the offset.
+func (t *TailReader) Next() (line []byte, offset int64, ok bool) {
+	var n int64
+	skipping := false
+	for {
+		chunk, err := t.r.ReadSlice('\n')
+		n += int64(len(chunk))
+		if !skipping && len(line)+len(chunk) > MaxLineSize {
+			skipping, line = true, nil
+		}
+		if !skipping {
+			line = append(line, chunk...)
+		}
+		switch err {
+		case nil:
+			t.offset += n
+			if skipping {
+				n, skipping = 0, false
+				continue
+			}
+			return line[:len(line)-1], t.offset, true
+		case bufio.ErrBufferFull:
+			continue
+		case io.EOF:
+			return nil, t.offset, false
+		default:
+			t.err = err
+			return nil, t.offset, false
+		}
+	}
+}
+
+// Err returns the read error that stopped Next, if any.
+func (t *TailReader) Err() error { return t.err }
@@ -0,0 +1,67 @@
+package events
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestNewScannerSkipsLongLines(t *testing.T) {
+	long := strings.Repeat("x", 3*MaxLineSize)
+	input := `{"type":"sling"}` + "\n" + long + "\n" + `{"type":"done"}` + "\n" + long + "\n" + `{"type":"mail"}`
+
+	sc := NewScanner(strings.NewReader(input))
+	var got []string
+	for sc.Scan() {
+		got = append(got, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	want := []string{`{"type":"sling"}`, `{"type":"done"}`, `{"type":"mail"}`}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+}
+
+func TestTailReader(t *testing.T) {
+	long := strings.Repeat("x", MaxLineSize+10)
+	input := `{"type":"sling"}` + "\n" + long + "\n" + `{"type":"done"}` + "\n" + `{"type":"ma`
+	const start = 100
+
+	tr := NewTailReader(strings.NewReader(input), start)
+	var got []string
+	var offsets []int64
+	for {
+		line, offset, ok := tr.Next()
+		if !ok {
+			break
+		}
+		got = append(got, string(line))
+		offsets = append(offsets, offset)
+	}
+	if tr.Err() != nil {
+		t.Fatalf("Err = %v", tr.Err())
+	}
+	if strings.Join(got, "|") != `{"type":"sling"}|{"type":"done"}` {
+		t.Errorf("lines = %q, want the whole ones, the long one skipped", got)
+	}
+	// The partial last line isn't consumed: the next tail starts at it.
+	end := int64(start + strings.LastIndex(input, "\n") + 1)
+	if len(offsets) != 2 || offsets[0] != start+17 || offsets[1] != end {
+		t.Errorf("offsets = %v, want [%d %d]", offsets, start+17, end)
+	}
+}
+
+func TestTailReader_ReadError(t *testing.T) {
+	boom := errors.New("boom")
+	tr := NewTailReader(io.MultiReader(strings.NewReader("{}\n"), iotest.ErrReader(boom)), 0)
+	if _, _, ok := tr.Next(); !ok {
+		t.Fatal("first line not read")
+	}
+	if _, offset, ok := tr.Next(); ok || offset != 3 || !errors.Is(tr.Err(), boom) {
+		t.Errorf("after error: ok %v, offset %d, Err %v", ok, offset, tr.Err())
+	}
+}
@@ -0,0 +1,157 @@
+package events
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// sigField opens the signature field. Writers splice it in as the line's last
+// field, so the signed bytes are the line with the field cut out again.
+const sigField = `,"sig":"`
+
+// SigStatus is the outcome of checking an event line's signature.
+type SigStatus int
+
+const (
+	SigUnsigned SigStatus = iota // no signature
+	SigValid                     // signed with the town key
+	SigInvalid                   // signed, but not with the town key, or altered since
+)
+
+// signingKeys caches each town's signing key (nil when it doesn't sign), so
+// writers load the town settings once per process rather than per event.
+var signingKeys = struct {
+	sync.Mutex
+	byTown map[string][]byte
+}{byTown: make(map[string][]byte)}
+
+// signingKey returns the town's event signing key, or nil when signing is
+// off (town settings event_signing). Errors aren't cached: the next write
+// tries the settings again.
+func signingKey(townRoot string) ([]byte, error) {
+	signingKeys.Lock()
+	defer signingKeys.Unlock()
+	if key, ok := signingKeys.byTown[townRoot]; ok {
+		return key, nil
+	}
+	cfg, err := signingConfig(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var key []byte
+	if cfg != nil {
+		key = []byte(cfg.Key)
+	}
+	signingKeys.byTown[townRoot] = key
+	return key, nil
+}
+
+// signingConfig returns the town's signing settings, nil when the town
+// doesn't sign events. Only a missing settings file means that: one that
+// can't be read or parsed is an error, since the town may well sign.
+func signingConfig(townRoot string) (*config.EventSigningConfig, error) {
+	if townRoot == "" {
+		return nil, nil
+	}
+	ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading event signing settings: %w", err)
+	}
+	if ts.EventSigning == nil || ts.EventSigning.Key == "" {
+		return nil, nil
+	}
+	return ts.EventSigning, nil
+}
+
+// sign appends the signature field to a marshaled event (a JSON object,
+// without the trailing newline).
+func sign(key, line []byte) []byte {
+	sig := hex.EncodeToString(mac(key, line))
+	out := make([]byte, 0, len(line)+len(sigField)+len(sig)+2)
+	out = append(out, line[:len(line)-1]...)
+	out = append(out, sigField...)
+	out = append(out, sig...)
+	return append(out, '"', '}')
+}
+
+func mac(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// splitSig separates a signed line into the bytes that were signed and the
+// signature. ok is false for unsigned lines.
+func splitSig(line []byte) (signed, sig []byte, ok bool) {
+	line = bytes.TrimRight(line, "\r\n")
+	i := bytes.LastIndex(line, []byte(sigField))
+	if i < 0 || !bytes.HasSuffix(line, []byte(`"}`)) {
+		return nil, nil, false
+	}
+	sig, err := hex.DecodeString(string(line[i+len(sigField) : len(line)-2]))
+	if err != nil || len(sig) != sha256.Size {
+		return nil, nil, false
+	}
+	signed = append(append(make([]byte, 0, i+1), line[:i]...), '}')
+	return signed, sig, true
+}
+
+// Verifier checks event signatures in readers. Every reader of the events
+// log passes its lines through one. A nil Verifier, returned when the town
+// doesn't sign events, accepts every line.
+type Verifier struct {
+	key           []byte
+	allowUnsigned bool
+	broken        bool // settings unreadable: trust nothing
+}
+
+// NewVerifier returns a verifier for the town's events, or nil when the
+// town doesn't sign them. When the town settings can't be loaded the
+// verifier rejects every line, rather than failing open.
+func NewVerifier(townRoot string) *Verifier {
+	cfg, err := signingConfig(townRoot)
+	if err != nil {
+		return &Verifier{broken: true}
+	}
+	if cfg == nil {
+		return nil
+	}
+	return &Verifier{key: []byte(cfg.Key), allowUnsigned: cfg.AllowUnsigned}
+}
+
+// Check reports the signature status of one line of the events file.
+func (v *Verifier) Check(line []byte) SigStatus {
+	signed, sig, ok := splitSig(line)
+	if !ok {
+		return SigUnsigned
+	}
+	if v == nil || v.broken || !hmac.Equal(sig, mac(v.key, signed)) {
+		return SigInvalid
+	}
+	return SigValid
+}
+
+// Valid reports whether a reader should trust the line: always when the
+// town doesn't sign events; otherwise when it carries a good signature, or
+// none if the town allows unsigned events.
+func (v *Verifier) Valid(line []byte) bool {
+	if v == nil {
+		return true
+	}
+	if v.broken {
+		return false
+	}
+	switch v.Check(line) {
+	case SigValid:
+		return true
+	case SigUnsigned:
+		return v.allowUnsigned
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package events
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// signedTown returns a town root whose settings sign events with a test key.
+func signedTown(t *testing.T, allowUnsigned bool) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	ts := config.NewTownSettings()
+	ts.EventSigning = &config.EventSigningConfig{Key: "test-key", AllowUnsigned: allowUnsigned}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), ts); err != nil {
+		t.Fatalf("saving town settings: %v", err)
+	}
+	return townRoot
+}
+
+func readLines(t *testing.T, townRoot string) [][]byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(townRoot, EventsFile))
+	if err != nil {
+		t.Fatalf("reading events file: %v", err)
+	}
+	return bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n"))
+}
+
+func TestSignedEventsVerify(t *testing.T) {
+	townRoot := signedTown(t, false)
+	payload := map[string]interface{}{"bead": "gt-1"}
+	if err := LogInTown(townRoot, "gastown/witness", "escalation_acked", "gastown/witness", payload, VisibilityFeed); err != nil {
+		t.Fatalf("LogInTown: %v", err)
+	}
+
+	line := readLines(t, townRoot)[0]
+	if !bytes.Contains(line, []byte(sigField)) {
+		t.Fatalf("event not signed: %s", line)
+	}
+	v := NewVerifier(townRoot)
+	if got := v.Check(line); got != SigValid {
+		t.Errorf("Check = %v, want SigValid", got)
+	}
+
+	tampered := bytes.Replace(line, []byte("gt-1"), []byte("gt-2"), 1)
+	if got := v.Check(tampered); got != SigInvalid || v.Valid(tampered) {
+		t.Errorf("tampered line: Check = %v, Valid = %v, want SigInvalid and untrusted", got, v.Valid(tampered))
+	}
+
+	other := &Verifier{key: []byte("other-key")}
+	if other.Valid(line) {
+		t.Error("line signed with the town key passed a different key")
+	}
+}
+
+func TestVerifierUnsigned(t *testing.T) {
+	unsigned := []byte(`{"ts":"2026-03-01T12:00:00Z","source":"gt","type":"escalation_acked","actor":"mayor"}`)
+
+	var off *Verifier
+	if !off.Valid(unsigned) {
+		t.Error("nil verifier rejected an unsigned line")
+	}
+	if NewVerifier(t.TempDir()) != nil {
+		t.Error("town without event_signing got a verifier")
+	}
+
+	// Once the town has a key, an unsigned line is as good as forged...
+	if v := NewVerifier(signedTown(t, false)); v.Check(unsigned) != SigUnsigned || v.Valid(unsigned) {
+		t.Error("unsigned line accepted in a town that signs events")
+	}
+	// ...unless the town allows them while older events age out.
+	if v := NewVerifier(signedTown(t, true)); !v.Valid(unsigned) {
+		t.Error("unsigned line rejected with allow_unsigned")
+	}
+}
+
+func TestDamagedSettingsFailClosed(t *testing.T) {
+	townRoot := t.TempDir()
+	path := config.TownSettingsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"event_signing": {"key": "te`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	unsigned := []byte(`{"ts":"2026-03-01T12:00:00Z","source":"gt","type":"escalation_acked","actor":"mayor"}`)
+	if v := NewVerifier(townRoot); v == nil || v.Valid(unsigned) {
+		t.Error("unsigned line accepted while the town settings can't be read")
+	}
+	if err := LogInTown(townRoot, "mayor", "escalation_acked", "mayor", nil, VisibilityFeed); err == nil {
+		t.Error("LogInTown wrote an event without knowing whether to sign it")
+	}
+}
+
+func TestSigningKeyLoadedOnce(t *testing.T) {
+	townRoot := signedTown(t, false)
+	if err := LogInTown(townRoot, "mayor", "escalation_acked", "mayor", nil, VisibilityFeed); err != nil {
+		t.Fatalf("LogInTown: %v", err)
+	}
+	// Later appends don't re-read the settings.
+	if err := os.WriteFile(config.TownSettingsPath(townRoot), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LogInTown(townRoot, "mayor", "escalation_acked", "mayor", nil, VisibilityFeed); err != nil {
+		t.Fatalf("LogInTown after the first: %v", err)
+	}
+	v := &Verifier{key: []byte("test-key")}
+	for i, line := range readLines(t, townRoot) {
+		if v.Check(line) != SigValid {
+			t.Errorf("line %d not signed with the town key: %s", i, line)
+		}
+	}
+}
@@ -37,6 +37,10 @@ type FeedEvent struct {
 	Summary   string                 `json:"summary"`
 	Payload   map[string]interface{} `json:"payload,omitempty"`
 	Count     int                    `json:"count,omitempty"` // For aggregated events
+
+	// IdempotencyKey carries the raw event's key so retries are dropped
+	// against what's already in the feed.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
 
 // Curator manages the feed curation process.
@@ -50,6 +54,16 @@ type Curator struct {
 	startOnce sync.Once // prevents concurrent Start() calls from spawning multiple goroutines
 	startErr  error     // result of the one-shot Start; visible to all callers via sync.Once happens-before
 
+	// verify drops forged or altered events when the town signs them; nil
+	// when it doesn't.
+	verify *events.Verifier
+
+	// feedKeys holds the idempotency keys already in the feed, seeded once
+	// from the feed's recent events and kept up as events are written, so a
+	// keyed event doesn't re-read the feed. Only the run goroutine uses it.
+	feedKeys       events.Dedup
+	feedKeysSeeded bool
+
 	// feedMu guards in-process access to the feed file. The flock in
 	// readRecentFeedEvents/writeFeedEvent coordinates across processes;
 	// this mutex coordinates goroutines within the same process.
@@ -89,6 +103,7 @@ func NewCurator(townRoot string) *Curator {
 		doneDedupeWindow:     config.ParseDurationOrDefault(cfg.DoneDedupeWindow, 10*time.Second),
 		slingAggregateWindow: config.ParseDurationOrDefault(cfg.SlingAggregateWindow, 30*time.Second),
 		minAggregateCount:    minAgg,
+		verify:               events.NewVerifier(townRoot),
 	}
 }
 
@@ -162,6 +177,10 @@ func (c *Curator) processLine(line string) {
 	if err := json.Unmarshal([]byte(line), &rawEvent); err != nil {
 		return // Skip malformed lines
 	}
+	if !c.verify.Valid([]byte(line)) {
+		log.Printf("warning: dropping %s event from %q: missing or bad signature", rawEvent.Type, rawEvent.Actor)
+		return
+	}
 
 	// Filter by visibility - only process feed-visible events
 	if rawEvent.Visibility != events.VisibilityFeed && rawEvent.Visibility != events.VisibilityBoth {
@@ -178,9 +197,27 @@ func (c *Curator) processLine(line string) {
 }
 
 // shouldDedupe checks if an event should be deduplicated.
-// ZFC: Derives state from the FEED file (what we've already output), not in-memory cache.
+// ZFC: Derives state from the FEED file (what we've already output). Only
+// idempotency keys are kept in memory, seeded from the feed on first use.
 // Returns true if the event should be dropped.
 func (c *Curator) shouldDedupe(event *events.Event) bool {
+	if event.IdempotencyKey != "" {
+		if !c.feedKeysSeeded {
+			recentFeedEvents, err := c.readRecentFeedEvents(idempotencyWindow)
+			if err != nil {
+				log.Printf("warning: reading recent feed events for dedup: %v", err)
+				return false // Fail-open; seed again on the next keyed event
+			}
+			for _, e := range recentFeedEvents {
+				c.feedKeys.Seen(e.IdempotencyKey)
+			}
+			c.feedKeysSeeded = true
+		}
+		if c.feedKeys.Seen(event.IdempotencyKey) {
+			return true // Retry of an event already in the feed
+		}
+	}
+
 	switch event.Type {
 	case events.TypeDone:
 		// Dedupe repeated done events from same actor within window
@@ -202,6 +239,10 @@ func (c *Curator) shouldDedupe(event *events.Event) bool {
 	return false
 }
 
+// idempotencyWindow is how far back the feed is searched for an event with the
+// same idempotency key. Emitter retries land within seconds.
+const idempotencyWindow = 10 * time.Minute
+
 // maxFeedFileSize is the maximum .feed.jsonl size before truncation.
 // When exceeded, the file is truncated to keep the newest half.
 const maxFeedFileSize int64 = 10 * 1024 * 1024 // 10MB
@@ -253,7 +294,7 @@ func (c *Curator) readRecentFeedEvents(window time.Duration) ([]FeedEvent, error
 		return nil, fmt.Errorf("seeking feed file: %w", err)
 	}
 
-	scanner := bufio.NewScanner(f)
+	scanner := events.NewScanner(f)
 	if seekTo > 0 {
 		scanner.Scan() // skip potential partial first line at cut point
 	}
@@ -309,16 +350,17 @@ func (c *Curator) readRecentEvents(window time.Duration) ([]events.Event, error)
 		return nil, fmt.Errorf("seeking events file: %w", err)
 	}
 
-	scanner := bufio.NewScanner(f)
+	scanner := events.NewScanner(f)
 	if seekTo > 0 {
 		scanner.Scan() // skip potential partial first line at cut point
 	}
 
 	cutoff := time.Now().Add(-window)
 	var result []events.Event
+	var dedup events.Dedup
 	for scanner.Scan() {
 		var event events.Event
-		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || !c.verify.Valid(scanner.Bytes()) || dedup.Seen(event.IdempotencyKey) {
 			continue
 		}
 		ts, err := time.Parse(time.RFC3339, event.Timestamp)
@@ -361,6 +403,8 @@ func (c *Curator) writeFeedEvent(event *events.Event) {
 		Actor:     event.Actor,
 		Summary:   c.generateSummary(event),
 		Payload:   event.Payload,
+
+		IdempotencyKey: event.IdempotencyKey,
 	}
 
 	// Check for aggregation opportunity (ZFC: derive from events file)
@@ -481,6 +525,18 @@ func (c *Curator) generateSummary(event *events.Event) string {
 		}
 		return fmt.Sprintf("%s signaled done", event.Actor)
 
+	case events.TypeBeadCreated, events.TypeBeadInProgress, events.TypeBeadClosed:
+		bead, _ := event.Payload["bead"].(string)
+		if title, ok := event.Payload["title"].(string); ok && title != "" {
+			bead += " (" + title + ")"
+		}
+		verb := map[string]string{
+			events.TypeBeadCreated:    "opened",
+			events.TypeBeadInProgress: "started",
+			events.TypeBeadClosed:     "closed",
+		}[event.Type]
+		return fmt.Sprintf("%s %s %s", event.Actor, verb, bead)
+
 	case events.TypeHandoff:
 		return fmt.Sprintf("%s handed off to fresh session", event.Actor)
 
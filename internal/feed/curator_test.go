@@ -157,6 +157,46 @@ func TestCurator_DedupesDoneEvents(t *testing.T) {
 	}
 }
 
+func TestCurator_DedupesIdempotencyKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+	eventsPath := filepath.Join(tmpDir, events.EventsFile)
+	feedPath := filepath.Join(tmpDir, FeedFile)
+	if err := os.WriteFile(eventsPath, []byte{}, 0644); err != nil {
+		t.Fatalf("creating events file: %v", err)
+	}
+
+	curator := NewCurator(tmpDir)
+	if err := curator.Start(); err != nil {
+		t.Fatalf("starting curator: %v", err)
+	}
+	defer curator.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+
+	// A retried tool event (same key) and a distinct one
+	f, _ := os.OpenFile(eventsPath, os.O_APPEND|os.O_WRONLY, 0644)
+	for _, key := range []string{"started:call-1", "started:call-1", "started:call-2"} {
+		data, _ := json.Marshal(events.Event{
+			Timestamp:      time.Now().UTC().Format(time.RFC3339),
+			Source:         "gt",
+			Type:           events.TypeToolStarted,
+			Actor:          "gastown/polecats/Toast",
+			Payload:        map[string]interface{}{"tool": "Bash"},
+			Visibility:     events.VisibilityFeed,
+			IdempotencyKey: key,
+		})
+		f.Write(append(data, '\n'))
+	}
+	f.Close()
+
+	time.Sleep(300 * time.Millisecond)
+
+	feedContent, _ := os.ReadFile(feedPath)
+	if lines := strings.Count(string(feedContent), "\n"); lines != 2 {
+		t.Errorf("expected 2 feed events after idempotency dedup, got %d:\n%s", lines, feedContent)
+	}
+}
+
 // --- Config loading tests ---
 
 func TestCurator_DefaultConfig_NoSettingsFile(t *testing.T) {
@@ -698,20 +738,17 @@ func TestCurator_StartErrorPersistsAcrossCalls(t *testing.T) {
 	}
 }
 
-// TestCurator_ReadRecentFeedEvents_ScannerError verifies that IO errors
-// during scanning are returned, not silently swallowed.
-// Regression test for gt-0e4.
-func TestCurator_ReadRecentFeedEvents_ScannerError(t *testing.T) {
+// TestCurator_ReadRecentFeedEvents_LongLine verifies that a line longer
+// than bufio.Scanner's default token size (64KB), as from an agent that
+// cat'd a large file, is read past rather than ending the scan early.
+func TestCurator_ReadRecentFeedEvents_LongLine(t *testing.T) {
 	tmpDir := t.TempDir()
 	feedPath := filepath.Join(tmpDir, FeedFile)
 
-	// Write a valid event followed by a line exceeding bufio.Scanner's
-	// default max token size (64KB). This triggers a scanner error.
 	f, err := os.OpenFile(feedPath, os.O_CREATE|os.O_WRONLY, 0600)
 	if err != nil {
 		t.Fatal(err)
 	}
-	// Write one valid event
 	ev := FeedEvent{
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 		Source:    "gt",
@@ -721,40 +758,26 @@ func TestCurator_ReadRecentFeedEvents_ScannerError(t *testing.T) {
 	}
 	data, _ := json.Marshal(ev)
 	f.Write(append(data, '\n'))
-
-	// Write a line that exceeds the default scanner buffer (64KB)
-	longLine := make([]byte, 70*1024)
-	for i := range longLine {
-		longLine[i] = 'x'
-	}
-	f.Write(longLine)
-	f.Write([]byte{'\n'})
+	f.Write([]byte(strings.Repeat("x", 70*1024) + "\n"))
+	f.Write(append(data, '\n'))
 	f.Close()
 
 	curator := NewCurator(tmpDir)
 	result, err := curator.readRecentFeedEvents(1 * time.Hour)
-
-	// Should return an error from scanner.Err()
-	if err == nil {
-		t.Fatal("readRecentFeedEvents should return scanner error for oversized line")
-	}
-	if !strings.Contains(err.Error(), "scanning feed file") {
-		t.Errorf("error should mention scanning, got: %v", err)
+	if err != nil {
+		t.Fatalf("readRecentFeedEvents: %v", err)
 	}
-	// Should still return the partial results read before the error
-	if len(result) != 1 {
-		t.Errorf("expected 1 partial result before scanner error, got %d", len(result))
+	if len(result) != 2 {
+		t.Errorf("expected the events on both sides of the long line, got %d", len(result))
 	}
 }
 
-// TestCurator_ReadRecentEvents_ScannerError verifies that IO errors
-// during events file scanning are returned, not silently swallowed.
-// Regression test for gt-0e4.
-func TestCurator_ReadRecentEvents_ScannerError(t *testing.T) {
+// TestCurator_ReadRecentEvents_LongLine verifies that the events file reader
+// reads past a line longer than bufio.Scanner's default token size.
+func TestCurator_ReadRecentEvents_LongLine(t *testing.T) {
 	tmpDir := t.TempDir()
 	eventsPath := filepath.Join(tmpDir, events.EventsFile)
 
-	// Write a valid event followed by an oversized line
 	f, err := os.OpenFile(eventsPath, os.O_CREATE|os.O_WRONLY, 0600)
 	if err != nil {
 		t.Fatal(err)
@@ -768,27 +791,16 @@ func TestCurator_ReadRecentEvents_ScannerError(t *testing.T) {
 	}
 	data, _ := json.Marshal(ev)
 	f.Write(append(data, '\n'))
-
-	// Oversized line triggers scanner.Err()
-	longLine := make([]byte, 70*1024)
-	for i := range longLine {
-		longLine[i] = 'y'
-	}
-	f.Write(longLine)
-	f.Write([]byte{'\n'})
+	f.Write([]byte(strings.Repeat("y", 70*1024) + "\n"))
+	f.Write(append(data, '\n'))
 	f.Close()
 
 	curator := NewCurator(tmpDir)
 	result, err := curator.readRecentEvents(1 * time.Hour)
-
-	if err == nil {
-		t.Fatal("readRecentEvents should return scanner error for oversized line")
-	}
-	if !strings.Contains(err.Error(), "scanning events file") {
-		t.Errorf("error should mention scanning, got: %v", err)
+	if err != nil {
+		t.Fatalf("readRecentEvents: %v", err)
 	}
-	// Partial results returned
-	if len(result) != 1 {
-		t.Errorf("expected 1 partial result before scanner error, got %d", len(result))
+	if len(result) != 2 {
+		t.Errorf("expected the events on both sides of the long line, got %d", len(result))
 	}
 }
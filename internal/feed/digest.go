@@ -0,0 +1,428 @@
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Digest output formats.
+const (
+	DigestFormatMarkdown = "md"
+	DigestFormatSlack    = "slack"
+	DigestFormatHTML     = "html"
+)
+
+// townKey groups events that don't belong to a rig (mayor, deacon, dogs,
+// the daemon).
+const townKey = "town"
+
+// digestMaxItems caps how many bead IDs/names are listed per line before
+// collapsing the rest into "+N more".
+const digestMaxItems = 5
+
+// RigDigest summarizes one rig's activity over the digest window.
+type RigDigest struct {
+	Rig         string
+	Dispatched  []string // beads slung to workers
+	Completed   []string // beads workers reported done
+	Merged      []string // workers/branches landed by the refinery
+	MergeFailed []string // "worker: reason" for failed merges
+	Escalations []string // "agent: reason" for escalations sent
+	Limits      []string // agents that hit rate limits or usage caps
+	Opened      []string // beads created
+	Started     []string // beads moved to in_progress
+	Closed      []string // beads closed
+	Health      []string // SLA breaches and SLO burns (AddHealth)
+}
+
+func (r *RigDigest) empty() bool {
+	return len(r.Dispatched) == 0 && len(r.Completed) == 0 &&
+		len(r.Merged) == 0 && len(r.MergeFailed) == 0 &&
+		len(r.Escalations) == 0 && len(r.Limits) == 0 &&
+		len(r.Opened) == 0 && len(r.Started) == 0 && len(r.Closed) == 0 &&
+		len(r.Health) == 0
+}
+
+// Digest is a per-rig narrative summary of the event feed, suitable for
+// posting as a morning summary.
+type Digest struct {
+	Since time.Time
+	Until time.Time
+	Rigs  []*RigDigest // town-level first, then rigs alphabetically
+}
+
+// ReadDigestEvents reads all events at or after since from the town's raw
+// events log. Unlike the curator's bounded tail read, this scans the whole
+// file: a digest window (typically 8-24h) can reach well past the tail.
+// A missing events file yields no events.
+func ReadDigestEvents(townRoot string, since time.Time) ([]events.Event, error) {
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening events file: %w", err)
+	}
+	defer f.Close()
+
+	var result []events.Event
+	var dedup events.Dedup
+	verify := events.NewVerifier(townRoot)
+	scanner := events.NewScanner(f)
+	for scanner.Scan() {
+		var event events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || !verify.Valid(scanner.Bytes()) {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, event.Timestamp)
+		if err != nil || ts.Before(since) || dedup.Seen(event.IdempotencyKey) {
+			continue
+		}
+		result = append(result, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("scanning events file: %w", err)
+	}
+	return result, nil
+}
+
+// BuildDigest groups events by rig and category. Event types that aren't
+// part of the digest (tool activity, observations, mail, ...) are ignored.
+func BuildDigest(evts []events.Event, since, until time.Time) *Digest {
+	byRig := make(map[string]*RigDigest)
+	rigFor := func(e *events.Event) *RigDigest {
+		name := eventRig(e)
+		rd, ok := byRig[name]
+		if !ok {
+			rd = &RigDigest{Rig: name}
+			byRig[name] = rd
+		}
+		return rd
+	}
+
+	for i := range evts {
+		e := &evts[i]
+		switch e.Type {
+		case events.TypeSling, events.TypeSchedulerDispatch:
+			if bead := payloadStr(e, "bead"); bead != "" {
+				rd := rigFor(e)
+				rd.Dispatched = appendUnique(rd.Dispatched, bead)
+			}
+		case events.TypeDone:
+			if bead := payloadStr(e, "bead"); bead != "" {
+				rd := rigFor(e)
+				rd.Completed = appendUnique(rd.Completed, bead)
+			}
+		case events.TypeBeadCreated, events.TypeBeadInProgress, events.TypeBeadClosed:
+			bead := payloadStr(e, "bead")
+			if bead == "" {
+				continue
+			}
+			rd := rigFor(e)
+			switch e.Type {
+			case events.TypeBeadCreated:
+				rd.Opened = appendUnique(rd.Opened, bead)
+			case events.TypeBeadInProgress:
+				rd.Started = appendUnique(rd.Started, bead)
+			default:
+				rd.Closed = appendUnique(rd.Closed, bead)
+			}
+		case events.TypeMerged:
+			rd := rigFor(e)
+			rd.Merged = append(rd.Merged, mergeSubject(e))
+		case events.TypeMergeFailed:
+			rd := rigFor(e)
+			item := mergeSubject(e)
+			if reason := payloadStr(e, "reason"); reason != "" {
+				item += ": " + reason
+			}
+			rd.MergeFailed = append(rd.MergeFailed, item)
+		case events.TypeEscalationSent:
+			rd := rigFor(e)
+			who := shortAgent(firstNonEmpty(payloadStr(e, "target"), e.Actor))
+			item := who
+			if reason := payloadStr(e, "reason"); reason != "" {
+				item += ": " + reason
+			}
+			rd.Escalations = append(rd.Escalations, item)
+		case events.TypeLimitHit:
+			if e.Actor != events.AgentWatchActor {
+				break // one per limit comes from the daemon; others are copies
+			}
+			rd := rigFor(e)
+			who := shortAgent(firstNonEmpty(payloadStr(e, "agent_id"), payloadStr(e, "session")))
+			item := who + " (" + strings.ReplaceAll(payloadStr(e, "kind"), "_", " ")
+			if reset := payloadStr(e, "reset"); reset != "" {
+				item += ", " + reset
+			}
+			rd.Limits = append(rd.Limits, item+")")
+		}
+	}
+
+	d := &Digest{Since: since, Until: until}
+	for _, rd := range byRig {
+		if !rd.empty() {
+			d.Rigs = append(d.Rigs, rd)
+		}
+	}
+	d.sortRigs()
+	return d
+}
+
+// FilterRig drops every section but rig's.
+func (d *Digest) FilterRig(rig string) {
+	var rigs []*RigDigest
+	for _, rd := range d.Rigs {
+		if rd.Rig == rig {
+			rigs = append(rigs, rd)
+		}
+	}
+	d.Rigs = rigs
+}
+
+// sortRigs orders the sections: town-level first, then rigs alphabetically.
+func (d *Digest) sortRigs() {
+	sort.Slice(d.Rigs, func(i, j int) bool {
+		a, b := d.Rigs[i].Rig, d.Rigs[j].Rig
+		if (a == townKey) != (b == townKey) {
+			return a == townKey
+		}
+		return a < b
+	})
+}
+
+// slaLabels and sloLabels name the SLA and SLO checks in digest prose.
+var (
+	slaLabels = map[string]string{SLAMergeQueueDrain: "merge queue drain", SLAEscalationAck: "escalation ack"}
+	sloLabels = map[string]string{SLOMergeFailures: "merge failure rate", SLOEscalations: "escalation rate"}
+)
+
+// AddHealth notes each rig's breached SLAs and burning SLOs, adding sections
+// for rigs that were otherwise quiet. Either map may be nil.
+func (d *Digest) AddHealth(slas map[string]*RigSLA, slos map[string]*RigSLO) {
+	byRig := make(map[string]*RigDigest, len(d.Rigs))
+	for _, rd := range d.Rigs {
+		byRig[rd.Rig] = rd
+	}
+	add := func(rig, line string) {
+		rd, ok := byRig[rig]
+		if !ok {
+			rd = &RigDigest{Rig: rig}
+			byRig[rig] = rd
+			d.Rigs = append(d.Rigs, rd)
+		}
+		rd.Health = append(rd.Health, line)
+	}
+
+	for _, rig := range sortedKeys(slas) {
+		for _, c := range slas[rig].Checks {
+			if c.Breaches > 0 {
+				add(rig, fmt.Sprintf("%s over %s %d time(s)", slaLabels[c.Name], formatDigestWindow(c.Target), c.Breaches))
+			}
+		}
+	}
+	for _, rig := range sortedKeys(slos) {
+		for _, c := range slos[rig].Checks {
+			if c.Burning() {
+				add(rig, fmt.Sprintf("%s %.0f%%, target %.0f%% (%d of %d)", sloLabels[c.Name], 100*c.Rate(), 100*c.Target, c.Failures, c.Total))
+			}
+		}
+	}
+	d.sortRigs()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// digestQuiet is the digest body for a window with nothing to report.
+const digestQuiet = "Quiet period: no work dispatched, no merges, no escalations, no limits hit."
+
+// Title returns the digest's heading.
+func (d *Digest) Title() string {
+	return "Gas Town digest: last " + formatDigestWindow(d.Until.Sub(d.Since))
+}
+
+// span renders the digest window in local time.
+func (d *Digest) span() string {
+	return d.Since.Local().Format("Jan 2 15:04") + " → " + d.Until.Local().Format("Jan 2 15:04")
+}
+
+// Render formats the digest as Markdown ("md"), Slack mrkdwn ("slack"), or
+// an HTML document ("html", for mail).
+func (d *Digest) Render(format string) (string, error) {
+	var heading, section, bullet, emph string
+	switch format {
+	case DigestFormatMarkdown, "":
+		heading, section, bullet, emph = "# %s", "## %s", "- ", "_%s_"
+	case DigestFormatSlack:
+		heading, section, bullet, emph = "*%s*", "*%s*", "• ", "_%s_"
+	case DigestFormatHTML:
+		return d.renderHTML(), nil
+	default:
+		return "", fmt.Errorf("unknown digest format %q (want %s, %s or %s)", format, DigestFormatMarkdown, DigestFormatSlack, DigestFormatHTML)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, heading+"\n", d.Title())
+	fmt.Fprintf(&b, emph+"\n", d.span())
+
+	if len(d.Rigs) == 0 {
+		b.WriteString("\n" + digestQuiet + "\n")
+		return b.String(), nil
+	}
+
+	for _, rd := range d.Rigs {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, section+"\n", rd.Rig)
+		for _, line := range rd.lines() {
+			b.WriteString(bullet + line + "\n")
+		}
+	}
+	return b.String(), nil
+}
+
+// renderHTML renders the digest as a minimal HTML document.
+func (d *Digest) renderHTML() string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>" + html.EscapeString(d.Title()) + "</title></head><body>\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<p><em>%s</em></p>\n", html.EscapeString(d.Title()), html.EscapeString(d.span()))
+	if len(d.Rigs) == 0 {
+		b.WriteString("<p>" + html.EscapeString(digestQuiet) + "</p>\n")
+	}
+	for _, rd := range d.Rigs {
+		fmt.Fprintf(&b, "<h2>%s</h2>\n<ul>\n", html.EscapeString(rd.Rig))
+		for _, line := range rd.lines() {
+			b.WriteString("<li>" + html.EscapeString(line) + "</li>\n")
+		}
+		b.WriteString("</ul>\n")
+	}
+	b.WriteString("</body></html>\n")
+	return b.String()
+}
+
+// lines renders the narrative sentences for one rig, skipping empty categories.
+func (r *RigDigest) lines() []string {
+	var out []string
+	if len(r.Dispatched) > 0 || len(r.Completed) > 0 {
+		line := fmt.Sprintf("Work: %d dispatched, %d completed", len(r.Dispatched), len(r.Completed))
+		if len(r.Completed) > 0 {
+			line += " (" + summarizeItems(r.Completed) + ")"
+		}
+		out = append(out, line)
+	}
+	if len(r.Opened) > 0 || len(r.Started) > 0 || len(r.Closed) > 0 {
+		line := fmt.Sprintf("Beads: %d opened, %d started, %d closed", len(r.Opened), len(r.Started), len(r.Closed))
+		if len(r.Closed) > 0 {
+			line += " (" + summarizeItems(r.Closed) + ")"
+		}
+		out = append(out, line)
+	}
+	if len(r.Merged) > 0 || len(r.MergeFailed) > 0 {
+		line := fmt.Sprintf("Merges: %d landed", len(r.Merged))
+		if len(r.MergeFailed) > 0 {
+			line += fmt.Sprintf(", %d failed (%s)", len(r.MergeFailed), summarizeItems(r.MergeFailed))
+		}
+		out = append(out, line)
+	}
+	if len(r.Escalations) > 0 {
+		out = append(out, fmt.Sprintf("Escalations: %d (%s)", len(r.Escalations), summarizeItems(r.Escalations)))
+	}
+	if len(r.Limits) > 0 {
+		out = append(out, fmt.Sprintf("Limits: %s", summarizeItems(r.Limits)))
+	}
+	if len(r.Health) > 0 {
+		out = append(out, "Health: "+strings.Join(r.Health, "; "))
+	}
+	return out
+}
+
+// eventRig attributes an event to a rig. Agent addresses ("gastown/polecats/Toast",
+// "gastown/refinery") are preferred over the payload's rig field, which some
+// emitters fill with non-rig values. Mayor, deacon, and dog activity is town-level.
+func eventRig(e *events.Event) string {
+	for _, addr := range []string{payloadStr(e, "target"), payloadStr(e, "agent_id"), e.Actor} {
+		if i := strings.Index(addr, "/"); i > 0 {
+			prefix := addr[:i]
+			if prefix == constants.RoleMayor || prefix == constants.RoleDeacon || prefix == "daemon" {
+				return townKey
+			}
+			return prefix
+		}
+	}
+	if rig := payloadStr(e, "rig"); rig != "" {
+		return rig
+	}
+	return townKey
+}
+
+// mergeSubject names what a merge event was about: the worker, else the branch.
+func mergeSubject(e *events.Event) string {
+	return firstNonEmpty(payloadStr(e, "worker"), payloadStr(e, "branch"), payloadStr(e, "mr"), "unknown")
+}
+
+// shortAgent reduces an agent address to its last segment ("gastown/polecats/Toast" → "Toast").
+func shortAgent(addr string) string {
+	addr = strings.TrimSuffix(addr, "/")
+	if i := strings.LastIndex(addr, "/"); i >= 0 {
+		return addr[i+1:]
+	}
+	return addr
+}
+
+// summarizeItems joins up to digestMaxItems items and notes how many were omitted.
+func summarizeItems(items []string) string {
+	if len(items) <= digestMaxItems {
+		return strings.Join(items, ", ")
+	}
+	return strings.Join(items[:digestMaxItems], ", ") + fmt.Sprintf(", +%d more", len(items)-digestMaxItems)
+}
+
+// formatDigestWindow renders a window as "8h", "90m", or "2d".
+func formatDigestWindow(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour && d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dm", int(d.Round(time.Minute)/time.Minute))
+	}
+}
+
+func payloadStr(e *events.Event, key string) string {
+	s, _ := e.Payload[key].(string)
+	return s
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func appendUnique(items []string, item string) []string {
+	for _, it := range items {
+		if it == item {
+			return items
+		}
+	}
+	return append(items, item)
+}
@@ -0,0 +1,251 @@
+package feed
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func digestEvent(ts time.Time, eventType, actor string, payload map[string]interface{}) events.Event {
+	return events.Event{
+		Timestamp:  ts.UTC().Format(time.RFC3339),
+		Source:     "gt",
+		Type:       eventType,
+		Actor:      actor,
+		Payload:    payload,
+		Visibility: events.VisibilityFeed,
+	}
+}
+
+func TestBuildDigest_GroupsByRig(t *testing.T) {
+	now := time.Now()
+	evts := []events.Event{
+		digestEvent(now, events.TypeSling, "mayor", events.SlingPayload("gt-abc", "gastown/polecats/Toast")),
+		digestEvent(now, events.TypeSling, "mayor", events.SlingPayload("gt-abc", "gastown/polecats/Toast")), // re-sling
+		digestEvent(now, events.TypeSling, "mayor", events.SlingPayload("bd-xyz", "beads/polecats/Nux")),
+		digestEvent(now, events.TypeDone, "gastown/polecats/Toast", events.DonePayload("gt-abc", "polecat/Toast")),
+		digestEvent(now, events.TypeMerged, "gastown/refinery", events.MergePayload("mr-1", "Toast", "polecat/Toast", "")),
+		digestEvent(now, events.TypeMergeFailed, "gastown/refinery", events.MergePayload("mr-2", "Nux", "polecat/Nux", "conflict")),
+		digestEvent(now, events.TypeEscalationSent, "gastown/polecats/Toast", events.EscalationPayload("hq-esc1", "gastown/polecats/Toast", "mayor/", "tests failing")),
+		digestEvent(now, events.TypeLimitHit, events.AgentWatchActor, events.LimitHitPayload("gt-Toast", "gastown/polecats/Toast", "usage_limit", "resets 2pm")),
+		digestEvent(now, events.TypeLimitHit, "gt-top", events.LimitHitPayload("gt-Toast", "gastown/polecats/Toast", "usage_limit", "resets 2pm")), // a copy: ignored
+		digestEvent(now, events.TypeLimitHit, events.AgentWatchActor, events.LimitHitPayload("hq-deacon", "deacon", "rate_limit", "")),
+		digestEvent(now, events.TypeToolStarted, "gt-top", map[string]interface{}{"tool": "Bash"}), // ignored
+	}
+
+	d := BuildDigest(evts, now.Add(-8*time.Hour), now)
+	if len(d.Rigs) != 3 {
+		t.Fatalf("got %d rigs, want 3 (town, beads, gastown)", len(d.Rigs))
+	}
+	if d.Rigs[0].Rig != townKey || d.Rigs[1].Rig != "beads" || d.Rigs[2].Rig != "gastown" {
+		t.Errorf("rig order = %s, %s, %s; want town, beads, gastown", d.Rigs[0].Rig, d.Rigs[1].Rig, d.Rigs[2].Rig)
+	}
+
+	gt := d.Rigs[2]
+	if len(gt.Dispatched) != 1 || gt.Dispatched[0] != "gt-abc" {
+		t.Errorf("Dispatched = %v, want [gt-abc]", gt.Dispatched)
+	}
+	if len(gt.Completed) != 1 {
+		t.Errorf("Completed = %v, want 1 bead", gt.Completed)
+	}
+	if len(gt.Merged) != 1 || gt.Merged[0] != "Toast" {
+		t.Errorf("Merged = %v, want [Toast]", gt.Merged)
+	}
+	if len(gt.MergeFailed) != 1 || gt.MergeFailed[0] != "Nux: conflict" {
+		t.Errorf("MergeFailed = %v, want [Nux: conflict]", gt.MergeFailed)
+	}
+	if len(gt.Escalations) != 1 || gt.Escalations[0] != "Toast: tests failing" {
+		t.Errorf("Escalations = %v, want [Toast: tests failing]", gt.Escalations)
+	}
+	if len(gt.Limits) != 1 || gt.Limits[0] != "Toast (usage limit, resets 2pm)" {
+		t.Errorf("Limits = %v, want [Toast (usage limit, resets 2pm)]", gt.Limits)
+	}
+	if len(d.Rigs[0].Limits) != 1 || d.Rigs[0].Limits[0] != "deacon (rate limit)" {
+		t.Errorf("town Limits = %v, want [deacon (rate limit)]", d.Rigs[0].Limits)
+	}
+}
+
+func TestBuildDigest_BeadMovement(t *testing.T) {
+	now := time.Now()
+	evts := []events.Event{
+		digestEvent(now, events.TypeBeadCreated, "mayor", events.BeadTransitionPayload("gt-a", "Fix login", "gastown", "")),
+		digestEvent(now, events.TypeBeadInProgress, "gastown/polecats/Toast", events.BeadTransitionPayload("gt-a", "", "gastown", "open")),
+		digestEvent(now, events.TypeBeadClosed, "gastown/polecats/Toast", events.BeadTransitionPayload("gt-a", "", "gastown", "in_progress")),
+		digestEvent(now, events.TypeBeadClosed, "steve", events.BeadTransitionPayload("gt-b", "", "gastown", "open")),
+	}
+
+	d := BuildDigest(evts, now.Add(-8*time.Hour), now)
+	var gastown *RigDigest
+	for _, rd := range d.Rigs {
+		if rd.Rig == "gastown" {
+			gastown = rd
+		}
+	}
+	if gastown == nil {
+		t.Fatalf("no gastown section in %+v", d.Rigs)
+	}
+	// Actors without an address ("mayor", "steve") fall back to the store's rig.
+	if len(gastown.Opened) != 1 || len(gastown.Started) != 1 || len(gastown.Closed) != 2 {
+		t.Errorf("gastown opened=%v started=%v closed=%v, want 1, 1 and 2", gastown.Opened, gastown.Started, gastown.Closed)
+	}
+
+	out, err := d.Render(DigestFormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Beads: 1 opened, 1 started, 2 closed (gt-a, gt-b)") {
+		t.Errorf("digest missing bead line:\n%s", out)
+	}
+}
+
+func TestDigestRender(t *testing.T) {
+	until := time.Date(2026, 1, 15, 9, 0, 0, 0, time.Local)
+	d := &Digest{
+		Since: until.Add(-8 * time.Hour),
+		Until: until,
+		Rigs: []*RigDigest{{
+			Rig:         "gastown",
+			Dispatched:  []string{"gt-a", "gt-b"},
+			Completed:   []string{"gt-a"},
+			MergeFailed: []string{"Nux: conflict"},
+		}},
+	}
+
+	md, err := d.Render(DigestFormatMarkdown)
+	if err != nil {
+		t.Fatalf("Render(md): %v", err)
+	}
+	for _, want := range []string{
+		"# Gas Town digest: last 8h",
+		"## gastown",
+		"- Work: 2 dispatched, 1 completed (gt-a)",
+		"- Merges: 0 landed, 1 failed (Nux: conflict)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "Escalations") {
+		t.Errorf("markdown should omit empty categories:\n%s", md)
+	}
+
+	slack, err := d.Render(DigestFormatSlack)
+	if err != nil {
+		t.Fatalf("Render(slack): %v", err)
+	}
+	if !strings.Contains(slack, "*gastown*") || !strings.Contains(slack, "• Work:") {
+		t.Errorf("slack output not in mrkdwn:\n%s", slack)
+	}
+
+	page, err := d.Render(DigestFormatHTML)
+	if err != nil {
+		t.Fatalf("Render(html): %v", err)
+	}
+	if !strings.Contains(page, "<h2>gastown</h2>") || !strings.Contains(page, "<li>Work:") {
+		t.Errorf("html output missing rig section:\n%s", page)
+	}
+
+	if _, err := d.Render("pdf"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestDigestAddHealth(t *testing.T) {
+	now := time.Now()
+	d := BuildDigest(nil, now.Add(-24*time.Hour), now)
+	slas := map[string]*RigSLA{
+		"gastown": {Rig: "gastown", Checks: []SLACheck{
+			{Name: SLAMergeQueueDrain, Target: 2 * time.Hour, Breaches: 3},
+			{Name: SLAEscalationAck, Target: 15 * time.Minute},
+		}},
+	}
+	slos := map[string]*RigSLO{
+		"beads": {Rig: "beads", Checks: []SLOCheck{
+			{Name: SLOMergeFailures, Target: 0.1, Failures: 3, Total: 10},
+		}},
+	}
+	d.AddHealth(slas, slos)
+
+	if len(d.Rigs) != 2 || d.Rigs[0].Rig != "beads" || d.Rigs[1].Rig != "gastown" {
+		t.Fatalf("rigs = %+v, want beads and gastown sections", d.Rigs)
+	}
+	if got := d.Rigs[1].Health; len(got) != 1 || !strings.Contains(got[0], "merge queue drain over 2h 3 time(s)") {
+		t.Errorf("gastown health = %q", got)
+	}
+	if got := d.Rigs[0].Health; len(got) != 1 || !strings.Contains(got[0], "merge failure rate 30%") {
+		t.Errorf("beads health = %q", got)
+	}
+	md, _ := d.Render(DigestFormatMarkdown)
+	if !strings.Contains(md, "- Health: merge queue drain") {
+		t.Errorf("markdown missing health line:\n%s", md)
+	}
+}
+
+func TestDigestRender_Quiet(t *testing.T) {
+	now := time.Now()
+	out, err := BuildDigest(nil, now.Add(-time.Hour), now).Render(DigestFormatMarkdown)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if !strings.Contains(out, "Quiet period") {
+		t.Errorf("expected quiet-period line:\n%s", out)
+	}
+}
+
+func TestReadDigestEvents_FiltersBySince(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	var lines []string
+	for _, e := range []events.Event{
+		digestEvent(now.Add(-10*time.Hour), events.TypeSling, "mayor", events.SlingPayload("gt-old", "gastown/polecats/Toast")),
+		digestEvent(now.Add(-1*time.Hour), events.TypeSling, "mayor", events.SlingPayload("gt-new", "gastown/polecats/Toast")),
+	} {
+		data, _ := json.Marshal(e)
+		lines = append(lines, string(data))
+	}
+	retry := digestEvent(now.Add(-30*time.Minute), events.TypeDone, "gastown/polecats/Toast", events.DonePayload("gt-new", "polecat/Toast"))
+	retry.IdempotencyKey = "done:gt-new"
+	for i := 0; i < 2; i++ {
+		data, _ := json.Marshal(retry)
+		lines = append(lines, string(data))
+	}
+	lines = append(lines, "not json")
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	evts, err := ReadDigestEvents(townRoot, now.Add(-8*time.Hour))
+	if err != nil {
+		t.Fatalf("ReadDigestEvents: %v", err)
+	}
+	if len(evts) != 2 || evts[0].Payload["bead"] != "gt-new" || evts[1].Type != events.TypeDone {
+		t.Errorf("got %v, want gt-new sling and one done (retry dropped)", evts)
+	}
+
+	evts, err = ReadDigestEvents(t.TempDir(), now)
+	if err != nil || evts != nil {
+		t.Errorf("missing file: got %v, %v; want nil, nil", evts, err)
+	}
+}
+
+func TestFormatDigestWindow(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{8 * time.Hour, "8h"},
+		{24 * time.Hour, "24h"},
+		{72 * time.Hour, "3d"},
+		{90 * time.Minute, "90m"},
+	}
+	for _, tt := range tests {
+		if got := formatDigestWindow(tt.d); got != tt.want {
+			t.Errorf("formatDigestWindow(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
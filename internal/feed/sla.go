@@ -0,0 +1,142 @@
+package feed
+
+import (
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// SLA check names.
+const (
+	SLAMergeQueueDrain = "merge"
+	SLAEscalationAck   = "ack"
+)
+
+// DefaultSLAWindow is how far back breaches are counted when the config
+// doesn't set a window.
+const DefaultSLAWindow = 24 * time.Hour
+
+// SLACheck is the state of one SLA target for one rig.
+type SLACheck struct {
+	Name     string        // SLAMergeQueueDrain or SLAEscalationAck
+	Target   time.Duration // configured limit
+	Breaches int           // items that exceeded the target (finished late or still open past it)
+	Open     int           // items still in flight and within target
+}
+
+// RigSLA holds the SLA checks for one rig, in a stable order.
+type RigSLA struct {
+	Rig    string
+	Checks []SLACheck
+}
+
+// Breached reports whether any check on the rig has breaches.
+func (r *RigSLA) Breached() bool {
+	for _, c := range r.Checks {
+		if c.Breaches > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// slaItem tracks one measured interval: a branch waiting to merge or an
+// escalation waiting to be acknowledged.
+type slaItem struct {
+	rig   string
+	start time.Time
+	end   time.Time // zero while still open
+}
+
+// EvaluateRigSLAs computes per-rig SLA state from events. Only rigs with at
+// least one configured target appear in the result (keyed by rig name);
+// town-level activity is never subject to rig SLAs.
+//
+// Merge queue drain is measured from a worker's done event to the merged
+// event for the same branch. Escalation ack is measured from
+// escalation_sent to escalation_acked or escalation_closed for the same
+// escalation ID. Items are attributed to the rig of the starting event.
+func EvaluateRigSLAs(evts []events.Event, cfg *config.RigSLAConfig, now time.Time) map[string]*RigSLA {
+	if cfg == nil {
+		return nil
+	}
+
+	merges := make(map[string]*slaItem) // branch -> item
+	escalations := make(map[string]*slaItem)
+	for i := range evts {
+		e := &evts[i]
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			continue
+		}
+		switch e.Type {
+		case events.TypeDone:
+			if branch := payloadStr(e, "branch"); branch != "" {
+				merges[branch] = &slaItem{rig: eventRig(e), start: ts}
+			}
+		case events.TypeMerged:
+			if it := merges[payloadStr(e, "branch")]; it != nil && it.end.IsZero() {
+				it.end = ts
+			}
+		case events.TypeEscalationSent:
+			// Re-escalations reuse the ID of an escalation that is already tracked.
+			id := payloadStr(e, "escalation_id")
+			if id != "" && escalations[id] == nil {
+				escalations[id] = &slaItem{rig: eventRig(e), start: ts}
+			}
+		case events.TypeEscalationAcked, events.TypeEscalationClosed:
+			if it := escalations[payloadStr(e, "escalation_id")]; it != nil && it.end.IsZero() {
+				it.end = ts
+			}
+		}
+	}
+
+	result := make(map[string]*RigSLA)
+	tally := func(items map[string]*slaItem, name string, target func(config.RigSLATargets) string) {
+		for _, it := range items {
+			if it.rig == townKey {
+				continue
+			}
+			limit := config.ParseDurationOrDefault(target(cfg.TargetsFor(it.rig)), 0)
+			if limit <= 0 {
+				continue
+			}
+			rs := result[it.rig]
+			if rs == nil {
+				rs = &RigSLA{Rig: it.rig}
+				result[it.rig] = rs
+			}
+			check := rs.check(name, limit)
+			end := it.end
+			if end.IsZero() {
+				end = now
+			}
+			switch {
+			case end.Sub(it.start) > limit:
+				check.Breaches++
+			case it.end.IsZero():
+				check.Open++
+			}
+		}
+	}
+	tally(merges, SLAMergeQueueDrain, func(t config.RigSLATargets) string { return t.MergeQueueDrain })
+	tally(escalations, SLAEscalationAck, func(t config.RigSLATargets) string { return t.EscalationAck })
+
+	for _, rs := range result {
+		sort.Slice(rs.Checks, func(i, j int) bool { return rs.Checks[i].Name > rs.Checks[j].Name })
+	}
+	return result
+}
+
+// check returns the named check, adding it if missing.
+func (r *RigSLA) check(name string, target time.Duration) *SLACheck {
+	for i := range r.Checks {
+		if r.Checks[i].Name == name {
+			return &r.Checks[i]
+		}
+	}
+	r.Checks = append(r.Checks, SLACheck{Name: name, Target: target})
+	return &r.Checks[len(r.Checks)-1]
+}
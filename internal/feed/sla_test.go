@@ -0,0 +1,69 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestEvaluateRigSLAs(t *testing.T) {
+	now := time.Now()
+	cfg := &config.RigSLAConfig{
+		Default: &config.RigSLATargets{MergeQueueDrain: "30m", EscalationAck: "10m"},
+		Rigs:    map[string]*config.RigSLATargets{"beads": {MergeQueueDrain: "2h"}},
+	}
+	esc := func(id string) map[string]interface{} {
+		p := events.EscalationPayload(id, "gastown/polecats/Toast", "mayor/", "stuck")
+		p["escalation_id"] = id
+		return p
+	}
+	evts := []events.Event{
+		// gastown: one merge on time, one late, one open past target, one open within target
+		digestEvent(now.Add(-3*time.Hour), events.TypeDone, "gastown/polecats/Toast", events.DonePayload("gt-1", "polecat/Toast-1")),
+		digestEvent(now.Add(-170*time.Minute), events.TypeMerged, "gastown/refinery", events.MergePayload("mr-1", "Toast", "polecat/Toast-1", "")),
+		digestEvent(now.Add(-2*time.Hour), events.TypeDone, "gastown/polecats/Nux", events.DonePayload("gt-2", "polecat/Nux-2")),
+		digestEvent(now.Add(-1*time.Hour), events.TypeMerged, "gastown/refinery", events.MergePayload("mr-2", "Nux", "polecat/Nux-2", "")),
+		digestEvent(now.Add(-45*time.Minute), events.TypeDone, "gastown/polecats/Slit", events.DonePayload("gt-3", "polecat/Slit-3")),
+		digestEvent(now.Add(-5*time.Minute), events.TypeDone, "gastown/polecats/Rust", events.DonePayload("gt-4", "polecat/Rust-4")),
+		// beads: 45m wait is fine under the 2h override
+		digestEvent(now.Add(-45*time.Minute), events.TypeDone, "beads/polecats/Ace", events.DonePayload("bd-1", "polecat/Ace-1")),
+		// gastown escalations: one acked late, one acked on time; re-escalation ignored
+		digestEvent(now.Add(-time.Hour), events.TypeEscalationSent, "gastown/polecats/Toast", esc("hq-e1")),
+		digestEvent(now.Add(-30*time.Minute), events.TypeEscalationSent, "mayor", map[string]interface{}{"escalation_id": "hq-e1", "reescalated": true}),
+		digestEvent(now.Add(-40*time.Minute), events.TypeEscalationAcked, "mayor", map[string]interface{}{"escalation_id": "hq-e1"}),
+		digestEvent(now.Add(-20*time.Minute), events.TypeEscalationSent, "gastown/polecats/Toast", esc("hq-e2")),
+		digestEvent(now.Add(-15*time.Minute), events.TypeEscalationClosed, "mayor", map[string]interface{}{"escalation_id": "hq-e2"}),
+		// town-level work never counts against rig SLAs
+		digestEvent(now.Add(-3*time.Hour), events.TypeDone, "mayor", events.DonePayload("hq-1", "mayor-branch")),
+	}
+
+	got := EvaluateRigSLAs(evts, cfg, now)
+	if len(got) != 2 {
+		t.Fatalf("got %d rigs, want 2 (gastown, beads): %+v", len(got), got)
+	}
+
+	gt := got["gastown"]
+	if len(gt.Checks) != 2 || gt.Checks[0].Name != SLAMergeQueueDrain || gt.Checks[1].Name != SLAEscalationAck {
+		t.Fatalf("gastown checks = %+v, want merge then ack", gt.Checks)
+	}
+	if merge := gt.Checks[0]; merge.Breaches != 2 || merge.Open != 1 || merge.Target != 30*time.Minute {
+		t.Errorf("gastown merge = %+v, want 2 breaches, 1 open, 30m target", merge)
+	}
+	if ack := gt.Checks[1]; ack.Breaches != 1 || ack.Open != 0 {
+		t.Errorf("gastown ack = %+v, want 1 breach", ack)
+	}
+	if !gt.Breached() {
+		t.Error("gastown should be breached")
+	}
+
+	bd := got["beads"]
+	if bd.Breached() || bd.Checks[0].Open != 1 {
+		t.Errorf("beads = %+v, want 1 open merge and no breaches", bd.Checks)
+	}
+
+	if EvaluateRigSLAs(evts, nil, now) != nil {
+		t.Error("nil config should yield no SLAs")
+	}
+}
@@ -0,0 +1,161 @@
+package feed
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// SLO check names.
+const (
+	SLOMergeFailures = "merge-fail"
+	SLOEscalations   = "escalate"
+)
+
+// DefaultSLOWindow is the rolling window failure rates are measured over
+// when the config doesn't set slo_window.
+const DefaultSLOWindow = time.Hour
+
+// MinSLOSamples is how many attempts a window needs before its failure rate
+// can burn an SLO, so one failed merge on a quiet rig isn't a 100% outage.
+const MinSLOSamples = 5
+
+// SLOCheck is the failure rate of one SLO for one rig over the SLO window.
+type SLOCheck struct {
+	Name     string  // SLOMergeFailures or SLOEscalations
+	Target   float64 // max failure rate, 0..1
+	Failures int     // failed merges, or escalations
+	Total    int     // merge attempts, or finished work plus escalations
+}
+
+// Rate returns the failure rate, 0 when there were no attempts.
+func (c SLOCheck) Rate() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return float64(c.Failures) / float64(c.Total)
+}
+
+// Burning reports whether the failure rate exceeds the target on enough
+// samples to mean something.
+func (c SLOCheck) Burning() bool {
+	return c.Total >= MinSLOSamples && c.Rate() > c.Target
+}
+
+// RigSLO holds the SLO checks for one rig, in a stable order.
+type RigSLO struct {
+	Rig    string
+	Checks []SLOCheck
+}
+
+// Burning reports whether any SLO on the rig is burning.
+func (r *RigSLO) Burning() bool {
+	for _, c := range r.Checks {
+		if c.Burning() {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateRigSLOs computes per-rig failure-rate SLOs over the rolling SLO
+// window ending at now. Only rigs with at least one configured rate target
+// appear in the result; town-level activity is never subject to rig SLOs.
+//
+// The merge failure rate is merge_failed over merged plus merge_failed. The
+// escalation rate is escalation_sent (re-escalations excluded) over done
+// plus escalation_sent, i.e. the share of finished work that needed a human.
+func EvaluateRigSLOs(evts []events.Event, cfg *config.RigSLAConfig, now time.Time) map[string]*RigSLO {
+	if cfg == nil {
+		return nil
+	}
+	since := now.Add(-config.ParseDurationOrDefault(cfg.SLOWindow, DefaultSLOWindow))
+
+	type tally struct{ merged, mergeFailed, done, escalated int }
+	counts := make(map[string]*tally)
+	for i := range evts {
+		e := &evts[i]
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil || ts.Before(since) || ts.After(now) {
+			continue
+		}
+		rig := eventRig(e)
+		if rig == townKey {
+			continue
+		}
+		c := counts[rig]
+		if c == nil {
+			c = &tally{}
+			counts[rig] = c
+		}
+		switch e.Type {
+		case events.TypeMerged:
+			c.merged++
+		case events.TypeMergeFailed:
+			c.mergeFailed++
+		case events.TypeDone:
+			c.done++
+		case events.TypeEscalationSent:
+			if re, _ := e.Payload["reescalated"].(bool); !re {
+				c.escalated++
+			}
+		}
+	}
+
+	result := make(map[string]*RigSLO)
+	for rig, c := range counts {
+		t := cfg.TargetsFor(rig)
+		var checks []SLOCheck
+		if target, ok := ParseSLORate(t.MergeFailureRate); ok {
+			checks = append(checks, SLOCheck{Name: SLOMergeFailures, Target: target,
+				Failures: c.mergeFailed, Total: c.merged + c.mergeFailed})
+		}
+		if target, ok := ParseSLORate(t.EscalationRate); ok {
+			checks = append(checks, SLOCheck{Name: SLOEscalations, Target: target,
+				Failures: c.escalated, Total: c.done + c.escalated})
+		}
+		if len(checks) > 0 {
+			result[rig] = &RigSLO{Rig: rig, Checks: checks}
+		}
+	}
+	return result
+}
+
+// ParseSLORate parses a rate target: a percentage ("20%") or a fraction
+// ("0.2"). Returns false for empty or invalid targets and for rates outside
+// [0, 1].
+func ParseSLORate(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+	scale := 1.0
+	if strings.HasSuffix(s, "%") {
+		s, scale = strings.TrimSpace(strings.TrimSuffix(s, "%")), 100
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	v /= scale
+	if v < 0 || v > 1 {
+		return 0, false
+	}
+	return v, true
+}
+
+// BurningSLOs returns the rigs with a burning SLO, sorted by name.
+func BurningSLOs(slos map[string]*RigSLO) []*RigSLO {
+	var out []*RigSLO
+	for _, rs := range slos {
+		if rs.Burning() {
+			out = append(out, rs)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Rig < out[j].Rig })
+	return out
+}
@@ -0,0 +1,112 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestEvaluateRigSLOs(t *testing.T) {
+	now := time.Now()
+	cfg := &config.RigSLAConfig{
+		Default: &config.RigSLATargets{MergeFailureRate: "20%", EscalationRate: "0.5"},
+		Rigs:    map[string]*config.RigSLATargets{"beads": {MergeFailureRate: "50%"}},
+	}
+	merged := func(at time.Duration, rig string) events.Event {
+		return digestEvent(now.Add(-at), events.TypeMerged, rig+"/refinery", events.MergePayload("mr", "Toast", "b", ""))
+	}
+	failed := func(at time.Duration, rig string) events.Event {
+		return digestEvent(now.Add(-at), events.TypeMergeFailed, rig+"/refinery", events.MergePayload("mr", "Toast", "b", "conflict"))
+	}
+	var evts []events.Event
+	// gastown: 3 of 7 merges failed in the last hour (43% > 20%)
+	for i := 0; i < 4; i++ {
+		evts = append(evts, merged(10*time.Minute, "gastown"))
+	}
+	for i := 0; i < 3; i++ {
+		evts = append(evts, failed(20*time.Minute, "gastown"))
+	}
+	// failures outside the window don't count
+	evts = append(evts, failed(2*time.Hour, "gastown"), failed(3*time.Hour, "gastown"))
+	// gastown escalations: 1 of 3 finished items (re-escalation ignored)
+	evts = append(evts,
+		digestEvent(now.Add(-5*time.Minute), events.TypeDone, "gastown/polecats/Nux", events.DonePayload("gt-1", "polecat/Nux")),
+		digestEvent(now.Add(-5*time.Minute), events.TypeDone, "gastown/polecats/Slit", events.DonePayload("gt-2", "polecat/Slit")),
+		digestEvent(now.Add(-5*time.Minute), events.TypeEscalationSent, "gastown/polecats/Toast", events.EscalationPayload("gastown", "gastown/polecats/Toast", "mayor/", "stuck")),
+		digestEvent(now.Add(-4*time.Minute), events.TypeEscalationSent, "mayor", map[string]interface{}{"escalation_id": "hq-e1", "reescalated": true}),
+	)
+	// beads: 2 of 5 failed, within its 50% override
+	for i := 0; i < 3; i++ {
+		evts = append(evts, merged(10*time.Minute, "beads"))
+	}
+	evts = append(evts, failed(10*time.Minute, "beads"), failed(10*time.Minute, "beads"))
+	// town-level activity is never subject to rig SLOs
+	evts = append(evts, digestEvent(now.Add(-time.Minute), events.TypeEscalationSent, "mayor", nil))
+
+	got := EvaluateRigSLOs(evts, cfg, now)
+	if len(got) != 2 {
+		t.Fatalf("got %d rigs, want 2 (gastown, beads): %+v", len(got), got)
+	}
+
+	gt := got["gastown"]
+	if len(gt.Checks) != 2 || gt.Checks[0].Name != SLOMergeFailures || gt.Checks[1].Name != SLOEscalations {
+		t.Fatalf("gastown checks = %+v, want merge-fail then escalate", gt.Checks)
+	}
+	if c := gt.Checks[0]; c.Failures != 3 || c.Total != 7 || c.Target != 0.2 || !c.Burning() {
+		t.Errorf("gastown merge-fail = %+v, want 3/7 burning a 20%% target", c)
+	}
+	if c := gt.Checks[1]; c.Failures != 1 || c.Total != 3 || c.Burning() {
+		t.Errorf("gastown escalate = %+v, want 1/3, not burning (too few samples)", c)
+	}
+	if c := got["beads"].Checks[0]; c.Failures != 2 || c.Total != 5 || c.Burning() {
+		t.Errorf("beads merge-fail = %+v, want 2/5 within 50%%", c)
+	}
+
+	burning := BurningSLOs(got)
+	if len(burning) != 1 || burning[0].Rig != "gastown" {
+		t.Errorf("BurningSLOs = %+v, want gastown only", burning)
+	}
+}
+
+func TestEvaluateRigSLOs_Window(t *testing.T) {
+	now := time.Now()
+	cfg := &config.RigSLAConfig{
+		Default:   &config.RigSLATargets{MergeFailureRate: "20%"},
+		SLOWindow: "3h",
+	}
+	var evts []events.Event
+	for i := 0; i < 5; i++ {
+		evts = append(evts, digestEvent(now.Add(-2*time.Hour), events.TypeMergeFailed, "gastown/refinery", nil))
+	}
+	if c := EvaluateRigSLOs(evts, cfg, now)["gastown"].Checks[0]; c.Total != 5 || !c.Burning() {
+		t.Errorf("3h window: %+v, want 5 failures burning", c)
+	}
+	if got := EvaluateRigSLOs(evts, nil, now); got != nil {
+		t.Errorf("nil config = %+v, want nil", got)
+	}
+}
+
+func TestParseSLORate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"20%", 0.2, true},
+		{" 5 % ", 0.05, true},
+		{"0.25", 0.25, true},
+		{"0", 0, true},
+		{"", 0, false},
+		{"150%", 0, false},
+		{"-1", 0, false},
+		{"lots", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseSLORate(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseSLORate(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -3,9 +3,13 @@
 // Injects gt prime context into the system prompt via experimental.chat.system.transform.
 export const GasTown = async ({ $, directory }) => {
   const role = (process.env.GT_ROLE || "").toLowerCase();
+  // Stable agent identity set by the session launcher. Included in every
+  // gt top event so the monitor can match events without session heuristics.
+  const agentId = process.env.GT_AGENT_ID || "";
   const autonomousRoles = new Set(["polecat", "witness", "refinery", "deacon"]);
   let didInit = false;
   let tmuxSession = null;
+  let tmuxSessionAt = 0;
 
   // Promise-based context loading ensures the system transform hook can
   // await the result even if session.created hasn't resolved yet.
@@ -27,12 +31,14 @@ export const GasTown = async ({ $, directory }) => {
   };
 
   // Get tmux session name (cached) for event matching in gt top.
+  // The cache is refreshed every 30s so a renamed session is picked up;
+  // gt top credits events sent under the old name in the meantime.
   // NOTE: The format token must be interpolated — Bun's shell treats
   // bare `#` as a comment character, so `#S` would be silently eaten.
   // Interpolated values are passed as literal string arguments.
   // .quiet() prevents stdout from leaking into the OpenCode TUI.
   const getSession = async () => {
-    if (tmuxSession) return tmuxSession;
+    if (tmuxSession && Date.now() - tmuxSessionAt < 30000) return tmuxSession;
     try {
       const fmt = "#S";
       const result = await $`tmux display-message -p ${fmt}`.quiet().cwd(directory);
@@ -40,12 +46,18 @@ export const GasTown = async ({ $, directory }) => {
     } catch {
       tmuxSession = "";
     }
+    tmuxSessionAt = Date.now();
     return tmuxSession;
   };
 
   // Shell-escape a string for safe embedding in gt top emit arguments.
   const esc = (s) => (s || "").replace(/['"\\$`!]/g, "").slice(0, 80);
 
+  // Idempotency key for a tool event, so a retried hook doesn't log the
+  // same tool call twice. Empty when OpenCode doesn't supply a call ID.
+  const key = (kind, callID) =>
+    callID ? ` --idempotency-key "${kind}:${esc(callID)}"` : "";
+
   const loadPrime = async () => {
     let context = await captureRun("gt prime");
     if (autonomousRoles.has(role)) {
@@ -71,11 +83,18 @@ export const GasTown = async ({ $, directory }) => {
         // Signal compaction finished to gt top, then reload prime context.
         const session = await getSession();
         emit(
-          `gt top emit compaction_finished --actor ${esc(role)} --status "done" --message "${session}"`,
+          `gt top emit compaction_finished --actor ${esc(role)} --status "done" --message "${session}" --agent-id "${esc(agentId)}"`,
         );
         // Reset so next system.transform gets fresh context.
         primePromise = loadPrime();
       }
+      if (event?.type === "session.idle") {
+        // Signal gt top the agent finished its turn and is at its prompt.
+        const session = await getSession();
+        emit(
+          `gt top emit agent_idle --actor ${esc(role)} --message "${session}" --agent-id "${esc(agentId)}"`,
+        );
+      }
       if (event?.type === "session.deleted") {
         const sessionID = event.properties?.info?.id;
         if (sessionID) {
@@ -101,7 +120,7 @@ export const GasTown = async ({ $, directory }) => {
     // These events populate the CurrentTool field in gt top's LED display,
     // giving visibility into what OpenCode agents are doing without
     // parsing the box-drawing-character TUI via tmux capture-pane.
-    "tool.execute.before": async ({ tool }) => {
+    "tool.execute.before": async ({ tool, callID }) => {
       const session = await getSession();
       const toolName = esc(tool?.name || "unknown");
       const toolInput = esc(
@@ -111,22 +130,22 @@ export const GasTown = async ({ $, directory }) => {
       );
       const toolInfo = toolInput ? `${toolName}(${toolInput})` : toolName;
       emit(
-        `gt top emit tool_started --actor ${esc(role)} --status "${toolInfo}" --message "${session}"`,
+        `gt top emit tool_started --actor ${esc(role)} --status "${toolInfo}" --message "${session}" --agent-id "${esc(agentId)}"${key("started", callID)}`,
       );
     },
 
-    "tool.execute.after": async ({ tool }) => {
+    "tool.execute.after": async ({ tool, callID }) => {
       const session = await getSession();
       const toolName = esc(tool?.name || "unknown");
       emit(
-        `gt top emit tool_finished --actor ${esc(role)} --status "${toolName}" --message "${session}"`,
+        `gt top emit tool_finished --actor ${esc(role)} --status "${toolName}" --message "${session}" --agent-id "${esc(agentId)}"${key("finished", callID)}`,
       );
     },
 
     "experimental.session.compacting": async ({ sessionID }, output) => {
       const session = await getSession();
       emit(
-        `gt top emit compaction_started --actor ${esc(role)} --status "compacting" --message "${session}"`,
+        `gt top emit compaction_started --actor ${esc(role)} --status "compacting" --message "${session}" --agent-id "${esc(agentId)}"`,
       );
       const roleDisplay = role || "unknown";
       output.context.push(`
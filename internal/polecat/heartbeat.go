@@ -32,9 +32,10 @@ const (
 // v1: timestamp only. v2 (gt-3vr5): adds agent-reported state, context, and bead.
 type SessionHeartbeat struct {
 	Timestamp time.Time      `json:"timestamp"`
-	State     HeartbeatState `json:"state,omitempty"`   // v2: agent-reported state
-	Context   string         `json:"context,omitempty"` // v2: what the agent is doing
-	Bead      string         `json:"bead,omitempty"`    // v2: current hook bead ID
+	State     HeartbeatState `json:"state,omitempty"`    // v2: agent-reported state
+	Context   string         `json:"context,omitempty"`  // v2: what the agent is doing
+	Bead      string         `json:"bead,omitempty"`     // v2: current hook bead ID
+	AgentID   string         `json:"agent_id,omitempty"` // stable agent identity (GT_AGENT_ID)
 }
 
 // EffectiveState returns the agent-reported state, defaulting to HeartbeatWorking
@@ -86,6 +87,7 @@ func TouchSessionHeartbeatWithState(townRoot, sessionName string, state Heartbea
 		State:     state,
 		Context:   context,
 		Bead:      bead,
+		AgentID:   os.Getenv("GT_AGENT_ID"),
 	}
 
 	data, err := json.Marshal(hb)
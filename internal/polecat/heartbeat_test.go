@@ -57,6 +57,21 @@ func TestTouchSessionHeartbeatWithState(t *testing.T) {
 	}
 }
 
+func TestTouchSessionHeartbeat_RecordsAgentID(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("GT_AGENT_ID", "gastown/polecats/Toast")
+
+	TouchSessionHeartbeat(townRoot, "gt-test-id")
+
+	hb := ReadSessionHeartbeat(townRoot, "gt-test-id")
+	if hb == nil {
+		t.Fatal("expected non-nil heartbeat after touch")
+	}
+	if hb.AgentID != "gastown/polecats/Toast" {
+		t.Errorf("agent_id = %q, want %q", hb.AgentID, "gastown/polecats/Toast")
+	}
+}
+
 func TestSessionHeartbeat_EffectiveState(t *testing.T) {
 	tests := []struct {
 		name  string
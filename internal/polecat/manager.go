@@ -2367,8 +2367,7 @@ func (m *Manager) DetectStalePolecats(threshold int) ([]*StalenessInfo, error) {
 // checkTmuxSession checks if a tmux session exists.
 func checkTmuxSession(sessionName string) bool {
 	// Use has-session command which returns 0 if session exists
-	cmd := tmux.BuildCommand("has-session", "-t", sessionName)
-	return cmd.Run() == nil
+	return tmux.Run(0, "has-session", "-t", sessionName) == nil
 }
 
 // countCommitsBehind counts how many commits a worktree is behind origin/<defaultBranch>.
@@ -1857,8 +1857,10 @@ func (e *Engineer) notifyDeaconConvoyFeeding(mr *MRInfo) {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Nudged deacon: CONVOY_NEEDS_FEEDING %s\n", mr.ConvoyID)
 	}
 
-	// Emit event to wake deacon from await-signal.
-	_ = events.LogFeed(events.TypeMail, e.rig.Name+"/refinery", events.MailPayload("deacon/", "CONVOY_NEEDS_FEEDING "+mr.ConvoyID))
+	// Emit event to wake deacon from await-signal. The town is the rig's
+	// parent, as in postMergeConvoyCheck.
+	_ = events.LogInTown(filepath.Dir(e.rig.Path), "gt", events.TypeMail, e.rig.Name+"/refinery",
+		events.MailPayload("deacon/", "CONVOY_NEEDS_FEEDING "+mr.ConvoyID), events.VisibilityFeed)
 }
 
 // convoyInfo holds minimal info about a closed convoy for post-merge processing.
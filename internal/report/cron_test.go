@@ -0,0 +1,75 @@
+package report
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestSchedule_Matches(t *testing.T) {
+	at := func(s string) time.Time {
+		ts, err := time.ParseInLocation("2006-01-02 15:04 Mon", s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+	tests := []struct {
+		expr string
+		at   string
+		want bool
+	}{
+		{"0 9 * * *", "2026-10-15 09:00 Thu", true},
+		{"0 9 * * *", "2026-10-15 09:01 Thu", false},
+		{"*/15 * * * *", "2026-10-15 13:45 Thu", true},
+		{"*/15 * * * *", "2026-10-15 13:50 Thu", false},
+		{"0 9 * * 1-5", "2026-10-17 09:00 Sat", false},
+		{"0 9 * * 7", "2026-10-18 09:00 Sun", true},
+		{"0 0 1,15 * *", "2026-10-15 00:00 Thu", true},
+		// Both day fields restricted: either one matching is enough.
+		{"0 0 1 * 1", "2026-10-19 00:00 Mon", true},
+		{"0 0 1 * 1", "2026-10-20 00:00 Tue", false},
+		{"@daily", "2026-10-15 00:00 Thu", true},
+	}
+	for _, tt := range tests {
+		s, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := s.Matches(at(tt.at)); got != tt.want {
+			t.Errorf("%q matches %s = %v, want %v", tt.expr, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	from := time.Date(2026, 10, 15, 9, 30, 20, 0, time.Local)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 9 * * *", time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)},
+		{"*/15 * * * *", time.Date(2026, 10, 15, 9, 45, 0, 0, time.Local)},
+		{"30 9 * * *", time.Date(2026, 10, 16, 9, 30, 0, 0, time.Local)},
+		{"0 8 * * 1", time.Date(2026, 10, 19, 8, 0, 0, 0, time.Local)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		s, _ := ParseCron(tt.expr)
+		if got := s.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q next after %s = %s, want %s", tt.expr, from, got, tt.want)
+		}
+	}
+
+	never, _ := ParseCron("0 0 30 2 *")
+	if got := never.Next(from); !got.IsZero() {
+		t.Errorf("February 30th next = %s, want zero", got)
+	}
+}
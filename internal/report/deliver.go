@@ -0,0 +1,100 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/feed"
+)
+
+// CommandTimeout bounds a report's command hook.
+const CommandTimeout = 2 * time.Minute
+
+// sendMail is smtp.SendMail, swapped in tests.
+var sendMail = smtp.SendMail
+
+// Deliver sends the report to each of the schedule's targets: mail
+// recipients, then the command hook. Every target is tried; the errors of
+// those that failed are joined.
+func Deliver(townRoot string, rs config.ReportSchedule, r *Report) error {
+	var errs []error
+	if len(rs.Mail) > 0 {
+		if err := mailReport(townRoot, rs.Mail, r); err != nil {
+			errs = append(errs, fmt.Errorf("mail: %w", err))
+		}
+	}
+	if rs.Command != "" {
+		if err := runHook(townRoot, rs.Command, r); err != nil {
+			errs = append(errs, fmt.Errorf("command: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// mailReport sends the report through the SMTP server configured in the
+// escalation config's contacts, the same one escalation e-mails use.
+func mailReport(townRoot string, to []string, r *Report) error {
+	cfg, err := config.LoadEscalationConfig(config.EscalationConfigPath(townRoot))
+	if err != nil {
+		return err
+	}
+	host := cfg.Contacts.SMTPHost
+	if host == "" {
+		return fmt.Errorf("no smtp_host in %s", config.EscalationConfigPath(townRoot))
+	}
+	port := cfg.Contacts.SMTPPort
+	if port == "" {
+		port = "587"
+	}
+	from := cfg.Contacts.SMTPFrom
+	if from == "" {
+		from = "gastown@localhost"
+	}
+
+	contentType := "text/plain"
+	if r.Format == feed.DigestFormatHTML {
+		contentType = "text/html"
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: %s; charset=UTF-8\r\n\r\n%s",
+		from, strings.Join(to, ", "), r.Subject, contentType, strings.ReplaceAll(r.Body, "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if cfg.Contacts.SMTPUser != "" {
+		auth = smtp.PlainAuth("", cfg.Contacts.SMTPUser, cfg.Contacts.SMTPPass, host)
+	}
+	return sendMail(host+":"+port, auth, from, to, []byte(msg))
+}
+
+// runHook runs the command hook in the town root with the report on stdin.
+// GT_REPORT_NAME, GT_REPORT_SUBJECT and GT_REPORT_FORMAT describe it.
+func runHook(townRoot, command string, r *Report) error {
+	ctx, cancel := context.WithTimeout(context.Background(), CommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // G204: command comes from town settings
+	cmd.Dir = townRoot
+	cmd.Stdin = strings.NewReader(r.Body)
+	cmd.Env = append(os.Environ(),
+		"GT_REPORT_NAME="+r.Name,
+		"GT_REPORT_SUBJECT="+r.Subject,
+		"GT_REPORT_FORMAT="+r.Format,
+	)
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", CommandTimeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package report
+
+import (
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestDeliver_CommandHook(t *testing.T) {
+	town := t.TempDir()
+	r := &Report{Name: "daily", Format: "md", Subject: "Gas Town digest: last 24h", Body: "# digest\n"}
+	rs := config.ReportSchedule{Name: "daily", Command: `cat > out.md && printf '%s|%s' "$GT_REPORT_NAME" "$GT_REPORT_FORMAT" > env.txt`}
+
+	if err := Deliver(town, rs, r); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(town, "out.md")); string(got) != r.Body {
+		t.Errorf("hook stdin = %q, want the report body", got)
+	}
+	if got, _ := os.ReadFile(filepath.Join(town, "env.txt")); string(got) != "daily|md" {
+		t.Errorf("hook env = %q", got)
+	}
+
+	rs.Command = "echo boom >&2; exit 3"
+	if err := Deliver(town, rs, r); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("failing hook err = %v, want its stderr", err)
+	}
+}
+
+func TestDeliver_Mail(t *testing.T) {
+	town := t.TempDir()
+	path := config.EscalationConfigPath(town)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	r := &Report{Name: "daily", Format: "html", Subject: "Gas Town digest: last 24h", Body: "<html></html>\n"}
+	rs := config.ReportSchedule{Name: "daily", Mail: []string{"ops@example.com"}}
+
+	if err := os.WriteFile(path, []byte(`{"type":"escalation","version":1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Deliver(town, rs, r); err == nil || !strings.Contains(err.Error(), "smtp_host") {
+		t.Errorf("err = %v, want a missing smtp_host error", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"type":"escalation","version":1,"contacts":{"smtp_host":"mail.example.com","smtp_from":"gt@example.com"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var addr, from, msg string
+	var to []string
+	old := sendMail
+	sendMail = func(a string, _ smtp.Auth, f string, t []string, m []byte) error {
+		addr, from, to, msg = a, f, t, string(m)
+		return nil
+	}
+	defer func() { sendMail = old }()
+
+	if err := Deliver(town, rs, r); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	if addr != "mail.example.com:587" || from != "gt@example.com" || len(to) != 1 || to[0] != "ops@example.com" {
+		t.Errorf("sent via %s from %s to %v", addr, from, to)
+	}
+	if !strings.Contains(msg, "Subject: Gas Town digest: last 24h\r\n") || !strings.Contains(msg, "Content-Type: text/html") {
+		t.Errorf("message headers wrong:\n%s", msg)
+	}
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/feed"
+)
+
+// DefaultSince is how far back a report looks when its schedule sets no Since.
+const DefaultSince = 24 * time.Hour
+
+// Report is a rendered town health report.
+type Report struct {
+	Name    string
+	Format  string
+	Subject string
+	Body    string
+}
+
+// Build renders the report for a schedule: the event digest over its window
+// plus each rig's SLA breaches and SLO burns, when the town has SLAs set.
+func Build(townRoot string, rs config.ReportSchedule, now time.Time) (*Report, error) {
+	window := DefaultSince
+	if rs.Since != "" {
+		d, err := time.ParseDuration(rs.Since)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("report %s: invalid since %q", rs.Name, rs.Since)
+		}
+		window = d
+	}
+	format := rs.Format
+	if format == "" {
+		format = feed.DigestFormatMarkdown
+	}
+
+	since := now.Add(-window)
+	evts, err := feed.ReadDigestEvents(townRoot, since)
+	if err != nil {
+		return nil, err
+	}
+	d := feed.BuildDigest(evts, since, now)
+
+	// Health is measured over the report window, not the gt top defaults.
+	if ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && ts.RigSLAs != nil {
+		cfg := *ts.RigSLAs
+		cfg.Window = window.String()
+		cfg.SLOWindow = window.String()
+		d.AddHealth(feed.EvaluateRigSLAs(evts, &cfg, now), feed.EvaluateRigSLOs(evts, &cfg, now))
+	}
+	if rs.Rig != "" {
+		d.FilterRig(rs.Rig)
+	}
+
+	body, err := d.Render(format)
+	if err != nil {
+		return nil, err
+	}
+	subject := d.Title()
+	if rs.Rig != "" {
+		subject += " (" + rs.Rig + ")"
+	}
+	return &Report{Name: rs.Name, Format: format, Subject: subject, Body: body}, nil
+}
+
+// Due reports whether a schedule fired between lastRun and now. A report
+// missed while the daemon was down is sent once on the next check.
+func Due(s *Schedule, lastRun, now time.Time) bool {
+	next := s.Next(lastRun)
+	return !next.IsZero() && !next.After(now)
+}
@@ -0,0 +1,94 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func writeTestEvents(t *testing.T, townRoot string, evts ...events.Event) {
+	t.Helper()
+	var b strings.Builder
+	for _, e := range evts {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuild_IncludesHealth(t *testing.T) {
+	town := t.TempDir()
+	now := time.Now()
+	ts := func(ago time.Duration) string { return now.Add(-ago).UTC().Format(time.RFC3339) }
+	writeTestEvents(t, town,
+		events.Event{Timestamp: ts(5 * time.Hour), Type: events.TypeDone, Actor: "gastown/polecats/Toast", Payload: map[string]interface{}{"bead": "gt-1", "branch": "polecat/Toast"}},
+		events.Event{Timestamp: ts(1 * time.Hour), Type: events.TypeMerged, Actor: "gastown/refinery", Payload: map[string]interface{}{"branch": "polecat/Toast"}},
+		events.Event{Timestamp: ts(48 * time.Hour), Type: events.TypeDone, Actor: "beads/polecats/Old", Payload: map[string]interface{}{"bead": "bd-1"}},
+	)
+	settings := config.NewTownSettings()
+	settings.RigSLAs = &config.RigSLAConfig{Default: &config.RigSLATargets{MergeQueueDrain: "2h"}}
+	if err := os.MkdirAll(filepath.Dir(config.TownSettingsPath(town)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(town), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Build(town, config.ReportSchedule{Name: "daily", Format: "html"}, now)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if r.Subject != "Gas Town digest: last 24h" {
+		t.Errorf("Subject = %q", r.Subject)
+	}
+	if !strings.Contains(r.Body, "<h2>gastown</h2>") || !strings.Contains(r.Body, "merge queue drain over 2h 1 time(s)") {
+		t.Errorf("body missing gastown health:\n%s", r.Body)
+	}
+	if strings.Contains(r.Body, "beads") {
+		t.Errorf("body includes an event from before the window:\n%s", r.Body)
+	}
+
+	r, err = Build(town, config.ReportSchedule{Name: "beads", Rig: "beads", Since: "72h"}, now)
+	if err != nil {
+		t.Fatalf("Build(rig): %v", err)
+	}
+	if strings.Contains(r.Body, "gastown") || !strings.Contains(r.Body, "## beads") || !strings.HasSuffix(r.Subject, "(beads)") {
+		t.Errorf("rig report = %q:\n%s", r.Subject, r.Body)
+	}
+}
+
+func TestBuild_InvalidSince(t *testing.T) {
+	if _, err := Build(t.TempDir(), config.ReportSchedule{Name: "x", Since: "yesterday"}, time.Now()); err == nil {
+		t.Error("expected an error for an invalid since")
+	}
+}
+
+func TestDue(t *testing.T) {
+	s, _ := ParseCron("0 9 * * *")
+	day := func(h, m int) time.Time { return time.Date(2026, 10, 15, h, m, 0, 0, time.Local) }
+
+	if Due(s, day(8, 0), day(8, 59)) {
+		t.Error("due before 09:00")
+	}
+	if !Due(s, day(8, 59), day(9, 0).Add(20*time.Second)) {
+		t.Error("not due at 09:00")
+	}
+	if Due(s, day(9, 0).Add(20*time.Second), day(9, 1)) {
+		t.Error("due twice in the same day")
+	}
+	if !Due(s, day(8, 0).AddDate(0, 0, -3), day(12, 0)) {
+		t.Error("a run missed while down is not caught up")
+	}
+}
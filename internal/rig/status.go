@@ -0,0 +1,96 @@
+package rig
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+// Rig identity bead labels set by "gt rig park" and "gt rig dock".
+const (
+	ParkedLabel = "status:parked"
+	DockedLabel = "status:docked"
+)
+
+// IsParkedOrDocked checks if a rig is parked or docked by any mechanism
+// (wisp ephemeral state or persistent bead labels). Returns (blocked, reason).
+// This is the single entry point for everything that starts agents on a rig
+// (sling, convoy launch and stage, gt top) to check rig availability.
+//
+// Parked vs docked asymmetry: parked state is checked in both the wisp layer
+// (ephemeral, set by "gt rig park") and bead labels (persistent fallback for
+// when wisp state is lost during cleanup). Docked state is bead-label only
+// because "gt rig dock" never writes to wisp — it persists exclusively via
+// the rig identity bead's status:docked label.
+func IsParkedOrDocked(townRoot, rigName string) (bool, string) {
+	// Check wisp layer first (fast, local) — only relevant for parked state
+	if wisp.NewConfig(townRoot, rigName).GetString("status") == "parked" {
+		return true, "parked"
+	}
+
+	// Single bead lookup for both parked and docked labels.
+	for _, l := range identityBeadLabels(townRoot, rigName) {
+		if l == ParkedLabel {
+			return true, "parked"
+		}
+		if l == DockedLabel {
+			return true, "docked"
+		}
+	}
+	return false, ""
+}
+
+// HasBeadLabel checks if a rig's identity bead has a specific label.
+// Returns false if the rig config or bead can't be loaded (safe default).
+func HasBeadLabel(townRoot, rigName, label string) bool {
+	for _, l := range identityBeadLabels(townRoot, rigName) {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// identityBeadLabels returns the labels on a rig's identity bead, or nil
+// when the rig's prefix or bead can't be found.
+func identityBeadLabels(townRoot, rigName string) []string {
+	// Look up the beads prefix from rigs.json (the rig registry), with fallback
+	// to the rig's own config.json for isolated/test scenarios.
+	rigPath := filepath.Join(townRoot, rigName)
+	prefix := beadsPrefix(townRoot, rigPath, rigName)
+	if prefix == "" {
+		return nil
+	}
+
+	beadsPath := filepath.Join(rigPath, "mayor", "rig")
+	if _, err := os.Stat(beadsPath); err != nil {
+		beadsPath = rigPath
+	}
+
+	bd := beads.New(beadsPath)
+	rigBead, err := bd.Show(beads.RigBeadIDWithPrefix(prefix, rigName))
+	if err != nil {
+		return nil
+	}
+	return rigBead.Labels
+}
+
+func beadsPrefix(townRoot, rigPath, rigName string) string {
+	rigsConfigPath := constants.MayorRigsPath(townRoot)
+	if rigsConfig, err := config.LoadRigsConfig(rigsConfigPath); err == nil {
+		if entry, ok := rigsConfig.Rigs[rigName]; ok && entry.BeadsConfig != nil && entry.BeadsConfig.Prefix != "" {
+			return entry.BeadsConfig.Prefix
+		}
+	}
+
+	rigConfigPath := filepath.Join(rigPath, "config.json")
+	if rigCfg, err := config.LoadRigConfig(rigConfigPath); err == nil && rigCfg.Beads != nil && rigCfg.Beads.Prefix != "" {
+		return rigCfg.Beads.Prefix
+	}
+
+	return ""
+}
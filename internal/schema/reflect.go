@@ -0,0 +1,94 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// fromType builds the schema of a Go type as encoding/json marshals it.
+// path is the dotted property path of the value (array items share their
+// array's path), used to look up enums.
+func fromType(t reflect.Type, path string, enums map[string][]string) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	s := map[string]any{}
+	switch {
+	case t == timeType:
+		s["type"] = "string"
+		s["format"] = "date-time"
+	case t.Kind() == reflect.Struct:
+		s["type"] = "object"
+		props := map[string]any{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, omitempty, ok := jsonField(f)
+			if !ok {
+				continue
+			}
+			props[name] = fromType(f.Type, join(path, name), enums)
+			if !omitempty {
+				required = append(required, name)
+			}
+		}
+		s["properties"] = props
+		if len(required) > 0 {
+			s["required"] = required
+		}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		s["type"] = "array"
+		s["items"] = fromType(t.Elem(), path, enums)
+	case t.Kind() == reflect.Map:
+		s["type"] = "object"
+		if t.Elem().Kind() == reflect.Interface {
+			s["additionalProperties"] = true
+		} else {
+			s["additionalProperties"] = fromType(t.Elem(), path, enums)
+		}
+	case t.Kind() == reflect.String:
+		s["type"] = "string"
+	case t.Kind() == reflect.Bool:
+		s["type"] = "boolean"
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		s["type"] = "integer"
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		s["type"] = "number"
+	}
+	if values, ok := enums[path]; ok && path != "" {
+		s["enum"] = values
+	}
+	return s
+}
+
+// jsonField returns the JSON name of an exported struct field and whether
+// it is omitempty. ok is false for fields encoding/json skips.
+func jsonField(f reflect.StructField) (name string, omitempty, ok bool) {
+	if !f.IsExported() {
+		return "", false, false
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	for _, o := range strings.Split(opts, ",") {
+		if o == "omitempty" || o == "omitzero" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, true
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
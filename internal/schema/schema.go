@@ -0,0 +1,119 @@
+// Package schema publishes versioned JSON Schemas for the files and feeds
+// Gas Town writes for other programs: gt top's agent status file and the
+// events log. The schemas are generated from the Go types by reflection and
+// checked in under v<Version>/, so external consumers can generate clients
+// and tests catch incompatible changes to the types.
+package schema
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/tui/activity"
+)
+
+// Version is the schema version. Adding an optional field or an enum value
+// is compatible and only needs the published files regenerated; removing or
+// renaming a field, changing its type, or making it required needs a new
+// version.
+const Version = 1
+
+// Draft is the JSON Schema dialect the schemas are written in.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+//go:embed v*/*.schema.json
+var published embed.FS
+
+// Schema describes one published schema.
+type Schema struct {
+	Name        string // e.g. "agent-status"; the file is v<Version>/<Name>.schema.json
+	Title       string
+	Description string
+
+	typ   reflect.Type
+	enums map[string][]string // property path (e.g. "agents.level") -> allowed values
+}
+
+var schemas = []Schema{
+	{
+		Name:        "agent-status",
+		Title:       "gt top agent status",
+		Description: "The status file gt top rewrites every poll at <town>/.runtime/" + activity.StatusFileName + ".",
+		typ:         reflect.TypeOf(activity.Status{}),
+		enums:       map[string][]string{"agents.level": activity.LevelNames()},
+	},
+	{
+		Name:        "event",
+		Title:       "Gas Town event",
+		Description: "One line of the events log (<town>/" + events.EventsFile + ") and the curated feed.",
+		typ:         reflect.TypeOf(events.Event{}),
+		enums: map[string][]string{
+			"type":       events.KnownTypes(),
+			"visibility": {events.VisibilityAudit, events.VisibilityFeed, events.VisibilityBoth},
+		},
+	},
+}
+
+// All returns every published schema, sorted by name.
+func All() []Schema {
+	out := append([]Schema(nil), schemas...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Get returns the named schema.
+func Get(name string) (Schema, error) {
+	for _, s := range schemas {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	var names []string
+	for _, s := range All() {
+		names = append(names, s.Name)
+	}
+	return Schema{}, fmt.Errorf("unknown schema %q (have %v)", name, names)
+}
+
+// ID returns the schema's $id.
+func (s Schema) ID() string {
+	return fmt.Sprintf("urn:gastown:schema:%s:v%d", s.Name, Version)
+}
+
+// FileName returns the schema's path in the published tree.
+func (s Schema) FileName() string {
+	return fmt.Sprintf("v%d/%s.schema.json", Version, s.Name)
+}
+
+// Document generates the schema document from the Go type.
+func (s Schema) Document() map[string]any {
+	doc := fromType(s.typ, "", s.enums)
+	doc["$schema"] = Draft
+	doc["$id"] = s.ID()
+	doc["title"] = s.Title
+	doc["description"] = s.Description
+	return doc
+}
+
+// JSON renders the generated schema as indented JSON with a trailing
+// newline, the form the published files are checked in as.
+func (s Schema) JSON() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(s.Document()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Published returns the checked-in schema file for the current version.
+func (s Schema) Published() ([]byte, error) {
+	return published.ReadFile(s.FileName())
+}
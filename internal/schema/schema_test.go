@@ -0,0 +1,176 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/tui/activity"
+)
+
+var update = flag.Bool("update", false, "rewrite the published schemas from the Go types")
+
+// TestPublishedSchemas fails when a Go type changed without the published
+// schema being regenerated (go test ./internal/schema -update). A change
+// that would break consumers of the published version (a property removed,
+// a type changed, an enum value dropped, a new required property) is
+// refused even with -update: it needs a new Version.
+func TestPublishedSchemas(t *testing.T) {
+	for _, s := range All() {
+		got, err := s.JSON()
+		if err != nil {
+			t.Fatalf("%s: %v", s.Name, err)
+		}
+		want, err := s.Published()
+		if err == nil {
+			var published, generated map[string]any
+			if err := json.Unmarshal(want, &published); err != nil {
+				t.Fatalf("%s: %v", s.Name, err)
+			}
+			_ = json.Unmarshal(got, &generated)
+			if problems := incompatible(published, generated, s.Name); len(problems) > 0 {
+				t.Errorf("%s is incompatible with published v%d, bump schema.Version:\n  %s",
+					s.Name, Version, strings.Join(problems, "\n  "))
+				continue
+			}
+		}
+		if *update {
+			if err := os.MkdirAll(filepath.Dir(s.FileName()), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(s.FileName(), got, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v (run go test ./internal/schema -update)", s.Name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: %s is out of date with the Go types; run go test ./internal/schema -update", s.Name, s.FileName())
+		}
+	}
+}
+
+// incompatible lists what in the old schema the new one dropped or changed.
+func incompatible(old, cur map[string]any, at string) []string {
+	var out []string
+	if old["type"] != cur["type"] {
+		out = append(out, at+": type changed")
+	}
+	if oldEnum, ok := old["enum"].([]any); ok {
+		have := map[any]bool{}
+		curEnum, _ := cur["enum"].([]any)
+		for _, v := range curEnum {
+			have[v] = true
+		}
+		for _, v := range oldEnum {
+			if !have[v] {
+				out = append(out, at+": enum value "+v.(string)+" removed")
+			}
+		}
+	}
+	oldReq, _ := old["required"].([]any)
+	curReq, _ := cur["required"].([]any)
+	if len(curReq) > len(oldReq) {
+		out = append(out, at+": new required properties")
+	}
+	oldProps, _ := old["properties"].(map[string]any)
+	curProps, _ := cur["properties"].(map[string]any)
+	for name, p := range oldProps {
+		c, ok := curProps[name].(map[string]any)
+		if !ok {
+			out = append(out, at+"."+name+": removed")
+			continue
+		}
+		out = append(out, incompatible(p.(map[string]any), c, at+"."+name)...)
+	}
+	if items, ok := old["items"].(map[string]any); ok {
+		c, _ := cur["items"].(map[string]any)
+		out = append(out, incompatible(items, c, at+"[]")...)
+	}
+	return out
+}
+
+func TestIncompatible(t *testing.T) {
+	old := map[string]any{"type": "object", "properties": map[string]any{
+		"a": map[string]any{"type": "string", "enum": []any{"x", "y"}},
+		"b": map[string]any{"type": "integer"},
+	}}
+	cur := map[string]any{"type": "object", "properties": map[string]any{
+		"a": map[string]any{"type": "string", "enum": []any{"x", "z"}},
+		"c": map[string]any{"type": "integer"},
+	}}
+	got := strings.Join(incompatible(old, cur, "$"), "; ")
+	if !strings.Contains(got, "$.a: enum value y removed") || !strings.Contains(got, "$.b: removed") {
+		t.Errorf("incompatible = %q", got)
+	}
+}
+
+func TestAgentStatusValidates(t *testing.T) {
+	s, err := Get("agent-status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := activity.Status{UpdatedAt: time.Now(), Agents: []activity.AgentStatus{
+		{Session: "gt-gastown-crew-max", AgentID: "gastown/crew/max", Rig: "gastown", Role: "crew", Level: "waiting", LastActivity: time.Now()},
+		{Session: "hq-mayor", LastActivity: time.Now()},
+	}}
+	data, _ := json.Marshal(status)
+	if err := s.Validate(data); err != nil {
+		t.Errorf("valid status rejected: %v", err)
+	}
+
+	bad := []string{
+		`{"updated_at": "2026-01-01T00:00:00Z"}`,
+		`{"updated_at": "yesterday", "agents": []}`,
+		`{"updated_at": "2026-01-01T00:00:00Z", "agents": [{"session": "s", "last_activity": "2026-01-01T00:00:00Z", "level": "sleepy"}]}`,
+		`{"updated_at": "2026-01-01T00:00:00Z", "agents": [{"last_activity": "2026-01-01T00:00:00Z"}]}`,
+	}
+	for _, doc := range bad {
+		if err := s.Validate([]byte(doc)); err == nil {
+			t.Errorf("invalid status accepted: %s", doc)
+		}
+	}
+}
+
+func TestEventValidates(t *testing.T) {
+	s, err := Get("event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := events.Event{
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Source:     "gt",
+		Type:       events.TypeMergeFailed,
+		Actor:      "gastown/refinery",
+		Payload:    events.MergePayload("mr-1", "Toast", "polecat/Toast", "conflict"),
+		Visibility: events.VisibilityFeed,
+	}
+	data, _ := json.Marshal(e)
+	if err := s.Validate(data); err != nil {
+		t.Errorf("valid event rejected: %v", err)
+	}
+	e.Type = "made_up"
+	data, _ = json.Marshal(e)
+	if err := s.Validate(data); err == nil {
+		t.Error("unknown event type accepted")
+	}
+}
+
+func TestGet(t *testing.T) {
+	if _, err := Get("nope"); err == nil || !strings.Contains(err.Error(), "agent-status") {
+		t.Errorf("Get(nope) = %v, want an error listing the schemas", err)
+	}
+	for _, s := range All() {
+		if filepath.Dir(s.FileName()) != "v1" || !strings.HasSuffix(s.ID(), ":v1") {
+			t.Errorf("%s: file %s, id %s", s.Name, s.FileName(), s.ID())
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Validate checks a JSON document against the schema. It understands the
+// subset of JSON Schema the generator emits: type, properties, required,
+// items, additionalProperties, enum, and the date-time format.
+func (s Schema) Validate(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return validate(s.Document(), v, "$")
+}
+
+func validate(s map[string]any, v any, at string) error {
+	if enum, ok := s["enum"].([]string); ok {
+		str, _ := v.(string)
+		found := false
+		for _, e := range enum {
+			found = found || e == str
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", at, v, enum)
+		}
+	}
+
+	switch s["type"] {
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: want string, got %s", at, jsonType(v))
+		}
+		if s["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+				return fmt.Errorf("%s: %q is not a date-time", at, str)
+			}
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: want boolean, got %s", at, jsonType(v))
+		}
+	case "integer", "number":
+		n, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("%s: want %s, got %s", at, s["type"], jsonType(v))
+		}
+		if s["type"] == "integer" && n != float64(int64(n)) {
+			return fmt.Errorf("%s: want integer, got %v", at, n)
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: want array, got %s", at, jsonType(v))
+		}
+		items, _ := s["items"].(map[string]any)
+		for i, item := range arr {
+			if err := validate(items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: want object, got %s", at, jsonType(v))
+		}
+		required, _ := s["required"].([]string)
+		for _, name := range required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: missing required %q", at, name)
+			}
+		}
+		props, _ := s["properties"].(map[string]any)
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sub, ok := props[k].(map[string]any)
+			if !ok {
+				sub, ok = s["additionalProperties"].(map[string]any)
+			}
+			if !ok {
+				continue // unknown properties are allowed: consumers must tolerate additions
+			}
+			if err := validate(sub, obj[k], at+"."+k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
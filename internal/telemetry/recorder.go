@@ -103,6 +103,9 @@ type recorderInstruments struct {
 	molBurnTotal          metric.Int64Counter
 	beadCreateTotal       metric.Int64Counter
 
+	// Gauges
+	townHealthGauge metric.Int64Gauge
+
 	// Histograms
 	bdDurationHist metric.Float64Histogram
 }
@@ -217,6 +220,11 @@ func initInstruments() {
 			metric.WithDescription("Total bead creations from molecule instantiation"),
 		)
 
+		// Gauges
+		inst.townHealthGauge, _ = m.Int64Gauge("gastown.town.health_score",
+			metric.WithDescription("Town health score from gt top, 0-100 (100: no agent blocked)"),
+		)
+
 		// Histograms
 		inst.bdDurationHist, _ = m.Float64Histogram("gastown.bd.duration_ms",
 			metric.WithDescription("bd CLI call round-trip latency in milliseconds"),
@@ -775,6 +783,16 @@ func RecordBeadCreate(ctx context.Context, beadID, parentID, molSource string) {
 	)
 }
 
+// RecordTownHealth records the town health score computed by gt top each
+// poll (metric only: the score changes every few seconds, too often for a
+// log event per reading).
+func RecordTownHealth(ctx context.Context, town string, score int) {
+	initInstruments()
+	inst.townHealthGauge.Record(ctx, int64(score),
+		metric.WithAttributes(attribute.String("town", town)),
+	)
+}
+
 // RecordPaneOutput emits a chunk of raw pane output (ANSI already stripped) to VictoriaLogs.
 // Opt-in: only called when GT_LOG_PANE_OUTPUT=true.
 // Content is truncated to GT_LOG_PANE_CONTENT_LIMIT bytes (default 8192).
@@ -294,3 +294,11 @@ func TestRecordBeadCreate(t *testing.T) {
 	RecordBeadCreate(ctx, "gt-abc12.s01", "gt-abc12", "mol-polecat-work")
 	RecordBeadCreate(ctx, "gt-def34.s01", "gt-def34", "mol-review")
 }
+
+func TestRecordTownHealth(t *testing.T) {
+	resetInstruments(t)
+	ctx := context.Background()
+
+	RecordTownHealth(ctx, "gt", 100)
+	RecordTownHealth(ctx, "gt", 37)
+}
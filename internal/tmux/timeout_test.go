@@ -0,0 +1,69 @@
+//go:build !windows
+
+package tmux
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeWedgedTmux puts a tmux on PATH that never returns, like a client
+// stuck talking to a wedged server.
+func fakeWedgedTmux(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\nexec sleep 30\n"
+	if err := os.WriteFile(filepath.Join(dir, "tmux"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestOutput_KillsWedgedCommand(t *testing.T) {
+	fakeWedgedTmux(t)
+
+	start := time.Now()
+	_, err := Output(100*time.Millisecond, "list-sessions")
+	if took := time.Since(start); took > 5*time.Second {
+		t.Fatalf("Output took %s, want it killed at the timeout", took)
+	}
+	if !errors.Is(err, ErrCommandTimeout) {
+		t.Fatalf("err = %v, want ErrCommandTimeout", err)
+	}
+	if !strings.Contains(err.Error(), "tmux list-sessions") {
+		t.Errorf("err = %q, want it to name the subcommand", err)
+	}
+}
+
+func TestTmuxRun_TimesOut(t *testing.T) {
+	fakeWedgedTmux(t)
+	old := CommandTimeout
+	CommandTimeout = 100 * time.Millisecond
+	defer func() { CommandTimeout = old }()
+
+	_, err := NewTmux().run("has-session", "-t", "gt-x")
+	if !errors.Is(err, ErrCommandTimeout) {
+		t.Fatalf("err = %v, want ErrCommandTimeout", err)
+	}
+}
+
+func TestIsNoServer(t *testing.T) {
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho 'no server running on /tmp/tmux-0/default' >&2\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(dir, "tmux"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	_, err := Output(time.Second, "list-sessions")
+	if err == nil || !IsNoServer(err) {
+		t.Errorf("IsNoServer(%v) = false, want true for a tmux with no server", err)
+	}
+	if IsNoServer(errors.New("permission denied")) || IsNoServer(nil) {
+		t.Error("IsNoServer true for an unrelated error")
+	}
+}
@@ -54,8 +54,19 @@ var (
 	ErrSessionRunning     = errors.New("session already running with healthy agent")
 	ErrInvalidSessionName = errors.New("invalid session name")
 	ErrIdleTimeout        = errors.New("agent not idle before timeout")
+	ErrCommandTimeout     = errors.New("tmux command timed out")
 )
 
+// CommandTimeout bounds every tmux command run through Tmux and the Run and
+// Output helpers. A tmux client talking to a wedged server otherwise blocks
+// forever, freezing whatever polls it. Interactive commands (attach) are
+// exempt.
+var CommandTimeout = 10 * time.Second
+
+// commandWaitDelay is how long a tmux client killed on timeout gets to close
+// its output pipes before Wait gives up on them.
+const commandWaitDelay = time.Second
+
 // validateSessionName checks that a session name contains only safe characters.
 // Returns ErrInvalidSessionName if the name contains dots, colons, or other
 // characters that cause tmux to silently fail or produce cryptic errors.
@@ -183,10 +194,60 @@ func BuildCommandContext(ctx context.Context, args ...string) *exec.Cmd {
 	}
 	allArgs = append(allArgs, args...)
 	cmd := exec.CommandContext(ctx, "tmux", allArgs...)
+	cmd.WaitDelay = commandWaitDelay
 	hideConsoleWindow(cmd)
 	return cmd
 }
 
+// Output runs a tmux command on the default socket and returns its stdout,
+// killing it if it hasn't finished within timeout (0 uses CommandTimeout).
+// A timeout is reported as ErrCommandTimeout.
+func Output(timeout time.Duration, args ...string) ([]byte, error) {
+	if timeout <= 0 {
+		timeout = CommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	out, err := BuildCommandContext(ctx, args...).Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, timeoutError(args, timeout)
+	}
+	return out, err
+}
+
+// Run is Output for commands whose output isn't needed.
+func Run(timeout time.Duration, args ...string) error {
+	_, err := Output(timeout, args...)
+	return err
+}
+
+// IsNoServer reports whether err, from Tmux or from Output and Run, means
+// no tmux server is running.
+func IsNoServer(err error) bool {
+	if errors.Is(err, ErrNoServer) {
+		return true
+	}
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && noServer(string(exitErr.Stderr))
+}
+
+// noServer reports whether tmux's stderr says there's no server to talk to.
+func noServer(stderr string) bool {
+	return strings.Contains(stderr, "no server running") ||
+		strings.Contains(stderr, "error connecting to") ||
+		strings.Contains(stderr, "no current target") ||
+		strings.Contains(stderr, "server exited unexpectedly")
+}
+
+// timeoutError describes a tmux command killed on timeout.
+func timeoutError(args []string, timeout time.Duration) error {
+	sub := "command"
+	if len(args) > 0 {
+		sub = args[0]
+	}
+	return fmt.Errorf("%w: tmux %s did not return within %s (server or session wedged?)", ErrCommandTimeout, sub, timeout)
+}
+
 // Tmux wraps tmux operations.
 type Tmux struct {
 	socketName string // tmux socket name (-L flag), empty = default socket
@@ -236,13 +297,22 @@ func (t *Tmux) run(args ...string) (string, error) {
 		allArgs = append(allArgs, "-L", t.socketName)
 	}
 	allArgs = append(allArgs, args...)
-	cmd := exec.Command("tmux", allArgs...)
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if len(args) == 0 || args[0] != "attach-session" {
+		ctx, cancel = context.WithTimeout(ctx, CommandTimeout)
+	}
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "tmux", allArgs...)
+	cmd.WaitDelay = commandWaitDelay
 	hideConsoleWindow(cmd)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", timeoutError(args, CommandTimeout)
+	}
 	if err != nil {
 		return "", t.wrapError(err, stderr.String(), args)
 	}
@@ -255,10 +325,7 @@ func (t *Tmux) wrapError(err error, stderr string, args []string) error {
 	stderr = strings.TrimSpace(stderr)
 
 	// Detect specific error types
-	if strings.Contains(stderr, "no server running") ||
-		strings.Contains(stderr, "error connecting to") ||
-		strings.Contains(stderr, "no current target") ||
-		strings.Contains(stderr, "server exited unexpectedly") {
+	if noServer(stderr) {
 		return ErrNoServer
 	}
 	if strings.Contains(stderr, "duplicate session") {
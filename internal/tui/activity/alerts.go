@@ -0,0 +1,276 @@
+package activity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// pagerDutyEnqueueURL is the PagerDuty Events API v2 endpoint.
+const pagerDutyEnqueueURL = "https://events.pagerduty.com/v2/enqueue"
+
+// alertTimeout bounds each notification request.
+const alertTimeout = 10 * time.Second
+
+// alertRule is a parsed top.alerts entry.
+type alertRule struct {
+	condition string
+	ladder    []alertStep
+}
+
+type alertStep struct {
+	after  time.Duration
+	notify string
+}
+
+// alertState tracks one condition on one agent while it persists.
+type alertState struct {
+	since time.Time // when the condition was first seen
+	fired int       // ladder steps already sent
+	acked bool      // acknowledged in gt top: no further steps
+}
+
+// alert is a notification due to be sent.
+type alert struct {
+	notify    string
+	session   string
+	agent     string // AgentID, or session name when unknown
+	condition string
+	summary   string // e.g. "NEEDS HUMAN: gastown/crew/max (permission) for 1h05m"
+}
+
+// alertSentMsg reports notifications that failed to send.
+type alertSentMsg struct {
+	errs []string
+}
+
+// parseAlertRules converts town settings top.alerts. Steps are sorted by
+// After so a misordered ladder still climbs in time order.
+func parseAlertRules(cfg []config.TopAlertRule) []alertRule {
+	var rules []alertRule
+	for _, c := range cfg {
+		r := alertRule{condition: c.Condition}
+		for _, s := range c.Ladder {
+			if s.Notify == "" {
+				continue
+			}
+			step := alertStep{after: config.ParseDurationOrDefault(s.After, 0), notify: s.Notify}
+			i := len(r.ladder)
+			for i > 0 && r.ladder[i-1].after > step.after {
+				i--
+			}
+			r.ladder = append(r.ladder[:i], append([]alertStep{step}, r.ladder[i:]...)...)
+		}
+		if r.condition != "" && len(r.ladder) > 0 {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// alertCondition reports whether the agent is in the named condition. Rig
+// conditions (slo_burn) never match an agent.
+func alertCondition(a *AgentLight, condition string) bool {
+	if condition == "looping" {
+		return a.Looping
+	}
+	return a.Level.String() == condition
+}
+
+// escalateAlerts advances every alert ladder and returns a command sending
+// the notifications now due, or nil. Must run after levels are computed.
+func (m *Model) escalateAlerts(now time.Time) tea.Cmd {
+	if len(m.alertRules) == 0 {
+		return nil
+	}
+	due := m.escalateSLOAlerts(now)
+	for _, a := range m.agents {
+		for _, r := range m.alertRules {
+			if !alertCondition(a, r.condition) || m.muted(a) || a.ScheduledOff {
+				delete(a.alerts, r.condition) // cleared: re-arm
+				continue
+			}
+			if r.condition == LevelRateLimited.String() && m.inOutage(a) {
+				delete(a.alerts, r.condition) // the outage banner covers it
+				continue
+			}
+			if a.alerts == nil {
+				a.alerts = make(map[string]*alertState)
+			}
+			st := a.alerts[r.condition]
+			if st == nil {
+				st = &alertState{since: now}
+				a.alerts[r.condition] = st
+			}
+			for !st.acked && st.fired < len(r.ladder) && now.Sub(st.since) >= r.ladder[st.fired].after {
+				due = append(due, a.newAlert(r.condition, r.ladder[st.fired].notify, now.Sub(st.since)))
+				st.fired++
+			}
+		}
+	}
+	if len(due) == 0 {
+		return nil
+	}
+	townRoot := m.townRoot
+	return func() tea.Msg {
+		var msg alertSentMsg
+		for _, al := range due {
+			if err := sendAlert(townRoot, al); err != nil {
+				kind, _, _ := strings.Cut(al.notify, ":") // the rest may be a secret
+				msg.errs = append(msg.errs, fmt.Sprintf("%s: %v", kind, err))
+			}
+		}
+		return msg
+	}
+}
+
+func (a *AgentLight) newAlert(condition, notify string, held time.Duration) alert {
+	agent := a.AgentID
+	if agent == "" {
+		agent = a.SessionName
+	}
+	label := strings.ToUpper(strings.ReplaceAll(condition, "_", " "))
+	if condition == "waiting" {
+		label = "NEEDS HUMAN"
+	}
+	summary := label + ": " + agent
+	if condition == "waiting" && a.WaitingReason != "" {
+		summary += " (" + a.WaitingReason + ")"
+	}
+	if held >= time.Minute {
+		summary += " for " + formatCountdown(held)
+	}
+	return alert{notify: notify, session: a.SessionName, agent: agent, condition: condition, summary: summary}
+}
+
+// acknowledgeAlerts stops the agent's alert ladders until their conditions
+// clear and recur.
+func (a *AgentLight) acknowledgeAlerts() {
+	for _, st := range a.alerts {
+		st.acked = true
+	}
+}
+
+// alertStage describes how far the agent's alerts have escalated for the
+// hover line, e.g. "alerted waiting via desktop, slack", or "".
+func (m *Model) alertStage(a *AgentLight) string {
+	var parts []string
+	for _, r := range m.alertRules {
+		st := a.alerts[r.condition]
+		if st == nil || st.fired == 0 {
+			continue
+		}
+		var via []string
+		for _, s := range r.ladder[:st.fired] {
+			via = append(via, strings.SplitN(s.notify, ":", 2)[0])
+		}
+		p := "alerted " + r.condition + " via " + strings.Join(via, ", ")
+		if st.acked {
+			p += " (acked)"
+		}
+		parts = append(parts, p)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// sendAlert delivers one notification. Slack and SMS use the contacts in
+// settings/escalation.json, shared with gt escalate.
+func sendAlert(townRoot string, al alert) error {
+	kind, arg, _ := strings.Cut(al.notify, ":")
+	switch kind {
+	case "desktop":
+		return desktopNotify("gt top", al.summary)
+	case "webhook":
+		return postJSON(arg, map[string]string{
+			"session":   al.session,
+			"agent":     al.agent,
+			"condition": al.condition,
+			"summary":   al.summary,
+		})
+	case "pagerduty":
+		return postJSON(pagerDutyEnqueueURL, map[string]any{
+			"routing_key":  arg,
+			"event_action": "trigger",
+			"dedup_key":    "gt-top-" + al.session + "-" + al.condition,
+			"payload": map[string]string{
+				"summary":  al.summary,
+				"source":   al.session,
+				"severity": "critical",
+			},
+		})
+	case "slack", "sms":
+		if townRoot == "" {
+			return fmt.Errorf("no town (contacts live in settings/escalation.json)")
+		}
+		cfg, err := config.LoadEscalationConfig(config.EscalationConfigPath(townRoot))
+		if err != nil {
+			return err
+		}
+		if kind == "slack" {
+			if cfg.Contacts.SlackWebhook == "" {
+				return fmt.Errorf("contacts.slack_webhook not configured")
+			}
+			return postJSON(cfg.Contacts.SlackWebhook, map[string]string{"text": "🚨 " + al.summary})
+		}
+		if cfg.Contacts.SMSWebhook == "" || cfg.Contacts.HumanSMS == "" {
+			return fmt.Errorf("contacts.sms_webhook or contacts.human_sms not configured")
+		}
+		return postJSON(cfg.Contacts.SMSWebhook, map[string]string{"to": cfg.Contacts.HumanSMS, "body": "[Gas Town] " + al.summary})
+	default:
+		return fmt.Errorf("unknown notify channel %q", al.notify)
+	}
+}
+
+// desktopNotify shows a local desktop notification.
+func desktopNotify(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title %q", body, title))
+	case "windows":
+		return fmt.Errorf("desktop notifications not supported on windows")
+	default:
+		cmd = exec.Command("notify-send", "--urgency=critical", title, body)
+	}
+	return cmd.Run()
+}
+
+func postJSON(target string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: alertTimeout}
+	resp, err := client.Post(target, "application/json", bytes.NewReader(body))
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return uerr.Err // drop the URL: it may embed a secret (Slack webhooks)
+		}
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("returned %s", resp.Status)
+	}
+	return nil
+}
+
+// handleAlertSent flashes notification failures; successes are silent.
+func (m *Model) handleAlertSent(msg alertSentMsg) {
+	if len(msg.errs) == 0 {
+		return
+	}
+	m.flashMessage = "alert failed: " + strings.Join(msg.errs, "; ")
+	m.flashTime = time.Now()
+}
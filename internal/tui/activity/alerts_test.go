@@ -0,0 +1,139 @@
+package activity
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestParseAlertRules(t *testing.T) {
+	rules := parseAlertRules([]config.TopAlertRule{
+		{Condition: "waiting", Ladder: []config.TopAlertStep{
+			{After: "1h", Notify: "sms"},
+			{Notify: "desktop"},
+			{After: "15m", Notify: "slack"},
+			{After: "5m"}, // no channel: dropped
+		}},
+		{Condition: "cold"}, // no ladder: dropped
+	})
+	if len(rules) != 1 {
+		t.Fatalf("got %d rules, want 1", len(rules))
+	}
+	var got []string
+	for _, s := range rules[0].ladder {
+		got = append(got, s.notify)
+	}
+	if strings.Join(got, ",") != "desktop,slack,sms" {
+		t.Errorf("ladder = %v, want sorted by after", got)
+	}
+}
+
+func TestEscalateAlerts_Ladder(t *testing.T) {
+	var posts []map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		posts = append(posts, p)
+	}))
+	defer srv.Close()
+
+	a := &AgentLight{SessionName: "gt-crew-max", AgentID: "gastown/crew/max", Level: LevelWaitingForHuman, WaitingReason: "permission"}
+	m := &Model{
+		agents: []*AgentLight{a},
+		alertRules: parseAlertRules([]config.TopAlertRule{{Condition: "waiting", Ladder: []config.TopAlertStep{
+			{Notify: "webhook:" + srv.URL},
+			{After: "15m", Notify: "webhook:" + srv.URL},
+			{After: "1h", Notify: "webhook:" + srv.URL},
+		}}}),
+	}
+
+	t0 := time.Now()
+	run := func(now time.Time) {
+		t.Helper()
+		if cmd := m.escalateAlerts(now); cmd != nil {
+			if msg := cmd().(alertSentMsg); len(msg.errs) > 0 {
+				t.Fatalf("send errors: %v", msg.errs)
+			}
+		}
+	}
+
+	run(t0)
+	run(t0.Add(5 * time.Minute))
+	if len(posts) != 1 || posts[0]["summary"] != "NEEDS HUMAN: gastown/crew/max (permission)" {
+		t.Fatalf("posts after 5m = %v", posts)
+	}
+	run(t0.Add(20 * time.Minute))
+	if len(posts) != 2 || posts[1]["summary"] != "NEEDS HUMAN: gastown/crew/max (permission) for 20m" {
+		t.Fatalf("posts after 20m = %v", posts)
+	}
+	if got := m.alertStage(a); got != "alerted waiting via webhook, webhook" {
+		t.Errorf("alertStage = %q", got)
+	}
+
+	// Acknowledged: the ladder stops climbing.
+	a.acknowledge()
+	run(t0.Add(2 * time.Hour))
+	if len(posts) != 2 {
+		t.Errorf("acknowledged alert escalated: %v", posts)
+	}
+
+	// The condition clears and recurs: the ladder starts over.
+	a.Level = LevelActive
+	run(t0.Add(3 * time.Hour))
+	a.Level = LevelWaitingForHuman
+	run(t0.Add(4 * time.Hour))
+	if len(posts) != 3 {
+		t.Errorf("recurring condition should re-alert, got %d posts", len(posts))
+	}
+}
+
+func TestSendAlert_SlackUsesEscalationContacts(t *testing.T) {
+	var text string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		text = p["text"]
+	}))
+	defer srv.Close()
+
+	town := t.TempDir()
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.SlackWebhook = srv.URL
+	data, _ := json.Marshal(cfg)
+	path := config.EscalationConfigPath(town)
+	_ = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sendAlert(town, alert{notify: "slack", summary: "HIT LIMIT: gastown/polecats/Toast"}); err != nil {
+		t.Fatalf("sendAlert: %v", err)
+	}
+	if text != "🚨 HIT LIMIT: gastown/polecats/Toast" {
+		t.Errorf("slack text = %q", text)
+	}
+}
+
+func TestSendAlert_ErrorsHideSecrets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	a := &AgentLight{SessionName: "s", Level: LevelCold}
+	m := &Model{
+		agents:     []*AgentLight{a},
+		alertRules: parseAlertRules([]config.TopAlertRule{{Condition: "cold", Ladder: []config.TopAlertStep{{Notify: "webhook:" + srv.URL + "/T0/SECRET"}}}}),
+	}
+	msg := m.escalateAlerts(time.Now())().(alertSentMsg)
+	if len(msg.errs) != 1 || strings.Contains(msg.errs[0], "SECRET") || !strings.Contains(msg.errs[0], "403") {
+		t.Errorf("errs = %v", msg.errs)
+	}
+}
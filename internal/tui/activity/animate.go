@@ -0,0 +1,51 @@
+package activity
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Animation (the blinking lights and the header sparkle) runs on its own
+// tick rather than once per poll, so a longer --interval, the idle backoff
+// or a slow-frame backoff make the data less fresh without freezing the
+// lights. --no-animate holds everything still, for clean recordings.
+
+// blinkStep is how often the lights blink and the sparkle turns.
+const blinkStep = time.Second
+
+// animateMsg advances the animation one frame.
+type animateMsg struct{}
+
+// animateTick schedules the next animation frame.
+func animateTick() tea.Cmd {
+	return tea.Tick(blinkStep, func(time.Time) tea.Msg { return animateMsg{} })
+}
+
+// DisableAnimation holds the lights steady and the sparkle still (gt top
+// --no-animate).
+func (m *Model) DisableAnimation() {
+	m.noAnimate = true
+	m.blinkOn = true
+}
+
+// startAnimation returns the first animation tick, or nil when animation
+// is off.
+func (m *Model) startAnimation() tea.Cmd {
+	if m.noAnimate {
+		return nil
+	}
+	return animateTick()
+}
+
+// animate advances the blink and sparkle one frame. While frames are over
+// budget it holds the lights on instead, sparing a redraw of every line.
+func (m *Model) animate() tea.Cmd {
+	if m.frames.slow {
+		m.blinkOn = true
+	} else {
+		m.blinkOn = !m.blinkOn
+		m.tickNum++
+	}
+	return animateTick()
+}
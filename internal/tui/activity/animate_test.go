@@ -0,0 +1,41 @@
+package activity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnimation_IndependentOfPolling(t *testing.T) {
+	m := NewModel(time.Minute, t.TempDir())
+	m.blinkOn = false
+	m.Update(sessionsMsg{})
+	if m.blinkOn || m.tickNum != 0 {
+		t.Errorf("a poll blinked the lights: blinkOn=%v tickNum=%d", m.blinkOn, m.tickNum)
+	}
+	if cmd := m.startAnimation(); cmd == nil {
+		t.Fatal("no animation tick scheduled")
+	}
+	for i := 0; i < 3; i++ {
+		if cmd := m.animate(); cmd == nil {
+			t.Fatal("animation stopped ticking")
+		}
+	}
+	if !m.blinkOn || m.tickNum != 3 {
+		t.Errorf("after 3 frames: blinkOn=%v tickNum=%d, want on and 3", m.blinkOn, m.tickNum)
+	}
+}
+
+func TestDisableAnimation(t *testing.T) {
+	m := NewModel(time.Second, t.TempDir())
+	m.DisableAnimation()
+	if cmd := m.startAnimation(); cmd != nil {
+		t.Error("--no-animate still schedules animation frames")
+	}
+	if !m.lit(true) || !m.lit(false) {
+		t.Error("--no-animate lights aren't held on")
+	}
+	m.Update(sessionsMsg{})
+	if m.tickNum != 0 || !m.blinkOn {
+		t.Errorf("poll moved a still animation: blinkOn=%v tickNum=%d", m.blinkOn, m.tickNum)
+	}
+}
@@ -0,0 +1,87 @@
+package activity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// attentionStuckAfter is how long an agent read straight from tmux must be
+// idle to be listed as stuck; with gt top running its cold level decides.
+const attentionStuckAfter = 30 * time.Minute
+
+// Attention is an agent that needs someone to act on it, and why, for the
+// mayor's decision loop (gt agents --attention).
+type Attention struct {
+	AgentStatus
+	Reason string `json:"reason"`
+}
+
+// attentionRank orders the attention list: agents that can't work at all
+// first, since their work is the first to reassign.
+var attentionRank = map[string]int{
+	LevelDead.String():            0,
+	LevelHitLimit.String():        1,
+	LevelWaitingForHuman.String(): 2,
+}
+
+// Attention returns the agents needing attention: dead ones, ones at a
+// usage limit or waiting on a human, and stuck ones (cold, or idle for
+// attentionStuckAfter without a gt top). Muted agents and agents scheduled
+// off are left out, and an idle agent whose work waits in the merge queue
+// isn't stuck.
+func (s *Status) Attention(now time.Time) []Attention {
+	var out []Attention
+	for _, a := range s.Agents {
+		if a.Muted || a.ScheduledOff {
+			continue
+		}
+		if reason := a.attentionReason(now); reason != "" {
+			out = append(out, Attention{AgentStatus: a, Reason: reason})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		ri, ok := attentionRank[out[i].Level]
+		if !ok {
+			ri = len(attentionRank)
+		}
+		rj, ok := attentionRank[out[j].Level]
+		if !ok {
+			rj = len(attentionRank)
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return out[i].LastActivity.Before(out[j].LastActivity)
+	})
+	return out
+}
+
+// attentionReason says why the agent needs attention, or "" if it doesn't.
+func (a AgentStatus) attentionReason(now time.Time) string {
+	switch a.Level {
+	case LevelDead.String():
+		return "agent process exited"
+	case LevelHitLimit.String():
+		if a.LimitReset != "" {
+			return "usage limit, " + a.LimitReset
+		}
+		return "usage limit"
+	case LevelWaitingForHuman.String():
+		if a.WaitingReason != "" {
+			return "waiting for human: " + a.WaitingReason
+		}
+		return "waiting for human"
+	}
+
+	idle := now.Sub(a.LastActivity)
+	stuck := a.Level == LevelCold.String() || (a.Level == "" && !a.LastActivity.IsZero() && idle >= attentionStuckAfter)
+	if !stuck || a.MergeQueuePos > 0 {
+		return ""
+	}
+	reason := fmt.Sprintf("no output for %s", idle.Truncate(time.Minute))
+	if a.WorkBead != "" {
+		reason += " on " + a.WorkBead
+	}
+	return reason
+}
@@ -0,0 +1,39 @@
+package activity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatusAttention(t *testing.T) {
+	now := time.Now()
+	s := &Status{Agents: []AgentStatus{
+		{Session: "gt-gastown-busy", Level: "active", LastActivity: now},
+		{Session: "gt-gastown-stuck", Level: "cold", LastActivity: now.Add(-40 * time.Minute), WorkBead: "gt-abc"},
+		{Session: "gt-gastown-waiting", Level: "waiting", WaitingReason: "permission", LastActivity: now.Add(-time.Minute)},
+		{Session: "gt-gastown-limited", Level: "hit_limit", LimitReset: "resets 2pm", LastActivity: now.Add(-time.Hour)},
+		{Session: "gt-gastown-dead", Level: "dead", LastActivity: now.Add(-5 * time.Minute)},
+		{Session: "gt-gastown-muted", Level: "dead", Muted: true},
+		{Session: "gt-gastown-off", Level: "cold", ScheduledOff: true},
+		{Session: "gt-gastown-queued", Level: "cold", MergeQueuePos: 2, LastActivity: now.Add(-time.Hour)},
+		{Session: "gt-gastown-tmux", LastActivity: now.Add(-45 * time.Minute)}, // no gt top
+		{Session: "gt-gastown-tmuxbusy", LastActivity: now.Add(-5 * time.Minute)},
+	}}
+
+	got := s.Attention(now)
+	want := []struct{ session, reason string }{
+		{"gt-gastown-dead", "agent process exited"},
+		{"gt-gastown-limited", "usage limit, resets 2pm"},
+		{"gt-gastown-waiting", "waiting for human: permission"},
+		{"gt-gastown-tmux", "no output for 45m0s"},
+		{"gt-gastown-stuck", "no output for 40m0s on gt-abc"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Attention = %+v, want %d agents", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].Session != w.session || got[i].Reason != w.reason {
+			t.Errorf("Attention[%d] = %s %q, want %s %q", i, got[i].Session, got[i].Reason, w.session, w.reason)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package activity
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/ui"
+	"golang.org/x/term"
+)
+
+// Background: the palette's adaptive colors take their light or dark shade
+// from lipgloss, which asks termenv. termenv won't query a terminal under
+// tmux or screen and guesses dark, so gt top, nearly always run in tmux,
+// drew its dark palette on Solarized Light. gt top asks the terminal
+// itself (OSC 11; recent tmux answers for the terminal outside it), and
+// --light and --dark override the answer and are remembered for later
+// launches. In order: the flag, GT_THEME, the remembered choice, town
+// settings cli_theme, the terminal's answer, termenv's guess.
+
+const (
+	// backgroundFile remembers --light or --dark in the state dir. It's
+	// per user, not per town: it describes the user's terminal.
+	backgroundFile = "top-background"
+
+	// backgroundQueryTimeout bounds the wait for the terminal's answer.
+	backgroundQueryTimeout = 500 * time.Millisecond
+)
+
+// SetBackground picks the background the palette is drawn for: "light" or
+// "dark" force it and are remembered, "auto" forgets a remembered choice,
+// and "" uses one. Without a choice the terminal is asked, when gt top has
+// one.
+func SetBackground(mode string) error {
+	switch mode {
+	case "light", "dark":
+		if err := saveBackground(mode); err != nil {
+			return fmt.Errorf("remembering --%s: %w", mode, err)
+		}
+	case "auto":
+		if err := os.Remove(backgroundPath()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		mode = ""
+	case "":
+	default:
+		return fmt.Errorf("unknown background %q (want light, dark or auto)", mode)
+	}
+	_, envSet := os.LookupEnv("GT_THEME")
+	if dark, ok := chooseBackground(mode, ui.GetThemeMode(), envSet, savedBackground(), backgroundQuery); ok {
+		lipgloss.SetHasDarkBackground(dark)
+	}
+	return nil
+}
+
+// chooseBackground reports whether the background is dark, and false for
+// ok when nothing but termenv's guess is left. theme is GT_THEME or, when
+// the variable isn't set, cli_theme.
+func chooseBackground(flag string, theme ui.ThemeMode, themeFromEnv bool, saved string, query func(time.Duration) (bool, bool)) (dark, ok bool) {
+	explicit := theme == ui.ThemeModeLight || theme == ui.ThemeModeDark
+	switch {
+	case flag != "":
+		return flag == "dark", true
+	case explicit && themeFromEnv:
+		return theme == ui.ThemeModeDark, true
+	case saved != "":
+		return saved == "dark", true
+	case explicit:
+		return theme == ui.ThemeModeDark, true
+	}
+	return query(backgroundQueryTimeout)
+}
+
+// backgroundPath returns the path --light and --dark are remembered at.
+func backgroundPath() string {
+	return filepath.Join(state.StateDir(), backgroundFile)
+}
+
+// saveBackground remembers a background for later launches.
+func saveBackground(mode string) error {
+	path := backgroundPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(mode+"\n"), 0644)
+}
+
+// savedBackground returns the remembered background, or "".
+func savedBackground() string {
+	data, err := os.ReadFile(backgroundPath())
+	if err != nil {
+		return ""
+	}
+	switch mode := strings.TrimSpace(string(data)); mode {
+	case "light", "dark":
+		return mode
+	}
+	return ""
+}
+
+// backgroundQuery asks the terminal; tests replace it.
+var backgroundQuery = queryBackground
+
+// cursorReport matches the terminal's answer to a cursor position query.
+var cursorReport = regexp.MustCompile(`\x1b\[\d+;\d+R`)
+
+// queryBackground asks the terminal for its background color and reports
+// whether it's dark; ok is false when there's no terminal or it doesn't
+// say. A cursor position query follows the OSC 11 one: every terminal
+// answers that, so one that ignores OSC 11 doesn't cost the whole timeout.
+func queryBackground(timeout time.Duration) (dark, ok bool) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false, false
+	}
+	defer tty.Close()
+
+	// Fd would put the tty in blocking mode and defeat the read deadline.
+	rc, err := tty.SyscallConn()
+	if err != nil {
+		return false, false
+	}
+	fd := -1
+	_ = rc.Control(func(f uintptr) { fd = int(f) })
+	if fd < 0 || !term.IsTerminal(fd) {
+		return false, false
+	}
+	saved, err := term.MakeRaw(fd)
+	if err != nil {
+		return false, false
+	}
+	defer func() { _ = term.Restore(fd, saved) }()
+	if err := tty.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return false, false
+	}
+	if _, err := tty.WriteString("\x1b]11;?\x1b\\\x1b[6n"); err != nil {
+		return false, false
+	}
+
+	var reply []byte
+	buf := make([]byte, 256)
+	for !cursorReport.Match(reply) {
+		n, err := tty.Read(buf)
+		reply = append(reply, buf[:n]...)
+		if err != nil {
+			break
+		}
+	}
+	return parseBackgroundReply(string(reply))
+}
+
+// backgroundReply matches an OSC 11 answer: "\x1b]11;rgb:fdfd/f6f6/e3e3"
+// then BEL or ST. Components are 1 to 4 hex digits.
+var backgroundReply = regexp.MustCompile(`\x1b\]11;rgba?:([0-9a-fA-F]{1,4})/([0-9a-fA-F]{1,4})/([0-9a-fA-F]{1,4})`)
+
+// parseBackgroundReply reports whether the color in an OSC 11 answer is
+// dark, by its relative luminance; ok is false when there's no answer.
+func parseBackgroundReply(reply string) (dark, ok bool) {
+	m := backgroundReply.FindStringSubmatch(reply)
+	if m == nil {
+		return false, false
+	}
+	var rgb [3]float64
+	for i, hex := range m[1:] {
+		v, err := strconv.ParseUint(hex, 16, 16)
+		if err != nil {
+			return false, false
+		}
+		rgb[i] = float64(v) / float64(uint64(1)<<(4*len(hex))-1)
+	}
+	luminance := 0.2126*rgb[0] + 0.7152*rgb[1] + 0.0722*rgb[2]
+	return luminance < 0.5, true
+}
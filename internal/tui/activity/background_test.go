@@ -0,0 +1,101 @@
+package activity
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/ui"
+)
+
+func TestParseBackgroundReply(t *testing.T) {
+	tests := []struct {
+		reply    string
+		dark, ok bool
+	}{
+		{"\x1b]11;rgb:fdfd/f6f6/e3e3\x1b\\\x1b[12;1R", false, true}, // Solarized Light
+		{"\x1b]11;rgb:0000/2b2b/3636\a\x1b[12;1R", true, true},      // Solarized Dark
+		{"\x1b]11;rgb:ff/ff/ff\a", false, true},
+		{"\x1b]11;rgba:1e1e/1e1e/2e2e/ffff\x1b\\", true, true},
+		{"\x1b[12;1R", false, false}, // OSC 11 ignored, only the cursor report
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		dark, ok := parseBackgroundReply(tt.reply)
+		if dark != tt.dark || ok != tt.ok {
+			t.Errorf("parseBackgroundReply(%q) = %v, %v; want %v, %v", tt.reply, dark, ok, tt.dark, tt.ok)
+		}
+	}
+}
+
+func TestChooseBackground(t *testing.T) {
+	answers := func(dark, ok bool) func(time.Duration) (bool, bool) {
+		return func(time.Duration) (bool, bool) { return dark, ok }
+	}
+	tests := []struct {
+		name         string
+		flag         string
+		theme        ui.ThemeMode
+		fromEnv      bool
+		saved        string
+		query        func(time.Duration) (bool, bool)
+		wantDark, ok bool
+	}{
+		{"flag over everything", "light", ui.ThemeModeDark, true, "dark", answers(true, true), false, true},
+		{"GT_THEME over saved", "", ui.ThemeModeLight, true, "dark", answers(true, true), false, true},
+		{"saved over cli_theme", "", ui.ThemeModeLight, false, "dark", answers(false, true), true, true},
+		{"cli_theme over the terminal", "", ui.ThemeModeDark, false, "", answers(false, true), true, true},
+		{"the terminal's answer", "", ui.ThemeModeAuto, false, "", answers(false, true), false, true},
+		{"no answer leaves termenv's guess", "", ui.ThemeModeAuto, false, "", answers(false, false), false, false},
+	}
+	for _, tt := range tests {
+		dark, ok := chooseBackground(tt.flag, tt.theme, tt.fromEnv, tt.saved, tt.query)
+		if dark != tt.wantDark || ok != tt.ok {
+			t.Errorf("%s: got %v, %v; want %v, %v", tt.name, dark, ok, tt.wantDark, tt.ok)
+		}
+	}
+}
+
+func TestSetBackground_Remembers(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	wasDark := lipgloss.HasDarkBackground()
+	t.Cleanup(func() { lipgloss.SetHasDarkBackground(wasDark) })
+	query := backgroundQuery
+	t.Cleanup(func() { backgroundQuery = query })
+	asked := 0
+	backgroundQuery = func(time.Duration) (bool, bool) {
+		asked++
+		return true, true
+	}
+	if theme := ui.GetThemeMode(); theme == ui.ThemeModeLight || theme == ui.ThemeModeDark {
+		t.Skipf("GT_THEME or cli_theme is set (%s)", theme)
+	}
+	if _, ok := os.LookupEnv("GT_THEME"); ok {
+		t.Skip("GT_THEME is set")
+	}
+
+	if err := SetBackground("light"); err != nil {
+		t.Fatal(err)
+	}
+	if lipgloss.HasDarkBackground() || savedBackground() != "light" {
+		t.Fatalf("--light: dark %v, saved %q", lipgloss.HasDarkBackground(), savedBackground())
+	}
+	if err := SetBackground(""); err != nil {
+		t.Fatal(err)
+	}
+	if lipgloss.HasDarkBackground() || asked != 0 {
+		t.Errorf("next launch: dark %v, asked the terminal %d times; want the remembered light", lipgloss.HasDarkBackground(), asked)
+	}
+
+	if err := SetBackground("auto"); err != nil {
+		t.Fatal(err)
+	}
+	if !lipgloss.HasDarkBackground() || asked != 1 || savedBackground() != "" {
+		t.Errorf("auto: dark %v, asked %d, saved %q; want the terminal's dark and nothing saved", lipgloss.HasDarkBackground(), asked, savedBackground())
+	}
+
+	if err := SetBackground("solarized"); err == nil {
+		t.Error("SetBackground accepted an unknown background")
+	}
+}
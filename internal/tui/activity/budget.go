@@ -0,0 +1,114 @@
+package activity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// frameBudget is how long one poll+render cycle may take before gt top
+// backs off: capturing every pane through an overloaded tmux server can take
+// seconds, and polling at the usual rate then keeps tmux (and the host) busy.
+const frameBudget = time.Second
+
+// maxPollBackoff caps how far a slow host stretches the poll interval.
+const maxPollBackoff = 8
+
+// frameCostWeight is the weight of the newest cycle in the smoothed cost, so
+// one slow capture doesn't trip the budget but a sustained slowdown does.
+const frameCostWeight = 0.3
+
+// frameStats tracks what poll+render cycles cost and how far polling has
+// backed off because of it.
+type frameStats struct {
+	cost    time.Duration // smoothed cost of a poll+render cycle
+	last    time.Duration // cost of the latest cycle
+	render  time.Duration // duration of the latest render
+	backoff int           // poll interval multiplier; 0 or 1 when within budget
+	slow    bool          // over budget: blink frames skipped, polling backed off
+}
+
+// recordFrame folds one cycle's cost into the budget. Over budget, the poll
+// interval doubles each cycle up to maxPollBackoff; the UI recovers once the
+// smoothed cost falls under half the budget, so it doesn't flap at the edge.
+func (m *Model) recordFrame(cost time.Duration) {
+	f := &m.frames
+	f.last = cost
+	if f.cost == 0 {
+		f.cost = cost
+	} else {
+		f.cost = time.Duration(frameCostWeight*float64(cost) + (1-frameCostWeight)*float64(f.cost))
+	}
+
+	switch {
+	case f.cost > frameBudget:
+		f.slow = true
+		f.backoff = max(f.backoff*2, 2)
+		if f.backoff > maxPollBackoff {
+			f.backoff = maxPollBackoff
+		}
+	case f.slow && f.cost < frameBudget/2:
+		f.backoff /= 2
+		if f.backoff <= 1 {
+			f.backoff = 0
+			f.slow = false
+		}
+	}
+}
+
+// pollBackoff returns the factor the poll interval is stretched by.
+func (m *Model) pollBackoff() time.Duration {
+	if m.frames.backoff < 1 {
+		return 1
+	}
+	return time.Duration(m.frames.backoff)
+}
+
+// noteTmuxStall records whether the poll's tmux listing timed out, and
+// reports whether it did. A stalled poll leaves the agents as they were:
+// clearing them would look like every session died.
+func (m *Model) noteTmuxStall(err error, now time.Time) bool {
+	if !errors.Is(err, tmux.ErrCommandTimeout) {
+		m.tmuxStall = nil
+		return false
+	}
+	if m.tmuxStall == nil {
+		m.tmuxStallSince = now
+	}
+	m.tmuxStall = err
+	return true
+}
+
+// renderTmuxStallBanner renders the warning shown while tmux isn't
+// answering, or "".
+func (m *Model) renderTmuxStallBanner() string {
+	if m.tmuxStall == nil {
+		return ""
+	}
+	style := degradedBannerStyle
+	if m.width > 4 {
+		style = style.MaxWidth(m.width - 4)
+	}
+	msg := "⚠ tmux not responding: " + strings.TrimPrefix(m.tmuxStall.Error(), tmux.ErrCommandTimeout.Error()+": ")
+	if d := formatElapsed(time.Since(m.tmuxStallSince)); d != "" {
+		msg += " · for " + d
+	}
+	return style.Render(msg + " · showing last known state")
+}
+
+// renderSlowBanner renders the slow-tmux warning shown under the header, or
+// "" while cycles fit the budget.
+func (m *Model) renderSlowBanner() string {
+	if !m.frames.slow {
+		return ""
+	}
+	style := degradedBannerStyle
+	if m.width > 4 {
+		style = style.MaxWidth(m.width - 4) // one line, so hover rows stay aligned
+	}
+	return style.Render(fmt.Sprintf("⚠ degraded (slow tmux): poll+render %s · polling every %s · blink off",
+		m.frames.cost.Round(10*time.Millisecond), m.pollInterval*m.pollBackoff()))
+}
@@ -0,0 +1,112 @@
+package activity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func TestRecordFrame_BacksOffAndRecovers(t *testing.T) {
+	m := &Model{pollInterval: 3 * time.Second}
+
+	m.recordFrame(200 * time.Millisecond)
+	if m.frames.slow || m.nextPollDelay() != 3*time.Second {
+		t.Fatalf("fast cycle: slow=%v delay=%s, want within budget at 3s", m.frames.slow, m.nextPollDelay())
+	}
+
+	// One slow capture is smoothed away.
+	m.recordFrame(2 * time.Second)
+	if m.frames.slow {
+		t.Errorf("single slow cycle tripped the budget (cost %s)", m.frames.cost)
+	}
+
+	for i := 0; i < 10; i++ {
+		m.recordFrame(3 * time.Second)
+	}
+	if !m.frames.slow {
+		t.Fatalf("sustained slow cycles did not trip the budget (cost %s)", m.frames.cost)
+	}
+	if got, want := m.nextPollDelay(), 3*time.Second*maxPollBackoff; got != want {
+		t.Errorf("backed-off delay = %s, want %s", got, want)
+	}
+
+	// Just under budget holds the backoff (hysteresis).
+	for i := 0; i < 20; i++ {
+		m.recordFrame(800 * time.Millisecond)
+	}
+	if !m.frames.slow {
+		t.Errorf("recovered at cost %s, want to hold until under half the budget", m.frames.cost)
+	}
+
+	for i := 0; i < 20; i++ {
+		m.recordFrame(100 * time.Millisecond)
+	}
+	if m.frames.slow || m.nextPollDelay() != 3*time.Second {
+		t.Errorf("after recovery: slow=%v delay=%s, want 3s", m.frames.slow, m.nextPollDelay())
+	}
+}
+
+func TestAnimate_SkipsBlinkWhenSlow(t *testing.T) {
+	m := NewModel(time.Second, t.TempDir())
+	m.frames = frameStats{slow: true, backoff: 2, cost: 3 * time.Second}
+	m.blinkOn = false
+	m.Update(animateMsg{})
+	if !m.blinkOn || m.tickNum != 0 {
+		t.Errorf("slow frame: blinkOn=%v tickNum=%d, want lights held on and no tick", m.blinkOn, m.tickNum)
+	}
+
+	m.frames = frameStats{}
+	m.Update(animateMsg{})
+	if m.blinkOn || m.tickNum != 1 {
+		t.Errorf("normal frame: blinkOn=%v tickNum=%d, want toggled and ticked", m.blinkOn, m.tickNum)
+	}
+}
+
+func TestHandleWake_IgnoredWhenSlow(t *testing.T) {
+	m := &Model{pollInterval: time.Second}
+	m.frames.slow = true
+	seq := m.pollSeq
+	m.handleWake(wakeMsg{signalled: true})
+	if m.pollSeq != seq || m.wakePending {
+		t.Error("wake scheduled a poll while over the frame budget")
+	}
+}
+
+func TestRenderSlowBanner(t *testing.T) {
+	m := &Model{width: 200, pollInterval: 3 * time.Second}
+	if got := m.renderSlowBanner(); got != "" {
+		t.Errorf("banner within budget = %q, want empty", got)
+	}
+	m.frames = frameStats{slow: true, backoff: 4, cost: 1500 * time.Millisecond}
+	got := m.renderSlowBanner()
+	for _, want := range []string{"degraded (slow tmux)", "1.5s", "every 12s"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("banner missing %q: %q", want, got)
+		}
+	}
+}
+
+func TestSessionsMsg_TmuxTimeoutKeepsAgents(t *testing.T) {
+	m := &Model{pollInterval: 3 * time.Second, width: 200}
+	m.updateAgents([]sessionInfo{{name: "hq-mayor", activity: time.Now().Unix()}})
+
+	stall := fmt.Errorf("%w: tmux list-sessions did not return within 2s (server or session wedged?)", tmux.ErrCommandTimeout)
+	m.Update(sessionsMsg{err: stall})
+	if len(m.agents) != 1 {
+		t.Fatalf("agents = %d after a tmux timeout, want the last known one kept", len(m.agents))
+	}
+	banner := m.renderTmuxStallBanner()
+	if !strings.Contains(banner, "tmux not responding: tmux list-sessions did not return") || !strings.Contains(banner, "showing last known state") {
+		t.Errorf("banner = %q", banner)
+	}
+
+	// tmux answering again, even with an error (no server), clears the stall.
+	m.Update(sessionsMsg{err: errors.New("no server running")})
+	if m.renderTmuxStallBanner() != "" || len(m.agents) != 0 {
+		t.Errorf("after tmux answered: banner=%q agents=%d", m.renderTmuxStallBanner(), len(m.agents))
+	}
+}
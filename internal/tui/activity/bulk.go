@@ -0,0 +1,295 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// bulkNudgeMessage is queued for every agent by "nudge all".
+const bulkNudgeMessage = "Status check from gt top: how's progress? Need help?"
+
+// agentFilter narrows the panels to a subset of agents (gt top --rig, --role,
+// --level, --convoy). Bulk actions apply to exactly the agents it matches.
+type agentFilter struct {
+	rig    string
+	role   string
+	levels []string // ActivityLevel names; empty matches any level
+	convoy string   // convoy or epic ID the agent's work belongs to
+}
+
+// active reports whether any filter is set.
+func (f agentFilter) active() bool {
+	return f.rig != "" || f.role != "" || len(f.levels) > 0 || f.convoy != ""
+}
+
+// matches reports whether the agent passes the filter.
+func (f agentFilter) matches(a *AgentLight) bool {
+	if f.rig != "" && a.Rig != f.rig {
+		return false
+	}
+	if f.convoy != "" && a.ConvoyID != f.convoy && a.EpicID != f.convoy {
+		return false
+	}
+	if f.role != "" && a.Role != f.role {
+		return false
+	}
+	if len(f.levels) == 0 {
+		return true
+	}
+	level := a.Level.String()
+	for _, l := range f.levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// describe names n matching agents for prompts, e.g. "7 cold crew in greenplace".
+func (f agentFilter) describe(n int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d", n)
+	if len(f.levels) > 0 {
+		b.WriteString(" " + strings.Join(f.levels, "/"))
+	}
+	switch {
+	case f.role == "":
+		b.WriteString(" agents")
+	case n != 1 && f.role != "crew":
+		b.WriteString(" " + f.role + "s")
+	default:
+		b.WriteString(" " + f.role)
+	}
+	if f.rig != "" {
+		b.WriteString(" in " + f.rig)
+	}
+	if f.convoy != "" {
+		b.WriteString(" on " + f.convoy)
+	}
+	return b.String()
+}
+
+// String renders the filter for the filter line, e.g. "rig=greenplace level=cold".
+func (f agentFilter) String() string {
+	var parts []string
+	if f.rig != "" {
+		parts = append(parts, "rig="+f.rig)
+	}
+	if f.role != "" {
+		parts = append(parts, "role="+f.role)
+	}
+	if len(f.levels) > 0 {
+		parts = append(parts, "level="+strings.Join(f.levels, ","))
+	}
+	if f.convoy != "" {
+		parts = append(parts, "convoy="+f.convoy)
+	}
+	return strings.Join(parts, " ")
+}
+
+// SetAgentFilter limits the panels to agents in rig, with role, at one of
+// the comma-separated levels (e.g. "cold,cool"). Empty arguments match all.
+func (m *Model) SetAgentFilter(rig, role, levels string) error {
+	f := agentFilter{rig: rig, role: role}
+	for _, l := range strings.Split(levels, ",") {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+		if !isLevelName(l) {
+			return fmt.Errorf("unknown level %q (want %s)", l, strings.Join(LevelNames(), ", "))
+		}
+		f.levels = append(f.levels, l)
+	}
+	f.convoy = m.view.convoy
+	m.view = f
+	return nil
+}
+
+// SetConvoyFilter limits the panels to agents whose hooked bead belongs to
+// the convoy or epic with this ID. Empty matches all.
+func (m *Model) SetConvoyFilter(id string) {
+	m.view.convoy = id
+}
+
+func isLevelName(name string) bool {
+	for l := LevelActive; l <= LevelDead; l++ {
+		if l.String() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// filteredAgents returns the agents the filter matches, in rig display order.
+func (m *Model) filteredAgents() []*AgentLight {
+	var out []*AgentLight
+	for _, rig := range m.rigs {
+		out = append(out, m.agentsForRig(rig)...)
+	}
+	return out
+}
+
+// bulkAction is a bulk operation awaiting confirmation.
+type bulkAction struct {
+	verb     string        // "nudge", "acknowledge", "kill", "restart" (hovered agent only), "start" or "stop"
+	agents   []*AgentLight // snapshot of the filtered set when the key was pressed
+	sessions []string
+	commands map[string][]string // start and stop: gt arguments per session (or control screen role)
+	prompt   string              // e.g. "nudge all 7 cold crew in greenplace"
+}
+
+// bulkDoneMsg reports a finished background bulk action.
+type bulkDoneMsg struct {
+	verb   string
+	done   int
+	total  int
+	errMsg string // first failure, if any
+}
+
+// prepareBulk snapshots the filtered agents and asks for confirmation.
+// Bulk actions are only offered while a filter is active, so "all" never
+// silently means the whole town.
+func (m *Model) prepareBulk(verb string) {
+	if !m.view.active() {
+		m.flashMessage = "set a filter (--rig, --role, --level, --convoy) to use bulk actions"
+		m.flashTime = time.Now()
+		return
+	}
+	agents := m.filteredAgents()
+	if len(agents) == 0 {
+		m.flashMessage = "no agents match the filter"
+		m.flashTime = time.Now()
+		return
+	}
+	b := &bulkAction{verb: verb, agents: agents, prompt: verb + " all " + m.view.describe(len(agents))}
+	for _, a := range agents {
+		b.sessions = append(b.sessions, a.SessionName)
+	}
+	m.pendingBulk = b
+}
+
+// handleBulkKey confirms ("y") or cancels (any other key) a pending action.
+func (m *Model) handleBulkKey(key string) tea.Cmd {
+	b := m.pendingBulk
+	m.pendingBulk = nil
+	if key != "y" && key != "Y" {
+		m.flashMessage = "cancelled: " + b.prompt
+		m.flashTime = time.Now()
+		return nil
+	}
+
+	switch b.verb {
+	case "acknowledge":
+		for _, a := range b.agents {
+			a.acknowledge()
+		}
+		m.flashMessage = fmt.Sprintf("acknowledged %d", len(b.agents))
+		m.flashTime = time.Now()
+		return nil
+	case "nudge":
+		if m.townRoot == "" {
+			m.flashMessage = "nudge all needs a town (tmux-only mode)"
+			m.flashTime = time.Now()
+			return nil
+		}
+		for _, s := range b.sessions {
+			m.touchedByHuman(s)
+		}
+		townRoot := m.townRoot
+		return runBulk(b, func(session string) error {
+			return nudge.Enqueue(townRoot, session, nudge.QueuedNudge{Sender: "gt-top", Message: bulkNudgeMessage})
+		})
+	case "kill":
+		t := tmux.NewTmux()
+		return runBulk(b, b.viaDocker("stop", b.viaKubernetes(m.kubernetes, false, t.KillSessionWithProcesses)))
+	case "restart":
+		return runBulk(b, b.viaDocker("restart", b.viaKubernetes(m.kubernetes, true, restartSession(m.townRoot))))
+	case "start", "stop":
+		return runBulk(b, runCommands(m.townRoot, b.commands))
+	}
+	return nil
+}
+
+// runBulk applies fn to each session in the background.
+func runBulk(b *bulkAction, fn func(session string) error) tea.Cmd {
+	return func() tea.Msg {
+		msg := bulkDoneMsg{verb: b.verb, total: len(b.sessions)}
+		for _, s := range b.sessions {
+			if err := fn(s); err != nil {
+				if msg.errMsg == "" {
+					msg.errMsg = fmt.Sprintf("%s: %v", s, err)
+				}
+				continue
+			}
+			msg.done++
+		}
+		return msg
+	}
+}
+
+// handleBulkDone flashes the outcome and re-polls so kills show at once.
+func (m *Model) handleBulkDone(msg bulkDoneMsg) tea.Cmd {
+	past := map[string]string{"nudge": "nudged", "kill": "killed", "restart": "restarted", "start": "started", "stop": "stopped"}[msg.verb]
+	m.flashMessage = fmt.Sprintf("%s %d/%d", past, msg.done, msg.total)
+	if msg.errMsg != "" {
+		m.flashMessage += " · " + msg.errMsg
+	}
+	m.flashTime = time.Now()
+	if m.polling {
+		m.wakePending = true
+		return nil
+	}
+	return m.pollTickAfter(0)
+}
+
+// acknowledge clears the alert badges an operator has seen: tool failures
+// and retry loops. Both come back if the agent fails or loops again. It also
+// stops any alert ladders in progress (see alerts.go).
+func (a *AgentLight) acknowledge() {
+	a.acknowledgeAlerts()
+	a.ToolErrorCount = 0
+	a.LastToolError = ""
+	a.Looping = false
+	a.LoopTool = ""
+	a.LoopCount = 0
+	a.toolStarts = nil
+}
+
+// acknowledgeHovered acknowledges the agent under the mouse.
+func (m *Model) acknowledgeHovered() {
+	a := m.hoveredAgent
+	if a == nil {
+		return
+	}
+	a.acknowledge()
+	m.flashMessage = "acknowledged " + a.SessionName
+	m.flashTime = time.Now()
+}
+
+// bulkPromptStyle highlights a pending bulk confirmation.
+var bulkPromptStyle = lipgloss.NewStyle().
+	Foreground(colorRateLimited).
+	Bold(true)
+
+// renderBulkPrompt renders the confirmation line for a pending action.
+func (m *Model) renderBulkPrompt() string {
+	return "  " + bulkPromptStyle.Render(m.pendingBulk.prompt+"?") +
+		helpStyle.Render("  y: confirm  •  any other key: cancel")
+}
+
+// renderFilterLine renders the active filter, the match count, and the bulk
+// keys, or "" with no filter.
+func (m *Model) renderFilterLine() string {
+	if !m.view.active() {
+		return ""
+	}
+	return helpStyle.Render(fmt.Sprintf("  filter: %s · %d of %d  •  N: nudge all  •  A: ack all  •  K: kill all",
+		m.view, len(m.filteredAgents()), m.totalAgents))
+}
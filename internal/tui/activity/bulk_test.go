@@ -0,0 +1,123 @@
+package activity
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// greenplaceCrew is two cold crew members (the first erroring and looping)
+// and a busy one.
+func greenplaceCrew() []*AgentLight {
+	return []*AgentLight{
+		{SessionName: "gp-crew-max", Name: "max", Rig: "greenplace", Role: "crew", Level: LevelCold, ToolErrorCount: 2, Looping: true},
+		{SessionName: "gp-crew-joe", Name: "joe", Rig: "greenplace", Role: "crew", Level: LevelCold},
+		{SessionName: "gp-crew-ann", Name: "ann", Rig: "greenplace", Role: "crew", Level: LevelActive},
+	}
+}
+
+func TestSetAgentFilter(t *testing.T) {
+	m := testModel(greenplaceCrew(), modelTown(t.TempDir()))
+	cold, busy := m.agents[0], m.agents[2]
+	if err := m.SetAgentFilter("greenplace", "crew", "cold, cool"); err != nil {
+		t.Fatalf("SetAgentFilter: %v", err)
+	}
+	if !m.view.matches(cold) || m.view.matches(busy) {
+		t.Error("filter should match cold crew only")
+	}
+	if got := m.view.describe(7); got != "7 cold/cool crew in greenplace" {
+		t.Errorf("describe = %q", got)
+	}
+	if got := (agentFilter{role: "polecat"}).describe(2); got != "2 polecats" {
+		t.Errorf("describe = %q", got)
+	}
+	if err := m.SetAgentFilter("", "", "frozen"); err == nil {
+		t.Error("unknown level should be rejected")
+	}
+}
+
+func TestBulk_RequiresFilter(t *testing.T) {
+	m := testModel(greenplaceCrew(), modelTown(t.TempDir()))
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")})
+	if m.pendingBulk != nil {
+		t.Fatal("bulk actions must not apply to the whole town without a filter")
+	}
+}
+
+func TestBulk_ConfirmAcknowledge(t *testing.T) {
+	m := testModel(greenplaceCrew(), modelTown(t.TempDir()))
+	cold, cold2, busy := m.agents[0], m.agents[1], m.agents[2]
+	_ = m.SetAgentFilter("greenplace", "crew", "cold")
+	busy.ToolErrorCount = 1
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	if m.pendingBulk == nil || m.pendingBulk.prompt != "acknowledge all 2 cold crew in greenplace" {
+		t.Fatalf("pending = %+v", m.pendingBulk)
+	}
+	if out := m.render(); !strings.Contains(out, "acknowledge all 2 cold crew in greenplace?") {
+		t.Error("confirmation prompt not rendered")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if m.pendingBulk != nil {
+		t.Error("confirmation should clear the pending action")
+	}
+	if cold.ToolErrorCount != 0 || cold.Looping || cold2.ToolErrorCount != 0 {
+		t.Error("filtered agents should be acknowledged")
+	}
+	if busy.ToolErrorCount != 1 {
+		t.Error("agents outside the filter must be untouched")
+	}
+}
+
+func TestBulk_CancelOnOtherKey(t *testing.T) {
+	m := testModel(greenplaceCrew(), modelTown(t.TempDir()))
+	cold := m.agents[0]
+	_ = m.SetAgentFilter("", "", "cold")
+	m.prepareBulk("acknowledge")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if cmd != nil || m.pendingBulk != nil || cold.ToolErrorCount == 0 {
+		t.Error("any key but y should cancel without acting (and without quitting)")
+	}
+}
+
+func TestBulk_NudgeQueuesForFilteredAgents(t *testing.T) {
+	m := testModel(greenplaceCrew(), modelTown(t.TempDir()))
+	_ = m.SetAgentFilter("greenplace", "", "cold")
+	m.prepareBulk("nudge")
+	cmd := m.handleBulkKey("y")
+	if cmd == nil {
+		t.Fatal("nudge should run in the background")
+	}
+	msg := cmd().(bulkDoneMsg)
+	if msg.done != 2 || msg.total != 2 {
+		t.Fatalf("bulk result = %+v", msg)
+	}
+	for _, s := range []string{"gp-crew-max", "gp-crew-joe"} {
+		entries, _ := os.ReadDir(filepath.Join(m.townRoot, ".runtime", "nudge_queue", s))
+		if len(entries) != 1 {
+			t.Errorf("%s: %d queued nudges, want 1", s, len(entries))
+		}
+	}
+	m.handleBulkDone(msg)
+	if m.activeFlash() != "nudged 2/2" {
+		t.Errorf("flash = %q", m.activeFlash())
+	}
+}
+
+func TestRender_FilterHidesAgents(t *testing.T) {
+	m := testModel(greenplaceCrew(), modelTown(t.TempDir()))
+	cold, busy := m.agents[0], m.agents[2]
+	busy.renderY = 5 // from an earlier unfiltered frame
+	_ = m.SetAgentFilter("", "", "cold")
+	out := m.render()
+	if !strings.Contains(out, "filter: level=cold · 2 of 3") {
+		t.Error("filter line missing")
+	}
+	if busy.renderY != 0 || cold.renderY == 0 {
+		t.Errorf("hidden agent must not stay hoverable (busy y=%d, cold y=%d)", busy.renderY, cold.renderY)
+	}
+}
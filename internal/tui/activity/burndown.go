@@ -0,0 +1,314 @@
+package activity
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// burndownPollInterval is how often the burn-down re-reads its convoy and
+// the events file. Convoys move over hours, so this is slower than beads.
+const burndownPollInterval = 30 * time.Second
+
+// burndownRows is the chart height inside the panel: open, closed, axis.
+const burndownRows = 3
+
+// sparkBlocks are the sparkline levels, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// burndown is the selected convoy or epic and when each of its beads was
+// opened and closed. Beads without a bead_created event count as open from
+// the start; closed beads without a bead_closed event as closed now.
+type burndown struct {
+	group  *workGroup
+	beads  []string             // IDs of the tracked beads or children
+	start  time.Time            // convoy/epic creation, else its first bead
+	opened map[string]time.Time // bead ID -> bead_created
+	closed map[string]time.Time // bead ID -> bead_closed, for beads closed now
+	err    string               // why the convoy couldn't be read, if it couldn't
+}
+
+// SetBurndown shows a burn-down panel for a convoy or epic from the start
+// (gt top --burndown). The b key toggles it for the hovered agent's work.
+func (m *Model) SetBurndown(id string) {
+	m.burndownID = id
+	m.burndown = nil
+	m.lastBurndownPoll = time.Time{}
+}
+
+// toggleBurndownHovered shows the burn-down for the hovered agent's convoy or
+// epic, or hides the panel when it already shows it (or nothing is hovered).
+func (m *Model) toggleBurndownHovered() {
+	id := ""
+	if a := m.hoveredAgent; a != nil {
+		id = a.groupID()
+		if id == "" {
+			m.flashMessage = a.Name + " isn't working on a convoy or epic"
+			m.flashTime = time.Now()
+			return
+		}
+	}
+	if id == m.burndownID {
+		id = ""
+	}
+	m.SetBurndown(id)
+	if id == "" {
+		m.flashMessage = "burn-down hidden"
+	} else {
+		m.flashMessage = "burn-down for " + id + " (b: hide)"
+		m.pollBurndown(time.Now())
+	}
+	m.flashTime = time.Now()
+}
+
+// pollBurndown re-reads the selected convoy or epic and its beads' history
+// (slower cadence, guarded internally).
+func (m *Model) pollBurndown(now time.Time) {
+	if m.burndownID == "" || m.townRoot == "" || now.Sub(m.lastBurndownPoll) < burndownPollInterval {
+		return
+	}
+	m.lastBurndownPoll = now
+	if m.rigBeadsDirs == nil {
+		m.rigBeadsDirs = m.discoverRigBeadsDirs()
+	}
+	m.burndown = m.readBurndown(m.burndownID)
+}
+
+// readBurndown reads a convoy from the town beads, or an epic from whichever
+// rig's beads hold it, and its beads' history from the events file.
+func (m *Model) readBurndown(id string) *burndown {
+	issue := m.findGroupIssue(id)
+	if issue == nil {
+		return &burndown{group: &workGroup{id: id, kind: "convoy"}, err: "not found in any rig's beads"}
+	}
+	kind := "convoy"
+	if issue.Type == "epic" {
+		kind = "epic"
+	}
+	bd := &burndown{
+		group:  newWorkGroup(kind, issue),
+		opened: make(map[string]time.Time),
+		closed: make(map[string]time.Time),
+	}
+	members := make(map[string]bool)
+	for _, d := range groupMembers(kind, issue) {
+		members[d.ID] = true
+		bd.beads = append(bd.beads, d.ID)
+		if beads.IssueStatus(d.Status).IsTerminal() {
+			bd.closed[d.ID] = time.Time{}
+		}
+	}
+	readBeadHistory(filepath.Join(m.townRoot, events.EventsFile), m.verify, members, bd)
+	bd.start, _ = time.Parse(time.RFC3339, issue.CreatedAt)
+	for _, t := range bd.opened {
+		if bd.start.IsZero() || t.Before(bd.start) {
+			bd.start = t
+		}
+	}
+	return bd
+}
+
+// findGroupIssue reads a convoy or epic bead, trying the town beads first.
+func (m *Model) findGroupIssue(id string) *beads.Issue {
+	dirs := make([]string, 0, len(m.rigBeadsDirs))
+	if dir, ok := m.rigBeadsDirs["hq"]; ok {
+		dirs = append(dirs, dir)
+	}
+	for rig, dir := range m.rigBeadsDirs {
+		if rig != "hq" {
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, dir := range dirs {
+		issues, _ := beads.New(dir).ShowMultiple([]string{id})
+		if issue := issues[id]; issue != nil {
+			return issue
+		}
+	}
+	return nil
+}
+
+// readBeadHistory scans the whole events file for the members' bead_created
+// and bead_closed events. A bead closed again after a reopen keeps its last
+// close; beads that aren't closed now drop theirs.
+func readBeadHistory(path string, verify *events.Verifier, members map[string]bool, bd *burndown) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var dedup events.Dedup
+	scanner := events.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		// Quick pre-filter before unmarshalling
+		if !strings.Contains(string(line), `"bead_c`) {
+			continue
+		}
+		var evt events.Event
+		if err := json.Unmarshal(line, &evt); err != nil || !verify.Valid(line) {
+			continue
+		}
+		id, _ := evt.Payload["bead"].(string)
+		if !members[id] || dedup.Seen(evt.IdempotencyKey) {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, evt.Timestamp)
+		if err != nil {
+			continue
+		}
+		switch evt.Type {
+		case events.TypeBeadCreated:
+			if t, ok := bd.opened[id]; !ok || ts.Before(t) {
+				bd.opened[id] = ts
+			}
+		case events.TypeBeadClosed:
+			if _, closedNow := bd.closed[id]; closedNow {
+				bd.closed[id] = ts
+			}
+		}
+	}
+}
+
+// series returns the open and closed bead counts at the end of each of n
+// equal buckets from the start to now.
+func (bd *burndown) series(n int, now time.Time) (open, closed []int) {
+	open, closed = make([]int, n), make([]int, n)
+	start := bd.start
+	if start.IsZero() || !start.Before(now) {
+		start = now.Add(-time.Hour)
+	}
+	step := now.Sub(start) / time.Duration(n)
+	for i := 0; i < n; i++ {
+		at := start.Add(step * time.Duration(i+1))
+		if i == n-1 {
+			at = now
+		}
+		for _, id := range bd.beads {
+			if t, ok := bd.opened[id]; ok && t.After(at) {
+				continue
+			}
+			t, ok := bd.closed[id]
+			if t.IsZero() {
+				t = now
+			}
+			if ok && !t.After(at) {
+				closed[i]++
+			} else {
+				open[i]++
+			}
+		}
+	}
+	return open, closed
+}
+
+// sparkline renders counts scaled against max, one block per count.
+func sparkline(counts []int, max int) string {
+	var b strings.Builder
+	for _, c := range counts {
+		switch {
+		case max <= 0 || c <= 0:
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(sparkBlocks[(c*(len(sparkBlocks)-1)+max-1)/max])
+		}
+	}
+	return b.String()
+}
+
+// eta estimates when the open beads will be closed at the rate beads have
+// been closed since the start, or "" when nothing has closed yet.
+func (bd *burndown) eta(open, closedTotal int, now time.Time) string {
+	elapsed := now.Sub(bd.start)
+	if open == 0 || closedTotal == 0 || bd.start.IsZero() || elapsed <= 0 {
+		return ""
+	}
+	remaining := time.Duration(float64(elapsed) * float64(open) / float64(closedTotal))
+	return "ETA ~" + formatCountdown(remaining)
+}
+
+// burndownAgents returns the agents working on the selected convoy or epic.
+func (m *Model) burndownAgents() []*AgentLight {
+	var out []*AgentLight
+	for _, a := range m.agents {
+		if a.groupID() == m.burndownID {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// burndownHeight is the panel's height in rows, for layout.
+func (m *Model) burndownHeight() int {
+	return burndownRows + 1 + panelChrome
+}
+
+// renderBurndown renders the burn-down panel: open and closed beads over
+// time, the close rate's ETA, and the agents assigned to the work.
+func (m *Model) renderBurndown(currentY *int) string {
+	*currentY += m.burndownHeight()
+
+	bd := m.burndown
+	if bd == nil {
+		bd = &burndown{group: &workGroup{id: m.burndownID, kind: "convoy"}}
+	}
+	header := rigHeaderStyle.Render("📉 burn-down · " + m.workHeader(bd.group))
+
+	maxW := m.layoutWidth() - 6
+	if maxW < 25 {
+		maxW = 25
+	}
+	const label = 7 // "closed " column
+	width := maxW - 4 - label - 6
+	if width < 10 {
+		width = 10
+	}
+
+	var lines []string
+	switch {
+	case bd.err != "":
+		lines = append(lines, statusDimStyle.Render(bd.err), "", "")
+	case m.burndown == nil:
+		lines = append(lines, statusDimStyle.Render("reading convoy…"), "", "")
+	default:
+		now := time.Now()
+		open, closed := bd.series(width, now)
+		total := len(bd.beads)
+		lastOpen, lastClosed := open[width-1], closed[width-1]
+		lines = append(lines,
+			fmt.Sprintf("%-*s%s %3d", label, "open", barWaitingStyle.Render(sparkline(open, total)), lastOpen),
+			fmt.Sprintf("%-*s%s %3d", label, "closed", barActiveStyle.Render(sparkline(closed, total)), lastClosed),
+		)
+		axis := []string{"since " + formatCountdown(now.Sub(bd.start)) + " ago"}
+		if eta := bd.eta(lastOpen, lastClosed, now); eta != "" {
+			axis = append(axis, eta)
+		} else if lastOpen == 0 && total > 0 {
+			axis = append(axis, "all closed")
+		}
+		lines = append(lines, statusDimStyle.Render(strings.Join(axis, " · ")))
+	}
+
+	var assigned []string
+	for _, a := range m.burndownAgents() {
+		assigned = append(assigned, m.renderBar(a)+" "+a.Name)
+	}
+	if len(assigned) == 0 {
+		lines = append(lines, statusDimStyle.Render("no agents assigned"))
+	} else {
+		lines = append(lines, "agents "+strings.Join(assigned, "  "))
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorBorder).
+		Padding(0, 1)
+	return header + "\n" + style.Width(maxW).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,163 @@
+package activity
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func writeBeadEvents(t *testing.T, path string, evts ...events.Event) {
+	t.Helper()
+	var lines []string
+	for _, e := range evts {
+		data, _ := json.Marshal(e)
+		lines = append(lines, string(data))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func beadEvent(typ, bead string, at time.Time) events.Event {
+	return events.Event{
+		Timestamp: at.UTC().Format(time.RFC3339),
+		Source:    "beads",
+		Type:      typ,
+		Actor:     "beads",
+		Payload:   events.BeadTransitionPayload(bead, "", "hq", ""),
+	}
+}
+
+func TestReadBeadHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), events.EventsFile)
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	writeBeadEvents(t, path,
+		beadEvent(events.TypeBeadCreated, "gt-1", t0),
+		beadEvent(events.TypeBeadCreated, "gt-2", t0.Add(time.Hour)),
+		beadEvent(events.TypeBeadClosed, "gt-1", t0.Add(2*time.Hour)),
+		beadEvent(events.TypeBeadClosed, "gt-2", t0.Add(3*time.Hour)), // reopened since
+		beadEvent(events.TypeBeadCreated, "gt-other", t0),
+	)
+
+	bd := &burndown{
+		opened: map[string]time.Time{},
+		closed: map[string]time.Time{"gt-1": {}},
+	}
+	readBeadHistory(path, nil, map[string]bool{"gt-1": true, "gt-2": true}, bd)
+
+	if got := bd.opened["gt-2"]; !got.Equal(t0.Add(time.Hour)) {
+		t.Errorf("gt-2 opened = %v, want %v", got, t0.Add(time.Hour))
+	}
+	if _, ok := bd.opened["gt-other"]; ok {
+		t.Error("bead outside the convoy was read")
+	}
+	if got := bd.closed["gt-1"]; !got.Equal(t0.Add(2 * time.Hour)) {
+		t.Errorf("gt-1 closed = %v, want %v", got, t0.Add(2*time.Hour))
+	}
+	if _, ok := bd.closed["gt-2"]; ok {
+		t.Error("gt-2 is open now but kept its old close")
+	}
+}
+
+func TestBurndownSeries(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := t0.Add(4 * time.Hour)
+	bd := &burndown{
+		beads: []string{"gt-1", "gt-2", "gt-3", "gt-4"},
+		start: t0,
+		opened: map[string]time.Time{
+			"gt-1": t0,
+			"gt-2": t0,
+			"gt-3": t0.Add(150 * time.Minute), // added to the convoy later
+		},
+		closed: map[string]time.Time{
+			"gt-1": t0.Add(90 * time.Minute),
+			"gt-4": {}, // closed, but no event: counts from now
+		},
+	}
+	open, closed := bd.series(4, now)
+	if want := []int{3, 2, 3, 2}; !equalInts(open, want) {
+		t.Errorf("open = %v, want %v", open, want)
+	}
+	if want := []int{0, 1, 1, 2}; !equalInts(closed, want) {
+		t.Errorf("closed = %v, want %v", closed, want)
+	}
+	if got := bd.eta(2, 2, now); got != "ETA ~4h00m" {
+		t.Errorf("eta = %q, want ETA ~4h00m", got)
+	}
+	if got := bd.eta(2, 0, now); got != "" {
+		t.Errorf("eta with nothing closed = %q, want empty", got)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]int{0, 1, 2, 4}, 4); got != " ▃▅█" {
+		t.Errorf("sparkline = %q, want %q", got, " ▃▅█")
+	}
+	if got := sparkline([]int{1}, 0); got != " " {
+		t.Errorf("sparkline with no beads = %q, want blank", got)
+	}
+}
+
+func TestToggleBurndownHovered(t *testing.T) {
+	m := testModel(convoyAgents(), modelGroups(convoyGroups()))
+	m.hoveredAgent = m.agents[0]
+	m.toggleBurndownHovered()
+	if m.burndownID != "hq-cv-abc" {
+		t.Fatalf("burndownID = %q, want hq-cv-abc", m.burndownID)
+	}
+	m.toggleBurndownHovered()
+	if m.burndownID != "" {
+		t.Errorf("second b left burndownID = %q, want hidden", m.burndownID)
+	}
+
+	m.hoveredAgent = m.agents[3] // witness, no convoy
+	m.toggleBurndownHovered()
+	if m.burndownID != "" || !strings.Contains(m.flashMessage, "isn't working on") {
+		t.Errorf("burndownID = %q, flash = %q", m.burndownID, m.flashMessage)
+	}
+}
+
+func TestRenderBurndown(t *testing.T) {
+	m := testModel(convoyAgents(), modelGroups(convoyGroups()))
+	m.SetBurndown("hq-cv-abc")
+	now := time.Now()
+	m.burndown = &burndown{
+		group:  m.groups["hq-cv-abc"],
+		beads:  []string{"gt-1", "gt-2", "gt-3"},
+		start:  now.Add(-2 * time.Hour),
+		opened: map[string]time.Time{},
+		closed: map[string]time.Time{"gt-1": now.Add(-time.Hour)},
+	}
+
+	y := 0
+	out := m.renderBurndown(&y)
+	for _, want := range []string{"burn-down · convoy hq-cv-abc: Auth rewrite", "open", "closed", "ETA ~", "Toast", "Nux"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("burn-down panel missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "max") {
+		t.Errorf("burn-down lists an agent on another epic:\n%s", out)
+	}
+	if lines := strings.Count(out, "\n") + 1; y != lines || y != m.burndownHeight() {
+		t.Errorf("panel advanced y by %d, rendered %d lines, want %d", y, lines, m.burndownHeight())
+	}
+}
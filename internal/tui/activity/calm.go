@@ -0,0 +1,70 @@
+package activity
+
+import (
+	"fmt"
+	"os"
+)
+
+// Calm rendering (gt top --calm, town settings top.calm) for big towns and
+// slow links. The terminal renderer already rewrites only the lines that
+// changed since the last frame, but with rig panels side by side nearly
+// every line holds a blinking light, so on a 100-agent town each blink
+// rewrote the whole screen. Calm lights hold steady except the alarms that
+// need a human (waiting, hit limit), so a blink touches only their lines.
+const (
+	// calmAgentsRemote is the agent count from which auto mode calms the
+	// lights over SSH.
+	calmAgentsRemote = 40
+	// calmAgentsLocal is the agent count from which auto mode calms the
+	// lights on a local terminal.
+	calmAgentsLocal = 100
+)
+
+// calmMode is the top.calm setting.
+type calmMode int
+
+const (
+	calmAuto calmMode = iota // calm once the town is big for the link
+	calmOn
+	calmOff
+)
+
+// SetCalm sets calm rendering: "auto" (or empty), "on" or "off".
+func (m *Model) SetCalm(mode string) error {
+	switch mode {
+	case "", "auto":
+		m.calm = calmAuto
+	case "on":
+		m.calm = calmOn
+	case "off":
+		m.calm = calmOff
+	default:
+		return fmt.Errorf("unknown calm mode %q (want auto, on or off)", mode)
+	}
+	return nil
+}
+
+// calming reports whether the lights are held steady this frame.
+func (m *Model) calming() bool {
+	switch m.calm {
+	case calmOn:
+		return true
+	case calmOff:
+		return false
+	}
+	if m.remote {
+		return len(m.agents) >= calmAgentsRemote
+	}
+	return len(m.agents) >= calmAgentsLocal
+}
+
+// lit reports whether a blinking light is in its bright phase. Alarm lights
+// blink even when calm.
+func (m *Model) lit(alarm bool) bool {
+	return m.blinkOn || (!alarm && m.calming())
+}
+
+// remoteTerminal reports whether gt top is running over SSH.
+func remoteTerminal() bool {
+	return os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != ""
+}
@@ -0,0 +1,53 @@
+package activity
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCalmHoldsLightsSteady(t *testing.T) {
+	limited := &AgentLight{Level: LevelRateLimited}
+	waiting := &AgentLight{Level: LevelWaitingForHuman}
+	m := &Model{}
+	if err := m.SetCalm("on"); err != nil {
+		t.Fatal(err)
+	}
+
+	m.blinkOn = true
+	limitedOn, waitingOn := m.renderBar(limited), m.renderBar(waiting)
+	m.blinkOn = false
+	if got := m.renderBar(limited); got != limitedOn {
+		t.Errorf("calm rate-limited light blinked: %q then %q", limitedOn, got)
+	}
+	if got := m.renderBar(waiting); got == waitingOn {
+		t.Error("calm waiting light stopped blinking; it needs a human")
+	}
+
+	if err := m.SetCalm("off"); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.renderBar(limited); got == limitedOn {
+		t.Error("rate-limited light held steady with calm off")
+	}
+	if err := m.SetCalm("sometimes"); err == nil {
+		t.Error("SetCalm accepted \"sometimes\"")
+	}
+}
+
+func TestCalmAuto(t *testing.T) {
+	m := &Model{}
+	for i := 0; i < calmAgentsRemote; i++ {
+		m.agents = append(m.agents, &AgentLight{SessionName: fmt.Sprintf("gt-gastown-%d", i)})
+	}
+	if m.calming() {
+		t.Errorf("calm locally at %d agents", len(m.agents))
+	}
+	m.remote = true
+	if !m.calming() {
+		t.Errorf("not calm over SSH at %d agents", len(m.agents))
+	}
+	m.agents = m.agents[:calmAgentsRemote-1]
+	if m.calming() {
+		t.Errorf("calm over SSH at %d agents", len(m.agents))
+	}
+}
@@ -0,0 +1,129 @@
+package activity
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/steveyegge/gastown/internal/agentparse"
+)
+
+// Pane capture depth is the number of scrollback lines read above the
+// visible pane (capture-pane -S -N). Each parser asks for what its signals
+// need: Claude's status and limit banners sit just above the prompt, while
+// OpenCode's sidebar and limit notices scroll further up. The hover detail
+// view reads deeper still for bead IDs.
+const (
+	claudeCaptureDepth   = 10
+	openCodeCaptureDepth = 30
+	detailCaptureDepth   = 100
+
+	// maxCaptureDepth caps every capture: gt top captures every pane on each
+	// poll, so a deep capture costs tmux work per agent per cycle.
+	maxCaptureDepth = 100
+)
+
+// maxPaneLineBytes caps a captured pane line. The parsers only look at the
+// start of a line, and an agent that prints a huge unwrapped line (a
+// minified blob, a base64 dump) would otherwise be copied and scanned in
+// full on every poll.
+const maxPaneLineBytes = 2048
+
+// parserCaptureDepth maps a parser (see chromeAgent) to its capture depth.
+var parserCaptureDepth = map[string]int{
+	"claude":   claudeCaptureDepth,
+	"opencode": openCodeCaptureDepth,
+}
+
+// captureDepth returns the capture depth for the parser of an agent type.
+// Agents not yet identified get the Claude depth, enough to detect the type.
+// A registered parser asks for its own (agentparse.DepthParser).
+func captureDepth(agentType string) int {
+	if p, ok := agentparse.Lookup(agentType); ok {
+		if d, ok := p.(agentparse.DepthParser); ok {
+			return clampCaptureDepth(d.CaptureDepth())
+		}
+		return clampCaptureDepth(claudeCaptureDepth)
+	}
+	return clampCaptureDepth(parserCaptureDepth[chromeAgent(agentType)])
+}
+
+// clampCaptureDepth bounds n to [1, maxCaptureDepth].
+func clampCaptureDepth(n int) int {
+	switch {
+	case n < 1:
+		return 1
+	case n > maxCaptureDepth:
+		return maxCaptureDepth
+	}
+	return n
+}
+
+// captureDepths maps each known session to the capture depth of the agent
+// type detected on earlier polls. Sessions not in the map (new ones) get
+// the default depth until their type is known.
+func (m *Model) captureDepths() map[string]int {
+	depths := make(map[string]int, len(m.agents))
+	for _, a := range m.agents {
+		depths[a.SessionName] = captureDepth(a.AgentType)
+	}
+	return depths
+}
+
+// depth returns the session's capture depth, defaulting to the depth for an
+// agent of unknown type.
+func (s sessionInfo) depth() int {
+	if s.captureDepth == 0 {
+		return captureDepth("")
+	}
+	return clampCaptureDepth(s.captureDepth)
+}
+
+// recaptureDeeper re-captures the panes whose detected agent type wants a
+// different depth than they were captured at, as on a session's second poll.
+func recaptureDeeper(sessions []sessionInfo, panes map[string][]string) {
+	var again []sessionInfo
+	for _, s := range sessions {
+		lines, ok := panes[s.name]
+		if !ok {
+			continue
+		}
+		if depth := captureDepth(detectAgentTypeFromPane(lines)); depth != s.depth() {
+			s.captureDepth = depth
+			again = append(again, s)
+		}
+	}
+	if len(again) == 0 {
+		return
+	}
+	for name, lines := range batchCapturePanes(again) {
+		panes[name] = lines
+	}
+}
+
+// cleanPaneLine makes a captured line safe to parse and render: invalid
+// UTF-8 (binary tool output, e.g. an agent catting an image) becomes U+FFFD,
+// which rune iteration and lipgloss width math would otherwise trip on, and
+// a line longer than maxPaneLineBytes is truncated at a rune boundary, the
+// cut marked with an ellipsis.
+func cleanPaneLine(line string) string {
+	if !utf8.ValidString(line) {
+		line = strings.ToValidUTF8(line, "\uFFFD")
+	}
+	if len(line) <= maxPaneLineBytes {
+		return line
+	}
+	cut := maxPaneLineBytes
+	for cut > 0 && !utf8.RuneStart(line[cut]) {
+		cut--
+	}
+	return line[:cut] + "…"
+}
+
+// splitPaneLines splits captured pane output into lines, cleaning each.
+func splitPaneLines(out string) []string {
+	lines := strings.Split(out, "\n")
+	for i, l := range lines {
+		lines[i] = cleanPaneLine(l)
+	}
+	return lines
+}
@@ -0,0 +1,103 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCaptureDepth(t *testing.T) {
+	tests := []struct {
+		agentType string
+		want      int
+	}{
+		{"", claudeCaptureDepth},
+		{"claude", claudeCaptureDepth},
+		{"gemini", claudeCaptureDepth}, // parsed by the Claude parser
+		{"opencode", openCodeCaptureDepth},
+	}
+	for _, tt := range tests {
+		if got := captureDepth(tt.agentType); got != tt.want {
+			t.Errorf("captureDepth(%q) = %d, want %d", tt.agentType, got, tt.want)
+		}
+	}
+}
+
+func TestClampCaptureDepth(t *testing.T) {
+	for _, tt := range []struct{ in, want int }{
+		{0, 1},
+		{-5, 1},
+		{30, 30},
+		{maxCaptureDepth + 1, maxCaptureDepth},
+	} {
+		if got := clampCaptureDepth(tt.in); got != tt.want {
+			t.Errorf("clampCaptureDepth(%d) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCaptureDepths_ByDetectedType(t *testing.T) {
+	m := &Model{agents: []*AgentLight{
+		{SessionName: "gt-gastown-crew-max", AgentType: "claude"},
+		{SessionName: "gt-gastown-crew-oc", AgentType: "opencode"},
+		{SessionName: "gt-gastown-crew-new"},
+	}}
+	depths := m.captureDepths()
+	if got := depths["gt-gastown-crew-oc"]; got != openCodeCaptureDepth {
+		t.Errorf("opencode depth = %d, want %d", got, openCodeCaptureDepth)
+	}
+	if got := depths["gt-gastown-crew-max"]; got != claudeCaptureDepth {
+		t.Errorf("claude depth = %d, want %d", got, claudeCaptureDepth)
+	}
+
+	// Sessions gt top hasn't seen yet capture at the default depth.
+	s := sessionInfo{name: "gt-gastown-crew-fresh", captureDepth: depths["gt-gastown-crew-fresh"]}
+	if got := s.depth(); got != claudeCaptureDepth {
+		t.Errorf("unseen session depth = %d, want %d", got, claudeCaptureDepth)
+	}
+}
+
+func TestCleanPaneLine(t *testing.T) {
+	short := "❯ hello"
+	if got := cleanPaneLine(short); got != short {
+		t.Errorf("cleanPaneLine(short) = %q, want it unchanged", got)
+	}
+
+	// A multi-byte rune straddling the limit must not be split.
+	long := strings.Repeat("a", maxPaneLineBytes-1) + strings.Repeat("é", 1000)
+	got := cleanPaneLine(long)
+	if !utf8.ValidString(got) {
+		t.Fatalf("cleanPaneLine produced invalid UTF-8")
+	}
+	if !strings.HasSuffix(got, "…") || len(got) > maxPaneLineBytes+len("…") {
+		t.Errorf("cleanPaneLine(long) = %d bytes, want at most %d ending in …", len(got), maxPaneLineBytes+len("…"))
+	}
+}
+
+func TestCleanPaneLineInvalidUTF8(t *testing.T) {
+	// The start of a PNG catted into the pane.
+	got := cleanPaneLine("\x89PNG\r\n\x1a\n\x00\xff\xfe ok")
+	if !utf8.ValidString(got) {
+		t.Fatalf("cleanPaneLine(%q) is not valid UTF-8", got)
+	}
+	if !strings.HasPrefix(got, "\uFFFDPNG") || !strings.HasSuffix(got, "\uFFFD ok") {
+		t.Errorf("cleanPaneLine = %q, want invalid bytes replaced and the rest kept", got)
+	}
+
+	// Invalid bytes straddling the cap still leave valid UTF-8.
+	long := strings.Repeat("a", maxPaneLineBytes-1) + strings.Repeat("\xff", 100)
+	if got := cleanPaneLine(long); !utf8.ValidString(got) || !strings.HasSuffix(got, "…") {
+		t.Errorf("cleanPaneLine(long binary) = %q…, want valid UTF-8 ending in …", got[len(got)-8:])
+	}
+}
+
+func TestSplitPaneLines(t *testing.T) {
+	out := "first\n" + strings.Repeat("x", 10*maxPaneLineBytes) + "\nlast"
+	lines := splitPaneLines(out)
+	if len(lines) != 3 || lines[0] != "first" || lines[2] != "last" {
+		t.Fatalf("splitPaneLines = %d lines, want first, capped, last", len(lines))
+	}
+	if len(lines[1]) > maxPaneLineBytes+len("…") {
+		t.Errorf("long line not capped: %d bytes", len(lines[1]))
+	}
+}
@@ -0,0 +1,238 @@
+package activity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deps"
+)
+
+// chromeSet is the TUI chrome of one agent from some version on: lines
+// matching chrome carry no status and are skipped by the pane parsers, and
+// interrupt is the status-bar hint shown while the agent is working.
+type chromeSet struct {
+	agent      string // "claude" or "opencode"
+	minVersion string // first version this set applies to; "" for any
+	chrome     []*regexp.Regexp
+	frame      bool     // lines of only box-drawing characters are chrome
+	interrupt  string   // e.g. "esc to interrupt"
+	permModes  []string // Claude permission-mode segments that precede the task name
+}
+
+// builtinChrome is the chrome of the agent versions gt was released
+// against. Town settings top.chrome adds sets for newer versions (see
+// config.TopChromePatterns) so a TUI change doesn't need a gt release.
+var builtinChrome = []*chromeSet{
+	{
+		agent: "claude",
+		chrome: mustCompileAll(
+			`^❯`,                 // input prompt
+			`⏵`,                  // bottom status bar indicators
+			`bypass permissions`, // status bar text
+			`shift\+tab to cycle`,
+			`esc to interrupt`,
+			`auto-accept`,
+		),
+		interrupt: "esc to interrupt",
+		permModes: []string{"bypass permissions on", "auto-accept edits on", "auto-accept all on"},
+	},
+	{
+		agent: "opencode",
+		chrome: mustCompileAll(
+			`esc interrupt`,    // bottom status bar, with optional progress dots
+			`ctrl\+p commands`, // "ctrl+t variants  tab agents  ctrl+p commands"
+			`^Build .*Copilot`, // model info line: "Build  Claude Opus 4.6 GitHub Copilot"
+		),
+		frame:     true,
+		interrupt: "esc interrupt",
+	},
+}
+
+// agentVersionPatterns find an agent's version in its pane: Claude Code's
+// welcome banner ("Claude Code v2.0.76") and OpenCode's bottom bar
+// ("• OpenCode 1.1.60").
+var agentVersionPatterns = map[string]*regexp.Regexp{
+	"claude":   regexp.MustCompile(`Claude Code v(\d+(?:\.\d+)*)`),
+	"opencode": regexp.MustCompile(`OpenCode v?(\d+(?:\.\d+)*)`),
+}
+
+func mustCompileAll(patterns ...string) []*regexp.Regexp {
+	out := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		out[i] = regexp.MustCompile(p)
+	}
+	return out
+}
+
+// chromeAgent maps an agent type to the chrome it uses: agents without a
+// parser of their own go through the Claude parser.
+func chromeAgent(agentType string) string {
+	if agentType == "opencode" {
+		return "opencode"
+	}
+	return "claude"
+}
+
+// lookupChrome returns the set for the agent type with the highest
+// minVersion not above version. An unknown version gets the newest set,
+// which matches a freshly installed agent. Later sets win ties.
+func lookupChrome(sets []*chromeSet, agentType, version string) *chromeSet {
+	agent := chromeAgent(agentType)
+	var best *chromeSet
+	for _, s := range sets {
+		if s.agent != agent {
+			continue
+		}
+		if version != "" && s.minVersion != "" && deps.CompareVersions(s.minVersion, version) > 0 {
+			continue
+		}
+		if best == nil || deps.CompareVersions(s.minVersion, best.minVersion) >= 0 {
+			best = s
+		}
+	}
+	return best
+}
+
+// chromeOf returns the chrome set the pane parsers use for the agent.
+func chromeOf(a *AgentLight) *chromeSet {
+	if a.chrome != nil {
+		return a.chrome
+	}
+	return lookupChrome(builtinChrome, a.AgentType, "")
+}
+
+// SetChromePatterns adds chrome sets from town settings top.chrome. Each
+// entry extends the set it would otherwise replace: its chrome patterns are
+// added to that set's, and interrupt and permission modes replace them when
+// given. Entries with an unknown agent or a bad pattern are skipped and
+// reported.
+func (m *Model) SetChromePatterns(cfg []config.TopChromePatterns) error {
+	sets := append([]*chromeSet(nil), builtinChrome...)
+	var errs []error
+	for _, c := range cfg {
+		if c.Agent != "claude" && c.Agent != "opencode" {
+			errs = append(errs, fmt.Errorf("top.chrome: unknown agent %q (want claude or opencode)", c.Agent))
+			continue
+		}
+		base := lookupChrome(sets, c.Agent, c.MinVersion)
+		s := &chromeSet{
+			agent:      c.Agent,
+			minVersion: c.MinVersion,
+			chrome:     append([]*regexp.Regexp(nil), base.chrome...),
+			frame:      base.frame,
+			interrupt:  base.interrupt,
+			permModes:  base.permModes,
+		}
+		bad := false
+		for _, p := range c.Chrome {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("top.chrome %s %s: %w", c.Agent, c.MinVersion, err))
+				bad = true
+				break
+			}
+			s.chrome = append(s.chrome, re)
+		}
+		if bad {
+			continue
+		}
+		if c.Interrupt != "" {
+			s.interrupt = c.Interrupt
+		}
+		if len(c.PermissionModes) > 0 {
+			s.permModes = c.PermissionModes
+		}
+		sets = append(sets, s)
+	}
+	m.chromeSets = sets
+	return errors.Join(errs...)
+}
+
+// selectChrome detects the agent's type and version from the pane, once
+// each, and picks the chrome set the pane parsers will use.
+func (m *Model) selectChrome(a *AgentLight, lines []string) {
+	if a.AgentType == "" {
+		a.AgentType = detectAgentTypeFromPane(lines)
+	}
+	if a.AgentVersion == "" {
+		a.AgentVersion = detectAgentVersion(a.AgentType, lines)
+	}
+	sets := m.chromeSets
+	if sets == nil {
+		sets = builtinChrome
+	}
+	a.chrome = lookupChrome(sets, a.AgentType, a.AgentVersion)
+}
+
+// detectAgentVersion returns the agent version shown in the pane, or "".
+func detectAgentVersion(agentType string, lines []string) string {
+	re := agentVersionPatterns[chromeAgent(agentType)]
+	for _, line := range lines {
+		if match := re.FindStringSubmatch(line); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// isChrome reports whether the line is TUI chrome with no status in it.
+func (c *chromeSet) isChrome(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || isSeparatorLine(trimmed) {
+		return true
+	}
+	if c.frame && isBoxDrawingOnly(trimmed) {
+		return true
+	}
+	for _, re := range c.chrome {
+		if re.MatchString(trimmed) {
+			return true
+		}
+	}
+	return false
+}
+
+// taskName pulls the task name from a Claude Code status bar line: the
+// segment between the permission mode and the interrupt hint, e.g.
+// "⏵⏵ bypass permissions on · Implement CWE enricher source (running) · esc to interrupt"
+// gives "Implement CWE enricher source". Uses text anchors (not symbol
+// chars) since tmux capture may render ⏵ as different codepoints.
+func (c *chromeSet) taskName(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if c.interrupt == "" {
+		return ""
+	}
+	escIdx := strings.Index(trimmed, c.interrupt)
+	if escIdx < 0 {
+		return ""
+	}
+
+	// Find where the permission mode segment ends
+	permEndIdx := -1
+	for _, marker := range c.permModes {
+		if idx := strings.Index(trimmed, marker); idx >= 0 {
+			permEndIdx = idx + len(marker)
+			break
+		}
+	}
+	if permEndIdx < 0 || permEndIdx >= escIdx {
+		return ""
+	}
+
+	// Everything between permission mode and the interrupt hint is the task name
+	middle := trimmed[permEndIdx:escIdx]
+
+	// Skip the "(shift+tab to cycle)" format which means no task name
+	if strings.Contains(middle, "shift+tab") {
+		return ""
+	}
+
+	// Strip separator chars from both ends (·, •, ∙, dashes, whitespace)
+	middle = strings.Trim(middle, " \t·•∙‧⋅─━—-|/")
+
+	// Strip redundant "(running)" suffix - the LED bar already shows activity
+	return strings.TrimSpace(strings.TrimSuffix(middle, "(running)"))
+}
@@ -0,0 +1,93 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestDetectAgentVersion(t *testing.T) {
+	tests := []struct {
+		agent string
+		line  string
+		want  string
+	}{
+		{"claude", "│ ✻ Welcome to Claude Code v2.0.76          │", "2.0.76"},
+		{"opencode", "  ┃  ~/gt/gastown  • OpenCode 1.1.60", "1.1.60"},
+		{"claude", "❯ ", ""},
+	}
+	for _, tt := range tests {
+		if got := detectAgentVersion(tt.agent, []string{tt.line}); got != tt.want {
+			t.Errorf("detectAgentVersion(%s, %q) = %q, want %q", tt.agent, tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestSetChromePatterns_Versioned(t *testing.T) {
+	m := &Model{}
+	err := m.SetChromePatterns([]config.TopChromePatterns{
+		{Agent: "claude", MinVersion: "2.1.0", Chrome: []string{`ctrl\+o to expand`}, Interrupt: "esc to stop"},
+	})
+	if err != nil {
+		t.Fatalf("SetChromePatterns: %v", err)
+	}
+
+	old := lookupChrome(m.chromeSets, "claude", "2.0.76")
+	cur := lookupChrome(m.chromeSets, "claude", "2.1.3")
+	if old.isChrome("ctrl+o to expand") {
+		t.Error("patterns for 2.1.0 must not apply to 2.0.76")
+	}
+	if !cur.isChrome("ctrl+o to expand") || !cur.isChrome("❯ ") {
+		t.Error("2.1.3 should get the new pattern on top of the built-ins")
+	}
+	if got := cur.taskName("⏵⏵ bypass permissions on · Fix tests · esc to stop"); got != "Fix tests" {
+		t.Errorf("taskName with new interrupt hint = %q", got)
+	}
+	if lookupChrome(m.chromeSets, "claude", "") != cur {
+		t.Error("an undetected version should get the newest set")
+	}
+	if lookupChrome(m.chromeSets, "gemini", "") != cur {
+		t.Error("agents without their own parser use Claude chrome")
+	}
+}
+
+func TestSetChromePatterns_BadEntries(t *testing.T) {
+	m := &Model{}
+	err := m.SetChromePatterns([]config.TopChromePatterns{
+		{Agent: "codex", Chrome: []string{"x"}},
+		{Agent: "opencode", MinVersion: "2.0", Chrome: []string{"("}},
+		{Agent: "opencode", MinVersion: "1.2", Chrome: []string{"tab agents"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), `"codex"`) || !strings.Contains(err.Error(), "opencode 2.0") {
+		t.Fatalf("err = %v, want both bad entries reported", err)
+	}
+	if got := lookupChrome(m.chromeSets, "opencode", "2.5"); got.minVersion != "1.2" {
+		t.Errorf("bad entry should be skipped, got set for %q", got.minVersion)
+	}
+}
+
+func TestSelectChrome_ParsesWithAgentVersion(t *testing.T) {
+	m := &Model{}
+	_ = m.SetChromePatterns([]config.TopChromePatterns{
+		{Agent: "claude", MinVersion: "3.0.0", Chrome: []string{`^\? for shortcuts`}},
+	})
+	lines := []string{
+		"│ ✻ Welcome to Claude Code v3.0.1 │",
+		"⏺ Bash(go test ./...)",
+		"? for shortcuts",
+	}
+	a := &AgentLight{SessionName: "gt-gastown-crew-max"}
+	m.selectChrome(a, lines)
+	if a.AgentType != "claude" || a.AgentVersion != "3.0.1" {
+		t.Fatalf("type/version = %q/%q", a.AgentType, a.AgentVersion)
+	}
+	if !chromeOf(a).isChrome("? for shortcuts") {
+		t.Error("agent should use the 3.0.0 chrome")
+	}
+
+	a.resetSticky()
+	if a.AgentVersion != "" || a.chrome != nil {
+		t.Error("restart should re-detect the version")
+	}
+}
@@ -0,0 +1,114 @@
+package activity
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// clipboardTool is a command that copies its stdin to the system clipboard.
+type clipboardTool struct {
+	args []string
+	env  string // only usable when this variable is set, e.g. a display server
+}
+
+// clipboardTools are tried in order; the first one on PATH wins. Without
+// any (e.g. over SSH), the text goes to the tmux paste buffer, which tmux
+// forwards to the terminal's clipboard when set-clipboard is on.
+var clipboardTools = []clipboardTool{
+	{args: []string{"pbcopy"}},
+	{args: []string{"wl-copy"}, env: "WAYLAND_DISPLAY"},
+	{args: []string{"xclip", "-selection", "clipboard"}, env: "DISPLAY"},
+	{args: []string{"xsel", "--clipboard", "--input"}, env: "DISPLAY"},
+	{args: []string{"clip.exe"}}, // WSL
+}
+
+// copiedMsg reports the result of a copy action.
+type copiedMsg struct {
+	what string // e.g. "session name"
+	text string
+	via  string // the tool that took it
+	err  error
+}
+
+// copyToClipboard copies text with the first available clipboard tool,
+// falling back to the tmux paste buffer. Returns the tool used.
+func copyToClipboard(text string) (string, error) {
+	for _, t := range clipboardTools {
+		if t.env != "" && os.Getenv(t.env) == "" {
+			continue
+		}
+		path, err := exec.LookPath(t.args[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, t.args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return t.args[0], err
+		}
+		return t.args[0], nil
+	}
+	if err := tmux.Run(tmuxTimeout, "set-buffer", "-w", "--", text); err != nil {
+		return "", fmt.Errorf("no clipboard tool found (pbcopy, wl-copy, xclip, xsel) and tmux set-buffer failed: %w", err)
+	}
+	return "tmux buffer", nil
+}
+
+// attachCommand returns the shell command that attaches to a session on the
+// town's tmux server.
+func attachCommand(sessionName string) string {
+	if sock := tmux.GetDefaultSocket(); sock != "" {
+		return fmt.Sprintf("tmux -L %s attach -t %s", sock, sessionName)
+	}
+	return "tmux attach -t " + sessionName
+}
+
+// copyHovered copies something about the hovered agent: "session" (y), its
+// attach command (Y), or "bead", its work bead ID (c).
+func (m *Model) copyHovered(what string) tea.Cmd {
+	a := m.hoveredAgent
+	m.flashTime = time.Now()
+	if a == nil {
+		m.flashMessage = "hover an agent to copy its " + what
+		return nil
+	}
+	var text string
+	switch what {
+	case "session name":
+		text = a.SessionName
+	case "attach command":
+		text = attachCommand(a.SessionName)
+		if cmd := m.containerAttachCommand(a); cmd != "" {
+			text = cmd
+		}
+	case "bead ID":
+		if a.WorkBeadID == "" {
+			m.flashMessage = a.SessionName + " has no work bead"
+			return nil
+		}
+		text = a.WorkBeadID
+	}
+	return func() tea.Msg {
+		via, err := copyToClipboard(text)
+		return copiedMsg{what: what, text: text, via: via, err: err}
+	}
+}
+
+// handleCopied flashes the outcome of a copy.
+func (m *Model) handleCopied(msg copiedMsg) {
+	m.flashTime = time.Now()
+	if msg.err != nil {
+		m.flashMessage = "copy failed: " + msg.err.Error()
+		return
+	}
+	m.flashMessage = "copied " + msg.text
+	if msg.via == "tmux buffer" {
+		m.flashMessage += " (tmux buffer)"
+	}
+}
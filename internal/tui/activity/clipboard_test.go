@@ -0,0 +1,73 @@
+package activity
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeClipboard puts a pbcopy on PATH that saves its input to the returned
+// file.
+func fakeClipboard(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script stand-in")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "clipboard")
+	script := "#!/bin/sh\ncat > '" + out + "'\n"
+	if err := os.WriteFile(filepath.Join(dir, "pbcopy"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return out
+}
+
+func TestCopyToClipboard(t *testing.T) {
+	out := fakeClipboard(t)
+	via, err := copyToClipboard("gt-gastown-crew-max")
+	if err != nil || via != "pbcopy" {
+		t.Fatalf("copyToClipboard = %q, %v; want pbcopy", via, err)
+	}
+	got, _ := os.ReadFile(out)
+	if string(got) != "gt-gastown-crew-max" {
+		t.Errorf("clipboard = %q", got)
+	}
+}
+
+func TestCopyHovered(t *testing.T) {
+	out := fakeClipboard(t)
+	a := &AgentLight{SessionName: "gt-gastown-crew-max", WorkBeadID: "gt-abc12"}
+	m := &Model{hoveredAgent: a}
+
+	tests := []struct{ what, want string }{
+		{"session name", "gt-gastown-crew-max"},
+		{"attach command", "attach -t gt-gastown-crew-max"},
+		{"bead ID", "gt-abc12"},
+	}
+	for _, tt := range tests {
+		cmd := m.copyHovered(tt.what)
+		if cmd == nil {
+			t.Fatalf("%s: no copy command", tt.what)
+		}
+		m.handleCopied(cmd().(copiedMsg))
+		got, _ := os.ReadFile(out)
+		if !strings.Contains(string(got), tt.want) {
+			t.Errorf("%s: clipboard = %q, want %q", tt.what, got, tt.want)
+		}
+		if !strings.HasPrefix(m.flashMessage, "copied ") {
+			t.Errorf("%s: flash = %q", tt.what, m.flashMessage)
+		}
+	}
+
+	a.WorkBeadID = ""
+	if cmd := m.copyHovered("bead ID"); cmd != nil || !strings.Contains(m.flashMessage, "no work bead") {
+		t.Errorf("no bead: cmd %v, flash %q", cmd != nil, m.flashMessage)
+	}
+	m.hoveredAgent = nil
+	if cmd := m.copyHovered("session name"); cmd != nil || !strings.HasPrefix(m.flashMessage, "hover an agent") {
+		t.Errorf("nothing hovered: cmd %v, flash %q", cmd != nil, m.flashMessage)
+	}
+}
@@ -0,0 +1,236 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Pane capture runs in one collector goroutine per session, feeding a
+// reducer that assembles each poll. A capture that hangs on a wedged session
+// only holds up its own collector: the poll waits at most collectWait, then
+// goes ahead with that session's last good pane and marks it stale.
+const (
+	// captureTimeout kills a capture-pane that hasn't returned.
+	captureTimeout = 3 * time.Second
+
+	// tmuxTimeout bounds gt top's other tmux calls (list-sessions,
+	// list-panes, show-environment, ...), some of which run on the UI
+	// goroutine where a hang would freeze the screen.
+	tmuxTimeout = 2 * time.Second
+
+	// collectWait is how long a poll waits for its captures before
+	// reducing without the ones still outstanding.
+	collectWait = 1500 * time.Millisecond
+
+	// collectorBacklog bounds the reducer's inbox. Collectors block on a
+	// full inbox, and each holds at most one pending request, so a slow
+	// reducer throttles captures instead of queueing them.
+	collectorBacklog = 64
+)
+
+// captureRequest asks a collector to capture its session's agent pane.
+type captureRequest struct {
+	seq    int
+	target string
+	depth  int
+}
+
+// captureResult is one collector's answer to a request.
+type captureResult struct {
+	session string
+	seq     int
+	lines   []string
+	err     error
+	at      time.Time
+}
+
+// collector captures one session's pane on request.
+type collector struct {
+	requests chan captureRequest // capacity 1: a busy collector is skipped, not queued
+	stop     chan struct{}
+}
+
+// collectorPool owns the per-session collectors and the reducer state: each
+// session's latest capture. The collector goroutines only see their own
+// channels; everything else is touched under mu.
+type collectorPool struct {
+	mu         sync.Mutex
+	collectors map[string]*collector
+	results    chan captureResult
+	latest     map[string]captureResult // last successful capture per session
+	failed     map[string]time.Time     // when captures started failing or timing out
+	seq        int
+
+	// capture takes the pane; capturePane except in tests.
+	capture func(ctx context.Context, target string, depth int) ([]string, error)
+}
+
+func newCollectorPool() *collectorPool {
+	return &collectorPool{
+		collectors: make(map[string]*collector),
+		results:    make(chan captureResult, collectorBacklog),
+		latest:     make(map[string]captureResult),
+		failed:     make(map[string]time.Time),
+		capture:    capturePane,
+	}
+}
+
+// capturePane captures the last depth lines of scrollback plus the visible
+// pane of target.
+func capturePane(ctx context.Context, target string, depth int) ([]string, error) {
+	out, err := tmux.BuildCommandContext(ctx, "capture-pane", "-t", target, "-p", "-S", fmt.Sprintf("-%d", depth)).Output()
+	if err != nil {
+		return nil, err
+	}
+	return splitPaneLines(strings.TrimSuffix(string(out), "\n")), nil
+}
+
+// run serves capture requests until stopped. A hung capture is killed after
+// captureTimeout and reported as an error.
+func (p *collectorPool) run(session string, c *collector) {
+	for {
+		select {
+		case <-c.stop:
+			return
+		case req := <-c.requests:
+			ctx, cancel := context.WithTimeout(context.Background(), captureTimeout)
+			lines, err := p.capture(ctx, req.target, req.depth)
+			if err == nil && ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			cancel()
+			select {
+			case p.results <- captureResult{session: session, seq: req.seq, lines: lines, err: err, at: time.Now()}:
+			case <-c.stop:
+				return
+			}
+		}
+	}
+}
+
+// collect captures every session's pane through its collector and fills in
+// paneLines. Sessions whose capture didn't come back within wait, or failed,
+// keep their last good lines and are marked stale.
+func (p *collectorPool) collect(sessions []sessionInfo, wait time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.seq++
+	seq := p.seq
+	p.syncCollectors(sessions)
+
+	// Fold in answers that arrived after the last poll gave up on them,
+	// so their collectors are free for this round.
+	for drained := false; !drained; {
+		select {
+		case r := <-p.results:
+			p.reduce(r)
+		default:
+			drained = true
+		}
+	}
+
+	pending := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		c := p.collectors[s.name]
+		select {
+		case c.requests <- captureRequest{seq: seq, target: s.target(), depth: s.depth()}:
+			pending[s.name] = true
+		default:
+			// Still busy with an earlier request: wedged, or just slow.
+		}
+	}
+	timeout := time.After(wait)
+	for len(pending) > 0 {
+		select {
+		case r := <-p.results:
+			p.reduce(r)
+			if r.seq == seq {
+				delete(pending, r.session)
+			}
+		case <-timeout:
+			pending = nil
+		}
+	}
+
+	now := time.Now()
+	for i := range sessions {
+		s := &sessions[i]
+		r, ok := p.latest[s.name]
+		if ok {
+			s.paneLines = r.lines
+		}
+		if !ok || r.seq != seq {
+			s.captureStale = true
+			if since, failing := p.failed[s.name]; failing {
+				s.staleSince = since
+			} else {
+				s.staleSince = now
+				p.failed[s.name] = now
+			}
+		}
+	}
+}
+
+// reduce folds one result into the per-session state.
+func (p *collectorPool) reduce(r captureResult) {
+	if _, tracked := p.collectors[r.session]; !tracked {
+		return // session went away while its capture was in flight
+	}
+	if r.err != nil {
+		if _, failing := p.failed[r.session]; !failing {
+			p.failed[r.session] = r.at
+		}
+		return
+	}
+	if prev, ok := p.latest[r.session]; ok && prev.seq > r.seq {
+		return
+	}
+	p.latest[r.session] = r
+	delete(p.failed, r.session)
+}
+
+// syncCollectors starts collectors for new sessions and stops those of
+// sessions that are gone.
+func (p *collectorPool) syncCollectors(sessions []sessionInfo) {
+	live := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		live[s.name] = true
+		if _, ok := p.collectors[s.name]; !ok {
+			c := &collector{requests: make(chan captureRequest, 1), stop: make(chan struct{})}
+			p.collectors[s.name] = c
+			go p.run(s.name, c)
+		}
+	}
+	for name, c := range p.collectors {
+		if !live[name] {
+			close(c.stop)
+			delete(p.collectors, name)
+			delete(p.latest, name)
+			delete(p.failed, name)
+		}
+	}
+}
+
+// size returns the number of running collectors.
+func (p *collectorPool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.collectors)
+}
+
+// captureSummary describes a stalled capture for the hover line, or "".
+func (a *AgentLight) captureSummary(now time.Time) string {
+	if a.CaptureStaleSince.IsZero() {
+		return ""
+	}
+	s := "pane capture stalled"
+	if d := formatElapsed(now.Sub(a.CaptureStaleSince)); d != "" {
+		s += " for " + d
+	}
+	return s + " · showing the last good capture"
+}
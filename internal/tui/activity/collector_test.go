@@ -0,0 +1,177 @@
+package activity
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeCapture answers with "<target> #n" per call, except for wedged targets,
+// which hang until their capture times out.
+type fakeCapture struct {
+	mu     sync.Mutex
+	wedged map[string]bool
+	calls  map[string]*atomic.Int32
+}
+
+func newFakeCapture(wedged ...string) *fakeCapture {
+	f := &fakeCapture{wedged: map[string]bool{}, calls: map[string]*atomic.Int32{}}
+	for _, w := range wedged {
+		f.wedged[w] = true
+	}
+	return f
+}
+
+func (f *fakeCapture) capture(ctx context.Context, target string, depth int) ([]string, error) {
+	f.mu.Lock()
+	n, ok := f.calls[target]
+	if !ok {
+		n = &atomic.Int32{}
+		f.calls[target] = n
+	}
+	wedged := f.wedged[target]
+	f.mu.Unlock()
+	call := n.Add(1)
+	if wedged {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return []string{target + " #" + string(rune('0'+call))}, nil
+}
+
+func (f *fakeCapture) setWedged(target string, wedged bool) {
+	f.mu.Lock()
+	f.wedged[target] = wedged
+	f.mu.Unlock()
+}
+
+func (f *fakeCapture) count(target string) int32 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if n, ok := f.calls[target]; ok {
+		return n.Load()
+	}
+	return 0
+}
+
+func testSessions(names ...string) []sessionInfo {
+	var out []sessionInfo
+	for _, n := range names {
+		out = append(out, sessionInfo{name: n})
+	}
+	return out
+}
+
+func TestCollectorPool_WedgedSessionDoesNotStallPoll(t *testing.T) {
+	fake := newFakeCapture("gt-wedged")
+	p := newCollectorPool()
+	p.capture = fake.capture
+
+	sessions := testSessions("gt-a", "gt-wedged", "gt-b")
+	start := time.Now()
+	p.collect(sessions, 100*time.Millisecond)
+	if took := time.Since(start); took > time.Second {
+		t.Fatalf("collect took %s with one wedged session, want about the wait", took)
+	}
+
+	for _, s := range sessions {
+		switch s.name {
+		case "gt-wedged":
+			if !s.captureStale || s.staleSince.IsZero() {
+				t.Errorf("wedged session not marked stale: %+v", s)
+			}
+		default:
+			if s.captureStale || len(s.paneLines) != 1 || !strings.HasPrefix(s.paneLines[0], s.name) {
+				t.Errorf("%s: stale=%v lines=%q, want a fresh capture", s.name, s.captureStale, s.paneLines)
+			}
+		}
+	}
+}
+
+func TestCollectorPool_BusyCollectorIsNotQueued(t *testing.T) {
+	fake := newFakeCapture("gt-wedged")
+	p := newCollectorPool()
+	p.capture = fake.capture
+
+	for i := 0; i < 3; i++ {
+		p.collect(testSessions("gt-a", "gt-wedged"), 20*time.Millisecond)
+	}
+	if n := fake.count("gt-wedged"); n != 1 {
+		t.Errorf("wedged collector was asked %d times, want 1 (later polls skip it while busy)", n)
+	}
+	if n := fake.count("gt-a"); n != 3 {
+		t.Errorf("healthy collector captured %d times, want 3", n)
+	}
+}
+
+func TestCollectorPool_KeepsLastGoodCaptureWhileStalled(t *testing.T) {
+	fake := newFakeCapture()
+	p := newCollectorPool()
+	p.capture = fake.capture
+
+	sessions := testSessions("gt-a")
+	p.collect(sessions, time.Second)
+	if sessions[0].captureStale {
+		t.Fatalf("first capture stale: %+v", sessions[0])
+	}
+	good := sessions[0].paneLines
+
+	fake.setWedged("gt-a", true)
+	sessions = testSessions("gt-a")
+	p.collect(sessions, 20*time.Millisecond)
+	if !sessions[0].captureStale || strings.Join(sessions[0].paneLines, "") != strings.Join(good, "") {
+		t.Errorf("stalled session = %+v, want stale with the last good lines %q", sessions[0], good)
+	}
+	since := sessions[0].staleSince
+
+	sessions = testSessions("gt-a")
+	p.collect(sessions, 20*time.Millisecond)
+	if !sessions[0].staleSince.Equal(since) {
+		t.Errorf("staleSince moved from %v to %v while still stalled", since, sessions[0].staleSince)
+	}
+}
+
+func TestCollectorPool_StopsCollectorsForGoneSessions(t *testing.T) {
+	fake := newFakeCapture()
+	p := newCollectorPool()
+	p.capture = fake.capture
+
+	p.collect(testSessions("gt-a", "gt-b"), time.Second)
+	if n := p.size(); n != 2 {
+		t.Fatalf("size = %d, want 2", n)
+	}
+	p.collect(testSessions("gt-b"), time.Second)
+	if n := p.size(); n != 1 {
+		t.Errorf("size after gt-a ended = %d, want 1", n)
+	}
+	if _, ok := p.latest["gt-a"]; ok {
+		t.Error("gone session's capture kept")
+	}
+}
+
+func TestUpdateAgents_StaleCaptureKeepsParsedState(t *testing.T) {
+	m := &Model{}
+	m.updateAgents([]sessionInfo{{name: "hq-mayor", activity: time.Now().Unix(), paneLines: []string{"Interrupted · What should Claude do instead?"}}})
+	if len(m.agents) != 1 {
+		t.Fatalf("agents = %d, want 1", len(m.agents))
+	}
+	a := m.agents[0]
+	if a.WaitingReason != "interrupted" {
+		t.Fatalf("WaitingReason = %q, want the fresh capture parsed", a.WaitingReason)
+	}
+
+	since := time.Now().Add(-30 * time.Second)
+	m.updateAgents([]sessionInfo{{name: "hq-mayor", activity: time.Now().Unix(), captureStale: true, staleSince: since}})
+	if a.WaitingReason != "interrupted" {
+		t.Errorf("WaitingReason = %q after a stale poll, want the last parse kept", a.WaitingReason)
+	}
+	if !a.CaptureStaleSince.Equal(since) {
+		t.Errorf("CaptureStaleSince = %v, want %v", a.CaptureStaleSince, since)
+	}
+	if s := a.captureSummary(time.Now()); !strings.Contains(s, "stalled for 30s") {
+		t.Errorf("captureSummary = %q", s)
+	}
+}
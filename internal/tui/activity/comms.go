@@ -0,0 +1,162 @@
+package activity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// commWindow is how far back agent-to-agent communication is shown.
+const commWindow = 10 * time.Minute
+
+// maxCommEdges caps how many edges the hover detail lists per agent.
+const maxCommEdges = 4
+
+// commEvent is one message from one agent address to another, extracted
+// from mail, nudge, sling, and escalation events.
+type commEvent struct {
+	At   time.Time
+	From string // sender address (event actor), e.g., "mayor" or "gastown/witness"
+	To   string // recipient address, e.g., "gastown/polecats/Toast" or "mayor/"
+	Kind string // event type: "mail", "nudge", "sling", "escalation_sent"
+}
+
+// commEdge aggregates commEvents between the hovered agent and one peer.
+type commEdge struct {
+	Outgoing bool   // true if the hovered agent sent the messages
+	Peer     string // the other side's address
+	Kind     string
+	Count    int
+	Last     time.Time
+}
+
+// commRecipients extracts recipient addresses from a communication event
+// payload. Returns nil for event types that aren't agent-to-agent messages.
+func commRecipients(eventType string, payload map[string]interface{}) []string {
+	str := func(key string) string {
+		s, _ := payload[key].(string)
+		return s
+	}
+	switch eventType {
+	case events.TypeMail:
+		return []string{str("to")}
+	case events.TypeSling:
+		return []string{str("target")}
+	case events.TypeNudge:
+		target := str("target")
+		// rig-scoped nudges carry the rig separately: {rig: gastown, target: Toast}
+		if rig := str("rig"); rig != "" && !strings.Contains(target, "/") {
+			target = rig + "/" + target
+		}
+		return []string{target}
+	case events.TypeEscalationSent:
+		// "to" is a comma-separated list of escalation targets
+		return strings.Split(str("to"), ",")
+	default:
+		return nil
+	}
+}
+
+// readRecentComms collects the communication events within commWindow
+// from the events tail.
+func (m *Model) readRecentComms() {
+	m.recentComms = nil
+	cutoff := time.Now().Add(-commWindow)
+	for _, evt := range m.tail.recent {
+		if evt.At.Before(cutoff) {
+			continue
+		}
+		for _, to := range commRecipients(evt.Type, evt.Payload) {
+			to = strings.TrimSpace(to)
+			if to == "" || evt.Actor == "" {
+				continue
+			}
+			m.recentComms = append(m.recentComms, commEvent{At: evt.At, From: evt.Actor, To: to, Kind: evt.Type})
+		}
+	}
+}
+
+// agentForAddress resolves a mail/nudge address to a running agent.
+// Accepts agent IDs ("gastown/polecats/Toast", "mayor/"), short mail
+// addresses ("gastown/Toast"), and raw session names ("gt-Toast").
+func (m *Model) agentForAddress(addr string) *AgentLight {
+	addr = strings.TrimSuffix(addr, "/")
+	if addr == "" {
+		return nil
+	}
+	for _, a := range m.agents {
+		if a.AgentID == addr || a.SessionName == addr {
+			return a
+		}
+	}
+	// Short form: <rig>/<name> for polecats and crew, <rig>/<role> otherwise
+	if parts := strings.Split(addr, "/"); len(parts) == 2 {
+		for _, a := range m.agents {
+			if a.Rig == parts[0] && (a.Name == parts[1] || a.Role == parts[1]) {
+				return a
+			}
+		}
+	}
+	return nil
+}
+
+// commEdgesFor aggregates recent communication involving a into edges,
+// most recent first.
+func (m *Model) commEdgesFor(a *AgentLight) []commEdge {
+	byKey := make(map[string]*commEdge)
+	var edges []*commEdge
+	add := func(outgoing bool, peer, kind string, at time.Time) {
+		key := fmt.Sprintf("%t|%s|%s", outgoing, peer, kind)
+		e, ok := byKey[key]
+		if !ok {
+			e = &commEdge{Outgoing: outgoing, Peer: peer, Kind: kind}
+			byKey[key] = e
+			edges = append(edges, e)
+		}
+		e.Count++
+		if at.After(e.Last) {
+			e.Last = at
+		}
+	}
+	for _, c := range m.recentComms {
+		from := m.agentForAddress(c.From)
+		to := m.agentForAddress(c.To)
+		if from == a && to != a {
+			add(true, c.To, c.Kind, c.At)
+		} else if to == a && from != a {
+			add(false, c.From, c.Kind, c.At)
+		}
+	}
+
+	sort.SliceStable(edges, func(i, j int) bool { return edges[i].Last.After(edges[j].Last) })
+	out := make([]commEdge, 0, len(edges))
+	for _, e := range edges {
+		out = append(out, *e)
+	}
+	return out
+}
+
+// renderCommEdges formats edges for the hover detail, e.g.
+// "← mayor sling · → gastown/refinery mail×2".
+func renderCommEdges(edges []commEdge) string {
+	var parts []string
+	for i, e := range edges {
+		if i == maxCommEdges {
+			parts = append(parts, fmt.Sprintf("+%d", len(edges)-maxCommEdges))
+			break
+		}
+		arrow := "←"
+		if e.Outgoing {
+			arrow = "→"
+		}
+		kind := strings.TrimSuffix(e.Kind, "_sent") // escalation_sent → escalation
+		if e.Count > 1 {
+			kind += fmt.Sprintf("×%d", e.Count)
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s", arrow, strings.TrimSuffix(e.Peer, "/"), kind))
+	}
+	return strings.Join(parts, " · ")
+}
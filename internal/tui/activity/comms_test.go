@@ -0,0 +1,73 @@
+package activity
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestReadRecentComms(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now().UTC()
+	evts := []events.Event{
+		{Timestamp: now.Format(time.RFC3339), Type: events.TypeMail, Actor: "mayor", Payload: events.MailPayload("gastown/Toast", "hi")},
+		{Timestamp: now.Format(time.RFC3339), Type: events.TypeNudge, Actor: "gastown/witness", Payload: events.NudgePayload("gastown", "Toast", "wake up")},
+		{Timestamp: now.Format(time.RFC3339), Type: events.TypeEscalationSent, Actor: "gastown/polecats/Toast", Payload: events.EscalationPayload("hq-1", "gastown/polecats/Toast", "mayor/,deacon/", "stuck")},
+		{Timestamp: now.Add(-time.Hour).Format(time.RFC3339), Type: events.TypeMail, Actor: "mayor", Payload: events.MailPayload("gastown/Toast", "old")},
+		{Timestamp: now.Format(time.RFC3339), Type: events.TypeToolStarted, Actor: "gastown/polecats/Toast", Payload: map[string]interface{}{"tool": "Bash"}},
+	}
+	var lines []string
+	for _, e := range evts {
+		data, _ := json.Marshal(e)
+		lines = append(lines, string(data))
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Model{townRoot: townRoot}
+	m.readEvents(now)
+	m.readRecentComms()
+	if len(m.recentComms) != 4 {
+		t.Fatalf("got %d comm events, want 4 (mail, nudge, 2 escalation targets): %+v", len(m.recentComms), m.recentComms)
+	}
+	if m.recentComms[1].To != "gastown/Toast" {
+		t.Errorf("nudge recipient = %q, want rig-qualified gastown/Toast", m.recentComms[1].To)
+	}
+}
+
+func TestCommEdgesFor(t *testing.T) {
+	now := time.Now()
+	toast := &AgentLight{Name: "Toast", Rig: "gastown", Role: "polecat", SessionName: "gt-Toast", AgentID: "gastown/polecats/Toast"}
+	mayor := &AgentLight{Name: "mayor", Role: "mayor", SessionName: "hq-mayor", AgentID: "mayor"}
+	m := &Model{
+		agents: []*AgentLight{toast, mayor},
+		recentComms: []commEvent{
+			{At: now.Add(-5 * time.Minute), From: "mayor", To: "gastown/Toast", Kind: "mail"},
+			{At: now.Add(-4 * time.Minute), From: "mayor", To: "gastown/polecats/Toast", Kind: "mail"},
+			{At: now.Add(-1 * time.Minute), From: "gastown/polecats/Toast", To: "mayor/", Kind: "escalation_sent"},
+			{At: now, From: "gastown/witness", To: "gastown/refinery", Kind: "nudge"}, // unrelated
+		},
+	}
+
+	edges := m.commEdgesFor(toast)
+	if len(edges) != 2 {
+		t.Fatalf("got %d edges, want 2: %+v", len(edges), edges)
+	}
+	if !edges[0].Outgoing || edges[0].Kind != "escalation_sent" {
+		t.Errorf("first edge = %+v, want most recent outgoing escalation", edges[0])
+	}
+	if edges[1].Outgoing || edges[1].Count != 2 {
+		t.Errorf("second edge = %+v, want incoming mail ×2 (short and full address merged)", edges[1])
+	}
+
+	got := renderCommEdges(edges)
+	if got != "→ mayor escalation · ← mayor mail×2" {
+		t.Errorf("renderCommEdges = %q", got)
+	}
+}
@@ -0,0 +1,309 @@
+package activity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// compareWindow is how far back the comparison overlay looks.
+const compareWindow = time.Hour
+
+// compareCells is the width of each agent's level timeline, one cell per
+// two minutes of the window.
+const compareCells = 30
+
+// comparison is the overlay comparing two agents over the last hour, read
+// from the status ledger (levels, tools) and the beads closed (beadClose)
+// when it was opened.
+type comparison struct {
+	at    time.Time
+	stats [2]*agentStats
+}
+
+// agentStats is one side of a comparison.
+type agentStats struct {
+	agent    *AgentLight
+	timeline []ActivityLevel // one per cell, oldest first; -1 where unknown
+	active   int             // percent of known cells at active or recent
+	known    bool            // the ledger covers some of the window
+	tools    int             // tool calls started in the window
+	topTools []string        // most used tool names, most used first
+	closed   int             // beads closed (done or bead_closed) by the agent
+}
+
+// levelUnknown marks timeline cells the ledger has nothing for.
+const levelUnknown ActivityLevel = -1
+
+// toggleCompareHovered picks the hovered agent as the first side of a
+// comparison, or opens the overlay once a second agent is picked. Picking
+// the first agent again cancels.
+func (m *Model) toggleCompareHovered() {
+	a := m.hoveredAgent
+	now := time.Now()
+	switch {
+	case a == nil:
+		if m.compareFirst != nil {
+			m.compareFirst = nil
+			m.flashMessage = "comparison cancelled"
+		} else {
+			m.flashMessage = "hover an agent and press v to compare it"
+		}
+	case m.compareFirst == nil:
+		m.compareFirst = a
+		m.flashMessage = "comparing " + a.Name + ": hover another agent and press v"
+	case m.compareFirst == a:
+		m.compareFirst = nil
+		m.flashMessage = "comparison cancelled"
+	default:
+		m.compare = m.buildComparison(m.compareFirst, a, now)
+		m.compareFirst = nil
+		m.flashMessage = ""
+	}
+	m.flashTime = now
+}
+
+// buildComparison reads both agents' last hour.
+func (m *Model) buildComparison(a, b *AgentLight, now time.Time) *comparison {
+	var entries []LedgerEntry
+	closed := map[string]map[string]bool{}
+	if m.townRoot != "" {
+		entries, _ = ReadLedger(m.townRoot)
+		closed = m.beadsClosedSince(now.Add(-compareWindow))
+	}
+	c := &comparison{at: now}
+	for i, agent := range []*AgentLight{a, b} {
+		s := ledgerStats(entries, agent.SessionName, now)
+		s.agent = agent
+		s.closed = len(closed[strings.TrimSuffix(agent.AgentID, "/")])
+		c.stats[i] = s
+	}
+	return c
+}
+
+// ledgerStats reads a session's level timeline and tool calls over the
+// window ending at now from the ledger entries.
+func ledgerStats(entries []LedgerEntry, session string, now time.Time) *agentStats {
+	start := now.Add(-compareWindow)
+	type change struct {
+		at    time.Time
+		level ActivityLevel
+	}
+	var changes []change
+	s := &agentStats{}
+	toolUse := make(map[string]int)
+	for _, e := range entries {
+		if e.Session != session || e.Time.After(now) {
+			continue
+		}
+		switch {
+		case e.Gone:
+			changes = append(changes, change{e.Time, LevelDead})
+		case e.Fields["level"] != "":
+			changes = append(changes, change{e.Time, parseLevel(e.Fields["level"])})
+		case e.Full:
+			changes = append(changes, change{e.Time, levelUnknown})
+		}
+		if tool := e.Fields["tool"]; tool != "" && !e.Time.Before(start) {
+			s.tools++
+			toolUse[toolName(tool)]++
+		}
+	}
+
+	s.timeline = make([]ActivityLevel, compareCells)
+	step := compareWindow / compareCells
+	known, active := 0, 0
+	for i := range s.timeline {
+		at := start.Add(step*time.Duration(i) + step/2)
+		l := levelUnknown
+		for _, c := range changes {
+			if c.at.After(at) {
+				break
+			}
+			l = c.level
+		}
+		s.timeline[i] = l
+		if l != levelUnknown {
+			known++
+			if l == LevelActive || l == LevelRecent {
+				active++
+			}
+		}
+	}
+	if known > 0 {
+		s.known = true
+		s.active = active * 100 / known
+	}
+
+	for name := range toolUse {
+		s.topTools = append(s.topTools, name)
+	}
+	sort.Slice(s.topTools, func(i, j int) bool {
+		ti, tj := s.topTools[i], s.topTools[j]
+		if toolUse[ti] != toolUse[tj] {
+			return toolUse[ti] > toolUse[tj]
+		}
+		return ti < tj
+	})
+	if len(s.topTools) > 3 {
+		s.topTools = s.topTools[:3]
+	}
+	return s
+}
+
+// parseLevel is the inverse of ActivityLevel.String; unknown names read as
+// levelUnknown.
+func parseLevel(name string) ActivityLevel {
+	for l := LevelActive; l <= LevelDead; l++ {
+		if l.String() == name {
+			return l
+		}
+	}
+	return levelUnknown
+}
+
+// beadClose is a bead an agent closed: a done event from a polecat, or a
+// bead_closed event attributed to an agent.
+type beadClose struct {
+	at    time.Time
+	actor string
+	bead  string
+}
+
+// trackBeadsClosed adds the beads closed in the events read this poll and
+// forgets those older than compareWindow.
+func (m *Model) trackBeadsClosed(now time.Time) {
+	cutoff := now.Add(-compareWindow)
+	kept := m.beadsClosed[:0]
+	for _, c := range m.beadsClosed {
+		if !c.at.Before(cutoff) {
+			kept = append(kept, c)
+		}
+	}
+	m.beadsClosed = kept
+	for _, evt := range m.tail.fresh {
+		if evt.Type != events.TypeDone && evt.Type != events.TypeBeadClosed {
+			continue
+		}
+		bead, _ := evt.Payload["bead"].(string)
+		if evt.At.Before(cutoff) || bead == "" {
+			continue
+		}
+		m.beadsClosed = append(m.beadsClosed, beadClose{at: evt.At, actor: strings.TrimSuffix(evt.Actor, "/"), bead: bead})
+	}
+}
+
+// beadsClosedSince returns the beads closed since the cutoff, keyed by the
+// closing actor.
+func (m *Model) beadsClosedSince(since time.Time) map[string]map[string]bool {
+	out := make(map[string]map[string]bool)
+	for _, c := range m.beadsClosed {
+		if c.at.Before(since) {
+			continue
+		}
+		if out[c.actor] == nil {
+			out[c.actor] = make(map[string]bool)
+		}
+		out[c.actor][c.bead] = true
+	}
+	return out
+}
+
+// renderTimeline renders a level timeline with the LED glyphs.
+func (m *Model) renderTimeline(levels []ActivityLevel) string {
+	var b strings.Builder
+	for _, l := range levels {
+		if l == levelUnknown {
+			b.WriteString(statusDimStyle.Render("·"))
+			continue
+		}
+		b.WriteString(m.renderBar(&AgentLight{Level: l}))
+	}
+	return b.String()
+}
+
+// signed renders a difference with its sign, "=" when there is none.
+func signed(d int, unit string) string {
+	switch {
+	case d > 0:
+		return fmt.Sprintf("+%d%s", d, unit)
+	case d < 0:
+		return fmt.Sprintf("%d%s", d, unit)
+	}
+	return "="
+}
+
+// renderCompare renders the comparison overlay shown in place of the
+// panels: each agent's last hour side by side, and the second minus the
+// first.
+func (m *Model) renderCompare() string {
+	c := m.compare
+	a, b := c.stats[0], c.stats[1]
+	const labelW, colW = 14, compareCells + 4
+
+	label := func(agent *AgentLight) string {
+		s := agent.Name
+		if agent.AgentType != "" {
+			s += " (" + strings.TrimSpace(agent.AgentType+" "+agent.AgentVersion) + ")"
+		}
+		return truncateWidth(s, colW-2, "…")
+	}
+	row := func(name, left, right, diff string) string {
+		return padWidth(statusDimStyle.Render(name), labelW) + padWidth(left, colW) + padWidth(right, colW) + statusDimStyle.Render(diff)
+	}
+	pct := func(s *agentStats) string {
+		if !s.known {
+			return "no ledger"
+		}
+		return fmt.Sprintf("%d%%", s.active)
+	}
+	tools := func(s *agentStats) string {
+		out := fmt.Sprint(s.tools)
+		if len(s.topTools) > 0 {
+			out += " · " + strings.Join(s.topTools, ", ")
+		}
+		return truncateWidth(out, colW-2, "…")
+	}
+	tokens := func(agent *AgentLight) string {
+		if agent.TokenCount == 0 && agent.TokensPerMin == 0 {
+			return "–"
+		}
+		out := formatTokenCount(agent.TokenCount)
+		if agent.TokensPerMin > 0 {
+			out += " · " + formatTokenRate(agent.TokensPerMin)
+		}
+		return out
+	}
+	activeDiff := ""
+	if a.known && b.known {
+		activeDiff = signed(b.active-a.active, "pts")
+	}
+
+	body := []string{
+		titleStyle.Render("compare · last hour") + subtitleStyle.Render("  as of "+c.at.Format("15:04")),
+		"",
+		row("", label(a.agent), label(b.agent), "Δ"),
+		row("levels", m.renderTimeline(a.timeline), m.renderTimeline(b.timeline), ""),
+		row("active", pct(a), pct(b), activeDiff),
+		row("tools run", tools(a), tools(b), signed(b.tools-a.tools, "")),
+		row("tokens", tokens(a.agent), tokens(b.agent), signed(b.agent.TokensPerMin-a.agent.TokensPerMin, " tok/m")),
+		row("beads closed", fmt.Sprint(a.closed), fmt.Sprint(b.closed), signed(b.closed-a.closed, "")),
+		"",
+		helpStyle.Render("levels: 2 min per cell, oldest first · tokens: session total · spend rate · any key: close"),
+	}
+	return probeBoxStyle.Render(strings.Join(body, "\n"))
+}
+
+// formatTokenCount renders a token total compactly, e.g. "120k".
+func formatTokenCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM tok", float64(n)/1_000_000)
+	case n >= 1000:
+		return fmt.Sprintf("%dk tok", n/1000)
+	}
+	return fmt.Sprintf("%d tok", n)
+}
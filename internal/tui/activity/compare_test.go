@@ -0,0 +1,105 @@
+package activity
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestLedgerStats(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []LedgerEntry{
+		// Before the window: sets the level the window starts at.
+		{Time: now.Add(-2 * time.Hour), Session: "gt-gastown-Toast", Full: true, Fields: map[string]string{"level": "cold"}},
+		{Time: now.Add(-30 * time.Minute), Session: "gt-gastown-Toast", Fields: map[string]string{"level": "active", "tool": "Bash(make)"}},
+		{Time: now.Add(-20 * time.Minute), Session: "gt-gastown-Toast", Fields: map[string]string{"tool": "Edit(main.go)"}},
+		{Time: now.Add(-15 * time.Minute), Session: "gt-gastown-Toast", Fields: map[string]string{"tool": "Bash(go test)"}},
+		{Time: now.Add(-10 * time.Minute), Session: "gt-gastown-Nux", Fields: map[string]string{"level": "active", "tool": "Read(x)"}},
+	}
+
+	s := ledgerStats(entries, "gt-gastown-Toast", now)
+	if !s.known || s.active != 50 {
+		t.Errorf("active = %d%% (known %v), want 50%%: cold for the first half hour", s.active, s.known)
+	}
+	if s.timeline[0] != LevelCold || s.timeline[compareCells-1] != LevelActive {
+		t.Errorf("timeline = %v, want cold then active", s.timeline)
+	}
+	if s.tools != 3 || strings.Join(s.topTools, ",") != "Bash,Edit" {
+		t.Errorf("tools = %d %v, want 3 with Bash first", s.tools, s.topTools)
+	}
+
+	nux := ledgerStats(entries, "gt-gastown-Nux", now)
+	if nux.timeline[0] != levelUnknown || nux.active != 100 {
+		t.Errorf("nux timeline = %v active = %d, want unknown until its first entry, then all active", nux.timeline, nux.active)
+	}
+	if none := ledgerStats(entries, "gt-gastown-furiosa", now); none.known || none.tools != 0 {
+		t.Errorf("unrecorded session: %+v", none)
+	}
+}
+
+func TestTrackBeadsClosed(t *testing.T) {
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, events.EventsFile)
+	now := time.Now()
+	done := func(actor, bead string, at time.Time) events.Event {
+		return events.Event{Timestamp: at.UTC().Format(time.RFC3339), Type: events.TypeDone, Actor: actor, Payload: events.DonePayload(bead, "b")}
+	}
+	writeBeadEvents(t, path,
+		done("gastown/polecats/Toast", "gt-1", now.Add(-2*time.Hour)), // before the window
+		done("gastown/polecats/Toast", "gt-2", now.Add(-time.Minute)),
+		beadEvent(events.TypeBeadClosed, "gt-2", now.Add(-time.Minute)), // actor "beads"
+		done("gastown/polecats/Toast", "gt-3", now.Add(-time.Minute)),
+		done("gastown/polecats/Nux", "gt-4", now.Add(-time.Minute)),
+	)
+	m := &Model{townRoot: townRoot}
+	m.readEvents(now)
+	m.trackBeadsClosed(now)
+	closed := m.beadsClosedSince(now.Add(-compareWindow))
+	if n := len(closed["gastown/polecats/Toast"]); n != 2 {
+		t.Errorf("Toast closed %d, want 2", n)
+	}
+	if n := len(closed["gastown/polecats/Nux"]); n != 1 {
+		t.Errorf("Nux closed %d, want 1", n)
+	}
+}
+
+func TestToggleCompareHovered(t *testing.T) {
+	toast := &AgentLight{SessionName: "gt-gastown-Toast", Name: "Toast", AgentType: "claude", TokenCount: 120000, TokensPerMin: 900}
+	nux := &AgentLight{SessionName: "gt-gastown-Nux", Name: "Nux", AgentType: "opencode", TokensPerMin: 400}
+	m := &Model{width: 120, agents: []*AgentLight{toast, nux}}
+
+	m.hoveredAgent = toast
+	m.toggleCompareHovered()
+	if m.compareFirst != toast || m.compare != nil {
+		t.Fatalf("first v: compareFirst = %v, compare = %v", m.compareFirst, m.compare)
+	}
+	m.toggleCompareHovered() // same agent again cancels
+	if m.compareFirst != nil {
+		t.Fatal("v on the same agent didn't cancel")
+	}
+
+	m.toggleCompareHovered()
+	m.hoveredAgent = nux
+	m.toggleCompareHovered()
+	if m.compare == nil || m.compare.stats[0].agent != toast || m.compare.stats[1].agent != nux {
+		t.Fatalf("comparison = %+v, want Toast vs Nux", m.compare)
+	}
+
+	out := m.renderCompare()
+	for _, want := range []string{"compare · last hour", "Toast (claude)", "Nux (opencode)", "120k tok", "-500 tok/m", "no ledger"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("overlay missing %q:\n%s", want, out)
+		}
+	}
+
+	// Any key closes the overlay.
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if m.compare != nil {
+		t.Error("key press didn't close the overlay")
+	}
+}
@@ -0,0 +1,326 @@
+package activity
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// Town control screen (T): the town's declared topology, role by role (the
+// mayor and deacon, and each rig's witness, refinery and crew), against the
+// sessions actually running, like systemctl status for the town. s starts
+// what a role is missing, x stops it, and R reconciles: it starts every
+// missing session of every role at once. Polecats and dogs are dispatched
+// on demand, so they show what's running with nothing expected.
+
+// declaredRole is one role in the town's declared topology.
+type declaredRole struct {
+	rig      string   // "hq" for the mayor, deacon and dogs
+	role     string   // constants.Role*
+	expected []string // sessions that should be running; nil for on-demand roles
+	onDemand bool     // polecats and dogs: started by dispatch, never expected
+	offline  string   // "parked" or "docked" when the rig is: nothing is expected
+	start    func(missing []string) []string
+	stop     []string // gt arguments that stop the role; nil kills its sessions
+}
+
+// declaredTopology reads the roles the town declares: the mayor and deacon,
+// and each registered rig's witness, refinery and crew (one per workspace
+// under <rig>/crew).
+func declaredTopology(townRoot string, offline map[string]string) []declaredRole {
+	fixed := func(args ...string) func([]string) []string {
+		return func([]string) []string { return args }
+	}
+	roles := []declaredRole{
+		{rig: "hq", role: constants.RoleMayor, expected: []string{session.MayorSessionName()}, start: fixed("mayor", "start"), stop: []string{"mayor", "stop"}},
+		{rig: "hq", role: constants.RoleDeacon, expected: []string{session.DeaconSessionName()}, start: fixed("deacon", "start"), stop: []string{"deacon", "stop"}},
+		{rig: "hq", role: constants.RoleDog, onDemand: true},
+	}
+	rigs := session.DefaultRegistry().AllRigs()
+	for _, rig := range sortedRigs(rigs) {
+		rig, prefix := rig, rigs[rig]
+		status := offline[rig]
+		var crew []string
+		for _, name := range crewNames(townRoot, rig) {
+			crew = append(crew, session.CrewSessionName(prefix, name))
+		}
+		roles = append(roles,
+			declaredRole{rig: rig, role: constants.RoleWitness, expected: []string{session.WitnessSessionName(prefix)}, offline: status,
+				start: fixed("witness", "start", rig), stop: []string{"witness", "stop", rig}},
+			declaredRole{rig: rig, role: constants.RoleRefinery, expected: []string{session.RefinerySessionName(prefix)}, offline: status,
+				start: fixed("refinery", "start", rig), stop: []string{"refinery", "stop", rig}},
+			declaredRole{rig: rig, role: constants.RoleCrew, expected: crew, offline: status,
+				start: func(missing []string) []string {
+					args := []string{"crew", "start", rig}
+					for _, s := range missing {
+						args = append(args, strings.TrimPrefix(s, prefix+"-crew-"))
+					}
+					return args
+				},
+				stop: []string{"crew", "stop", rig}},
+			declaredRole{rig: rig, role: constants.RolePolecat, onDemand: true, offline: status},
+		)
+	}
+	return roles
+}
+
+// crewNames lists a rig's crew workspaces, sorted.
+func crewNames(townRoot, rig string) []string {
+	entries, err := os.ReadDir(filepath.Join(townRoot, rig, "crew"))
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// controlRow is a declared role with what's running for it.
+type controlRow struct {
+	declaredRole
+	running []*AgentLight
+	missing []string // expected sessions with none running
+}
+
+// label names the row in prompts, e.g. "gastown crew".
+func (r controlRow) label() string {
+	return r.rig + " " + r.role
+}
+
+// controlScreen is the town control overlay.
+type controlScreen struct {
+	roles []declaredRole // re-read on every poll while open
+	sel   int
+}
+
+// openControl opens the control screen. Town mode only: without a town
+// there is no declared topology.
+func (m *Model) openControl() {
+	if m.townRoot == "" {
+		m.flashMessage = "the control screen needs a town (tmux-only mode)"
+		m.flashTime = time.Now()
+		return
+	}
+	m.lastRigStatusPoll = time.Time{} // start from the rigs' current state
+	m.pollRigStatus(time.Now())
+	m.control = &controlScreen{roles: declaredTopology(m.townRoot, m.offlineRigs)}
+}
+
+// refreshControl re-reads the declared topology after a poll, so rigs
+// parked or docked and crew added show up while the screen is open.
+func (m *Model) refreshControl() {
+	if m.control != nil && m.townRoot != "" {
+		m.pollRigStatus(time.Now())
+		m.control.roles = declaredTopology(m.townRoot, m.offlineRigs)
+	}
+}
+
+// controlRows matches the declared roles to the running sessions. Parked
+// and docked rigs' roles list what's running but expect nothing.
+func (m *Model) controlRows() []controlRow {
+	running := make(map[string]bool, len(m.agents))
+	for _, a := range m.agents {
+		running[a.SessionName] = true
+	}
+	rows := make([]controlRow, 0, len(m.control.roles))
+	for _, d := range m.control.roles {
+		row := controlRow{declaredRole: d}
+		for _, a := range m.agents {
+			if a.Rig == d.rig && a.Role == d.role && a.Name != "overseer" {
+				row.running = append(row.running, a)
+			}
+		}
+		if d.offline == "" {
+			for _, s := range d.expected {
+				if !running[s] {
+					row.missing = append(row.missing, s)
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// handleControlKey handles a key while the control screen is open.
+func (m *Model) handleControlKey(key string) {
+	rows := m.controlRows()
+	c := m.control
+	switch key {
+	case "esc", "q", "T":
+		m.control = nil
+	case "up", "k":
+		if c.sel > 0 {
+			c.sel--
+		}
+	case "down", "j", "tab":
+		if c.sel < len(rows)-1 {
+			c.sel++
+		}
+	case "S":
+		if c.sel < len(rows) {
+			m.prepareRoleStart(rows[c.sel])
+		}
+	case "x":
+		if c.sel < len(rows) {
+			m.prepareRoleStop(rows[c.sel])
+		}
+	case "U":
+		m.prepareReconcile(rows)
+	}
+}
+
+// prepareRoleStart asks for confirmation to start what a role is missing.
+func (m *Model) prepareRoleStart(r controlRow) {
+	var reason string
+	switch {
+	case r.onDemand:
+		reason = r.role + "s start when work is dispatched to them"
+	case r.offline != "":
+		// gt rig unpark / gt rig undock
+		reason = r.rig + " is " + r.offline + "; gt rig un" + strings.TrimSuffix(r.offline, "ed") + " " + r.rig + " first"
+	case len(r.missing) == 0:
+		reason = "nothing missing for " + r.label()
+	}
+	if reason != "" {
+		m.flashMessage = reason
+		m.flashTime = time.Now()
+		return
+	}
+	args := r.start(r.missing)
+	m.pendingBulk = &bulkAction{
+		verb:     "start",
+		sessions: []string{r.label()},
+		commands: map[string][]string{r.label(): args},
+		prompt:   "start " + r.label() + " (" + cliCommand(args) + ")",
+	}
+}
+
+// prepareRoleStop asks for confirmation to stop a role's running sessions:
+// with its stop command when it has one, else by killing them.
+func (m *Model) prepareRoleStop(r controlRow) {
+	if len(r.running) == 0 {
+		m.flashMessage = "nothing running for " + r.label()
+		m.flashTime = time.Now()
+		return
+	}
+	if r.stop == nil {
+		b := &bulkAction{verb: "kill", agents: r.running, prompt: fmt.Sprintf("kill %d %s", len(r.running), r.label())}
+		for _, a := range r.running {
+			b.sessions = append(b.sessions, a.SessionName)
+		}
+		m.pendingBulk = b
+		return
+	}
+	m.pendingBulk = &bulkAction{
+		verb:     "stop",
+		sessions: []string{r.label()},
+		commands: map[string][]string{r.label(): r.stop},
+		prompt:   "stop " + r.label() + " (" + cliCommand(r.stop) + ")",
+	}
+}
+
+// prepareReconcile asks for confirmation to start every missing session of
+// every role not parked or docked.
+func (m *Model) prepareReconcile(rows []controlRow) {
+	b := &bulkAction{verb: "start", commands: make(map[string][]string)}
+	var parts []string
+	for _, r := range rows {
+		if len(r.missing) == 0 || r.start == nil {
+			continue
+		}
+		b.sessions = append(b.sessions, r.label())
+		b.commands[r.label()] = r.start(r.missing)
+		part := r.label()
+		if len(r.missing) > 1 {
+			part += fmt.Sprintf(" (%d)", len(r.missing))
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		m.flashMessage = "nothing to reconcile: every declared session is running"
+		m.flashTime = time.Now()
+		return
+	}
+	b.prompt = "reconcile: start " + strings.Join(parts, ", ")
+	m.pendingBulk = b
+}
+
+// controlState renders a row's state, e.g. "missing 2: joe, max".
+func (r controlRow) state() (string, lipgloss.Style) {
+	switch {
+	case r.offline != "" && len(r.running) > 0:
+		return fmt.Sprintf("%s · %d still running", r.offline, len(r.running)), statRateLimitedStyle
+	case r.offline != "":
+		return r.offline, statusDimStyle
+	case r.onDemand:
+		return "on demand", statusDimStyle
+	case len(r.missing) > 0:
+		var names []string
+		for _, s := range r.missing {
+			names = append(names, controlName(s))
+		}
+		return fmt.Sprintf("missing %d: %s", len(r.missing), strings.Join(names, ", ")), statWaitingStyle
+	case len(r.running) > len(r.expected):
+		return fmt.Sprintf("ok · %d undeclared", len(r.running)-len(r.expected)), statusDimStyle
+	}
+	return "ok", statActiveStyle
+}
+
+// controlName shortens a missing session to its agent name.
+func controlName(s string) string {
+	a := &AgentLight{SessionName: s}
+	parseSessionName(a)
+	return a.Name
+}
+
+// renderControl renders the control screen overlay shown in place of the
+// panels.
+func (m *Model) renderControl() string {
+	rows := m.controlRows()
+	c := m.control
+	if c.sel >= len(rows) {
+		c.sel = max(len(rows)-1, 0)
+	}
+	missing := 0
+	for _, r := range rows {
+		missing += len(r.missing)
+	}
+	summary := "every declared session is running"
+	if missing > 0 {
+		summary = fmt.Sprintf("%d declared sessions not running", missing)
+	}
+	lines := []string{titleStyle.Render("town control") + "  " + subtitleStyle.Render(summary), ""}
+	lines = append(lines, helpStyle.Render(fmt.Sprintf("%-12s %-9s %7s  %s", "rig", "role", "run/exp", "state")))
+	for i, r := range rows {
+		exp := fmt.Sprint(len(r.expected))
+		if r.onDemand {
+			exp = "-"
+		} else if r.offline != "" {
+			exp = "0"
+		}
+		state, style := r.state()
+		line := fmt.Sprintf("%-12s %-9s %7s  ", truncateWidth(r.rig, 12, "…"), r.role, fmt.Sprintf("%d/%s", len(r.running), exp))
+		state = truncateWidth(state, max(m.width-8-len(line), 12), "…")
+		if i == c.sel {
+			line = switcherSelectedStyle.Render(line + state)
+		} else {
+			line += style.Render(state)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", helpStyle.Render("S: start missing · x: stop · U: reconcile (start everything missing) · ↑/↓: move · esc: close"))
+	return probeBoxStyle.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,140 @@
+package activity
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// controlTown registers rigs gastown, beads (parked) and wyvern (docked)
+// and gives gastown two crew workspaces, returning the town root.
+func controlTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	withRigs(t, map[string]string{"gastown": "gt", "beads": "bd", "wyvern": "wy"})
+	withRigStatus(t, map[string]string{"beads": "parked", "wyvern": "docked"})
+	for _, name := range []string{"joe", "max", ".git"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, "gastown", "crew", name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return townRoot
+}
+
+// controlAgents is the mayor, gastown's witness, crew joe and polecat
+// Toast, and the parked beads rig's witness.
+func controlAgents() []*AgentLight {
+	return sessionAgents("hq-mayor", "gt-witness", "gt-crew-joe", "gt-Toast", "bd-witness")
+}
+
+func findRow(rows []controlRow, rig, role string) controlRow {
+	for _, r := range rows {
+		if r.rig == rig && r.role == role {
+			return r
+		}
+	}
+	return controlRow{}
+}
+
+func TestControlRows(t *testing.T) {
+	townRoot := controlTown(t) // registers the rigs controlAgents are placed in
+	m := testModel(controlAgents(), modelTown(townRoot), modelSize(160, 40))
+	m.openControl()
+	rows := m.controlRows()
+
+	if r := findRow(rows, "hq", "deacon"); strings.Join(r.missing, " ") != "hq-deacon" {
+		t.Errorf("deacon missing = %v", r.missing)
+	}
+	crew := findRow(rows, "gastown", "crew")
+	if len(crew.expected) != 2 || len(crew.running) != 1 || strings.Join(crew.missing, " ") != "gt-crew-max" {
+		t.Errorf("crew = expected %v, running %d, missing %v", crew.expected, len(crew.running), crew.missing)
+	}
+	if got := strings.Join(crew.start(crew.missing), " "); got != "crew start gastown max" {
+		t.Errorf("crew start = %q", got)
+	}
+	if r := findRow(rows, "gastown", "polecat"); !r.onDemand || len(r.running) != 1 || len(r.missing) != 0 {
+		t.Errorf("polecats = %+v, want Toast running on demand", r)
+	}
+	parked := findRow(rows, "beads", "witness")
+	if len(parked.missing) != 0 {
+		t.Errorf("parked rig missing %v, want nothing expected", parked.missing)
+	}
+	if state, _ := parked.state(); state != "parked · 1 still running" {
+		t.Errorf("parked witness state = %q", state)
+	}
+	docked := findRow(rows, "wyvern", "refinery")
+	if state, _ := docked.state(); len(docked.missing) != 0 || state != "docked" {
+		t.Errorf("docked refinery = missing %v, state %q; want nothing expected", docked.missing, state)
+	}
+	m.prepareRoleStart(docked)
+	if m.pendingBulk != nil || !strings.Contains(m.flashMessage, "gt rig undock wyvern") {
+		t.Errorf("start on a docked rig: pending %+v, flash %q", m.pendingBulk, m.flashMessage)
+	}
+
+	view := m.renderControl()
+	for _, want := range []string{"town control", "3 declared sessions not running", "missing 1: max", "on demand"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestControlKeys(t *testing.T) {
+	townRoot := controlTown(t) // registers the rigs controlAgents are placed in
+	m := testModel(controlAgents(), modelTown(townRoot), modelSize(160, 40))
+	typeKeys(m, "T")
+	if m.control == nil {
+		t.Fatal("T didn't open the control screen")
+	}
+
+	// Reconcile starts everything missing outside parked and docked rigs.
+	typeKeys(m, "U")
+	b := m.pendingBulk
+	if b == nil || b.verb != "start" {
+		t.Fatalf("U: pending = %+v, want a start", b)
+	}
+	if got := strings.Join(b.sessions, ", "); got != "hq deacon, gastown refinery, gastown crew" {
+		t.Errorf("reconcile targets = %q", got)
+	}
+	if got := strings.Join(b.commands["gastown refinery"], " "); got != "refinery start gastown" {
+		t.Errorf("refinery command = %q", got)
+	}
+	typeKeys(m, "n")
+	if m.pendingBulk != nil || m.control == nil {
+		t.Fatal("cancelling the reconcile closed the screen or left it pending")
+	}
+
+	// The first row is the mayor, running: S has nothing to start, x stops it.
+	typeKeys(m, "S")
+	if m.pendingBulk != nil || !strings.Contains(m.flashMessage, "nothing missing") {
+		t.Errorf("S on a running mayor: pending %+v, flash %q", m.pendingBulk, m.flashMessage)
+	}
+	typeKeys(m, "x")
+	if b := m.pendingBulk; b == nil || b.verb != "stop" || strings.Join(b.commands["hq mayor"], " ") != "mayor stop" {
+		t.Fatalf("x on the mayor: pending = %+v", b)
+	}
+	typeKeys(m, "n")
+
+	// Polecats have no stop command: x kills their sessions.
+	rows := m.controlRows()
+	for i, r := range rows {
+		if r.rig == "gastown" && r.role == "polecat" {
+			m.control.sel = i
+		}
+	}
+	typeKeys(m, "x")
+	if b := m.pendingBulk; b == nil || b.verb != "kill" || strings.Join(b.sessions, " ") != "gt-Toast" {
+		t.Fatalf("x on polecats: pending = %+v", b)
+	}
+	typeKeys(m, "n", "esc")
+	if m.control != nil {
+		t.Error("esc didn't close the control screen")
+	}
+
+	m.townRoot = ""
+	typeKeys(m, "T")
+	if m.control != nil {
+		t.Error("control screen opened without a town")
+	}
+}
@@ -0,0 +1,203 @@
+package activity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// noGroupTitle heads the grouped-view panel of agents outside any convoy or
+// epic.
+const noGroupTitle = "no convoy"
+
+// workGroup is a convoy or epic whose beads agents are hooked to. Progress
+// counts the convoy's tracked beads, or the epic's children.
+type workGroup struct {
+	id    string
+	kind  string // "convoy" or "epic"
+	title string
+	done  int
+	total int
+}
+
+// groupID returns the convoy the agent's work belongs to, else its epic,
+// else "".
+func (a *AgentLight) groupID() string {
+	if a.ConvoyID != "" {
+		return a.ConvoyID
+	}
+	return a.EpicID
+}
+
+// newWorkGroup reads a convoy or epic bead. Convoys count the beads they
+// track; epics their children.
+func newWorkGroup(kind string, issue *beads.Issue) *workGroup {
+	g := &workGroup{id: issue.ID, kind: kind, title: issue.Title}
+	for _, d := range groupMembers(kind, issue) {
+		g.total++
+		if beads.IssueStatus(d.Status).IsTerminal() {
+			g.done++
+		}
+	}
+	return g
+}
+
+// groupMembers returns the beads a convoy tracks, or an epic's children.
+func groupMembers(kind string, issue *beads.Issue) []beads.IssueDep {
+	deps, depType := issue.Dependencies, "tracks"
+	if kind == "epic" {
+		deps, depType = issue.Dependents, "parent-child"
+	}
+	var out []beads.IssueDep
+	for _, d := range deps {
+		if d.DependencyType == depType {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// resolveEpics keeps each agent's hook-bead parent as its epic when the
+// parent is an epic, and records the epics as work groups. parents maps
+// agents to their hook bead's parent ID, fetched from the rig's beads.
+func (m *Model) resolveEpics(b *beads.Beads, parents map[*AgentLight]string, groups map[string]*workGroup) {
+	ids := make([]string, 0, len(parents))
+	seen := make(map[string]bool)
+	for _, id := range parents {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	issues, _ := b.ShowMultiple(ids)
+	for a, id := range parents {
+		issue := issues[id]
+		if issue == nil || issue.Type != "epic" {
+			a.EpicID = ""
+			continue
+		}
+		a.EpicID = id
+		if groups[id] == nil {
+			groups[id] = newWorkGroup("epic", issue)
+		}
+	}
+}
+
+// resolveConvoys records the convoys agents' work belongs to as work
+// groups, read from the town beads where convoys live. A convoy that can't
+// be read still groups its agents, by ID alone.
+func (m *Model) resolveConvoys(groups map[string]*workGroup) {
+	var ids []string
+	for _, a := range m.agents {
+		if a.ConvoyID != "" && groups[a.ConvoyID] == nil {
+			groups[a.ConvoyID] = &workGroup{id: a.ConvoyID, kind: "convoy"}
+			ids = append(ids, a.ConvoyID)
+		}
+	}
+	hqDir, ok := m.rigBeadsDirs["hq"]
+	if len(ids) == 0 || !ok {
+		return
+	}
+	issues, _ := beads.New(hqDir).ShowMultiple(ids)
+	for _, id := range ids {
+		if issue := issues[id]; issue != nil {
+			groups[id] = newWorkGroup("convoy", issue)
+		}
+	}
+}
+
+// header renders the group's panel title, e.g.
+// "convoy hq-cv-abc: Auth rewrite · 3/7 done".
+func (g *workGroup) header() string {
+	s := g.kind + " " + g.id
+	if g.title != "" {
+		s += ": " + g.title
+	}
+	if g.total > 0 {
+		s += fmt.Sprintf(" · %d/%d done", g.done, g.total)
+	}
+	return s
+}
+
+// SetGroupByConvoy starts gt top with agents grouped by convoy or epic
+// instead of by rig (the g key toggles it).
+func (m *Model) SetGroupByConvoy(on bool) {
+	m.groupByConvoy = on
+}
+
+// toggleGroupByConvoy switches between rig panels and convoy panels.
+func (m *Model) toggleGroupByConvoy() {
+	m.groupByConvoy = !m.groupByConvoy
+	if m.groupByConvoy {
+		m.flashMessage = "grouped by convoy/epic (g: back to rigs)"
+	} else {
+		m.flashMessage = "grouped by rig"
+	}
+	m.flashTime = time.Now()
+}
+
+// groupOrder returns the IDs of the groups shown agents work on, convoys
+// before epics, then by ID, with "" (no group) last.
+func (m *Model) groupOrder() []string {
+	seen := make(map[string]bool)
+	var ids []string
+	none := false
+	for _, a := range m.filteredAgents() {
+		id := a.groupID()
+		switch {
+		case id == "":
+			none = true
+		case !seen[id]:
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	kind := func(id string) string {
+		if g := m.groups[id]; g != nil {
+			return g.kind
+		}
+		return ""
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if ki, kj := kind(ids[i]), kind(ids[j]); ki != kj {
+			return ki == "convoy"
+		}
+		return ids[i] < ids[j]
+	})
+	if none {
+		ids = append(ids, "")
+	}
+	return ids
+}
+
+// agentsForGroup returns the shown agents working on the group, in rig
+// display order. "" selects agents outside any group.
+func (m *Model) agentsForGroup(id string) []*AgentLight {
+	var out []*AgentLight
+	for _, a := range m.filteredAgents() {
+		if a.groupID() == id {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// groupHeader renders the panel header for a group ID.
+func (m *Model) groupHeader(id string) string {
+	if id == "" {
+		return rigHeaderStyle.Render(noGroupTitle)
+	}
+	g := m.groups[id]
+	if g == nil {
+		g = &workGroup{id: id, kind: "convoy"}
+	}
+	return rigHeaderStyle.Render(m.workHeader(g))
+}
+
+// renderGroupWithPositions renders one convoy/epic panel. Pinned agents are
+// shown in the pinned section instead.
+func (m *Model) renderGroupWithPositions(id string, currentY *int) string {
+	return m.renderPanelWithPositions(m.groupHeader(id), m.unpinnedAgents(m.agentsForGroup(id)), currentY)
+}
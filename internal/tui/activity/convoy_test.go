@@ -0,0 +1,108 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestNewWorkGroup_Progress(t *testing.T) {
+	convoy := &beads.Issue{ID: "hq-cv-abc", Title: "Auth rewrite", Dependencies: []beads.IssueDep{
+		{ID: "gt-1", Status: "closed", DependencyType: "tracks"},
+		{ID: "gt-2", Status: "in_progress", DependencyType: "tracks"},
+		{ID: "gt-3", Status: "open", DependencyType: "tracks"},
+		{ID: "gt-4", Status: "closed", DependencyType: "blocks"}, // not tracked
+	}}
+	g := newWorkGroup("convoy", convoy)
+	if g.done != 1 || g.total != 3 {
+		t.Errorf("convoy progress = %d/%d, want 1/3", g.done, g.total)
+	}
+	if got, want := g.header(), "convoy hq-cv-abc: Auth rewrite · 1/3 done"; got != want {
+		t.Errorf("header = %q, want %q", got, want)
+	}
+
+	epic := &beads.Issue{ID: "gt-epic", Title: "Login", Dependents: []beads.IssueDep{
+		{ID: "gt-5", Status: "closed", DependencyType: "parent-child"},
+		{ID: "gt-6", Status: "closed", DependencyType: "parent-child"},
+	}}
+	if g := newWorkGroup("epic", epic); g.done != 2 || g.total != 2 {
+		t.Errorf("epic progress = %d/%d, want 2/2", g.done, g.total)
+	}
+}
+
+// convoyAgents are two polecats on one convoy across rigs, a crew member on
+// an epic and a witness on neither.
+func convoyAgents() []*AgentLight {
+	return []*AgentLight{
+		{SessionName: "gt-gastown-Toast", Name: "Toast", Rig: "gastown", Role: "polecat", ConvoyID: "hq-cv-abc"},
+		{SessionName: "gp-greenplace-Nux", Name: "Nux", Rig: "greenplace", Role: "polecat", ConvoyID: "hq-cv-abc"},
+		{SessionName: "gt-gastown-crew-max", Name: "max", Rig: "gastown", Role: "crew", EpicID: "gt-epic"},
+		{SessionName: "gt-gastown-witness", Name: "witness", Rig: "gastown", Role: "witness"},
+	}
+}
+
+// convoyGroups are the convoy and epic convoyAgents work under.
+func convoyGroups() map[string]*workGroup {
+	return map[string]*workGroup{
+		"hq-cv-abc": {id: "hq-cv-abc", kind: "convoy", title: "Auth rewrite", done: 1, total: 3},
+		"gt-epic":   {id: "gt-epic", kind: "epic", title: "Login"},
+	}
+}
+
+func TestGroupOrder(t *testing.T) {
+	m := testModel(convoyAgents(), modelGroups(convoyGroups()))
+	got := m.groupOrder()
+	want := []string{"hq-cv-abc", "gt-epic", ""}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("groupOrder = %q, want %q", got, want)
+	}
+	if n := len(m.agentsForGroup("hq-cv-abc")); n != 2 {
+		t.Errorf("agents on convoy = %d, want 2 (across rigs)", n)
+	}
+}
+
+func TestConvoyFilter(t *testing.T) {
+	m := testModel(convoyAgents(), modelGroups(convoyGroups()))
+	m.SetConvoyFilter("hq-cv-abc")
+	if err := m.SetAgentFilter("", "polecat", ""); err != nil {
+		t.Fatal(err)
+	}
+	if m.view.convoy != "hq-cv-abc" {
+		t.Fatalf("SetAgentFilter dropped the convoy filter: %+v", m.view)
+	}
+	if n := len(m.filteredAgents()); n != 2 {
+		t.Errorf("filtered agents = %d, want 2", n)
+	}
+	if got, want := m.view.describe(2), "2 polecats on hq-cv-abc"; got != want {
+		t.Errorf("describe = %q, want %q", got, want)
+	}
+	if got := m.view.String(); !strings.Contains(got, "convoy=hq-cv-abc") {
+		t.Errorf("filter line = %q", got)
+	}
+
+	m.SetConvoyFilter("gt-epic")
+	m.view.role = ""
+	if got := m.filteredAgents(); len(got) != 1 || got[0].Name != "max" {
+		t.Errorf("epic filter = %v, want max", got)
+	}
+}
+
+func TestRender_GroupByConvoy(t *testing.T) {
+	m := testModel(convoyAgents(), modelGroups(convoyGroups()))
+	m.toggleGroupByConvoy()
+	out := m.render()
+	for _, want := range []string{"convoy hq-cv-abc: Auth rewrite · 1/3 done", "epic gt-epic: Login", noGroupTitle} {
+		if !strings.Contains(out, want) {
+			t.Errorf("grouped view missing %q", want)
+		}
+	}
+	if strings.Contains(out, "greenplace") {
+		t.Error("grouped view still shows rig panels")
+	}
+
+	m.toggleGroupByConvoy()
+	if out := m.render(); !strings.Contains(out, "greenplace") {
+		t.Error("toggling back did not restore rig panels")
+	}
+}
@@ -0,0 +1,103 @@
+package activity
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/cli"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Double-click actions (town settings top.double_click): what double-clicking
+// an agent opens, per role. Attaching suits crew and polecats, but an
+// infrastructure agent's pane is mostly patrol chatter, so by default the
+// refinery opens its merge queue and the witness and deacon the problems
+// view. Alt+double-click always attaches.
+
+// clickAction is what double-clicking an agent does.
+type clickAction string
+
+const (
+	clickAttach clickAction = "attach" // a terminal attached to the session
+	clickQueue  clickAction = "queue"  // the rig's merge queue, refreshed
+	clickPatrol clickAction = "patrol" // gt feed's problems view for the rig
+	clickNone   clickAction = "none"   // nothing
+)
+
+// queueRefreshSeconds is how often the merge queue view re-reads the queue.
+const queueRefreshSeconds = 5
+
+// defaultClickActions are the roles that don't attach on double-click.
+var defaultClickActions = map[string]clickAction{
+	constants.RoleRefinery: clickQueue,
+	constants.RoleWitness:  clickPatrol,
+	constants.RoleDeacon:   clickPatrol,
+}
+
+// parseClickActions merges the configured role -> action map over the
+// defaults. Unknown actions are ignored, keeping the role's default.
+func parseClickActions(cfg map[string]string) map[string]clickAction {
+	actions := make(map[string]clickAction, len(defaultClickActions)+len(cfg))
+	for role, act := range defaultClickActions {
+		actions[role] = act
+	}
+	for role, act := range cfg {
+		switch a := clickAction(act); a {
+		case clickAttach, clickQueue, clickPatrol, clickNone:
+			actions[role] = a
+		}
+	}
+	return actions
+}
+
+// clickActionFor returns what double-clicking the agent does.
+func (m *Model) clickActionFor(a *AgentLight) clickAction {
+	actions := m.clickActions
+	if actions == nil {
+		actions = defaultClickActions
+	}
+	if act, ok := actions[a.Role]; ok {
+		return act
+	}
+	return clickAttach
+}
+
+// doubleClick runs the agent's double-click action; alt attaches whatever
+// the role's action.
+func (m *Model) doubleClick(a *AgentLight, alt bool) {
+	act := m.clickActionFor(a)
+	if alt || m.townRoot == "" || a.inContainer() {
+		act = clickAttach
+	}
+	switch act {
+	case clickNone:
+		return
+	case clickQueue, clickPatrol:
+		cmd, label := viewCommand(m.townRoot, act, a.Rig)
+		m.touchedByHuman(a.SessionName)
+		m.openTerminal(cmd, label)
+	default:
+		m.attachTo(a)
+	}
+}
+
+// viewCommand returns the command a terminal runs for a queue or patrol
+// view of rig, run from the town root, and a label for the flash message.
+func viewCommand(townRoot string, act clickAction, rig string) (cmd, label string) {
+	gt := cli.Name()
+	var script string
+	switch act {
+	case clickQueue:
+		label = "merge queue " + rig
+		script = fmt.Sprintf("cd %s && while :; do clear; %s refinery queue %s; sleep %d; done",
+			config.ShellQuote(townRoot), gt, config.ShellQuote(rig), queueRefreshSeconds)
+	default:
+		label = "problems"
+		script = fmt.Sprintf("cd %s && exec %s feed --problems", config.ShellQuote(townRoot), gt)
+		if rig != "" && rig != "hq" {
+			label += " " + rig
+			script += " --rig " + config.ShellQuote(rig)
+		}
+	}
+	return "sh -c " + config.ShellQuote(script), label
+}
@@ -0,0 +1,62 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+func TestClickActionFor(t *testing.T) {
+	crew := &AgentLight{Role: constants.RoleCrew}
+	refinery := &AgentLight{Role: constants.RoleRefinery}
+	witness := &AgentLight{Role: constants.RoleWitness}
+
+	m := &Model{}
+	for _, tt := range []struct {
+		a    *AgentLight
+		want clickAction
+	}{
+		{crew, clickAttach},
+		{refinery, clickQueue},
+		{witness, clickPatrol},
+	} {
+		if got := m.clickActionFor(tt.a); got != tt.want {
+			t.Errorf("default action for %s = %q, want %q", tt.a.Role, got, tt.want)
+		}
+	}
+
+	m.clickActions = parseClickActions(map[string]string{
+		constants.RoleRefinery: "attach",
+		constants.RoleCrew:     "none",
+		constants.RoleWitness:  "bogus", // ignored: keeps the default
+	})
+	if got := m.clickActionFor(refinery); got != clickAttach {
+		t.Errorf("configured refinery action = %q, want attach", got)
+	}
+	if got := m.clickActionFor(crew); got != clickNone {
+		t.Errorf("configured crew action = %q, want none", got)
+	}
+	if got := m.clickActionFor(witness); got != clickPatrol {
+		t.Errorf("witness with unknown action = %q, want the default patrol", got)
+	}
+}
+
+func TestViewCommand(t *testing.T) {
+	cmd, label := viewCommand("/town", clickQueue, "gastown")
+	if label != "merge queue gastown" || !strings.Contains(cmd, "refinery queue gastown") || !strings.HasPrefix(cmd, "sh -c ") {
+		t.Errorf("queue view = %q (%s)", cmd, label)
+	}
+
+	cmd, label = viewCommand("/my town", clickPatrol, "gastown")
+	if label != "problems gastown" || !strings.Contains(cmd, "feed --problems --rig gastown") {
+		t.Errorf("patrol view = %q (%s)", cmd, label)
+	}
+	if !strings.Contains(cmd, `'\''/my town'\''`) {
+		t.Errorf("town root not quoted inside the script: %q", cmd)
+	}
+
+	if _, label := viewCommand("/town", clickPatrol, "hq"); label != "problems" {
+		t.Errorf("deacon patrol view label = %q, want town-wide problems", label)
+	}
+}
@@ -0,0 +1,40 @@
+package activity
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tmuxOnlyMissing lists what gt top cannot show without a town root. Tmux
+// polling and pane parsing keep working; everything read from the town's
+// files does not. Without rigs.json the prefix registry is empty, so only
+// hq- sessions (mayor, deacon) on the default tmux socket are recognized.
+var tmuxOnlyMissing = []string{
+	"rig agents",
+	"events (tool activity, comms, loops, limits)",
+	"beads (work context)",
+	"SLAs",
+}
+
+var degradedBannerStyle = lipgloss.NewStyle().
+	Foreground(colorRateLimited)
+
+// tmuxOnly reports whether gt top is running without a town root.
+func (m *Model) tmuxOnly() bool {
+	return m.townRoot == ""
+}
+
+// renderDegradedBanner renders the tmux-only warning shown under the header,
+// or "" when a town root is available.
+func (m *Model) renderDegradedBanner() string {
+	if !m.tmuxOnly() {
+		return ""
+	}
+	style := degradedBannerStyle
+	if m.width > 4 {
+		style = style.MaxWidth(m.width - 4) // one line, so hover rows stay aligned
+	}
+	return style.Render("⚠ tmux-only mode: no town root (use --town or GT_TOWN_ROOT) · missing: " +
+		strings.Join(tmuxOnlyMissing, ", "))
+}
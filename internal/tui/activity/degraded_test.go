@@ -0,0 +1,43 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestRenderDegradedBanner(t *testing.T) {
+	m := &Model{townRoot: "/town", width: 200}
+	if got := m.renderDegradedBanner(); got != "" {
+		t.Errorf("banner with town root = %q, want empty", got)
+	}
+
+	m.townRoot = ""
+	got := m.renderDegradedBanner()
+	for _, want := range []string{"tmux-only mode", "--town", "events", "beads"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("banner missing %q: %q", want, got)
+		}
+	}
+
+	m.width = 40
+	if h := lipgloss.Height(m.renderDegradedBanner()); h != 1 {
+		t.Errorf("narrow banner height = %d, want 1", h)
+	}
+}
+
+func TestRender_DegradedBannerShiftsHoverRows(t *testing.T) {
+	a := &AgentLight{SessionName: "hq-mayor", Name: "mayor", Rig: "town"}
+	m := &Model{width: 120, height: 40, agents: []*AgentLight{a}, totalAgents: 1, rigs: []string{"town"}}
+
+	m.townRoot = "/town"
+	m.render()
+	withTown := a.renderY
+
+	m.townRoot = ""
+	m.render()
+	if a.renderY != withTown+1 {
+		t.Errorf("renderY in tmux-only mode = %d, want %d", a.renderY, withTown+1)
+	}
+}
@@ -0,0 +1,301 @@
+package activity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// Container labels read by the Docker source. An agent container opts in
+// with gastown.session, whose value is the session name it would have under
+// tmux (e.g. "gt-gastown-Toast"), so it lands in the right rig and role.
+const (
+	DockerSessionLabel   = "gastown.session"   // required: the agent's session name
+	DockerAgentLabel     = "gastown.agent"     // optional: agent type (claude, opencode, ...)
+	DockerHeartbeatLabel = "gastown.heartbeat" // optional: path of the agent's heartbeat file inside the container
+)
+
+const (
+	dockerTimeout = 2 * time.Second  // per docker CLI call
+	dockerBackoff = 30 * time.Second // how long an unreachable docker daemon is left alone
+)
+
+// dockerSource discovers agents running in Docker containers and reads their
+// status: the tail of the container's log stream stands in for the pane,
+// and a heartbeat file (gt heartbeat, read with docker exec) supplies the
+// self-reported state. Like openCodeSource, it is only used from the poll
+// command, which never runs concurrently with itself.
+type dockerSource struct {
+	// run executes the docker CLI; swapped in tests.
+	run  func(ctx context.Context, args ...string) ([]byte, error)
+	down time.Time // docker ps failed; retry after this
+	err  error     // why docker ps last failed, nil once it works again
+}
+
+func newDockerSource() *dockerSource {
+	return &dockerSource{run: runDocker}
+}
+
+// runDocker runs a docker CLI command, returning stdout and stderr
+// together: docker logs replays the container's stderr on its own.
+func runDocker(ctx context.Context, args ...string) ([]byte, error) {
+	out, err := exec.CommandContext(ctx, "docker", args...).CombinedOutput()
+	if err != nil && ctx.Err() != nil {
+		return nil, fmt.Errorf("docker %s: %w", args[0], ctx.Err())
+	}
+	return out, err
+}
+
+// EnableDocker makes gt top also monitor agents in labelled Docker containers.
+func (m *Model) EnableDocker() {
+	if m.docker == nil {
+		m.docker = newDockerSource()
+	}
+}
+
+// agentContainer is one running container labelled as an agent.
+type agentContainer struct {
+	id, session, agentType, heartbeat string
+	created                           time.Time
+}
+
+// list returns the running agent containers.
+func (s *dockerSource) list() ([]agentContainer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dockerTimeout)
+	defer cancel()
+	format := fmt.Sprintf(`{{.ID}}\t{{.Label %q}}\t{{.Label %q}}\t{{.Label %q}}\t{{.CreatedAt}}`,
+		DockerSessionLabel, DockerAgentLabel, DockerHeartbeatLabel)
+	out, err := s.run(ctx, "ps", "--filter", "label="+DockerSessionLabel, "--format", format)
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []agentContainer
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		f := strings.Split(line, "\t")
+		if len(f) < 5 || f[0] == "" || f[1] == "" {
+			continue
+		}
+		c := agentContainer{id: f[0], session: f[1], agentType: f[2], heartbeat: f[3]}
+		c.created, _ = time.Parse("2006-01-02 15:04:05 -0700 MST", f[4])
+		containers = append(containers, c)
+	}
+	return containers, nil
+}
+
+// poll returns a session for each agent container, skipping session names
+// tmux already has. depths is the log tail to read per session.
+func (s *dockerSource) poll(skip map[string]bool, depths map[string]int, now time.Time) []sessionInfo {
+	if now.Before(s.down) {
+		return nil
+	}
+	containers, err := s.list()
+	s.err = err
+	if err != nil {
+		s.down = now.Add(dockerBackoff)
+		return nil
+	}
+
+	var out []sessionInfo
+	var heartbeats []string
+	for _, c := range containers {
+		if skip[c.session] || !session.IsKnownSession(c.session) {
+			continue
+		}
+		skip[c.session] = true
+		si := sessionInfo{name: c.session, container: c.id, agentType: c.agentType, captureDepth: depths[c.session]}
+		if !c.created.IsZero() {
+			si.created = c.created.Unix()
+		}
+		out = append(out, si)
+		heartbeats = append(heartbeats, c.heartbeat)
+	}
+
+	// One container's slow docker exec shouldn't hold up the others.
+	var wg sync.WaitGroup
+	for i := range out {
+		wg.Add(1)
+		go func(si *sessionInfo, heartbeat string) {
+			defer wg.Done()
+			s.read(si, heartbeat)
+		}(&out[i], heartbeats[i])
+	}
+	wg.Wait()
+	return out
+}
+
+// read fills in a container session from docker logs and docker exec.
+func (s *dockerSource) read(si *sessionInfo, heartbeat string) {
+	ctx, cancel := context.WithTimeout(context.Background(), dockerTimeout)
+	defer cancel()
+	readContainerAgent(si,
+		func() ([]byte, error) {
+			return s.run(ctx, "logs", "--timestamps", "--tail", fmt.Sprint(si.depth()), si.container)
+		},
+		func() ([]byte, error) {
+			if heartbeat == "" {
+				return nil, nil
+			}
+			return s.run(ctx, "exec", si.container, "cat", heartbeat)
+		})
+}
+
+// readContainerAgent fills in a container or pod session's log tail,
+// heartbeat, and activity: the later of the last log line and the
+// heartbeat, else the container's start. logs returns timestamped log
+// lines; heartbeat returns the heartbeat file, or nil when there is none. A
+// failed log read leaves the session stale, like a wedged pane.
+func readContainerAgent(si *sessionInfo, logs, heartbeat func() ([]byte, error)) {
+	var last time.Time
+	out, err := logs()
+	if err != nil {
+		si.captureStale, si.staleSince = true, time.Now()
+	} else {
+		si.paneLines, last = parseDockerLogs(out)
+	}
+
+	if out, err := heartbeat(); err == nil && out != nil {
+		var hb polecat.SessionHeartbeat
+		if json.Unmarshal(out, &hb) == nil && !hb.Timestamp.IsZero() {
+			si.heartbeat = &hb
+			if hb.Timestamp.After(last) {
+				last = hb.Timestamp
+			}
+		}
+	}
+
+	si.activity = si.created
+	if !last.IsZero() {
+		si.activity = last.Unix()
+	}
+}
+
+// parseDockerLogs splits "docker logs --timestamps" (or kubectl logs
+// --timestamps) output into plain lines
+// and the time of the last one. A TTY's escape sequences are stripped,
+// carriage-return redraws keep only what was drawn last, and lines are
+// cleaned as pane captures are.
+func parseDockerLogs(out []byte) ([]string, time.Time) {
+	var lines []string
+	var last time.Time
+	for _, raw := range bytes.Split(bytes.TrimRight(out, "\n"), []byte("\n")) {
+		line := string(raw)
+		if ts, rest, ok := strings.Cut(line, " "); ok {
+			if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+				last, line = t, rest
+			}
+		}
+		line = strings.TrimRight(line, "\r")
+		if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+			line = line[i+1:]
+		}
+		lines = append(lines, cleanPaneLine(ansi.Strip(line)))
+	}
+	return lines, last
+}
+
+// applyHeartbeat overlays a container agent's self-reported state on the
+// pane-parsed values.
+func applyHeartbeat(a *AgentLight, hb *polecat.SessionHeartbeat) {
+	if hb.AgentID != "" {
+		a.AgentID = hb.AgentID
+	}
+	switch hb.EffectiveState() {
+	case polecat.HeartbeatStuck:
+		a.WaitingForHuman = true
+		a.WaitingReason = "self-reported stuck"
+		if hb.Context != "" {
+			a.WaitingReason += ": " + hb.Context
+		}
+	case polecat.HeartbeatExiting:
+		if a.StatusText == "" {
+			a.StatusText = "exiting"
+		}
+	case polecat.HeartbeatWorking:
+		if a.StatusText == "" && hb.Context != "" {
+			a.StatusText = hb.Context
+		}
+	}
+}
+
+// dockerAttachCommand returns the shell command that attaches to an agent
+// container's TTY. Ctrl-P Ctrl-Q detaches without stopping it.
+func dockerAttachCommand(container string) string {
+	return "docker attach " + container
+}
+
+// containerized reports whether the session is a container or pod agent
+// rather than a tmux session.
+func (s sessionInfo) containerized() bool {
+	return s.container != "" || s.pod != ""
+}
+
+// inContainer reports whether the agent runs in a container or pod, so
+// tmux actions (attach, send-keys, kill-session) don't apply.
+func (a *AgentLight) inContainer() bool {
+	return a.Container != "" || a.Pod != ""
+}
+
+// containerAttachCommand returns the command that attaches to a container or
+// pod agent, or "" for a tmux agent.
+func (m *Model) containerAttachCommand(a *AgentLight) string {
+	switch {
+	case a.Container != "":
+		return dockerAttachCommand(a.Container)
+	case a.Pod != "" && m.kubernetes != nil:
+		return m.kubernetes.attachCommand(a.Pod)
+	}
+	return ""
+}
+
+// containerSummary describes a container or pod agent for the hover line,
+// or "".
+func (a *AgentLight) containerSummary() string {
+	switch {
+	case a.Container != "":
+		return "container " + a.Container + " · detach with Ctrl-P Ctrl-Q"
+	case a.Pod != "":
+		return "pod " + a.Pod
+	}
+	return ""
+}
+
+// viaDocker routes a bulk action's container agents to a docker command
+// (stop, restart) and its tmux agents to fn.
+func (b *bulkAction) viaDocker(dockerVerb string, fn func(session string) error) func(session string) error {
+	containers := make(map[string]string)
+	for _, a := range b.agents {
+		if a.Container != "" {
+			containers[a.SessionName] = a.Container
+		}
+	}
+	return func(session string) error {
+		id, ok := containers[session]
+		if !ok {
+			return fn(session)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return commandError(runDocker(ctx, dockerVerb, id))
+	}
+}
+
+// commandError returns a failed CLI command's output as its error.
+func commandError(out []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	if msg := strings.TrimSpace(string(out)); msg != "" {
+		return fmt.Errorf("%s", msg)
+	}
+	return err
+}
@@ -0,0 +1,119 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDocker answers docker CLI calls from canned output keyed by the
+// subcommand and its last argument (e.g. "logs c1", "exec c1").
+type fakeDocker struct {
+	out   map[string]string
+	calls []string
+}
+
+func (f *fakeDocker) run(ctx context.Context, args ...string) ([]byte, error) {
+	key := args[0]
+	switch args[0] {
+	case "logs":
+		key += " " + args[len(args)-1]
+	case "exec":
+		key += " " + args[1]
+	}
+	f.calls = append(f.calls, key)
+	out, ok := f.out[key]
+	if !ok {
+		return nil, errors.New("no such container")
+	}
+	return []byte(out), nil
+}
+
+func TestDockerSource_Poll(t *testing.T) {
+	f := &fakeDocker{out: map[string]string{
+		"ps": "c1\thq-mayor\tclaude\t/gt/.runtime/heartbeats/hq-mayor.json\t2026-10-15 08:00:00 +0000 UTC\n" +
+			"c2\thq-deacon\t\t\t2026-10-15 08:00:00 +0000 UTC\n" +
+			"c3\tscratch\t\t\t2026-10-15 08:00:00 +0000 UTC\n",
+		"logs c1": "2026-10-15T09:00:00.5Z \x1b[1mBuilding\x1b[0m\n" +
+			"2026-10-15T09:00:05Z 50%\r100%\r\n",
+		"exec c1": `{"timestamp":"2026-10-15T09:01:00Z","state":"stuck","context":"tests hang"}`,
+	}}
+	s := &dockerSource{run: f.run}
+
+	sessions := s.poll(map[string]bool{"hq-deacon": true}, nil, time.Now())
+	if len(sessions) != 1 {
+		t.Fatalf("sessions = %+v, want only hq-mayor (hq-deacon is in tmux, scratch isn't a town session)", sessions)
+	}
+	si := sessions[0]
+	if si.name != "hq-mayor" || si.container != "c1" || si.agentType != "claude" {
+		t.Errorf("session = %+v", si)
+	}
+	if got := strings.Join(si.paneLines, "|"); got != "Building|100%" {
+		t.Errorf("paneLines = %q, want escapes and redraws stripped", got)
+	}
+	if si.heartbeat == nil || si.heartbeat.Context != "tests hang" {
+		t.Errorf("heartbeat = %+v", si.heartbeat)
+	}
+	if want := time.Date(2026, 10, 15, 9, 1, 0, 0, time.UTC).Unix(); si.activity != want {
+		t.Errorf("activity = %d, want the heartbeat's %d (later than the last log line)", si.activity, want)
+	}
+	if si.created != time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC).Unix() {
+		t.Errorf("created = %d", si.created)
+	}
+}
+
+func TestDockerSource_LogFailureIsStale(t *testing.T) {
+	f := &fakeDocker{out: map[string]string{"ps": "c1\thq-mayor\t\t\t2026-10-15 08:00:00 +0000 UTC\n"}}
+	s := &dockerSource{run: f.run}
+
+	sessions := s.poll(map[string]bool{}, nil, time.Now())
+	if len(sessions) != 1 || !sessions[0].captureStale {
+		t.Fatalf("sessions = %+v, want hq-mayor marked stale", sessions)
+	}
+	if sessions[0].activity != sessions[0].created {
+		t.Errorf("activity = %d, want the container start %d", sessions[0].activity, sessions[0].created)
+	}
+}
+
+func TestDockerSource_BacksOffWhenDockerIsDown(t *testing.T) {
+	f := &fakeDocker{out: map[string]string{}}
+	s := &dockerSource{run: f.run}
+	now := time.Now()
+
+	s.poll(map[string]bool{}, nil, now)
+	s.poll(map[string]bool{}, nil, now.Add(time.Second))
+	if len(f.calls) != 1 {
+		t.Errorf("docker called %d times (%v), want 1 while backing off", len(f.calls), f.calls)
+	}
+	s.poll(map[string]bool{}, nil, now.Add(dockerBackoff+time.Second))
+	if len(f.calls) != 2 {
+		t.Errorf("docker not retried after the backoff: %v", f.calls)
+	}
+}
+
+func TestUpdateAgents_ContainerAgent(t *testing.T) {
+	f := &fakeDocker{out: map[string]string{
+		"ps":      "c1\thq-mayor\topencode\t/hb.json\t2026-10-15 08:00:00 +0000 UTC\n",
+		"logs c1": "2026-10-15T09:00:00Z working\n",
+		"exec c1": `{"timestamp":"2026-10-15T09:00:00Z","state":"stuck","context":"tests hang","agent_id":"mayor"}`,
+	}}
+	m := &Model{}
+	m.updateAgents((&dockerSource{run: f.run}).poll(map[string]bool{}, nil, time.Now()))
+
+	if len(m.agents) != 1 {
+		t.Fatalf("agents = %d, want 1", len(m.agents))
+	}
+	a := m.agents[0]
+	if a.Container != "c1" || a.AgentType != "opencode" || a.AgentID != "mayor" {
+		t.Errorf("agent = container %q, type %q, id %q", a.Container, a.AgentType, a.AgentID)
+	}
+	// WaitingForHuman itself is debounced until the agent has been quiet 5s.
+	if a.WaitingReason != "self-reported stuck: tests hang" {
+		t.Errorf("WaitingReason = %q, want the stuck heartbeat", a.WaitingReason)
+	}
+	if !strings.Contains(a.containerSummary(), "container c1") {
+		t.Errorf("containerSummary = %q", a.containerSummary())
+	}
+}
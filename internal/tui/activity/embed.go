@@ -0,0 +1,63 @@
+package activity
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// SetEmbedded prepares the model to run inside another program's TUI
+// (pkg/activity): quit keys do nothing, the window title and mouse mode are
+// left to the host, and the idle screensaver and auto-exit are off.
+func (m *Model) SetEmbedded() {
+	m.embedded = true
+	m.idle = idleConfig{}
+}
+
+// SetReporter makes the model emit agent health events (limit_hit,
+// loop_detected, provider_outage) as actor. The daemon's agent watch is the
+// one reporter, so every gt top instance only displays those states. The
+// first poll only records them: the reporter starting up isn't news.
+func (m *Model) SetReporter(actor string) {
+	m.reportAs = actor
+}
+
+// reportHealth emits an agent health event, when the model is the reporter.
+// Best-effort: polling carries on if the events file is unwritable.
+func (m *Model) reportHealth(eventType string, payload map[string]interface{}) {
+	if m.reportAs == "" || !m.reporting {
+		return
+	}
+	_ = events.LogInTown(m.townRoot, "daemon", eventType, m.reportAs, payload, events.VisibilityFeed)
+}
+
+// ViewSnapshot is Snapshot narrowed to the agents the view filter (--rig,
+// --role, --level, --convoy) shows, for gt top --json.
+func (m *Model) ViewSnapshot(now time.Time) Status {
+	s := m.Snapshot(now)
+	if !m.view.active() {
+		return s
+	}
+	kept := s.Agents[:0]
+	for i, a := range m.agents { // Snapshot keeps the agents' order
+		if m.view.matches(a) {
+			kept = append(kept, s.Agents[i])
+		}
+	}
+	s.Agents = kept
+	return s
+}
+
+// Poll runs one poll synchronously, as the TUI does on each tick, for
+// callers that read agent levels without running the TUI. Alerts aren't
+// escalated. It returns the tmux listing error, if any; on a timeout the
+// last known agents are kept.
+func (m *Model) Poll() error {
+	if m.townRoot != "" && time.Since(m.lastRegistryRefresh) >= registryRefreshInterval {
+		m.refreshRegistry()
+	}
+	m.lastPoll = time.Now()
+	msg, _ := m.pollSessions()().(sessionsMsg)
+	m.Update(msg)
+	return msg.err
+}
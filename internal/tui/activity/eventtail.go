@@ -0,0 +1,109 @@
+package activity
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// The events file is read once per poll into eventTail, and every reader of
+// it (tool events, comms, the ticker, nudges, the merge queue, the compare
+// overlay) works from the parsed events there instead of opening, seeking
+// and scanning the file itself.
+
+const (
+	// eventTailKeep is how much of the file's end the tail keeps parsed
+	// for the windowed readers: at ~2 events/s * ~160 bytes, 256KB covers
+	// the longest window (the ticker's 15 minutes).
+	eventTailKeep = 256 * 1024
+
+	// eventTailFirstRead bounds how much of the file the first read covers:
+	// enough for a day of nudges and the merge queue without reading the
+	// whole log.
+	eventTailFirstRead = 8 * 1024 * 1024
+)
+
+// tailEvent is one verified event line from the events file.
+type tailEvent struct {
+	events.Event
+	At   time.Time // parsed Timestamp; zero if it doesn't parse
+	size int64     // line length, for trimming the tail to eventTailKeep
+}
+
+// eventTail is the end of the events file, read incrementally.
+type eventTail struct {
+	offset int64
+	recent []tailEvent // the last eventTailKeep bytes' events, oldest first
+	fresh  []tailEvent // those appended since the previous read (the end of recent)
+	bytes  int64       // total size of recent
+	dedup  events.Dedup
+}
+
+// readEvents reads the events appended since the last poll: unsigned or
+// forged ones (when the town signs events) and retries already read are
+// dropped. A trailing partial line is left for the next poll.
+func (m *Model) readEvents(now time.Time) {
+	t := &m.tail
+	t.fresh = nil
+	if m.townRoot == "" {
+		return
+	}
+	f, err := os.Open(filepath.Join(m.townRoot, events.EventsFile))
+	m.noteSource("events file", err, now)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return
+	}
+	cut := false
+	switch {
+	case info.Size() < t.offset: // rotated
+		t.offset, t.recent, t.bytes = 0, nil, 0
+	case t.offset == 0 && info.Size() > eventTailFirstRead:
+		t.offset, cut = info.Size()-eventTailFirstRead, true
+	}
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return
+	}
+
+	r := events.NewTailReader(f, t.offset)
+	if cut {
+		if _, off, ok := r.Next(); ok { // starts mid-line
+			t.offset = off
+		}
+	}
+	for {
+		line, off, ok := r.Next()
+		if !ok {
+			break
+		}
+		size := off - t.offset
+		t.offset = off
+		var evt events.Event
+		if err := json.Unmarshal(line, &evt); err != nil || !m.verify.Valid(line) || t.dedup.Seen(evt.IdempotencyKey) {
+			continue
+		}
+		at, _ := time.Parse(time.RFC3339, evt.Timestamp)
+		t.fresh = append(t.fresh, tailEvent{Event: evt, At: at, size: size})
+	}
+
+	t.recent = append(t.recent, t.fresh...)
+	for _, e := range t.fresh {
+		t.bytes += e.size
+	}
+	drop := 0
+	for drop < len(t.recent) && t.bytes > eventTailKeep {
+		t.bytes -= t.recent[drop].size
+		drop++
+	}
+	if drop > 0 {
+		t.recent = append([]tailEvent(nil), t.recent[drop:]...)
+	}
+}
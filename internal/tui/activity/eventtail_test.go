@@ -0,0 +1,66 @@
+package activity
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestReadEvents(t *testing.T) {
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, events.EventsFile)
+	now := time.Now().Truncate(time.Second)
+	m := &Model{townRoot: townRoot}
+
+	writeEvent(t, townRoot, now, events.TypeDone, "gastown/polecats/Toast", events.DonePayload("gt-1", "b"))
+	m.readEvents(now)
+	if len(m.tail.fresh) != 1 || len(m.tail.recent) != 1 || !m.tail.fresh[0].At.Equal(now) {
+		t.Fatalf("first read: fresh %d, recent %d", len(m.tail.fresh), len(m.tail.recent))
+	}
+
+	// Only what was appended since is fresh; a partial line waits.
+	writeEvent(t, townRoot, now, events.TypeSling, "mayor", nil)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"ts":"`)
+	_ = f.Close()
+	m.readEvents(now)
+	if len(m.tail.fresh) != 1 || m.tail.fresh[0].Type != events.TypeSling || len(m.tail.recent) != 2 {
+		t.Fatalf("second read: fresh %+v, recent %d", m.tail.fresh, len(m.tail.recent))
+	}
+	m.readEvents(now)
+	if len(m.tail.fresh) != 0 {
+		t.Errorf("nothing new: fresh %+v", m.tail.fresh)
+	}
+
+	// A rotated file starts the tail over.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	writeEvent(t, townRoot, now, events.TypeMail, "mayor", nil)
+	m.readEvents(now)
+	if len(m.tail.fresh) != 1 || len(m.tail.recent) != 1 || m.tail.recent[0].Type != events.TypeMail {
+		t.Errorf("after rotation: fresh %d, recent %+v", len(m.tail.fresh), m.tail.recent)
+	}
+}
+
+func TestReadEvents_KeepsTheEnd(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	m := &Model{townRoot: townRoot}
+
+	pad := map[string]interface{}{"pad": strings.Repeat("x", 1024)}
+	for i := 0; i < eventTailKeep/1024+10; i++ {
+		writeEvent(t, townRoot, now, events.TypeSling, "mayor", pad)
+	}
+	m.readEvents(now)
+	if m.tail.bytes > eventTailKeep || len(m.tail.recent) >= len(m.tail.fresh) {
+		t.Errorf("kept %d bytes in %d events of %d read, want at most %d bytes", m.tail.bytes, len(m.tail.recent), len(m.tail.fresh), eventTailKeep)
+	}
+}
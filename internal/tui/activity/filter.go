@@ -0,0 +1,59 @@
+package activity
+
+import (
+	"fmt"
+	"path"
+)
+
+// sessionFilter selects which tmux sessions gt top monitors, by glob on the
+// session name. Helper sessions that happen to carry a rig prefix (gt-scratch,
+// personal experiments) can be kept out of the panels and the stats.
+type sessionFilter struct {
+	include []string // when non-empty, a session must match one of these
+	exclude []string // a session matching any of these is dropped
+}
+
+// allows reports whether a session passes the filter.
+func (f sessionFilter) allows(name string) bool {
+	if len(f.include) > 0 && !matchAny(f.include, name) {
+		return false
+	}
+	return !matchAny(f.exclude, name)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSessionFilter adds include/exclude session globs (gt top --include,
+// --exclude) to those from town settings. Patterns use path.Match syntax,
+// e.g. "gt-scratch*" or "*-crew-experiment?".
+func (m *Model) SetSessionFilter(include, exclude []string) error {
+	m.filter.include = append(m.filter.include, include...)
+	m.filter.exclude = append(m.filter.exclude, exclude...)
+	for _, p := range append(append([]string{}, m.filter.include...), m.filter.exclude...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid session pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// filterSessions drops sessions the filter excludes.
+func (f sessionFilter) filterSessions(sessions []sessionInfo) []sessionInfo {
+	if len(f.include) == 0 && len(f.exclude) == 0 {
+		return sessions
+	}
+	kept := sessions[:0]
+	for _, s := range sessions {
+		if f.allows(s.name) {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
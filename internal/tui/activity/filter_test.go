@@ -0,0 +1,54 @@
+package activity
+
+import "testing"
+
+func TestSessionFilter(t *testing.T) {
+	sessions := []sessionInfo{
+		{name: "hq-mayor"}, {name: "gt-gastown-Toast"}, {name: "gt-scratch"}, {name: "gt-scratch2"}, {name: "bd-beads-Nux"},
+	}
+	names := func(ss []sessionInfo) []string {
+		var out []string
+		for _, s := range ss {
+			out = append(out, s.name)
+		}
+		return out
+	}
+	tests := []struct {
+		name             string
+		include, exclude []string
+		want             []string
+	}{
+		{"no patterns", nil, nil, []string{"hq-mayor", "gt-gastown-Toast", "gt-scratch", "gt-scratch2", "bd-beads-Nux"}},
+		{"exclude", nil, []string{"gt-scratch*"}, []string{"hq-mayor", "gt-gastown-Toast", "bd-beads-Nux"}},
+		{"include", []string{"gt-*"}, nil, []string{"gt-gastown-Toast", "gt-scratch", "gt-scratch2"}},
+		{"include and exclude", []string{"gt-*", "hq-*"}, []string{"gt-scratch?"}, []string{"hq-mayor", "gt-gastown-Toast", "gt-scratch"}},
+	}
+	for _, tt := range tests {
+		f := sessionFilter{include: tt.include, exclude: tt.exclude}
+		got := names(f.filterSessions(append([]sessionInfo(nil), sessions...)))
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSetSessionFilter(t *testing.T) {
+	m := &Model{filter: sessionFilter{exclude: []string{"gt-scratch*"}}}
+	if err := m.SetSessionFilter(nil, []string{"*-experiment"}); err != nil {
+		t.Fatalf("SetSessionFilter: %v", err)
+	}
+	if m.filter.allows("gt-scratch") || m.filter.allows("gt-crew-experiment") || !m.filter.allows("gt-gastown-Toast") {
+		t.Errorf("flag patterns should add to config patterns: %+v", m.filter)
+	}
+
+	if err := m.SetSessionFilter([]string{"gt-["}, nil); err == nil {
+		t.Error("malformed glob should be rejected")
+	}
+}
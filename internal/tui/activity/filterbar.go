@@ -0,0 +1,157 @@
+package activity
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Filter bar (/): the --rig, --role, --level and --convoy filter, edited in
+// place. Each word names a rig, a role or a level ("gastown polecat
+// waiting"), or is key=value ("level=cold,cool", "convoy=hq-cv-abc"); the
+// panels narrow as you type. Several levels match any of them; a second rig
+// or role replaces the first. Enter keeps the filter, esc puts back the one
+// from before.
+
+var filterUnknownStyle = lipgloss.NewStyle().Foreground(colorWaiting)
+
+// filterBar is the filter being edited.
+type filterBar struct {
+	query   string
+	prev    agentFilter // restored by esc
+	unknown []string    // words matching no rig, role or level
+}
+
+// openFilterBar opens the filter bar holding the current filter.
+func (m *Model) openFilterBar() {
+	m.filterBar = &filterBar{query: m.view.String(), prev: m.view}
+	if m.filterBar.query != "" {
+		m.filterBar.query += " "
+	}
+}
+
+// handleFilterKey handles a key while the filter bar is open.
+func (m *Model) handleFilterKey(key string) {
+	fb := m.filterBar
+	switch key {
+	case "esc":
+		m.view = fb.prev
+		m.filterBar = nil
+		return
+	case "enter":
+		m.filterBar = nil
+		return
+	case "backspace":
+		if r := []rune(fb.query); len(r) > 0 {
+			fb.query = string(r[:len(r)-1])
+		}
+	case "ctrl+u":
+		fb.query = ""
+	case "tab":
+		fb.query = m.completeFilter(fb.query)
+	default:
+		if r := []rune(key); len(r) == 1 && unicode.IsPrint(r[0]) {
+			fb.query += key
+		}
+	}
+	m.view, fb.unknown = parseFilterQuery(fb.query, m.rigs)
+}
+
+// filterRoles are the roles the filter bar knows, in display order.
+func filterRoles() []string {
+	roles := make([]string, 0, len(roleOrder))
+	for r := range roleOrder {
+		roles = append(roles, r)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roleOrder[roles[i]] < roleOrder[roles[j]] })
+	return roles
+}
+
+// filterRole returns the role a word names, plurals included ("polecats"),
+// or "".
+func filterRole(word string) string {
+	for _, w := range []string{word, strings.TrimSuffix(word, "s")} {
+		if _, ok := roleOrder[w]; ok {
+			return w
+		}
+	}
+	return ""
+}
+
+// parseFilterQuery parses a filter bar query against the town's rigs. Words
+// it can't place are returned so the bar can flag them.
+func parseFilterQuery(query string, rigs []string) (agentFilter, []string) {
+	var f agentFilter
+	var unknown []string
+	for _, word := range strings.Fields(query) {
+		if key, value, ok := strings.Cut(word, "="); ok {
+			switch key {
+			case "rig":
+				f.rig = value
+			case "role":
+				f.role = value
+				if role := filterRole(value); role != "" {
+					f.role = role
+				}
+			case "level":
+				for _, l := range strings.Split(value, ",") {
+					if isLevelName(l) {
+						f.levels = append(f.levels, l)
+					} else if l != "" {
+						unknown = append(unknown, l)
+					}
+				}
+			case "convoy":
+				f.convoy = value
+			default:
+				unknown = append(unknown, word)
+			}
+			continue
+		}
+		switch {
+		case slices.Contains(rigs, word):
+			f.rig = word
+		case filterRole(word) != "":
+			f.role = filterRole(word)
+		case isLevelName(word):
+			f.levels = append(f.levels, word)
+		default:
+			unknown = append(unknown, word)
+		}
+	}
+	return f, unknown
+}
+
+// completeFilter completes the query's last word with the first rig, role
+// or level it begins.
+func (m *Model) completeFilter(query string) string {
+	i := strings.LastIndexAny(query, " =,") + 1
+	word := query[i:]
+	if word == "" {
+		return query
+	}
+	candidates := append(append(append([]string{}, m.rigs...), filterRoles()...), LevelNames()...)
+	for _, c := range candidates {
+		if strings.HasPrefix(c, word) && c != word {
+			return query[:i] + c + " "
+		}
+	}
+	return query
+}
+
+// renderFilterBar renders the filter bar in place of the filter line: the
+// query, the match count, and what can be typed.
+func (m *Model) renderFilterBar() string {
+	fb := m.filterBar
+	line := "  " + titleStyle.Render("filter") + " " + fb.query + "▏  " +
+		subtitleStyle.Render(m.view.describe(len(m.filteredAgents())))
+	if len(fb.unknown) > 0 {
+		line += "  " + filterUnknownStyle.Render("unknown: "+strings.Join(fb.unknown, ", "))
+	}
+	hint := "rigs: " + strings.Join(m.rigs, " ") + " · roles: " + strings.Join(filterRoles(), " ") +
+		" · levels: " + strings.Join(LevelNames(), " ") + " · tab: complete · enter: keep · esc: cancel"
+	return line + "\n" + helpStyle.Render("  "+truncateWidth(hint, max(m.width-6, 20), "…"))
+}
@@ -0,0 +1,63 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFilterQuery(t *testing.T) {
+	rigs := []string{"hq", "gastown", "beads"}
+	f, unknown := parseFilterQuery("gastown polecats waiting level=cold,bogus frob", rigs)
+	if f.rig != "gastown" || f.role != "polecat" || strings.Join(f.levels, ",") != "waiting,cold" {
+		t.Errorf("filter = %+v", f)
+	}
+	if strings.Join(unknown, ",") != "bogus,frob" {
+		t.Errorf("unknown = %v", unknown)
+	}
+	if f, _ := parseFilterQuery("role=witness convoy=hq-cv-abc", rigs); f.role != "witness" || f.convoy != "hq-cv-abc" {
+		t.Errorf("key=value filter = %+v", f)
+	}
+	if f, _ := parseFilterQuery(agentFilter{rig: "beads", role: "crew", levels: []string{"cool"}}.String(), rigs); f.rig != "beads" || f.role != "crew" || f.levels[0] != "cool" {
+		t.Errorf("round trip = %+v", f)
+	}
+}
+
+func TestFilterBar(t *testing.T) {
+	m := &Model{width: 160, rigs: []string{"gastown", "beads"}, agents: []*AgentLight{
+		{SessionName: "gt-gastown-Toast", Name: "Toast", Rig: "gastown", Role: "polecat", Level: LevelWaitingForHuman},
+		{SessionName: "gt-gastown-Nux", Name: "Nux", Rig: "gastown", Role: "polecat", Level: LevelActive},
+		{SessionName: "gt-beads-crew-max", Name: "max", Rig: "beads", Role: "crew", Level: LevelWaitingForHuman},
+	}}
+	m.totalAgents = len(m.agents)
+
+	typeKeys(m, "/")
+	if m.filterBar == nil {
+		t.Fatal("/ didn't open the filter bar")
+	}
+	// Narrows as you type; tab completes.
+	typeKeys(m, "w", "a", "i", "t", "tab")
+	if m.filterBar.query != "waiting " || len(m.filteredAgents()) != 2 {
+		t.Errorf("query %q matches %d agents, want waiting matching 2", m.filterBar.query, len(m.filteredAgents()))
+	}
+	typeKeys(m, "g", "a", "s", "t", "o", "w", "n")
+	if got := m.filteredAgents(); len(got) != 1 || got[0].Name != "Toast" {
+		t.Errorf("gastown waiting matches %v, want Toast", got)
+	}
+	if bar := m.renderFilterBar(); !strings.Contains(bar, "1 waiting agents in gastown") {
+		t.Errorf("bar = %q", bar)
+	}
+	typeKeys(m, "enter")
+	if m.filterBar != nil || m.view.rig != "gastown" {
+		t.Fatalf("enter: bar %v, view %+v; want closed, filter kept", m.filterBar, m.view)
+	}
+
+	// Esc puts back the filter from before editing.
+	typeKeys(m, "/", "ctrl+u", "b", "e", "a", "d", "s")
+	if m.view.rig != "beads" {
+		t.Errorf("editing: view %+v, want beads", m.view)
+	}
+	typeKeys(m, "esc")
+	if m.filterBar != nil || m.view.rig != "gastown" || len(m.view.levels) != 1 {
+		t.Errorf("esc: view %+v, want the previous filter back", m.view)
+	}
+}
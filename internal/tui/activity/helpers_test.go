@@ -0,0 +1,55 @@
+package activity
+
+import "slices"
+
+// sessionAgents returns an agent for each session name, placed by rig and
+// role as gt top places tmux sessions (register the rigs' prefixes with
+// withRigs first).
+func sessionAgents(sessions ...string) []*AgentLight {
+	agents := make([]*AgentLight, 0, len(sessions))
+	for _, s := range sessions {
+		a := &AgentLight{SessionName: s}
+		parseSessionName(a)
+		agents = append(agents, a)
+	}
+	return agents
+}
+
+// modelOption adjusts a Model built by testModel.
+type modelOption func(*Model)
+
+// testModel builds a Model around the given agents, as a poll would leave
+// it: 120x40, every agent counted, and the rigs in the order the agents
+// first name them. Options adjust it from there.
+func testModel(agents []*AgentLight, opts ...modelOption) *Model {
+	m := &Model{width: 120, height: 40, agents: agents, totalAgents: len(agents)}
+	for _, a := range agents {
+		if a.Rig != "" && !slices.Contains(m.rigs, a.Rig) {
+			m.rigs = append(m.rigs, a.Rig)
+		}
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// modelSize sets the terminal size.
+func modelSize(width, height int) modelOption {
+	return func(m *Model) { m.width, m.height = width, height }
+}
+
+// modelTown sets the town root.
+func modelTown(root string) modelOption {
+	return func(m *Model) { m.townRoot = root }
+}
+
+// modelRigs sets the rig order.
+func modelRigs(rigs ...string) modelOption {
+	return func(m *Model) { m.rigs = rigs }
+}
+
+// modelGroups sets the convoys and epics the agents work under.
+func modelGroups(groups map[string]*workGroup) modelOption {
+	return func(m *Model) { m.groups = groups }
+}
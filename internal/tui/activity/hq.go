@@ -0,0 +1,180 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+)
+
+// hqPollInterval is how often orchestration state is re-read. Counting the
+// scheduler queue scans a day of the events file, like the SLA poll.
+const hqPollInterval = 15 * time.Second
+
+// hqQueueWindow bounds how far back scheduler_enqueue events are counted.
+const hqQueueWindow = 24 * time.Hour
+
+// hqStatus is the mayor/deacon orchestration state shown in the hq panel
+// header, read from the deacon's status files, the scheduler state file,
+// the nudge queues, and the events feed.
+type hqStatus struct {
+	heartbeat    *deacon.Heartbeat // nil when the deacon never wrote one
+	paused       *deacon.PauseState
+	schedPaused  bool
+	queued       int       // beads scheduled but not yet dispatched
+	lastDispatch time.Time // zero when never dispatched
+	nudges       int       // nudges queued for hq agents
+}
+
+// pollHQStatus re-reads orchestration state when the poll interval has
+// elapsed. Must run after the agent list is rebuilt.
+func (m *Model) pollHQStatus(now time.Time) {
+	if m.townRoot == "" {
+		return
+	}
+	if !m.lastHQPoll.IsZero() && now.Sub(m.lastHQPoll) < hqPollInterval {
+		return
+	}
+	m.lastHQPoll = now
+
+	st := &hqStatus{heartbeat: deacon.ReadHeartbeat(m.townRoot)}
+	if paused, ps, err := deacon.IsPaused(m.townRoot); err == nil && paused {
+		st.paused = ps
+	}
+	if ss, err := capacity.LoadState(m.townRoot); err == nil {
+		st.schedPaused = ss.Paused
+		st.lastDispatch, _ = time.Parse(time.RFC3339, ss.LastDispatchAt)
+	}
+	if evts, err := feed.ReadDigestEvents(m.townRoot, now.Add(-hqQueueWindow)); err == nil {
+		st.queued = scheduledBacklog(evts)
+	} else if m.hq != nil {
+		st.queued = m.hq.queued // keep the last count rather than blanking it
+	}
+	for _, a := range m.agents {
+		if a.Rig == "hq" && (a.Role == constants.RoleMayor || a.Role == constants.RoleDeacon) {
+			st.nudges += nudge.QueueLen(m.townRoot, a.SessionName)
+		}
+	}
+	m.hq = st
+}
+
+// scheduledBacklog counts beads with a scheduler_enqueue event and no later
+// scheduler_dispatch. A failed dispatch is requeued, so it stays counted.
+func scheduledBacklog(evts []events.Event) int {
+	pending := make(map[string]bool)
+	for _, e := range evts {
+		bead, _ := e.Payload["bead"].(string)
+		if bead == "" {
+			continue
+		}
+		switch e.Type {
+		case events.TypeSchedulerEnqueue:
+			pending[bead] = true
+		case events.TypeSchedulerDispatch:
+			delete(pending, bead)
+		}
+	}
+	return len(pending)
+}
+
+var (
+	hqOKStyle   = lipgloss.NewStyle().Foreground(colorActive)
+	hqWarnStyle = lipgloss.NewStyle().Foreground(colorRateLimited)
+	hqBadStyle  = lipgloss.NewStyle().Foreground(colorWaiting).Bold(true)
+)
+
+// renderHQBadges renders orchestration health for the hq panel header, e.g.
+// "deacon ♥ #412 2m ago  sched 5 queued  ✉3". Returns "" before the first poll.
+func (m *Model) renderHQBadges(now time.Time) string {
+	st := m.hq
+	if st == nil {
+		return ""
+	}
+	var badges []string
+
+	hb := st.heartbeat
+	switch {
+	case st.paused != nil:
+		label := "deacon paused"
+		if st.paused.Reason != "" {
+			label += ": " + st.paused.Reason
+		}
+		badges = append(badges, hqWarnStyle.Render(label))
+	case hb == nil:
+		if m.hqAgent(constants.RoleDeacon) != nil {
+			badges = append(badges, hqWarnStyle.Render("deacon ♥ none"))
+		}
+	default:
+		age := now.Sub(hb.Timestamp)
+		label := fmt.Sprintf("deacon ♥ #%d %s ago", hb.Cycle, formatCountdown(age))
+		switch {
+		case age >= deacon.HeartbeatVeryStaleThreshold:
+			badges = append(badges, hqBadStyle.Render(label))
+		case age >= deacon.HeartbeatStaleThreshold:
+			badges = append(badges, hqWarnStyle.Render(label))
+		default:
+			badges = append(badges, hqOKStyle.Render(label))
+		}
+		if hb.UnhealthyAgents > 0 {
+			badges = append(badges, hqBadStyle.Render(fmt.Sprintf("%d unhealthy", hb.UnhealthyAgents)))
+		}
+	}
+
+	switch {
+	case st.schedPaused:
+		badges = append(badges, hqWarnStyle.Render(fmt.Sprintf("sched paused · %d queued", st.queued)))
+	case st.queued > 0:
+		badges = append(badges, statusDimStyle.Render(fmt.Sprintf("sched %d queued", st.queued)))
+	}
+
+	if st.nudges > 0 {
+		badges = append(badges, statusDimStyle.Render(fmt.Sprintf("✉%d", st.nudges)))
+	}
+	return strings.Join(badges, "  ")
+}
+
+// hqDetail describes the deacon's last heartbeat, or the scheduler, for the
+// hover line of the deacon or mayor. Returns "" for other agents.
+func (m *Model) hqDetail(a *AgentLight, now time.Time) string {
+	st := m.hq
+	if st == nil || a.Rig != "hq" {
+		return ""
+	}
+	switch a.Role {
+	case constants.RoleDeacon:
+		hb := st.heartbeat
+		if hb == nil {
+			return ""
+		}
+		s := fmt.Sprintf("heartbeat #%d %s ago · %d healthy, %d unhealthy",
+			hb.Cycle, formatCountdown(now.Sub(hb.Timestamp)), hb.HealthyAgents, hb.UnhealthyAgents)
+		if hb.LastAction != "" {
+			s += " · " + hb.LastAction
+		}
+		return s
+	case constants.RoleMayor:
+		s := fmt.Sprintf("scheduler: %d queued", st.queued)
+		if !st.lastDispatch.IsZero() {
+			s += ", last dispatch " + formatCountdown(now.Sub(st.lastDispatch)) + " ago"
+		}
+		return s
+	}
+	return ""
+}
+
+// hqAgent returns the hq agent with the given role, or nil.
+func (m *Model) hqAgent(role string) *AgentLight {
+	for _, a := range m.agents {
+		if a.Rig == "hq" && a.Role == role {
+			return a
+		}
+	}
+	return nil
+}
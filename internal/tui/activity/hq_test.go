@@ -0,0 +1,91 @@
+package activity
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/nudge"
+)
+
+func TestScheduledBacklog(t *testing.T) {
+	evts := []events.Event{
+		{Type: events.TypeSchedulerEnqueue, Payload: events.SchedulerEnqueuePayload("gt-1", "gastown")},
+		{Type: events.TypeSchedulerEnqueue, Payload: events.SchedulerEnqueuePayload("gt-2", "gastown")},
+		{Type: events.TypeSchedulerEnqueue, Payload: events.SchedulerEnqueuePayload("gt-3", "gastown")},
+		{Type: events.TypeSchedulerDispatch, Payload: events.SchedulerDispatchPayload("gt-1", "gastown", "Toast")},
+		{Type: events.TypeSchedulerDispatchFailed, Payload: events.SchedulerDispatchFailedPayload("gt-2", "gastown", "boom")},
+	}
+	if got := scheduledBacklog(evts); got != 2 {
+		t.Errorf("scheduledBacklog = %d, want 2 (failed dispatches stay queued)", got)
+	}
+}
+
+func TestPollHQStatus(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	if err := deacon.WriteHeartbeat(townRoot, &deacon.Heartbeat{
+		Timestamp: now.Add(-2 * time.Minute), Cycle: 412, LastAction: "nudged Toast", HealthyAgents: 5, UnhealthyAgents: 1,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	enq := events.Event{
+		Timestamp: now.Add(-time.Hour).UTC().Format(time.RFC3339),
+		Type:      events.TypeSchedulerEnqueue,
+		Payload:   events.SchedulerEnqueuePayload("gt-9", "gastown"),
+	}
+	data, _ := json.Marshal(enq)
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), append(data, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mayor := &AgentLight{SessionName: "hq-mayor", Rig: "hq", Role: constants.RoleMayor}
+	dc := &AgentLight{SessionName: "hq-deacon", Rig: "hq", Role: constants.RoleDeacon}
+	if err := nudge.Enqueue(townRoot, "hq-mayor", nudge.QueuedNudge{Sender: "test", Message: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Model{townRoot: townRoot, agents: []*AgentLight{mayor, dc}}
+	m.pollHQStatus(now)
+	got := m.renderHQBadges(now)
+	for _, want := range []string{"deacon ♥ #412 2m ago", "1 unhealthy", "sched 1 queued", "✉1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("badges = %q, missing %q", got, want)
+		}
+	}
+	if d := m.hqDetail(dc, now); !strings.Contains(d, "5 healthy, 1 unhealthy · nudged Toast") {
+		t.Errorf("deacon detail = %q", d)
+	}
+	if d := m.hqDetail(mayor, now); d != "scheduler: 1 queued" {
+		t.Errorf("mayor detail = %q", d)
+	}
+
+	// Within the poll interval the cached state is kept.
+	if err := deacon.Pause(townRoot, "maintenance", "human"); err != nil {
+		t.Fatal(err)
+	}
+	m.pollHQStatus(now.Add(time.Second))
+	if strings.Contains(m.renderHQBadges(now), "paused") {
+		t.Error("state re-read before the poll interval")
+	}
+	m.pollHQStatus(now.Add(hqPollInterval))
+	if got := m.renderHQBadges(now); !strings.Contains(got, "deacon paused: maintenance") {
+		t.Errorf("badges = %q, want paused deacon", got)
+	}
+}
+
+func TestRenderHQBadges_MissingHeartbeat(t *testing.T) {
+	m := &Model{hq: &hqStatus{}}
+	if got := m.renderHQBadges(time.Now()); got != "" {
+		t.Errorf("no deacon running: badges = %q, want empty", got)
+	}
+	m.agents = []*AgentLight{{SessionName: "hq-deacon", Rig: "hq", Role: constants.RoleDeacon}}
+	if got := m.renderHQBadges(time.Now()); !strings.Contains(got, "deacon ♥ none") {
+		t.Errorf("badges = %q, want missing heartbeat", got)
+	}
+}
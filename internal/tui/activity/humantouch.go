@@ -0,0 +1,179 @@
+package activity
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// HumanTouchFileName is the file under <town>/.runtime/ where gt top keeps
+// when a human last attached to or typed into each session. It outlives gt
+// top and is shared by every instance watching the town, so a team can see
+// which agents nobody has looked at lately.
+const HumanTouchFileName = "top-human-touch.json"
+
+// humanTouchRetention is how long a touch is kept for a session that hasn't
+// been touched since; polecat names are reused, so older ones mislead.
+const humanTouchRetention = 7 * 24 * time.Hour
+
+// humanTouch is the last time a human attached to or sent input to a session.
+type humanTouch struct {
+	At time.Time `json:"at"`
+	By string    `json:"by,omitempty"` // user, when known
+}
+
+// HumanTouchPath returns the human touch file path for a town.
+func HumanTouchPath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), HumanTouchFileName)
+}
+
+// listClientTouches returns the latest input from a tmux client attached to
+// each session, keyed by session name. One tmux call covers all clients.
+func listClientTouches() map[string]humanTouch {
+	out, err := tmux.Output(tmuxTimeout, "list-clients", "-F", "#{client_session}|#{client_activity}|#{client_user}")
+	if err != nil {
+		return nil
+	}
+	touches := make(map[string]humanTouch)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.SplitN(line, "|", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		var ts int64
+		if _, err := fmt.Sscanf(parts[1], "%d", &ts); err != nil || ts == 0 {
+			continue
+		}
+		t := humanTouch{At: time.Unix(ts, 0), By: parts[2]} // client_user is empty before tmux 3.3
+		if t.At.After(touches[parts[0]].At) {
+			touches[parts[0]] = t
+		}
+	}
+	return touches
+}
+
+// sessionTouch is the latest human touch tmux knows of for a session: its
+// last attach, or input from a client attached now, whichever is later.
+func sessionTouch(lastAttached int64, client humanTouch) humanTouch {
+	t := client
+	if lastAttached > 0 && time.Unix(lastAttached, 0).After(t.At) {
+		t = humanTouch{At: time.Unix(lastAttached, 0)}
+	}
+	return t
+}
+
+// loadHumanTouches reads the town's human touch file. Best-effort: a missing
+// or unreadable file starts empty.
+func loadHumanTouches(townRoot string) map[string]humanTouch {
+	touches := make(map[string]humanTouch)
+	if townRoot == "" {
+		return touches
+	}
+	if data, err := os.ReadFile(HumanTouchPath(townRoot)); err == nil {
+		_ = json.Unmarshal(data, &touches)
+	}
+	return touches
+}
+
+// trackHumanTouches takes the human touches tmux reported in this poll,
+// saving them when any session was touched since gt top last looked.
+func (m *Model) trackHumanTouches(sessions []sessionInfo) {
+	changed := false
+	for _, s := range sessions {
+		if m.recordTouch(s.name, s.touch) {
+			changed = true
+		}
+	}
+	if changed {
+		m.saveHumanTouches()
+	}
+}
+
+// touchedByHuman records input sent to a session from gt top itself (attach,
+// probe, nudge), which tmux can't attribute to a human.
+func (m *Model) touchedByHuman(session string) {
+	if m.recordTouch(session, humanTouch{At: time.Now(), By: os.Getenv("USER")}) {
+		m.saveHumanTouches()
+	}
+}
+
+// recordTouch keeps t as the session's last touch if it is the latest.
+func (m *Model) recordTouch(session string, t humanTouch) bool {
+	if t.At.IsZero() || !t.At.After(m.humanTouches[session].At) {
+		return false
+	}
+	if m.humanTouches == nil {
+		m.humanTouches = make(map[string]humanTouch)
+	}
+	m.humanTouches[session] = t
+	return true
+}
+
+// saveHumanTouches merges this instance's touches with the file, which other
+// gt top instances may have written since, and writes the result back.
+// Best-effort: tmux-only mode has no town.
+func (m *Model) saveHumanTouches() {
+	if m.townRoot == "" {
+		return
+	}
+	path := HumanTouchPath(m.townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	for session, t := range loadHumanTouches(m.townRoot) {
+		m.recordTouch(session, t)
+	}
+	cutoff := time.Now().Add(-humanTouchRetention)
+	for session, t := range m.humanTouches {
+		if t.At.Before(cutoff) {
+			delete(m.humanTouches, session)
+		}
+	}
+	data, err := json.MarshalIndent(m.humanTouches, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = util.AtomicWriteFile(path, data, 0644)
+}
+
+// humanTouchLabel renders an agent's last human touch for the hover detail,
+// e.g. "last human touch: 2h ago (alice)", or "" when none is known.
+func (m *Model) humanTouchLabel(a *AgentLight, now time.Time) string {
+	t, ok := m.humanTouches[a.SessionName]
+	if !ok {
+		return ""
+	}
+	label := "last human touch: " + formatAgo(now.Sub(t.At))
+	if t.By != "" {
+		label += " (" + t.By + ")"
+	}
+	return label
+}
+
+// formatAgo renders how long ago something happened, to the largest unit.
+func formatAgo(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours())/24)
+	}
+}
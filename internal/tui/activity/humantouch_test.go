@@ -0,0 +1,89 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionTouch(t *testing.T) {
+	attached := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	typed := humanTouch{At: attached.Add(time.Hour), By: "alice"}
+
+	if got := sessionTouch(attached.Unix(), typed); got != typed {
+		t.Errorf("later client input = %+v, want %+v", got, typed)
+	}
+	if got := sessionTouch(attached.Add(2*time.Hour).Unix(), typed); !got.At.Equal(attached.Add(2*time.Hour)) || got.By != "" {
+		t.Errorf("later attach = %+v, want the attach time with no user", got)
+	}
+	if got := sessionTouch(0, humanTouch{}); !got.At.IsZero() {
+		t.Errorf("never touched = %+v, want zero", got)
+	}
+}
+
+func TestTrackHumanTouchesPersists(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+	m := &Model{townRoot: townRoot, humanTouches: loadHumanTouches(townRoot)}
+
+	m.trackHumanTouches([]sessionInfo{
+		{name: "gt-gastown-Toast", touch: humanTouch{At: now.Add(-2 * time.Hour), By: "alice"}},
+		{name: "gt-gastown-Nux"},
+	})
+	// An older attach doesn't replace a newer touch.
+	m.trackHumanTouches([]sessionInfo{{name: "gt-gastown-Toast", touch: humanTouch{At: now.Add(-3 * time.Hour)}}})
+
+	// Another gt top watching the town sees the touch, and adds its own.
+	other := &Model{townRoot: townRoot, humanTouches: loadHumanTouches(townRoot)}
+	if got := other.humanTouches["gt-gastown-Toast"]; !got.At.Equal(now.Add(-2*time.Hour)) || got.By != "alice" {
+		t.Fatalf("reloaded touch = %+v, want alice 2h ago", got)
+	}
+	if _, ok := other.humanTouches["gt-gastown-Nux"]; ok {
+		t.Error("untouched session recorded")
+	}
+	other.touchedByHuman("gt-gastown-Nux")
+
+	m.trackHumanTouches([]sessionInfo{{name: "gt-gastown-Slit", touch: humanTouch{At: now}}})
+	if _, ok := m.humanTouches["gt-gastown-Nux"]; !ok {
+		t.Error("save didn't merge the other instance's touch")
+	}
+	if _, ok := loadHumanTouches(townRoot)["gt-gastown-Nux"]; !ok {
+		t.Error("save dropped the other instance's touch from the file")
+	}
+}
+
+func TestHumanTouchLabel(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	toast := &AgentLight{SessionName: "gt-gastown-Toast"}
+	m := &Model{humanTouches: map[string]humanTouch{
+		"gt-gastown-Toast": {At: now.Add(-2*time.Hour - 10*time.Minute), By: "alice"},
+	}}
+
+	if got := m.humanTouchLabel(toast, now); got != "last human touch: 2h ago (alice)" {
+		t.Errorf("label = %q", got)
+	}
+	if got := m.humanTouchLabel(&AgentLight{SessionName: "gt-gastown-Nux"}, now); got != "" {
+		t.Errorf("untouched label = %q, want empty", got)
+	}
+
+	m.width, m.agents, m.hoveredAgent = 200, []*AgentLight{toast}, toast
+	if out := m.renderHoverDetail(); !strings.Contains(out, "last human touch: ") {
+		t.Errorf("hover detail missing the human touch:\n%s", out)
+	}
+}
+
+func TestFormatAgo(t *testing.T) {
+	for _, tc := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "just now"},
+		{5 * time.Minute, "5m ago"},
+		{2*time.Hour + 59*time.Minute, "2h ago"},
+		{50 * time.Hour, "2d ago"},
+	} {
+		if got := formatAgo(tc.d); got != tc.want {
+			t.Errorf("formatAgo(%v) = %q, want %q", tc.d, got, tc.want)
+		}
+	}
+}
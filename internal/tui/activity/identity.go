@@ -0,0 +1,63 @@
+package activity
+
+import (
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// detectAgentID reads GT_AGENT_ID from the tmux session environment.
+// Session launchers set it via config.AgentEnv; sessions started by older
+// gt versions won't have it, in which case "" is returned and the caller
+// falls back to deriveAgentID.
+func detectAgentID(sessionName string) string {
+	out, err := tmux.Output(tmuxTimeout, "show-environment", "-t", sessionName, "GT_AGENT_ID")
+	if err != nil {
+		return ""
+	}
+	// Output format: GT_AGENT_ID=gastown/polecats/Toast
+	parts := strings.SplitN(strings.TrimSpace(string(out)), "=", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// deriveAgentID builds the stable agent identity from the parsed session
+// name, using the same address format as BD_ACTOR (and therefore GT_AGENT_ID).
+// Used when the session environment doesn't carry GT_AGENT_ID.
+func deriveAgentID(a *AgentLight) string {
+	switch a.Role {
+	case constants.RoleMayor:
+		return constants.RoleMayor
+	case constants.RoleDeacon:
+		if a.Name == "Boot" {
+			return "deacon-boot"
+		}
+		return constants.RoleDeacon
+	case constants.RoleDog:
+		return "deacon/dogs/" + a.Name
+	case constants.RoleWitness, constants.RoleRefinery:
+		return a.Rig + "/" + a.Role
+	case constants.RoleCrew:
+		return a.Rig + "/crew/" + a.Name
+	case constants.RolePolecat:
+		if a.Rig == "hq" {
+			return a.Name // hq-overseer
+		}
+		return a.Rig + "/polecats/" + a.Name
+	default:
+		return ""
+	}
+}
+
+// resolveAgentID sets a.AgentID from the session environment, falling back
+// to the derived identity. Must run after parseSessionName.
+func resolveAgentID(a *AgentLight) {
+	if id := detectAgentID(a.SessionName); id != "" {
+		a.AgentID = id
+		return
+	}
+	a.AgentID = deriveAgentID(a)
+}
@@ -0,0 +1,49 @@
+package activity
+
+import "testing"
+
+func TestDeriveAgentID(t *testing.T) {
+	tests := []struct {
+		name  string
+		agent AgentLight
+		want  string
+	}{
+		{"mayor", AgentLight{Role: "mayor", Rig: "hq", Name: "Mayor"}, "mayor"},
+		{"deacon", AgentLight{Role: "deacon", Rig: "hq", Name: "Deacon"}, "deacon"},
+		{"boot", AgentLight{Role: "deacon", Rig: "hq", Name: "Boot"}, "deacon-boot"},
+		{"dog", AgentLight{Role: "dog", Rig: "hq", Name: "alpha"}, "deacon/dogs/alpha"},
+		{"witness", AgentLight{Role: "witness", Rig: "gastown", Name: "witness"}, "gastown/witness"},
+		{"refinery", AgentLight{Role: "refinery", Rig: "gastown", Name: "refinery"}, "gastown/refinery"},
+		{"crew", AgentLight{Role: "crew", Rig: "gastown", Name: "joe"}, "gastown/crew/joe"},
+		{"polecat", AgentLight{Role: "polecat", Rig: "gastown", Name: "Toast"}, "gastown/polecats/Toast"},
+		{"unknown", AgentLight{Name: "weird"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deriveAgentID(&tt.agent); got != tt.want {
+				t.Errorf("deriveAgentID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyToolEvents_MatchesByAgentID(t *testing.T) {
+	toast := &AgentLight{SessionName: "gt-Toast", AgentID: "gastown/polecats/Toast", AgentType: "opencode"}
+	joe := &AgentLight{SessionName: "gt-crew-joe", AgentID: "gastown/crew/joe", AgentType: "opencode"}
+	m := &Model{
+		agents: []*AgentLight{toast, joe},
+		recentToolEvents: []toolEvent{
+			// Session name is stale (agent relaunched under a new session),
+			// but the ID still identifies the right agent.
+			{AgentID: "gastown/crew/joe", Session: "gt-old-session", Tool: "Bash(ls)", EventType: "tool_started"},
+		},
+	}
+	m.applyToolEvents()
+
+	if joe.CurrentTool != "Bash(ls)" {
+		t.Errorf("joe.CurrentTool = %q, want %q", joe.CurrentTool, "Bash(ls)")
+	}
+	if toast.CurrentTool != "" {
+		t.Errorf("toast.CurrentTool = %q, want empty", toast.CurrentTool)
+	}
+}
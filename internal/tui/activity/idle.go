@@ -0,0 +1,198 @@
+package activity
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/state"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Inactivity handling (gt top --screensaver, --exit-after): after a while
+// with no key press or mouse movement the screen dims to a one-glance
+// summary, and after longer gt top exits to free the terminal, leaving its
+// view behind for the next launch to restore.
+type idleConfig struct {
+	screensaver time.Duration // 0 = never
+	exitAfter   time.Duration // 0 = never
+}
+
+var screensaverStyle = lipgloss.NewStyle().Foreground(colorDim)
+
+// SetScreensaver sets how long without input before the screen dims to the
+// screensaver. Zero turns it off.
+func (m *Model) SetScreensaver(d time.Duration) {
+	m.idle.screensaver = d
+}
+
+// SetExitAfter sets how long without input before gt top exits, saving its
+// view for the next launch. Zero turns it off.
+func (m *Model) SetExitAfter(d time.Duration) {
+	m.idle.exitAfter = d
+}
+
+// noteInput records operator input. It reports whether the input only woke
+// the screensaver, in which case it shouldn't act on the view it hid.
+func (m *Model) noteInput(now time.Time) bool {
+	woke := m.screensaving(now)
+	m.lastInput = now
+	return woke
+}
+
+// screensaving reports whether the screensaver is on.
+func (m *Model) screensaving(now time.Time) bool {
+	return m.idle.screensaver > 0 && !m.lastInput.IsZero() && now.Sub(m.lastInput) >= m.idle.screensaver
+}
+
+// idleExpired reports whether gt top has gone unused for top.exit_after. On
+// expiry the view is saved for the next launch.
+func (m *Model) idleExpired(now time.Time) bool {
+	if m.idle.exitAfter <= 0 || m.lastInput.IsZero() || now.Sub(m.lastInput) < m.idle.exitAfter {
+		return false
+	}
+	_ = m.saveView()
+	m.autoExited = true
+	return true
+}
+
+// AutoExited reports whether gt top quit because nobody was using it.
+func (m *Model) AutoExited() bool {
+	return m.autoExited
+}
+
+// renderScreensaver renders the dimmed screen shown while idle: enough to
+// see at a glance that nothing needs a human.
+func (m *Model) renderScreensaver() string {
+	var waiting int
+	for _, a := range m.agents {
+		if a.Level == LevelWaitingForHuman {
+			waiting++
+		}
+	}
+	summary := screensaverStyle.Render(fmt.Sprintf("%d agents", len(m.agents)))
+	if score := m.renderScore(); score != "" {
+		summary = score + screensaverStyle.Render("  ·  ") + summary
+	}
+	if waiting > 0 {
+		summary += screensaverStyle.Render("  ·  ") + statWaitingStyle.Render(fmt.Sprintf("⚠ %d need a human", waiting))
+	}
+	lines := []string{
+		screensaverStyle.Render(m.townTitle() + " · idle"),
+		"",
+		summary,
+		"",
+		screensaverStyle.Render("any key or mouse movement to wake"),
+	}
+	block := lipgloss.JoinVertical(lipgloss.Center, lines...)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, block)
+}
+
+// savedView is the view an auto-exited gt top leaves for the next launch.
+type savedView struct {
+	Rig      string   `json:"rig,omitempty"`
+	Role     string   `json:"role,omitempty"`
+	Levels   []string `json:"levels,omitempty"`
+	Convoy   string   `json:"convoy,omitempty"`
+	ByConvoy bool     `json:"by_convoy,omitempty"`
+	Burndown string   `json:"burndown,omitempty"`
+	Pinned   []string `json:"pinned,omitempty"`
+	Hovered  string   `json:"hovered,omitempty"` // session name
+	Redact   bool     `json:"redact,omitempty"`
+	Ticker   bool     `json:"ticker,omitempty"`
+}
+
+// savedViewPath returns the per-user saved view path for a town.
+func savedViewPath(townRoot string) string {
+	sum := sha256.Sum256([]byte(townRoot))
+	return filepath.Join(state.StateDir(), fmt.Sprintf("top-view-%x.json", sum[:6]))
+}
+
+// saveView writes the current view for RestoreView.
+func (m *Model) saveView() error {
+	v := savedView{
+		Rig:      m.view.rig,
+		Role:     m.view.role,
+		Levels:   m.view.levels,
+		Convoy:   m.view.convoy,
+		ByConvoy: m.groupByConvoy,
+		Burndown: m.burndownID,
+		Redact:   m.redact,
+		Ticker:   m.ticker != nil,
+	}
+	for s := range m.pinned {
+		v.Pinned = append(v.Pinned, s)
+	}
+	sort.Strings(v.Pinned)
+	if m.hoveredAgent != nil {
+		v.Hovered = m.hoveredAgent.SessionName
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := savedViewPath(m.townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteFile(path, data, 0644)
+}
+
+// RestoreView restores the view a gt top left when it exited for
+// inactivity in this town, and discards it: it is restored once. Redaction
+// is only ever turned on, never off. Reports whether a view was restored.
+func (m *Model) RestoreView() bool {
+	path := savedViewPath(m.townRoot)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	_ = os.Remove(path)
+	var v savedView
+	if err := json.Unmarshal(data, &v); err != nil {
+		return false
+	}
+	if err := m.SetAgentFilter(v.Rig, v.Role, strings.Join(v.Levels, ",")); err != nil {
+		return false
+	}
+	m.SetConvoyFilter(v.Convoy)
+	m.SetGroupByConvoy(v.ByConvoy)
+	m.SetBurndown(v.Burndown)
+	if len(v.Pinned) > 0 {
+		m.pinned = make(map[string]bool, len(v.Pinned))
+		for _, s := range v.Pinned {
+			m.pinned[s] = true
+		}
+	}
+	m.restoreHover = v.Hovered
+	if v.Redact {
+		m.SetRedact(true)
+	}
+	if v.Ticker {
+		m.EnableTicker()
+	}
+	m.flashMessage = "restored the view from before gt top exited while idle"
+	m.flashTime = time.Now()
+	return true
+}
+
+// applyRestoredHover re-selects the agent that was hovered when the view
+// was saved, once its session shows up.
+func (m *Model) applyRestoredHover() {
+	if m.restoreHover == "" {
+		return
+	}
+	for _, a := range m.agents {
+		if a.SessionName == m.restoreHover {
+			m.restoreHover = ""
+			m.selectAgent(a)
+			return
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestScreensaver(t *testing.T) {
+	now := time.Now()
+	m := &Model{width: 100, height: 20, townName: "Test Town"}
+	m.agents = []*AgentLight{
+		{SessionName: "gt-gastown-Toast", Level: LevelWaitingForHuman},
+		{SessionName: "gt-gastown-Nux", Level: LevelActive},
+	}
+	m.SetScreensaver(10 * time.Minute)
+	m.lastInput = now.Add(-5 * time.Minute)
+	if m.screensaving(now) {
+		t.Fatal("screensaver on before 10 idle minutes")
+	}
+
+	m.lastInput = now.Add(-11 * time.Minute)
+	if !m.screensaving(now) {
+		t.Fatal("screensaver off after 11 idle minutes")
+	}
+	out := m.View()
+	for _, want := range []string{"idle", "2 agents", "1 need a human"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("screensaver missing %q:\n%s", want, out)
+		}
+	}
+
+	// The waking key is swallowed: "f" would otherwise pin.
+	m.hoveredAgent = m.agents[0]
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if m.screensaving(time.Now()) || m.pinned["gt-gastown-Toast"] {
+		t.Errorf("waking key: screensaving %v, pinned %v", m.screensaving(time.Now()), m.pinned)
+	}
+}
+
+func TestIdleExitSavesAndRestoresView(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	townRoot := t.TempDir()
+	toast := &AgentLight{SessionName: "gt-gastown-Toast", Rig: "gastown"}
+
+	m := &Model{townRoot: townRoot, agents: []*AgentLight{toast}}
+	if err := m.SetAgentFilter("gastown", "", "cold,cool"); err != nil {
+		t.Fatal(err)
+	}
+	m.SetGroupByConvoy(true)
+	m.pinned = map[string]bool{"gt-gastown-Toast": true}
+	m.hoveredAgent = toast
+	m.SetRedact(true)
+	m.SetExitAfter(8 * time.Hour)
+
+	m.lastInput = time.Now().Add(-7 * time.Hour)
+	if m.idleExpired(time.Now()) {
+		t.Fatal("exited before 8 idle hours")
+	}
+	m.lastInput = time.Now().Add(-9 * time.Hour)
+	if !m.idleExpired(time.Now()) || !m.AutoExited() {
+		t.Fatal("didn't exit after 9 idle hours")
+	}
+
+	next := &Model{townRoot: townRoot}
+	if !next.RestoreView() {
+		t.Fatal("no view to restore")
+	}
+	if next.view.rig != "gastown" || strings.Join(next.view.levels, ",") != "cold,cool" || !next.groupByConvoy ||
+		!next.pinned["gt-gastown-Toast"] || !next.redact {
+		t.Errorf("restored view = %+v grouped %v pinned %v redact %v", next.view, next.groupByConvoy, next.pinned, next.redact)
+	}
+	next.agents = []*AgentLight{toast}
+	next.applyRestoredHover()
+	if next.hoveredAgent != toast {
+		t.Error("hovered agent not re-selected")
+	}
+
+	if (&Model{townRoot: townRoot}).RestoreView() {
+		t.Error("view restored twice")
+	}
+}
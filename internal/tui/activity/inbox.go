@@ -0,0 +1,211 @@
+package activity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// inbox is the attention inbox overlay (i): a triage list of only the agents
+// someone has to act on, most urgent first, each a key away from being
+// nudged, acknowledged, muted or restarted, rather than spotted in the
+// panels.
+type inbox struct {
+	sel *AgentLight // selected agent; kept across polls as the list moves
+}
+
+// inboxItem is one agent in the inbox and why it's there.
+type inboxItem struct {
+	agent  *AgentLight
+	kind   string // "needs human", "hit limit", "stuck"
+	detail string
+}
+
+// inboxKinds ranks the inbox sections: an agent blocked on a human can't
+// move at all, a limited one resumes by itself at its reset, a stuck one
+// may yet get going.
+var inboxKinds = []string{"needs human", "hit limit", "stuck"}
+
+// openInbox opens the attention inbox with its first item selected.
+func (m *Model) openInbox() {
+	m.inbox = &inbox{}
+	if items := m.inboxItems(time.Now()); len(items) > 0 {
+		m.inbox.sel = items[0].agent
+	}
+}
+
+// inboxItems lists the agents needing attention: waiting on a human, oldest
+// first; at a usage limit, soonest reset first; then stuck (cold), longest
+// first. Muted agents, agents scheduled off and agents whose work waits in
+// the merge queue are left out, as are ones dismissed from the inbox in
+// their current state.
+func (m *Model) inboxItems(now time.Time) []inboxItem {
+	listed := func(a *AgentLight) bool {
+		if a.placeholder || m.muted(a) || a.showsScheduledOff() || a.waitsOnMerge() {
+			return false
+		}
+		level, ok := m.inboxDismissed[a]
+		return !ok || level != a.Level
+	}
+	oldestFirst := func(agents []*AgentLight) {
+		sort.SliceStable(agents, func(i, j int) bool {
+			return agents[i].LastChangeTime.Before(agents[j].LastChangeTime)
+		})
+	}
+
+	var waiting, stuck []*AgentLight
+	for _, a := range m.agents {
+		if !listed(a) {
+			continue
+		}
+		switch a.Level {
+		case LevelWaitingForHuman:
+			waiting = append(waiting, a)
+		case LevelCold:
+			stuck = append(stuck, a)
+		}
+	}
+	oldestFirst(waiting)
+	oldestFirst(stuck)
+
+	var items []inboxItem
+	for _, a := range waiting {
+		d := m.display(a)
+		detail := d.WaitingReason
+		if since := formatElapsed(now.Sub(a.LastChangeTime)); since != "" {
+			detail = strings.TrimPrefix(detail+" · waiting "+since, " · ")
+		}
+		items = append(items, inboxItem{a, inboxKinds[0], detail})
+	}
+	for _, a := range m.hitLimitByReset() {
+		if listed(a) {
+			items = append(items, inboxItem{a, inboxKinds[1], resetLabel(a.limitReset, now)})
+		}
+	}
+	for _, a := range stuck {
+		detail := "no output"
+		if since := formatElapsed(now.Sub(a.LastChangeTime)); since != "" {
+			detail += " for " + since
+		}
+		if a.WorkBeadID != "" {
+			detail += " on " + a.WorkBeadID
+		}
+		items = append(items, inboxItem{a, inboxKinds[2], detail})
+	}
+	return items
+}
+
+// handleInboxKey handles a key while the inbox is open. The actions apply
+// to the selected agent as if hovered; nudge and restart ask to confirm.
+func (m *Model) handleInboxKey(key string) {
+	items := m.inboxItems(time.Now())
+	sel := -1
+	for i, it := range items {
+		if it.agent == m.inbox.sel {
+			sel = i
+		}
+	}
+	if sel < 0 && len(items) > 0 {
+		sel = 0
+	}
+	move := func(i int) {
+		if i >= 0 && i < len(items) {
+			m.inbox.sel = items[i].agent
+		}
+	}
+
+	switch key {
+	case "esc", "i", "q":
+		m.inbox = nil
+		return
+	case "up", "k":
+		move(sel - 1)
+		return
+	case "down", "j", "tab":
+		move(sel + 1)
+		return
+	}
+	if sel < 0 {
+		return
+	}
+	a := items[sel].agent
+	switch key {
+	case "enter", "o":
+		m.inbox = nil
+		m.selectAgent(a)
+		if key == "o" {
+			m.attachTo(a)
+		}
+	case "n":
+		m.hoveredAgent = a
+		m.prepareNudge()
+	case "a":
+		m.hoveredAgent = a
+		m.acknowledgeHovered()
+		if m.inboxDismissed == nil {
+			m.inboxDismissed = make(map[*AgentLight]ActivityLevel)
+		}
+		m.inboxDismissed[a] = a.Level // back if its state changes
+		move(sel + 1)
+	case "m":
+		m.hoveredAgent = a
+		m.cycleMuteHovered()
+		move(sel + 1)
+	case "R":
+		m.hoveredAgent = a
+		m.prepareRestart()
+	}
+}
+
+// prepareNudge asks for confirmation to nudge the hovered agent.
+func (m *Model) prepareNudge() {
+	a := m.hoveredAgent
+	if a == nil {
+		return
+	}
+	if m.townRoot == "" {
+		m.flashMessage = "nudge needs a town (tmux-only mode)"
+		m.flashTime = time.Now()
+		return
+	}
+	m.pendingBulk = &bulkAction{
+		verb:     "nudge",
+		agents:   []*AgentLight{a},
+		sessions: []string{a.SessionName},
+		prompt:   "nudge " + a.SessionName,
+	}
+}
+
+// renderInbox renders the inbox overlay shown in place of the panels.
+func (m *Model) renderInbox() string {
+	items := m.inboxItems(time.Now())
+	lines := []string{titleStyle.Render("attention inbox") + "  " + subtitleStyle.Render(inboxCount(len(items))), ""}
+	if len(items) == 0 {
+		lines = append(lines, subtitleStyle.Render("nothing needs you right now"))
+	}
+	selected := false
+	for _, it := range items {
+		selected = selected || it.agent == m.inbox.sel
+	}
+	for i, it := range items {
+		a := m.display(it.agent)
+		line := padWidth(it.kind, 12) + " " + padWidth(truncateWidth(a.Name, 16, "~"), 16) + " " +
+			padWidth(truncateWidth(a.SessionName, 28, "~"), 28) + " " + it.detail
+		line = truncateWidth(line, max(m.width-12, 40), "…")
+		if it.agent == m.inbox.sel || (!selected && i == 0) {
+			line = switcherSelectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", helpStyle.Render("enter: select · o: attach · n: nudge · a: acknowledge · m: mute · R: restart · ↑/↓: move · esc: close"))
+	return probeBoxStyle.Render(strings.Join(lines, "\n"))
+}
+
+// inboxCount renders the item count, e.g. "3 items".
+func inboxCount(n int) string {
+	if n == 1 {
+		return "1 item"
+	}
+	return fmt.Sprintf("%d items", n)
+}
@@ -0,0 +1,94 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// inboxAgents are one agent of each kind the inbox lists or leaves out,
+// their ages and resets relative to now.
+func inboxAgents(now time.Time) []*AgentLight {
+	return []*AgentLight{
+		{SessionName: "gt-gastown-Toast", Name: "Toast", Level: LevelCold, LastChangeTime: now.Add(-40 * time.Minute), WorkBeadID: "gt-12"},
+		{SessionName: "gt-gastown-Nux", Name: "Nux", Level: LevelWaitingForHuman, WaitingReason: "permission", LastChangeTime: now.Add(-5 * time.Minute)},
+		{SessionName: "gt-gastown-Slit", Name: "Slit", Level: LevelHitLimit, limitReset: resetClock{src: "resets 5pm", at: now.Add(2 * time.Hour)}},
+		{SessionName: "gt-gastown-Rictus", Name: "Rictus", Level: LevelHitLimit, limitReset: resetClock{src: "resets 3pm", at: now.Add(time.Hour)}},
+		{SessionName: "gt-gastown-Furiosa", Name: "Furiosa", Level: LevelCold, LastChangeTime: now.Add(-90 * time.Minute)},
+		{SessionName: "gt-gastown-Dag", Name: "Dag", Level: LevelWaitingForHuman, LastChangeTime: now.Add(-20 * time.Minute)},
+		{SessionName: "gt-gastown-Ace", Name: "Ace", Level: LevelActive},
+		{SessionName: "gt-gastown-Capable", Name: "Capable", Level: LevelCold, MergeQueuePos: 2, LastChangeTime: now.Add(-2 * time.Hour)},
+	}
+}
+
+func inboxNames(items []inboxItem) string {
+	var names []string
+	for _, it := range items {
+		names = append(names, it.agent.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+func TestInboxOrder(t *testing.T) {
+	now := time.Now()
+	m := testModel(inboxAgents(now), modelSize(140, 40))
+	items := m.inboxItems(now)
+
+	// Needs human oldest first, hit limit soonest reset first, stuck
+	// longest first; active and merge-queued agents left out.
+	if got, want := inboxNames(items), "Dag Nux Rictus Slit Furiosa Toast"; got != want {
+		t.Fatalf("inbox = %q, want %q", got, want)
+	}
+	if items[1].kind != "needs human" || !strings.Contains(items[1].detail, "permission") {
+		t.Errorf("Nux item = %+v, want needs human with its reason", items[1])
+	}
+	if items[5].kind != "stuck" || !strings.Contains(items[5].detail, "gt-12") {
+		t.Errorf("Toast item = %+v, want stuck on gt-12", items[5])
+	}
+}
+
+func TestInboxKeys(t *testing.T) {
+	now := time.Now()
+	m := testModel(inboxAgents(now), modelSize(140, 40))
+	m.townRoot = t.TempDir()
+	typeKeys(m, "i")
+	if m.inbox == nil || m.inbox.sel == nil || m.inbox.sel.Name != "Dag" {
+		t.Fatalf("i: inbox = %+v, want it open on Dag", m.inbox)
+	}
+
+	// Acknowledging drops the item and moves on, until its level changes.
+	typeKeys(m, "a")
+	if got := inboxNames(m.inboxItems(now)); strings.Contains(got, "Dag") {
+		t.Errorf("after a: inbox = %q, want Dag dismissed", got)
+	}
+	if m.inbox.sel.Name != "Nux" {
+		t.Errorf("after a: selected %s, want Nux", m.inbox.sel.Name)
+	}
+	m.agents[5].Level = LevelCold
+	if got := inboxNames(m.inboxItems(now)); !strings.Contains(got, "Dag") {
+		t.Errorf("Dag went cold: inbox = %q, want it back", got)
+	}
+
+	// Restart asks to confirm, for the selected agent.
+	typeKeys(m, "down", "R")
+	if m.pendingBulk == nil || m.pendingBulk.sessions[0] != "gt-gastown-Rictus" {
+		t.Fatalf("R: pending = %+v, want a restart of Rictus", m.pendingBulk)
+	}
+	typeKeys(m, "x") // cancels
+	if m.pendingBulk != nil || m.inbox == nil {
+		t.Fatalf("cancel: pending = %+v, inbox = %+v; want the inbox still open", m.pendingBulk, m.inbox)
+	}
+
+	typeKeys(m, "enter")
+	if m.inbox != nil || m.hoveredAgent == nil || m.hoveredAgent.Name != "Rictus" {
+		t.Errorf("enter: inbox = %+v, hovered = %v; want closed with Rictus selected", m.inbox, m.hoveredAgent)
+	}
+}
+
+func TestRenderInboxEmpty(t *testing.T) {
+	m := &Model{width: 120, agents: []*AgentLight{{SessionName: "gt-gastown-Ace", Name: "Ace", Level: LevelActive}}}
+	m.openInbox()
+	if out := m.renderInbox(); !strings.Contains(out, "nothing needs you") || !strings.Contains(out, "0 items") {
+		t.Errorf("empty inbox rendered:\n%s", out)
+	}
+}
@@ -0,0 +1,77 @@
+package activity
+
+import "slices"
+
+// Keyboard selection: ↑/↓ (or k/j) move a selection through the agent rows
+// in the order the panels show them, so agents can be picked without a
+// mouse, e.g. over SSH without mouse reporting. The selection is the hover:
+// its detail line replaces the help bar and every key that acts on the
+// hovered agent acts on it. Its row stays highlighted until esc clears it or
+// the mouse hovers something else; enter attaches, like a double-click.
+
+// selectionRows returns the agent rows in display order: pinned agents,
+// then each rig's rows (or each convoy group's, when grouped).
+func (m *Model) selectionRows() []*AgentLight {
+	rows := m.pinnedAgents()
+	if m.groupByConvoy {
+		for _, id := range m.groupOrder() {
+			rows = append(rows, m.unpinnedAgents(m.agentsForGroup(id))...)
+		}
+		return rows
+	}
+	for _, rig := range m.rigs {
+		rows = append(rows, m.panelAgents(rig)...)
+	}
+	return rows
+}
+
+// moveSelection moves the keyboard selection by delta rows, clamped to the
+// first and last. With nothing selected, down starts at the first row and
+// up at the last.
+func (m *Model) moveSelection(delta int) {
+	rows := m.selectionRows()
+	if len(rows) == 0 {
+		return
+	}
+	i := slices.Index(rows, m.hoveredAgent)
+	switch {
+	case i < 0 && delta > 0:
+		i = 0
+	case i < 0:
+		i = len(rows) - 1
+	default:
+		i = min(max(i+delta, 0), len(rows)-1)
+	}
+	m.hoveredAgent = rows[i]
+	m.keySelected = true
+	m.fetchAgentDetails(rows[i])
+}
+
+// clearSelection drops the keyboard selection.
+func (m *Model) clearSelection() {
+	m.hoveredAgent = nil
+	m.keySelected = false
+}
+
+// isKeySelected reports whether a's row is highlighted as the keyboard
+// selection.
+func (m *Model) isKeySelected(a *AgentLight) bool {
+	return m.keySelected && a == m.hoveredAgent
+}
+
+// pruneSelection drops a keyboard selection whose session went away, so the
+// detail line doesn't describe an agent no longer shown.
+func (m *Model) pruneSelection() {
+	if !m.keySelected {
+		return
+	}
+	if slices.Contains(m.agents, m.hoveredAgent) {
+		return
+	}
+	for _, p := range m.placeholders {
+		if p == m.hoveredAgent {
+			return
+		}
+	}
+	m.clearSelection()
+}
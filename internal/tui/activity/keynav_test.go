@@ -0,0 +1,75 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+)
+
+// keynavAgents lists beads' crew member ahead of gastown's polecats, so
+// selection order has to come from the rig order, not the agent order.
+func keynavAgents() []*AgentLight {
+	toast := &AgentLight{SessionName: "gt-gastown-Toast", Name: "Toast", Rig: "gastown", Role: "polecat"}
+	nux := &AgentLight{SessionName: "gt-gastown-Nux", Name: "Nux", Rig: "gastown", Role: "polecat"}
+	max := &AgentLight{SessionName: "gt-beads-crew-max", Name: "max", Rig: "beads", Role: "crew"}
+	return []*AgentLight{max, toast, nux}
+}
+
+func TestKeyboardSelection(t *testing.T) {
+	m := testModel(keynavAgents(), modelSize(160, 40), modelRigs("gastown", "beads"))
+	rows := m.selectionRows()
+	if len(rows) != 3 || rows[2].Name != "max" {
+		t.Fatalf("rows = %v, want gastown's agents then beads'", rows)
+	}
+
+	typeKeys(m, "down")
+	if m.hoveredAgent != rows[0] || !m.keySelected {
+		t.Fatalf("down selected %v, want the first row", m.hoveredAgent)
+	}
+	typeKeys(m, "j", "j", "j")
+	if m.hoveredAgent != rows[2] {
+		t.Errorf("j past the end selected %v, want the last row", m.hoveredAgent.Name)
+	}
+	typeKeys(m, "k", "up")
+	if m.hoveredAgent != rows[0] {
+		t.Errorf("k, up selected %v, want the first row", m.hoveredAgent.Name)
+	}
+	if !m.isKeySelected(rows[0]) || m.isKeySelected(rows[1]) {
+		t.Error("only the selected row should be highlighted")
+	}
+	if got := m.renderHoverDetail(); !strings.Contains(got, rows[0].SessionName) {
+		t.Errorf("view doesn't show the selection's detail line:\n%s", got)
+	}
+
+	// esc clears the selection instead of quitting.
+	typeKeys(m, "esc")
+	if m.hoveredAgent != nil || m.keySelected {
+		t.Errorf("esc left %v selected", m.hoveredAgent)
+	}
+
+	// Up with nothing selected starts from the bottom.
+	typeKeys(m, "up")
+	if m.hoveredAgent != rows[2] {
+		t.Errorf("up from nothing selected %v, want the last row", m.hoveredAgent)
+	}
+
+	// The mouse takes over.
+	m.mouseX, m.mouseY = -1, -1
+	m.updateHoveredAgent()
+	if m.keySelected {
+		t.Error("a mouse move kept the keyboard selection")
+	}
+}
+
+func TestPruneSelection(t *testing.T) {
+	m := testModel(keynavAgents(), modelSize(160, 40), modelRigs("gastown", "beads"))
+	typeKeys(m, "down")
+	m.pruneSelection()
+	if m.hoveredAgent == nil {
+		t.Fatal("pruned a selection still running")
+	}
+	m.agents = m.agents[:1]
+	m.pruneSelection()
+	if m.hoveredAgent != nil || m.keySelected {
+		t.Errorf("selection %v survived its session going away", m.hoveredAgent)
+	}
+}
@@ -0,0 +1,353 @@
+package activity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// DefaultKubernetesSelector is the pod label selector used when town
+// settings don't set top.kubernetes.selector.
+const DefaultKubernetesSelector = "app.kubernetes.io/part-of=gastown"
+
+// Pod annotations read by the Kubernetes source. A pod is placed either by
+// gastown.session (its session name, as for Docker containers) or by
+// gastown.rig, gastown.role and gastown.name, from which the session name
+// is derived the way gt names tmux sessions.
+const (
+	KubernetesSessionAnnotation   = DockerSessionLabel   // explicit session name
+	KubernetesRigAnnotation       = "gastown.rig"        // rig name, for rig-level roles
+	KubernetesRoleAnnotation      = "gastown.role"       // mayor, deacon, witness, refinery, crew, polecat, dog
+	KubernetesNameAnnotation      = "gastown.name"       // crew, polecat or dog name
+	KubernetesAgentAnnotation     = DockerAgentLabel     // agent type
+	KubernetesHeartbeatAnnotation = DockerHeartbeatLabel // heartbeat file path inside the agent container
+	KubernetesContainerAnnotation = "gastown.container"  // agent container in multi-container pods
+
+	// KubernetesHeartbeatStateAnnotation carries the heartbeat itself (the
+	// JSON gt heartbeat writes), for agents that keep it on their pod with
+	// kubectl annotate. It comes with the pod listing, so no exec is needed.
+	KubernetesHeartbeatStateAnnotation = "gastown.heartbeat-state"
+)
+
+const (
+	kubectlTimeout = 5 * time.Second  // per kubectl call; API servers are further away than dockerd
+	kubectlBackoff = 30 * time.Second // how long an unreachable cluster is left alone
+
+	// podHeartbeatInterval is how often a heartbeat file is read with
+	// kubectl exec, which starts a process in the pod; polls in between
+	// reuse the last read.
+	podHeartbeatInterval = 30 * time.Second
+)
+
+// kubernetesSource discovers agents running in Kubernetes pods with kubectl
+// and reads their status the way dockerSource does: pod logs stand in for
+// the pane, and the heartbeat (from the pod's annotation, else its file via
+// kubectl exec) for self-reported state. It is only used from the poll
+// command.
+type kubernetesSource struct {
+	selector  string
+	namespace string // "" for the kubeconfig context's namespace, "*" for all
+	context   string // kubeconfig context, "" for the current one
+
+	// run executes kubectl; swapped in tests.
+	run  func(ctx context.Context, args ...string) ([]byte, error)
+	down time.Time // listing pods failed; retry after this
+	err  error     // why listing pods last failed, nil once it works again
+
+	// heartbeats caches heartbeat files read with kubectl exec, by pod.
+	mu         sync.Mutex
+	heartbeats map[string]podHeartbeat
+}
+
+// podHeartbeat is a heartbeat file's contents and when they were read.
+type podHeartbeat struct {
+	data []byte
+	at   time.Time
+}
+
+func newKubernetesSource(cfg *config.TopKubernetesConfig) *kubernetesSource {
+	s := &kubernetesSource{selector: DefaultKubernetesSelector, run: runKubectl, heartbeats: make(map[string]podHeartbeat)}
+	if cfg != nil {
+		if cfg.Selector != "" {
+			s.selector = cfg.Selector
+		}
+		s.namespace, s.context = cfg.Namespace, cfg.Context
+	}
+	return s
+}
+
+// runKubectl runs a kubectl command, returning its stdout. Stderr (warnings
+// such as deprecation notices) is kept out of the output, which callers
+// parse, and goes into the error when the command fails.
+func runKubectl(ctx context.Context, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		sub := args[0]
+		if sub == "--context" && len(args) > 2 {
+			sub = args[2]
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("kubectl %s: %w", sub, ctx.Err())
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("kubectl %s: %s", sub, msg)
+		}
+		return nil, fmt.Errorf("kubectl %s: %w", sub, err)
+	}
+	return out, nil
+}
+
+// EnableKubernetes makes gt top also monitor agents in Kubernetes pods. cfg
+// may be nil for the default selector and the current kubeconfig context.
+func (m *Model) EnableKubernetes(cfg *config.TopKubernetesConfig) {
+	if m.kubernetes == nil {
+		m.kubernetes = newKubernetesSource(cfg)
+	}
+}
+
+// kubectl prefixes args with the configured context.
+func (s *kubernetesSource) kubectl(ctx context.Context, args ...string) ([]byte, error) {
+	if s.context != "" {
+		args = append([]string{"--context", s.context}, args...)
+	}
+	return s.run(ctx, args...)
+}
+
+// podList is kubectl get pods -o json, reduced to the fields gt top reads.
+type podList struct {
+	Items []struct {
+		Metadata struct {
+			Name              string            `json:"name"`
+			Namespace         string            `json:"namespace"`
+			CreationTimestamp time.Time         `json:"creationTimestamp"`
+			Annotations       map[string]string `json:"annotations"`
+		} `json:"metadata"`
+		Status struct {
+			Phase string `json:"phase"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// agentPod is one running pod placed as an agent.
+type agentPod struct {
+	namespace, name, session, agentType, heartbeat, container string
+	heartbeatState                                            string // the heartbeat annotation's JSON, if any
+	created                                                   time.Time
+}
+
+// list returns the running pods matching the selector that can be placed.
+func (s *kubernetesSource) list() ([]agentPod, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kubectlTimeout)
+	defer cancel()
+	args := []string{"get", "pods", "-l", s.selector, "-o", "json"}
+	switch s.namespace {
+	case "":
+	case "*":
+		args = append(args, "--all-namespaces")
+	default:
+		args = append(args, "-n", s.namespace)
+	}
+	out, err := s.kubectl(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var list podList
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("parsing kubectl get pods: %w", err)
+	}
+
+	var pods []agentPod
+	for _, item := range list.Items {
+		if item.Status.Phase != "Running" {
+			continue
+		}
+		ann := item.Metadata.Annotations
+		name := podSessionName(ann)
+		if name == "" {
+			continue
+		}
+		pods = append(pods, agentPod{
+			namespace: item.Metadata.Namespace,
+			name:      item.Metadata.Name,
+			session:   name,
+			agentType: ann[KubernetesAgentAnnotation],
+			heartbeat: ann[KubernetesHeartbeatAnnotation],
+			container: ann[KubernetesContainerAnnotation],
+			created:   item.Metadata.CreationTimestamp,
+
+			heartbeatState: ann[KubernetesHeartbeatStateAnnotation],
+		})
+	}
+	return pods, nil
+}
+
+// podSessionName returns the session name a pod's annotations place it as,
+// or "" when they don't name one.
+func podSessionName(ann map[string]string) string {
+	if name := ann[KubernetesSessionAnnotation]; name != "" {
+		return name
+	}
+	role := ann[KubernetesRoleAnnotation]
+	if role == "" {
+		return ""
+	}
+	id := &session.AgentIdentity{Role: session.Role(role), Rig: ann[KubernetesRigAnnotation], Name: ann[KubernetesNameAnnotation]}
+	switch id.Role {
+	case session.RoleWitness, session.RoleRefinery:
+		if id.Rig == "" {
+			return ""
+		}
+	case session.RoleCrew, session.RolePolecat:
+		if id.Rig == "" || id.Name == "" {
+			return ""
+		}
+	case session.RoleDog:
+		if id.Name == "" {
+			return ""
+		}
+	}
+	return id.SessionName()
+}
+
+// poll returns a session for each agent pod, skipping session names already
+// taken by tmux or a container. depths is the log tail to read per session.
+func (s *kubernetesSource) poll(skip map[string]bool, depths map[string]int, now time.Time) []sessionInfo {
+	if now.Before(s.down) {
+		return nil
+	}
+	pods, err := s.list()
+	s.err = err
+	if err != nil {
+		s.down = now.Add(kubectlBackoff)
+		return nil
+	}
+
+	var out []sessionInfo
+	var placed []agentPod
+	live := make(map[string]bool)
+	for _, p := range pods {
+		live[p.namespace+"/"+p.name] = true
+		if skip[p.session] || !session.IsKnownSession(p.session) {
+			continue
+		}
+		skip[p.session] = true
+		si := sessionInfo{name: p.session, pod: p.namespace + "/" + p.name, agentType: p.agentType, captureDepth: depths[p.session]}
+		if !p.created.IsZero() {
+			si.created = p.created.Unix()
+		}
+		out = append(out, si)
+		placed = append(placed, p)
+	}
+
+	var wg sync.WaitGroup
+	for i := range out {
+		wg.Add(1)
+		go func(si *sessionInfo, p agentPod) {
+			defer wg.Done()
+			s.read(si, p)
+		}(&out[i], placed[i])
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	for pod := range s.heartbeats {
+		if !live[pod] {
+			delete(s.heartbeats, pod)
+		}
+	}
+	s.mu.Unlock()
+	return out
+}
+
+// read fills in a pod session from kubectl logs and its heartbeat.
+func (s *kubernetesSource) read(si *sessionInfo, p agentPod) {
+	ctx, cancel := context.WithTimeout(context.Background(), kubectlTimeout)
+	defer cancel()
+	target := []string{"-n", p.namespace, p.name}
+	if p.container != "" {
+		target = append(target, "-c", p.container)
+	}
+	readContainerAgent(si,
+		func() ([]byte, error) {
+			args := append([]string{"logs", "--timestamps", "--tail", fmt.Sprint(si.depth())}, target...)
+			return s.kubectl(ctx, args...)
+		},
+		func() ([]byte, error) {
+			return s.heartbeat(ctx, p, target)
+		})
+}
+
+// heartbeat returns a pod's heartbeat: its heartbeat annotation, else its
+// heartbeat file, read with kubectl exec at most every podHeartbeatInterval.
+// nil when the pod has neither.
+func (s *kubernetesSource) heartbeat(ctx context.Context, p agentPod, target []string) ([]byte, error) {
+	if p.heartbeatState != "" {
+		return []byte(p.heartbeatState), nil
+	}
+	if p.heartbeat == "" {
+		return nil, nil
+	}
+	pod := p.namespace + "/" + p.name
+	s.mu.Lock()
+	hb, ok := s.heartbeats[pod]
+	s.mu.Unlock()
+	if ok && time.Since(hb.at) < podHeartbeatInterval {
+		return hb.data, nil
+	}
+	args := append(append([]string{"exec"}, target...), "--", "cat", p.heartbeat)
+	out, err := s.kubectl(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.heartbeats[pod] = podHeartbeat{data: out, at: time.Now()}
+	s.mu.Unlock()
+	return out, nil
+}
+
+// attachCommand returns the shell command that attaches to an agent pod's
+// TTY, given as "namespace/name".
+func (s *kubernetesSource) attachCommand(pod string) string {
+	ns, name, _ := strings.Cut(pod, "/")
+	cmd := "kubectl"
+	if s.context != "" {
+		cmd += " --context " + s.context
+	}
+	return fmt.Sprintf("%s attach -it -n %s %s", cmd, ns, name)
+}
+
+// viaKubernetes routes a bulk action's pod agents to Kubernetes and its
+// other agents to fn. A restart deletes the pod for its controller to
+// replace; a kill is refused, since the controller would replace it too.
+func (b *bulkAction) viaKubernetes(k *kubernetesSource, restart bool, fn func(session string) error) func(session string) error {
+	pods := make(map[string]string)
+	for _, a := range b.agents {
+		if a.Pod != "" {
+			pods[a.SessionName] = a.Pod
+		}
+	}
+	return func(session string) error {
+		pod, ok := pods[session]
+		if !ok {
+			return fn(session)
+		}
+		if !restart {
+			return fmt.Errorf("pod %s: scale its workload down to stop it", pod)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		ns, name, _ := strings.Cut(pod, "/")
+		return commandError(k.kubectl(ctx, "delete", "pod", "-n", ns, name, "--wait=false"))
+	}
+}
@@ -0,0 +1,175 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+const testPodList = `{"items": [
+ {"metadata": {"name": "mayor-0", "namespace": "town", "creationTimestamp": "2026-10-15T08:00:00Z",
+   "annotations": {"gastown.role": "mayor", "gastown.heartbeat": "/gt/hb.json", "gastown.container": "agent"}},
+  "status": {"phase": "Running"}},
+ {"metadata": {"name": "deacon-0", "namespace": "town", "annotations": {"gastown.session": "hq-deacon"}},
+  "status": {"phase": "Pending"}},
+ {"metadata": {"name": "sidecar", "namespace": "town", "annotations": {}},
+  "status": {"phase": "Running"}}
+]}`
+
+func TestKubernetesSource_Poll(t *testing.T) {
+	var calls [][]string
+	s := newKubernetesSource(&config.TopKubernetesConfig{Namespace: "*", Context: "prod"})
+	s.run = func(ctx context.Context, args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		switch args[2] {
+		case "get":
+			return []byte(testPodList), nil
+		case "logs":
+			return []byte("2026-10-15T09:00:00Z thinking\n"), nil
+		case "exec":
+			return []byte(`{"timestamp":"2026-10-15T09:02:00Z","state":"working","context":"reviewing convoy"}`), nil
+		}
+		return nil, errors.New("unexpected")
+	}
+
+	sessions := s.poll(map[string]bool{}, nil, time.Now())
+	if len(sessions) != 1 {
+		t.Fatalf("sessions = %+v, want only the running mayor pod", sessions)
+	}
+	si := sessions[0]
+	if si.name != "hq-mayor" || si.pod != "town/mayor-0" {
+		t.Errorf("session = %+v", si)
+	}
+	if len(si.paneLines) != 1 || si.paneLines[0] != "thinking" || si.heartbeat == nil {
+		t.Errorf("lines = %q, heartbeat = %+v", si.paneLines, si.heartbeat)
+	}
+	if want := time.Date(2026, 10, 15, 9, 2, 0, 0, time.UTC).Unix(); si.activity != want {
+		t.Errorf("activity = %d, want the heartbeat's %d", si.activity, want)
+	}
+
+	for _, c := range calls {
+		if c[0] != "--context" || c[1] != "prod" {
+			t.Errorf("kubectl %v not run against the configured context", c)
+		}
+	}
+	if got := strings.Join(calls[0], " "); !strings.Contains(got, "-l "+DefaultKubernetesSelector) || !strings.Contains(got, "--all-namespaces") {
+		t.Errorf("list = kubectl %s", got)
+	}
+	for _, c := range calls[1:] {
+		if got := strings.Join(c, " "); !strings.Contains(got, "-n town mayor-0 -c agent") {
+			t.Errorf("read = kubectl %s, want the annotated container", got)
+		}
+	}
+}
+
+func TestKubernetesSource_SkipsTakenSessionsAndBacksOff(t *testing.T) {
+	n := 0
+	s := newKubernetesSource(nil)
+	s.run = func(ctx context.Context, args ...string) ([]byte, error) {
+		n++
+		if args[0] == "get" && n > 1 {
+			return nil, errors.New("connection refused")
+		}
+		return []byte(testPodList), nil
+	}
+	now := time.Now()
+
+	if got := s.poll(map[string]bool{"hq-mayor": true}, nil, now); len(got) != 0 {
+		t.Errorf("sessions = %+v, want hq-mayor left to tmux", got)
+	}
+	s.poll(map[string]bool{}, nil, now.Add(time.Second)) // fails
+	s.poll(map[string]bool{}, nil, now.Add(2*time.Second))
+	if n != 2 {
+		t.Errorf("kubectl called %d times, want no retry while backing off", n)
+	}
+}
+
+func TestPodSessionName(t *testing.T) {
+	tests := []struct {
+		ann  map[string]string
+		want string
+	}{
+		{map[string]string{"gastown.session": "gt-Toast", "gastown.role": "mayor"}, "gt-Toast"},
+		{map[string]string{"gastown.role": "deacon"}, "hq-deacon"},
+		{map[string]string{"gastown.role": "polecat", "gastown.rig": "gastown"}, ""},
+		{map[string]string{"gastown.rig": "gastown"}, ""},
+	}
+	for _, tt := range tests {
+		if got := podSessionName(tt.ann); got != tt.want {
+			t.Errorf("podSessionName(%v) = %q, want %q", tt.ann, got, tt.want)
+		}
+	}
+}
+
+func TestKubernetesAttachCommand(t *testing.T) {
+	s := newKubernetesSource(&config.TopKubernetesConfig{Context: "prod"})
+	if got, want := s.attachCommand("town/mayor-0"), "kubectl --context prod attach -it -n town mayor-0"; got != want {
+		t.Errorf("attachCommand = %q, want %q", got, want)
+	}
+}
+
+func TestKubernetesSource_Heartbeats(t *testing.T) {
+	const list = `{"items": [
+ {"metadata": {"name": "mayor-0", "namespace": "town",
+   "annotations": {"gastown.role": "mayor", "gastown.heartbeat": "/gt/hb.json"}},
+  "status": {"phase": "Running"}},
+ {"metadata": {"name": "deacon-0", "namespace": "town",
+   "annotations": {"gastown.role": "deacon", "gastown.heartbeat": "/gt/hb.json",
+     "gastown.heartbeat-state": "{\"timestamp\":\"2026-10-15T09:03:00Z\",\"state\":\"working\"}"}},
+  "status": {"phase": "Running"}}
+]}`
+	execs := map[string]int{}
+	s := newKubernetesSource(nil)
+	s.run = func(ctx context.Context, args ...string) ([]byte, error) {
+		switch args[0] {
+		case "get":
+			return []byte(list), nil
+		case "exec":
+			execs[args[3]]++ // exec -n town <pod>
+			return []byte(`{"timestamp":"2026-10-15T09:02:00Z","state":"working"}`), nil
+		}
+		return nil, nil
+	}
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		sessions := s.poll(map[string]bool{}, nil, now)
+		if len(sessions) != 2 || sessions[0].heartbeat == nil || sessions[1].heartbeat == nil {
+			t.Fatalf("poll %d: sessions = %+v, want both with heartbeats", i, sessions)
+		}
+	}
+	if execs["mayor-0"] != 1 || execs["deacon-0"] != 0 {
+		t.Errorf("kubectl exec calls = %v, want the mayor's file read once and the deacon's annotation used", execs)
+	}
+}
+
+func TestRunKubectl_KeepsStderrOutOfOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script stand-in")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"echo 'Warning: v1 ComponentStatus is deprecated' >&2\n" +
+		"if [ \"$1\" = get ]; then echo '{\"items\": []}'; exit 0; fi\n" +
+		"echo 'error: pods \"x\" not found' >&2\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(dir, "kubectl"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	s := newKubernetesSource(nil)
+	if pods, err := s.list(); err != nil || len(pods) != 0 {
+		t.Errorf("list = %v, %v; want no pods despite the warning", pods, err)
+	}
+	_, err := runKubectl(context.Background(), "delete", "pod", "x")
+	if err == nil || !strings.Contains(err.Error(), `pods "x" not found`) {
+		t.Errorf("err = %v, want kubectl's stderr", err)
+	}
+}
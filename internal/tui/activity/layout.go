@@ -0,0 +1,130 @@
+package activity
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// columnMinWidth is the narrowest column a rig panel is laid out in. Below
+// twice this width the panels stack in a single column.
+const columnMinWidth = 90
+
+// maxColumns caps the side-by-side columns on ultrawide terminals.
+const maxColumns = 3
+
+// panelChrome is the number of rows a panel adds around its agents:
+// header, top border, bottom border.
+const panelChrome = 3
+
+// panel is one bordered section (pinned or a rig) awaiting layout.
+type panel struct {
+	height int                        // rows, including panelChrome
+	render func(currentY *int) string // records agent positions as it renders
+}
+
+// columnCount returns how many columns to lay out n panels in.
+func (m *Model) columnCount(n int) int {
+	cols := m.width / columnMinWidth
+	if cols > maxColumns {
+		cols = maxColumns
+	}
+	if cols > n {
+		cols = n
+	}
+	if cols < 1 {
+		cols = 1
+	}
+	return cols
+}
+
+// layoutWidth is the width one panel is laid out against: the whole screen
+// in single-column mode, one column's share otherwise.
+func (m *Model) layoutWidth() int {
+	if m.colW > 0 {
+		return m.colW
+	}
+	return m.width
+}
+
+// renderPanels lays panels out top to bottom, splitting them into columns on
+// wide terminals. Panels keep their order, filling each column before the
+// next, with column breaks chosen to even out the column heights.
+func (m *Model) renderPanels(panels []panel, currentY *int) string {
+	cols := m.columnCount(len(panels))
+	if cols == 1 {
+		var out []string
+		for _, p := range panels {
+			out = append(out, p.render(currentY))
+		}
+		return strings.Join(out, "\n")
+	}
+
+	colW := m.width / cols
+	startY := *currentY
+	maxY := startY
+	var rendered []string
+	for c, group := range splitColumns(panels, cols) {
+		m.colX = 1 + c*colW // outerStyle pads one column on the left
+		m.colW = colW
+		y := startY
+		var out []string
+		for _, p := range group {
+			out = append(out, p.render(&y))
+		}
+		col := strings.Join(out, "\n")
+		if c < cols-1 {
+			// Pad to the full column width so the next column starts at colX.
+			col = lipgloss.NewStyle().Width(colW).Render(col)
+		}
+		rendered = append(rendered, col)
+		if y > maxY {
+			maxY = y
+		}
+	}
+	m.colX, m.colW = 0, 0
+
+	*currentY = maxY
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+}
+
+// splitColumns partitions panels into at most cols contiguous groups,
+// starting a new column once the current one reaches its share of the
+// total height.
+func splitColumns(panels []panel, cols int) [][]panel {
+	total := 0
+	for _, p := range panels {
+		total += p.height
+	}
+	target := (total + cols - 1) / cols
+
+	groups := make([][]panel, 0, cols)
+	var cur []panel
+	curH := 0
+	for i, p := range panels {
+		remainingPanels := len(panels) - i
+		remainingCols := cols - len(groups) - 1
+		// Break before p if this column is full, or if every remaining
+		// column still needs at least one panel.
+		if len(cur) > 0 && remainingCols > 0 &&
+			(curH+p.height > target || remainingPanels <= remainingCols) {
+			groups = append(groups, cur)
+			cur, curH = nil, 0
+		}
+		cur = append(cur, p)
+		curH += p.height
+	}
+	if len(cur) > 0 {
+		groups = append(groups, cur)
+	}
+	return groups
+}
+
+// containsPoint reports whether screen cell (x, y) falls on the agent's row.
+// A zero renderWidth (single-column layout) matches any x.
+func (a *AgentLight) containsPoint(x, y int) bool {
+	if a.renderY <= 0 || y < a.renderY || y >= a.renderY+a.renderHeight {
+		return false
+	}
+	return a.renderWidth == 0 || (x >= a.renderX && x < a.renderX+a.renderWidth)
+}
@@ -0,0 +1,125 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func testPanels(heights ...int) []panel {
+	var ps []panel
+	for _, h := range heights {
+		ps = append(ps, panel{height: h})
+	}
+	return ps
+}
+
+func groupHeights(groups [][]panel) []int {
+	var out []int
+	for _, g := range groups {
+		h := 0
+		for _, p := range g {
+			h += p.height
+		}
+		out = append(out, h)
+	}
+	return out
+}
+
+func TestSplitColumns(t *testing.T) {
+	tests := []struct {
+		heights []int
+		cols    int
+		want    []int
+	}{
+		{[]int{5, 5, 5, 5}, 2, []int{10, 10}},
+		{[]int{10, 3, 3, 3}, 2, []int{10, 9}},
+		{[]int{4, 4, 4, 4, 4, 4}, 3, []int{8, 8, 8}},
+		{[]int{20, 4}, 3, []int{20, 4}}, // fewer panels than columns
+		{[]int{3, 3, 30}, 2, []int{6, 30}},
+	}
+	for _, tt := range tests {
+		got := groupHeights(splitColumns(testPanels(tt.heights...), tt.cols))
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("splitColumns(%v, %d) heights = %v, want %v", tt.heights, tt.cols, got, tt.want)
+		}
+	}
+}
+
+func TestColumnCount(t *testing.T) {
+	tests := []struct{ width, panels, want int }{
+		{120, 4, 1},
+		{180, 4, 2},
+		{300, 4, 3},
+		{500, 4, 3},
+		{300, 2, 2},
+		{40, 0, 1},
+	}
+	for _, tt := range tests {
+		m := &Model{width: tt.width}
+		if got := m.columnCount(tt.panels); got != tt.want {
+			t.Errorf("columnCount(width=%d, panels=%d) = %d, want %d", tt.width, tt.panels, got, tt.want)
+		}
+	}
+}
+
+// layoutAgents are one agent in each of two rigs, gastown's first.
+func layoutAgents() []*AgentLight {
+	return []*AgentLight{
+		{SessionName: "gt-gastown-Toast", Name: "Toast", Rig: "gastown"},
+		{SessionName: "bd-beads-Nux", Name: "Nux", Rig: "beads"},
+	}
+}
+
+func TestRender_TwoColumnsOnWideTerminal(t *testing.T) {
+	m := testModel(layoutAgents(), modelSize(200, 40), modelTown("/town"))
+	toast, nux := m.agents[0], m.agents[1]
+	out := m.render()
+
+	if toast.renderY != nux.renderY {
+		t.Errorf("side-by-side rigs should share a row: %d vs %d", toast.renderY, nux.renderY)
+	}
+	if toast.renderWidth == 0 || nux.renderX < toast.renderX+toast.renderWidth {
+		t.Errorf("columns overlap: toast x=%d w=%d, nux x=%d", toast.renderX, toast.renderWidth, nux.renderX)
+	}
+
+	lines := strings.Split(out, "\n")
+	for i, l := range lines {
+		if w := lipgloss.Width(l); w > m.width {
+			t.Errorf("line %d is %d wide, exceeds terminal width %d", i, w, m.width)
+		}
+	}
+	// Each agent's name is drawn inside its own column on its recorded row.
+	row := ansi.Strip(lines[toast.renderY])
+	if i := strings.Index(row, "Toast"); i < toast.renderX || i >= toast.renderX+toast.renderWidth {
+		t.Errorf("Toast drawn at x=%d, outside its column [%d,%d): %q", i, toast.renderX, toast.renderX+toast.renderWidth, row)
+	}
+	if i := strings.Index(row, "Nux"); i < nux.renderX {
+		t.Errorf("Nux drawn at x=%d, before its column at %d: %q", i, nux.renderX, row)
+	}
+
+	if got := m.agentAt(nux.renderX+2, nux.renderY); got != nux {
+		t.Errorf("agentAt(right column) = %v, want Nux", got)
+	}
+	if got := m.agentAt(toast.renderX+2, toast.renderY); got != toast {
+		t.Errorf("agentAt(left column) = %v, want Toast", got)
+	}
+}
+
+func TestRender_SingleColumnOnNarrowTerminal(t *testing.T) {
+	m := testModel(layoutAgents(), modelSize(120, 40), modelTown("/town"))
+	toast, nux := m.agents[0], m.agents[1]
+	m.render()
+	if nux.renderY <= toast.renderY {
+		t.Errorf("narrow layout should stack rigs: toast y=%d, nux y=%d", toast.renderY, nux.renderY)
+	}
+	if toast.renderWidth != 0 || m.colW != 0 {
+		t.Error("single-column layout should match hover at any x")
+	}
+	if got := m.agentAt(100, nux.renderY); got != nux {
+		t.Errorf("agentAt = %v, want Nux", got)
+	}
+}
@@ -0,0 +1,330 @@
+package activity
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// LedgerFileName is the file under <town>/.runtime/ where gt top appends
+// each poll's per-agent status changes, so gt forensics can rebuild what
+// the monitor showed for an agent at any past time. Only derived fields are
+// kept, never pane content.
+const LedgerFileName = "top-ledger.jsonl"
+
+// defaultLedgerRetention is how long ledger entries are kept in full when
+// town settings top.ledger_retention is unset. Older entries are folded
+// into one snapshot per session, so state at the cutoff is still known.
+const defaultLedgerRetention = 7 * 24 * time.Hour
+
+// ledgerPruneInterval is how often gt top folds entries past retention.
+const ledgerPruneInterval = time.Hour
+
+// LedgerEntry is one line of the ledger: the fields of one session that
+// changed at Time. A field set to "" was cleared.
+type LedgerEntry struct {
+	Time    time.Time         `json:"t"`
+	Session string            `json:"s"`
+	Full    bool              `json:"full,omitempty"` // Fields replace the recorded state rather than update it
+	Gone    bool              `json:"gone,omitempty"` // the session ended
+	Fields  map[string]string `json:"f,omitempty"`
+}
+
+// LedgerPath returns the ledger path for a town.
+func LedgerPath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), LedgerFileName)
+}
+
+// ledgerFields is the agent state recorded in the ledger: identity, level,
+// work, and every pane-derived field except the token count, which changes
+// on nearly every poll of a working agent.
+func (a *AgentLight) ledgerFields() map[string]string {
+	f := make(map[string]string)
+	set := func(k, v string) {
+		if v != "" {
+			f[k] = v
+		}
+	}
+	set("agent_id", a.AgentID)
+	set("level", a.Level.String())
+	set("work_bead", a.WorkBeadID)
+	set("task", a.CurrentTask)
+	for _, pf := range parsedFields(a) {
+		if pf.Name != "tokens" {
+			set(pf.Name, pf.Value)
+		}
+	}
+	return f
+}
+
+// diffFields returns the fields of cur that differ from prev, with "" for
+// fields cur no longer has.
+func diffFields(prev, cur map[string]string) map[string]string {
+	d := make(map[string]string)
+	for k, v := range cur {
+		if prev[k] != v {
+			d[k] = v
+		}
+	}
+	for k := range prev {
+		if _, ok := cur[k]; !ok {
+			d[k] = ""
+		}
+	}
+	return d
+}
+
+// recordLedger appends the changes since the last poll to the ledger. The
+// first poll writes a full snapshot of each agent, so state left by an
+// earlier gt top doesn't leak into this one's. Best-effort: tmux-only mode
+// has no town, and a failed write resends full snapshots next poll.
+func (m *Model) recordLedger(now time.Time) {
+	if m.townRoot == "" {
+		return
+	}
+	if m.ledger == nil {
+		m.ledger = make(map[string]map[string]string)
+	}
+
+	var entries []LedgerEntry
+	seen := make(map[string]bool, len(m.agents))
+	for _, a := range m.agents {
+		seen[a.SessionName] = true
+		cur := a.ledgerFields()
+		prev, ok := m.ledger[a.SessionName]
+		switch {
+		case !ok:
+			entries = append(entries, LedgerEntry{Time: now, Session: a.SessionName, Full: true, Fields: cur})
+		default:
+			if d := diffFields(prev, cur); len(d) > 0 {
+				entries = append(entries, LedgerEntry{Time: now, Session: a.SessionName, Fields: d})
+			}
+		}
+		m.ledger[a.SessionName] = cur
+	}
+	var gone []string
+	for s := range m.ledger {
+		if !seen[s] {
+			gone = append(gone, s)
+		}
+	}
+	sort.Strings(gone)
+	for _, s := range gone {
+		entries = append(entries, LedgerEntry{Time: now, Session: s, Gone: true})
+		delete(m.ledger, s)
+	}
+
+	if err := appendLedger(LedgerPath(m.townRoot), entries); err != nil {
+		m.ledger = nil
+	}
+
+	if now.Sub(m.lastLedgerPrune) >= ledgerPruneInterval {
+		m.lastLedgerPrune = now
+		_ = PruneLedger(m.townRoot, now.Add(-m.ledgerRetention))
+	}
+}
+
+// appendLedger appends entries to the ledger, one JSON object per line.
+func appendLedger(path string, entries []LedgerEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// Several gt top instances may share a town; PruneLedger rewrites the file.
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return err
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: ledger holds no secrets, like the events file
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadLedger reads every ledger entry for a town, oldest first. Malformed
+// lines (e.g. a write cut short by a crash) are skipped. A missing ledger
+// reads as empty.
+func ReadLedger(townRoot string) ([]LedgerEntry, error) {
+	f, err := os.Open(LedgerPath(townRoot))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []LedgerEntry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var e LedgerEntry
+		if json.Unmarshal(sc.Bytes(), &e) == nil && e.Session != "" {
+			entries = append(entries, e)
+		}
+	}
+	return entries, sc.Err()
+}
+
+// PruneLedger folds entries older than cutoff into one full snapshot per
+// session, dated at the session's last folded change, and drops sessions
+// that had ended by then. State at any time after cutoff is unchanged.
+func PruneLedger(townRoot string, cutoff time.Time) error {
+	path := LedgerPath(townRoot)
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return err
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	entries, err := ReadLedger(townRoot)
+	if err != nil || len(entries) == 0 || !entries[0].Time.Before(cutoff) {
+		return err
+	}
+
+	folded := make(map[string]*LedgerEntry)
+	var order []string
+	var kept []LedgerEntry
+	for _, e := range entries {
+		if !e.Time.Before(cutoff) {
+			kept = append(kept, e)
+			continue
+		}
+		snap, ok := folded[e.Session]
+		if !ok {
+			snap = &LedgerEntry{Session: e.Session, Full: true}
+			folded[e.Session] = snap
+			order = append(order, e.Session)
+		}
+		applyLedgerEntry(snap, e)
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return folded[order[i]].Time.Before(folded[order[j]].Time)
+	})
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, s := range order {
+		if snap := folded[s]; !snap.Gone {
+			if err := enc.Encode(snap); err != nil {
+				return err
+			}
+		}
+	}
+	for _, e := range kept {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return util.AtomicWriteFile(path, buf.Bytes(), 0644)
+}
+
+// applyLedgerEntry folds e into the snapshot state.
+func applyLedgerEntry(state *LedgerEntry, e LedgerEntry) {
+	state.Time = e.Time
+	state.Gone = e.Gone
+	if e.Gone {
+		state.Fields = nil
+		return
+	}
+	if e.Full || state.Fields == nil {
+		state.Fields = make(map[string]string, len(e.Fields))
+	}
+	for k, v := range e.Fields {
+		if v == "" {
+			delete(state.Fields, k)
+		} else {
+			state.Fields[k] = v
+		}
+	}
+}
+
+// LedgerState is an agent's status at a past time, rebuilt from the ledger.
+type LedgerState struct {
+	Session string               `json:"session"`
+	At      time.Time            `json:"at"`
+	Fields  map[string]string    `json:"fields"`
+	Since   map[string]time.Time `json:"since"`          // when each field took its value
+	Updated time.Time            `json:"updated"`        // the session's last change by At
+	Gone    bool                 `json:"gone,omitempty"` // the session had ended by At
+	Changes []LedgerEntry        `json:"changes"`        // the session's entries up to At, oldest first
+}
+
+// ReconstructAt rebuilds the state of agent at the given time. The agent is
+// a session name or an agent ID (e.g. gastown/crew/max); when several
+// sessions carried the ID, the one most recently recorded by then wins.
+// Returns nil when the ledger has nothing for the agent by that time.
+func ReconstructAt(entries []LedgerEntry, agent string, at time.Time) *LedgerState {
+	session := ""
+	var latest time.Time
+	ids := make(map[string]string) // session -> agent_id
+	for _, e := range entries {
+		if e.Time.After(at) {
+			continue // instances sharing a town may interleave slightly out of order
+		}
+		if id, ok := e.Fields["agent_id"]; ok {
+			ids[e.Session] = id
+		}
+		if (e.Session == agent || ids[e.Session] == agent) && !e.Time.Before(latest) {
+			session, latest = e.Session, e.Time
+		}
+	}
+	if session == "" {
+		return nil
+	}
+
+	st := &LedgerState{Session: session, At: at, Since: make(map[string]time.Time)}
+	var snap LedgerEntry
+	for _, e := range entries {
+		if e.Time.After(at) || e.Session != session {
+			continue
+		}
+		before := maps.Clone(snap.Fields)
+		applyLedgerEntry(&snap, e)
+		for k, v := range snap.Fields {
+			if before[k] != v {
+				st.Since[k] = e.Time
+			}
+		}
+		st.Changes = append(st.Changes, e)
+	}
+	st.Fields = snap.Fields
+	if st.Fields == nil {
+		st.Fields = map[string]string{}
+	}
+	for k := range st.Since {
+		if _, ok := st.Fields[k]; !ok {
+			delete(st.Since, k)
+		}
+	}
+	st.Gone = snap.Gone
+	st.Updated = snap.Time
+	return st
+}
@@ -0,0 +1,121 @@
+package activity
+
+import (
+	"testing"
+	"time"
+)
+
+// ledgerTown records three polls of one agent: working, then waiting on a
+// human, then gone. It returns the poll times.
+func ledgerTown(t *testing.T) (string, []time.Time) {
+	t.Helper()
+	town := t.TempDir()
+	t0 := time.Date(2025, 1, 10, 2, 0, 0, 0, time.UTC)
+	times := []time.Time{t0, t0.Add(time.Hour), t0.Add(2 * time.Hour)}
+
+	a := &AgentLight{SessionName: "gt-gastown-crew-max", AgentID: "gastown/crew/max",
+		Level: LevelActive, StatusText: "Thinking…", CurrentTool: "Bash", TokenCount: 1200}
+	m := &Model{townRoot: town, agents: []*AgentLight{a}, lastLedgerPrune: t0, ledgerRetention: defaultLedgerRetention}
+	m.recordLedger(times[0])
+
+	a.Level, a.StatusText, a.CurrentTool = LevelWaitingForHuman, "", ""
+	a.WaitingForHuman, a.WaitingReason = true, "Permission prompt"
+	a.TokenCount = 5000 // not recorded
+	m.recordLedger(times[1])
+	m.recordLedger(times[1].Add(time.Minute)) // no change, no entry
+
+	m.agents = nil
+	m.recordLedger(times[2])
+	return town, times
+}
+
+func TestRecordLedger_Diffs(t *testing.T) {
+	town, _ := ledgerTown(t)
+	entries, err := ReadLedger(town)
+	if err != nil {
+		t.Fatalf("ReadLedger: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("entries = %+v, want full snapshot, diff, gone", entries)
+	}
+	if !entries[0].Full || entries[0].Fields["tool"] != "Bash" || entries[0].Fields["tokens"] != "" {
+		t.Errorf("first entry = %+v, want full snapshot without tokens", entries[0])
+	}
+	d := entries[1].Fields
+	if entries[1].Full || d["level"] != "waiting" || d["waiting"] != "Permission prompt" || d["tool"] != "" {
+		t.Errorf("diff = %+v", entries[1])
+	}
+	if _, ok := d["tool"]; !ok {
+		t.Errorf("diff %+v does not clear tool", d)
+	}
+	if _, ok := d["agent_id"]; ok {
+		t.Errorf("diff %+v repeats an unchanged field", d)
+	}
+	if !entries[2].Gone {
+		t.Errorf("last entry = %+v, want gone", entries[2])
+	}
+}
+
+func TestReconstructAt(t *testing.T) {
+	town, times := ledgerTown(t)
+	entries, _ := ReadLedger(town)
+
+	if st := ReconstructAt(entries, "gastown/crew/max", times[0].Add(-time.Second)); st != nil {
+		t.Errorf("state before the first entry = %+v, want nil", st)
+	}
+
+	st := ReconstructAt(entries, "gastown/crew/max", times[1].Add(30*time.Minute))
+	if st == nil || st.Session != "gt-gastown-crew-max" {
+		t.Fatalf("state by agent ID = %+v", st)
+	}
+	if st.Fields["level"] != "waiting" || st.Fields["tool"] != "" || st.Fields["agent_id"] != "gastown/crew/max" {
+		t.Errorf("fields = %+v", st.Fields)
+	}
+	if !st.Since["level"].Equal(times[1]) || !st.Since["agent_id"].Equal(times[0]) {
+		t.Errorf("since = %+v", st.Since)
+	}
+	if len(st.Changes) != 2 {
+		t.Errorf("changes = %d, want 2", len(st.Changes))
+	}
+
+	st = ReconstructAt(entries, "gt-gastown-crew-max", times[2])
+	if st == nil || !st.Gone || !st.Updated.Equal(times[2]) {
+		t.Errorf("state after exit = %+v, want gone", st)
+	}
+
+	if st := ReconstructAt(entries, "gastown/crew/other", times[2]); st != nil {
+		t.Errorf("unknown agent = %+v, want nil", st)
+	}
+}
+
+func TestPruneLedger_KeepsStateAfterCutoff(t *testing.T) {
+	town, times := ledgerTown(t)
+	at := times[1].Add(30 * time.Minute)
+	before, _ := ReadLedger(town)
+	want := ReconstructAt(before, "gt-gastown-crew-max", at)
+
+	if err := PruneLedger(town, at); err != nil {
+		t.Fatalf("PruneLedger: %v", err)
+	}
+	after, _ := ReadLedger(town)
+	if len(after) != 2 || !after[0].Full {
+		t.Fatalf("pruned ledger = %+v, want one snapshot and the gone entry", after)
+	}
+	got := ReconstructAt(after, "gastown/crew/max", at)
+	if got == nil || got.Fields["level"] != want.Fields["level"] || got.Fields["waiting"] != want.Fields["waiting"] {
+		t.Errorf("state after prune = %+v, want %+v", got, want)
+	}
+
+	// Sessions that had ended by the cutoff are dropped.
+	if err := PruneLedger(town, times[2].Add(time.Second)); err != nil {
+		t.Fatalf("PruneLedger: %v", err)
+	}
+	if after, _ := ReadLedger(town); len(after) != 0 {
+		t.Errorf("ledger after pruning an ended session = %+v, want empty", after)
+	}
+}
+
+func TestRecordLedger_NoTownIsNoop(t *testing.T) {
+	m := &Model{agents: []*AgentLight{{SessionName: "hq-mayor"}}}
+	m.recordLedger(time.Now()) // must not panic or write relative to cwd
+}
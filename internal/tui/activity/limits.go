@@ -0,0 +1,39 @@
+package activity
+
+import (
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Limit kinds reported in limit_hit events.
+const (
+	limitKindRate  = "rate_limit"  // transient API rate limit
+	limitKindUsage = "usage_limit" // usage cap — agent is dead until reset
+)
+
+// limitKind maps an agent's level to the limit it is blocked on, or "".
+func limitKind(a *AgentLight) string {
+	switch a.Level {
+	case LevelHitLimit:
+		return limitKindUsage
+	case LevelRateLimited:
+		return limitKindRate
+	default:
+		return ""
+	}
+}
+
+// reportLimits emits a limit_hit event when an agent enters a limited level,
+// so limits show up in the feed (and gt digest) rather than only on screen.
+// Only the reporter (SetReporter) emits.
+// Rate limits during a provider outage are covered by its provider_outage
+// event instead, and muted agents report none. Must run after levels are
+// computed for this poll.
+func (m *Model) reportLimits() {
+	for _, a := range m.agents {
+		kind := limitKind(a)
+		if kind != "" && kind != a.limitReported && !(kind == limitKindRate && m.inOutage(a)) && !m.muted(a) && !a.ScheduledOff {
+			m.reportHealth(events.TypeLimitHit, events.LimitHitPayload(a.SessionName, a.AgentID, kind, a.LimitResetInfo))
+		}
+		a.limitReported = kind
+	}
+}
@@ -0,0 +1,77 @@
+package activity
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestReportLimits_EmitsOnTransition(t *testing.T) {
+	townRoot := t.TempDir()
+	a := &AgentLight{SessionName: "gt-Toast", AgentID: "gastown/polecats/Toast", Level: LevelRateLimited}
+	m := &Model{townRoot: townRoot, agents: []*AgentLight{a}, reportAs: "daemon/agents", reporting: true}
+
+	m.reportLimits()
+	m.reportLimits() // still rate limited: no second event
+
+	a.Level = LevelHitLimit
+	a.LimitResetInfo = "resets 2pm"
+	m.reportLimits() // escalated to usage cap: new event
+
+	a.Level = LevelActive
+	m.reportLimits()
+
+	data, err := os.ReadFile(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		t.Fatalf("reading events file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d events, want 2:\n%s", len(lines), data)
+	}
+	if !strings.Contains(lines[0], `"kind":"rate_limit"`) {
+		t.Errorf("first event = %s, want rate_limit", lines[0])
+	}
+	if !strings.Contains(lines[1], `"kind":"usage_limit"`) || !strings.Contains(lines[1], `"reset":"resets 2pm"`) {
+		t.Errorf("second event = %s, want usage_limit with reset", lines[1])
+	}
+	if a.limitReported != "" {
+		t.Errorf("limitReported = %q after recovery, want empty", a.limitReported)
+	}
+}
+
+func TestReportLimits_OnlyReporterEmits(t *testing.T) {
+	townRoot := t.TempDir()
+	a := &AgentLight{SessionName: "gt-Toast", Level: LevelHitLimit}
+	eventsPath := filepath.Join(townRoot, events.EventsFile)
+
+	// gt top shows the limit but writes nothing.
+	m := &Model{townRoot: townRoot, agents: []*AgentLight{a}}
+	m.reportLimits()
+	if _, err := os.Stat(eventsPath); !os.IsNotExist(err) {
+		t.Fatalf("gt top emitted limit_hit (stat err %v)", err)
+	}
+
+	// The reporter's first poll records the limit it starts up into.
+	a.limitReported = ""
+	m = &Model{townRoot: townRoot, agents: []*AgentLight{a}}
+	m.SetReporter("daemon/agents")
+	m.reportLimits()
+	m.reporting = true
+	m.reportLimits()
+	if _, err := os.Stat(eventsPath); !os.IsNotExist(err) {
+		t.Fatalf("reporter emitted limit_hit for a limit found at startup (stat err %v)", err)
+	}
+
+	a.Level = LevelActive
+	m.reportLimits()
+	a.Level = LevelRateLimited
+	m.reportLimits()
+	data, err := os.ReadFile(eventsPath)
+	if err != nil || strings.Count(string(data), "\n") != 1 || !strings.Contains(string(data), `"actor":"daemon/agents"`) {
+		t.Errorf("after a new limit: %v\n%s", err, data)
+	}
+}
@@ -0,0 +1,290 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Collector load harness. The benchmarks run the collector and the pane
+// parsers against a fake tmux: synthetic sessions whose panes are scripted
+// Claude Code output that moves on with every capture, answered after a
+// jittered delay like a capture-pane exec. They report poll latency
+// percentiles at 50, 100 and 200 agents:
+//
+//	go test ./internal/tui/activity -run '^$' -bench Collector -benchmem
+//
+// TestCollectorLoadTmux does the same against a real tmux server on a
+// private socket, with N sessions printing scripted output, when asked to:
+//
+//	GT_TOP_LOADTEST=200 go test ./internal/tui/activity -run CollectorLoadTmux -v
+
+// loadAgentCounts are the town sizes the harness measures.
+var loadAgentCounts = []int{50, 100, 200}
+
+// synthPane scripts one agent's pane: a Claude Code session working through
+// tool calls, one step further on each capture.
+type synthPane struct {
+	mu   sync.Mutex
+	id   int
+	step int
+}
+
+var synthTools = []string{
+	"Bash(go test ./internal/...)",
+	"Read(internal/tui/activity/model.go)",
+	"Edit(internal/tui/activity/view.go)",
+	"Grep(\"func updateAgents\")",
+	"Bash(git status)",
+}
+
+// lines renders the pane at the next step, depth lines deep.
+func (p *synthPane) lines(depth int) []string {
+	p.mu.Lock()
+	p.step++
+	step := p.step
+	p.mu.Unlock()
+
+	var out []string
+	for i := step; len(out) < depth-8; i++ {
+		out = append(out,
+			"⏺ "+synthTools[(p.id+i)%len(synthTools)],
+			fmt.Sprintf("  ⎿  ok  github.com/steveyegge/gastown/internal/pkg%d\t0.%03ds", i%17, i%1000),
+			"",
+		)
+	}
+	out = append(out,
+		fmt.Sprintf("✻ Thinking… (%ds · ↓ %d.%dk tokens · esc to interrupt)", step%90, step%40, step%10),
+		"",
+		"╭──────────────────────────────────────────────────────────────╮",
+		"│ >                                                            │",
+		"╰──────────────────────────────────────────────────────────────╯",
+		"  ⏵⏵ accept edits on (shift+tab to cycle)",
+		fmt.Sprintf("  Context left until auto-compact: %d%%", 100-step%80),
+	)
+	return out
+}
+
+// synthTown is a fake tmux serving n synthetic sessions.
+type synthTown struct {
+	panes   map[string]*synthPane
+	latency time.Duration // mean capture latency; each call is jittered ±50%
+}
+
+func newSynthTown(n int, latency time.Duration) *synthTown {
+	t := &synthTown{panes: make(map[string]*synthPane, n), latency: latency}
+	for i := 0; i < n; i++ {
+		t.panes[synthSessionName(i)] = &synthPane{id: i}
+	}
+	return t
+}
+
+func synthSessionName(i int) string {
+	return fmt.Sprintf("gt-loadrig-agent%03d", i)
+}
+
+func (t *synthTown) sessions() []sessionInfo {
+	out := make([]sessionInfo, 0, len(t.panes))
+	for name := range t.panes {
+		out = append(out, sessionInfo{name: name, activity: time.Now().Unix(), agentType: "claude"})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+func (t *synthTown) capture(ctx context.Context, target string, depth int) ([]string, error) {
+	if t.latency > 0 {
+		d := t.latency/2 + time.Duration(rand.Int63n(int64(t.latency)))
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	p, ok := t.panes[target]
+	if !ok {
+		return nil, fmt.Errorf("can't find pane: %s", target)
+	}
+	return p.lines(depth), nil
+}
+
+// pollStats collects per-poll durations and reports their percentiles.
+type pollStats []time.Duration
+
+func (s pollStats) percentile(p float64) time.Duration {
+	if len(s) == 0 {
+		return 0
+	}
+	sorted := append(pollStats(nil), s...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[int(p*float64(len(sorted)-1))]
+}
+
+func (s pollStats) report(b *testing.B) {
+	b.ReportMetric(float64(s.percentile(0.5).Microseconds())/1000, "p50-ms/poll")
+	b.ReportMetric(float64(s.percentile(0.99).Microseconds())/1000, "p99-ms/poll")
+}
+
+// stopPool stops the pool's collectors.
+func stopPool(p *collectorPool) {
+	p.mu.Lock()
+	p.syncCollectors(nil)
+	p.mu.Unlock()
+}
+
+// BenchmarkCollectorPoll measures a whole poll: capturing every pane through
+// the collectors, then parsing them in updateAgents.
+func BenchmarkCollectorPoll(b *testing.B) {
+	for _, n := range loadAgentCounts {
+		b.Run(fmt.Sprintf("agents=%d", n), func(b *testing.B) {
+			town := newSynthTown(n, 5*time.Millisecond)
+			p := newCollectorPool()
+			p.capture = town.capture
+			defer stopPool(p)
+			m := &Model{}
+			sessions := town.sessions()
+			p.collect(sessions, collectWait)
+			m.updateAgents(sessions) // first poll detects agent types
+
+			var stats pollStats
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				start := time.Now()
+				sessions := town.sessions()
+				p.collect(sessions, collectWait)
+				m.updateAgents(sessions)
+				stats = append(stats, time.Since(start))
+			}
+			stats.report(b)
+		})
+	}
+}
+
+// BenchmarkCollectorParse measures the CPU side of a poll alone: parsing
+// every agent's freshly changed pane.
+func BenchmarkCollectorParse(b *testing.B) {
+	for _, n := range loadAgentCounts {
+		b.Run(fmt.Sprintf("agents=%d", n), func(b *testing.B) {
+			town := newSynthTown(n, 0)
+			m := &Model{}
+			fill := func() []sessionInfo {
+				sessions := town.sessions()
+				for i := range sessions {
+					sessions[i].paneLines, _ = town.capture(context.Background(), sessions[i].name, claudeCaptureDepth)
+				}
+				return sessions
+			}
+			m.updateAgents(fill())
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				sessions := fill()
+				b.StartTimer()
+				m.updateAgents(sessions)
+			}
+		})
+	}
+}
+
+// TestCollectorScales guards the collector's concurrency: at the largest
+// town size, captures that each take tens of milliseconds must all land
+// within one poll's wait, however many agents there are.
+func TestCollectorScales(t *testing.T) {
+	n := loadAgentCounts[len(loadAgentCounts)-1]
+	town := newSynthTown(n, 40*time.Millisecond)
+	p := newCollectorPool()
+	p.capture = town.capture
+	defer stopPool(p)
+
+	for poll := 0; poll < 3; poll++ {
+		sessions := town.sessions()
+		start := time.Now()
+		p.collect(sessions, collectWait)
+		took := time.Since(start)
+		stale := 0
+		for _, s := range sessions {
+			if s.captureStale {
+				stale++
+			}
+		}
+		if stale > 0 {
+			t.Fatalf("poll %d: %d of %d captures missed the %s wait (took %s)", poll, stale, n, collectWait, took)
+		}
+	}
+}
+
+// TestCollectorLoadTmux runs the collector against GT_TOP_LOADTEST real
+// tmux sessions on a private socket and logs poll latency and the CPU time
+// the tmux server and gt top spent.
+func TestCollectorLoadTmux(t *testing.T) {
+	n, err := strconv.Atoi(os.Getenv("GT_TOP_LOADTEST"))
+	if err != nil || n <= 0 {
+		t.Skip("set GT_TOP_LOADTEST=<agents> to run against a real tmux server")
+	}
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+
+	prev := tmux.GetDefaultSocket()
+	socket := fmt.Sprintf("gt-top-loadtest-%d", os.Getpid())
+	tmux.SetDefaultSocket(socket)
+	defer func() {
+		_ = exec.Command("tmux", "-L", socket, "kill-server").Run()
+		tmux.SetDefaultSocket(prev)
+	}()
+
+	// Each session prints a tool call every 200ms, like a busy agent.
+	script := `i=0; while :; do i=$((i+1)); printf '⏺ Bash(go test ./pkg%d/...)\n  ⎿  ok  pkg%d\t0.%03ds\n' $i $i $((i%1000)); sleep 0.2; done`
+	var sessions []sessionInfo
+	for i := 0; i < n; i++ {
+		name := synthSessionName(i)
+		if out, err := exec.Command("tmux", "-L", socket, "new-session", "-d", "-s", name, "-x", "200", "-y", "50", "sh", "-c", script).CombinedOutput(); err != nil {
+			t.Fatalf("starting %s: %v: %s", name, err, out)
+		}
+		sessions = append(sessions, sessionInfo{name: name, agentType: "claude"})
+	}
+	time.Sleep(time.Second) // let the panes fill
+
+	p := newCollectorPool()
+	defer stopPool(p)
+	m := &Model{}
+	var stats pollStats
+	var stale int
+	for poll := 0; poll < 10; poll++ {
+		polled := append([]sessionInfo(nil), sessions...)
+		start := time.Now()
+		p.collect(polled, collectWait)
+		m.updateAgents(polled)
+		stats = append(stats, time.Since(start))
+		for _, s := range polled {
+			if s.captureStale {
+				stale++
+			}
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	t.Logf("%d agents: poll p50 %s, p99 %s, %d stale captures over 10 polls",
+		n, stats.percentile(0.5), stats.percentile(0.99), stale)
+	server, _ := exec.Command("tmux", "-L", socket, "display-message", "-p", "#{pid}").Output()
+	t.Logf("CPU time: test process %s, tmux server %s", psCPU(strconv.Itoa(os.Getpid())), psCPU(strings.TrimSpace(string(server))))
+}
+
+// psCPU returns the cumulative CPU time ps reports for a process, or "?".
+func psCPU(pid string) string {
+	out, err := exec.Command("ps", "-o", "time=", "-p", pid).Output()
+	if err != nil || pid == "" {
+		return "?"
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,95 @@
+package activity
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Retry-loop detection: an agent that starts the same tool invocation
+// loopThreshold times within loopWindow is marked as looping. One-off retries
+// are normal; four identical calls in five minutes almost always means the
+// agent is stuck re-running a failing command.
+const (
+	loopThreshold = 4
+	loopWindow    = 5 * time.Minute
+)
+
+// toolStart records one observed start of a tool invocation.
+type toolStart struct {
+	tool string
+	at   time.Time
+}
+
+// recordToolStart appends a tool start to the agent's history.
+// Event-derived starts are re-read on every poll (the events file is tailed
+// with a sliding window), so exact duplicates are dropped here.
+func recordToolStart(a *AgentLight, tool string, at time.Time) {
+	if tool == "" {
+		return
+	}
+	for _, s := range a.toolStarts {
+		if s.tool == tool && s.at.Equal(at) {
+			return
+		}
+	}
+	a.toolStarts = append(a.toolStarts, toolStart{tool: tool, at: at})
+}
+
+// detectLoops updates Looping for every agent and emits loop_detected when an
+// agent enters the looping state. Must run after applyToolEvents so that
+// CurrentTool is final for this poll.
+//
+// Claude agents have no tool events, so a start is inferred whenever the
+// pane-derived CurrentTool changes. Event-driven agents record starts in
+// applyToolEvents directly, which also catches repeats faster than the poll.
+func (m *Model) detectLoops(now time.Time) {
+	cutoff := now.Add(-loopWindow)
+	for _, a := range m.agents {
+		if isClaudeAgent(a.AgentType) && a.CurrentTool != "" && a.CurrentTool != a.prevTool {
+			recordToolStart(a, a.CurrentTool, now)
+		}
+		a.prevTool = a.CurrentTool
+
+		// Prune starts that fell out of the window.
+		kept := a.toolStarts[:0]
+		for _, s := range a.toolStarts {
+			if s.at.After(cutoff) {
+				kept = append(kept, s)
+			}
+		}
+		a.toolStarts = kept
+
+		// Only the most recent tool counts — running anything else breaks the loop.
+		wasLooping := a.Looping
+		a.Looping = false
+		a.LoopCount = 0
+		if n := len(a.toolStarts); n > 0 {
+			latest := a.toolStarts[n-1].tool
+			count := 0
+			for _, s := range a.toolStarts {
+				if s.tool == latest {
+					count++
+				}
+			}
+			if count >= loopThreshold {
+				a.Looping = true
+				a.LoopTool = latest
+				a.LoopCount = count
+			}
+		}
+		if !a.Looping {
+			a.LoopTool = ""
+		}
+
+		if a.Looping && !wasLooping && !m.muted(a) && !a.ScheduledOff {
+			m.emitLoopDetected(a)
+		}
+	}
+}
+
+// emitLoopDetected writes a loop_detected event so the witness can nudge or
+// escalate. Only the reporter (SetReporter) emits.
+func (m *Model) emitLoopDetected(a *AgentLight) {
+	m.reportHealth(events.TypeLoopDetected, events.LoopDetectedPayload(a.SessionName, a.AgentID, a.LoopTool, a.LoopCount, loopWindow.String()))
+}
@@ -0,0 +1,59 @@
+package activity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectLoops_PaneInferredStarts(t *testing.T) {
+	a := &AgentLight{SessionName: "gt-crew-joe", AgentType: "claude"}
+	m := &Model{agents: []*AgentLight{a}}
+	now := time.Now()
+
+	// The same command alternating with an empty pane counts as a new start each time.
+	for i := 0; i < loopThreshold; i++ {
+		a.CurrentTool = "Bash(go test ./...)"
+		m.detectLoops(now.Add(time.Duration(2*i) * time.Second))
+		if i < loopThreshold-1 && a.Looping {
+			t.Fatalf("looping after %d starts, want threshold %d", i+1, loopThreshold)
+		}
+		a.CurrentTool = ""
+		m.detectLoops(now.Add(time.Duration(2*i+1) * time.Second))
+	}
+	if !a.Looping || a.LoopCount != loopThreshold {
+		t.Fatalf("Looping=%v LoopCount=%d, want true/%d", a.Looping, a.LoopCount, loopThreshold)
+	}
+
+	// Running a different tool breaks the loop.
+	a.CurrentTool = "Read(main.go)"
+	m.detectLoops(now.Add(20 * time.Second))
+	if a.Looping {
+		t.Error("still looping after a different tool started")
+	}
+}
+
+func TestDetectLoops_WindowExpiry(t *testing.T) {
+	a := &AgentLight{AgentType: "opencode"}
+	m := &Model{agents: []*AgentLight{a}}
+	start := time.Now().Add(-10 * time.Minute)
+	for i := 0; i < loopThreshold; i++ {
+		recordToolStart(a, "Bash(make)", start.Add(time.Duration(i)*time.Second))
+	}
+	m.detectLoops(time.Now())
+	if a.Looping {
+		t.Error("starts outside the window should not count")
+	}
+	if len(a.toolStarts) != 0 {
+		t.Errorf("expired starts not pruned: %d left", len(a.toolStarts))
+	}
+}
+
+func TestRecordToolStart_DedupsRereadEvents(t *testing.T) {
+	a := &AgentLight{}
+	ts := time.Now()
+	recordToolStart(a, "Bash(ls)", ts)
+	recordToolStart(a, "Bash(ls)", ts) // same event re-read on the next poll
+	if len(a.toolStarts) != 1 {
+		t.Errorf("toolStarts = %d, want 1", len(a.toolStarts))
+	}
+}
@@ -0,0 +1,231 @@
+package activity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Macros (town settings top.macros): named keystroke sequences for the
+// interventions operators make over and over, like approving a prompt and
+// telling the agent to carry on, or asking it to compact. M opens a menu of
+// them on the hovered agent; enter (or the macro's number) sends the steps
+// to its tmux session.
+
+// macroMaxWait caps a <wait> step, so a typo can't hang the macro.
+const macroMaxWait = 10 * time.Second
+
+// macroKeyPattern matches a tmux key name step, e.g. "<Escape>" or "<C-c>".
+var macroKeyPattern = regexp.MustCompile(`^<([A-Za-z0-9-]+)>$`)
+
+// macroWaitPattern matches a pause step, e.g. "<wait 500ms>".
+var macroWaitPattern = regexp.MustCompile(`^<wait ([^>]+)>$`)
+
+// macroStep is one step of a macro: text to type and submit, a key to send,
+// or a pause.
+type macroStep struct {
+	text string
+	key  string
+	wait time.Duration
+}
+
+// macro is a parsed top.macros entry.
+type macro struct {
+	name  string
+	steps []macroStep
+}
+
+// macroMenu is the macro menu overlay (M) for one agent.
+type macroMenu struct {
+	agent *AgentLight
+	sel   int
+}
+
+// macroDoneMsg reports a macro that finished sending.
+type macroDoneMsg struct {
+	name, session string
+	err           error
+}
+
+// keySender sends keystrokes to a tmux session; *tmux.Tmux is one.
+type keySender interface {
+	SendKeys(session, keys string) error
+	SendKeysRaw(session, keys string) error
+}
+
+// SetMacros sets the macros the M menu offers. Invalid macros are skipped
+// and reported; the valid ones still apply.
+func (m *Model) SetMacros(cfg []config.TopMacro) error {
+	var macros []macro
+	var errs []error
+	for _, c := range cfg {
+		mac, err := parseMacro(c)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("top.macros %q: %w", c.Name, err))
+			continue
+		}
+		macros = append(macros, mac)
+	}
+	m.macros = macros
+	return errors.Join(errs...)
+}
+
+func parseMacro(c config.TopMacro) (macro, error) {
+	mac := macro{name: strings.TrimSpace(c.Name)}
+	if mac.name == "" {
+		return mac, fmt.Errorf("needs a name")
+	}
+	if len(c.Steps) == 0 {
+		return mac, fmt.Errorf("needs steps")
+	}
+	for _, s := range c.Steps {
+		switch {
+		case macroWaitPattern.MatchString(s):
+			d, err := time.ParseDuration(macroWaitPattern.FindStringSubmatch(s)[1])
+			if err != nil || d <= 0 || d > macroMaxWait {
+				return mac, fmt.Errorf("step %q: wait must be a duration up to %s", s, macroMaxWait)
+			}
+			mac.steps = append(mac.steps, macroStep{wait: d})
+		case macroKeyPattern.MatchString(s):
+			mac.steps = append(mac.steps, macroStep{key: macroKeyPattern.FindStringSubmatch(s)[1]})
+		case strings.TrimSpace(s) == "":
+			return mac, fmt.Errorf("empty step")
+		default:
+			mac.steps = append(mac.steps, macroStep{text: s})
+		}
+	}
+	return mac, nil
+}
+
+// openMacroMenu opens the macro menu for the hovered agent.
+func (m *Model) openMacroMenu() {
+	a := m.hoveredAgent
+	if a == nil || a.placeholder {
+		return
+	}
+	var reason string
+	switch {
+	case len(m.macros) == 0:
+		reason = "no macros defined; add them to town settings top.macros"
+	case a.inContainer():
+		reason = "macros need a tmux session; " + a.SessionName + " runs in a container"
+	}
+	if reason != "" {
+		m.flashMessage = reason
+		m.flashTime = time.Now()
+		return
+	}
+	m.macroMenu = &macroMenu{agent: a}
+}
+
+// handleMacroKey handles a key while the macro menu is open.
+func (m *Model) handleMacroKey(key string) tea.Cmd {
+	menu := m.macroMenu
+	switch key {
+	case "esc", "q", "M":
+		m.macroMenu = nil
+	case "up", "k":
+		if menu.sel > 0 {
+			menu.sel--
+		}
+	case "down", "j", "tab":
+		if menu.sel < len(m.macros)-1 {
+			menu.sel++
+		}
+	case "enter":
+		m.macroMenu = nil
+		return m.runMacro(menu.agent, m.macros[menu.sel])
+	default:
+		if len(key) != 1 {
+			break
+		}
+		if n := int(key[0]) - '0'; n >= 1 && n <= min(len(m.macros), 9) {
+			m.macroMenu = nil
+			return m.runMacro(menu.agent, m.macros[n-1])
+		}
+	}
+	return nil
+}
+
+// runMacro sends the macro's steps to the agent's session in the background.
+func (m *Model) runMacro(a *AgentLight, mac macro) tea.Cmd {
+	m.flashMessage = "running " + mac.name + " on " + a.SessionName + "…"
+	m.flashTime = time.Now()
+	session := a.SessionName
+	m.touchedByHuman(session)
+	return func() tea.Msg {
+		return macroDoneMsg{name: mac.name, session: session, err: sendMacro(tmux.NewTmux(), session, mac.steps)}
+	}
+}
+
+// sendMacro sends the steps in order, stopping at the first that fails.
+func sendMacro(t keySender, session string, steps []macroStep) error {
+	for i, s := range steps {
+		var err error
+		switch {
+		case s.wait > 0:
+			time.Sleep(s.wait)
+		case s.key != "":
+			err = t.SendKeysRaw(session, s.key)
+		default:
+			err = t.SendKeys(session, s.text)
+		}
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// handleMacroDone flashes how the macro went.
+func (m *Model) handleMacroDone(msg macroDoneMsg) {
+	m.flashMessage = "ran " + msg.name + " on " + msg.session
+	if msg.err != nil {
+		m.flashMessage = msg.name + " on " + msg.session + " failed: " + msg.err.Error()
+	}
+	m.flashTime = time.Now()
+}
+
+// renderMacroMenu renders the macro menu overlay shown in place of the
+// panels.
+func (m *Model) renderMacroMenu() string {
+	menu := m.macroMenu
+	lines := []string{titleStyle.Render("macros") + "  " + subtitleStyle.Render("on "+menu.agent.SessionName), ""}
+	for i, mac := range m.macros {
+		num := " "
+		if i < 9 {
+			num = fmt.Sprint(i + 1)
+		}
+		line := num + "  " + padWidth(truncateWidth(mac.name, 24, "…"), 24) + "  " + statusDimStyle.Render(describeMacro(mac))
+		line = truncateWidth(line, max(m.width-12, 40), "…")
+		if i == menu.sel {
+			line = switcherSelectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", helpStyle.Render("enter or 1-9: send to "+menu.agent.Name+" · ↑/↓: move · esc: close"))
+	return probeBoxStyle.Render(strings.Join(lines, "\n"))
+}
+
+// describeMacro renders a macro's steps compactly, e.g.
+// "<Enter> · continue ⏎ · <wait 1s>".
+func describeMacro(mac macro) string {
+	parts := make([]string, 0, len(mac.steps))
+	for _, s := range mac.steps {
+		switch {
+		case s.wait > 0:
+			parts = append(parts, "<wait "+s.wait.String()+">")
+		case s.key != "":
+			parts = append(parts, "<"+s.key+">")
+		default:
+			parts = append(parts, s.text+" ⏎")
+		}
+	}
+	return strings.Join(parts, " · ")
+}
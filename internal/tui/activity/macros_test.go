@@ -0,0 +1,119 @@
+package activity
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestSetMacros(t *testing.T) {
+	m := &Model{}
+	err := m.SetMacros([]config.TopMacro{
+		{Name: "approve-and-continue", Steps: []string{"<Enter>", "<wait 200ms>", "continue"}},
+		{Name: "", Steps: []string{"hi"}},
+		{Name: "stall", Steps: []string{"<wait 1h>"}},
+		{Name: "empty"},
+		{Name: "compact now", Steps: []string{"<Escape>", "/compact"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), `"stall"`) || !strings.Contains(err.Error(), `"empty"`) {
+		t.Errorf("err = %v, want the bad macros reported", err)
+	}
+	if len(m.macros) != 2 || m.macros[0].name != "approve-and-continue" || m.macros[1].name != "compact now" {
+		t.Fatalf("macros = %+v, want the two valid ones", m.macros)
+	}
+	want := []macroStep{{key: "Enter"}, {wait: 200 * time.Millisecond}, {text: "continue"}}
+	for i, s := range m.macros[0].steps {
+		if s != want[i] {
+			t.Errorf("step %d = %+v, want %+v", i, s, want[i])
+		}
+	}
+	if got := describeMacro(m.macros[1]); got != "<Escape> · /compact ⏎" {
+		t.Errorf("describeMacro = %q", got)
+	}
+}
+
+// fakeSender records the keystrokes a macro sends.
+type fakeSender struct {
+	sent []string
+	fail string
+}
+
+func (f *fakeSender) SendKeys(session, keys string) error {
+	f.sent = append(f.sent, session+" text "+keys)
+	return nil
+}
+
+func (f *fakeSender) SendKeysRaw(session, keys string) error {
+	if keys == f.fail {
+		return errors.New("no such key")
+	}
+	f.sent = append(f.sent, session+" key "+keys)
+	return nil
+}
+
+func TestSendMacro(t *testing.T) {
+	f := &fakeSender{}
+	steps := []macroStep{{key: "Escape"}, {wait: time.Millisecond}, {text: "/compact"}}
+	if err := sendMacro(f, "gt-gastown-Toast", steps); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(f.sent, "; "), "gt-gastown-Toast key Escape; gt-gastown-Toast text /compact"; got != want {
+		t.Errorf("sent %q, want %q", got, want)
+	}
+
+	f = &fakeSender{fail: "Escape"}
+	if err := sendMacro(f, "gt-gastown-Toast", steps); err == nil || !strings.Contains(err.Error(), "step 1") {
+		t.Errorf("err = %v, want step 1 to fail", err)
+	}
+	if len(f.sent) != 0 {
+		t.Errorf("sent %q after a failed step", f.sent)
+	}
+}
+
+func TestMacroMenuKeys(t *testing.T) {
+	toast := &AgentLight{SessionName: "gt-gastown-Toast", Name: "Toast"}
+	m := &Model{width: 120, agents: []*AgentLight{toast}}
+
+	m.hoveredAgent = toast
+	typeKeys(m, "M")
+	if m.macroMenu != nil || !strings.Contains(m.flashMessage, "top.macros") {
+		t.Fatalf("M with no macros: menu = %+v, flash %q", m.macroMenu, m.flashMessage)
+	}
+
+	_ = m.SetMacros([]config.TopMacro{
+		{Name: "run tests", Steps: []string{"run the tests"}},
+		{Name: "compact now", Steps: []string{"/compact"}},
+	})
+	typeKeys(m, "M", "down")
+	if m.macroMenu == nil || m.macroMenu.sel != 1 {
+		t.Fatalf("M down: menu = %+v, want it open on the second macro", m.macroMenu)
+	}
+	if view := m.renderMacroMenu(); !strings.Contains(view, "compact now") || !strings.Contains(view, "Toast") {
+		t.Errorf("menu view missing macros or agent:\n%s", view)
+	}
+	if cmd := m.handleMacroKey("3"); cmd != nil || m.macroMenu == nil {
+		t.Error("3 with two macros ran something")
+	}
+	if cmd := m.handleMacroKey(""); cmd != nil || m.macroMenu == nil {
+		t.Error("an empty key ran something")
+	}
+	if cmd := m.handleMacroKey("1"); cmd == nil || m.macroMenu != nil {
+		t.Error("1 didn't run the first macro and close the menu")
+	}
+	if !strings.Contains(m.flashMessage, "run tests") {
+		t.Errorf("flash = %q, want the macro named", m.flashMessage)
+	}
+
+	m.handleMacroDone(macroDoneMsg{name: "run tests", session: "gt-gastown-Toast", err: errors.New("step 1: no session")})
+	if !strings.Contains(m.flashMessage, "failed") {
+		t.Errorf("flash = %q, want the failure", m.flashMessage)
+	}
+
+	typeKeys(m, "M", "esc")
+	if m.macroMenu != nil {
+		t.Error("esc didn't close the menu")
+	}
+}
@@ -0,0 +1,167 @@
+package activity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Merge queue positions: an agent that ran gt done has its branch waiting in
+// the rig's refinery queue, and sitting idle is what it should be doing.
+// gt top rebuilds each rig's queue from the events file (done events join
+// it, merge_started marks a branch as merging, merged, merge_failed and
+// merge_skipped take it out) and shows "waiting on merge, queue position 3"
+// for such agents instead of counting them as stuck.
+
+// mergeQueueMaxAge drops entries the refinery never reported on, e.g.
+// merged while no merge events were emitted.
+const mergeQueueMaxAge = 24 * time.Hour
+
+// mergeEntry is one branch waiting in a rig's merge queue.
+type mergeEntry struct {
+	Rig     string
+	Branch  string
+	Agent   string // submitting agent's address (done event actor)
+	At      time.Time
+	Merging bool // the refinery started merging it
+}
+
+// mergeQueue is the rigs' merge queues, read incrementally from the events
+// file.
+type mergeQueue struct {
+	entries []*mergeEntry // in submission order
+}
+
+// trackMergeQueue reads new merge queue events and sets each agent's queue
+// position. Idle agents whose work is queued move from stuck to idle. Must
+// run after levels are computed.
+func (m *Model) trackMergeQueue(now time.Time) {
+	if m.townRoot == "" {
+		return
+	}
+	if m.mergeQueue == nil {
+		m.mergeQueue = &mergeQueue{}
+	}
+	m.readMergeQueue()
+	q := m.mergeQueue
+	kept := q.entries[:0]
+	for _, e := range q.entries {
+		if now.Sub(e.At) < mergeQueueMaxAge {
+			kept = append(kept, e)
+		}
+	}
+	q.entries = kept
+
+	for _, a := range m.agents {
+		a.MergeQueuePos, a.mergeEntry = 0, nil
+	}
+	for rig, entries := range q.byRig() {
+		for i, e := range entries {
+			a := m.agentForAddress(e.Agent)
+			if a == nil || a.Rig != rig || a.mergeEntry != nil {
+				continue
+			}
+			a.MergeQueuePos, a.mergeEntry = i+1, e
+			if a.Level == LevelCold && !a.ScheduledOff {
+				m.stuckCount--
+				m.idleCount++
+			}
+		}
+	}
+}
+
+// byRig returns each rig's queue in merge order: branches being merged
+// first, then the rest by submission time.
+func (q *mergeQueue) byRig() map[string][]*mergeEntry {
+	rigs := make(map[string][]*mergeEntry)
+	for _, e := range q.entries {
+		rigs[e.Rig] = append(rigs[e.Rig], e)
+	}
+	for _, entries := range rigs {
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Merging && !entries[j].Merging
+		})
+	}
+	return rigs
+}
+
+// readMergeQueue applies the merge queue events read this poll.
+func (m *Model) readMergeQueue() {
+	for i := range m.tail.fresh {
+		m.mergeQueue.apply(&m.tail.fresh[i].Event)
+	}
+}
+
+// apply updates the queue for one event.
+func (q *mergeQueue) apply(evt *events.Event) {
+	branch, _ := evt.Payload["branch"].(string)
+	if branch == "" {
+		return
+	}
+	rig, _ := evt.Payload["rig"].(string)
+	switch evt.Type {
+	case events.TypeDone:
+		at, err := time.Parse(time.RFC3339, evt.Timestamp)
+		if err != nil {
+			return
+		}
+		rig = strings.SplitN(evt.Actor, "/", 2)[0]
+		q.remove(rig, branch) // resubmitted: back of the queue
+		q.entries = append(q.entries, &mergeEntry{Rig: rig, Branch: branch, Agent: evt.Actor, At: at})
+	case events.TypeMergeStarted:
+		for _, e := range q.entries {
+			if e.Branch == branch && (rig == "" || e.Rig == rig) {
+				e.Merging = true
+			}
+		}
+	case events.TypeMerged, events.TypeMergeFailed, events.TypeMergeSkipped:
+		q.remove(rig, branch)
+	}
+}
+
+// remove drops the branch's entries; rig "" matches any rig.
+func (q *mergeQueue) remove(rig, branch string) {
+	kept := q.entries[:0]
+	for _, e := range q.entries {
+		if e.Branch != branch || (rig != "" && e.Rig != rig) {
+			kept = append(kept, e)
+		}
+	}
+	q.entries = kept
+}
+
+// waitsOnMerge reports whether the agent is idle because its work is in the
+// merge queue. One still working shows what it's doing.
+func (a *AgentLight) waitsOnMerge() bool {
+	return a.MergeQueuePos > 0 && (a.Level == LevelWarm || a.Level == LevelCool || a.Level == LevelCold || a.Level == LevelReady)
+}
+
+// mergeQueueStatus renders the agent line's status while it waits on a
+// merge, e.g. "waiting on merge, queue position 3".
+func (a *AgentLight) mergeQueueStatus() string {
+	if a.mergeEntry != nil && a.mergeEntry.Merging {
+		return "waiting on merge, merging now"
+	}
+	return fmt.Sprintf("waiting on merge, queue position %d", a.MergeQueuePos)
+}
+
+// mergeQueueDetail renders the agent's queued branch for the hover detail,
+// e.g. "polecat/Toast queued 12m ago, position 3", or "".
+func mergeQueueDetail(a *AgentLight, now time.Time) string {
+	e := a.mergeEntry
+	if e == nil {
+		return ""
+	}
+	since := "just now"
+	if elapsed := formatElapsed(now.Sub(e.At)); elapsed != "" {
+		since = elapsed + " ago"
+	}
+	s := e.Branch + " queued " + since
+	if e.Merging {
+		return s + ", merging now"
+	}
+	return s + fmt.Sprintf(", position %d", a.MergeQueuePos)
+}
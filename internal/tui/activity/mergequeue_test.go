@@ -0,0 +1,80 @@
+package activity
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func writeEvent(t *testing.T, townRoot string, at time.Time, typ, actor string, payload map[string]interface{}) {
+	t.Helper()
+	evt := events.Event{
+		Timestamp:  at.UTC().Format(time.RFC3339),
+		Source:     "gt",
+		Type:       typ,
+		Actor:      actor,
+		Payload:    payload,
+		Visibility: events.VisibilityFeed,
+	}
+	data, _ := json.Marshal(evt)
+	f, err := os.OpenFile(filepath.Join(townRoot, events.EventsFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	_, _ = f.Write(append(data, '\n'))
+}
+
+func TestTrackMergeQueue(t *testing.T) {
+	townRoot := t.TempDir()
+	max := &AgentLight{SessionName: "gt-gastown-crew-max", AgentID: "gastown/crew/max", Rig: "gastown", Role: "crew", Name: "max", Level: LevelCold}
+	joe := &AgentLight{SessionName: "gt-gastown-crew-joe", AgentID: "gastown/crew/joe", Rig: "gastown", Role: "crew", Name: "joe", Level: LevelActive}
+	m := &Model{townRoot: townRoot, agents: []*AgentLight{max, joe}, stuckCount: 1}
+
+	now := time.Now()
+	writeEvent(t, townRoot, now.Add(-30*time.Minute), events.TypeDone, "gastown/crew/ann", events.DonePayload("gt-1", "crew/ann"))
+	writeEvent(t, townRoot, now.Add(-20*time.Minute), events.TypeDone, "gastown/crew/bob", events.DonePayload("gt-2", "crew/bob"))
+	writeEvent(t, townRoot, now.Add(-10*time.Minute), events.TypeDone, "gastown/crew/max", events.DonePayload("gt-3", "crew/max"))
+	writeEvent(t, townRoot, now.Add(-5*time.Minute), events.TypeDone, "gastown/crew/joe", events.DonePayload("gt-4", "crew/joe"))
+	writeEvent(t, townRoot, now.Add(-2*time.Minute), events.TypeMerged, "gastown/refinery", map[string]interface{}{"rig": "gastown", "branch": "crew/ann"})
+	writeEvent(t, townRoot, now.Add(-time.Minute), events.TypeMergeStarted, "gastown/refinery", map[string]interface{}{"rig": "gastown", "branch": "crew/bob"})
+
+	m.readEvents(now)
+	m.trackMergeQueue(now)
+	if max.MergeQueuePos != 2 || joe.MergeQueuePos != 3 {
+		t.Fatalf("positions: max %d, joe %d, want 2 and 3", max.MergeQueuePos, joe.MergeQueuePos)
+	}
+	if m.stuckCount != 0 || m.idleCount != 1 {
+		t.Errorf("stuck %d idle %d: a cold agent waiting on a merge counted as stuck", m.stuckCount, m.idleCount)
+	}
+	if !max.waitsOnMerge() || max.mergeQueueStatus() != "waiting on merge, queue position 2" {
+		t.Errorf("max status = %q", max.mergeQueueStatus())
+	}
+	if joe.waitsOnMerge() {
+		t.Error("an active agent shows as waiting on merge")
+	}
+	if got := mergeQueueDetail(max, now); !strings.HasPrefix(got, "crew/max queued 10m ago") {
+		t.Errorf("detail = %q", got)
+	}
+
+	// bob's merge lands and max's starts: max is up.
+	writeEvent(t, townRoot, now, events.TypeMerged, "gastown/refinery", map[string]interface{}{"rig": "gastown", "branch": "crew/bob"})
+	writeEvent(t, townRoot, now, events.TypeMergeStarted, "gastown/refinery", map[string]interface{}{"rig": "gastown", "branch": "crew/max"})
+	m.readEvents(now)
+	m.trackMergeQueue(now)
+	if max.MergeQueuePos != 1 || max.mergeQueueStatus() != "waiting on merge, merging now" {
+		t.Errorf("max: position %d, status %q", max.MergeQueuePos, max.mergeQueueStatus())
+	}
+
+	writeEvent(t, townRoot, now, events.TypeMergeFailed, "gastown/refinery", map[string]interface{}{"rig": "gastown", "branch": "crew/max"})
+	m.readEvents(now)
+	m.trackMergeQueue(now)
+	if max.MergeQueuePos != 0 || joe.MergeQueuePos != 1 {
+		t.Errorf("after max's merge failed: max %d, joe %d", max.MergeQueuePos, joe.MergeQueuePos)
+	}
+}
@@ -0,0 +1,143 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Braille glyphs for the minimap, from densest (just active) to sparsest
+// (cold). Attention states reuse the dense glyph and are told apart by color.
+const (
+	brailleActive = "⣿"
+	brailleRecent = "⣶"
+	brailleWarm   = "⣤"
+	brailleCool   = "⣀"
+	brailleCold   = "⠄"
+	brailleStart  = "⠶"
+)
+
+// jumpHighlight is how long a minimap click highlights the target agent's row.
+const jumpHighlight = 2 * time.Second
+
+// minimapCell records where one agent's dot was drawn, for mouse hit-testing.
+type minimapCell struct {
+	x     int
+	agent *AgentLight
+}
+
+// minimapAgents returns every agent in rig display order, pinned or not.
+func (m *Model) minimapAgents() [][]*AgentLight {
+	var groups [][]*AgentLight
+	for _, rig := range m.rigs {
+		if agents := m.agentsForRig(rig); len(agents) > 0 {
+			groups = append(groups, agents)
+		}
+	}
+	return groups
+}
+
+// renderMinimap renders one braille dot per agent, rigs separated by a space,
+// and records each dot's position on row y. Agents that don't fit the width
+// are summarized as "+N".
+func (m *Model) renderMinimap(y int) string {
+	m.minimapY = y
+	m.minimapCells = m.minimapCells[:0]
+
+	groups := m.minimapAgents()
+	total, width := 0, len(groups)-1 // one separator between rigs
+	for _, g := range groups {
+		total += len(g)
+		width += len(g)
+	}
+
+	// Reserve room for the overflow count only when everything won't fit.
+	limit := m.width - 4 // content width inside outerStyle
+	if width > limit {
+		limit -= len(fmt.Sprintf(" +%d", total))
+	}
+
+	var b strings.Builder
+	x := 1 // outerStyle pads one column on the left
+	shown := 0
+fill:
+	for gi, g := range groups {
+		if gi > 0 {
+			b.WriteString(" ")
+			x++
+		}
+		for _, a := range g {
+			if x > limit {
+				break fill
+			}
+			b.WriteString(m.renderMinimapDot(a))
+			m.minimapCells = append(m.minimapCells, minimapCell{x: x, agent: a})
+			x++
+			shown++
+		}
+	}
+	if shown < total {
+		b.WriteString(statusDimStyle.Render(fmt.Sprintf(" +%d", total-shown)))
+	}
+	return b.String()
+}
+
+// renderMinimapDot renders a single agent's minimap glyph, colored like its
+// LED: purple compacting, red waiting, orange limited/looping, else by level
+// (or in its rig's color when coloring by rig).
+func (m *Model) renderMinimapDot(a *AgentLight) string {
+	switch {
+	case a.IsCompacting:
+		return barCompactingStyle.Render(brailleActive)
+	case a.Level == LevelWaitingForHuman:
+		return barWaitingStyle.Render(brailleActive)
+	case a.Level == LevelHitLimit, a.Level == LevelRateLimited, a.Looping:
+		return barRateLimitedStyle.Render(brailleActive)
+	}
+	style, glyph := barColdStyle, brailleCold
+	switch a.Level {
+	case LevelActive:
+		style, glyph = barActiveStyle, brailleActive
+	case LevelRecent:
+		style, glyph = barRecentStyle, brailleRecent
+	case LevelStarting:
+		style, glyph = barRecentStyle, brailleStart
+	case LevelReady:
+		style, glyph = barRecentStyle, brailleWarm
+	case LevelWarm:
+		style, glyph = barWarmStyle, brailleWarm
+	case LevelCool:
+		style, glyph = barCoolStyle, brailleCool
+	}
+	if dot, ok := m.renderRigLight(a, glyph); ok {
+		return dot
+	}
+	return style.Render(glyph)
+}
+
+// minimapAgentAt returns the agent whose minimap dot is at (x, y), or nil.
+func (m *Model) minimapAgentAt(x, y int) *AgentLight {
+	if len(m.minimapCells) == 0 || y != m.minimapY {
+		return nil
+	}
+	for _, c := range m.minimapCells {
+		if c.x == x {
+			return c.agent
+		}
+	}
+	return nil
+}
+
+// jumpTo highlights an agent's row after a minimap click, so the eye can
+// find it in the panels below.
+func (m *Model) jumpTo(a *AgentLight) {
+	m.jumpAgent = a
+	m.jumpTime = time.Now()
+	m.flashMessage = "→ " + a.SessionName
+	m.flashTime = m.jumpTime
+}
+
+// isJumpTarget reports whether a's row is currently highlighted by a jump.
+func (m *Model) isJumpTarget(a *AgentLight) bool {
+	return a == m.jumpAgent && time.Since(m.jumpTime) < jumpHighlight
+}
@@ -0,0 +1,101 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRender_MinimapDotsInRigOrder(t *testing.T) {
+	m := testModel(layoutAgents(), modelSize(120, 40), modelTown("/town"))
+	toast, nux := m.agents[0], m.agents[1]
+	toast.Level = LevelActive
+	nux.Level = LevelCold
+	lines := strings.Split(m.render(), "\n")
+
+	if m.minimapY != 1 {
+		t.Fatalf("minimap row = %d, want 1 (under the header)", m.minimapY)
+	}
+	row := []rune(ansi.Strip(lines[m.minimapY]))
+	want := map[*AgentLight]string{toast: brailleActive, nux: brailleCold}
+	if len(m.minimapCells) != 2 {
+		t.Fatalf("got %d minimap cells, want 2", len(m.minimapCells))
+	}
+	for _, c := range m.minimapCells {
+		if got := string(row[c.x]); got != want[c.agent] {
+			t.Errorf("%s dot at x=%d is %q, want %q", c.agent.Name, c.x, got, want[c.agent])
+		}
+	}
+	if m.minimapCells[0].agent != toast || m.minimapCells[1].x != m.minimapCells[0].x+2 {
+		t.Errorf("rigs should appear in order separated by a space: %+v", m.minimapCells)
+	}
+	// Panels start below the minimap.
+	if toast.renderY <= m.minimapY {
+		t.Errorf("agent row %d overlaps minimap row %d", toast.renderY, m.minimapY)
+	}
+}
+
+func TestRenderMinimap_OverflowCount(t *testing.T) {
+	m := &Model{width: 20, rigs: []string{"gastown"}}
+	for i := 0; i < 30; i++ {
+		m.agents = append(m.agents, &AgentLight{Name: fmt.Sprintf("p%02d", i), Rig: "gastown"})
+	}
+	out := ansi.Strip(m.renderMinimap(1))
+
+	shown := len(m.minimapCells)
+	if !strings.HasSuffix(out, fmt.Sprintf(" +%d", 30-shown)) {
+		t.Errorf("minimap %q should end with overflow count +%d", out, 30-shown)
+	}
+	if w := len([]rune(out)); w > m.width-4 {
+		t.Errorf("minimap is %d wide, exceeds content width %d", w, m.width-4)
+	}
+}
+
+func TestMinimapDot_AttentionColors(t *testing.T) {
+	m := &Model{}
+	tests := []struct {
+		name  string
+		agent *AgentLight
+		want  string
+	}{
+		{"compacting", &AgentLight{Level: LevelCold, IsCompacting: true}, barCompactingStyle.Render(brailleActive)},
+		{"waiting", &AgentLight{Level: LevelWaitingForHuman}, barWaitingStyle.Render(brailleActive)},
+		{"hit limit", &AgentLight{Level: LevelHitLimit}, barRateLimitedStyle.Render(brailleActive)},
+		{"looping", &AgentLight{Level: LevelWarm, Looping: true}, barRateLimitedStyle.Render(brailleActive)},
+		{"warm", &AgentLight{Level: LevelWarm}, barWarmStyle.Render(brailleWarm)},
+	}
+	for _, tt := range tests {
+		if got := m.renderMinimapDot(tt.agent); got != tt.want {
+			t.Errorf("%s: dot = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMinimapClick_HoversAndHighlights(t *testing.T) {
+	m := testModel(layoutAgents(), modelSize(120, 40), modelTown("/town"))
+	nux := m.agents[1]
+	m.render()
+	var x int
+	for _, c := range m.minimapCells {
+		if c.agent == nux {
+			x = c.x
+		}
+	}
+
+	m.Update(tea.MouseMsg{X: x, Y: m.minimapY, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	if m.hoveredAgent != nux {
+		t.Errorf("hovering a minimap dot should hover its agent, got %v", m.hoveredAgent)
+	}
+	if !m.isJumpTarget(nux) {
+		t.Error("clicking a minimap dot should highlight its agent")
+	}
+	if m.lastClickAgent != nil {
+		t.Error("minimap click should not arm double-click attach")
+	}
+	if m.minimapAgentAt(x, m.minimapY+1) != nil {
+		t.Error("minimap hit-test should only match its own row")
+	}
+}
@@ -0,0 +1,216 @@
+package activity
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/cli"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// placeholderStyle dims the LEDs of roles that have no running session.
+var placeholderStyle = lipgloss.NewStyle().Foreground(colorBorder)
+
+// expectedRole is a long-running role the town declares: the mayor and
+// deacon, and each registered rig's witness and refinery.
+type expectedRole struct {
+	session string
+	start   []string // gt arguments that launch it
+}
+
+// expectedRoles lists the roles that should have a session. Offline rigs
+// (parked or docked) are left out: their agents are stopped on purpose.
+func expectedRoles(offline map[string]string) []expectedRole {
+	roles := []expectedRole{
+		{session: session.MayorSessionName(), start: []string{"mayor", "start"}},
+		{session: session.DeaconSessionName(), start: []string{"deacon", "start"}},
+	}
+	rigs := session.DefaultRegistry().AllRigs()
+	for _, rig := range sortedRigs(rigs) {
+		if offline[rig] != "" {
+			continue
+		}
+		roles = append(roles,
+			expectedRole{session: session.WitnessSessionName(rigs[rig]), start: []string{"witness", "start", rig}},
+			expectedRole{session: session.RefinerySessionName(rigs[rig]), start: []string{"refinery", "start", rig}},
+		)
+	}
+	return roles
+}
+
+// sortedRigs returns the registered rig names, sorted.
+func sortedRigs(rigs map[string]string) []string {
+	names := make([]string, 0, len(rigs))
+	for rig := range rigs {
+		names = append(names, rig)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rigStatusInterval is how often gt top re-checks which rigs are parked or
+// docked. Docked state lives on the rig bead, so each check runs bd.
+const rigStatusInterval = 30 * time.Second
+
+// rigStatus reports whether a rig is parked or docked; tests replace it.
+var rigStatus = rig.IsParkedOrDocked
+
+// pollRigStatus refreshes m.offlineRigs, rig -> "parked" (gt rig park) or
+// "docked" (gt rig dock), on a slow cadence.
+func (m *Model) pollRigStatus(now time.Time) {
+	if m.townRoot == "" {
+		return
+	}
+	if !m.lastRigStatusPoll.IsZero() && now.Sub(m.lastRigStatusPoll) < rigStatusInterval {
+		return
+	}
+	m.lastRigStatusPoll = now
+	offline := make(map[string]string)
+	for name := range session.DefaultRegistry().AllRigs() {
+		if blocked, reason := rigStatus(m.townRoot, name); blocked {
+			offline[name] = reason
+		}
+	}
+	m.offlineRigs = offline
+}
+
+// updatePlaceholders refreshes the dark placeholder LEDs shown for expected
+// roles with no running session. Placeholders are kept apart from m.agents,
+// so counts, alerts and bulk actions only ever see real sessions. Town mode
+// only: without a town there is nothing to expect.
+func (m *Model) updatePlaceholders() {
+	if m.townRoot == "" {
+		return
+	}
+	running := make(map[string]bool, len(m.agents))
+	for _, a := range m.agents {
+		running[a.SessionName] = true
+	}
+	var names []sessionInfo
+	starts := make(map[string][]string)
+	m.pollRigStatus(time.Now())
+	for _, r := range expectedRoles(m.offlineRigs) {
+		if !running[r.session] {
+			names = append(names, sessionInfo{name: r.session})
+			starts[r.session] = r.start
+		}
+	}
+
+	prev := m.placeholders
+	m.placeholders = make(map[string]*AgentLight)
+	for _, s := range m.filter.filterSessions(names) {
+		a := prev[s.name] // the same light, so hovering it survives polls
+		if a == nil {
+			a = &AgentLight{SessionName: s.name, Level: LevelDead, placeholder: true}
+			parseSessionName(a)
+		}
+		a.startArgs = starts[s.name]
+		m.placeholders[s.name] = a
+	}
+}
+
+// placeholdersForRig returns a rig's placeholders that pass the view filter,
+// in the panel's role order.
+func (m *Model) placeholdersForRig(rig string) []*AgentLight {
+	var out []*AgentLight
+	for _, a := range m.placeholders {
+		if a.Rig == rig && m.view.matches(a) {
+			out = append(out, a)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Role != out[j].Role {
+			return roleOrder[out[i].Role] < roleOrder[out[j].Role]
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// panelAgents returns the rows of a rig's panel: its unpinned agents, then
+// its placeholders.
+func (m *Model) panelAgents(rig string) []*AgentLight {
+	return append(m.unpinnedAgents(m.agentsForRig(rig)), m.placeholdersForRig(rig)...)
+}
+
+// startCommand renders the command that launches a placeholder's role.
+func (a *AgentLight) startCommand() string {
+	return cliCommand(a.startArgs)
+}
+
+// cliCommand renders a gt command line, e.g. "gt witness start gastown".
+func cliCommand(args []string) string {
+	return cli.Name() + " " + strings.Join(args, " ")
+}
+
+// prepareStart asks for confirmation to launch the hovered placeholder's
+// session with its role's start command.
+func (m *Model) prepareStart() {
+	a := m.hoveredAgent
+	if a == nil || !a.placeholder {
+		m.flashMessage = "hover a missing role's placeholder to start it"
+		m.flashTime = time.Now()
+		return
+	}
+	m.pendingBulk = &bulkAction{
+		verb:     "start",
+		agents:   []*AgentLight{a},
+		sessions: []string{a.SessionName},
+		commands: map[string][]string{a.SessionName: a.startArgs},
+		prompt:   "start " + a.SessionName + " (" + a.startCommand() + ")",
+	}
+}
+
+// runCommands runs each target's gt command (a placeholder's start command,
+// or a role's start or stop from the control screen) from the town root, so
+// it resolves the same town gt top is showing.
+func runCommands(townRoot string, commands map[string][]string) func(target string) error {
+	return func(target string) error {
+		c := exec.Command(cli.Name(), commands[target]...)
+		c.Dir = townRoot
+		out, err := c.CombinedOutput()
+		if err != nil {
+			lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+			if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+				return fmt.Errorf("%s", last)
+			}
+			return err
+		}
+		return nil
+	}
+}
+
+// renderPlaceholder renders a placeholder's row: a dark LED and how to
+// start it.
+func (m *Model) renderPlaceholder(a *AgentLight) string {
+	name := padWidth(truncateWidth(a.Name, 10, "~"), 10)
+	if m.isJumpTarget(a) || m.isKeySelected(a) {
+		name = lipgloss.NewStyle().Reverse(true).Render(name)
+	}
+	if _, off := m.scheduledOffUntil(a, time.Now()); off {
+		return a.Icon + " " + placeholderStyle.Render(name+" "+m.led().cold+"  scheduled off")
+	}
+	return a.Icon + " " + placeholderStyle.Render(name+" "+m.led().cold+"  not running") +
+		"  " + statusDimStyle.Render("s: start")
+}
+
+// placeholderDetail renders the hover detail for a placeholder.
+func (m *Model) placeholderDetail(a *AgentLight) string {
+	parts := []string{
+		lipgloss.NewStyle().Bold(true).Render(a.Icon + " " + a.SessionName),
+		placeholderStyle.Render("no session"),
+		"s: start with " + a.startCommand(),
+	}
+	if sched := m.scheduleDetail(a, time.Now()); sched != "" {
+		parts = append(parts, statusDimStyle.Render(sched))
+	}
+	if touch := m.humanTouchLabel(a, time.Now()); touch != "" {
+		parts = append(parts, statusDimStyle.Render(touch))
+	}
+	return "  " + lipgloss.NewStyle().Foreground(colorTitle).Render(strings.Join(parts, "  ·  "))
+}
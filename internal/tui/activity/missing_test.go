@@ -0,0 +1,103 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// withRigs installs a prefix registry with the given rig -> prefix pairs for
+// the duration of the test.
+func withRigs(t *testing.T, rigs map[string]string) {
+	t.Helper()
+	old := session.DefaultRegistry()
+	r := session.NewPrefixRegistry()
+	for rig, prefix := range rigs {
+		r.Register(prefix, rig)
+	}
+	session.SetDefaultRegistry(r)
+	t.Cleanup(func() { session.SetDefaultRegistry(old) })
+}
+
+// withRigStatus reports the given rigs as parked or docked (rig -> reason)
+// for the duration of the test, and every other rig as up.
+func withRigStatus(t *testing.T, offline map[string]string) {
+	t.Helper()
+	old := rigStatus
+	rigStatus = func(_, rig string) (bool, string) {
+		return offline[rig] != "", offline[rig]
+	}
+	t.Cleanup(func() { rigStatus = old })
+}
+
+func TestUpdatePlaceholders(t *testing.T) {
+	townRoot := t.TempDir()
+	withRigs(t, map[string]string{"gastown": "gt", "beads": "bd", "wyvern": "wy"})
+	withRigStatus(t, map[string]string{"beads": "parked", "wyvern": "docked"})
+
+	witness := &AgentLight{SessionName: "gt-witness"}
+	parseSessionName(witness)
+	m := &Model{townRoot: townRoot, agents: []*AgentLight{witness}}
+	m.updatePlaceholders()
+
+	var got []string
+	for s := range m.placeholders {
+		got = append(got, s)
+	}
+	for _, want := range []string{"hq-mayor", "hq-deacon", "gt-refinery"} {
+		if m.placeholders[want] == nil {
+			t.Errorf("no placeholder for %s; got %v", want, got)
+		}
+	}
+	if len(m.placeholders) != 3 {
+		t.Errorf("placeholders = %v, want mayor, deacon and the gastown refinery only", got)
+	}
+	if r := m.placeholders["gt-refinery"]; r.Rig != "gastown" || strings.Join(r.startArgs, " ") != "refinery start gastown" {
+		t.Errorf("refinery placeholder = rig %q args %v", r.Rig, r.startArgs)
+	}
+
+	// The same light survives polls; it goes once the session is back.
+	mayor := m.placeholders["hq-mayor"]
+	m.updatePlaceholders()
+	if m.placeholders["hq-mayor"] != mayor {
+		t.Error("placeholder recreated on the next poll")
+	}
+	m.agents = append(m.agents, &AgentLight{SessionName: "hq-mayor"})
+	m.updatePlaceholders()
+	if m.placeholders["hq-mayor"] != nil {
+		t.Error("placeholder kept after the session came back")
+	}
+
+	m.rebuildRigOrder()
+	if rows := m.panelAgents("gastown"); len(rows) != 2 || rows[0] != witness || !rows[1].placeholder {
+		t.Errorf("gastown panel = %v, want the witness then the refinery placeholder", rows)
+	}
+}
+
+func TestStartPlaceholder(t *testing.T) {
+	withRigs(t, map[string]string{"gastown": "gt"})
+	m := &Model{townRoot: t.TempDir(), width: 120}
+	m.updatePlaceholders()
+	m.rebuildRigOrder()
+
+	m.hoveredAgent = m.placeholders["gt-witness"]
+	if out := m.renderHoverDetail(); !strings.Contains(out, "witness start gastown") {
+		t.Errorf("hover detail = %q, want the start command", out)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	if m.pendingBulk == nil || m.pendingBulk.verb != "start" || m.pendingBulk.sessions[0] != "gt-witness" {
+		t.Fatalf("pendingBulk = %+v, want a start of gt-witness", m.pendingBulk)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.pendingBulk != nil {
+		t.Error("n didn't cancel the start")
+	}
+
+	m.attachTo(m.hoveredAgent)
+	if !strings.Contains(m.flashMessage, "isn't running") {
+		t.Errorf("attach to a placeholder flashed %q", m.flashMessage)
+	}
+}
@@ -4,8 +4,7 @@
 package activity
 
 import (
-	"bufio"
-	"encoding/json"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -17,11 +16,15 @@ import (
 	"strconv"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -39,22 +42,45 @@ const (
 	LevelRateLimited                          // hit rate limit
 	LevelHitLimit                             // hit usage cap - agent dead until reset
 	LevelWaitingForHuman                      // blocked waiting for human input
+	LevelStarting                             // session still booting (see startup.go)
+	LevelReady                                // idle at its prompt per an agent_idle event (see ready.go)
 	LevelDead                                 // no session
 )
 
 // AgentLight represents one "LED" on the panel.
 type AgentLight struct {
-	Name        string
-	Icon        string
-	Role        string
-	Rig         string
-	SessionName string
-	AgentType   string // "claude", "opencode", "gemini", etc. (cached, read once from GT_AGENT)
+	Name         string
+	Icon         string
+	Role         string
+	Rig          string
+	SessionName  string
+	AgentID      string // stable identity from GT_AGENT_ID (e.g., "gastown/polecats/Toast"), derived from session name if unset
+	AgentType    string // "claude", "opencode", "gemini", etc. (cached, read once from GT_AGENT)
+	AgentVersion string // agent version seen in the pane (e.g., "2.0.76"), "" until seen
+
+	// Multi-pane sessions (panes.go): the pane captured instead of the active one
+	Panes      int    // panes in the session
+	PaneID     string // agent pane captured when Panes > 1, e.g. "%5"
+	PaneReason string // how PaneID was picked
+
+	// CaptureStaleSince is when pane captures of this session stopped coming
+	// back (a wedged session, see collector.go); zero while they're fresh.
+	CaptureStaleSince time.Time
+
+	// Container is the Docker container ID of an agent running in a
+	// container instead of tmux (docker.go), else "".
+	Container string
+	// Pod is "namespace/name" for an agent running in a Kubernetes pod
+	// (kubernetes.go), else "".
+	Pod string
 
 	// Tracking activity changes (is text scrolling?)
 	CurActivity    int64     // current window_activity unix timestamp
 	PrevActivity   int64     // previous poll's timestamp
 	LastChangeTime time.Time // when we last saw the timestamp change
+	paneHash       uint64    // hash of the pane's tail at the last fresh capture (panehash.go)
+	lastAttached   int64     // session_last_attached at the last poll
+	workDir        string    // pane working directory the repo was resolved from (repos.go)
 	Level          ActivityLevel
 
 	// Pane-derived status (updated every poll)
@@ -62,6 +88,7 @@ type AgentLight struct {
 	WaitingForHuman   bool   // agent is blocked on human input
 	WaitingReason     string // why waiting (e.g., "user prompt", "permission")
 	RateLimited       bool   // pane shows rate limit message
+	APIError          bool   // pane shows a provider-side API error (5xx, overloaded)
 	HitLimit          bool   // agent hit usage/token limit (dead until reset)
 	LimitResetInfo    string // extracted reset info (e.g., "resets 2pm (America/Los_Angeles)")
 	ContextPercent    int    // context remaining (0-100, 0=unknown); displayed as "used" (100-value)
@@ -72,6 +99,21 @@ type AgentLight struct {
 	IsCompacting      bool   // sticky: compaction detected, persists until cleared
 	PreCompactCtxPct  int    // context% snapshot from when compaction started, for drop detection
 	PrevStatusText    string // previous cycle's StatusText, used to avoid "streaming" clobbering useful info
+	ToolErrorCount    int    // consecutive failed tool results (cleared on next successful result)
+	LastToolError     string // summary of the most recent failed tool result (e.g., "Exit code 1")
+	Looping           bool   // same tool invocation started loopThreshold+ times within loopWindow
+	ScheduledOff      bool   // in a top.offline window of expected downtime (schedule.go)
+	Repo              string // git repo the agent's pane is in, "" if none (repos.go)
+	MergeQueuePos     int    // position of the agent's branch in the rig's merge queue, 0 if none (mergequeue.go)
+	LoopTool          string // the repeated tool invocation (when Looping)
+	LoopCount         int    // how many times LoopTool started within the window
+	StartupPhase      string // boot phase while the session is young (e.g., "loading MCP servers"), "" once up
+	TokensPerMin      int    // estimated tokens/minute over spendWindow (from Claude's "↓ 6.8k tokens")
+	CurrentTask       string // in-progress todo read from the session transcript (see transcript.go)
+	PushedBranch      string // branch of the last successful git push seen in the pane (pushes.go, sticky)
+	PRNumber          int    // number of the last pull request created in the pane, 0 if none (sticky)
+	Progress          int    // percent done the agent last reported, while progressOn (progress.go)
+	ProgressLabel     string // what the agent reported progress through, e.g. "through test suite"
 
 	// Work tracking (from beads DB, updated on slower cadence)
 	WorkBeadID    string // assigned/hooked bead ID (e.g., "wp-abc123")
@@ -80,6 +122,8 @@ type AgentLight struct {
 	StepCurrent   string // current step title (e.g., "branch-setup"), empty if no molecule
 	StepsDone     int    // completed steps in molecule
 	StepsTotal    int    // total steps in molecule
+	ConvoyID      string // convoy tracking the hooked bead (from its convoy_id field), empty if none
+	EpicID        string // epic the hooked bead is a child of, empty if none
 	AgentState    string // agent lifecycle state from bead (e.g., "working", "idle", "stuck")
 	LastPatrol    string // last patrol summary (sticky — persists until replaced by newer patrol)
 
@@ -88,6 +132,40 @@ type AgentLight struct {
 	SessionCreated time.Time // when the tmux session was created (for uptime)
 	renderY        int       // Y position in render (for hover detection)
 	renderHeight   int       // height of rendered agent (for hover detection)
+	renderX        int       // X of the agent's column (multi-column layout)
+	renderWidth    int       // width of the agent's column; 0 = full width
+
+	lastToolResultSig string                 // last tool call/result pair seen in the pane (for error dedup)
+	lastToolResultRun int                    // how many of it were in a row at the bottom of the pane
+	toolStarts        []toolStart            // recent tool starts within loopWindow (for loop detection)
+	prevTool          string                 // previous poll's CurrentTool (to infer starts from pane changes)
+	limitReported     string                 // limit kind last reported via limit_hit ("" when not limited)
+	failingSince      time.Time              // when the pane started showing API errors or rate limits (outage.go)
+	contextSeen       time.Time              // when ContextPercent was last parsed from the pane
+	sessionLimitSeen  time.Time              // when SessionLimitPct was last parsed from the pane
+	restartSeen       time.Time              // timestamp of the last agent_restarted event applied
+	turnTokens        int                    // last seen per-turn token counter (0 between turns)
+	spendSamples      []spendSample          // token deltas within spendWindow
+	spendSince        time.Time              // first poll that tracked spend (rate warm-up)
+	limitReset        resetClock             // parsed LimitResetInfo (usage cap countdown)
+	sessionReset      resetClock             // parsed SessionLimitReset
+	apiLive           bool                   // this poll's status came from the OpenCode server (opencode_api.go)
+	readyAt           time.Time              // latest agent_idle event (ready.go)
+	progressAt        time.Time              // latest progress report or clearing event applied (progress.go)
+	progressOn        bool                   // Progress is shown: reported, and not since cleared or gone stale
+	workAt            time.Time              // latest sign of work: a tool or compaction starting, or the pane busy
+	busyInPane        bool                   // the pane shows the agent working: streaming, a tool panel or a pending op
+	toolFromPane      bool                   // a registered pane parser set CurrentTool this poll (parsers.go)
+	parserGapSince    time.Time              // since when output showed with nothing parsed (parsergap.go)
+	parserGapLogged   bool                   // the current parser gap was logged
+	mergeEntry        *mergeEntry            // the agent's branch in the merge queue, nil if none (mergequeue.go)
+	pushBead          string                 // WorkBeadID when PushedBranch/PRNumber were seen
+	alerts            map[string]*alertState // condition -> escalation progress (alerts.go)
+	chrome            *chromeSet             // pane chrome for the detected agent version (chrome.go)
+
+	// Placeholder for an expected role with no session (missing.go)
+	placeholder bool
+	startArgs   []string // gt arguments that launch the role
 }
 
 // Model is the bubbletea model for the blinkenlights TUI.
@@ -99,18 +177,66 @@ type Model struct {
 	agents []*AgentLight
 	rigs   []string // ordered rig names (hq first)
 
+	// Dark placeholder lights for expected roles with no session, by
+	// session name (missing.go); never in agents
+	placeholders map[string]*AgentLight
+
 	// Animation state
-	blinkOn bool // toggles every tick for blink effect
-	tickNum int  // counts ticks for sparkle effects
+	blinkOn   bool       // toggles every animation frame for blink effect
+	tickNum   int        // counts animation frames for sparkle effects
+	noAnimate bool       // --no-animate: lights steady, sparkle still (animate.go)
+	wall      *wallState // gt wall: rotating rig pages of large lights (wall.go); nil in gt top
+	frames    frameStats // poll+render cost and backoff (budget.go)
+
+	// tmuxStall is set while tmux list-sessions times out (budget.go)
+	tmuxStall      error
+	tmuxStallSince time.Time
+
+	// Data sources' latest outcomes, for the degraded footer (sources.go)
+	sources map[string]*sourceHealth
 
 	// Mouse hover state
 	hoveredAgent *AgentLight // currently hovered agent
+	keySelected  bool        // hoveredAgent was selected with the keyboard (keynav.go)
 	mouseX       int
 	mouseY       int
 
 	// Double-click detection (bubbletea has no native double-click)
-	lastClickAgent *AgentLight // agent that was last left-clicked
-	lastClickTime  time.Time   // when the last left-click occurred
+	lastClickAgent *AgentLight            // agent that was last left-clicked
+	lastClickTime  time.Time              // when the last left-click occurred
+	clickActions   map[string]clickAction // role -> double-click action (dblclick.go)
+
+	// Activity from pane content instead of window_activity (panehash.go)
+	activityFromPane bool
+
+	// Log agents whose output the parsers derive nothing from (parsergap.go)
+	parserGapCheck bool
+
+	// Windows of expected downtime (schedule.go; town settings top.offline)
+	offline []offlineWindow
+
+	// Today's witness nudges and whether they worked (nudges.go)
+	nudges *nudgeLog
+
+	// The rigs' merge queues, rebuilt from events (mergequeue.go)
+	mergeQueue *mergeQueue
+
+	// Repo names by pane working directory (repos.go)
+	repos map[string]string
+
+	// Minimap: one braille dot per agent on the row under the header
+	minimapY     int           // screen row of the minimap
+	minimapCells []minimapCell // dot positions from the last render
+	jumpAgent    *AgentLight   // agent highlighted by the last minimap click
+	jumpTime     time.Time     // when jumpAgent was set
+
+	// View filter (gt top --rig/--role/--level) and a bulk action awaiting y/n
+	view        agentFilter
+	filterBar   *filterBar // the filter being edited (/), or nil (filterbar.go)
+	pendingBulk *bulkAction
+
+	// Focus pins: agents shown in a fixed section above the rig panels
+	pinned map[string]bool // session name -> pinned (f key toggles the hovered agent)
 
 	// Status flash message (e.g., "Opened terminal for gt-foo-crew-bar")
 	flashMessage string    // message to display briefly
@@ -125,12 +251,168 @@ type Model struct {
 	rigBeadsDirs  map[string]string           // rig name -> beads dir path (cached)
 	patrolCache   map[string]patrolCacheEntry // "rig/role" -> cached patrol summary
 
+	// Session include/exclude globs (town settings top.include/exclude plus flags)
+	filter sessionFilter
+
+	// Claude Code transcript adapter (gt top --transcripts); nil = pane parsing only
+	transcripts *transcriptReader
+
+	// OpenCode server API source for sessions with GT_OPENCODE_URL set
+	openCode   *openCodeSource
+	docker     *dockerSource     // nil unless container agents are monitored (docker.go)
+	kubernetes *kubernetesSource // nil unless pod agents are monitored (kubernetes.go)
+
+	// Per-session pane capture goroutines and their reducer (collector.go)
+	collectors *collectorPool
+
+	// Alert escalation ladders (town settings top.alerts)
+	alertRules []alertRule
+
+	// LED glyphs (town settings top.theme/top.glyphs, gt top --theme); zero = circles
+	theme ledTheme
+
+	// Pane chrome per agent version: built-ins plus town settings top.chrome; nil = built-ins
+	chromeSets []*chromeSet
+
+	// /cost or /status answer shown over the panels until a key is pressed
+	probe *probeResult
+
+	// Guided tour (tour.go): F1, or on first run; nil when closed
+	tour *tourState
+
+	// Sticky field expiry (from town settings top.*_ttl; 0 = never expire)
+	contextTTL      time.Duration
+	sessionLimitTTL time.Duration
+
+	// Rig SLAs (from town settings rig_slas, evaluated from events on a slow cadence)
+	slaConfig   *config.RigSLAConfig    // nil when no SLAs are configured
+	rigSLAs     map[string]*feed.RigSLA // rig name -> current SLA state
+	lastSLAPoll time.Time               // when SLAs were last evaluated
+	rigSLOs     map[string]*feed.RigSLO // rig name -> failure-rate SLO state
+	sloAlerts   map[string]*alertState  // rig name -> slo_burn ladder while burning
+
+	// Parked or docked rigs, re-checked on a slow cadence (see missing.go)
+	offlineRigs       map[string]string // rig -> "parked" or "docked"
+	lastRigStatusPoll time.Time
+
+	// Mayor/deacon orchestration state for the hq panel header (see hq.go)
+	hq         *hqStatus
+	lastHQPoll time.Time
+
+	// Status ledger for gt forensics (see ledger.go)
+	ledger          map[string]map[string]string // session -> fields last recorded
+	ledgerRetention time.Duration                // from town settings top.ledger_retention
+	lastLedgerPrune time.Time
+
+	// Convoy/epic grouping (see convoy.go)
+	groups        map[string]*workGroup // convoy or epic ID -> group, from the last beads poll
+	groupByConvoy bool                  // panels per convoy/epic instead of per rig
+
+	// Agent comparison overlay (compare.go): v on two agents in turn
+	compareFirst *AgentLight // picked first, awaiting the second
+	compare      *comparison // the open overlay
+	beadsClosed  []beadClose // the last compareWindow's closed beads, for the overlay
+
+	// Quick switcher overlay (switcher.go): ctrl+f
+	switcher *switcher
+
+	// Town control screen (control.go): T
+	control *controlScreen
+
+	// Macros (macros.go): M opens the menu on the hovered agent
+	macros    []macro
+	macroMenu *macroMenu
+
+	// Attention inbox overlay (inbox.go): i
+	inbox          *inbox
+	inboxDismissed map[*AgentLight]ActivityLevel // acknowledged from the inbox, at the level then
+
+	// Scrolling event ticker under the panels (ticker.go); nil unless enabled
+	ticker *ticker
+
+	// Per-rig history chart under each rig header (righistory.go); nil unless enabled
+	rigHistory *rigHistory
+
+	// Screensaver and auto-exit after operator inactivity (idle.go)
+	idle         idleConfig
+	lastInput    time.Time // last key press or mouse event
+	autoExited   bool      // quit by top.exit_after
+	restoreHover string    // session to re-select once seen (RestoreView)
+
+	// Running inside another program's TUI (embed.go, pkg/activity)
+	embedded bool
+
+	// Agent health events (limit_hit, loop_detected, provider_outage) are
+	// emitted only by the daemon's agent watch (SetReporter); gt top shows
+	// the same states without writing anything.
+	reportAs  string // actor to emit as; "" = don't emit
+	reporting bool   // past the reporter's first poll
+
+	// Steady lights for big towns and slow links (calm.go)
+	calm   calmMode
+	remote bool // running over SSH
+
+	// Lights in each rig's own hue (rigcolor.go)
+	colorBy colorBy
+
+	// Outputs fed the status after every poll (sink.go)
+	sinks      []Sink
+	sinkFailed map[Sink]bool // failed on the last poll
+
+	// Signature check for events read from the town's log; nil when the
+	// town doesn't sign events (town settings event_signing)
+	verify *events.Verifier
+
+	// Custom event types from town settings event_types (ticker.go); nil
+	// when the town declares none
+	eventTypes *events.Registry
+
+	// Last human attach or input per session, shared through the town's
+	// human touch file (humantouch.go)
+	humanTouches map[string]humanTouch
+
+	// Muted agents by session, re-read from the town's mute file every poll
+	// (mute.go)
+	mutes map[string]Mute
+
+	// Agents relaunched under new session names (relaunch.go)
+	sessionMoves  map[string]string // old session -> the session its agent moved to
+	agentSessions map[string]string // agent ID -> session it was last seen in
+
+	// Redacted mode for screen sharing (redact.go): mask titles, paths, args
+	redact bool
+
+	// Burn-down panel for one convoy or epic (burndown.go; b key, gt top --burndown)
+	burndownID       string
+	burndown         *burndown // nil until the first read
+	lastBurndownPoll time.Time
+
+	// Status report rewritten every poll (see report.go); "" = off
+	reportPath   string
+	reportFailed bool // last write failed; already flashed
+
 	// Poll configuration
 	pollInterval        time.Duration // how often to poll tmux sessions (default 3s)
 	lastRegistryRefresh time.Time     // when we last re-read rigs.json for new rigs
 
+	// Poll scheduling (see wake.go)
+	pollSeq     int       // sequence of the current poll tick; stale ticks are dropped
+	polling     bool      // a pollSessions command is in flight
+	lastPoll    time.Time // when the last poll started
+	wakeLive    bool      // tmux wait-for is working, so idle polling can slow down
+	wakePending bool      // a wake arrived mid-poll; re-poll right after it
+
+	// The end of the events file, read once per poll (eventtail.go)
+	tail eventTail
+
 	// Plugin event consumption (for non-Claude agents like OpenCode)
 	recentToolEvents []toolEvent // recent tool_started events (< 15s old)
+	firstPoll        time.Time   // when gt top first polled; earlier restarts are history
+	recentComms      []commEvent // mail/nudge/sling/escalation events within commWindow
+
+	// Multi-column layout state, set only while rendering columns (layout.go)
+	colX int
+	colW int
 
 	// Stats
 	totalAgents      int
@@ -141,11 +423,22 @@ type Model struct {
 	rateLimitedCount int
 	hitLimitCount    int
 	waitingCount     int
+	loopingCount     int
+	startingCount    int
+	readyCount       int
+	tokensPerMin     int  // town-wide estimated spend rate
+	healthScore      int  // town score, 0-100; see townScore
+	scored           bool // healthScore is set: there are agents to rate
+
+	// Probable provider outages in progress, by provider (outage.go)
+	outages map[string]*outage
 }
 
 // NewModel creates a new activity TUI model.
 // pollInterval controls how often tmux sessions are polled; 0 uses the default (3s).
-func NewModel(pollInterval time.Duration) *Model {
+// townRoot, when non-empty, is used as-is instead of auto-detection (gt top --town).
+// With no town root the model runs in tmux-only mode (see degraded.go).
+func NewModel(pollInterval time.Duration, townRoot string) *Model {
 	if pollInterval <= 0 {
 		pollInterval = 3 * time.Second
 	}
@@ -153,9 +446,25 @@ func NewModel(pollInterval time.Duration) *Model {
 	// Try workspace detection from CWD first, then fall back to env vars.
 	// gt top can be run from anywhere (not just inside the town), so the
 	// GT_TOWN_ROOT / GT_ROOT env vars set by shell integration are critical.
-	townRoot := detectTownRoot()
+	if townRoot == "" {
+		townRoot = detectTownRoot()
+	}
 
 	var townName string
+	var slaConfig *config.RigSLAConfig
+	contextTTL, sessionLimitTTL := defaultContextTTL, defaultSessionLimitTTL
+	ledgerRetention := defaultLedgerRetention
+	var reportPath string
+	var filter sessionFilter
+	var transcripts, docker, redact, ticker, parserGapCheck, rigHistory bool
+	var screensaver, exitAfter time.Duration
+	var kubernetes *config.TopKubernetesConfig
+	var alertRules []alertRule
+	var themeName, calm, colorByMode, activitySource string
+	var glyphs, doubleClick map[string]string
+	var chromeCfg []config.TopChromePatterns
+	var macroCfg []config.TopMacro
+	var offlineCfg []config.TopOfflineWindow
 	if townRoot != "" {
 		// Initialize session prefix registry so IsKnownSession and
 		// ParseSessionName can resolve rig-specific prefixes (e.g., "wi-"
@@ -167,6 +476,36 @@ func NewModel(pollInterval time.Duration) *Model {
 			townName = tc.Name
 		}
 
+		// Rig SLA targets are optional; without them no badges are shown.
+		if ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+			slaConfig = ts.RigSLAs
+			if ts.Top != nil {
+				contextTTL = config.ParseDurationOrDefault(ts.Top.ContextTTL, defaultContextTTL)
+				sessionLimitTTL = config.ParseDurationOrDefault(ts.Top.SessionLimitTTL, defaultSessionLimitTTL)
+				filter = sessionFilter{include: ts.Top.Include, exclude: ts.Top.Exclude}
+				transcripts = ts.Top.Transcripts
+				redact = ts.Top.Redact
+				ticker = ts.Top.Ticker
+				screensaver = config.ParseDurationOrDefault(ts.Top.Screensaver, 0)
+				exitAfter = config.ParseDurationOrDefault(ts.Top.ExitAfter, 0)
+				docker = ts.Top.Docker
+				kubernetes = ts.Top.Kubernetes
+				alertRules = parseAlertRules(ts.Top.Alerts)
+				themeName, glyphs = ts.Top.Theme, ts.Top.Glyphs
+				chromeCfg = ts.Top.Chrome
+				ledgerRetention = config.ParseDurationOrDefault(ts.Top.LedgerRetention, defaultLedgerRetention)
+				reportPath = ts.Top.Report
+				calm = ts.Top.Calm
+				colorByMode = ts.Top.ColorBy
+				doubleClick = ts.Top.DoubleClick
+				activitySource = ts.Top.ActivitySource
+				offlineCfg = ts.Top.Offline
+				parserGapCheck = ts.Top.ParserGapCheck
+				macroCfg = ts.Top.Macros
+				rigHistory = ts.Top.RigHistory
+			}
+		}
+
 		// Ensure GT_DOLT_PORT is set so bd CLI connects to the correct
 		// Dolt server. Without this, bd falls back to dolt-server.port
 		// files in each .beads/ dir which may contain stale port numbers
@@ -179,13 +518,63 @@ func NewModel(pollInterval time.Duration) *Model {
 		}
 	}
 
-	return &Model{
+	m := &Model{
 		agents:              make([]*AgentLight, 0),
 		townRoot:            townRoot,
 		townName:            townName,
+		verify:              events.NewVerifier(townRoot),
+		eventTypes:          events.LoadRegistry(townRoot),
+		humanTouches:        loadHumanTouches(townRoot),
+		slaConfig:           slaConfig,
+		contextTTL:          contextTTL,
+		sessionLimitTTL:     sessionLimitTTL,
+		ledgerRetention:     ledgerRetention,
+		filter:              filter,
+		openCode:            newOpenCodeSource(),
+		collectors:          newCollectorPool(),
+		alertRules:          alertRules,
 		pollInterval:        pollInterval,
 		lastRegistryRefresh: time.Now(),
+		remote:              remoteTerminal(),
+	}
+	if transcripts {
+		m.EnableTranscripts()
+	}
+	m.SetRedact(redact)
+	if ticker {
+		m.EnableTicker()
+	}
+	if rigHistory {
+		m.EnableRigHistory()
+	}
+	m.SetScreensaver(screensaver)
+	m.SetExitAfter(exitAfter)
+	if docker {
+		m.EnableDocker()
+	}
+	if kubernetes != nil {
+		m.EnableKubernetes(kubernetes)
+	}
+	_ = m.SetTheme(themeName, glyphs) // a bad setting keeps the default look
+	_ = m.SetCalm(calm)
+	_ = m.SetColorBy(colorByMode)
+	_ = m.SetActivitySource(activitySource)
+	m.SetParserGapCheck(parserGapCheck)
+	m.clickActions = parseClickActions(doubleClick)
+	m.SetReportPath(reportPath)
+	if err := m.SetChromePatterns(chromeCfg); err != nil {
+		m.flashMessage = err.Error() // the valid sets still apply
+		m.flashTime = time.Now()
+	}
+	if err := m.SetOfflineWindows(offlineCfg); err != nil {
+		m.flashMessage = err.Error() // the valid windows still apply
+		m.flashTime = time.Now()
+	}
+	if err := m.SetMacros(macroCfg); err != nil {
+		m.flashMessage = err.Error() // the valid macros still apply
+		m.flashTime = time.Now()
 	}
+	return m
 }
 
 // detectTownRoot finds the town root directory using multiple strategies.
@@ -266,87 +655,50 @@ func townRootFromShellCache() string {
 	return ""
 }
 
-// toolEvent represents a parsed tool_started/tool_finished or compaction_started/compaction_finished
-// event from the events JSONL file, used to populate CurrentTool and IsCompacting for non-Claude agents.
+// toolEvent represents a parsed tool_started/tool_finished, compaction_started/compaction_finished
+// or agent_idle event from the events JSONL file, used to populate CurrentTool, IsCompacting and
+// the ready level for non-Claude agents.
 type toolEvent struct {
 	Timestamp time.Time
 	Actor     string // e.g., "gastown/crew/joe"
+	AgentID   string // stable agent identity (from payload.agent_id)
 	Session   string // tmux session name (from payload.session)
 	Tool      string // e.g., "Bash(git status)"
-	EventType string // "tool_started" or "tool_finished"
-}
-
-// readRecentToolEvents reads the last N lines of the events JSONL file
-// and extracts tool_started/tool_finished events from the last 15 seconds.
-// This is called on each poll to provide tool execution info for non-Claude agents.
-func (m *Model) readRecentToolEvents() {
-	m.recentToolEvents = nil
-
-	if m.townRoot == "" {
-		return
-	}
 
-	eventsPath := filepath.Join(m.townRoot, ".events.jsonl")
-	f, err := os.Open(eventsPath)
-	if err != nil {
-		return
-	}
-	defer f.Close()
+	PrevSession string // agent_restarted: the session the agent ran in before (payload.previous_session)
 
-	// Seek to near the end of the file — we only care about recent events.
-	// Tool events use a 15s window, but compaction events use 10 minutes.
-	// At ~2 events/s * ~160 bytes, 10 minutes ≈ 192KB. Use 256KB to be safe.
-	const tailSize = 256 * 1024
-	info, err := f.Stat()
-	if err != nil {
-		return
-	}
-	if info.Size() > tailSize {
-		if _, err := f.Seek(-tailSize, 2); err != nil {
-			return
-		}
-	}
+	Progress      int    // percent done (payload.progress), -1 when the event carries none
+	ProgressLabel string // what the agent is working through (payload.progress_label)
 
-	cutoff := time.Now().Add(-15 * time.Second)
-	compactionCutoff := time.Now().Add(-10 * time.Minute) // compaction events need longer window
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
+	EventType string // "tool_started", "tool_finished", "compaction_*", "agent_idle", "agent_progress", or "agent_restarted"
+}
 
-		// Quick pre-filter: only parse lines containing relevant event types
-		lineStr := string(line)
-		if !strings.Contains(lineStr, "tool_started") && !strings.Contains(lineStr, "tool_finished") &&
-			!strings.Contains(lineStr, "compaction_started") && !strings.Contains(lineStr, "compaction_finished") {
-			continue
-		}
+// readRecentToolEvents extracts the tool, compaction, idle, progress and
+// restart events from the events tail: tool events from the last 15
+// seconds, the rest from the last 10 minutes. They provide tool execution
+// info for non-Claude agents.
+func (m *Model) readRecentToolEvents(now time.Time) {
+	m.recentToolEvents = nil
 
-		var evt struct {
-			Timestamp string                 `json:"ts"`
-			Type      string                 `json:"type"`
-			Actor     string                 `json:"actor"`
-			Payload   map[string]interface{} `json:"payload"`
-		}
-		if err := json.Unmarshal(line, &evt); err != nil {
-			continue
-		}
+	cutoff := now.Add(-15 * time.Second)
+	compactionCutoff := now.Add(-10 * time.Minute) // compaction events need longer window
+	for _, evt := range m.tail.recent {
 		if evt.Type != "tool_started" && evt.Type != "tool_finished" &&
-			evt.Type != "compaction_started" && evt.Type != "compaction_finished" {
+			evt.Type != "compaction_started" && evt.Type != "compaction_finished" &&
+			evt.Type != events.TypeAgentRestarted && evt.Type != events.TypeAgentIdle &&
+			evt.Type != events.TypeAgentProgress {
 			continue
 		}
 
-		ts, err := time.Parse(time.RFC3339, evt.Timestamp)
-		if err != nil {
-			continue
-		}
+		ts := evt.At
 		// Compaction events use a longer window — they're rare (one pair per
 		// compaction cycle) and need to persist through the entire compaction
-		// duration (30-90+ seconds). Tool events use the short 15s window
+		// duration (30-90+ seconds). Idle events too, so an agent that went
+		// idle before gt top started still shows ready, and progress, which
+		// long tasks report seldom. Tool events use the short 15s window
 		// because they're rapid-fire.
 		isCompactionEvent := evt.Type == "compaction_started" || evt.Type == "compaction_finished"
-		if isCompactionEvent {
+		if isCompactionEvent || evt.Type == events.TypeAgentIdle || evt.Type == events.TypeAgentProgress {
 			if ts.Before(compactionCutoff) {
 				continue
 			}
@@ -360,6 +712,7 @@ func (m *Model) readRecentToolEvents() {
 			Timestamp: ts,
 			Actor:     evt.Actor,
 			EventType: evt.Type,
+			Progress:  -1,
 		}
 		if evt.Payload != nil {
 			if tool, ok := evt.Payload["tool"].(string); ok && tool != "unknown" {
@@ -368,21 +721,33 @@ func (m *Model) readRecentToolEvents() {
 			if session, ok := evt.Payload["session"].(string); ok {
 				te.Session = session
 			}
+			if agentID, ok := evt.Payload["agent_id"].(string); ok {
+				te.AgentID = agentID
+			}
+			if prev, ok := evt.Payload["previous_session"].(string); ok {
+				te.PrevSession = prev
+			}
+			if pct, ok := evt.Payload["progress"].(float64); ok && pct >= 0 && pct <= 100 {
+				te.Progress = int(pct)
+				te.ProgressLabel, _ = evt.Payload["progress_label"].(string)
+			}
 		}
 		m.recentToolEvents = append(m.recentToolEvents, te)
 	}
 }
 
 // applyToolEvents populates CurrentTool and IsCompacting for non-Claude agents
-// using plugin-emitted events. Matches events to agents by tmux session name
-// (preferred) or actor name (fallback).
+// using plugin-emitted events. Matches events to agents by stable agent ID
+// (preferred), then tmux session name, then actor name (legacy fallback for
+// events emitted before GT_AGENT_ID existed).
 // This is the sole owner of CurrentTool for OpenCode agents — parsePaneContentOpenCode
-// does not set it.
+// does not set it — except those whose server answered this poll (opencode_api.go).
 func (m *Model) applyToolEvents() {
 	// Reset CurrentTool for all non-Claude agents first. If no recent event
-	// confirms a tool is still running, it should show as cleared.
+	// confirms a tool is still running, it should show as cleared. A tool a
+	// registered pane parser read this poll stands unless an event overrides it.
 	for _, a := range m.agents {
-		if !isClaudeAgent(a.AgentType) {
+		if !isClaudeAgent(a.AgentType) && !a.apiLive && !a.toolFromPane {
 			a.CurrentTool = ""
 		}
 	}
@@ -391,36 +756,14 @@ func (m *Model) applyToolEvents() {
 		return
 	}
 
-	// Build index of agents that need event-based tool info (non-Claude only)
-	needsEvents := make(map[string]*AgentLight)
-	for _, a := range m.agents {
-		if !isClaudeAgent(a.AgentType) {
-			needsEvents[a.SessionName] = a
-		}
-	}
-	if len(needsEvents) == 0 {
+	idx := m.eventAgents()
+	if len(idx.bySession) == 0 {
 		return
 	}
 
-	// Process events in chronological order — last event for a session wins.
+	// Process events in chronological order — last event for an agent wins.
 	for _, evt := range m.recentToolEvents {
-		// Find the matching agent by session name or actor fallback
-		var matched *AgentLight
-		if evt.Session != "" {
-			matched = needsEvents[evt.Session]
-		}
-		if matched == nil && evt.Actor != "" {
-			parts := strings.Split(evt.Actor, "/")
-			if len(parts) > 0 {
-				lastPart := parts[len(parts)-1]
-				for _, a := range needsEvents {
-					if strings.Contains(a.SessionName, lastPart) {
-						matched = a
-						break
-					}
-				}
-			}
-		}
+		matched := m.eventAgent(idx, evt)
 		if matched == nil {
 			continue
 		}
@@ -428,7 +771,8 @@ func (m *Model) applyToolEvents() {
 		switch evt.EventType {
 		case "tool_started":
 			matched.CurrentTool = evt.Tool
-		case "tool_finished":
+			recordToolStart(matched, evt.Tool, evt.Timestamp)
+		case "tool_finished", events.TypeAgentIdle:
 			matched.CurrentTool = ""
 		case "compaction_started":
 			matched.IsCompacting = true
@@ -439,10 +783,73 @@ func (m *Model) applyToolEvents() {
 	}
 }
 
+// eventIndex indexes the agents that plugin events describe: non-Claude
+// agents, except those whose OpenCode server answered this poll.
+type eventIndex struct {
+	bySession map[string]*AgentLight
+	byID      map[string]*AgentLight
+}
+
+func (m *Model) eventAgents() eventIndex {
+	idx := eventIndex{bySession: make(map[string]*AgentLight), byID: make(map[string]*AgentLight)}
+	for _, a := range m.agents {
+		if !isClaudeAgent(a.AgentType) && !a.apiLive {
+			idx.bySession[a.SessionName] = a
+			if a.AgentID != "" {
+				idx.byID[a.AgentID] = a
+			}
+		}
+	}
+	return idx
+}
+
+// eventAgent finds the agent an event is about by stable agent ID, then
+// tmux session name, then actor name (legacy fallback for events emitted
+// before GT_AGENT_ID existed). nil if none.
+func (m *Model) eventAgent(idx eventIndex, evt toolEvent) *AgentLight {
+	if evt.AgentID != "" {
+		if a := idx.byID[evt.AgentID]; a != nil {
+			return a
+		}
+	}
+	if evt.Session != "" {
+		if a := idx.bySession[m.movedSession(evt.Session)]; a != nil {
+			return a
+		}
+	}
+	if evt.Actor != "" {
+		parts := strings.Split(evt.Actor, "/")
+		lastPart := parts[len(parts)-1]
+		for _, a := range idx.bySession {
+			if strings.Contains(a.SessionName, lastPart) {
+				return a
+			}
+		}
+	}
+	return nil
+}
+
 // Init initializes the model.
 func (m *Model) Init() tea.Cmd {
+	m.polling = true
+	m.lastPoll = time.Now()
+	m.lastInput = time.Now()
+	var scroll tea.Cmd
+	if m.ticker != nil {
+		scroll = tickerTick()
+	}
+	if m.embedded {
+		return tea.Batch(m.pollSessions(), scroll, m.startAnimation(), waitForWake())
+	}
+	if m.wall != nil {
+		// No mouse tracking: nobody points at a wall.
+		return tea.Batch(m.pollSessions(), m.startAnimation(), waitForWake(), m.wallTick(), tea.SetWindowTitle("GT Wall"))
+	}
 	return tea.Batch(
 		m.pollSessions(),
+		scroll,
+		m.startAnimation(),
+		waitForWake(),
 		tea.SetWindowTitle("GT Activity"),
 		tea.EnableMouseAllMotion, // Enable mouse tracking
 	)
@@ -452,15 +859,40 @@ func (m *Model) Init() tea.Cmd {
 type (
 	sessionsMsg struct {
 		sessions []sessionInfo
+		elapsed  time.Duration    // time spent listing and capturing
+		err      error            // listing failed; on a timeout the last known agents are kept
+		sources  map[string]error // each source this poll read, nil if it read fine (sources.go)
+	}
+	pollMsg struct {
+		seq int
 	}
-	pollMsg struct{}
 )
 
 type sessionInfo struct {
 	name      string
 	activity  int64
-	created   int64    // unix timestamp when session was created
-	paneLines []string // captured pane content for status extraction
+	created   int64           // unix timestamp when session was created
+	paneLines []string        // captured pane content for status extraction
+	openCode  *openCodeStatus // status from the agent's OpenCode server, nil if unavailable
+
+	container string                    // Docker container ID for container agents (docker.go), else ""
+	pod       string                    // "namespace/name" for pod agents (kubernetes.go), else ""
+	agentType string                    // agent type from the container label, "" to detect
+	heartbeat *polecat.SessionHeartbeat // container agent's heartbeat file, nil if unread
+
+	captureDepth int // scrollback lines to capture (capture.go); 0 = default
+
+	panes      int    // panes in the session (panes.go)
+	pane       string // agent pane to capture when panes > 1, else ""
+	paneReason string // how pane was picked
+
+	captureStale bool      // no fresh capture this poll; paneLines is the last good one
+	staleSince   time.Time // when captures of this session stopped coming back
+
+	lastAttached int64      // unix timestamp of the last client attach, 0 if never
+	attached     int        // clients attached right now
+	workDir      string     // active pane's working directory (repos.go)
+	touch        humanTouch // last human attach or input tmux knows of (humantouch.go)
 }
 
 // registryRefreshInterval controls how often gt top re-reads rigs.json to
@@ -488,59 +920,73 @@ func (m *Model) refreshRegistry() {
 	}
 }
 
-// pollSessions queries tmux for all Gas Town session activity.
+// pollSessions queries tmux for all Gas Town session activity, from a
+// snapshot of the poll's inputs (snapshot.go).
 func (m *Model) pollSessions() tea.Cmd {
-	return func() tea.Msg {
-		cmd := tmux.BuildCommand("list-sessions", "-F", "#{session_name}|#{window_activity}|#{session_created}")
-		out, err := cmd.Output()
-		if err != nil {
-			return sessionsMsg{sessions: nil}
-		}
+	return m.pollInputs().poll
+}
 
-		var sessions []sessionInfo
-		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
-			if line == "" {
-				continue
-			}
-			parts := strings.SplitN(line, "|", 3)
-			if len(parts) < 2 {
-				continue
-			}
-			name := parts[0]
-			// Only Gas Town sessions (uses prefix registry to match all rig prefixes)
-			if !session.IsKnownSession(name) {
-				continue
-			}
-			var ts int64
-			if _, err := fmt.Sscanf(parts[1], "%d", &ts); err != nil || ts == 0 {
-				continue
-			}
-			var created int64
-			if len(parts) >= 3 {
-				fmt.Sscanf(parts[2], "%d", &created)
-			}
-			sessions = append(sessions, sessionInfo{name: name, activity: ts, created: created})
-		}
+// listSessions returns the activity timestamps of all Gas Town sessions,
+// without pane content.
+func listSessions() ([]sessionInfo, error) {
+	out, err := tmux.Output(tmuxTimeout, "list-sessions", "-F", "#{session_name}|#{window_activity}|#{session_created}|#{session_last_attached}|#{session_attached}|#{pane_current_path}")
+	if err != nil {
+		return nil, err
+	}
 
-		// Capture pane content for all sessions in a single shell invocation.
-		// This replaces N individual tmux capture-pane subprocesses with 1.
-		if len(sessions) > 0 {
-			paneMap := batchCapturePanes(sessions)
-			for i := range sessions {
-				if lines, ok := paneMap[sessions[i].name]; ok {
-					sessions[i].paneLines = lines
-				}
-			}
+	var sessions []sessionInfo
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
 		}
-
-		return sessionsMsg{sessions: sessions}
+		parts := strings.SplitN(line, "|", 6)
+		if len(parts) < 2 {
+			continue
+		}
+		name := parts[0]
+		// Only Gas Town sessions (uses prefix registry to match all rig prefixes)
+		if !session.IsKnownSession(name) {
+			continue
+		}
+		var ts int64
+		if _, err := fmt.Sscanf(parts[1], "%d", &ts); err != nil || ts == 0 {
+			continue
+		}
+		var created int64
+		if len(parts) >= 3 {
+			fmt.Sscanf(parts[2], "%d", &created)
+		}
+		var lastAttached int64
+		if len(parts) >= 4 {
+			fmt.Sscanf(parts[3], "%d", &lastAttached)
+		}
+		var attached int
+		if len(parts) >= 5 {
+			fmt.Sscanf(parts[4], "%d", &attached)
+		}
+		var workDir string
+		if len(parts) >= 6 {
+			workDir = parts[5]
+		}
+		sessions = append(sessions, sessionInfo{name: name, activity: ts, created: created, lastAttached: lastAttached, attached: attached, workDir: workDir})
 	}
+	return sessions, nil
 }
 
-// pollTick fires on the configured interval to re-poll tmux.
+// pollTick fires after the next poll delay to re-poll tmux.
 func (m *Model) pollTick() tea.Cmd {
-	return tea.Tick(m.pollInterval, func(t time.Time) tea.Msg {
-		return pollMsg{}
+	return m.pollTickAfter(m.nextPollDelay())
+}
+
+// pollTickAfter schedules a poll after d, superseding any pending tick.
+func (m *Model) pollTickAfter(d time.Duration) tea.Cmd {
+	if d < 0 {
+		d = 0
+	}
+	m.pollSeq++
+	seq := m.pollSeq
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return pollMsg{seq: seq}
 	})
 }
 
@@ -550,6 +996,7 @@ func (m *Model) pollTick() tea.Cmd {
 func batchCapturePanes(sessions []sessionInfo) map[string][]string {
 	// Build a shell script that captures each pane with a delimiter.
 	// Delimiter format: ===PANE:sessionName===
+	// Multi-pane sessions capture their agent pane (panes.go), not the active one.
 	// Include the tmux socket flag so we target the town server, not the default.
 	socketFlag := ""
 	if sock := tmux.GetDefaultSocket(); sock != "" {
@@ -559,10 +1006,12 @@ func batchCapturePanes(sessions []sessionInfo) map[string][]string {
 	for _, s := range sessions {
 		// Session names are safe (alphanumeric + hyphens from our naming convention)
 		fmt.Fprintf(&script, "echo '===PANE:%s==='\n", s.name)
-		fmt.Fprintf(&script, "tmux%s capture-pane -t '%s' -p -S -10 2>/dev/null\n", socketFlag, s.name)
+		fmt.Fprintf(&script, "tmux%s capture-pane -t '%s' -p -S -%d 2>/dev/null\n", socketFlag, s.target(), s.depth())
 	}
 
-	cmd := exec.Command("sh", "-c", script.String())
+	ctx, cancel := context.WithTimeout(context.Background(), tmux.CommandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", script.String())
 	out, err := cmd.Output()
 	if err != nil {
 		return nil
@@ -582,7 +1031,7 @@ func batchCapturePanes(sessions []sessionInfo) map[string][]string {
 			currentSession = line[8 : len(line)-3]
 			currentLines = nil
 		} else if currentSession != "" {
-			currentLines = append(currentLines, line)
+			currentLines = append(currentLines, cleanPaneLine(line))
 		}
 	}
 	// Flush last session
@@ -597,24 +1046,149 @@ func batchCapturePanes(sessions []sessionInfo) map[string][]string {
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.wall != nil {
+			return m, m.handleWallKey(msg.String())
+		}
+		if m.noteInput(time.Now()) && msg.String() != "ctrl+c" {
+			return m, nil // the key only woke the screensaver
+		}
+		if m.pendingBulk != nil && msg.String() != "ctrl+c" {
+			return m, m.handleBulkKey(msg.String())
+		}
+		if m.tour != nil && msg.String() != "ctrl+c" {
+			m.handleTourKey(msg.String())
+			return m, nil
+		}
+		if m.probe != nil && msg.String() != "ctrl+c" {
+			m.probe = nil
+			return m, nil
+		}
+		if m.compare != nil && msg.String() != "ctrl+c" {
+			m.compare = nil
+			return m, nil
+		}
+		if m.switcher != nil && msg.String() != "ctrl+c" {
+			m.handleSwitcherKey(msg.String())
+			return m, nil
+		}
+		if m.inbox != nil && msg.String() != "ctrl+c" {
+			m.handleInboxKey(msg.String())
+			return m, nil
+		}
+		if m.macroMenu != nil && msg.String() != "ctrl+c" {
+			return m, m.handleMacroKey(msg.String())
+		}
+		if m.control != nil && msg.String() != "ctrl+c" {
+			m.handleControlKey(msg.String())
+			return m, nil
+		}
+		if m.filterBar != nil && msg.String() != "ctrl+c" {
+			m.handleFilterKey(msg.String())
+			return m, nil
+		}
+		if msg.String() == "esc" && m.keySelected {
+			m.clearSelection()
+			return m, nil
+		}
 		switch msg.String() {
 		case "q", "ctrl+c", "esc":
+			if m.embedded {
+				return m, nil // the host owns the program
+			}
 			return m, tea.Quit
-		}
+		case "up", "k":
+			m.moveSelection(-1)
+		case "down", "j":
+			m.moveSelection(1)
+		case "enter":
+			if m.hoveredAgent != nil {
+				m.doubleClick(m.hoveredAgent, false)
+			}
+		case "f":
+			m.togglePinHovered()
+		case "f5", "r":
+			return m, m.refreshHovered()
+		case "f1":
+			m.startTour()
+		case "N":
+			m.prepareBulk("nudge")
+		case "a":
+			m.acknowledgeHovered()
+		case "m":
+			m.cycleMuteHovered()
+		case "H":
+			m.toggleColorBy()
+		case "$":
+			return m, m.probeHovered("/cost")
+		case "s":
+			return m, m.probeHovered("/status")
+		case "S":
+			m.prepareStart()
+		case "A":
+			m.prepareBulk("acknowledge")
+		case "K":
+			m.prepareBulk("kill")
+		case "R":
+			m.prepareRestart()
+		case "g":
+			m.toggleGroupByConvoy()
+		case "b":
+			m.toggleBurndownHovered()
+		case "v":
+			m.toggleCompareHovered()
+		case "ctrl+f":
+			m.openSwitcher()
+		case "/":
+			m.openFilterBar()
+		case "i":
+			m.openInbox()
+		case "M":
+			m.openMacroMenu()
+		case "T":
+			m.openControl()
+		case "y":
+			return m, m.copyHovered("session name")
+		case "Y":
+			return m, m.copyHovered("attach command")
+		case "c":
+			return m, m.copyHovered("bead ID")
+		}
+
+	case bulkDoneMsg:
+		return m, m.handleBulkDone(msg)
+
+	case copiedMsg:
+		m.handleCopied(msg)
+
+	case alertSentMsg:
+		m.handleAlertSent(msg)
+
+	case probeDoneMsg:
+		m.handleProbeDone(msg)
+
+	case macroDoneMsg:
+		m.handleMacroDone(msg)
 
 	case tea.MouseMsg:
+		if m.noteInput(time.Now()) {
+			return m, nil
+		}
 		m.mouseX = msg.X
 		m.mouseY = msg.Y
 		m.updateHoveredAgent()
 
 		// Double-click detection: two left-button presses on the same agent within 500ms.
 		if msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress {
-			clickedAgent := m.agentAtY(msg.Y)
+			if a := m.minimapAgentAt(msg.X, msg.Y); a != nil {
+				m.jumpTo(a)
+				break
+			}
+			clickedAgent := m.agentAt(msg.X, msg.Y)
 			if clickedAgent != nil && clickedAgent == m.lastClickAgent &&
 				time.Since(m.lastClickTime) < 500*time.Millisecond {
-				// Double-click detected — launch terminal attached to this session
+				// Double-click detected — attach, or open the role's view
 				m.lastClickAgent = nil // reset to avoid triple-click
-				m.openTerminalWithTmuxAttach(clickedAgent.SessionName)
+				m.doubleClick(clickedAgent, msg.Alt)
 			} else {
 				m.lastClickAgent = clickedAgent
 				m.lastClickTime = time.Now()
@@ -626,27 +1200,114 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 
 	case sessionsMsg:
+		m.noteSources(msg.sources, time.Now())
+		if m.noteTmuxStall(msg.err, time.Now()) {
+			m.polling = false
+			return m, m.pollTick()
+		}
+		start := time.Now()
 		m.updateAgents(msg.sessions)
-		m.blinkOn = !m.blinkOn
-		m.tickNum++
-		return m, m.pollTick()
+		m.refreshControl()
+		m.pruneSelection()
+		m.trackHumanTouches(msg.sessions)
+		m.applyRestoredHover()
+		m.recordFrame(msg.elapsed + time.Since(start) + m.frames.render)
+		m.polling = false
+		if m.idleExpired(time.Now()) {
+			return m, tea.Quit
+		}
+		return m, tea.Batch(m.pollTick(), m.escalateAlerts(time.Now()))
+
+	case wakeMsg:
+		return m, m.handleWake(msg)
+
+	case tickerMsg:
+		return m, m.scrollTicker()
+
+	case animateMsg:
+		return m, m.animate()
+
+	case wallRotateMsg:
+		return m, m.rotateWall(msg)
 
 	case pollMsg:
+		if msg.seq != m.pollSeq || m.polling {
+			return m, nil // superseded by a wake-triggered tick
+		}
 		// Periodically refresh the prefix registry to detect newly added rigs.
 		// Without this, rigs added after gt top starts would be invisible
 		// because IsKnownSession would not recognize their session prefixes.
 		if m.townRoot != "" && time.Since(m.lastRegistryRefresh) >= registryRefreshInterval {
 			m.refreshRegistry()
 		}
+		m.polling = true
+		m.lastPoll = time.Now()
 		return m, m.pollSessions()
 	}
 
 	return m, nil
 }
 
+// resetSticky clears every field carried over between polls: parsed pane
+// state, work context, health flags, and loop history. Used when a session
+// restarts and when the operator force-refreshes an agent. AgentType is
+// cleared too, so the type is re-detected from the pane. The limit last
+// reported is kept: reportLimits re-arms once the agent is no longer limited,
+// so a restart that finds the same limit doesn't report it again.
+func (a *AgentLight) resetSticky() {
+	a.ContextPercent = 0
+	a.TokenCount = 0
+	a.CurrentTask = ""
+	a.SessionLimitPct = 0
+	a.SessionLimitReset = ""
+	a.IsCompacting = false
+	a.PreCompactCtxPct = 0
+	a.PrevStatusText = ""
+	a.CurrentTool = ""
+	a.StatusText = ""
+	a.LastPatrol = ""
+	a.WorkBeadID = ""
+	a.WorkBeadTitle = ""
+	a.ConvoyID = ""
+	a.EpicID = ""
+	a.FormulaName = ""
+	a.StepCurrent = ""
+	a.StepsDone = 0
+	a.StepsTotal = 0
+	a.AgentState = ""
+	a.AgentType = "" // force re-detection
+	a.AgentVersion = ""
+	a.chrome = nil
+	a.HitLimit = false
+	a.LimitResetInfo = ""
+	a.RateLimited = false
+	a.APIError = false
+	a.WaitingForHuman = false
+	a.WaitingReason = ""
+	a.readyAt = time.Time{}
+	a.workAt = time.Now()       // idle events from the old session don't count
+	a.clearProgress(time.Now()) // nor progress the old session reported
+	a.RecentOutput = ""
+	a.PushedBranch = ""
+	a.PRNumber = 0
+	a.ToolErrorCount = 0
+	a.LastToolError = ""
+	a.lastToolResultSig = ""
+	a.lastToolResultRun = 0
+	a.Looping = false
+	a.LoopTool = ""
+	a.LoopCount = 0
+	a.toolStarts = nil
+	a.prevTool = ""
+	a.failingSince = time.Time{}
+	a.contextSeen = time.Time{}
+	a.sessionLimitSeen = time.Time{}
+}
+
 // updateAgents merges new session data into the agent lights.
 func (m *Model) updateAgents(sessions []sessionInfo) {
 	now := time.Now()
+	m.mutes = LoadMutes(m.townRoot)
 
 	// Build lookup from current agents
 	existing := make(map[string]*AgentLight)
@@ -662,7 +1323,13 @@ func (m *Model) updateAgents(sessions []sessionInfo) {
 		agent, ok := existing[s.name]
 		if !ok {
 			// New agent — detect agent type from tmux environment (one-time read)
-			agentType := detectAgentType(s.name)
+			agentType := s.agentType
+			switch {
+			case !s.containerized():
+				agentType = detectAgentType(s.name)
+			case agentType == "":
+				agentType = detectAgentTypeFromPane(s.paneLines)
+			}
 			agent = &AgentLight{
 				SessionName:    s.name,
 				AgentType:      agentType,
@@ -674,14 +1341,25 @@ func (m *Model) updateAgents(sessions []sessionInfo) {
 				agent.SessionCreated = time.Unix(s.created, 0)
 			}
 			parseSessionName(agent)
+			resolveAgentID(agent)
 			m.agents = append(m.agents, agent)
 			existing[s.name] = agent
+			if m.activityFromPane {
+				agent.notePaneActivity(s, now)
+			} else {
+				agent.attachOnly(s)
+			}
 		} else {
 			// Update existing
-			agent.PrevActivity = agent.CurActivity
-			agent.CurActivity = s.activity
-			if agent.CurActivity != agent.PrevActivity {
-				agent.LastChangeTime = now
+			if m.activityFromPane {
+				agent.notePaneActivity(s, now)
+			} else {
+				attachOnly := agent.attachOnly(s)
+				agent.PrevActivity = agent.CurActivity
+				agent.CurActivity = s.activity
+				if agent.CurActivity != agent.PrevActivity && !attachOnly {
+					agent.LastChangeTime = now
+				}
 			}
 			// Update created time if session was restarted (new created timestamp)
 			if s.created > 0 {
@@ -689,33 +1367,20 @@ func (m *Model) updateAgents(sessions []sessionInfo) {
 				if !newCreated.Equal(agent.SessionCreated) {
 					agent.SessionCreated = newCreated
 					// Reset all sticky fields from the previous session
-					agent.ContextPercent = 0
-					agent.TokenCount = 0
-					agent.SessionLimitPct = 0
-					agent.SessionLimitReset = ""
-					agent.IsCompacting = false
-					agent.PreCompactCtxPct = 0
-					agent.PrevStatusText = ""
-					agent.CurrentTool = ""
-					agent.StatusText = ""
-					agent.LastPatrol = ""
-					agent.WorkBeadID = ""
-					agent.WorkBeadTitle = ""
-					agent.FormulaName = ""
-					agent.StepCurrent = ""
-					agent.StepsDone = 0
-					agent.StepsTotal = 0
-					agent.AgentState = ""
-					agent.AgentType = "" // force re-detection
-					agent.HitLimit = false
-					agent.LimitResetInfo = ""
-					agent.RateLimited = false
-					agent.WaitingForHuman = false
-					agent.WaitingReason = ""
-					agent.RecentOutput = ""
+					agent.resetSticky()
+					if m.transcripts != nil {
+						m.transcripts.forget(agent.SessionName)
+					}
 				}
 			}
 		}
+		agent.Panes, agent.PaneID, agent.PaneReason = s.panes, s.pane, s.paneReason
+		agent.Container, agent.Pod = s.container, s.pod
+		m.trackRepo(agent, s.workDir)
+		agent.CaptureStaleSince = time.Time{}
+		if s.captureStale {
+			agent.CaptureStaleSince = s.staleSince
+		}
 	}
 
 	// Remove dead agents (not seen in this poll)
@@ -723,14 +1388,27 @@ func (m *Model) updateAgents(sessions []sessionInfo) {
 	for _, a := range m.agents {
 		if seen[a.SessionName] {
 			filtered = append(filtered, a)
+		} else if m.transcripts != nil {
+			m.transcripts.forget(a.SessionName)
 		}
 	}
 	m.agents = filtered
 
 	// Build pane content lookup from session data
 	paneMap := make(map[string][]string)
+	apiMap := make(map[string]*openCodeStatus)
+	hbMap := make(map[string]*polecat.SessionHeartbeat)
 	for _, s := range sessions {
-		paneMap[s.name] = s.paneLines
+		// A stale session keeps what was parsed from its last good capture.
+		if !s.captureStale {
+			paneMap[s.name] = s.paneLines
+		}
+		if s.openCode != nil {
+			apiMap[s.name] = s.openCode
+		}
+		if s.heartbeat != nil {
+			hbMap[s.name] = s.heartbeat
+		}
 	}
 
 	// Update activity levels and stats
@@ -741,12 +1419,43 @@ func (m *Model) updateAgents(sessions []sessionInfo) {
 	m.rateLimitedCount = 0
 	m.hitLimitCount = 0
 	m.waitingCount = 0
+	m.startingCount = 0
+	m.readyCount = 0
+
+	// Idle events set the ready level below, so read the events file first.
+	m.readEvents(now)
+	m.readRecentToolEvents(now)
+	m.learnSessionMoves()
+	m.trackReadyEvents()
+	// Restarts drop sticky state, so apply them before this poll's parse.
+	if m.firstPoll.IsZero() {
+		m.firstPoll = now.Truncate(time.Second) // event timestamps are to the second
+	}
+	m.applyRestartEvents()
 
 	for _, a := range m.agents {
 		// Parse pane content for status info
 		if lines, ok := paneMap[a.SessionName]; ok {
+			m.selectChrome(a, lines)
 			parsePaneContent(a, lines)
+			if m.transcripts != nil && isClaudeAgent(a.AgentType) {
+				m.transcripts.apply(a)
+			}
+			a.apiLive = false
+			if st := apiMap[a.SessionName]; st != nil {
+				applyOpenCodeStatus(a, st)
+			}
+			detectStartup(a, lines, now)
+			if hb := hbMap[a.SessionName]; hb != nil {
+				applyHeartbeat(a, hb)
+			}
+			if m.parserGapCheck {
+				m.checkParserGap(a, lines, now)
+			}
 		}
+		m.expireSticky(a, now)
+		a.limitReset.update(a.LimitResetInfo, now)
+		a.sessionReset.update(a.SessionLimitReset, now)
 
 		sinceLast := now.Sub(a.LastChangeTime)
 
@@ -771,6 +1480,22 @@ func (m *Model) updateAgents(sessions []sessionInfo) {
 			continue
 		}
 
+		// Booting agents get their own level instead of flapping between
+		// active (startup output) and cold (waiting on MCP servers).
+		if a.StartupPhase != "" {
+			a.Level = LevelStarting
+			m.startingCount++
+			continue
+		}
+
+		// An agent whose plugin said it's idle at its prompt is ready for
+		// work, however recently its TUI redrew.
+		if m.readyForWork(a, now) {
+			a.Level = LevelReady
+			m.readyCount++
+			continue
+		}
+
 		switch {
 		case sinceLast < 3*time.Second:
 			a.Level = LevelActive
@@ -801,13 +1526,24 @@ func (m *Model) updateAgents(sessions []sessionInfo) {
 			m.rateLimitedCount++
 		}
 	}
+	m.applySchedules(now)
+	m.trackNudges(now)
+	m.trackMergeQueue(now)
+	m.trackBeadsClosed(now)
+	m.sampleRigHistory(now)
 	m.totalAgents = len(m.agents)
+	m.trackSpend(now)
+	m.updateScore(now)
+	m.detectOutages(now)
+	m.reportLimits()
 
 	// Apply plugin-emitted tool events for non-Claude agents.
 	// This populates CurrentTool from events written by gastown.js plugin
 	// hooks (tool.execute.before/after), sidestepping pane parsing.
-	m.readRecentToolEvents()
 	m.applyToolEvents()
+	m.applyProgressEvents(now)
+	m.readRecentComms()
+	m.readTicker(now)
 
 	// Apply compaction override AFTER both pane-scraping and event processing.
 	// IsCompacting may have been set by parsePaneContentOpenCode (pane-based)
@@ -821,11 +1557,47 @@ func (m *Model) updateAgents(sessions []sessionInfo) {
 		}
 	}
 
+	// Retry-loop detection runs on the final CurrentTool for this poll.
+	m.detectLoops(now)
+	m.loopingCount = 0
+	for _, a := range m.agents {
+		if a.Looping {
+			m.loopingCount++
+		}
+	}
+
 	// Poll beads DB for work assignments (slower cadence, guarded internally)
 	m.pollBeadsWork()
 
+	// Re-evaluate rig SLAs (slower cadence, guarded internally)
+	m.pollRigSLAs(now)
+
+	// Re-read deacon heartbeat and scheduler backlog (slower cadence, guarded internally)
+	m.pollHQStatus(now)
+
+	// Re-read the burn-down convoy's history (slower cadence, guarded internally)
+	m.pollBurndown(now)
+
+	// Placeholders for expected roles whose sessions are gone
+	m.updatePlaceholders()
+
 	// Rebuild rig ordering
 	m.rebuildRigOrder()
+
+	// Publish levels for witness patrols and gt agents --stale
+	m.writeStatus(now)
+
+	// Record status changes for gt forensics
+	m.recordLedger(now)
+
+	// Rewrite the Markdown/org status report, when enabled
+	m.writeReport(now)
+
+	// Feed the web, metrics, JSON and events sinks
+	m.publishSinks(now)
+
+	// The reporter's first poll only recorded the health states it found.
+	m.reporting = m.reportAs != ""
 }
 
 // parseSessionName extracts role/rig/name from a session name using the
@@ -929,6 +1701,9 @@ func (m *Model) rebuildRigOrder() {
 			rigSet[a.Rig] = true
 		}
 	}
+	for _, a := range m.placeholders {
+		rigSet[a.Rig] = true
+	}
 
 	m.rigs = nil
 	if rigSet["hq"] {
@@ -944,21 +1719,22 @@ func (m *Model) rebuildRigOrder() {
 	m.rigs = append(m.rigs, others...)
 }
 
+// roleOrder is the order of roles within a rig panel.
+var roleOrder = map[string]int{
+	constants.RoleMayor:    0,
+	constants.RoleDeacon:   1,
+	constants.RoleDog:      2,
+	constants.RoleWitness:  3,
+	constants.RoleRefinery: 4,
+	constants.RoleCrew:     5,
+	constants.RolePolecat:  6,
+}
+
 // agentsForRig returns agents belonging to a rig in display order.
 func (m *Model) agentsForRig(rig string) []*AgentLight {
-	roleOrder := map[string]int{
-		constants.RoleMayor:    0,
-		constants.RoleDeacon:   1,
-		constants.RoleDog:      2,
-		constants.RoleWitness:  3,
-		constants.RoleRefinery: 4,
-		constants.RoleCrew:     5,
-		constants.RolePolecat:  6,
-	}
-
 	var agents []*AgentLight
 	for _, a := range m.agents {
-		if a.Rig == rig {
+		if a.Rig == rig && m.view.matches(a) {
 			agents = append(agents, a)
 		}
 	}
@@ -977,33 +1753,11 @@ func (m *Model) agentsForRig(rig string) []*AgentLight {
 }
 
 // isChromeLine returns true if the line is Claude Code TUI chrome that should
-// be ignored when extracting agent status. These elements are always present
-// at the bottom of a Claude Code session and carry no status information.
+// be ignored when extracting agent status, per the built-in chrome for the
+// newest Claude Code (see chrome.go). Parsers with an agent at hand use
+// chromeOf(a) instead, which follows the agent's detected version.
 func isChromeLine(line string) bool {
-	trimmed := strings.TrimSpace(line)
-	if trimmed == "" {
-		return true
-	}
-	// Claude Code input prompt (always visible at bottom, not a status signal)
-	if strings.HasPrefix(trimmed, "❯") {
-		return true
-	}
-	// Claude Code bottom status bar indicators
-	if strings.Contains(trimmed, "⏵") {
-		return true
-	}
-	// Status bar text (bypass permissions, auto-accept, etc.)
-	if strings.Contains(trimmed, "bypass permissions") ||
-		strings.Contains(trimmed, "shift+tab to cycle") ||
-		strings.Contains(trimmed, "esc to interrupt") ||
-		strings.Contains(trimmed, "auto-accept") {
-		return true
-	}
-	// Separator lines (all box-drawing horizontal characters)
-	if isSeparatorLine(trimmed) {
-		return true
-	}
-	return false
+	return lookupChrome(builtinChrome, "claude", "").isChrome(line)
 }
 
 // isSeparatorLine checks if a string is entirely horizontal line characters.
@@ -1019,68 +1773,19 @@ func isSeparatorLine(s string) bool {
 	return true
 }
 
-// extractTaskName pulls the conversation/task name from a Claude Code status bar line.
-// Format: "⏵⏵ bypass permissions on · Implement CWE enricher source (running) · esc to interrupt"
-// Returns: "Implement CWE enricher source"
-// Uses text anchors (not symbol chars) since tmux capture may render ⏵ as different codepoints.
+// extractTaskName pulls the conversation/task name from a Claude Code status
+// bar line using the built-in chrome for the newest Claude Code.
 func extractTaskName(line string) string {
-	trimmed := strings.TrimSpace(line)
-
-	// The status bar always ends with "esc to interrupt"
-	escIdx := strings.Index(trimmed, "esc to interrupt")
-	if escIdx < 0 {
-		return ""
-	}
-
-	// Find where the permission mode segment ends
-	var permEndIdx int
-	found := false
-	for _, marker := range []string{
-		"bypass permissions on",
-		"auto-accept edits on",
-		"auto-accept all on",
-	} {
-		if idx := strings.Index(trimmed, marker); idx >= 0 {
-			permEndIdx = idx + len(marker)
-			found = true
-			break
-		}
-	}
-	if !found {
-		return ""
-	}
-
-	if permEndIdx >= escIdx {
-		return ""
-	}
-
-	// Everything between permission mode and "esc to interrupt" is the task name
-	middle := trimmed[permEndIdx:escIdx]
-
-	// Skip the "(shift+tab to cycle)" format which means no task name
-	if strings.Contains(middle, "shift+tab") {
-		return ""
-	}
-
-	// Strip separator chars from both ends (·, •, ∙, dashes, whitespace)
-	middle = strings.Trim(middle, " \t·•∙‧⋅─━—-|/")
-	if middle == "" {
-		return ""
-	}
-
-	// Strip redundant "(running)" suffix - the LED bar already shows activity
-	middle = strings.TrimSuffix(middle, "(running)")
-	middle = strings.TrimSpace(middle)
-
-	return middle
+	return lookupChrome(builtinChrome, "claude", "").taskName(line)
 }
 
 // parsePaneContent analyzes captured pane lines to extract status information.
-// Lines are ordered top-to-bottom (time flows downward). For Claude Code sessions,
-// we strip UI chrome from the bottom, then scan upward from the most recent real
+// Lines are ordered top-to-bottom (time flows downward). The agent type's
+// pane parser does the reading (parsers.go): for Claude Code sessions, we
+// strip UI chrome from the bottom, then scan upward from the most recent real
 // content to find status signals. For OpenCode agents, we parse their distinctive
-// TUI patterns (▣ working indicator, ✱ tools, context %). For other non-Claude
-// agents, we use a generic parser.
+// TUI patterns (▣ working indicator, ✱ tools, context %). Agents of a type
+// with no parser get the Claude parser.
 func parsePaneContent(a *AgentLight, lines []string) {
 	// Lazy agent type detection from pane content.
 	// GT_AGENT is rarely set in tmux env — detect from TUI signatures instead.
@@ -1089,14 +1794,17 @@ func parsePaneContent(a *AgentLight, lines []string) {
 		a.AgentType = detectAgentTypeFromPane(lines)
 	}
 
+	// Pushes and PRs look the same in every agent's pane (pushes.go).
+	trackPushes(a, lines)
+
 	// Dispatch to agent-specific parser.
-	switch a.AgentType {
-	case "opencode":
-		parsePaneContentOpenCode(a, lines)
-	default:
-		// Claude Code or unknown agents use the Claude parser.
-		parsePaneContentClaude(a, lines)
+	a.toolFromPane = false
+	p := paneParser(a.AgentType)
+	if lp, ok := p.(lightParser); ok {
+		lp.parseLight(a, lines)
+		return
 	}
+	applyPaneStatus(a, p.Parse(lines))
 }
 
 // parsePaneContentClaude is the pane parser for Claude Code sessions.
@@ -1107,21 +1815,27 @@ func parsePaneContentClaude(a *AgentLight, lines []string) {
 	a.WaitingForHuman = false
 	a.WaitingReason = ""
 	a.RateLimited = false
+	a.APIError = false
 	a.HitLimit = false
 	a.LimitResetInfo = ""
 	a.CurrentTool = "" // Reset each poll - stale tools cause false display
-	// ContextPercent persists until updated (sticky)
-	// SessionLimitPct and SessionLimitReset persist until updated (sticky)
+	// ContextPercent persists until updated or expired (sticky, see sticky.go)
+	// SessionLimitPct and SessionLimitReset persist until updated or expired (sticky)
 
 	if len(lines) == 0 {
 		return
 	}
 
+	// Recent tool failures (sticky until a successful result replaces them)
+	trackToolErrors(a, lines)
+
+	chrome := chromeOf(a)
+
 	// Extract task name from Claude Code status bar (before chrome filtering,
 	// since the status bar IS chrome but contains the task name)
 	taskName := ""
 	for i := len(lines) - 1; i >= 0; i-- {
-		if tn := extractTaskName(lines[i]); tn != "" {
+		if tn := chrome.taskName(lines[i]); tn != "" {
 			taskName = tn
 			break
 		}
@@ -1157,17 +1871,14 @@ func parsePaneContentClaude(a *AgentLight, lines []string) {
 		// Match on "of your session limit" to avoid curly apostrophe issue with "you've"
 		if strings.Contains(lower, "of your session limit") || strings.Contains(lower, "% of your session") {
 			if pct, reset := extractSessionLimit(trimmed); pct > 0 {
-				a.SessionLimitPct = pct
-				if reset != "" {
-					a.SessionLimitReset = reset
-				}
+				a.observeSessionLimit(pct, reset)
 			}
 		}
 
 		// Context percentage: "Context left until auto-compact: 20%"
 		if strings.Contains(lower, "context left until auto-compact:") {
 			if pct := extractContextPercent(trimmed); pct > 0 {
-				a.ContextPercent = pct
+				a.observeContext(pct)
 			}
 		}
 
@@ -1194,6 +1905,9 @@ func parsePaneContentClaude(a *AgentLight, lines []string) {
 				a.RateLimited = true
 				break
 			}
+			if isAPIErrorLine(lower) {
+				a.APIError = true
+			}
 		}
 	}
 
@@ -1202,7 +1916,7 @@ func parsePaneContentClaude(a *AgentLight, lines []string) {
 	// false positives from stale output higher in the pane.
 	contentChecked := 0
 	for i := len(lines) - 1; i >= 0 && contentChecked < 8; i-- {
-		if isChromeLine(lines[i]) {
+		if chrome.isChrome(lines[i]) {
 			continue
 		}
 		trimmed := strings.TrimSpace(lines[i])
@@ -1339,9 +2053,9 @@ func extractAndStripSidebar(lines []string) ([]string, sidebarInfo) {
 	// when lines have short main content followed by long gaps — the
 	// sidebar boundary is where the sidebar text begins, not where the
 	// preceding whitespace begins.
-	sidebarCol := 0
+	sidebarCol := 0 // display column, not byte offset
 	for _, line := range lines {
-		if len(line) < 100 {
+		if ansi.StringWidth(line) < 100 {
 			continue
 		}
 		// Find gaps of 8+ spaces and check what follows
@@ -1365,7 +2079,9 @@ func extractAndStripSidebar(lines []string) ([]string, sidebarInfo) {
 					// Use the marker position (j) as the sidebar boundary,
 					// with a small buffer for visual separation. Subtract 2
 					// to trim the trailing whitespace before the marker.
-					markerCol := j - 2
+					// Wide characters in the main content shift the
+					// marker's byte offset, so measure its column.
+					markerCol := ansi.StringWidth(line[:j]) - 2
 					if markerCol < 0 {
 						markerCol = 0
 					}
@@ -1396,11 +2112,12 @@ func extractAndStripSidebar(lines []string) ([]string, sidebarInfo) {
 	currentAccum := accumNone
 
 	for _, line := range lines {
-		if len(line) <= sidebarCol {
+		cut := columnOffset(line, sidebarCol)
+		if cut >= len(line) {
 			currentAccum = accumNone
 			continue
 		}
-		sidebarText := strings.TrimSpace(line[sidebarCol:])
+		sidebarText := strings.TrimSpace(line[cut:])
 		if sidebarText == "" {
 			currentAccum = accumNone
 			continue
@@ -1465,8 +2182,8 @@ func extractAndStripSidebar(lines []string) ([]string, sidebarInfo) {
 	// Truncate all lines at the sidebar column and trim trailing whitespace.
 	result := make([]string, len(lines))
 	for i, line := range lines {
-		if len(line) > sidebarCol {
-			result[i] = strings.TrimRight(line[:sidebarCol], " ")
+		if cut := columnOffset(line, sidebarCol); cut < len(line) {
+			result[i] = strings.TrimRight(line[:cut], " ")
 		} else {
 			result[i] = line
 		}
@@ -1513,6 +2230,7 @@ func parsePaneContentOpenCode(a *AgentLight, lines []string) {
 	a.WaitingForHuman = false
 	a.WaitingReason = ""
 	a.RateLimited = false
+	a.APIError = false
 	a.HitLimit = false
 	a.LimitResetInfo = ""
 	// CurrentTool is NOT reset here — it's owned by applyToolEvents().
@@ -1521,6 +2239,7 @@ func parsePaneContentOpenCode(a *AgentLight, lines []string) {
 	if len(lines) == 0 {
 		return
 	}
+	chrome := chromeOf(a)
 
 	// Extract useful data from the sidebar AND strip it before parsing.
 	// On wide panes (>120 cols), OpenCode renders a ~42-column sidebar with
@@ -1531,7 +2250,7 @@ func parsePaneContentOpenCode(a *AgentLight, lines []string) {
 	lines, sidebar = extractAndStripSidebar(lines)
 
 	if sidebar.contextPercent > 0 {
-		a.ContextPercent = sidebar.contextPercent
+		a.observeContext(sidebar.contextPercent)
 	}
 	if sidebar.tokenCount > 0 {
 		a.TokenCount = sidebar.tokenCount
@@ -1566,13 +2285,13 @@ func parsePaneContentOpenCode(a *AgentLight, lines []string) {
 		lower := strings.ToLower(trimmed)
 
 		// ── "esc interrupt" means the agent is actively streaming/working ──
-		// Detect BEFORE the chrome filter since isOpenCodeChromeLine skips it.
-		if strings.Contains(trimmed, "esc interrupt") {
+		// Detect BEFORE the chrome filter, which skips it.
+		if chrome.interrupt != "" && strings.Contains(trimmed, chrome.interrupt) {
 			agentIsStreaming = true
 		}
 
 		// Skip empty lines and pure box-drawing chrome.
-		if trimmed == "" || isOpenCodeChromeLine(trimmed) {
+		if chrome.isChrome(trimmed) {
 			continue
 		}
 
@@ -1625,7 +2344,7 @@ func parsePaneContentOpenCode(a *AgentLight, lines []string) {
 
 		// ── Context/token info in header line: "40,140  31% ($0.00)" ──
 		if pct := extractOpenCodeContextPercent(trimmed); pct > 0 {
-			a.ContextPercent = pct
+			a.observeContext(pct)
 		}
 		if tc := extractOpenCodeHeaderTokenCount(trimmed); tc > 0 {
 			a.TokenCount = tc
@@ -1695,6 +2414,9 @@ func parsePaneContentOpenCode(a *AgentLight, lines []string) {
 		if strings.Contains(lower, "retrying in") && strings.Contains(lower, "attempt") {
 			a.RateLimited = true
 		}
+		if isAPIErrorLine(lower) {
+			a.APIError = true
+		}
 	}
 
 	// ── Scan bottom-to-top for active tool panels ──
@@ -1707,6 +2429,8 @@ func parsePaneContentOpenCode(a *AgentLight, lines []string) {
 	// Completed panels use ┃ frames with # headers — we skip those.
 	activeToolPanel = extractActiveToolPanel(lines)
 
+	a.busyInPane = agentIsStreaming || activeToolPanel != "" || pendingOp != ""
+
 	// If the agent is actively streaming or has an active tool panel,
 	// any stale billing error from scrollback is superseded — agent recovered.
 	if sawBillingError && (agentIsStreaming || activeToolPanel != "") {
@@ -1798,34 +2522,9 @@ func parsePaneContentOpenCode(a *AgentLight, lines []string) {
 }
 
 // isOpenCodeChromeLine returns true if the line is OpenCode TUI chrome that
-// should be ignored when extracting status. These are always-present UI
-// elements that carry no status information.
+// should be ignored, per the built-in chrome for the newest OpenCode.
 func isOpenCodeChromeLine(line string) bool {
-	trimmed := strings.TrimSpace(line)
-	if trimmed == "" {
-		return true
-	}
-	// Pure box-drawing: lines that are only ┃, ╹, ▀, spaces
-	if isBoxDrawingOnly(trimmed) {
-		return true
-	}
-	// Bottom status bar: "esc interrupt" with optional progress dots
-	if strings.Contains(trimmed, "esc interrupt") {
-		return true
-	}
-	// Bottom bar: "ctrl+t variants  tab agents  ctrl+p commands"
-	if strings.Contains(trimmed, "ctrl+p commands") {
-		return true
-	}
-	// Model info line: "Build  Claude Opus 4.6 GitHub Copilot"
-	if strings.HasPrefix(trimmed, "Build ") && strings.Contains(trimmed, "Copilot") {
-		return true
-	}
-	// Separator lines
-	if isSeparatorLine(trimmed) {
-		return true
-	}
-	return false
+	return lookupChrome(builtinChrome, "opencode", "").isChrome(line)
 }
 
 // isBoxDrawingOnly returns true if the string contains only box-drawing
@@ -2329,34 +3028,56 @@ func extractCurrentTool(line string) string {
 // truncateStatus does a light safety trim. Real truncation happens in
 // renderLight based on actual terminal width.
 func truncateStatus(s string) string {
-	if len(s) > 200 {
-		return s[:197] + "..."
-	}
-	return s
+	return truncateWidth(s, 200, "...")
 }
 
 // updateHoveredAgent determines which agent (if any) the mouse is hovering over.
 func (m *Model) updateHoveredAgent() {
 	m.hoveredAgent = nil
+	m.keySelected = false
 	for _, a := range m.agents {
-		if a.renderY > 0 && m.mouseY >= a.renderY && m.mouseY < a.renderY+a.renderHeight {
+		if a.containsPoint(m.mouseX, m.mouseY) {
 			m.hoveredAgent = a
 			m.fetchAgentDetails(a)
-			break
+			return
 		}
 	}
+	if a := m.minimapAgentAt(m.mouseX, m.mouseY); a != nil {
+		m.hoveredAgent = a
+		m.fetchAgentDetails(a)
+	}
 }
 
-// agentAtY returns the agent at the given Y coordinate, or nil.
-func (m *Model) agentAtY(y int) *AgentLight {
+// agentAt returns the agent at the given screen cell, or nil.
+func (m *Model) agentAt(x, y int) *AgentLight {
 	for _, a := range m.agents {
-		if a.renderY > 0 && y >= a.renderY && y < a.renderY+a.renderHeight {
+		if a.containsPoint(x, y) {
+			return a
+		}
+	}
+	for _, a := range m.placeholders {
+		if a.containsPoint(x, y) {
 			return a
 		}
 	}
 	return nil
 }
 
+// attachTo opens a terminal attached to the agent's session (or container).
+func (m *Model) attachTo(a *AgentLight) {
+	if a.placeholder {
+		m.flashMessage = a.SessionName + " isn't running; press s to start it"
+		m.flashTime = time.Now()
+		return
+	}
+	m.touchedByHuman(a.SessionName)
+	if cmd := m.containerAttachCommand(a); cmd != "" {
+		m.openTerminal(cmd, a.SessionName)
+	} else {
+		m.openTerminalWithTmuxAttach(a.SessionName)
+	}
+}
+
 // openTerminalWithTmuxAttach launches a new terminal window/tab running
 // "tmux attach -t <session>". On macOS, it tries iTerm2 first (AppleScript),
 // then falls back to Terminal.app. The command is run in the background so
@@ -2375,7 +3096,11 @@ func (m *Model) openTerminalWithTmuxAttach(sessionName string) {
 		socketArgs = fmt.Sprintf(" -L %s", sock)
 	}
 	attachCmd := fmt.Sprintf("%s%s attach -t %s", tmuxPath, socketArgs, sessionName)
+	m.openTerminal(attachCmd, sessionName)
+}
 
+// openTerminal launches a new terminal window/tab running attachCmd.
+func (m *Model) openTerminal(attachCmd, sessionName string) {
 	// Try iTerm2 first (very common on macOS for dev)
 	// Request 192x60 so the agent TUI (especially OpenCode's sidebar) renders fully.
 	iterm := exec.Command("osascript", "-e", fmt.Sprintf(
@@ -2460,11 +3185,25 @@ func (m *Model) pollBeadsWork() {
 		agentBySession[a.SessionName] = a
 	}
 
+	// Convoys and epics the hooked beads belong to (see convoy.go)
+	groups := make(map[string]*workGroup)
+
 	// Query each rig's beads DB for agent beads
+	var failed int
+	var firstErr error
+	defer func() {
+		if failed > 0 {
+			firstErr = fmt.Errorf("%d of %d rigs unreadable: %w", failed, len(m.rigBeadsDirs), firstErr)
+		}
+		m.noteSource("beads", firstErr, time.Now())
+	}()
 	for _, beadsDir := range m.rigBeadsDirs {
 		b := beads.New(beadsDir)
 		agentBeads, err := b.ListAgentBeads()
 		if err != nil {
+			if failed++; firstErr == nil {
+				firstErr = err
+			}
 			continue
 		}
 
@@ -2559,9 +3298,11 @@ func (m *Model) pollBeadsWork() {
 			moleculeID string
 		}
 		var molQueries []molQuery
+		parents := make(map[*AgentLight]string) // hook bead parents, possibly epics
 
 		for _, match := range matches {
 			agent := match.agent
+			agent.ConvoyID, agent.EpicID = "", ""
 
 			if match.activeMR != "" {
 				// Refinery with ActiveMR
@@ -2607,8 +3348,14 @@ func (m *Model) pollBeadsWork() {
 
 			agent.WorkBeadID = match.hookBeadID
 			agent.WorkBeadTitle = hookBead.Title
+			if hookBead.Parent != "" {
+				parents[agent] = hookBead.Parent
+			}
 
 			attachment := beads.ParseAttachmentFields(hookBead)
+			if attachment != nil {
+				agent.ConvoyID = attachment.ConvoyID
+			}
 			if attachment == nil {
 				agent.FormulaName = ""
 				agent.StepCurrent = ""
@@ -2634,7 +3381,13 @@ func (m *Model) pollBeadsWork() {
 		for _, mq := range molQueries {
 			m.fetchMoleculeProgress(b, mq.agent, mq.moleculeID)
 		}
+
+		if len(parents) > 0 {
+			m.resolveEpics(b, parents, groups)
+		}
 	}
+	m.resolveConvoys(groups)
+	m.groups = groups
 
 	// ── Pass 4: Populate patrol summaries for hq agents without agent beads ──
 	// The deacon is a town-level agent whose patrol wisps live in the hq beads
@@ -2998,9 +3751,8 @@ func deriveSessionNameFromComponents(prefix, role, name string) string {
 
 // fetchAgentDetails fetches additional info for hover tooltip.
 func (m *Model) fetchAgentDetails(a *AgentLight) {
-	// Capture last 20 lines to extract bead IDs and recent activity
-	cmd := tmux.BuildCommand("capture-pane", "-t", a.SessionName, "-p", "-S", "-20")
-	out, err := cmd.Output()
+	// Capture deep scrollback to extract bead IDs and recent activity
+	out, err := tmux.Output(tmuxTimeout, "capture-pane", "-t", a.captureTarget(), "-p", "-S", fmt.Sprintf("-%d", clampCaptureDepth(detailCaptureDepth)))
 	if err != nil {
 		return
 	}
@@ -3008,7 +3760,7 @@ func (m *Model) fetchAgentDetails(a *AgentLight) {
 	content := string(out)
 
 	// Store last few non-empty lines for tooltip
-	lines := strings.Split(content, "\n")
+	lines := splitPaneLines(content)
 	var recent []string
 	for i := len(lines) - 1; i >= 0 && len(recent) < 3; i-- {
 		line := strings.TrimSpace(lines[i])
@@ -3027,8 +3779,7 @@ func (m *Model) fetchAgentDetails(a *AgentLight) {
 // Returns "" (unknown) if GT_AGENT is not set — caller should use
 // detectAgentTypeFromPane() on subsequent polls to identify from pane content.
 func detectAgentType(sessionName string) string {
-	cmd := tmux.BuildCommand("show-environment", "-t", sessionName, "GT_AGENT")
-	out, err := cmd.Output()
+	out, err := tmux.Output(tmuxTimeout, "show-environment", "-t", sessionName, "GT_AGENT")
 	if err != nil {
 		return "" // GT_AGENT not set — unknown, detect from pane content later
 	}
@@ -3041,24 +3792,6 @@ func detectAgentType(sessionName string) string {
 	return parts[1]
 }
 
-// detectAgentTypeFromPane identifies the agent type by inspecting pane content.
-// OpenCode has distinctive signatures: "OpenCode" in the bottom status bar,
-// box-drawing chrome (┃, ╹▀), and "esc interrupt" without Claude's ❯ prompt.
-// Returns "opencode" or "claude" (fallback).
-func detectAgentTypeFromPane(lines []string) string {
-	for _, line := range lines {
-		// OpenCode version string in bottom bar: "• OpenCode 1.1.60"
-		if strings.Contains(line, "OpenCode") {
-			return "opencode"
-		}
-		// OpenCode's bottom bar: "ctrl+t variants  tab agents  ctrl+p commands"
-		if strings.Contains(line, "ctrl+p commands") && strings.Contains(line, "tab agents") {
-			return "opencode"
-		}
-	}
-	return "claude" // default fallback
-}
-
 // isClaudeAgent returns true if the agent type represents a Claude Code session.
 // Empty string or "claude" both indicate Claude (the default).
 func isClaudeAgent(agentType string) bool {
@@ -3067,5 +3800,16 @@ func isClaudeAgent(agentType string) bool {
 
 // View renders the TUI.
 func (m *Model) View() string {
-	return m.render()
+	start := time.Now()
+	if m.screensaving(start) {
+		return m.renderScreensaver()
+	}
+	var out string
+	if m.wall != nil {
+		out = m.renderWall(start)
+	} else {
+		out = m.render()
+	}
+	m.frames.render = time.Since(start)
+	return out
 }
@@ -0,0 +1,166 @@
+package activity
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// MuteFileName is the file under <town>/.runtime/ listing muted agents.
+// Every gt top watching the town reads it each poll, so a mute set in one
+// instance (m key) or with gt top mute silences them all.
+const MuteFileName = "top-mutes.json"
+
+// muteSteps are the durations the m key cycles through on an agent; zero
+// mutes until unmuted, and the next press unmutes.
+var muteSteps = []time.Duration{time.Hour, 8 * time.Hour, 0}
+
+// Mute is one muted agent. A muted agent stays on screen, dimmed, but sends
+// no alerts and emits no loop_detected or limit_hit events, for agents left
+// broken on purpose while someone debugs them.
+type Mute struct {
+	At    time.Time `json:"at"`
+	Until time.Time `json:"until,omitempty"` // zero: until unmuted
+	By    string    `json:"by,omitempty"`
+}
+
+// active reports whether the mute still holds at now.
+func (mu Mute) active(now time.Time) bool {
+	return mu.Until.IsZero() || now.Before(mu.Until)
+}
+
+// MutePath returns the mute file path for a town.
+func MutePath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), MuteFileName)
+}
+
+// LoadMutes reads a town's mutes that still hold, keyed by session name.
+// Best-effort: a missing or unreadable file has none.
+func LoadMutes(townRoot string) map[string]Mute {
+	mutes := make(map[string]Mute)
+	if townRoot == "" {
+		return mutes
+	}
+	if data, err := os.ReadFile(MutePath(townRoot)); err == nil {
+		_ = json.Unmarshal(data, &mutes)
+	}
+	now := time.Now()
+	for session, mu := range mutes {
+		if !mu.active(now) {
+			delete(mutes, session)
+		}
+	}
+	return mutes
+}
+
+// SetMute mutes a session for d, or until unmuted when d is zero, and drops
+// expired mutes from the file.
+func SetMute(townRoot, session string, d time.Duration) error {
+	return updateMutes(townRoot, func(mutes map[string]Mute) {
+		mu := Mute{At: time.Now(), By: os.Getenv("USER")}
+		if d > 0 {
+			mu.Until = mu.At.Add(d)
+		}
+		mutes[session] = mu
+	})
+}
+
+// Unmute unmutes a session. Unmuting one that isn't muted does nothing.
+func Unmute(townRoot, session string) error {
+	return updateMutes(townRoot, func(mutes map[string]Mute) {
+		delete(mutes, session)
+	})
+}
+
+// updateMutes applies change to the town's mutes under the file lock.
+func updateMutes(townRoot string, change func(map[string]Mute)) error {
+	if townRoot == "" {
+		return fmt.Errorf("muting needs a town")
+	}
+	path := MutePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return err
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	mutes := LoadMutes(townRoot)
+	change(mutes)
+	data, err := json.MarshalIndent(mutes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.AtomicWriteFile(path, data, 0644)
+}
+
+// muted reports whether the agent is muted.
+func (m *Model) muted(a *AgentLight) bool {
+	mu, ok := m.mutes[a.SessionName]
+	return ok && mu.active(time.Now())
+}
+
+// cycleMuteHovered steps the hovered agent through muteSteps, then unmutes
+// it.
+func (m *Model) cycleMuteHovered() {
+	a := m.hoveredAgent
+	if a == nil || a.placeholder {
+		return
+	}
+	m.flashTime = time.Now()
+	next := 0
+	if mu, ok := m.mutes[a.SessionName]; ok && mu.active(time.Now()) {
+		if mu.Until.IsZero() {
+			if err := Unmute(m.townRoot, a.SessionName); err != nil {
+				m.flashMessage = "unmute failed: " + err.Error()
+				return
+			}
+			m.mutes = LoadMutes(m.townRoot)
+			m.flashMessage = "unmuted " + a.SessionName
+			return
+		}
+		// The step after the one this mute lasts closest to.
+		left := time.Until(mu.Until)
+		for next < len(muteSteps)-1 && muteSteps[next] > 0 && muteSteps[next] < left+time.Minute {
+			next++
+		}
+	}
+	d := muteSteps[next]
+	if err := SetMute(m.townRoot, a.SessionName, d); err != nil {
+		m.flashMessage = "mute failed: " + err.Error()
+		return
+	}
+	m.mutes = LoadMutes(m.townRoot)
+	m.flashMessage = "muted " + a.SessionName + " " + muteLabel(m.mutes[a.SessionName], time.Now()) + " (m again to extend)"
+}
+
+// muteLabel describes how long a mute lasts, e.g. "for 58m" or "until
+// unmuted".
+func muteLabel(mu Mute, now time.Time) string {
+	if mu.Until.IsZero() {
+		return "until unmuted"
+	}
+	return "for " + formatCountdown(mu.Until.Sub(now))
+}
+
+// muteDetail renders an agent's mute for the hover detail, e.g.
+// "muted for 58m by alice: no alerts or escalations", or "".
+func (m *Model) muteDetail(a *AgentLight, now time.Time) string {
+	mu, ok := m.mutes[a.SessionName]
+	if !ok || !mu.active(now) {
+		return ""
+	}
+	label := "muted " + muteLabel(mu, now)
+	if mu.By != "" {
+		label += " by " + mu.By
+	}
+	return label + ": no alerts or escalations"
+}
@@ -0,0 +1,77 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestMuteFile(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := SetMute(townRoot, "gt-gastown-Toast", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetMute(townRoot, "gt-gastown-Nux", 0); err != nil {
+		t.Fatal(err)
+	}
+	mutes := LoadMutes(townRoot)
+	if len(mutes) != 2 || mutes["gt-gastown-Toast"].Until.IsZero() || !mutes["gt-gastown-Nux"].Until.IsZero() {
+		t.Errorf("mutes = %+v, want Toast for an hour and Nux until unmuted", mutes)
+	}
+
+	if err := Unmute(townRoot, "gt-gastown-Nux"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := LoadMutes(townRoot)["gt-gastown-Nux"]; ok {
+		t.Error("Nux still muted after Unmute")
+	}
+}
+
+func TestMutedAgentSendsNoAlerts(t *testing.T) {
+	townRoot := t.TempDir()
+	toast := &AgentLight{SessionName: "gt-gastown-Toast", Level: LevelWaitingForHuman}
+	m := &Model{
+		townRoot:   townRoot,
+		agents:     []*AgentLight{toast},
+		alertRules: parseAlertRules([]config.TopAlertRule{{Condition: "waiting", Ladder: []config.TopAlertStep{{Notify: "desktop"}}}}),
+	}
+	if err := SetMute(townRoot, toast.SessionName, 0); err != nil {
+		t.Fatal(err)
+	}
+	m.mutes = LoadMutes(townRoot)
+
+	if cmd := m.escalateAlerts(time.Now()); cmd != nil {
+		t.Error("muted agent raised an alert")
+	}
+	if out := m.renderLight(toast); !strings.Contains(out, "muted") {
+		t.Errorf("row = %q, want it marked muted", out)
+	}
+	s := m.Snapshot(time.Now())
+	if !s.Agents[0].Muted {
+		t.Error("status file doesn't show the mute")
+	}
+}
+
+func TestCycleMute(t *testing.T) {
+	townRoot := t.TempDir()
+	toast := &AgentLight{SessionName: "gt-gastown-Toast"}
+	m := &Model{townRoot: townRoot, agents: []*AgentLight{toast}, hoveredAgent: toast}
+	key := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")}
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		m.Update(key)
+		got = append(got, muteLabel(m.mutes[toast.SessionName], time.Now()))
+		if !m.muted(toast) {
+			got[i] = "unmuted"
+		}
+	}
+	want := []string{"for 59m", "for 7h59m", "until unmuted", "unmuted"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("m cycles %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,144 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Nudge outcomes: gt top follows the witness's polecat_nudged events and
+// watches whether each nudged agent gets back to work, so the hover detail
+// can say "nudged 3× today, responded 2×" for an agent, and how often a
+// witness's nudges work, for judging whether a nudging policy pays off.
+
+const (
+	// nudgeSettle is how long after a nudge activity starts to count as a
+	// response: the nudge's own keystrokes redraw the pane.
+	nudgeSettle = 15 * time.Second
+
+	// nudgeResponseWindow is how long a nudged agent has to respond.
+	nudgeResponseWindow = 10 * time.Minute
+)
+
+// nudgeRecord is one polecat_nudged event and how it turned out.
+type nudgeRecord struct {
+	At        time.Time
+	By        string // the nudging witness's address (event actor)
+	Target    string // the nudged agent's address, e.g. "gastown/Toast"
+	Responded bool   // became active within nudgeResponseWindow
+}
+
+// settled reports whether the nudge's outcome is final at now.
+func (n *nudgeRecord) settled(now time.Time) bool {
+	return n.Responded || now.Sub(n.At) >= nudgeResponseWindow
+}
+
+// nudgeLog is today's nudges, read incrementally from the events file.
+type nudgeLog struct {
+	day     string // local date the records are for
+	records []*nudgeRecord
+}
+
+// trackNudges reads new polecat_nudged events and marks the nudged agents
+// now working as having responded. Must run after levels are computed.
+func (m *Model) trackNudges(now time.Time) {
+	if m.townRoot == "" {
+		return
+	}
+	if m.nudges == nil {
+		m.nudges = &nudgeLog{}
+	}
+	log := m.nudges
+	if day := now.Format("2006-01-02"); day != log.day {
+		log.day, log.records = day, nil
+	}
+	m.readNudges(now)
+	for _, n := range log.records {
+		if n.settled(now) || now.Sub(n.At) < nudgeSettle {
+			continue
+		}
+		if a := m.agentForAddress(n.Target); a != nil && a.Level == LevelActive {
+			n.Responded = true
+		}
+	}
+}
+
+// readNudges appends today's polecat_nudged events read this poll.
+func (m *Model) readNudges(now time.Time) {
+	log := m.nudges
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for _, evt := range m.tail.fresh {
+		if evt.Type != events.TypePolecatNudged || evt.At.Before(midnight) {
+			continue
+		}
+		rig, _ := evt.Payload["rig"].(string)
+		target, _ := evt.Payload["target"].(string)
+		if target == "" {
+			continue
+		}
+		if rig != "" && !strings.Contains(target, "/") {
+			target = rig + "/" + target
+		}
+		log.records = append(log.records, &nudgeRecord{At: evt.At, By: evt.Actor, Target: target})
+	}
+}
+
+// nudgeCounts counts today's nudges matching keep, and how many of those
+// that have settled were answered.
+func (m *Model) nudgeCounts(keep func(n *nudgeRecord) bool) (nudged, responded, settled int) {
+	if m.nudges == nil {
+		return 0, 0, 0
+	}
+	now := time.Now()
+	for _, n := range m.nudges.records {
+		if !keep(n) {
+			continue
+		}
+		nudged++
+		if n.Responded {
+			responded++
+		}
+		if n.settled(now) {
+			settled++
+		}
+	}
+	return nudged, responded, settled
+}
+
+// nudgeDetail renders the hover detail line for an agent's nudges today,
+// e.g. "nudged 3× today, responded 2×", and for a witness how well its
+// nudges work, e.g. "nudges today: 12, 8 answered (67%)". "" when none.
+func (m *Model) nudgeDetail(a *AgentLight) string {
+	var parts []string
+	if nudged, responded, _ := m.nudgeCounts(func(n *nudgeRecord) bool {
+		return m.agentForAddress(n.Target) == a
+	}); nudged > 0 {
+		parts = append(parts, fmt.Sprintf("nudged %d× today, responded %d×", nudged, responded))
+	}
+	if nudged, responded, settled := m.nudgeCounts(func(n *nudgeRecord) bool {
+		return m.agentForAddress(n.By) == a
+	}); nudged > 0 {
+		s := fmt.Sprintf("nudges today: %d, %d answered", nudged, responded)
+		if settled > 0 {
+			s += fmt.Sprintf(" (%d%%)", responded*100/settled)
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// nudgeSummary renders the town's nudge effectiveness for the stats line,
+// e.g. "⚡ 12 nudges today, 67% answered", or "" before the first nudge.
+func (m *Model) nudgeSummary() string {
+	nudged, responded, settled := m.nudgeCounts(func(*nudgeRecord) bool { return true })
+	if nudged == 0 {
+		return ""
+	}
+	s := fmt.Sprintf("⚡ %d nudges today", nudged)
+	if settled > 0 {
+		s += fmt.Sprintf(", %d%% answered", responded*100/settled)
+	}
+	return s
+}
@@ -0,0 +1,58 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func writeNudge(t *testing.T, townRoot string, at time.Time, target string) {
+	t.Helper()
+	writeEvent(t, townRoot, at, events.TypePolecatNudged, "gastown/witness", events.NudgePayload("gastown", target, "idle for 10 minutes"))
+}
+
+func TestTrackNudges(t *testing.T) {
+	townRoot := t.TempDir()
+	toast := &AgentLight{SessionName: "gt-gastown-Toast", AgentID: "gastown/polecats/Toast", Rig: "gastown", Role: "polecat", Name: "Toast", Level: LevelCold}
+	nux := &AgentLight{SessionName: "gt-gastown-Nux", AgentID: "gastown/polecats/Nux", Rig: "gastown", Role: "polecat", Name: "Nux", Level: LevelCold}
+	witness := &AgentLight{SessionName: "gt-gastown-witness", AgentID: "gastown/witness", Rig: "gastown", Role: "witness", Name: "witness"}
+	m := &Model{townRoot: townRoot, agents: []*AgentLight{toast, nux, witness}}
+
+	now := time.Now().Truncate(time.Second)
+	if now.Hour() == 0 && now.Minute() < 30 {
+		t.Skip("the nudges would straddle midnight")
+	}
+	writeNudge(t, townRoot, now.Add(-20*time.Minute), "Toast") // unanswered
+	writeNudge(t, townRoot, now.Add(-time.Minute), "Toast")
+	writeNudge(t, townRoot, now.Add(-time.Minute), "Nux")
+
+	// Toast gets back to work; Nux stays quiet.
+	toast.Level = LevelActive
+	m.readEvents(now)
+	m.trackNudges(now)
+
+	if got := m.nudgeDetail(toast); got != "nudged 2× today, responded 1×" {
+		t.Errorf("Toast detail = %q", got)
+	}
+	if got := m.nudgeDetail(nux); got != "nudged 1× today, responded 0×" {
+		t.Errorf("Nux detail = %q", got)
+	}
+	// Of the witness's three nudges, Nux's is still open: one of two settled answered.
+	if got := m.nudgeDetail(witness); got != "nudges today: 3, 1 answered (50%)" {
+		t.Errorf("witness detail = %q", got)
+	}
+	if got := m.nudgeSummary(); !strings.HasPrefix(got, "⚡ 3 nudges today") {
+		t.Errorf("summary = %q", got)
+	}
+
+	// Activity right after a nudge is the nudge itself being typed.
+	writeNudge(t, townRoot, now, "Nux")
+	nux.Level = LevelActive
+	m.readEvents(now)
+	m.trackNudges(now.Add(5 * time.Second))
+	if got := m.nudgeDetail(nux); got != "nudged 2× today, responded 1×" {
+		t.Errorf("Nux detail after a fresh nudge = %q, want only the older nudge answered", got)
+	}
+}
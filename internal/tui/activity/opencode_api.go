@@ -0,0 +1,179 @@
+package activity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// OpenCodeURLEnv is the tmux session environment variable holding the base
+// URL of an OpenCode agent's local server (e.g. http://127.0.0.1:4096, for
+// an agent started with "opencode --port 4096"). When set, gt top reads the
+// agent's status from the server instead of the pane.
+const OpenCodeURLEnv = "GT_OPENCODE_URL"
+
+const (
+	openCodeTimeout = 500 * time.Millisecond // per request; a slow server falls back to the pane
+	openCodeBackoff = 30 * time.Second       // how long an unreachable server is left alone
+)
+
+// openCodeStatus is an OpenCode agent's state as reported by its server.
+type openCodeStatus struct {
+	tool   string // running tool call, e.g. "bash(go test ./...)"
+	tokens int    // context size as of the last assistant message
+	busy   bool   // the last assistant message is still being generated
+}
+
+// openCodeSource queries OpenCode servers for agent status. It is only used
+// from the poll command, which never runs concurrently with itself.
+type openCodeSource struct {
+	client *http.Client
+	lookup func(session string) string // session -> base URL, "" when unset
+	urls   map[string]string           // cached lookup results
+	down   map[string]time.Time        // base URL -> retry time after a failure
+}
+
+func newOpenCodeSource() *openCodeSource {
+	t := tmux.NewTmux()
+	return &openCodeSource{
+		client: &http.Client{Timeout: openCodeTimeout},
+		lookup: func(session string) string {
+			v, _ := t.GetEnvironment(session, OpenCodeURLEnv)
+			return strings.TrimRight(v, "/")
+		},
+		urls: make(map[string]string),
+		down: make(map[string]time.Time),
+	}
+}
+
+// fetch returns the server status for each session that has a reachable
+// OpenCode server. Sessions missing from the result use the pane parser.
+func (s *openCodeSource) fetch(sessions []sessionInfo, now time.Time) map[string]*openCodeStatus {
+	out := make(map[string]*openCodeStatus)
+	live := make(map[string]string, len(sessions))
+	for _, si := range sessions {
+		base, ok := s.urls[si.name]
+		if !ok {
+			base = s.lookup(si.name)
+		}
+		live[si.name] = base
+		if base == "" || now.Before(s.down[base]) {
+			continue
+		}
+		st, err := s.status(base)
+		if err != nil {
+			s.down[base] = now.Add(openCodeBackoff)
+			continue
+		}
+		delete(s.down, base)
+		out[si.name] = st
+	}
+	s.urls = live // forget sessions that went away
+	return out
+}
+
+// OpenCode server API responses, reduced to the fields gt top reads.
+type (
+	openCodeSession struct {
+		ID       string `json:"id"`
+		ParentID string `json:"parentID"` // set for subagent sessions
+		Time     struct {
+			Updated int64 `json:"updated"`
+		} `json:"time"`
+	}
+	openCodeMessage struct {
+		Info struct {
+			Role string `json:"role"`
+			Time struct {
+				Completed int64 `json:"completed"`
+			} `json:"time"`
+			Tokens struct {
+				Input     int `json:"input"`
+				Output    int `json:"output"`
+				Reasoning int `json:"reasoning"`
+				Cache     struct {
+					Read  int `json:"read"`
+					Write int `json:"write"`
+				} `json:"cache"`
+			} `json:"tokens"`
+		} `json:"info"`
+		Parts []struct {
+			Type  string `json:"type"`
+			Tool  string `json:"tool"`
+			State struct {
+				Status string          `json:"status"` // pending, running, completed, error
+				Input  json.RawMessage `json:"input"`
+			} `json:"state"`
+		} `json:"parts"`
+	}
+)
+
+// status reads the most recently updated top-level session on a server.
+func (s *openCodeSource) status(base string) (*openCodeStatus, error) {
+	var sessions []openCodeSession
+	if err := s.get(base+"/session", &sessions); err != nil {
+		return nil, err
+	}
+	var cur *openCodeSession
+	for i := range sessions {
+		if sessions[i].ParentID == "" && (cur == nil || sessions[i].Time.Updated > cur.Time.Updated) {
+			cur = &sessions[i]
+		}
+	}
+	st := &openCodeStatus{}
+	if cur == nil {
+		return st, nil // server up, no conversation yet
+	}
+
+	var msgs []openCodeMessage
+	if err := s.get(base+"/session/"+url.PathEscape(cur.ID)+"/message?limit=10", &msgs); err != nil {
+		return nil, err
+	}
+	for i := len(msgs) - 1; i >= 0; i-- {
+		m := msgs[i]
+		if m.Info.Role != "assistant" {
+			continue
+		}
+		t := m.Info.Tokens
+		st.tokens = t.Input + t.Output + t.Reasoning + t.Cache.Read + t.Cache.Write
+		st.busy = m.Info.Time.Completed == 0
+		for _, p := range m.Parts {
+			if p.Type == "tool" && (p.State.Status == "running" || p.State.Status == "pending") {
+				st.tool = formatTranscriptTool(p.Tool, p.State.Input)
+			}
+		}
+		break
+	}
+	return st, nil
+}
+
+func (s *openCodeSource) get(u string, v any) error {
+	resp, err := s.client.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", u, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// applyOpenCodeStatus overlays server status on the pane-parsed values. The
+// pane parser still runs first: permission prompts and limit messages are
+// only visible there.
+func applyOpenCodeStatus(a *AgentLight, st *openCodeStatus) {
+	a.apiLive = true
+	a.CurrentTool = st.tool
+	if st.tokens > 0 {
+		a.TokenCount = st.tokens
+	}
+	if st.busy && a.StatusText == "" {
+		a.StatusText = "working"
+	}
+}
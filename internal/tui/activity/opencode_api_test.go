@@ -0,0 +1,96 @@
+package activity
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func fakeOpenCodeServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/session", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"id":"ses_old","time":{"updated":100}},
+			{"id":"ses_sub","parentID":"ses_cur","time":{"updated":300}},
+			{"id":"ses_cur","time":{"updated":200}}
+		]`))
+	})
+	mux.HandleFunc("/session/ses_cur/message", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"info":{"role":"user"},"parts":[{"type":"text"}]},
+			{"info":{"role":"assistant","time":{"completed":0},
+			         "tokens":{"input":1200,"output":300,"reasoning":0,"cache":{"read":38000,"write":500}}},
+			 "parts":[
+				{"type":"tool","tool":"read","state":{"status":"completed","input":{"filePath":"go.mod"}}},
+				{"type":"tool","tool":"bash","state":{"status":"running","input":{"command":"go test ./..."}}}
+			 ]}
+		]`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testOpenCodeSource(urls map[string]string) *openCodeSource {
+	return &openCodeSource{
+		client: &http.Client{Timeout: time.Second},
+		lookup: func(session string) string { return urls[session] },
+		urls:   make(map[string]string),
+		down:   make(map[string]time.Time),
+	}
+}
+
+func TestOpenCodeSource_Fetch(t *testing.T) {
+	srv := fakeOpenCodeServer(t)
+	s := testOpenCodeSource(map[string]string{"gt-oc": srv.URL})
+
+	got := s.fetch([]sessionInfo{{name: "gt-oc"}, {name: "gt-claude"}}, time.Now())
+	st := got["gt-oc"]
+	if st == nil {
+		t.Fatal("expected status for the session with a server")
+	}
+	if _, ok := got["gt-claude"]; ok {
+		t.Error("sessions without a server URL should fall back to the pane")
+	}
+	if st.tool != "bash(go test ./...)" || st.tokens != 40000 || !st.busy {
+		t.Errorf("status = %+v", st)
+	}
+
+	a := &AgentLight{AgentType: "opencode"}
+	applyOpenCodeStatus(a, st)
+	if a.CurrentTool != "bash(go test ./...)" || a.TokenCount != 40000 || a.StatusText != "working" || !a.apiLive {
+		t.Errorf("agent = %+v", a)
+	}
+}
+
+func TestOpenCodeSource_Unreachable(t *testing.T) {
+	srv := fakeOpenCodeServer(t)
+	base := srv.URL
+	srv.Close()
+
+	calls := 0
+	s := testOpenCodeSource(nil)
+	s.lookup = func(string) string { calls++; return base }
+
+	now := time.Now()
+	if got := s.fetch([]sessionInfo{{name: "gt-oc"}}, now); len(got) != 0 {
+		t.Fatal("unreachable server should fall back to the pane")
+	}
+	if s.down[base].IsZero() {
+		t.Fatal("unreachable server should be backed off")
+	}
+	s.fetch([]sessionInfo{{name: "gt-oc"}}, now.Add(time.Second))
+	if calls != 1 {
+		t.Errorf("lookup called %d times, want the URL cached", calls)
+	}
+}
+
+func TestApplyToolEvents_APIOwnsTool(t *testing.T) {
+	a := &AgentLight{SessionName: "gt-oc", AgentType: "opencode", CurrentTool: "bash(make)", apiLive: true}
+	m := &Model{agents: []*AgentLight{a}}
+	m.applyToolEvents()
+	if a.CurrentTool != "bash(make)" {
+		t.Errorf("tool events must not clear a tool reported by the server, got %q", a.CurrentTool)
+	}
+}
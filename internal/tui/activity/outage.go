@@ -0,0 +1,170 @@
+package activity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Provider outage detection: when agents across several rigs start failing
+// against the same provider's API within a few minutes of each other, the
+// provider is the likely cause, not the agents.
+const (
+	// outageWindow is how close together the agents must start failing.
+	outageWindow = 5 * time.Minute
+	// outageMinAgents is how many failing agents make an outage, and how
+	// many must still be failing for it to continue.
+	outageMinAgents = 3
+	// outageMinRigs is how many rigs the failing agents must span: a burst
+	// within one rig is more likely that rig's doing.
+	outageMinRigs = 2
+)
+
+// outage is a probable provider outage in progress.
+type outage struct {
+	provider string
+	since    time.Time // when the first affected agent started failing
+	agents   int       // agents affected now
+	rigs     int       // rigs they span
+}
+
+// providerNames maps agent types to the API provider they call. Agents that
+// can call several (opencode, pi) are grouped by agent type instead.
+var providerNames = map[string]string{
+	"claude":  "Anthropic",
+	"codex":   "OpenAI",
+	"gemini":  "Google",
+	"copilot": "GitHub Copilot",
+	"cursor":  "Cursor",
+	"amp":     "Amp",
+	"auggie":  "Augment",
+}
+
+// providerFor returns the provider an agent's API errors are attributed to,
+// or "" when the agent type is unknown.
+func providerFor(a *AgentLight) string {
+	t := strings.ToLower(a.AgentType)
+	if isClaudeAgent(t) {
+		return providerNames["claude"]
+	}
+	if name, ok := providerNames[t]; ok {
+		return name
+	}
+	return t
+}
+
+// isAPIErrorLine reports whether a lowercased pane line shows a
+// provider-side API failure: a 5xx, an overload, or an unavailable service.
+// Client errors (bad request, auth) are the agent's problem, not an outage.
+func isAPIErrorLine(lower string) bool {
+	if strings.Contains(lower, "overloaded") || strings.Contains(lower, "service unavailable") ||
+		strings.Contains(lower, "internal server error") {
+		return true
+	}
+	if !strings.Contains(lower, "api error") {
+		return false
+	}
+	for _, code := range []string{"500", "502", "503", "504", "529"} {
+		if strings.Contains(lower, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectOutages updates each agent's failure start and the provider
+// outages, reporting provider_outage when one starts or resolves. Must run
+// after the pane parse for this poll and before reportLimits, which leaves
+// agents covered by an outage to it.
+func (m *Model) detectOutages(now time.Time) {
+	failing := make(map[string][]*AgentLight)
+	for _, a := range m.agents {
+		if !a.RateLimited && !a.APIError {
+			a.failingSince = time.Time{}
+			continue
+		}
+		if a.failingSince.IsZero() {
+			a.failingSince = now
+		}
+		if p := providerFor(a); p != "" {
+			failing[p] = append(failing[p], a)
+		}
+	}
+
+	for p, o := range m.outages {
+		agents := failing[p]
+		if len(agents) >= outageMinAgents {
+			o.agents, o.rigs = len(agents), countRigs(agents)
+			continue
+		}
+		delete(m.outages, p)
+		m.reportHealth(events.TypeProviderOutage, events.ProviderOutagePayload(p, "resolved", o.agents, o.rigs, o.since))
+	}
+
+	for p, agents := range failing {
+		if m.outages[p] != nil {
+			continue
+		}
+		var recent []*AgentLight
+		for _, a := range agents {
+			if now.Sub(a.failingSince) <= outageWindow {
+				recent = append(recent, a)
+			}
+		}
+		if len(recent) < outageMinAgents || countRigs(recent) < outageMinRigs {
+			continue
+		}
+		o := &outage{provider: p, since: now, agents: len(agents), rigs: countRigs(agents)}
+		for _, a := range recent {
+			if a.failingSince.Before(o.since) {
+				o.since = a.failingSince
+			}
+		}
+		if m.outages == nil {
+			m.outages = make(map[string]*outage)
+		}
+		m.outages[p] = o
+		m.reportHealth(events.TypeProviderOutage, events.ProviderOutagePayload(p, "started", o.agents, o.rigs, o.since))
+	}
+}
+
+// inOutage reports whether the agent is failing under a provider outage,
+// so its own limit_hit event and rate_limited alerts are left to the outage.
+func (m *Model) inOutage(a *AgentLight) bool {
+	return !a.failingSince.IsZero() && m.outages[providerFor(a)] != nil
+}
+
+// countRigs counts the distinct rigs of the agents.
+func countRigs(agents []*AgentLight) int {
+	rigs := make(map[string]bool)
+	for _, a := range agents {
+		rigs[a.Rig] = true
+	}
+	return len(rigs)
+}
+
+// renderOutageBanner renders the provider outage banner shown under the
+// header, or "" while no outage is in progress.
+func (m *Model) renderOutageBanner() string {
+	if len(m.outages) == 0 {
+		return ""
+	}
+	var outages []*outage
+	for _, o := range m.outages {
+		outages = append(outages, o)
+	}
+	sort.Slice(outages, func(i, j int) bool { return outages[i].since.Before(outages[j].since) })
+	var parts []string
+	for _, o := range outages {
+		parts = append(parts, fmt.Sprintf("%s API degraded — %d agents affected since %s",
+			o.provider, o.agents, o.since.Local().Format("15:04")))
+	}
+	style := slaBreachStyle
+	if m.width > 4 {
+		style = style.MaxWidth(m.width - 4) // one line, so hover rows stay aligned
+	}
+	return style.Render("⚠ " + strings.Join(parts, " · "))
+}
@@ -0,0 +1,102 @@
+package activity
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestIsAPIErrorLine(t *testing.T) {
+	for line, want := range map[string]bool{
+		`⎿ api error: 529 {"type":"error","error":{"type":"overloaded_error"}}`: true,
+		"api error: 500 internal server error":                                  true,
+		"error: service unavailable, retrying":                                  true,
+		"api error: 400 invalid request":                                        false,
+		"api error: 401 authentication failed":                                  false,
+		"running tests: 500 passed":                                             false,
+	} {
+		if got := isAPIErrorLine(line); got != want {
+			t.Errorf("isAPIErrorLine(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
+
+func TestDetectOutages(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	agent := func(name, rig, agentType string) *AgentLight {
+		return &AgentLight{SessionName: "gt-" + rig + "-" + name, Name: name, Rig: rig, AgentType: agentType, Level: LevelRateLimited}
+	}
+	toast, nux, slit := agent("Toast", "gastown", "claude"), agent("Nux", "gastown", "claude"), agent("Slit", "beads", "claude")
+	codex := agent("Ace", "beads", "codex")
+	m := &Model{width: 160, townRoot: townRoot, agents: []*AgentLight{toast, nux, slit, codex}, reportAs: "daemon/agents", reporting: true}
+
+	// Two failing Claude agents and one Codex agent: no outage yet.
+	toast.APIError, nux.RateLimited, codex.RateLimited = true, true, true
+	m.detectOutages(now)
+	if len(m.outages) != 0 {
+		t.Fatalf("outages = %v with only two failing Claude agents", m.outages)
+	}
+
+	// A third, in another rig, within the window: outage.
+	slit.APIError = true
+	m.detectOutages(now.Add(2 * time.Minute))
+	o := m.outages["Anthropic"]
+	if o == nil || o.agents != 3 || o.rigs != 2 || !o.since.Equal(now) {
+		t.Fatalf("outage = %+v, want Anthropic, 3 agents in 2 rigs since the first failure", o)
+	}
+	if banner := m.renderOutageBanner(); !strings.Contains(banner, "Anthropic API degraded — 3 agents affected since "+now.Format("15:04")) {
+		t.Errorf("banner = %q", banner)
+	}
+
+	// The affected agents' own rate limits are covered by the outage; the
+	// Codex agent's is not.
+	m.reportLimits()
+	if !m.inOutage(nux) || m.inOutage(codex) {
+		t.Errorf("inOutage: nux %v codex %v", m.inOutage(nux), m.inOutage(codex))
+	}
+
+	// Recovery below the threshold resolves it.
+	toast.APIError = false
+	m.detectOutages(now.Add(3 * time.Minute))
+	if len(m.outages) != 0 || m.renderOutageBanner() != "" {
+		t.Errorf("outage not resolved: %v", m.outages)
+	}
+
+	data, err := os.ReadFile(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		t.Fatalf("reading events file: %v", err)
+	}
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var evt events.Event
+		if err := json.Unmarshal([]byte(line), &evt); err != nil {
+			t.Fatalf("bad event line %q: %v", line, err)
+		}
+		switch evt.Type {
+		case events.TypeProviderOutage:
+			got = append(got, evt.Payload["status"].(string))
+		case events.TypeLimitHit:
+			got = append(got, "limit_hit "+evt.Payload["session"].(string))
+		}
+	}
+	if want := "started,limit_hit gt-beads-Ace,resolved"; strings.Join(got, ",") != want {
+		t.Errorf("events = %v, want %s", got, want)
+	}
+}
+
+func TestDetectOutages_OneRigIsNotAnOutage(t *testing.T) {
+	m := &Model{}
+	for _, name := range []string{"Toast", "Nux", "Slit", "Ace"} {
+		m.agents = append(m.agents, &AgentLight{SessionName: "gt-gastown-" + name, Rig: "gastown", AgentType: "claude", APIError: true})
+	}
+	m.detectOutages(time.Now())
+	if len(m.outages) != 0 {
+		t.Errorf("outages = %v for failures confined to one rig", m.outages)
+	}
+}
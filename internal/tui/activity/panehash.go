@@ -0,0 +1,82 @@
+package activity
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+// Activity source (gt top --activity-source, town settings
+// top.activity_source): by default a light follows tmux's window_activity,
+// which also moves when a human attaches, types or scrolls. With "pane" it
+// follows the captured pane content instead, so only output drives the
+// LEDs.
+//
+// Even in window mode, a window_activity change while a human is attached
+// (e.g. after a double-click) counts only if the pane content changed too:
+// attaching redraws the window, which shouldn't light up the agent.
+
+// paneHashLines is how many trailing lines of a capture are hashed. Every
+// capture is a tail of the same scrollback, so the last lines match
+// whatever depth the pane was captured at.
+const paneHashLines = 20
+
+// SetActivitySource sets what marks an agent active: "window" (or empty)
+// for tmux's window_activity, or "pane" for changes in the pane content.
+func (m *Model) SetActivitySource(source string) error {
+	switch source {
+	case "", "window":
+		m.activityFromPane = false
+	case "pane":
+		m.activityFromPane = true
+	default:
+		return fmt.Errorf("unknown activity source %q (want window or pane)", source)
+	}
+	return nil
+}
+
+// paneHash hashes the last paneHashLines lines of a capture.
+func paneHash(lines []string) uint64 {
+	if len(lines) > paneHashLines {
+		lines = lines[len(lines)-paneHashLines:]
+	}
+	h := fnv.New64a()
+	for _, l := range lines {
+		_, _ = h.Write([]byte(l))
+		_, _ = h.Write([]byte{'\n'})
+	}
+	return h.Sum64()
+}
+
+// notePaneActivity moves the agent's last change to now when its pane
+// content changed since the last fresh capture. A stale capture says
+// nothing. CurActivity follows, so the status file's last_activity agrees
+// with the LEDs.
+func (a *AgentLight) notePaneActivity(s sessionInfo, now time.Time) {
+	if s.captureStale || s.paneLines == nil {
+		return
+	}
+	h := paneHash(s.paneLines)
+	if a.paneHash != 0 && h != a.paneHash {
+		a.LastChangeTime = now
+		a.PrevActivity, a.CurActivity = a.CurActivity, now.Unix()
+	}
+	a.paneHash = h
+}
+
+// attachOnly reports whether a window_activity change this poll can be put
+// down to a human attaching: the session is attached, or was attached since
+// the last poll, and the pane's tail hashes the same as at the last fresh
+// capture. Without a fresh capture it can't tell and reports false. It
+// keeps the hash and attach time current either way, so call it every poll.
+func (a *AgentLight) attachOnly(s sessionInfo) bool {
+	attached := s.attached > 0 || s.lastAttached > a.lastAttached
+	a.lastAttached = s.lastAttached
+	if s.captureStale || s.paneLines == nil {
+		return false
+	}
+	h := paneHash(s.paneLines)
+	same := a.paneHash != 0 && h == a.paneHash
+	a.paneHash = h
+	return attached && same
+}
@@ -0,0 +1,103 @@
+package activity
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPaneHashIgnoresCaptureDepth(t *testing.T) {
+	var buf []string
+	for i := 0; i < 60; i++ {
+		buf = append(buf, fmt.Sprintf("line %d", i))
+	}
+	if paneHash(buf[10:]) != paneHash(buf[30:]) {
+		t.Error("captures of the same pane at different depths hash differently")
+	}
+	if paneHash(buf[10:]) == paneHash(append(buf[10:len(buf)-1:len(buf)-1], "new output")) {
+		t.Error("new output doesn't change the hash")
+	}
+}
+
+func TestPaneActivitySource(t *testing.T) {
+	m := &Model{}
+	if err := m.SetActivitySource("pane"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetActivitySource("cursor"); err == nil {
+		t.Error("SetActivitySource accepted an unknown source")
+	}
+
+	start := time.Now()
+	s := sessionInfo{name: "gt-gastown-Toast", activity: 100, paneLines: []string{"❯ "}}
+	m.updateAgents([]sessionInfo{s})
+	a := m.agents[0]
+	a.LastChangeTime = start.Add(-time.Hour)
+
+	// A human attaching moves window_activity, but the pane is unchanged.
+	s.activity = 200
+	m.updateAgents([]sessionInfo{s})
+	if !a.LastChangeTime.Before(start) {
+		t.Error("window_activity moved the light in pane mode")
+	}
+
+	// A stale capture says nothing either.
+	s.paneLines, s.captureStale = []string{"something else"}, true
+	m.updateAgents([]sessionInfo{s})
+	if !a.LastChangeTime.Before(start) {
+		t.Error("a stale capture moved the light")
+	}
+
+	s.paneLines, s.captureStale = []string{"⏺ Bash(go test ./...)"}, false
+	m.updateAgents([]sessionInfo{s})
+	if a.LastChangeTime.Before(start) {
+		t.Error("new pane output didn't move the light")
+	}
+}
+
+func TestAttachActivityIgnored(t *testing.T) {
+	m := &Model{}
+	start := time.Now()
+	s := sessionInfo{name: "gt-gastown-Toast", activity: 100, lastAttached: 50, paneLines: []string{"❯ "}}
+	m.updateAgents([]sessionInfo{s})
+	a := m.agents[0]
+	a.LastChangeTime = start.Add(-time.Hour)
+
+	// A double-click attaches; tmux redraws the window but the pane is unchanged.
+	s.activity, s.lastAttached, s.attached = 200, 190, 1
+	m.updateAgents([]sessionInfo{s})
+	if !a.LastChangeTime.Before(start) {
+		t.Error("attaching moved the light")
+	}
+
+	// Detached again by the next poll, the attach still accounts for it.
+	s.activity, s.lastAttached, s.attached = 210, 205, 0
+	m.updateAgents([]sessionInfo{s})
+	if !a.LastChangeTime.Before(start) {
+		t.Error("an attach between polls moved the light")
+	}
+
+	// Output while attached counts.
+	s.activity, s.attached = 220, 1
+	s.paneLines = []string{"⏺ Bash(go test ./...)"}
+	m.updateAgents([]sessionInfo{s})
+	if a.LastChangeTime.Before(start) {
+		t.Error("new output while attached didn't move the light")
+	}
+
+	// Without a fresh capture there's no telling, so activity counts.
+	a.LastChangeTime = start.Add(-time.Hour)
+	s.activity, s.captureStale = 230, true
+	m.updateAgents([]sessionInfo{s})
+	if a.LastChangeTime.Before(start) {
+		t.Error("activity with a stale capture was taken for an attach")
+	}
+
+	// Unattached activity counts even if the pane looks the same.
+	a.LastChangeTime = start.Add(-time.Hour)
+	s.activity, s.captureStale, s.attached = 240, false, 0
+	m.updateAgents([]sessionInfo{s})
+	if a.LastChangeTime.Before(start) {
+		t.Error("unattached activity was ignored")
+	}
+}
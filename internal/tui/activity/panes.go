@@ -0,0 +1,127 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// agentPaneTitle tags the agent's pane in a multi-pane session: gt top
+// captures a pane titled this (tmux select-pane -T gt-agent) over any other.
+const agentPaneTitle = "gt-agent"
+
+// How a multi-pane session's agent pane was picked, for the hover line.
+const (
+	paneByTitle    = "titled " + agentPaneTitle
+	paneByDeclared = "GT_PANE_ID"
+	paneByLargest  = "largest"
+)
+
+// paneInfo is one tmux pane of a session.
+type paneInfo struct {
+	id     string // e.g. "%5"
+	title  string
+	width  int
+	height int
+}
+
+// listPanes returns the panes of every tmux session, in tmux order, keyed
+// by session name. One tmux call covers all sessions.
+func listPanes() map[string][]paneInfo {
+	out, err := tmux.Output(tmuxTimeout, "list-panes", "-a", "-F",
+		"#{session_name}|#{pane_id}|#{pane_width}|#{pane_height}|#{pane_title}")
+	if err != nil {
+		return nil
+	}
+	panes := make(map[string][]paneInfo)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.SplitN(line, "|", 5)
+		if len(parts) < 5 {
+			continue
+		}
+		p := paneInfo{id: parts[1], title: parts[4]}
+		fmt.Sscanf(parts[2], "%d", &p.width)
+		fmt.Sscanf(parts[3], "%d", &p.height)
+		panes[parts[0]] = append(panes[parts[0]], p)
+	}
+	return panes
+}
+
+// declaredPane returns the agent pane recorded in the session's GT_PANE_ID
+// at startup, or "".
+func declaredPane(session string) string {
+	out, err := tmux.Output(tmuxTimeout, "show-environment", "-t", session, "GT_PANE_ID")
+	if err != nil {
+		return ""
+	}
+	_, id, _ := strings.Cut(strings.TrimSpace(string(out)), "=")
+	return id
+}
+
+// dominantPane picks the pane the agent runs in: one titled agentPaneTitle,
+// else the pane declared in GT_PANE_ID, else the largest (the first on a
+// tie). A human splitting a pane to poke around leaves the agent's pane
+// the one captured.
+func dominantPane(panes []paneInfo, declared string) (paneInfo, string) {
+	for _, p := range panes {
+		if p.title == agentPaneTitle {
+			return p, paneByTitle
+		}
+	}
+	for _, p := range panes {
+		if declared != "" && p.id == declared {
+			return p, paneByDeclared
+		}
+	}
+	best := panes[0]
+	for _, p := range panes[1:] {
+		if p.width*p.height > best.width*best.height {
+			best = p
+		}
+	}
+	return best, paneByLargest
+}
+
+// resolveAgentPanes points each multi-pane session's capture at its agent
+// pane. Single-pane sessions keep capturing the session target, and so
+// cost nothing beyond the one list-panes call.
+func resolveAgentPanes(sessions []sessionInfo) {
+	all := listPanes()
+	for i := range sessions {
+		s := &sessions[i]
+		panes := all[s.name]
+		s.panes = len(panes)
+		if len(panes) < 2 {
+			continue
+		}
+		p, how := dominantPane(panes, declaredPane(s.name))
+		s.pane, s.paneReason = p.id, how
+	}
+}
+
+// target returns the tmux target to capture: the agent pane when the
+// session has several, else the session itself.
+func (s sessionInfo) target() string {
+	if s.pane != "" {
+		return s.pane
+	}
+	return s.name
+}
+
+// captureTarget returns the tmux target for the agent's pane.
+func (a *AgentLight) captureTarget() string {
+	if a.PaneID != "" {
+		return a.PaneID
+	}
+	return a.SessionName
+}
+
+// paneSummary describes a multi-pane session for the hover line, e.g.
+// "3 panes · watching %5 (largest)", or "" for a single pane.
+func (a *AgentLight) paneSummary() string {
+	if a.Panes < 2 {
+		return ""
+	}
+	return fmt.Sprintf("%d panes · watching %s (%s)", a.Panes, a.PaneID, a.PaneReason)
+}
@@ -0,0 +1,54 @@
+package activity
+
+import "testing"
+
+func TestDominantPane(t *testing.T) {
+	agent := paneInfo{id: "%1", width: 120, height: 40}
+	split := paneInfo{id: "%7", width: 80, height: 20}
+	tagged := paneInfo{id: "%9", width: 40, height: 10, title: agentPaneTitle}
+
+	tests := []struct {
+		name     string
+		panes    []paneInfo
+		declared string
+		wantID   string
+		wantHow  string
+	}{
+		{"largest wins", []paneInfo{split, agent}, "", "%1", paneByLargest},
+		{"declared beats largest", []paneInfo{agent, split}, "%7", "%7", paneByDeclared},
+		{"stale declared falls back", []paneInfo{split, agent}, "%42", "%1", paneByLargest},
+		{"title beats declared", []paneInfo{agent, split, tagged}, "%1", "%9", paneByTitle},
+		{"tie keeps the first", []paneInfo{{id: "%2", width: 10, height: 10}, {id: "%3", width: 10, height: 10}}, "", "%2", paneByLargest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, how := dominantPane(tt.panes, tt.declared)
+			if p.id != tt.wantID || how != tt.wantHow {
+				t.Errorf("dominantPane = %s (%s), want %s (%s)", p.id, how, tt.wantID, tt.wantHow)
+			}
+		})
+	}
+}
+
+func TestPaneTargets(t *testing.T) {
+	s := sessionInfo{name: "gt-gastown-crew-max"}
+	if got := s.target(); got != "gt-gastown-crew-max" {
+		t.Errorf("single pane target = %q, want the session", got)
+	}
+	s.pane = "%5"
+	if got := s.target(); got != "%5" {
+		t.Errorf("multi-pane target = %q, want %%5", got)
+	}
+
+	a := &AgentLight{SessionName: "gt-gastown-crew-max", Panes: 1}
+	if a.captureTarget() != a.SessionName || a.paneSummary() != "" {
+		t.Errorf("single pane: target %q, summary %q", a.captureTarget(), a.paneSummary())
+	}
+	a.Panes, a.PaneID, a.PaneReason = 3, "%5", paneByLargest
+	if a.captureTarget() != "%5" {
+		t.Errorf("captureTarget = %q, want %%5", a.captureTarget())
+	}
+	if got, want := a.paneSummary(), "3 panes · watching %5 (largest)"; got != want {
+		t.Errorf("paneSummary = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,154 @@
+package activity
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ParseField is one value the pane parsers derived, e.g. {"status", "Thinking…"}.
+type ParseField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ParseLine is one captured pane line and what the parsers made of it.
+type ParseLine struct {
+	Text   string   `json:"text"`
+	Chrome bool     `json:"chrome,omitempty"` // skipped as TUI chrome
+	Fields []string `json:"fields,omitempty"` // fields whose value depends on this line
+}
+
+// ParseReport is the parser pipeline's reading of one live pane, for
+// gt top parse-check and parser bug reports.
+type ParseReport struct {
+	Session      string       `json:"session"`
+	AgentType    string       `json:"agent_type"`
+	AgentVersion string       `json:"agent_version,omitempty"`
+	Fields       []ParseField `json:"fields"`
+	Lines        []ParseLine  `json:"lines"`
+}
+
+// ParseCheck captures the session's pane the way gt top does and runs it
+// through agent detection, chrome selection, the pane parser, and startup
+// detection, as on the session's first poll. An empty session checks every
+// session gt top monitors.
+func (m *Model) ParseCheck(session string) ([]*ParseReport, error) {
+	sessions, err := listSessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	if session == "" {
+		sessions = m.filter.filterSessions(sessions)
+	} else {
+		var match []sessionInfo
+		for _, s := range sessions {
+			if s.name == session {
+				match = append(match, s)
+			}
+		}
+		if len(match) == 0 {
+			return nil, fmt.Errorf("session %q not found", session)
+		}
+		sessions = match
+	}
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+
+	resolveAgentPanes(sessions)
+	panes := batchCapturePanes(sessions)
+	recaptureDeeper(sessions, panes)
+	now := time.Now()
+	var reports []*ParseReport
+	for _, s := range sessions {
+		lines, ok := panes[s.name]
+		if !ok {
+			return nil, fmt.Errorf("capturing pane of %s failed", s.name)
+		}
+		reports = append(reports, m.parseReport(s.name, lines, time.Unix(s.created, 0), now))
+	}
+	return reports, nil
+}
+
+// parseReport runs the pipeline over lines, then again with each line left
+// out: the fields that change without a line are the ones it produced.
+func (m *Model) parseReport(session string, lines []string, created, now time.Time) *ParseReport {
+	a := m.parseOnce(session, lines, created, now)
+	fields := parsedFields(a)
+	rep := &ParseReport{Session: session, AgentType: a.AgentType, AgentVersion: a.AgentVersion, Fields: fields}
+
+	chrome := chromeOf(a)
+	for i, text := range lines {
+		pl := ParseLine{Text: text, Chrome: chrome.isChrome(text)}
+		without := append(append([]string(nil), lines[:i]...), lines[i+1:]...)
+		alt := fieldMap(parsedFields(m.parseOnce(session, without, created, now)))
+		for _, f := range fields {
+			if alt[f.Name] != f.Value {
+				pl.Fields = append(pl.Fields, f.Name)
+			}
+		}
+		rep.Lines = append(rep.Lines, pl)
+	}
+	return rep
+}
+
+// parseOnce parses lines for a fresh agent, as updateAgents does on a
+// session's first poll.
+func (m *Model) parseOnce(session string, lines []string, created, now time.Time) *AgentLight {
+	a := &AgentLight{SessionName: session, SessionCreated: created}
+	m.selectChrome(a, lines)
+	parsePaneContent(a, lines)
+	detectStartup(a, lines, now)
+	return a
+}
+
+// parsedFields lists the pane-derived fields that are set, in display order.
+// Agent type and version are reported separately; RecentOutput is left out
+// since every line feeds it.
+func parsedFields(a *AgentLight) []ParseField {
+	var out []ParseField
+	add := func(name, value string) {
+		if value != "" {
+			out = append(out, ParseField{Name: name, Value: value})
+		}
+	}
+	num := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+	flag := func(b bool, detail string) string {
+		if !b {
+			return ""
+		}
+		if detail == "" {
+			return "yes"
+		}
+		return detail
+	}
+
+	add("status", a.StatusText)
+	add("tool", a.CurrentTool)
+	add("waiting", flag(a.WaitingForHuman, a.WaitingReason))
+	add("rate_limited", flag(a.RateLimited, ""))
+	add("hit_limit", flag(a.HitLimit, a.LimitResetInfo))
+	add("context_left_pct", num(a.ContextPercent))
+	add("tokens", num(a.TokenCount))
+	add("session_limit_pct", num(a.SessionLimitPct))
+	add("session_limit_reset", a.SessionLimitReset)
+	add("compacting", flag(a.IsCompacting, ""))
+	add("tool_errors", num(a.ToolErrorCount))
+	add("last_tool_error", a.LastToolError)
+	add("startup", a.StartupPhase)
+	return out
+}
+
+func fieldMap(fields []ParseField) map[string]string {
+	m := make(map[string]string, len(fields))
+	for _, f := range fields {
+		m[f.Name] = f.Value
+	}
+	return m
+}
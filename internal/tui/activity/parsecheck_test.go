@@ -0,0 +1,53 @@
+package activity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseReport_CreditsLines(t *testing.T) {
+	lines := []string{
+		"│ ✻ Welcome to Claude Code v2.0.76 │",
+		"⏺ Bash(go test ./...)",
+		"  ⎿  Running…",
+		"────────────────────────────────────────",
+		"❯ ",
+		"  ⏵⏵ bypass permissions on (shift+tab to cycle)",
+	}
+	now := time.Now()
+	m := &Model{}
+	rep := m.parseReport("gt-gastown-crew-max", lines, now.Add(-time.Hour), now)
+
+	if rep.AgentType != "claude" || rep.AgentVersion != "2.0.76" {
+		t.Errorf("agent = %q %q", rep.AgentType, rep.AgentVersion)
+	}
+	if want := []ParseField{{Name: "tool", Value: "Bash(go test ./...)"}}; !reflect.DeepEqual(rep.Fields, want) {
+		t.Errorf("fields = %+v, want %+v", rep.Fields, want)
+	}
+	if len(rep.Lines) != len(lines) {
+		t.Fatalf("got %d lines, want %d", len(rep.Lines), len(lines))
+	}
+	for i, l := range rep.Lines {
+		wantFields := i == 1
+		if (len(l.Fields) > 0) != wantFields {
+			t.Errorf("line %d %q credited with %v", i, l.Text, l.Fields)
+		}
+		wantChrome := i >= 3
+		if l.Chrome != wantChrome {
+			t.Errorf("line %d %q chrome = %v, want %v", i, l.Text, l.Chrome, wantChrome)
+		}
+	}
+}
+
+func TestParseReport_Startup(t *testing.T) {
+	now := time.Now()
+	m := &Model{}
+	rep := m.parseReport("gt-gastown-crew-max", []string{"Connecting to MCP servers…", "❯ "}, now.Add(-10*time.Second), now)
+	if want := []ParseField{{Name: "startup", Value: "loading MCP servers"}}; !reflect.DeepEqual(rep.Fields, want) {
+		t.Errorf("fields = %+v, want %+v", rep.Fields, want)
+	}
+	if got := rep.Lines[0].Fields; !reflect.DeepEqual(got, []string{"startup"}) {
+		t.Errorf("boot line credited with %v", got)
+	}
+}
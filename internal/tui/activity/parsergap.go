@@ -0,0 +1,154 @@
+package activity
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Parser gap check (gt top --parser-gap-check, town settings
+// top.parser_gap_check): an agent that keeps producing output while the
+// pane parsers derive nothing from it is most likely running a TUI version
+// they don't know. gt top logs such gaps to a local file, with a fingerprint
+// of the pane's shape rather than its text, for attaching to a parser bug
+// report. Nothing is sent anywhere.
+
+// ParserGapFileName is the file under <town>/.runtime/ gt top appends
+// parser gaps to, one JSON object per line.
+const ParserGapFileName = "top-parser-gaps.jsonl"
+
+const (
+	// parserGapAfter is how long an agent must show output with nothing
+	// parsed before a gap is logged.
+	parserGapAfter = 10 * time.Minute
+
+	// parserGapActive is how recent the agent's last output must be for it
+	// to count as producing output.
+	parserGapActive = 30 * time.Second
+
+	// parserGapLines is how many trailing non-blank lines are fingerprinted.
+	parserGapLines = 15
+
+	// parserGapWidth caps each fingerprinted line.
+	parserGapWidth = 80
+)
+
+// ParserGap is one logged parser gap.
+type ParserGap struct {
+	Time         time.Time `json:"time"`
+	Role         string    `json:"role,omitempty"`
+	AgentType    string    `json:"agent_type"`
+	AgentVersion string    `json:"agent_version,omitempty"`
+	Hash         string    `json:"hash"`  // short hash of Shape, to tell reports of the same layout apart
+	Shape        []string  `json:"shape"` // the pane's tail with text masked (paneShape)
+}
+
+// ParserGapPath returns the parser gap log path for a town.
+func ParserGapPath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), ParserGapFileName)
+}
+
+// SetParserGapCheck turns the parser gap check on or off.
+func (m *Model) SetParserGapCheck(on bool) {
+	m.parserGapCheck = on
+}
+
+// checkParserGap tracks how long the agent has produced output with nothing
+// parsed from its pane, and logs a gap once that passes parserGapAfter.
+// Called with each fresh capture, after parsing.
+func (m *Model) checkParserGap(a *AgentLight, lines []string, now time.Time) {
+	if len(parsedFields(a)) > 0 || a.apiLive {
+		a.parserGapSince, a.parserGapLogged = time.Time{}, false
+		return
+	}
+	if now.Sub(a.LastChangeTime) >= parserGapActive {
+		return // quiet: nothing to parse, so no evidence either way
+	}
+	if a.parserGapSince.IsZero() {
+		a.parserGapSince = now
+		return
+	}
+	if a.parserGapLogged || now.Sub(a.parserGapSince) < parserGapAfter {
+		return
+	}
+	a.parserGapLogged = true
+
+	gap := ParserGap{Time: now, Role: a.Role, AgentType: a.AgentType, AgentVersion: a.AgentVersion, Shape: paneShape(lines)}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.Join(gap.Shape, "\n")))
+	gap.Hash = fmt.Sprintf("%08x", h.Sum32())
+
+	m.flashMessage = fmt.Sprintf("parser gap: %s has shown output for %s with no status parsed", a.SessionName, parserGapAfter)
+	if m.townRoot != "" {
+		if err := appendParserGap(ParserGapPath(m.townRoot), gap); err != nil {
+			m.flashMessage += " (logging it failed: " + err.Error() + ")"
+		} else {
+			m.flashMessage += " (see .runtime/" + ParserGapFileName + ")"
+		}
+	}
+	m.flashTime = now
+}
+
+// appendParserGap appends one gap to the log.
+func appendParserGap(path string, gap ParserGap) error {
+	data, err := json.Marshal(gap)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// paneShape masks the pane's last non-blank lines down to their layout:
+// runs of letters become "a", runs of digits "9", runs of spaces one space,
+// while the symbols, box drawing and spinners a parser keys on are kept.
+// "⏺ Bash(git push origin main)" becomes "⏺ a(a a a a)".
+func paneShape(lines []string) []string {
+	var tail []string
+	for i := len(lines) - 1; i >= 0 && len(tail) < parserGapLines; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			tail = append(tail, lines[i])
+		}
+	}
+	shape := make([]string, 0, len(tail))
+	for i := len(tail) - 1; i >= 0; i-- {
+		var b strings.Builder
+		var prev rune
+		n := 0
+		for _, r := range strings.TrimRight(tail[i], " ") {
+			switch {
+			case unicode.IsLetter(r):
+				r = 'a'
+			case unicode.IsDigit(r):
+				r = '9'
+			case unicode.IsSpace(r):
+				r = ' '
+			}
+			if (r == 'a' || r == '9' || r == ' ') && r == prev {
+				continue
+			}
+			prev = r
+			if n++; n > parserGapWidth {
+				break
+			}
+			b.WriteRune(r)
+		}
+		shape = append(shape, b.String())
+	}
+	return shape
+}
@@ -0,0 +1,78 @@
+package activity
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPaneShape(t *testing.T) {
+	got := paneShape([]string{"", "⏺ Bash(git push origin main)", "  ⎿  42 files   ", "", ""})
+	want := []string{"⏺ a(a a a a)", " ⎿ 9 a"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("paneShape = %q, want %q", got, want)
+	}
+}
+
+func TestCheckParserGap(t *testing.T) {
+	townRoot := t.TempDir()
+	m := &Model{townRoot: townRoot}
+	m.SetParserGapCheck(true)
+	a := &AgentLight{SessionName: "gt-gastown-Toast", Role: "polecat", AgentType: "newagent"}
+	lines := []string{"» working on secret-project.go"}
+
+	start := time.Now()
+	poll := func(at time.Time) {
+		a.LastChangeTime = at // output keeps coming
+		m.checkParserGap(a, lines, at)
+	}
+	poll(start)
+	poll(start.Add(parserGapAfter - time.Second))
+	if _, err := os.Stat(ParserGapPath(townRoot)); err == nil {
+		t.Fatal("gap logged before parserGapAfter")
+	}
+	poll(start.Add(parserGapAfter))
+	poll(start.Add(parserGapAfter + time.Minute)) // logged once per gap
+
+	data, err := os.ReadFile(ParserGapPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "\n"); n != 1 {
+		t.Fatalf("%d gaps logged, want 1", n)
+	}
+	if strings.Contains(string(data), "secret") || strings.Contains(string(data), "Toast") {
+		t.Errorf("gap log leaks pane text or names: %s", data)
+	}
+	var gap ParserGap
+	if err := json.Unmarshal(data, &gap); err != nil {
+		t.Fatal(err)
+	}
+	if gap.AgentType != "newagent" || gap.Hash == "" || len(gap.Shape) != 1 {
+		t.Errorf("gap = %+v", gap)
+	}
+	if !strings.Contains(m.flashMessage, "parser gap") {
+		t.Errorf("flash = %q", m.flashMessage)
+	}
+
+	// A parsed status ends the gap.
+	a.StatusText = "Thinking…"
+	poll(start.Add(parserGapAfter + 2*time.Minute))
+	if !a.parserGapSince.IsZero() || a.parserGapLogged {
+		t.Error("a parsed status didn't end the gap")
+	}
+}
+
+func TestCheckParserGapIgnoresQuietAgents(t *testing.T) {
+	m := &Model{}
+	a := &AgentLight{SessionName: "gt-gastown-Toast"}
+	start := time.Now()
+	for i := 0; i <= 20; i++ {
+		m.checkParserGap(a, []string{"❯ "}, start.Add(time.Duration(i)*time.Minute))
+	}
+	if !a.parserGapSince.IsZero() || m.flashMessage != "" {
+		t.Error("an agent with no output counted as a parser gap")
+	}
+}
@@ -0,0 +1,134 @@
+package activity
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/agentparse"
+)
+
+// Pane parsers: each agent type's pane is read by an agentparse.PaneParser.
+// Parsers registered with agentparse are tried first, then the built-in
+// ones below, so supporting a new agent means registering a parser, not
+// patching the dispatch here. The built-ins also parse straight into the
+// AgentLight (lightParser), since they keep sticky state (tool errors,
+// context and session-limit TTLs) and pick up chrome from town settings
+// that a bare Status can't carry.
+
+// lightParser is a PaneParser that can parse into the agent's light
+// directly.
+type lightParser interface {
+	parseLight(a *AgentLight, lines []string)
+}
+
+// builtinParsers are the parsers gt top ships, in detection order. Claude
+// Code comes last: it claims any pane no other parser recognizes.
+var builtinParsers = []struct {
+	agentType string
+	parser    agentparse.PaneParser
+}{
+	{"opencode", openCodeParser{}},
+	{"claude", claudeParser{}},
+}
+
+// claudeParser reads Claude Code panes (parsePaneContentClaude). It's also
+// the parser for agents of a type no parser is registered for.
+type claudeParser struct{}
+
+func (claudeParser) Detect([]string) bool { return true }
+
+func (p claudeParser) Parse(lines []string) agentparse.Status {
+	a := &AgentLight{AgentType: "claude"}
+	p.parseLight(a, lines)
+	return paneStatus(a)
+}
+
+func (claudeParser) parseLight(a *AgentLight, lines []string) { parsePaneContentClaude(a, lines) }
+
+// openCodeParser reads OpenCode panes (parsePaneContentOpenCode).
+type openCodeParser struct{}
+
+// Detect looks for OpenCode's signatures: its name and version in the
+// bottom bar ("• OpenCode 1.1.60"), or the bar's key hints.
+func (openCodeParser) Detect(lines []string) bool {
+	return slices.ContainsFunc(lines, func(line string) bool {
+		return strings.Contains(line, "OpenCode") ||
+			(strings.Contains(line, "ctrl+p commands") && strings.Contains(line, "tab agents"))
+	})
+}
+
+func (p openCodeParser) Parse(lines []string) agentparse.Status {
+	a := &AgentLight{AgentType: "opencode"}
+	p.parseLight(a, lines)
+	return paneStatus(a)
+}
+
+func (openCodeParser) parseLight(a *AgentLight, lines []string) { parsePaneContentOpenCode(a, lines) }
+
+// paneParser returns the parser for an agent type: the registered one, else
+// the built-in, else Claude Code's.
+func paneParser(agentType string) agentparse.PaneParser {
+	if p, ok := agentparse.Lookup(agentType); ok {
+		return p
+	}
+	for _, b := range builtinParsers {
+		if b.agentType == agentType {
+			return b.parser
+		}
+	}
+	return claudeParser{}
+}
+
+// detectAgentTypeFromPane identifies the agent type by inspecting pane
+// content: the first registered parser to recognize it, else the first
+// built-in. Claude Code recognizes everything, so this is never "".
+func detectAgentTypeFromPane(lines []string) string {
+	if t := agentparse.Detect(lines); t != "" {
+		return t
+	}
+	for _, b := range builtinParsers {
+		if b.parser.Detect(lines) {
+			return b.agentType
+		}
+	}
+	return "claude"
+}
+
+// applyPaneStatus sets the agent's pane-derived fields from a registered
+// parser's Status. Context and session limits are sticky, as with the
+// built-in parsers: a pane that stops showing them doesn't clear them.
+func applyPaneStatus(a *AgentLight, st agentparse.Status) {
+	a.StatusText = st.Status
+	a.CurrentTool = st.Tool
+	a.toolFromPane = st.Tool != ""
+	a.busyInPane = st.Busy
+	a.WaitingForHuman = st.WaitingReason != ""
+	a.WaitingReason = st.WaitingReason
+	a.RateLimited = st.RateLimited
+	a.APIError = st.APIError
+	a.HitLimit = st.HitLimit
+	a.LimitResetInfo = st.LimitReset
+	if st.ContextLeft > 0 {
+		a.observeContext(st.ContextLeft)
+	}
+	if st.SessionLimit > 0 {
+		a.observeSessionLimit(st.SessionLimit, st.SessionReset)
+	}
+}
+
+// paneStatus returns the agent's pane-derived fields as a Status.
+func paneStatus(a *AgentLight) agentparse.Status {
+	return agentparse.Status{
+		Status:        a.StatusText,
+		Tool:          a.CurrentTool,
+		Busy:          a.busyInPane,
+		WaitingReason: a.WaitingReason,
+		RateLimited:   a.RateLimited,
+		APIError:      a.APIError,
+		HitLimit:      a.HitLimit,
+		LimitReset:    a.LimitResetInfo,
+		ContextLeft:   a.ContextPercent,
+		SessionLimit:  a.SessionLimitPct,
+		SessionReset:  a.SessionLimitReset,
+	}
+}
@@ -0,0 +1,139 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/agentparse"
+)
+
+// aiderParser is a parser a town might register for an agent gt top
+// doesn't ship one for.
+type aiderParser struct{}
+
+func (aiderParser) Detect(lines []string) bool {
+	for _, l := range lines {
+		if strings.HasPrefix(l, "Aider v") {
+			return true
+		}
+	}
+	return false
+}
+
+func (aiderParser) Parse(lines []string) agentparse.Status {
+	st := agentparse.Status{ContextLeft: 70}
+	for _, l := range lines {
+		switch {
+		case strings.HasPrefix(l, "Running "):
+			st.Tool = "Bash(" + strings.TrimPrefix(l, "Running ") + ")"
+			st.Busy = true
+		case strings.Contains(l, "(Y)es/(N)o"):
+			st.WaitingReason = "confirm"
+		}
+	}
+	return st
+}
+
+func TestDetectAgentTypeFromPane(t *testing.T) {
+	tests := []struct {
+		lines []string
+		want  string
+	}{
+		{[]string{"▣  Build · claude-opus-4.6", "• OpenCode 1.1.60"}, "opencode"},
+		{[]string{"ctrl+t variants  tab agents  ctrl+p commands"}, "opencode"},
+		{[]string{"✻ Thinking… (12s)", "❯ "}, "claude"},
+		{[]string{"Aider v0.80.0"}, "claude"}, // nothing registered for it yet
+	}
+	for _, tt := range tests {
+		if got := detectAgentTypeFromPane(tt.lines); got != tt.want {
+			t.Errorf("detectAgentTypeFromPane(%q) = %q, want %q", tt.lines, got, tt.want)
+		}
+	}
+}
+
+func TestRegisteredPaneParser(t *testing.T) {
+	agentparse.Register("aider", aiderParser{})
+	t.Cleanup(func() { agentparse.Unregister("aider") })
+
+	lines := []string{"Aider v0.80.0", "Running go test ./...", "Apply edit? (Y)es/(N)o"}
+	a := &AgentLight{SessionName: "gt-gastown-crew-joe", Name: "joe"}
+	parsePaneContent(a, lines)
+	if a.AgentType != "aider" {
+		t.Fatalf("AgentType = %q, want aider", a.AgentType)
+	}
+	if a.CurrentTool != "Bash(go test ./...)" || !a.busyInPane || !a.WaitingForHuman || a.WaitingReason != "confirm" || a.ContextPercent != 70 {
+		t.Errorf("parsed %+v", paneStatus(a))
+	}
+
+	// The tool the pane showed survives the event pass that clears other
+	// non-Claude agents' tools.
+	m := &Model{agents: []*AgentLight{a}}
+	m.applyToolEvents()
+	if a.CurrentTool != "Bash(go test ./...)" {
+		t.Errorf("applyToolEvents cleared the pane's tool: %q", a.CurrentTool)
+	}
+
+	// The next capture without a tool clears it; context is sticky.
+	parsePaneContent(a, []string{"Aider v0.80.0", "> "})
+	m.applyToolEvents()
+	if a.CurrentTool != "" || a.WaitingForHuman || a.ContextPercent != 70 {
+		t.Errorf("after an idle capture: tool %q, waiting %v, context %d", a.CurrentTool, a.WaitingForHuman, a.ContextPercent)
+	}
+}
+
+func TestRegisteredPaneParser_ReplacesBuiltin(t *testing.T) {
+	agentparse.Register("opencode", aiderParser{})
+	t.Cleanup(func() { agentparse.Unregister("opencode") })
+
+	a := &AgentLight{AgentType: "opencode"}
+	parsePaneContent(a, []string{"• OpenCode 1.1.60", "Running make"})
+	if a.CurrentTool != "Bash(make)" {
+		t.Errorf("CurrentTool = %q, want the registered parser's", a.CurrentTool)
+	}
+}
+
+// deepParser reads further up the pane than the default.
+type deepParser struct{ aiderParser }
+
+func (deepParser) CaptureDepth() int { return 40 }
+
+func TestRegisteredPaneParser_CaptureDepth(t *testing.T) {
+	agentparse.Register("aider", aiderParser{})
+	agentparse.Register("goose", deepParser{})
+	t.Cleanup(func() {
+		agentparse.Unregister("aider")
+		agentparse.Unregister("goose")
+	})
+	if got := captureDepth("aider"); got != claudeCaptureDepth {
+		t.Errorf("captureDepth(aider) = %d, want the default %d", got, claudeCaptureDepth)
+	}
+	if got := captureDepth("goose"); got != 40 {
+		t.Errorf("captureDepth(goose) = %d, want the parser's 40", got)
+	}
+	if got := captureDepth("opencode"); got != openCodeCaptureDepth {
+		t.Errorf("captureDepth(opencode) = %d", got)
+	}
+}
+
+func TestBuiltinParsers_Parse(t *testing.T) {
+	st := claudeParser{}.Parse([]string{
+		"⏺ Bash(go test ./...)",
+		"Context left until auto-compact: 20%",
+	})
+	if st.Tool != "Bash(go test ./...)" || st.ContextLeft != 20 {
+		t.Errorf("claude Parse = %+v", st)
+	}
+
+	// Every built-in answers through the same interface gt top dispatches on.
+	for _, b := range builtinParsers {
+		if _, ok := b.parser.(lightParser); !ok {
+			t.Errorf("built-in %s parser can't parse into a light", b.agentType)
+		}
+		if got := paneParser(b.agentType); got != b.parser {
+			t.Errorf("paneParser(%q) = %T", b.agentType, got)
+		}
+	}
+	if _, ok := paneParser("codex").(claudeParser); !ok {
+		t.Error("an agent type with no parser should get Claude's")
+	}
+}
@@ -0,0 +1,72 @@
+package activity
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pinnedHeaderStyle marks the pinned section apart from rig headers.
+var pinnedHeaderStyle = lipgloss.NewStyle().
+	Bold(true).
+	Foreground(colorWarm).
+	PaddingLeft(1)
+
+// togglePinHovered pins or unpins the hovered agent. Pins are keyed by session
+// name, so they survive the agent's session restarting and reappearing.
+func (m *Model) togglePinHovered() {
+	a := m.hoveredAgent
+	if a == nil {
+		m.flashMessage = "hover an agent to pin it"
+		m.flashTime = time.Now()
+		return
+	}
+	if m.pinned == nil {
+		m.pinned = make(map[string]bool)
+	}
+	if m.pinned[a.SessionName] {
+		delete(m.pinned, a.SessionName)
+		m.flashMessage = "Unpinned " + a.SessionName
+	} else {
+		m.pinned[a.SessionName] = true
+		m.flashMessage = "Pinned " + a.SessionName
+	}
+	m.flashTime = time.Now()
+}
+
+// pinnedAgents returns the live pinned agents in rig display order.
+func (m *Model) pinnedAgents() []*AgentLight {
+	if len(m.pinned) == 0 {
+		return nil
+	}
+	var agents []*AgentLight
+	for _, rig := range m.rigs {
+		for _, a := range m.agentsForRig(rig) {
+			if m.pinned[a.SessionName] {
+				agents = append(agents, a)
+			}
+		}
+	}
+	return agents
+}
+
+// unpinnedAgents filters pinned agents out of a rig's list so each agent is
+// rendered (and hover-tracked) exactly once.
+func (m *Model) unpinnedAgents(agents []*AgentLight) []*AgentLight {
+	if len(m.pinned) == 0 {
+		return agents
+	}
+	var out []*AgentLight
+	for _, a := range agents {
+		if !m.pinned[a.SessionName] {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// renderPinnedWithPositions renders the pinned section, or "" when nothing
+// pinned is currently running.
+func (m *Model) renderPinnedWithPositions(currentY *int) string {
+	return m.renderPanelWithPositions(pinnedHeaderStyle.Render("📌 pinned"), m.pinnedAgents(), currentY)
+}
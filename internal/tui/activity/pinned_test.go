@@ -0,0 +1,61 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTogglePinHovered(t *testing.T) {
+	toast := &AgentLight{Name: "Toast", Rig: "gastown", Role: "polecat", SessionName: "gt-Toast"}
+	m := &Model{agents: []*AgentLight{toast}, rigs: []string{"gastown"}}
+
+	m.togglePinHovered()
+	if len(m.pinned) != 0 || m.flashMessage == "" {
+		t.Fatalf("pin with no hover: pinned=%v flash=%q, want nothing pinned and a hint", m.pinned, m.flashMessage)
+	}
+
+	m.hoveredAgent = toast
+	m.togglePinHovered()
+	if !m.pinned["gt-Toast"] {
+		t.Fatal("expected gt-Toast pinned")
+	}
+	m.togglePinHovered()
+	if m.pinned["gt-Toast"] {
+		t.Fatal("expected gt-Toast unpinned after second toggle")
+	}
+}
+
+func TestPinnedAgents_MovedOutOfRigPanel(t *testing.T) {
+	toast := &AgentLight{Name: "Toast", Rig: "gastown", Role: "polecat", SessionName: "gt-Toast"}
+	nux := &AgentLight{Name: "Nux", Rig: "gastown", Role: "polecat", SessionName: "gt-Nux"}
+	witness := &AgentLight{Name: "witness", Rig: "gastown", Role: "witness", SessionName: "gt-witness"}
+	m := &Model{
+		agents:      []*AgentLight{toast, nux, witness},
+		rigs:        []string{"gastown"},
+		pinned:      map[string]bool{"gt-Toast": true, "gt-gone": true},
+		width:       120,
+		height:      40,
+		totalAgents: 3,
+	}
+
+	pinned := m.pinnedAgents()
+	if len(pinned) != 1 || pinned[0] != toast {
+		t.Fatalf("pinnedAgents = %v, want only Toast (stale pins ignored)", pinned)
+	}
+	rest := m.unpinnedAgents(m.agentsForRig("gastown"))
+	if len(rest) != 2 {
+		t.Fatalf("unpinnedAgents returned %d agents, want 2", len(rest))
+	}
+
+	out := m.render()
+	if !strings.Contains(out, "pinned") {
+		t.Errorf("render missing pinned section:\n%s", out)
+	}
+	if strings.Count(out, "Toast") != 1 {
+		t.Errorf("pinned agent should render exactly once:\n%s", out)
+	}
+	// Pinned section comes first, so the pinned agent sits above the rig's agents.
+	if toast.renderY <= 0 || toast.renderY >= witness.renderY {
+		t.Errorf("Toast renderY=%d, witness renderY=%d; want pinned row above rig rows", toast.renderY, witness.renderY)
+	}
+}
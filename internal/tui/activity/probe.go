@@ -0,0 +1,176 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Probe timing: how long to wait for Claude to answer a slash command, and
+// how often to re-capture the pane while waiting.
+const (
+	probeTimeout  = 5 * time.Second
+	probeInterval = 250 * time.Millisecond
+)
+
+// probeResult is Claude's answer to /cost or /status, shown in an overlay.
+type probeResult struct {
+	session string
+	command string   // "/cost" or "/status"
+	lines   []string // "Key: value" lines from the response
+	err     string
+}
+
+// probeDoneMsg delivers a finished probe.
+type probeDoneMsg struct {
+	result probeResult
+}
+
+// probeHovered asks the hovered Claude agent for /cost or /status. Only an
+// idle agent is probed: typing into a busy one would queue the command as
+// its next prompt.
+func (m *Model) probeHovered(command string) tea.Cmd {
+	a := m.hoveredAgent
+	if a == nil {
+		return nil
+	}
+	var reason string
+	switch {
+	case m.redact:
+		reason = command + " output isn't shown in redacted mode"
+	case !isClaudeAgent(a.AgentType):
+		reason = command + " is only supported for Claude agents"
+	case a.inContainer():
+		reason = command + " needs a tmux session; " + a.SessionName + " runs in a container"
+	case a.Level == LevelWaitingForHuman || a.Level == LevelStarting || a.Level == LevelHitLimit:
+		reason = a.SessionName + " can't take commands right now"
+	case a.StatusText != "" || a.CurrentTool != "":
+		reason = a.SessionName + " is busy; try again when it is idle"
+	}
+	if reason != "" {
+		m.flashMessage = reason
+		m.flashTime = time.Now()
+		return nil
+	}
+	m.flashMessage = "asking " + a.SessionName + " for " + command + "…"
+	m.flashTime = time.Now()
+	session := a.SessionName
+	m.touchedByHuman(session)
+	return func() tea.Msg {
+		return probeDoneMsg{result: runProbe(tmux.NewTmux(), session, command)}
+	}
+}
+
+// runProbe types command at the session's empty prompt, waits for the
+// response, and dismisses the /status dialog so the prompt is back as it was.
+func runProbe(t *tmux.Tmux, session, command string) probeResult {
+	res := probeResult{session: session, command: command}
+	lines, err := t.CapturePaneLines(session, 50)
+	if err != nil {
+		res.err = err.Error()
+		return res
+	}
+	if !promptEmpty(lines) {
+		res.err = "prompt has pending input; not typing over it"
+		return res
+	}
+	if err := t.SendKeys(session, command); err != nil {
+		res.err = err.Error()
+		return res
+	}
+	if command == "/status" {
+		defer func() { _ = t.SendKeysRaw(session, "Escape") }()
+	}
+
+	deadline := time.Now().Add(probeTimeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(probeInterval)
+		lines, err = t.CapturePaneLines(session, 80)
+		if err != nil {
+			continue
+		}
+		if out := parseProbeOutput(lines, command); len(out) > 0 {
+			res.lines = out
+			return res
+		}
+	}
+	res.err = "no response to " + command + " within " + probeTimeout.String()
+	return res
+}
+
+// promptEmpty reports whether the pane's Claude input prompt ("❯") is
+// showing with nothing typed after it.
+func promptEmpty(lines []string) bool {
+	for i := len(lines) - 1; i >= 0; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "❯") {
+			return strings.TrimSpace(strings.TrimPrefix(trimmed, "❯")) == ""
+		}
+	}
+	return false
+}
+
+// parseProbeOutput extracts the "Key: value" lines Claude printed after the
+// last echo of command, e.g. "Total cost: $0.0827" from /cost or
+// "Model: opus" from /status. Subscription accounts answer /cost with a
+// sentence instead, which is returned as is.
+func parseProbeOutput(lines []string, command string) []string {
+	start := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.Contains(lines[i], command) {
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+
+	var out []string
+	for _, line := range lines[start:] {
+		trimmed := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "⎿"))
+		if strings.HasPrefix(trimmed, "❯") || isSeparatorLine(trimmed) {
+			break // back at the prompt (or the dialog's bottom edge)
+		}
+		trimmed = strings.Trim(trimmed, "│ ")
+		if trimmed == "" || isChromeLine(trimmed) {
+			continue
+		}
+		if key, val, ok := strings.Cut(trimmed, ":"); ok && val != "" {
+			out = append(out, strings.TrimSpace(key)+": "+strings.Join(strings.Fields(val), " "))
+		} else if strings.Contains(strings.ToLower(trimmed), "subscription") {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
+
+// handleProbeDone shows the probe result in the overlay, or flashes the
+// error.
+func (m *Model) handleProbeDone(msg probeDoneMsg) {
+	if msg.result.err != "" {
+		m.flashMessage = msg.result.command + " " + msg.result.session + ": " + msg.result.err
+		m.flashTime = time.Now()
+		return
+	}
+	m.probe = &msg.result
+	m.flashMessage = ""
+}
+
+var probeBoxStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(colorTitle).
+	Padding(0, 1)
+
+// renderProbe renders the probe overlay shown in place of the rig panels.
+func (m *Model) renderProbe() string {
+	p := m.probe
+	body := []string{titleStyle.Render(fmt.Sprintf("%s · %s", p.command, p.session)), ""}
+	body = append(body, p.lines...)
+	body = append(body, "", helpStyle.Render("any key: close"))
+	return probeBoxStyle.Render(strings.Join(body, "\n"))
+}
@@ -0,0 +1,84 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Progress: an agent running a long, predictable task (a test suite, a
+// migration, shards of a batch) can say how far through it is with
+// agent_progress events, or a progress field on its tool events. gt top
+// draws the latest report as a mini bar on the agent's line, so a long run
+// shows real headway rather than just "active". The bar stays until the
+// agent goes idle or restarts, or reports nothing for progressStale.
+// Progress is matched for every agent, Claude included: it comes from
+// whatever the agent runs (gt top emit agent_progress ...), not a plugin.
+
+// progressStale is how long a progress report is shown without another.
+// It matches the window agent_progress events are read over.
+const progressStale = 10 * time.Minute
+
+// progressWidth is the number of cells in the mini progress bar.
+const progressWidth = 5
+
+// applyProgressEvents applies the progress reports, idle and restart events
+// read this poll, newest last, and drops reports gone stale.
+func (m *Model) applyProgressEvents(now time.Time) {
+	idx := eventIndex{bySession: make(map[string]*AgentLight), byID: make(map[string]*AgentLight)}
+	for _, a := range m.agents {
+		idx.bySession[a.SessionName] = a
+		if a.AgentID != "" {
+			idx.byID[a.AgentID] = a
+		}
+	}
+	for _, evt := range m.recentToolEvents {
+		if evt.Progress < 0 && evt.EventType != events.TypeAgentIdle && evt.EventType != events.TypeAgentRestarted {
+			continue
+		}
+		a := m.eventAgent(idx, evt)
+		if a == nil || evt.Timestamp.Before(a.progressAt) {
+			continue
+		}
+		if evt.Progress < 0 {
+			a.clearProgress(evt.Timestamp)
+			continue
+		}
+		a.Progress = evt.Progress
+		a.ProgressLabel = evt.ProgressLabel
+		a.progressAt = evt.Timestamp
+		a.progressOn = true
+	}
+	for _, a := range m.agents {
+		if a.progressOn && now.Sub(a.progressAt) > progressStale {
+			a.progressOn = false
+		}
+	}
+}
+
+// clearProgress hides the agent's progress, and ignores reports older than
+// at.
+func (a *AgentLight) clearProgress(at time.Time) {
+	a.Progress = 0
+	a.ProgressLabel = ""
+	a.progressAt = at
+	a.progressOn = false
+}
+
+// renderProgressIndicator renders the agent's progress as a mini bar, its
+// percentage and label ("▰▰▰▱▱ 60% through test suite"); compact drops the
+// label. Empty when the agent has reported none.
+func renderProgressIndicator(a *AgentLight, compact bool) string {
+	if !a.progressOn {
+		return ""
+	}
+	filled := (a.Progress*progressWidth + 50) / 100
+	text := barActiveStyle.Render(strings.Repeat("▰", filled)) +
+		statusDimStyle.Render(strings.Repeat("▱", progressWidth-filled)+fmt.Sprintf(" %d%%", a.Progress))
+	if a.ProgressLabel != "" && !compact {
+		text += statusDimStyle.Render(" " + truncateWidth(a.ProgressLabel, 24, "…"))
+	}
+	return text
+}
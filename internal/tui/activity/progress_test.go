@@ -0,0 +1,102 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestProgressEvents(t *testing.T) {
+	townRoot := t.TempDir()
+	toast := &AgentLight{SessionName: "gt-gastown-Toast", AgentID: "gastown/polecats/Toast", AgentType: "opencode", Name: "Toast", Rig: "gastown"}
+	joe := &AgentLight{SessionName: "gt-gastown-crew-joe", AgentID: "gastown/crew/joe", AgentType: "claude", Name: "joe", Rig: "gastown"}
+	m := &Model{townRoot: townRoot, width: 200, pollInterval: time.Second, agents: []*AgentLight{toast, joe}}
+	sessions := []sessionInfo{{name: toast.SessionName, activity: 1}, {name: joe.SessionName, activity: 1}}
+
+	now := time.Now()
+	writeEvent(t, townRoot, now.Add(-3*time.Minute), events.TypeAgentProgress, "crew", events.AgentProgressPayload(joe.SessionName, 20, "through migrations"))
+	writeEvent(t, townRoot, now.Add(-2*time.Minute), events.TypeAgentProgress, "crew", events.AgentProgressPayload(joe.SessionName, 60, "through test suite"))
+	writeEvent(t, townRoot, now.Add(-time.Second), "tool_started", "polecat",
+		map[string]interface{}{"session": toast.SessionName, "tool": "Bash(make)", "progress": 3, "progress_label": "shards"})
+	m.updateAgents(sessions)
+
+	// Claude agents report progress too: it doesn't come from a plugin.
+	if !joe.progressOn || joe.Progress != 60 || joe.ProgressLabel != "through test suite" {
+		t.Fatalf("joe progress = %v %d %q, want the latest report", joe.progressOn, joe.Progress, joe.ProgressLabel)
+	}
+	if !toast.progressOn || toast.Progress != 3 {
+		t.Errorf("toast progress = %v %d, want 3 from its tool event", toast.progressOn, toast.Progress)
+	}
+	if got := m.renderLight(joe); !strings.Contains(got, "▰▰▰▱▱") || !strings.Contains(got, "60% through test suite") {
+		t.Errorf("light %q, want a 60%% bar and label", got)
+	}
+	if st := joe.status(); st.Progress == nil || *st.Progress != 60 || st.ProgressLabel != "through test suite" {
+		t.Errorf("status progress = %v %q", st.Progress, st.ProgressLabel)
+	}
+
+	// Still shown on the next poll; gone once the agent goes idle.
+	writeEvent(t, townRoot, time.Now(), events.TypeAgentIdle, "polecat", events.AgentIdlePayload(toast.SessionName))
+	m.updateAgents(sessions)
+	if !joe.progressOn {
+		t.Error("joe's progress dropped on a poll without a report")
+	}
+	if toast.progressOn {
+		t.Error("toast's progress survived agent_idle")
+	}
+	if st := toast.status(); st.Progress != nil {
+		t.Errorf("status progress = %d after idle, want none", *st.Progress)
+	}
+}
+
+func TestProgressEvents_Stale(t *testing.T) {
+	joe := &AgentLight{SessionName: "gt-gastown-crew-joe", Name: "joe"}
+	now := time.Now()
+	m := &Model{agents: []*AgentLight{joe}, recentToolEvents: []toolEvent{
+		{Timestamp: now.Add(-progressStale - time.Minute), Session: joe.SessionName, EventType: events.TypeAgentProgress, Progress: 40},
+	}}
+	m.applyProgressEvents(now)
+	if joe.progressOn {
+		t.Error("progress older than progressStale is shown")
+	}
+}
+
+func TestProgressEvents_RestartClears(t *testing.T) {
+	joe := &AgentLight{SessionName: "gt-gastown-crew-joe", Name: "joe"}
+	now := time.Now()
+	m := &Model{agents: []*AgentLight{joe}, recentToolEvents: []toolEvent{
+		{Timestamp: now.Add(-time.Minute), Session: joe.SessionName, EventType: events.TypeAgentProgress, Progress: 40},
+	}}
+	m.applyProgressEvents(now)
+	joe.resetSticky()
+	m.applyProgressEvents(now)
+	if joe.progressOn {
+		t.Error("the old session's progress came back after a restart")
+	}
+}
+
+func TestRenderProgressIndicator(t *testing.T) {
+	tests := []struct {
+		pct     int
+		label   string
+		compact bool
+		want    string
+	}{
+		{0, "", false, "▱▱▱▱▱ 0%"},
+		{60, "through test suite", false, "▰▰▰▱▱ 60% through test suite"},
+		{60, "through test suite", true, "▰▰▰▱▱ 60%"},
+		{100, "", false, "▰▰▰▰▰ 100%"},
+		{50, "through a label far too long to fit the row", false, "▰▰▰▱▱ 50% through a label far too…"},
+	}
+	for _, tt := range tests {
+		a := &AgentLight{Progress: tt.pct, ProgressLabel: tt.label, progressOn: true}
+		if got := ansi.Strip(renderProgressIndicator(a, tt.compact)); got != tt.want {
+			t.Errorf("renderProgressIndicator(%d, %q, %v) = %q, want %q", tt.pct, tt.label, tt.compact, got, tt.want)
+		}
+	}
+	if got := renderProgressIndicator(&AgentLight{}, false); got != "" {
+		t.Errorf("no progress rendered %q", got)
+	}
+}
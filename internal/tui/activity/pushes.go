@@ -0,0 +1,93 @@
+package activity
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Push and PR badges: when an agent's pane shows a successful git push or a
+// freshly created pull request, its line gets a small ↑ badge (↑#123 with
+// the PR number), so reviewers can see which agents have work ready for
+// review. The badge is bright once the agent has gone quiet, and dim while
+// it is still iterating. It is sticky, as the pane scrolls the output away,
+// but only for the bead the agent was on when it pushed.
+
+// prURLPattern matches a pull request (GitHub) or merge request (GitLab)
+// URL alone on a line, as gh pr create and glab mr create print it. A URL
+// inside other text is more likely a link being discussed.
+var prURLPattern = regexp.MustCompile(`^https?://\S+/(?:pull|-/merge_requests)/(\d+)/?$`)
+
+// pushRefPattern matches a ref line of git push's summary that was
+// updated: "   1a2b3c4..5d6e7f8  main -> main", " * [new branch]      x -> x"
+// or a forced " + 1a2b3c4...5d6e7f8 x -> x (forced update)". Rejected refs
+// ("! [rejected]") don't match.
+var pushRefPattern = regexp.MustCompile(`^(?:\+\s+)?(?:\*\s+\[new branch\]|[0-9a-f]{7,}\.\.\.?[0-9a-f]{7,})\s+\S+\s+->\s+(\S+)`)
+
+// trackPushes records the last push and pull request shown in the pane.
+func trackPushes(a *AgentLight, lines []string) {
+	sawPushTarget := false
+	for _, line := range lines {
+		text := strings.TrimSpace(line)
+		text = strings.TrimSpace(strings.TrimPrefix(text, "⎿"))
+		if strings.HasPrefix(text, "To ") {
+			sawPushTarget = true // git push names the remote before its refs
+			continue
+		}
+		if m := prURLPattern.FindStringSubmatch(text); m != nil {
+			if n, err := strconv.Atoi(m[1]); err == nil {
+				a.PRNumber, a.pushBead = n, a.WorkBeadID
+			}
+			continue
+		}
+		m := pushRefPattern.FindStringSubmatch(text)
+		if m == nil {
+			sawPushTarget = false // the refs follow the remote line directly
+			continue
+		}
+		if sawPushTarget {
+			a.PushedBranch, a.pushBead = m[1], a.WorkBeadID
+		}
+	}
+}
+
+// showsPush reports whether the agent's push badge applies to its current
+// work.
+func (a *AgentLight) showsPush() bool {
+	return (a.PushedBranch != "" || a.PRNumber > 0) && a.pushBead == a.WorkBeadID
+}
+
+// renderPushBadge renders the ↑ badge, with the PR number when there is
+// one: bright when the agent is idle, its work waiting on review.
+func renderPushBadge(a *AgentLight) string {
+	if !a.showsPush() {
+		return ""
+	}
+	text := "↑"
+	if a.PRNumber > 0 {
+		text += fmt.Sprintf("#%d", a.PRNumber)
+	}
+	if a.Level == LevelActive || a.Level == LevelRecent {
+		return statusDimStyle.Render(text)
+	}
+	return lipgloss.NewStyle().Foreground(colorActive).Render(text)
+}
+
+// pushDetail renders the agent's push for the hover detail, e.g.
+// "pushed crew/max · PR #123", or "".
+func pushDetail(a *AgentLight) string {
+	if !a.showsPush() {
+		return ""
+	}
+	var parts []string
+	if a.PushedBranch != "" {
+		parts = append(parts, "pushed "+a.PushedBranch)
+	}
+	if a.PRNumber > 0 {
+		parts = append(parts, fmt.Sprintf("PR #%d", a.PRNumber))
+	}
+	return strings.Join(parts, " · ")
+}
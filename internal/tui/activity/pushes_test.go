@@ -0,0 +1,60 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTrackPushes(t *testing.T) {
+	a := &AgentLight{WorkBeadID: "gt-abc", Level: LevelCold}
+	trackPushes(a, []string{
+		"⏺ Bash(git push -u origin crew/max)",
+		"  ⎿  To github.com:steveyegge/gastown.git",
+		"      * [new branch]      crew/max -> crew/max",
+		"     branch 'crew/max' set up to track 'origin/crew/max'.",
+		"⏺ Bash(gh pr create --fill)",
+		"  ⎿  https://github.com/steveyegge/gastown/pull/1234",
+	})
+	if a.PushedBranch != "crew/max" || a.PRNumber != 1234 {
+		t.Fatalf("pushed %q, PR %d", a.PushedBranch, a.PRNumber)
+	}
+	if got := ansi.Strip(renderPushBadge(a)); got != "↑#1234" {
+		t.Errorf("badge = %q", got)
+	}
+	if got := pushDetail(a); got != "pushed crew/max · PR #1234" {
+		t.Errorf("detail = %q", got)
+	}
+
+	// The badge belongs to the bead that was pushed.
+	a.WorkBeadID = "gt-def"
+	if a.showsPush() {
+		t.Error("badge shown for the next bead")
+	}
+}
+
+func TestTrackPushesIgnoresLookalikes(t *testing.T) {
+	a := &AgentLight{}
+	trackPushes(a, []string{
+		"To github.com:steveyegge/gastown.git",
+		" ! [rejected]        main -> main (fetch first)",
+		"   1a2b3c4..5d6e7f8  main -> main", // not right after the remote line
+		"See https://github.com/steveyegge/gastown/pull/99 for context",
+	})
+	if a.showsPush() {
+		t.Errorf("pushed %q, PR %d from a rejected push and a mentioned link", a.PushedBranch, a.PRNumber)
+	}
+
+	trackPushes(a, []string{
+		"To https://gitlab.example.com/team/app.git",
+		" + 1a2b3c4...5d6e7f8 feature -> feature (forced update)",
+		"https://gitlab.example.com/team/app/-/merge_requests/7",
+	})
+	if a.PushedBranch != "feature" || a.PRNumber != 7 {
+		t.Errorf("gitlab: pushed %q, MR %d", a.PushedBranch, a.PRNumber)
+	}
+	if !strings.HasPrefix(ansi.Strip(renderPushBadge(a)), "↑#7") {
+		t.Errorf("badge = %q", ansi.Strip(renderPushBadge(a)))
+	}
+}
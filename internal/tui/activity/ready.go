@@ -0,0 +1,54 @@
+package activity
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Ready for work: agent plugins emit agent_idle when the agent finishes a
+// turn and sits at its prompt. Pane timestamps can't tell that apart from a
+// TUI redrawing its clock or spinner, so a non-Claude agent with a recent
+// idle event gets its own level, ready, instead of whatever the timestamps
+// say; it isn't cold or stuck, just waiting to be given something. It stays
+// ready until the next sign of work: a tool or compaction starting, or the
+// pane showing it busy.
+
+// readyGrace is how long after an idle event a busy-looking pane is taken
+// for the turn that just ended, not new work.
+const readyGrace = 3 * time.Second
+
+// trackReadyEvents records each agent's latest idle event and latest tool
+// or compaction start from the events file. Runs before levels are computed.
+func (m *Model) trackReadyEvents() {
+	idx := m.eventAgents()
+	for _, evt := range m.recentToolEvents {
+		a := m.eventAgent(idx, evt)
+		if a == nil {
+			continue
+		}
+		switch evt.EventType {
+		case events.TypeAgentIdle:
+			if evt.Timestamp.After(a.readyAt) {
+				a.readyAt = evt.Timestamp
+			}
+		case "tool_started", "compaction_started":
+			if evt.Timestamp.After(a.workAt) {
+				a.workAt = evt.Timestamp
+			}
+		}
+	}
+}
+
+// readyForWork reports whether the agent is idle at its prompt: its latest
+// idle event is newer than any sign of work since. A rate-limited agent
+// isn't ready, whatever its plugin says.
+func (m *Model) readyForWork(a *AgentLight, now time.Time) bool {
+	if a.readyAt.IsZero() || isClaudeAgent(a.AgentType) {
+		return false
+	}
+	if a.busyInPane && now.Sub(a.readyAt) > readyGrace {
+		a.workAt = now
+	}
+	return a.readyAt.After(a.workAt) && !a.RateLimited
+}
@@ -0,0 +1,76 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestReadyForWork(t *testing.T) {
+	townRoot := t.TempDir()
+	toast := &AgentLight{SessionName: "gt-gastown-Toast", AgentID: "gastown/polecats/Toast", AgentType: "opencode", Name: "Toast", Rig: "gastown"}
+	joe := &AgentLight{SessionName: "gt-gastown-crew-joe", AgentID: "gastown/crew/joe", AgentType: "claude", Name: "joe", Rig: "gastown"}
+	m := &Model{townRoot: townRoot, width: 200, pollInterval: time.Second, agents: []*AgentLight{toast, joe}}
+	sessions := []sessionInfo{{name: toast.SessionName, activity: 1}, {name: joe.SessionName, activity: 1}}
+
+	now := time.Now()
+	writeEvent(t, townRoot, now.Add(-20*time.Second), "tool_started", "polecat", map[string]interface{}{"session": toast.SessionName, "tool": "Bash(make)"})
+	writeEvent(t, townRoot, now.Add(-5*time.Minute), events.TypeAgentIdle, "crew", events.AgentIdlePayload(joe.SessionName))
+	m.updateAgents(sessions)
+	if toast.Level == LevelReady {
+		t.Fatal("Toast ready before any idle event")
+	}
+
+	// The idle event wins over a fresh pane timestamp and clears the tool.
+	toast.CurrentTool = "Bash(make)"
+	writeEvent(t, townRoot, now.Add(-time.Second), events.TypeAgentIdle, "polecat", events.AgentIdlePayload(toast.SessionName))
+	m.updateAgents(sessions)
+	if toast.Level != LevelReady || toast.CurrentTool != "" {
+		t.Fatalf("after agent_idle: level %s, tool %q; want ready, no tool", toast.Level, toast.CurrentTool)
+	}
+	if m.readyCount != 1 || joe.Level == LevelReady {
+		t.Errorf("readyCount = %d, joe %s; Claude agents don't take idle events", m.readyCount, joe.Level)
+	}
+	if got := m.renderStats(); !strings.Contains(got, "1 ready") {
+		t.Errorf("stats %q, want the ready count", got)
+	}
+	if got := m.renderLight(toast); !strings.Contains(got, "ready for work") {
+		t.Errorf("light %q, want ready for work", got)
+	}
+
+	// Still ready once the event falls out of the tool window.
+	m.updateAgents(sessions)
+	if toast.Level != LevelReady {
+		t.Errorf("second poll: level %s, want still ready", toast.Level)
+	}
+
+	// The next tool ends it.
+	writeEvent(t, townRoot, time.Now(), "tool_started", "polecat", map[string]interface{}{"session": toast.SessionName, "tool": "Read(go.mod)"})
+	m.updateAgents(sessions)
+	if toast.Level == LevelReady || toast.CurrentTool != "Read(go.mod)" {
+		t.Errorf("after tool_started: level %s, tool %q", toast.Level, toast.CurrentTool)
+	}
+}
+
+func TestReadyForWork_BusyPane(t *testing.T) {
+	now := time.Now()
+	m := &Model{}
+	a := &AgentLight{AgentType: "opencode", readyAt: now.Add(-time.Second), busyInPane: true}
+	if !m.readyForWork(a, now) {
+		t.Error("busy pane right after the idle event ended readiness; it's the turn that just finished")
+	}
+	if m.readyForWork(a, now.Add(time.Minute)) {
+		t.Error("busy pane a minute after the idle event left the agent ready")
+	}
+	a.busyInPane = false
+	if m.readyForWork(a, now.Add(2*time.Minute)) {
+		t.Error("ready again without a new idle event")
+	}
+
+	a = &AgentLight{AgentType: "opencode", readyAt: now, RateLimited: true}
+	if m.readyForWork(a, now) {
+		t.Error("rate-limited agent counted ready")
+	}
+}
@@ -0,0 +1,51 @@
+package activity
+
+import "strings"
+
+// SetRedact masks bead titles, file paths and command arguments on screen
+// (gt top --redact, town settings top.redact), so the monitor can be
+// screen-shared or wall-mounted without showing what agents work on. Agent
+// names, bead IDs, tool names and levels stay visible. Only the display is
+// masked: alerts, the status file and the ledger still see everything.
+func (m *Model) SetRedact(on bool) {
+	m.redact = on
+}
+
+// display returns the agent as the screen shows it: itself, or a redacted
+// copy in redacted mode.
+func (m *Model) display(a *AgentLight) *AgentLight {
+	if !m.redact || a == nil {
+		return a
+	}
+	r := *a
+	r.WorkBeadTitle = ""
+	r.StepCurrent = ""
+	r.CurrentTask = ""
+	r.StatusText = ""
+	r.WaitingReason = ""
+	r.LastToolError = ""
+	r.ProgressLabel = ""
+	r.CurrentTool = toolName(a.CurrentTool)
+	r.LoopTool = toolName(a.LoopTool)
+	return &r
+}
+
+// toolName strips a tool call's arguments: "Bash(git status)" and
+// "bash: git status" both become the bare tool name.
+func toolName(tool string) string {
+	if i := strings.IndexAny(tool, "(: "); i >= 0 {
+		tool = tool[:i]
+	}
+	return tool
+}
+
+// workHeader renders a convoy or epic header, without its title in
+// redacted mode.
+func (m *Model) workHeader(g *workGroup) string {
+	if m.redact {
+		untitled := *g
+		untitled.title = ""
+		return untitled.header()
+	}
+	return g.header()
+}
@@ -0,0 +1,76 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToolName(t *testing.T) {
+	for in, want := range map[string]string{
+		"Bash(git push origin main)":  "Bash",
+		"Read(/srv/client/secret.go)": "Read",
+		"bash: make deploy":           "bash",
+		"Grep":                        "Grep",
+		"":                            "",
+	} {
+		if got := toolName(in); got != want {
+			t.Errorf("toolName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// redactAgent is a polecat whose work, step, tool and convoy all name the
+// client.
+func redactAgent() *AgentLight {
+	return &AgentLight{
+		SessionName: "gt-gastown-Toast", Name: "Toast", Rig: "gastown", Icon: "😺",
+		Level: LevelActive, LastChangeTime: time.Now(),
+		WorkBeadID: "gt-42", WorkBeadTitle: "Acme billing export",
+		StepsTotal: 3, StepsDone: 1, StepCurrent: "edit invoice.go",
+		CurrentTool: "Bash(psql acme_prod)", CurrentTask: "Fix Acme rounding",
+		ConvoyID: "hq-cv-abc",
+	}
+}
+
+func TestRedact_MasksAgentLineAndHover(t *testing.T) {
+	a := redactAgent()
+	m := testModel([]*AgentLight{a}, modelSize(160, 40),
+		modelGroups(map[string]*workGroup{"hq-cv-abc": {id: "hq-cv-abc", kind: "convoy", title: "Acme launch", done: 1, total: 3}}))
+	m.hoveredAgent = a
+
+	plain := m.renderLight(a) + m.renderHoverDetail()
+	if !strings.Contains(plain, "Acme billing export") || !strings.Contains(plain, "psql acme_prod") {
+		t.Fatalf("unredacted view lacks the details under test: %q", plain)
+	}
+
+	m.SetRedact(true)
+	out := m.renderLight(a) + m.renderHoverDetail() + m.groupHeader("hq-cv-abc")
+	for _, leak := range []string{"Acme", "invoice.go", "psql", "acme_prod"} {
+		if strings.Contains(out, leak) {
+			t.Errorf("redacted view shows %q: %q", leak, out)
+		}
+	}
+	for _, want := range []string{"Toast", "gt-42", "[1/3]", "⏺ Bash", "convoy hq-cv-abc · 1/3 done"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("redacted view lost %q: %q", want, out)
+		}
+	}
+
+	// Only the display is masked.
+	if a.WorkBeadTitle == "" || a.CurrentTool != "Bash(psql acme_prod)" {
+		t.Errorf("redaction changed the agent: %+v", a)
+	}
+}
+
+func TestRedact_RefusesProbes(t *testing.T) {
+	a := redactAgent()
+	m := testModel([]*AgentLight{a}, modelSize(160, 40),
+		modelGroups(map[string]*workGroup{"hq-cv-abc": {id: "hq-cv-abc", kind: "convoy", title: "Acme launch", done: 1, total: 3}}))
+	a.Level, a.CurrentTool, a.AgentType = LevelWarm, "", "claude"
+	m.hoveredAgent = a
+	m.SetRedact(true)
+	if cmd := m.probeHovered("/status"); cmd != nil || !strings.Contains(m.flashMessage, "redacted") {
+		t.Errorf("probe in redacted mode: cmd=%v flash=%q", cmd != nil, m.flashMessage)
+	}
+}
@@ -0,0 +1,30 @@
+package activity
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// refreshHovered force-refreshes the hovered agent: sticky fields are
+// dropped, the agent type is re-detected, and a poll runs right away to
+// re-capture the pane. For when the operator just fixed something (cleared a
+// prompt, switched agents) and doesn't want to wait out sticky state.
+func (m *Model) refreshHovered() tea.Cmd {
+	a := m.hoveredAgent
+	m.flashTime = time.Now()
+	if a == nil {
+		m.flashMessage = "hover an agent to refresh it"
+		return nil
+	}
+
+	a.resetSticky()
+	a.AgentType = detectAgentType(a.SessionName)
+	m.flashMessage = "Refreshing " + a.SessionName
+
+	if m.polling {
+		m.wakePending = true // re-poll as soon as the in-flight poll lands
+		return nil
+	}
+	return m.pollTickAfter(0)
+}
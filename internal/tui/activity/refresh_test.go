@@ -0,0 +1,58 @@
+package activity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRefreshHovered_NoHover(t *testing.T) {
+	m := &Model{}
+	if cmd := m.refreshHovered(); cmd != nil {
+		t.Error("expected no command without a hovered agent")
+	}
+	if m.flashMessage == "" {
+		t.Error("expected a flash hint")
+	}
+}
+
+func TestRefreshHovered_ResetsStickyAndPolls(t *testing.T) {
+	a := &AgentLight{
+		SessionName:     "gt-nonexistent-refresh-test",
+		AgentType:       "opencode",
+		WaitingForHuman: true,
+		WaitingReason:   "permission prompt",
+		ContextPercent:  42,
+		Looping:         true,
+		LoopCount:       5,
+		limitReported:   limitKindUsage,
+	}
+	m := &Model{agents: []*AgentLight{a}, hoveredAgent: a, pollInterval: time.Second}
+	seq := m.pollSeq
+
+	if cmd := m.refreshHovered(); cmd == nil {
+		t.Fatal("expected an immediate poll")
+	}
+	if m.pollSeq != seq+1 {
+		t.Error("refresh should supersede the pending poll tick")
+	}
+	if a.WaitingForHuman || a.WaitingReason != "" || a.ContextPercent != 0 || a.Looping || a.LoopCount != 0 {
+		t.Errorf("sticky fields not reset: %+v", a)
+	}
+	if a.AgentType != "" {
+		t.Errorf("AgentType = %q, want re-detection (no tmux env for this session)", a.AgentType)
+	}
+	if a.limitReported != limitKindUsage {
+		t.Error("refresh should not re-arm limit_hit reporting")
+	}
+}
+
+func TestRefreshHovered_DuringPoll(t *testing.T) {
+	a := &AgentLight{SessionName: "gt-nonexistent-refresh-test"}
+	m := &Model{agents: []*AgentLight{a}, hoveredAgent: a, polling: true}
+	if cmd := m.refreshHovered(); cmd != nil {
+		t.Error("no new poll while one is in flight")
+	}
+	if !m.wakePending {
+		t.Error("refresh during a poll should re-poll when it lands")
+	}
+}
@@ -0,0 +1,62 @@
+package activity
+
+import "github.com/steveyegge/gastown/internal/events"
+
+// An agent relaunched under a new session name (respawned by a launcher, or
+// its session renamed) goes on receiving plugin events addressed to the old
+// name for a while: the plugin caches the name, and events queued or retried
+// across the relaunch carry it. gt top follows agent_restarted events to
+// learn the move, from the event's previous_session or from its agent ID
+// last seen under another session, and credits the old name's events to
+// the new session.
+
+// maxSessionMoves bounds how many moves movedSession follows, in case two
+// sessions were each recorded as moving to the other.
+const maxSessionMoves = 8
+
+// learnSessionMoves records the moves announced by this poll's
+// agent_restarted events, then notes each agent's current session for the
+// next poll. A session name that is live again is no longer followed: it
+// belongs to a new agent.
+func (m *Model) learnSessionMoves() {
+	for _, evt := range m.recentToolEvents {
+		if evt.EventType != events.TypeAgentRestarted || evt.Session == "" {
+			continue
+		}
+		prev := evt.PrevSession
+		if prev == "" && evt.AgentID != "" {
+			prev = m.agentSessions[evt.AgentID]
+		}
+		if prev == "" || prev == evt.Session {
+			continue
+		}
+		if m.sessionMoves == nil {
+			m.sessionMoves = make(map[string]string)
+		}
+		m.sessionMoves[prev] = evt.Session
+	}
+
+	for _, a := range m.agents {
+		delete(m.sessionMoves, a.SessionName)
+		if a.AgentID == "" {
+			continue
+		}
+		if m.agentSessions == nil {
+			m.agentSessions = make(map[string]string)
+		}
+		m.agentSessions[a.AgentID] = a.SessionName
+	}
+}
+
+// movedSession returns the session an event addressed to session belongs
+// to now: session itself unless its agent moved.
+func (m *Model) movedSession(session string) string {
+	for i := 0; i < maxSessionMoves; i++ {
+		next, ok := m.sessionMoves[session]
+		if !ok {
+			break
+		}
+		session = next
+	}
+	return session
+}
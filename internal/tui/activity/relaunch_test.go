@@ -0,0 +1,61 @@
+package activity
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestLearnSessionMoves(t *testing.T) {
+	joe := &AgentLight{SessionName: "gt-crew-joe-2", AgentType: "opencode"}
+	m := &Model{
+		agents: []*AgentLight{joe},
+		recentToolEvents: []toolEvent{
+			{Session: "gt-crew-joe-2", PrevSession: "gt-crew-joe", EventType: events.TypeAgentRestarted},
+			// Queued under the old name before the plugin noticed the move.
+			{Session: "gt-crew-joe", Tool: "Bash(ls)", EventType: "tool_started"},
+		},
+	}
+	m.learnSessionMoves()
+	if got := m.movedSession("gt-crew-joe"); got != "gt-crew-joe-2" {
+		t.Fatalf("movedSession(gt-crew-joe) = %q, want gt-crew-joe-2", got)
+	}
+	m.applyToolEvents()
+	if joe.CurrentTool != "Bash(ls)" {
+		t.Errorf("joe.CurrentTool = %q, want the old session's tool", joe.CurrentTool)
+	}
+
+	// Once the old name is live again it belongs to someone else.
+	m.agents = append(m.agents, &AgentLight{SessionName: "gt-crew-joe"})
+	m.recentToolEvents = nil
+	m.learnSessionMoves()
+	if got := m.movedSession("gt-crew-joe"); got != "gt-crew-joe" {
+		t.Errorf("movedSession(gt-crew-joe) = %q after the name came back", got)
+	}
+}
+
+func TestLearnSessionMoves_ByAgentID(t *testing.T) {
+	toast := &AgentLight{SessionName: "gt-Toast", AgentID: "gastown/polecats/Toast"}
+	m := &Model{agents: []*AgentLight{toast}}
+	m.learnSessionMoves()
+
+	// Relaunched under a new name; the restart event carries only the ID.
+	toast.SessionName = "gt-Toast-2"
+	m.recentToolEvents = []toolEvent{
+		{Session: "gt-Toast-2", AgentID: "gastown/polecats/Toast", EventType: events.TypeAgentRestarted},
+	}
+	m.learnSessionMoves()
+	if got := m.movedSession("gt-Toast"); got != "gt-Toast-2" {
+		t.Errorf("movedSession(gt-Toast) = %q, want gt-Toast-2", got)
+	}
+	if got := m.agentForToolEvent(toolEvent{Session: "gt-Toast"}); got != toast {
+		t.Errorf("agentForToolEvent(gt-Toast) = %v, want Toast", got)
+	}
+}
+
+func TestMovedSessionStopsOnCycles(t *testing.T) {
+	m := &Model{sessionMoves: map[string]string{"a": "b", "b": "a"}}
+	if got := m.movedSession("a"); got != "a" && got != "b" {
+		t.Errorf("movedSession(a) = %q", got)
+	}
+}
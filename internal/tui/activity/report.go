@@ -0,0 +1,138 @@
+package activity
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// reportColumns are the columns of the report's per-rig agent table.
+var reportColumns = []string{"", "Agent", "Level", "Status", "Idle", "Tool errors", "Session", "Context"}
+
+// SetReportPath makes gt top rewrite a status report at path on every poll:
+// the TUI's stats line and rig panels as Markdown tables, or as org-mode
+// tables when path ends in .org. A relative path is taken from the town
+// root, or the working directory in tmux-only mode. "" turns it off.
+func (m *Model) SetReportPath(path string) {
+	if path != "" && !filepath.IsAbs(path) && m.townRoot != "" {
+		path = filepath.Join(m.townRoot, path)
+	}
+	m.reportPath = path
+}
+
+// writeReport rewrites the report file. Write errors are flashed once, not
+// on every poll, so a bad path doesn't drown out other messages.
+func (m *Model) writeReport(now time.Time) {
+	if m.reportPath == "" {
+		return
+	}
+	err := os.MkdirAll(filepath.Dir(m.reportPath), 0755)
+	if err == nil {
+		err = util.AtomicWriteFile(m.reportPath, []byte(m.renderReport(now)), 0644)
+	}
+	if err != nil && !m.reportFailed {
+		m.flashMessage = "status report: " + err.Error()
+		m.flashTime = now
+	}
+	m.reportFailed = err != nil
+}
+
+// renderReport renders the status report in the format picked by the
+// report path's extension.
+func (m *Model) renderReport(now time.Time) string {
+	org := strings.EqualFold(filepath.Ext(m.reportPath), ".org")
+	heading := func(level int, text string) string {
+		if org {
+			return strings.Repeat("*", level) + " " + text
+		}
+		return strings.Repeat("#", level) + " " + text
+	}
+
+	var b strings.Builder
+	b.WriteString(heading(1, m.townTitle()+" status") + "\n\n")
+	fmt.Fprintf(&b, "Updated %s by gt top.\n\n", now.Format("2006-01-02 15:04:05 MST"))
+	if m.totalAgents == 0 {
+		b.WriteString("No agent sessions running.\n")
+		return b.String()
+	}
+	if stats := strings.TrimSpace(ansi.Strip(m.renderStats())); stats != "" {
+		b.WriteString(stats + "\n\n")
+	}
+
+	for _, rig := range m.rigs {
+		agents := m.agentsForRig(rig)
+		if len(agents) == 0 {
+			continue
+		}
+		title := rig
+		if badges := renderSLABadges(m.rigSLAs[rig]); badges != "" {
+			title += " · " + ansi.Strip(badges)
+		}
+		if badges := renderSLOBadges(m.rigSLOs[rig]); badges != "" {
+			title += " · " + ansi.Strip(badges)
+		}
+		if rig == "hq" {
+			if badges := m.renderHQBadges(now); badges != "" {
+				title += " · " + ansi.Strip(badges)
+			}
+		}
+		b.WriteString(heading(2, title) + "\n\n")
+
+		rows := [][]string{reportColumns}
+		for _, a := range agents {
+			rows = append(rows, reportRow(a, now))
+		}
+		writeReportTable(&b, rows, org)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// reportRow is an agent's row: the same status and indicators as its TUI
+// line, without styling.
+func reportRow(a *AgentLight, now time.Time) []string {
+	status, _ := agentStatus(a)
+	var toolErrors string
+	if a.ToolErrorCount > 0 {
+		toolErrors = ansi.Strip(renderToolErrorBadge(a.ToolErrorCount, false))
+	}
+	return []string{
+		a.Icon,
+		a.Name,
+		a.Level.String(),
+		status,
+		formatElapsed(now.Sub(a.LastChangeTime)),
+		toolErrors,
+		ansi.Strip(renderSessionLimitIndicator(a.SessionLimitPct, a.SessionLimitReset)),
+		ansi.Strip(renderContextIndicator(a.ContextPercent, false, a.TokenCount, a.SessionCreated)),
+	}
+}
+
+// writeReportTable writes rows as a table whose first row is the header.
+// Markdown and org-mode tables differ only in the separator row.
+func writeReportTable(b *strings.Builder, rows [][]string, org bool) {
+	for i, row := range rows {
+		cells := make([]string, len(row))
+		for j, c := range row {
+			cells[j] = strings.ReplaceAll(c, "|", `\|`)
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if i > 0 {
+			continue
+		}
+		sep := make([]string, len(row))
+		for j := range sep {
+			sep[j] = "---"
+		}
+		if org {
+			b.WriteString("|" + strings.Join(sep, "+") + "|\n")
+		} else {
+			b.WriteString("|" + strings.Join(sep, "|") + "|\n")
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package activity
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// reportNow is the time the report tests render at.
+var reportNow = time.Date(2025, 1, 10, 3, 0, 0, 0, time.UTC)
+
+// reportAgents are an active mayor and a crew member waiting on a human.
+func reportAgents() []*AgentLight {
+	return []*AgentLight{
+		{SessionName: "hq-mayor", Name: "mayor", Rig: "hq", Role: "mayor", Icon: "🎩",
+			Level: LevelActive, CurrentTool: "Bash", LastChangeTime: reportNow.Add(-5 * time.Second), ContextPercent: 70},
+		{SessionName: "gt-gastown-crew-max", Name: "max", Rig: "gastown", Role: "crew", Icon: "👷",
+			Level: LevelWaitingForHuman, WaitingReason: "Permission | prompt", LastChangeTime: reportNow.Add(-3 * time.Minute)},
+	}
+}
+
+// reportTown names the town and counts reportAgents as a poll would.
+func reportTown(m *Model) {
+	m.townName, m.activeCount, m.waitingCount = "greenplace", 1, 1
+}
+
+func TestRenderReport_Markdown(t *testing.T) {
+	now := reportNow
+	m := testModel(reportAgents(), reportTown)
+	m.SetReportPath("/tmp/STATUS.md")
+	got := m.renderReport(now)
+
+	for _, want := range []string{
+		"# GREENPLACE status",
+		"⚠ 1 NEED HUMAN",
+		"## hq\n",
+		"## gastown\n",
+		"|  | Agent | Level | Status | Idle | Tool errors | Session | Context |\n|---|---|",
+		"| 🎩 | mayor | active | ⏺ Bash |  |  |  | 30% used |",
+		`⚠ NEEDS HUMAN · Permission \| prompt`,
+		"| 3m |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "\x1b[") {
+		t.Errorf("report contains ANSI escapes:\n%q", got)
+	}
+}
+
+func TestRenderReport_Org(t *testing.T) {
+	now := reportNow
+	m := testModel(reportAgents(), reportTown)
+	m.SetReportPath("/tmp/status.org")
+	got := m.renderReport(now)
+	for _, want := range []string{"* GREENPLACE status", "** hq\n", "|---+---+"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("org report missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	town := t.TempDir()
+	now := reportNow
+	m := testModel(reportAgents(), reportTown)
+	m.townRoot = town
+	m.SetReportPath(".gastown/STATUS.md")
+	m.writeReport(now)
+
+	data, err := os.ReadFile(filepath.Join(town, ".gastown", "STATUS.md"))
+	if err != nil {
+		t.Fatalf("report not written under the town root: %v", err)
+	}
+	if !strings.Contains(string(data), "| max |") {
+		t.Errorf("report = %s", data)
+	}
+
+	// A failing write is flashed once.
+	m.SetReportPath(filepath.Join(town, ".gastown", "STATUS.md", "nested.md"))
+	m.writeReport(now)
+	if !m.reportFailed || !strings.HasPrefix(m.flashMessage, "status report:") {
+		t.Fatalf("failed write: reportFailed=%v flash=%q", m.reportFailed, m.flashMessage)
+	}
+	m.flashMessage = ""
+	m.writeReport(now)
+	if m.flashMessage != "" {
+		t.Errorf("repeated failure flashed again: %q", m.flashMessage)
+	}
+}
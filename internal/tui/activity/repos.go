@@ -0,0 +1,191 @@
+package activity
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Multi-repo rigs: a rig's agents needn't all work in the rig's own repo.
+// gt top maps each agent's pane working directory to the repository it is
+// in, named after its origin remote so clones and worktrees of one repo
+// share a name. When a rig's agents span more than one repo, each agent's
+// status leads with its repo and a footer under the rig's panel breaks its
+// work down per repo.
+
+// trackRepo resolves the agent's repo when its pane's working directory
+// changes. Resolutions are cached per directory.
+func (m *Model) trackRepo(a *AgentLight, workDir string) {
+	if workDir == "" || workDir == a.workDir {
+		return
+	}
+	a.workDir = workDir
+	if m.repos == nil {
+		m.repos = make(map[string]string)
+	}
+	repo, ok := m.repos[workDir]
+	if !ok {
+		repo = repoName(workDir)
+		m.repos[workDir] = repo
+	}
+	a.Repo = repo
+}
+
+// repoName names the git repository dir is in, or "" outside one: the last
+// element of its origin remote URL, else of its top-level directory.
+func repoName(dir string) string {
+	top, gitPath := findGitDir(dir)
+	if top == "" {
+		return ""
+	}
+	if url := originURL(gitPath); url != "" {
+		url = strings.TrimSuffix(strings.TrimRight(url, "/"), ".git")
+		if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+			url = url[i+1:]
+		}
+		if url != "" {
+			return url
+		}
+	}
+	return filepath.Base(top)
+}
+
+// findGitDir walks up from dir to the repository's top level, returning it
+// and its .git entry, a directory or, in worktrees and submodules, a file.
+func findGitDir(dir string) (top, gitPath string) {
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		p := filepath.Join(d, ".git")
+		if _, err := os.Stat(p); err == nil {
+			return d, p
+		}
+		if filepath.Dir(d) == d {
+			return "", ""
+		}
+	}
+}
+
+// originURL reads the origin remote's URL from the repository's config,
+// following a worktree's gitdir file to the main repository.
+func originURL(gitPath string) string {
+	gitDir := gitPath
+	if info, err := os.Stat(gitPath); err == nil && !info.IsDir() {
+		data, err := os.ReadFile(gitPath)
+		if err != nil {
+			return ""
+		}
+		gitDir = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(data)), "gitdir:"))
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(filepath.Dir(gitPath), gitDir)
+		}
+		if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+			common := strings.TrimSpace(string(data))
+			if !filepath.IsAbs(common) {
+				common = filepath.Join(gitDir, common)
+			}
+			gitDir = common
+		}
+	}
+	f, err := os.Open(filepath.Join(gitDir, "config"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	inOrigin := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && inOrigin && strings.TrimSpace(key) == "url" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// rigRepos returns the repos a rig's agents are in, by name, with the
+// agents in each.
+func (m *Model) rigRepos(rig string) map[string][]*AgentLight {
+	repos := make(map[string][]*AgentLight)
+	for _, a := range m.agents {
+		if a.Rig == rig && a.Repo != "" && !a.placeholder {
+			repos[a.Repo] = append(repos[a.Repo], a)
+		}
+	}
+	return repos
+}
+
+// spansRepos reports whether the rig's agents work in more than one repo.
+func (m *Model) spansRepos(rig string) bool {
+	return len(m.rigRepos(rig)) > 1
+}
+
+// repoFooterHeight is how many rows the per-repo footer adds under a rig's
+// panel.
+func (m *Model) repoFooterHeight(rig string) int {
+	if m.spansRepos(rig) {
+		return 1
+	}
+	return 0
+}
+
+// renderRepoFooter renders the rig's per-repo breakdown for a rig spanning
+// several repos, e.g. "gastown 3 agents, 1 pushed · beads 2 agents, 1 in
+// merge queue", busiest repo first. Returns "" for a single-repo rig.
+func (m *Model) renderRepoFooter(rig string) string {
+	repos := m.rigRepos(rig)
+	if len(repos) < 2 {
+		return ""
+	}
+	names := make([]string, 0, len(repos))
+	for name := range repos {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(repos[names[i]]) != len(repos[names[j]]) {
+			return len(repos[names[i]]) > len(repos[names[j]])
+		}
+		return names[i] < names[j]
+	})
+
+	var parts []string
+	for _, name := range names {
+		agents := repos[name]
+		pushed, queued := 0, 0
+		for _, a := range agents {
+			if a.showsPush() {
+				pushed++
+			}
+			if a.MergeQueuePos > 0 {
+				queued++
+			}
+		}
+		s := fmt.Sprintf("%s %d agent", name, len(agents))
+		if len(agents) != 1 {
+			s += "s"
+		}
+		if pushed > 0 {
+			s += fmt.Sprintf(", %d pushed", pushed)
+		}
+		if queued > 0 {
+			s += fmt.Sprintf(", %d in merge queue", queued)
+		}
+		parts = append(parts, s)
+	}
+	line := "  " + strings.Join(parts, " · ")
+	return statusDimStyle.Render(truncateWidth(line, max(m.layoutWidth()-2, 20), "…"))
+}
+
+// repoLabel is the repo prefix for an agent's status in a multi-repo rig,
+// e.g. "beads · ", or "".
+func (m *Model) repoLabel(a *AgentLight) string {
+	if a.Repo == "" || !m.spansRepos(a.Rig) {
+		return ""
+	}
+	return a.Repo + " · "
+}
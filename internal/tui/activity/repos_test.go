@@ -0,0 +1,79 @@
+package activity
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// fakeRepo creates a repository directory whose config has an origin url.
+func fakeRepo(t *testing.T, dir, url string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "[core]\n\tbare = false\n"
+	if url != "" {
+		config += "[remote \"origin\"]\n\turl = " + url + "\n\tfetch = +refs/heads/*:refs/remotes/origin/*\n"
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".git", "config"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRepoName(t *testing.T) {
+	root := t.TempDir()
+	fakeRepo(t, filepath.Join(root, "gastown", "mayor", "rig"), "git@github.com:steveyegge/gastown.git")
+	fakeRepo(t, filepath.Join(root, "local"), "")
+
+	// A worktree's .git file points into the main repo's worktrees dir.
+	wtGit := filepath.Join(root, "gastown", "mayor", "rig", ".git", "worktrees", "Toast")
+	if err := os.MkdirAll(wtGit, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wtGit, "commondir"), []byte("../..\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wt := filepath.Join(root, "gastown", "polecats", "Toast", "gastown")
+	if err := os.MkdirAll(filepath.Join(wt, "internal"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+wtGit+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct{ dir, want string }{
+		{filepath.Join(root, "gastown", "mayor", "rig"), "gastown"},
+		{filepath.Join(wt, "internal"), "gastown"},
+		{filepath.Join(root, "local"), "local"},
+		{root, ""},
+	}
+	for _, tt := range tests {
+		if got := repoName(tt.dir); got != tt.want {
+			t.Errorf("repoName(%s) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestRepoFooter(t *testing.T) {
+	m := &Model{width: 160, agents: []*AgentLight{
+		{SessionName: "gt-gastown-Toast", Rig: "gastown", Repo: "gastown", PushedBranch: "polecat/Toast"},
+		{SessionName: "gt-gastown-Nux", Rig: "gastown", Repo: "gastown"},
+		{SessionName: "gt-gastown-Slit", Rig: "gastown", Repo: "beads", MergeQueuePos: 1},
+		{SessionName: "gt-beads-crew-max", Rig: "beads", Repo: "beads"},
+	}}
+	if got := ansi.Strip(m.renderRepoFooter("gastown")); !strings.Contains(got, "gastown 2 agents, 1 pushed · beads 1 agent, 1 in merge queue") {
+		t.Errorf("footer = %q", got)
+	}
+	if m.repoLabel(m.agents[2]) != "beads · " || m.repoFooterHeight("gastown") != 1 {
+		t.Errorf("multi-repo rig: label %q, footer height %d", m.repoLabel(m.agents[2]), m.repoFooterHeight("gastown"))
+	}
+
+	// A single-repo rig shows neither.
+	if m.renderRepoFooter("beads") != "" || m.repoLabel(m.agents[3]) != "" || m.repoFooterHeight("beads") != 0 {
+		t.Error("single-repo rig shows repo labels")
+	}
+}
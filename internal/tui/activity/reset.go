@@ -0,0 +1,145 @@
+package activity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/quota"
+)
+
+// resetGrace is how far in the past a date-less reset clock may be before it
+// is read as the next day's. A limit message still on screen a few minutes
+// after its reset time means "reset due", not "resets tomorrow".
+const resetGrace = time.Hour
+
+// resetClock caches the absolute time parsed from a reset message, so the
+// deadline stays fixed while the same message stays on screen.
+type resetClock struct {
+	src string    // message the time was parsed from
+	at  time.Time // zero when src is empty or unparseable
+}
+
+// update re-parses info if it changed since the last poll.
+func (c *resetClock) update(info string, now time.Time) {
+	if info == c.src {
+		return
+	}
+	c.src = info
+	c.at, _ = parseResetTime(info, now)
+}
+
+// parseResetTime turns Claude's reset message into an absolute time:
+//
+//	"resets 2pm (America/Los_Angeles)"    → next 2pm in Los Angeles
+//	"resets 3:30am"                       → next 3:30am local time
+//	"resets Oct 20, 9am (Europe/London)"  → Oct 20 9am in London
+//
+// The clock and zone are parsed by quota.ParseResetTime, shared with the
+// account quota tracker.
+func parseResetTime(info string, now time.Time) (time.Time, bool) {
+	s := strings.TrimSpace(info)
+	if len(s) < len("resets ") || !strings.EqualFold(s[:len("resets ")], "resets ") {
+		return time.Time{}, false
+	}
+	s = strings.TrimPrefix(strings.TrimSpace(s[len("resets "):]), "at ")
+
+	month, day, clock := splitResetDate(s)
+	t, err := quota.ParseResetTime(clock, now)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if month == 0 {
+		if t.Before(now.Add(-resetGrace)) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, true
+	}
+	t = time.Date(t.Year(), month, day, t.Hour(), t.Minute(), 0, 0, t.Location())
+	if t.Before(now.AddDate(0, -6, 0)) {
+		t = t.AddDate(1, 0, 0) // "Jan 3" seen in late December
+	}
+	return t, true
+}
+
+// splitResetDate splits an optional leading "Oct 20," or "Oct 20 at" off a
+// reset message, returning month 0 when there is no date.
+func splitResetDate(s string) (time.Month, int, string) {
+	fields := strings.Fields(s)
+	if len(fields) < 3 {
+		return 0, 0, s
+	}
+	d, err := time.Parse("Jan 2", fields[0]+" "+strings.TrimSuffix(fields[1], ","))
+	if err != nil {
+		return 0, 0, s
+	}
+	rest := strings.Join(fields[2:], " ")
+	return d.Month(), d.Day(), strings.TrimPrefix(rest, "at ")
+}
+
+// formatCountdown renders time remaining until a reset, e.g. "1h05m".
+func formatCountdown(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// resetLabel renders a live countdown for a reset message, falling back to
+// the message verbatim when it couldn't be parsed.
+func resetLabel(c resetClock, now time.Time) string {
+	if c.at.IsZero() {
+		return c.src
+	}
+	if d := c.at.Sub(now); d > 0 {
+		return "resets in " + formatCountdown(d)
+	}
+	return "reset due"
+}
+
+// hitLimitByReset returns the hit-limit agents, soonest reset first. Agents
+// whose reset time is unknown sort last.
+func (m *Model) hitLimitByReset() []*AgentLight {
+	var out []*AgentLight
+	for _, a := range m.agents {
+		if a.Level == LevelHitLimit {
+			out = append(out, a)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		ai, aj := out[i].limitReset.at, out[j].limitReset.at
+		if ai.IsZero() || aj.IsZero() {
+			return !ai.IsZero() && aj.IsZero()
+		}
+		return ai.Before(aj)
+	})
+	return out
+}
+
+// renderNextResets lists up to n hit-limit agents in reset order for the
+// stats bar, e.g. "next: Toast 12m, Nux 1h05m".
+func (m *Model) renderNextResets(n int) string {
+	now := time.Now()
+	var parts []string
+	for _, a := range m.hitLimitByReset() {
+		if a.limitReset.at.IsZero() || len(parts) == n {
+			break
+		}
+		left := "due"
+		if d := a.limitReset.at.Sub(now); d > 0 {
+			left = formatCountdown(d)
+		}
+		parts = append(parts, a.Name+" "+left)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "next: " + strings.Join(parts, ", ")
+}
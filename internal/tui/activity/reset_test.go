@@ -0,0 +1,86 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseResetTime(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	// 10:00 Pacific on Oct 15.
+	now := time.Date(2026, 10, 15, 10, 0, 0, 0, la)
+
+	tests := []struct {
+		info string
+		want time.Time
+	}{
+		{"resets 2pm (America/Los_Angeles)", time.Date(2026, 10, 15, 14, 0, 0, 0, la)},
+		{"resets 3:30am (America/Los_Angeles)", time.Date(2026, 10, 16, 3, 30, 0, 0, la)}, // already passed today
+		{"resets 9:30am (America/Los_Angeles)", time.Date(2026, 10, 15, 9, 30, 0, 0, la)}, // just passed: reset due
+		{"resets 6pm (America/New_York)", time.Date(2026, 10, 15, 15, 0, 0, 0, la)},
+		{"resets Oct 20, 9am (America/Los_Angeles)", time.Date(2026, 10, 20, 9, 0, 0, 0, la)},
+		{"resets Jan 3 at 9am (America/Los_Angeles)", time.Date(2027, 1, 3, 9, 0, 0, 0, la)},
+	}
+	for _, tt := range tests {
+		got, ok := parseResetTime(tt.info, now)
+		if !ok || !got.Equal(tt.want) {
+			t.Errorf("parseResetTime(%q) = %v, %v; want %v", tt.info, got, ok, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "resets soon", "2pm", "resets tomorrow"} {
+		if _, ok := parseResetTime(bad, now); ok {
+			t.Errorf("parseResetTime(%q) should fail", bad)
+		}
+	}
+}
+
+func TestResetLabel(t *testing.T) {
+	now := time.Now()
+	if got := resetLabel(resetClock{src: "resets whenever"}, now); got != "resets whenever" {
+		t.Errorf("unparsed label = %q, want verbatim", got)
+	}
+	if got := resetLabel(resetClock{src: "x", at: now.Add(65*time.Minute + time.Second)}, now); got != "resets in 1h05m" {
+		t.Errorf("label = %q", got)
+	}
+	if got := resetLabel(resetClock{src: "x", at: now.Add(-time.Minute)}, now); got != "reset due" {
+		t.Errorf("past label = %q", got)
+	}
+}
+
+func TestResetClock_StableWhileMessageUnchanged(t *testing.T) {
+	now := time.Date(2026, 10, 15, 13, 0, 0, 0, time.Local)
+	var c resetClock
+	c.update("resets 2pm", now)
+	first := c.at
+	// Two hours later the same message is still on screen: the deadline
+	// must not roll forward to tomorrow.
+	c.update("resets 2pm", now.Add(2*time.Hour))
+	if !c.at.Equal(first) {
+		t.Errorf("deadline moved from %v to %v", first, c.at)
+	}
+	c.update("", now)
+	if !c.at.IsZero() {
+		t.Error("clearing the message should clear the deadline")
+	}
+}
+
+func TestRenderStats_HitLimitSortedBySoonestReset(t *testing.T) {
+	now := time.Now()
+	late := &AgentLight{Name: "Late", Level: LevelHitLimit, limitReset: resetClock{src: "x", at: now.Add(3*time.Hour + 30*time.Second)}}
+	soon := &AgentLight{Name: "Soon", Level: LevelHitLimit, limitReset: resetClock{src: "x", at: now.Add(10*time.Minute + 30*time.Second)}}
+	unknown := &AgentLight{Name: "Unknown", Level: LevelHitLimit}
+	m := &Model{agents: []*AgentLight{unknown, late, soon}, totalAgents: 3, hitLimitCount: 3}
+
+	got := m.hitLimitByReset()
+	if got[0] != soon || got[1] != late || got[2] != unknown {
+		t.Errorf("order = %s, %s, %s", got[0].Name, got[1].Name, got[2].Name)
+	}
+	if stats := m.renderStats(); !strings.Contains(stats, "next: Soon 10m, Late 3h00m") {
+		t.Errorf("stats bar = %q", stats)
+	}
+}
@@ -0,0 +1,49 @@
+package activity
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/cli"
+)
+
+// prepareRestart asks for confirmation to restart the hovered agent in place
+// (gt agents restart): the tmux session survives, the agent is relaunched.
+// A container agent's container, or a pod agent's pod, is restarted instead.
+func (m *Model) prepareRestart() {
+	a := m.hoveredAgent
+	if a == nil {
+		return
+	}
+	if m.townRoot == "" && !a.inContainer() {
+		m.flashMessage = "restart needs a town (tmux-only mode)"
+		m.flashTime = time.Now()
+		return
+	}
+	m.pendingBulk = &bulkAction{
+		verb:     "restart",
+		agents:   []*AgentLight{a},
+		sessions: []string{a.SessionName},
+		prompt:   "restart " + a.SessionName,
+	}
+}
+
+// restartSession runs gt agents restart from the town root, so the restart
+// command resolves the same town gt top is showing.
+func restartSession(townRoot string) func(session string) error {
+	return func(session string) error {
+		c := exec.Command(cli.Name(), "agents", "restart", session)
+		c.Dir = townRoot
+		out, err := c.CombinedOutput()
+		if err != nil {
+			lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+			if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+				return fmt.Errorf("%s", last)
+			}
+			return err
+		}
+		return nil
+	}
+}
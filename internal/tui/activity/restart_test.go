@@ -0,0 +1,51 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRestart_ConfirmsHoveredAgent(t *testing.T) {
+	m := testModel(greenplaceCrew(), modelTown(t.TempDir()))
+	cold := m.agents[0]
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	if m.pendingBulk != nil {
+		t.Fatal("R with nothing hovered should do nothing")
+	}
+
+	m.hoveredAgent = cold
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	if m.pendingBulk == nil || m.pendingBulk.verb != "restart" || m.pendingBulk.prompt != "restart gp-crew-max" {
+		t.Fatalf("pending = %+v", m.pendingBulk)
+	}
+	if out := m.render(); !strings.Contains(out, "restart gp-crew-max?") {
+		t.Error("confirmation prompt not rendered")
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if cmd != nil || m.pendingBulk != nil {
+		t.Error("any key but y should cancel without restarting")
+	}
+}
+
+func TestRestart_NeedsTown(t *testing.T) {
+	m := testModel(greenplaceCrew(), modelTown(t.TempDir()))
+	cold := m.agents[0]
+	m.townRoot = ""
+	m.hoveredAgent = cold
+	m.prepareRestart()
+	if m.pendingBulk != nil || !strings.Contains(m.flashMessage, "needs a town") {
+		t.Errorf("pending = %+v, flash = %q", m.pendingBulk, m.flashMessage)
+	}
+}
+
+func TestRestart_DoneFlash(t *testing.T) {
+	m := testModel(greenplaceCrew(), modelTown(t.TempDir()))
+	m.polling = true
+	m.handleBulkDone(bulkDoneMsg{verb: "restart", done: 1, total: 1})
+	if m.flashMessage != "restarted 1/1" {
+		t.Errorf("flash = %q", m.flashMessage)
+	}
+}
@@ -0,0 +1,155 @@
+package activity
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Rig coloring (gt top --color-by rig, town settings top.color_by, H to
+// toggle): each rig gets its own hue and a light's brightness shows how
+// active its agent is, so cross-rig patterns, like one rig gone entirely
+// dark, stand out in a dense grid. Lights that call for attention (needs
+// human, limits, looping, compacting) keep their usual colors.
+
+// colorBy is what the lights are colored by.
+type colorBy int
+
+const (
+	colorByLevel colorBy = iota // the default: a color per activity level
+	colorByRig
+)
+
+// rigHueStep spaces the rigs' hues by the golden angle, so neighbouring rigs
+// get far-apart hues however many rigs there are.
+const rigHueStep = 137.508
+
+// rigSaturation is the saturation of every rig's lights.
+const rigSaturation = 0.7
+
+// SetColorBy sets what the lights are colored by: "level" (or empty) or
+// "rig".
+func (m *Model) SetColorBy(mode string) error {
+	switch mode {
+	case "", "level":
+		m.colorBy = colorByLevel
+	case "rig":
+		m.colorBy = colorByRig
+	default:
+		return fmt.Errorf("unknown color mode %q (want level or rig)", mode)
+	}
+	return nil
+}
+
+// toggleColorBy switches between coloring by level and by rig.
+func (m *Model) toggleColorBy() {
+	if m.colorBy == colorByRig {
+		m.colorBy = colorByLevel
+		m.flashMessage = "lights colored by activity level"
+	} else {
+		m.colorBy = colorByRig
+		m.flashMessage = "lights colored by rig · brightness is activity"
+	}
+	m.flashTime = time.Now()
+}
+
+// rigHue returns a rig's hue in degrees, from its place in the rig order.
+func (m *Model) rigHue(rig string) float64 {
+	for i, r := range m.rigs {
+		if r == rig {
+			return math.Mod(float64(i)*rigHueStep, 360)
+		}
+	}
+	return 0
+}
+
+// rigColor returns a rig's color at the given lightness (0-1).
+func (m *Model) rigColor(rig string, lightness float64) lipgloss.Color {
+	return hslColor(m.rigHue(rig), rigSaturation, lightness)
+}
+
+// rigLightness returns how bright a light is drawn in rig coloring for an
+// agent's level, and false for the levels that keep their own colors.
+func (m *Model) rigLightness(a *AgentLight) (float64, bool) {
+	switch a.Level {
+	case LevelActive:
+		if m.lit(false) {
+			return 0.65, true
+		}
+		return 0.5, true
+	case LevelRecent:
+		return 0.55, true
+	case LevelWarm:
+		return 0.42, true
+	case LevelCool:
+		return 0.3, true
+	case LevelCold:
+		return 0.18, true
+	}
+	return 0, false
+}
+
+// renderRigLight renders glyph in the agent's rig color, reporting false
+// when rig coloring doesn't apply to it.
+func (m *Model) renderRigLight(a *AgentLight, glyph string) (string, bool) {
+	if m.colorBy != colorByRig || a.IsCompacting || a.Looping {
+		return "", false
+	}
+	l, ok := m.rigLightness(a)
+	if !ok {
+		return "", false
+	}
+	return lipgloss.NewStyle().Foreground(m.rigColor(a.Rig, l)).Render(glyph), true
+}
+
+// levelGlyph returns the theme glyph for a quiet level's light.
+func (m *Model) levelGlyph(level ActivityLevel) string {
+	led := m.led()
+	switch level {
+	case LevelActive:
+		return led.active
+	case LevelRecent:
+		return led.recent
+	case LevelWarm:
+		return led.warm
+	case LevelCool:
+		return led.cool
+	}
+	return led.cold
+}
+
+// renderRigHeader renders a rig panel's name, in the rig's color when
+// coloring by rig, so the hues read as a legend.
+func (m *Model) renderRigHeader(rig string) string {
+	if m.colorBy != colorByRig {
+		return rigHeaderStyle.Render(rig)
+	}
+	return rigHeaderStyle.Foreground(m.rigColor(rig, 0.6)).Render(rig)
+}
+
+// hslColor converts a hue (degrees), saturation and lightness (0-1) to a
+// hex color.
+func hslColor(h, s, l float64) lipgloss.Color {
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	m := l - c/2
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x",
+		int(math.Round((r+m)*255)), int(math.Round((g+m)*255)), int(math.Round((b+m)*255))))
+}
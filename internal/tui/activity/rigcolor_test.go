@@ -0,0 +1,63 @@
+package activity
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestHSLColor(t *testing.T) {
+	tests := []struct {
+		h, s, l float64
+		want    lipgloss.Color
+	}{
+		{0, 1, 0.5, "#ff0000"},
+		{120, 1, 0.5, "#00ff00"},
+		{240, 1, 0.5, "#0000ff"},
+		{0, 0, 1, "#ffffff"},
+		{200, 0.7, 0, "#000000"},
+	}
+	for _, tt := range tests {
+		if got := hslColor(tt.h, tt.s, tt.l); got != tt.want {
+			t.Errorf("hslColor(%v, %v, %v) = %s, want %s", tt.h, tt.s, tt.l, got, tt.want)
+		}
+	}
+}
+
+func TestColorByRig(t *testing.T) {
+	m := &Model{rigs: []string{"hq", "beads", "gastown"}}
+	if m.rigHue("hq") == m.rigHue("beads") || m.rigHue("beads") == m.rigHue("gastown") {
+		t.Errorf("rig hues collide: hq %v beads %v gastown %v", m.rigHue("hq"), m.rigHue("beads"), m.rigHue("gastown"))
+	}
+
+	active := &AgentLight{Rig: "gastown", Level: LevelActive}
+	cold := &AgentLight{Rig: "gastown", Level: LevelCold}
+	waiting := &AgentLight{Rig: "gastown", Level: LevelWaitingForHuman}
+	if _, ok := m.renderRigLight(active, "●"); ok {
+		t.Error("rig light drawn while coloring by level")
+	}
+
+	if err := m.SetColorBy("rig"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.renderRigLight(active, "●"); !ok {
+		t.Error("active agent not drawn in its rig color")
+	}
+	if _, ok := m.renderRigLight(waiting, "‼"); ok {
+		t.Error("needs-human alarm drawn in the rig color")
+	}
+	la, _ := m.rigLightness(active)
+	lc, _ := m.rigLightness(cold)
+	if la <= lc {
+		t.Errorf("active lightness %v not above cold %v", la, lc)
+	}
+	if err := m.SetColorBy("rainbow"); err == nil {
+		t.Error("unknown color mode accepted")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	if m.colorBy != colorByLevel {
+		t.Error("H didn't switch back to coloring by level")
+	}
+}
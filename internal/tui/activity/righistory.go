@@ -0,0 +1,197 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Rig history (gt top --rig-history, town settings top.rig_history): a tiny
+// stacked bar chart under each rig header of how many of its agents were
+// active, idle and in trouble each minute of the last half hour, so a rig
+// slowly going quiet or piling up problems shows without a dashboard.
+
+const (
+	// rigHistoryMinutes is how far back the chart goes, one column a minute.
+	rigHistoryMinutes = 30
+
+	// rigHistoryRows is the chart height.
+	rigHistoryRows = 2
+)
+
+// rigSample is a rig's agent counts in one minute.
+type rigSample struct {
+	at                    time.Time // the minute
+	active, idle, problem int
+}
+
+func (s rigSample) total() int { return s.active + s.idle + s.problem }
+
+// rigHistory is each rig's samples, oldest first.
+type rigHistory struct {
+	rigs map[string][]rigSample
+}
+
+// EnableRigHistory adds the per-rig history chart under each rig header.
+func (m *Model) EnableRigHistory() {
+	if m.rigHistory == nil {
+		m.rigHistory = &rigHistory{rigs: make(map[string][]rigSample)}
+	}
+}
+
+// sampleRigHistory records each rig's counts for the current minute; later
+// polls in the same minute replace its sample. Must run after levels are
+// computed and the merge queue applied.
+func (m *Model) sampleRigHistory(now time.Time) {
+	h := m.rigHistory
+	if h == nil {
+		return
+	}
+	minute := now.Truncate(time.Minute)
+	counts := make(map[string]*rigSample)
+	for _, a := range m.agents {
+		if a.placeholder || a.Rig == "" {
+			continue
+		}
+		c := counts[a.Rig]
+		if c == nil {
+			c = &rigSample{at: minute}
+			counts[a.Rig] = c
+		}
+		switch m.rigHistoryClass(a) {
+		case "active":
+			c.active++
+		case "idle":
+			c.idle++
+		default:
+			c.problem++
+		}
+	}
+
+	cutoff := minute.Add(-(rigHistoryMinutes - 1) * time.Minute)
+	for rig, samples := range h.rigs {
+		kept := samples[:0]
+		for _, s := range samples {
+			if !s.at.Before(cutoff) && !s.at.Equal(minute) {
+				kept = append(kept, s)
+			}
+		}
+		h.rigs[rig] = kept
+	}
+	for rig, c := range counts {
+		h.rigs[rig] = append(h.rigs[rig], *c)
+	}
+	for rig, samples := range h.rigs {
+		if len(samples) == 0 {
+			delete(h.rigs, rig)
+		}
+	}
+}
+
+// rigHistoryClass buckets an agent for the chart: "active", "idle" or
+// "problem". Agents muted, scheduled off or waiting on the merge queue are
+// idle, whatever their level.
+func (m *Model) rigHistoryClass(a *AgentLight) string {
+	switch a.Level {
+	case LevelActive, LevelRecent, LevelStarting:
+		return "active"
+	case LevelWarm, LevelCool, LevelReady:
+		return "idle"
+	}
+	if m.muted(a) || a.showsScheduledOff() || a.waitsOnMerge() {
+		return "idle"
+	}
+	return "problem"
+}
+
+// rigHistoryHeight is how many rows the chart adds under a rig's header.
+func (m *Model) rigHistoryHeight(rig string) int {
+	if m.rigHistory == nil || len(m.rigHistory.rigs[rig]) == 0 {
+		return 0
+	}
+	return rigHistoryRows
+}
+
+// renderRigHistory renders the rig's chart, one column a minute with the
+// current minute on the right, problems stacked at the bottom, then idle,
+// then active, scaled to the busiest minute. The latest counts are shown
+// beside it. Returns "" without history.
+func (m *Model) renderRigHistory(rig string, now time.Time) string {
+	if m.rigHistoryHeight(rig) == 0 {
+		return ""
+	}
+	samples := m.rigHistory.rigs[rig]
+	minute := now.Truncate(time.Minute)
+	cols := make([]rigSample, rigHistoryMinutes)
+	peak := 0
+	for _, s := range samples {
+		i := rigHistoryMinutes - 1 - int(minute.Sub(s.at)/time.Minute)
+		if i < 0 || i >= rigHistoryMinutes {
+			continue
+		}
+		cols[i] = s
+		peak = max(peak, s.total())
+	}
+
+	rows := stackedBars(cols, peak, rigHistoryRows)
+	last := samples[len(samples)-1]
+	labels := []string{
+		statusDimStyle.Render(fmt.Sprintf("%dm", rigHistoryMinutes)),
+		statusDimStyle.Render(fmt.Sprintf("%d active · %d idle · %d problem", last.active, last.idle, last.problem)),
+	}
+	for i := range rows {
+		rows[i] = "  " + rows[i] + " " + labels[min(i, len(labels)-1)]
+	}
+	return strings.Join(rows, "\n")
+}
+
+var (
+	rigHistoryActiveStyle  = lipgloss.NewStyle().Foreground(colorActive)
+	rigHistoryIdleStyle    = lipgloss.NewStyle().Foreground(colorWarm)
+	rigHistoryProblemStyle = lipgloss.NewStyle().Foreground(colorWaiting)
+)
+
+// stackedBars renders one vertical bar per sample, height rows tall with
+// eighth-block resolution, top row first. Each cell takes the color of the
+// segment (problem, idle, active from the bottom) under the middle of its
+// filled part.
+func stackedBars(cols []rigSample, peak, height int) []string {
+	rows := make([]strings.Builder, height)
+	steps := height * 8
+	scale := func(n int) int {
+		if peak <= 0 {
+			return 0
+		}
+		return (n*steps + peak/2) / peak
+	}
+	for _, s := range cols {
+		top := scale(s.total())
+		if top == 0 && s.total() > 0 {
+			top = 1
+		}
+		problemTop, idleTop := scale(s.problem), scale(s.problem+s.idle)
+		for r := 0; r < height; r++ {
+			filled := min(max(top-r*8, 0), 8)
+			cell := &rows[height-1-r]
+			if filled == 0 {
+				cell.WriteRune(' ')
+				continue
+			}
+			style := rigHistoryActiveStyle
+			switch mid := r*8 + (filled-1)/2; {
+			case mid < problemTop:
+				style = rigHistoryProblemStyle
+			case mid < idleTop:
+				style = rigHistoryIdleStyle
+			}
+			cell.WriteString(style.Render(string(sparkBlocks[filled-1])))
+		}
+	}
+	out := make([]string, height)
+	for i := range rows {
+		out[i] = rows[i].String()
+	}
+	return out
+}
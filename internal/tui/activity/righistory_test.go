@@ -0,0 +1,71 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSampleRigHistory(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 30, 0, time.UTC)
+	m := &Model{agents: []*AgentLight{
+		{SessionName: "gt-gastown-Toast", Rig: "gastown", Level: LevelActive},
+		{SessionName: "gt-gastown-Nux", Rig: "gastown", Level: LevelWarm},
+		{SessionName: "gt-gastown-Slit", Rig: "gastown", Level: LevelWaitingForHuman},
+		{SessionName: "gt-gastown-Dag", Rig: "gastown", Level: LevelCold, MergeQueuePos: 1},
+		{SessionName: "gt-beads-crew-max", Rig: "beads", Level: LevelCold},
+	}}
+	m.sampleRigHistory(now) // off: nothing recorded
+	m.EnableRigHistory()
+
+	m.sampleRigHistory(now)
+	m.agents[0].Level = LevelCold
+	m.sampleRigHistory(now.Add(20 * time.Second)) // same minute: replaces
+	got := m.rigHistory.rigs["gastown"]
+	if len(got) != 1 || got[0].active != 0 || got[0].idle != 2 || got[0].problem != 2 {
+		t.Fatalf("gastown samples = %+v, want one minute of 0 active, 2 idle (one queued), 2 problem", got)
+	}
+
+	// Samples age out after the window.
+	m.sampleRigHistory(now.Add(rigHistoryMinutes * time.Minute))
+	if got := m.rigHistory.rigs["gastown"]; len(got) != 1 {
+		t.Errorf("after %dm: %d samples, want the old one dropped", rigHistoryMinutes, len(got))
+	}
+}
+
+func TestRenderRigHistory(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 30, 0, time.UTC)
+	m := &Model{}
+	if m.renderRigHistory("gastown", now) != "" || m.rigHistoryHeight("gastown") != 0 {
+		t.Fatal("rig history rendered while off")
+	}
+	m.EnableRigHistory()
+	minute := now.Truncate(time.Minute)
+	m.rigHistory.rigs["gastown"] = []rigSample{
+		{at: minute.Add(-10 * time.Minute), active: 4},
+		{at: minute, active: 1, idle: 1, problem: 2},
+	}
+
+	out := m.renderRigHistory("gastown", now)
+	lines := strings.Split(ansi.Strip(out), "\n")
+	if len(lines) != rigHistoryRows || m.rigHistoryHeight("gastown") != rigHistoryRows {
+		t.Fatalf("chart is %d rows, want %d:\n%s", len(lines), rigHistoryRows, out)
+	}
+	// The busiest minute fills both rows; the gap between is blank.
+	col := func(line string, i int) string { return string([]rune(line)[2+i]) }
+	if col(lines[0], 19) != "█" || col(lines[1], 19) != "█" || col(lines[1], 20) != " " {
+		t.Errorf("chart columns wrong:\n%s", strings.Join(lines, "\n"))
+	}
+	if !strings.Contains(lines[1], "1 active · 1 idle · 2 problem") {
+		t.Errorf("latest counts missing: %q", lines[1])
+	}
+}
+
+func TestStackedBarsColors(t *testing.T) {
+	rows := stackedBars([]rigSample{{problem: 1, active: 1}}, 2, 2)
+	if !strings.Contains(rows[1], rigHistoryProblemStyle.Render("█")) || !strings.Contains(rows[0], rigHistoryActiveStyle.Render("█")) {
+		t.Errorf("want problems at the bottom, active on top: %q", rows)
+	}
+}
@@ -0,0 +1,170 @@
+package activity
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Offline schedules (town settings top.offline): recurring windows when a
+// rig's agents, or sessions matching a glob, are expected to be down, like
+// a nightly pause. Inside one a quiet agent shows as "scheduled off" rather
+// than stuck, a missing role isn't flagged as not running, and no alerts,
+// limit_hit or loop_detected events go out for it.
+
+// offlineWindow is a parsed top.offline entry.
+type offlineWindow struct {
+	rig     string
+	session string        // glob
+	from    time.Duration // since local midnight
+	to      time.Duration
+	days    [7]bool // by the weekday the window starts on; none set is every day
+}
+
+// offlineDays maps the day names top.offline accepts to weekdays.
+var offlineDays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// SetOfflineWindows sets the windows of expected downtime. Invalid windows
+// are skipped and reported in the returned error; the rest still apply.
+func (m *Model) SetOfflineWindows(cfg []config.TopOfflineWindow) error {
+	var windows []offlineWindow
+	var errs []error
+	for _, c := range cfg {
+		w, err := parseOfflineWindow(c)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("top.offline %s-%s: %w", c.From, c.To, err))
+			continue
+		}
+		windows = append(windows, w)
+	}
+	m.offline = windows
+	return errors.Join(errs...)
+}
+
+func parseOfflineWindow(c config.TopOfflineWindow) (offlineWindow, error) {
+	w := offlineWindow{rig: c.Rig, session: c.Session}
+	if w.rig == "" && w.session == "" {
+		return w, fmt.Errorf("needs a rig or session")
+	}
+	if _, err := path.Match(w.session, ""); err != nil {
+		return w, fmt.Errorf("session %q: %w", w.session, err)
+	}
+	var err error
+	if w.from, err = parseClock(c.From); err != nil {
+		return w, err
+	}
+	if w.to, err = parseClock(c.To); err != nil {
+		return w, err
+	}
+	if w.from == w.to {
+		return w, fmt.Errorf("window is empty")
+	}
+	for _, d := range c.Days {
+		day, ok := offlineDays[strings.ToLower(d)]
+		if !ok {
+			return w, fmt.Errorf("unknown day %q (want mon ... sun)", d)
+		}
+		w.days[day] = true
+	}
+	return w, nil
+}
+
+// parseClock parses a time of day ("22:00") as the time since midnight.
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("time of day %q: want HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// covers reports whether the window applies to the agent.
+func (w offlineWindow) covers(a *AgentLight) bool {
+	if w.rig != "" && a.Rig == w.rig {
+		return true
+	}
+	if w.session != "" {
+		ok, _ := path.Match(w.session, a.SessionName)
+		return ok
+	}
+	return false
+}
+
+// startsOn reports whether the window opens on the given weekday.
+func (w offlineWindow) startsOn(d time.Weekday) bool {
+	if w.days == [7]bool{} {
+		return true
+	}
+	return w.days[d]
+}
+
+// openAt reports whether the window is open at now, and when it closes.
+func (w offlineWindow) openAt(now time.Time) (time.Time, bool) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	clock := now.Sub(midnight)
+	if w.from < w.to {
+		if clock >= w.from && clock < w.to && w.startsOn(now.Weekday()) {
+			return midnight.Add(w.to), true
+		}
+		return time.Time{}, false
+	}
+	// Past midnight: open from `from` today, or until `to` if it opened yesterday.
+	if clock >= w.from && w.startsOn(now.Weekday()) {
+		return midnight.AddDate(0, 0, 1).Add(w.to), true
+	}
+	if clock < w.to && w.startsOn(midnight.AddDate(0, 0, -1).Weekday()) {
+		return midnight.Add(w.to), true
+	}
+	return time.Time{}, false
+}
+
+// scheduledOffUntil reports whether the agent is in a window of expected
+// downtime at now, and when the last of its open windows closes.
+func (m *Model) scheduledOffUntil(a *AgentLight, now time.Time) (time.Time, bool) {
+	var until time.Time
+	for _, w := range m.offline {
+		if !w.covers(a) {
+			continue
+		}
+		if end, ok := w.openAt(now); ok && end.After(until) {
+			until = end
+		}
+	}
+	return until, !until.IsZero()
+}
+
+// applySchedules marks the agents in a window of expected downtime. Must
+// run after levels are computed: a scheduled-off cold agent counts as idle,
+// not stuck.
+func (m *Model) applySchedules(now time.Time) {
+	for _, a := range m.agents {
+		_, a.ScheduledOff = m.scheduledOffUntil(a, now)
+		if a.ScheduledOff && a.Level == LevelCold {
+			m.stuckCount--
+			m.idleCount++
+		}
+	}
+}
+
+// showsScheduledOff reports whether the agent is drawn as scheduled off: in
+// a window and quiet. One still working shows what it's doing.
+func (a *AgentLight) showsScheduledOff() bool {
+	return a.ScheduledOff && (a.Level == LevelCold || a.Level == LevelDead)
+}
+
+// scheduleDetail renders an agent's open window for the hover detail, e.g.
+// "scheduled off until 06:30: no alerts", or "".
+func (m *Model) scheduleDetail(a *AgentLight, now time.Time) string {
+	until, ok := m.scheduledOffUntil(a, now)
+	if !ok {
+		return ""
+	}
+	return "scheduled off until " + until.Format("15:04") + ": no alerts"
+}
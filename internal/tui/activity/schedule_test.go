@@ -0,0 +1,91 @@
+package activity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestOfflineWindowOpenAt(t *testing.T) {
+	m := &Model{}
+	err := m.SetOfflineWindows([]config.TopOfflineWindow{
+		{Rig: "gastown", From: "22:00", To: "06:30", Days: []string{"Mon"}}, // overnight, Monday nights
+		{Session: "gt-beads-*", From: "12:00", To: "13:00"},
+	})
+	if err != nil {
+		t.Fatalf("SetOfflineWindows: %v", err)
+	}
+	gastown := &AgentLight{SessionName: "gt-gastown-Toast", Rig: "gastown"}
+	beads := &AgentLight{SessionName: "gt-beads-witness", Rig: "beads"}
+
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, 10, day, hour, min, 0, 0, time.Local) // Oct 12 2026 is a Monday
+	}
+	tests := []struct {
+		name  string
+		a     *AgentLight
+		now   time.Time
+		want  bool
+		until string
+	}{
+		{"monday night", gastown, at(12, 23, 0), true, "06:30"},
+		{"tuesday early morning", gastown, at(13, 5, 0), true, "06:30"},
+		{"tuesday after the window", gastown, at(13, 7, 0), false, ""},
+		{"tuesday night: not a window day", gastown, at(13, 23, 0), false, ""},
+		{"monday early morning: opened sunday", gastown, at(12, 5, 0), false, ""},
+		{"lunch, glob", beads, at(14, 12, 30), true, "13:00"},
+		{"window end is exclusive", beads, at(14, 13, 0), false, ""},
+	}
+	for _, tt := range tests {
+		until, ok := m.scheduledOffUntil(tt.a, tt.now)
+		if ok != tt.want {
+			t.Errorf("%s: scheduled off = %v, want %v", tt.name, ok, tt.want)
+			continue
+		}
+		if ok && until.Format("15:04") != tt.until {
+			t.Errorf("%s: until %s, want %s", tt.name, until.Format("15:04"), tt.until)
+		}
+	}
+}
+
+func TestSetOfflineWindowsRejectsBadWindows(t *testing.T) {
+	m := &Model{}
+	err := m.SetOfflineWindows([]config.TopOfflineWindow{
+		{From: "22:00", To: "06:00"},                                      // no rig or session
+		{Rig: "gastown", From: "10pm", To: "06:00"},                       // bad time
+		{Rig: "gastown", From: "22:00", To: "06:00", Days: []string{"x"}}, // bad day
+		{Rig: "gastown", From: "22:00", To: "22:00"},                      // empty
+		{Rig: "beads", From: "01:00", To: "02:00"},
+	})
+	if err == nil {
+		t.Fatal("SetOfflineWindows accepted bad windows")
+	}
+	if len(m.offline) != 1 || m.offline[0].rig != "beads" {
+		t.Errorf("valid windows = %+v, want just the beads one", m.offline)
+	}
+}
+
+func TestApplySchedulesCountsIdleNotStuck(t *testing.T) {
+	m := &Model{}
+	_ = m.SetOfflineWindows([]config.TopOfflineWindow{{Rig: "gastown", From: "00:00", To: "23:59"}})
+	off := &AgentLight{SessionName: "gt-gastown-Toast", Rig: "gastown", Level: LevelCold}
+	stuck := &AgentLight{SessionName: "gt-beads-Nux", Rig: "beads", Level: LevelCold}
+	m.agents = []*AgentLight{off, stuck}
+	m.stuckCount = 2
+
+	m.applySchedules(time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local))
+	if !off.ScheduledOff || stuck.ScheduledOff {
+		t.Fatalf("ScheduledOff = %v, %v; want true, false", off.ScheduledOff, stuck.ScheduledOff)
+	}
+	if m.stuckCount != 1 || m.idleCount != 1 {
+		t.Errorf("stuck %d idle %d, want 1 and 1", m.stuckCount, m.idleCount)
+	}
+	if !off.showsScheduledOff() {
+		t.Error("a cold agent in its window isn't drawn as scheduled off")
+	}
+
+	if score, _ := townScore(m.agents, time.Now()); score != 50 {
+		t.Errorf("town score = %d, want 50 (only the stuck agent counts)", score)
+	}
+}
@@ -0,0 +1,85 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/telemetry"
+)
+
+// scoreRamp is how long an agent must stay blocked before it costs the town
+// score its full weight; until then it costs half, rising linearly, so a
+// brief rate limit dents the score and a morning-long one sinks it.
+const scoreRamp = 30 * time.Minute
+
+// scoreWeights is how much of its share of the score each blocked level
+// costs at full weight. An agent waiting on a human is the worst off: nothing
+// moves until someone looks. Levels not listed cost nothing.
+var scoreWeights = map[ActivityLevel]float64{
+	LevelWaitingForHuman: 1.0,
+	LevelHitLimit:        0.8,
+	LevelCold:            0.5, // stuck: no output for five minutes
+	LevelRateLimited:     0.3,
+}
+
+// townScore rates the town's health from 0 to 100: each agent holds an
+// equal share, and loses part of it while waiting on a human, at its usage
+// limit, stuck or rate-limited, weighted by how long it has been so. Dead
+// sessions, and agents scheduled off, don't count. ok is false when there are no agents to rate.
+func townScore(agents []*AgentLight, now time.Time) (score int, ok bool) {
+	var n int
+	var lost float64
+	for _, a := range agents {
+		if a.Level == LevelDead || a.ScheduledOff {
+			continue
+		}
+		n++
+		w := scoreWeights[a.Level]
+		if w == 0 {
+			continue
+		}
+		ramp := 1.0
+		if !a.LastChangeTime.IsZero() {
+			ramp = math.Min(1, float64(now.Sub(a.LastChangeTime))/float64(scoreRamp))
+		}
+		lost += w * (0.5 + 0.5*ramp)
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return int(math.Round(100 * (1 - lost/float64(n)))), true
+}
+
+// updateScore recomputes the town score after a poll and records it as the
+// gastown.town.health_score gauge, exported when telemetry is enabled.
+func (m *Model) updateScore(now time.Time) {
+	score, ok := townScore(m.agents, now)
+	m.healthScore, m.scored = score, ok
+	if !ok {
+		return
+	}
+	town := m.townName
+	if town == "" && m.townRoot != "" {
+		town = filepath.Base(m.townRoot)
+	}
+	telemetry.RecordTownHealth(context.Background(), town, score)
+}
+
+// renderScore renders the town score for the header: green when healthy,
+// orange when some agents are blocked, red when most are.
+func (m *Model) renderScore() string {
+	if !m.scored {
+		return ""
+	}
+	style := statActiveStyle
+	switch {
+	case m.healthScore < 50:
+		style = statWaitingStyle
+	case m.healthScore < 80:
+		style = statRateLimitedStyle
+	}
+	return style.Render(fmt.Sprintf("health %d", m.healthScore))
+}
@@ -0,0 +1,60 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTownScore(t *testing.T) {
+	now := time.Now()
+	agent := func(l ActivityLevel, since time.Duration) *AgentLight {
+		return &AgentLight{Level: l, LastChangeTime: now.Add(-since)}
+	}
+	tests := []struct {
+		name   string
+		agents []*AgentLight
+		want   int
+	}{
+		{"all working", []*AgentLight{agent(LevelActive, 0), agent(LevelWarm, time.Minute)}, 100},
+		{"one of two just started waiting", []*AgentLight{agent(LevelActive, 0), agent(LevelWaitingForHuman, 0)}, 75},
+		{"one of two waiting all morning", []*AgentLight{agent(LevelActive, 0), agent(LevelWaitingForHuman, 3*time.Hour)}, 50},
+		{"hit limit for half the ramp", []*AgentLight{agent(LevelHitLimit, scoreRamp/2)}, 40},
+		{"stuck and rate-limited", []*AgentLight{agent(LevelCold, time.Hour), agent(LevelRateLimited, time.Hour)}, 60},
+		{"dead sessions don't count", []*AgentLight{agent(LevelActive, 0), agent(LevelDead, time.Hour)}, 100},
+	}
+	for _, tt := range tests {
+		got, ok := townScore(tt.agents, now)
+		if !ok || got != tt.want {
+			t.Errorf("%s: townScore = %d (ok %v), want %d", tt.name, got, ok, tt.want)
+		}
+	}
+	if _, ok := townScore([]*AgentLight{agent(LevelDead, 0)}, now); ok {
+		t.Error("a town with no live agents was scored")
+	}
+}
+
+func TestScore_HeaderAndStatusFile(t *testing.T) {
+	town := t.TempDir()
+	now := time.Now()
+	m := &Model{width: 120, townRoot: town, agents: []*AgentLight{
+		{SessionName: "gt-gastown-Toast", Level: LevelActive, LastChangeTime: now},
+		{SessionName: "gt-gastown-Nux", Level: LevelWaitingForHuman, LastChangeTime: now.Add(-time.Hour)},
+	}}
+	if strings.Contains(m.renderHeader(), "health") {
+		t.Error("header shows a score before one was computed")
+	}
+
+	m.updateScore(now)
+	if h := m.renderHeader(); !strings.Contains(h, "health 50") {
+		t.Errorf("header = %q, want health 50", h)
+	}
+	m.writeStatus(now)
+	s, err := ReadStatus(town)
+	if err != nil {
+		t.Fatalf("ReadStatus: %v", err)
+	}
+	if s.HealthScore == nil || *s.HealthScore != 50 {
+		t.Errorf("status file health_score = %v, want 50", s.HealthScore)
+	}
+}
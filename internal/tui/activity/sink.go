@@ -0,0 +1,133 @@
+package activity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Sinks take the town's status after every poll (gt top --web, --metrics,
+// --json-out, --events), so one gt top feeds them all from a single tmux
+// poll instead of each re-polling tmux on its own. They run alongside the
+// TUI, or on their own with --ui=false.
+
+// Sink receives the status gt top assembles on every poll. Publish runs on
+// the UI goroutine, once the poll is reduced, so a sink should hand slow
+// work off rather than block it. The status is the sink's own to keep.
+type Sink interface {
+	// Name identifies the sink in error messages, e.g. "web :8080".
+	Name() string
+	// Publish takes the status as of a poll.
+	Publish(s Status) error
+	// Close releases the sink's resources (listeners, files).
+	Close() error
+}
+
+// AddSink makes gt top publish its status to s after every poll.
+func (m *Model) AddSink(s Sink) {
+	m.sinks = append(m.sinks, s)
+}
+
+// SinkNames returns the names of the sinks, in the order they were added.
+func (m *Model) SinkNames() []string {
+	names := make([]string, len(m.sinks))
+	for i, s := range m.sinks {
+		names[i] = s.Name()
+	}
+	return names
+}
+
+// CloseSinks closes every sink. Errors are ignored: gt top is exiting.
+func (m *Model) CloseSinks() {
+	for _, s := range m.sinks {
+		_ = s.Close()
+	}
+}
+
+// publishSinks hands the poll's status to every sink. A failing sink is
+// flashed once, like the status report, and not again until it recovers.
+func (m *Model) publishSinks(now time.Time) {
+	if len(m.sinks) == 0 {
+		return
+	}
+	status := m.Snapshot(now)
+	for _, s := range m.sinks {
+		err := s.Publish(status.clone()) // each sink's own, shared with nothing (snapshot.go)
+		failed := m.sinkFailed[s]
+		if err != nil && !failed {
+			m.flashMessage = s.Name() + ": " + err.Error()
+			m.flashTime = now
+		}
+		if m.sinkFailed == nil {
+			m.sinkFailed = make(map[Sink]bool)
+		}
+		m.sinkFailed[s] = err != nil
+	}
+}
+
+// JSONFileSink rewrites a file with the status, as JSON, on every poll:
+// the town's status file (.runtime/top-status.json) at a path of your own.
+type JSONFileSink struct {
+	path string
+}
+
+// NewJSONFileSink returns a sink that writes the status to path.
+func NewJSONFileSink(path string) *JSONFileSink {
+	return &JSONFileSink{path: path}
+}
+
+// Name implements Sink.
+func (s *JSONFileSink) Name() string { return "json " + s.path }
+
+// Publish implements Sink.
+func (s *JSONFileSink) Publish(st Status) error {
+	return util.EnsureDirAndWriteJSON(s.path, st)
+}
+
+// Close implements Sink.
+func (s *JSONFileSink) Close() error { return nil }
+
+// EventsSink logs an agent_observation event to the town's events file
+// whenever an agent's level changes, for feeds and tools that follow the
+// log rather than poll.
+type EventsSink struct {
+	townRoot string
+	levels   map[string]string // session -> level at the last poll
+}
+
+// NewEventsSink returns a sink that logs level changes in townRoot.
+func NewEventsSink(townRoot string) *EventsSink {
+	return &EventsSink{townRoot: townRoot}
+}
+
+// Name implements Sink.
+func (s *EventsSink) Name() string { return "events" }
+
+// Publish implements Sink. The first poll only records the levels: gt top
+// starting up isn't a change.
+func (s *EventsSink) Publish(st Status) error {
+	levels := make(map[string]string, len(st.Agents))
+	var firstErr error
+	for _, a := range st.Agents {
+		levels[a.Session] = a.Level
+		prev, seen := s.levels[a.Session]
+		if s.levels == nil || (seen && prev == a.Level) {
+			continue
+		}
+		name := a.Session
+		if i := strings.LastIndex(a.AgentID, "/"); i >= 0 {
+			name = a.AgentID[i+1:]
+		}
+		payload := events.AgentObservationPayload(a.Session, name, a.Role, a.Rig, a.Level, "")
+		if err := events.LogInTown(s.townRoot, "gt-top", events.TypeAgentObservation, "gt-top", payload, events.VisibilityAudit); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	s.levels = levels
+	return firstErr
+}
+
+// Close implements Sink.
+func (s *EventsSink) Close() error { return nil }
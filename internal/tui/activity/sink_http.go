@@ -0,0 +1,160 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// httpSink serves the latest status over HTTP. The listener is bound when
+// the sink is made, so a taken port fails gt top at startup rather than
+// on the first poll.
+type httpSink struct {
+	name   string
+	server *http.Server
+
+	mu     sync.Mutex
+	status *Status // nil until the first poll
+}
+
+func newHTTPSink(kind, addr string, routes func(mux *http.ServeMux, s *httpSink)) (*httpSink, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", kind, err)
+	}
+	s := &httpSink{name: kind + " " + ln.Addr().String()}
+	mux := http.NewServeMux()
+	routes(mux, s)
+	s.server = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() { _ = s.server.Serve(ln) }()
+	return s, nil
+}
+
+// Name implements Sink.
+func (s *httpSink) Name() string { return s.name }
+
+// Publish implements Sink.
+func (s *httpSink) Publish(st Status) error {
+	s.mu.Lock()
+	s.status = &st
+	s.mu.Unlock()
+	return nil
+}
+
+// Close implements Sink.
+func (s *httpSink) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+// latest returns the status of the last poll, or nil before the first.
+func (s *httpSink) latest() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// serveStatusJSON serves the latest status as JSON.
+func (s *httpSink) serveStatusJSON(w http.ResponseWriter, r *http.Request) {
+	st := s.latest()
+	if st == nil {
+		http.Error(w, "no poll yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(st)
+}
+
+// NewWebSink returns a sink serving a self-refreshing agent table at / and
+// the status as JSON at /status.json on addr (e.g. "localhost:7878").
+func NewWebSink(addr string) (Sink, error) {
+	return newHTTPSink("web", addr, func(mux *http.ServeMux, s *httpSink) {
+		mux.HandleFunc("/status.json", s.serveStatusJSON)
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_ = webPage.Execute(w, s.latest())
+		})
+	})
+}
+
+// webPage is the web sink's agent table. It reloads itself every few
+// seconds; there is nothing to click.
+var webPage = template.Must(template.New("top").Funcs(template.FuncMap{
+	"idle": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return formatElapsed(time.Since(t))
+	},
+}).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><meta http-equiv="refresh" content="5"><title>gt top</title>
+<style>body{font-family:monospace;background:#111;color:#ddd}td,th{padding:2px 12px;text-align:left}
+.waiting,.hit_limit{color:#f55}.rate_limited{color:#fa3}.active,.recent{color:#5f5}.warm{color:#fc5}.cool{color:#999}.cold{color:#555}</style>
+</head><body>
+{{if .}}<p>updated {{.UpdatedAt.Format "15:04:05"}}{{with .HealthScore}} · health {{.}}{{end}} · {{len .Agents}} agents</p>
+<table><tr><th>Session</th><th>Rig</th><th>Role</th><th>Level</th><th>Idle</th><th>Bead</th></tr>
+{{range .Agents}}<tr><td>{{.Session}}</td><td>{{.Rig}}</td><td>{{.Role}}</td><td class="{{.Level}}">{{.Level}}{{if .Muted}} (muted){{end}}{{if .ScheduledOff}} (scheduled off){{end}}</td><td>{{idle .LastActivity}}</td><td>{{.WorkBead}}</td></tr>
+{{end}}</table>{{else}}<p>waiting for the first poll…</p>{{end}}
+</body></html>
+`))
+
+// NewMetricsSink returns a sink serving the status in the Prometheus text
+// format at /metrics on addr (e.g. "localhost:9464").
+func NewMetricsSink(addr string) (Sink, error) {
+	return newHTTPSink("metrics", addr, func(mux *http.ServeMux, s *httpSink) {
+		mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			st := s.latest()
+			if st == nil {
+				http.Error(w, "no poll yet", http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+			_, _ = w.Write([]byte(renderMetrics(st, time.Now())))
+		})
+	})
+}
+
+// renderMetrics renders a status as Prometheus metrics.
+func renderMetrics(st *Status, now time.Time) string {
+	var b strings.Builder
+	counts := make(map[string]int)
+	for _, a := range st.Agents {
+		counts[a.Level]++
+	}
+	b.WriteString("# HELP gastown_agents Agents by activity level.\n# TYPE gastown_agents gauge\n")
+	for _, l := range LevelNames() {
+		fmt.Fprintf(&b, "gastown_agents{level=%q} %d\n", l, counts[l])
+	}
+
+	b.WriteString("# HELP gastown_agent_idle_seconds Seconds since the agent's last activity.\n# TYPE gastown_agent_idle_seconds gauge\n")
+	for _, a := range st.Agents {
+		idle := 0.0
+		if !a.LastActivity.IsZero() {
+			idle = now.Sub(a.LastActivity).Seconds()
+		}
+		fmt.Fprintf(&b, "gastown_agent_idle_seconds{session=\"%s\",rig=\"%s\",role=\"%s\",level=\"%s\",muted=\"%t\"} %.0f\n",
+			metricLabel(a.Session), metricLabel(a.Rig), metricLabel(a.Role), metricLabel(a.Level), a.Muted, idle)
+	}
+
+	if st.HealthScore != nil {
+		fmt.Fprintf(&b, "# HELP gastown_health_score Town health score, 0-100.\n# TYPE gastown_health_score gauge\ngastown_health_score %d\n", *st.HealthScore)
+	}
+	fmt.Fprintf(&b, "# HELP gastown_top_updated_timestamp_seconds When gt top last polled.\n# TYPE gastown_top_updated_timestamp_seconds gauge\ngastown_top_updated_timestamp_seconds %d\n", st.UpdatedAt.Unix())
+	return b.String()
+}
+
+// metricLabel escapes a Prometheus label value.
+func metricLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
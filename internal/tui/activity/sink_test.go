@@ -0,0 +1,139 @@
+package activity
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// recordingSink remembers what it was published, failing while err is set.
+type recordingSink struct {
+	got []Status
+	err error
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+func (s *recordingSink) Publish(st Status) error {
+	s.got = append(s.got, st)
+	return s.err
+}
+func (s *recordingSink) Close() error { return nil }
+
+func TestPublishSinks(t *testing.T) {
+	now := time.Now()
+	m := &Model{agents: []*AgentLight{{SessionName: "gt-gastown-Toast", Level: LevelActive}}}
+	a, b := &recordingSink{}, &recordingSink{err: errors.New("disk full")}
+	m.AddSink(a)
+	m.AddSink(b)
+
+	m.publishSinks(now)
+	if len(a.got) != 1 || len(b.got) != 1 || a.got[0].Agents[0].Level != "active" {
+		t.Fatalf("sinks got %+v and %+v, want one status each", a.got, b.got)
+	}
+	if !strings.Contains(m.flashMessage, "disk full") {
+		t.Errorf("flash = %q, want the failing sink's error", m.flashMessage)
+	}
+
+	// The same failure isn't flashed on every poll.
+	m.flashMessage = ""
+	m.publishSinks(now)
+	if m.flashMessage != "" {
+		t.Errorf("failure flashed again: %q", m.flashMessage)
+	}
+}
+
+func TestJSONFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "status.json")
+	s := NewJSONFileSink(path)
+	if err := s.Publish(Status{Agents: []AgentStatus{{Session: "gt-gastown-Toast", Level: "cold"}}}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Status
+	if err := json.Unmarshal(data, &got); err != nil || len(got.Agents) != 1 || got.Agents[0].Level != "cold" {
+		t.Errorf("file = %s (%v)", data, err)
+	}
+}
+
+func TestEventsSink(t *testing.T) {
+	townRoot := t.TempDir()
+	s := NewEventsSink(townRoot)
+	status := func(level string) Status {
+		return Status{Agents: []AgentStatus{{Session: "gt-gastown-Toast", AgentID: "gastown/polecats/Toast", Rig: "gastown", Role: "polecat", Level: level}}}
+	}
+	for _, level := range []string{"active", "active", "cold"} {
+		if err := s.Publish(status(level)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(filepath.Join(townRoot, events.EventsFile))
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("events = %q, want only the change to cold", lines)
+	}
+	var evt events.Event
+	if err := json.Unmarshal([]byte(lines[0]), &evt); err != nil {
+		t.Fatal(err)
+	}
+	if evt.Type != events.TypeAgentObservation || evt.Payload["level"] != "cold" || evt.Payload["name"] != "Toast" {
+		t.Errorf("event = %+v", evt)
+	}
+}
+
+func TestHTTPSinks(t *testing.T) {
+	web, err := NewWebSink("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer web.Close()
+	metrics, err := NewMetricsSink("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer metrics.Close()
+
+	get := func(s Sink, path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get("http://" + strings.Fields(s.Name())[1] + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+	if code, _ := get(metrics, "/metrics"); code != http.StatusServiceUnavailable {
+		t.Errorf("metrics before the first poll: %d", code)
+	}
+
+	score := 87
+	st := Status{UpdatedAt: time.Now(), HealthScore: &score, Agents: []AgentStatus{
+		{Session: "gt-gastown-Toast", Rig: "gastown", Role: "polecat", Level: "waiting"},
+	}}
+	_ = web.Publish(st)
+	_ = metrics.Publish(st)
+
+	if _, body := get(web, "/"); !strings.Contains(body, "gt-gastown-Toast") || !strings.Contains(body, "health 87") {
+		t.Errorf("web page = %s", body)
+	}
+	if _, body := get(web, "/status.json"); !strings.Contains(body, `"session":"gt-gastown-Toast"`) {
+		t.Errorf("status.json = %s", body)
+	}
+	_, body := get(metrics, "/metrics")
+	for _, want := range []string{`gastown_agents{level="waiting"} 1`, `gastown_agents{level="active"} 0`, `session="gt-gastown-Toast"`, "gastown_health_score 87"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
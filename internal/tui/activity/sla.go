@@ -0,0 +1,69 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/feed"
+)
+
+// slaPollInterval is how often rig SLAs are re-evaluated. SLA evaluation
+// scans the whole SLA window of the events file, so it runs far less often
+// than the tmux poll.
+const slaPollInterval = 30 * time.Second
+
+var (
+	slaOKStyle     = lipgloss.NewStyle().Foreground(colorActive)
+	slaBreachStyle = lipgloss.NewStyle().Foreground(colorWaiting).Bold(true)
+)
+
+// pollRigSLAs re-evaluates rig SLAs and failure-rate SLOs from the events
+// file when configured (town settings rig_slas) and the poll interval has
+// elapsed.
+func (m *Model) pollRigSLAs(now time.Time) {
+	if m.slaConfig == nil || m.townRoot == "" {
+		return
+	}
+	if !m.lastSLAPoll.IsZero() && now.Sub(m.lastSLAPoll) < slaPollInterval {
+		return
+	}
+	m.lastSLAPoll = now
+
+	window := config.ParseDurationOrDefault(m.slaConfig.Window, feed.DefaultSLAWindow)
+	evts, err := feed.ReadDigestEvents(m.townRoot, now.Add(-max(window, m.sloWindow())))
+	if err != nil {
+		return // keep the previous result rather than blanking the badges
+	}
+	m.rigSLAs = feed.EvaluateRigSLAs(evts, m.slaConfig, now)
+	m.rigSLOs = feed.EvaluateRigSLOs(evts, m.slaConfig, now)
+}
+
+// renderSLABadges renders a rig's SLA checks for its panel header, e.g.
+// "merge≤30m ✓  ack≤10m ✗2". Returns "" when the rig has no SLAs.
+func renderSLABadges(rs *feed.RigSLA) string {
+	if rs == nil {
+		return ""
+	}
+	var badges []string
+	for _, c := range rs.Checks {
+		label := fmt.Sprintf("%s≤%s", c.Name, formatSLATarget(c.Target))
+		if c.Breaches > 0 {
+			badges = append(badges, slaBreachStyle.Render(fmt.Sprintf("%s ✗%d", label, c.Breaches)))
+		} else {
+			badges = append(badges, slaOKStyle.Render(label+" ✓"))
+		}
+	}
+	return strings.Join(badges, "  ")
+}
+
+// formatSLATarget renders a target compactly: 30m, 1h, 1h30m.
+func formatSLATarget(d time.Duration) string {
+	s := strings.TrimSuffix(d.String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package activity
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/feed"
+)
+
+func TestFormatSLATarget(t *testing.T) {
+	tests := map[time.Duration]string{
+		30 * time.Minute: "30m",
+		time.Hour:        "1h",
+		90 * time.Minute: "1h30m",
+		45 * time.Second: "45s",
+	}
+	for d, want := range tests {
+		if got := formatSLATarget(d); got != want {
+			t.Errorf("formatSLATarget(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestRenderSLABadges(t *testing.T) {
+	if got := renderSLABadges(nil); got != "" {
+		t.Errorf("renderSLABadges(nil) = %q, want empty", got)
+	}
+	got := renderSLABadges(&feed.RigSLA{Rig: "gastown", Checks: []feed.SLACheck{
+		{Name: feed.SLAMergeQueueDrain, Target: 30 * time.Minute, Breaches: 2},
+		{Name: feed.SLAEscalationAck, Target: 10 * time.Minute},
+	}})
+	if !strings.Contains(got, "merge≤30m ✗2") || !strings.Contains(got, "ack≤10m ✓") {
+		t.Errorf("renderSLABadges = %q", got)
+	}
+}
+
+func TestPollRigSLAs(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	done := events.Event{
+		Timestamp: now.Add(-time.Hour).UTC().Format(time.RFC3339),
+		Type:      events.TypeDone,
+		Actor:     "gastown/polecats/Toast",
+		Payload:   events.DonePayload("gt-1", "polecat/Toast"),
+	}
+	data, _ := json.Marshal(done)
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), append(data, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Model{
+		townRoot:  townRoot,
+		slaConfig: &config.RigSLAConfig{Default: &config.RigSLATargets{MergeQueueDrain: "30m"}},
+	}
+	m.pollRigSLAs(now)
+	rs := m.rigSLAs["gastown"]
+	if rs == nil || !rs.Breached() {
+		t.Fatalf("rigSLAs[gastown] = %+v, want merge breach for a branch waiting 1h", rs)
+	}
+
+	// Within the poll interval the cached result is kept.
+	m.rigSLAs = nil
+	m.pollRigSLAs(now.Add(time.Second))
+	if m.rigSLAs != nil {
+		t.Error("expected no re-evaluation within slaPollInterval")
+	}
+}
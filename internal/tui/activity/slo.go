@@ -0,0 +1,121 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/feed"
+)
+
+// sloBurnCondition is the top.alerts condition for a rig whose failure-rate
+// SLO is burning. It is raised per rig rather than per agent.
+const sloBurnCondition = "slo_burn"
+
+// renderSLOBadges renders a rig's failure-rate SLOs for its panel header,
+// e.g. "merge-fail≤20% ✓  escalate≤10% ✗35%". Returns "" when the rig has
+// no SLOs.
+func renderSLOBadges(rs *feed.RigSLO) string {
+	if rs == nil {
+		return ""
+	}
+	var badges []string
+	for _, c := range rs.Checks {
+		label := c.Name + "≤" + formatSLORate(c.Target)
+		if c.Burning() {
+			badges = append(badges, slaBreachStyle.Render(label+" ✗"+formatSLORate(c.Rate())))
+		} else {
+			badges = append(badges, slaOKStyle.Render(label+" ✓"))
+		}
+	}
+	return strings.Join(badges, "  ")
+}
+
+// formatSLORate renders a rate as a whole percentage: 0.2 → "20%".
+func formatSLORate(r float64) string {
+	return fmt.Sprintf("%.0f%%", r*100)
+}
+
+// sloWindow returns the rolling window SLOs are measured over.
+func (m *Model) sloWindow() time.Duration {
+	if m.slaConfig == nil {
+		return feed.DefaultSLOWindow
+	}
+	return config.ParseDurationOrDefault(m.slaConfig.SLOWindow, feed.DefaultSLOWindow)
+}
+
+// sloBurnSummary describes a rig's burning SLOs, e.g.
+// "gastown: 7/20 merges failed (35% > 20%) in the last 1h".
+func (m *Model) sloBurnSummary(rs *feed.RigSLO) string {
+	var parts []string
+	for _, c := range rs.Checks {
+		if !c.Burning() {
+			continue
+		}
+		what := "merges failed"
+		if c.Name == feed.SLOEscalations {
+			what = "finished work escalated"
+		}
+		parts = append(parts, fmt.Sprintf("%d/%d %s (%s > %s)",
+			c.Failures, c.Total, what, formatSLORate(c.Rate()), formatSLORate(c.Target)))
+	}
+	return fmt.Sprintf("%s: %s in the last %s", rs.Rig, strings.Join(parts, ", "), formatSLATarget(m.sloWindow()))
+}
+
+// renderSLOBanner renders the SLO burn alert shown under the header: a
+// systemic failure rate that no single agent's light shows. Returns "" while
+// no SLO is burning.
+func (m *Model) renderSLOBanner() string {
+	burning := feed.BurningSLOs(m.rigSLOs)
+	if len(burning) == 0 {
+		return ""
+	}
+	var parts []string
+	for _, rs := range burning {
+		parts = append(parts, m.sloBurnSummary(rs))
+	}
+	style := slaBreachStyle
+	if m.width > 4 {
+		style = style.MaxWidth(m.width - 4) // one line, so hover rows stay aligned
+	}
+	return style.Render("⚠ SLO burn · " + strings.Join(parts, " · "))
+}
+
+// escalateSLOAlerts advances the slo_burn alert ladders, one per rig, and
+// returns the notifications now due. Ladders re-arm once the rig's failure
+// rates fall back within target.
+func (m *Model) escalateSLOAlerts(now time.Time) []alert {
+	var due []alert
+	for _, r := range m.alertRules {
+		if r.condition != sloBurnCondition {
+			continue
+		}
+		for rig := range m.sloAlerts {
+			if rs := m.rigSLOs[rig]; rs == nil || !rs.Burning() {
+				delete(m.sloAlerts, rig)
+			}
+		}
+		for _, rs := range feed.BurningSLOs(m.rigSLOs) {
+			if m.sloAlerts == nil {
+				m.sloAlerts = make(map[string]*alertState)
+			}
+			st := m.sloAlerts[rs.Rig]
+			if st == nil {
+				st = &alertState{since: now}
+				m.sloAlerts[rs.Rig] = st
+			}
+			for st.fired < len(r.ladder) && now.Sub(st.since) >= r.ladder[st.fired].after {
+				due = append(due, alert{
+					notify:    r.ladder[st.fired].notify,
+					session:   "rig-" + rs.Rig,
+					agent:     rs.Rig,
+					condition: sloBurnCondition,
+					summary:   "SLO BURN: " + m.sloBurnSummary(rs),
+				})
+				st.fired++
+			}
+		}
+	}
+	return due
+}
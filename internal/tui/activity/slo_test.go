@@ -0,0 +1,73 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/feed"
+)
+
+func burningSLO() *feed.RigSLO {
+	return &feed.RigSLO{Rig: "gastown", Checks: []feed.SLOCheck{
+		{Name: feed.SLOMergeFailures, Target: 0.2, Failures: 7, Total: 20},
+		{Name: feed.SLOEscalations, Target: 0.1, Failures: 1, Total: 20},
+	}}
+}
+
+func TestRenderSLOBadges(t *testing.T) {
+	if got := renderSLOBadges(nil); got != "" {
+		t.Errorf("renderSLOBadges(nil) = %q, want empty", got)
+	}
+	got := renderSLOBadges(burningSLO())
+	if !strings.Contains(got, "merge-fail≤20% ✗35%") || !strings.Contains(got, "escalate≤10% ✓") {
+		t.Errorf("renderSLOBadges = %q", got)
+	}
+}
+
+func TestRenderSLOBanner(t *testing.T) {
+	m := &Model{}
+	if got := m.renderSLOBanner(); got != "" {
+		t.Errorf("banner with no SLOs = %q, want empty", got)
+	}
+	m.rigSLOs = map[string]*feed.RigSLO{"gastown": burningSLO()}
+	got := m.renderSLOBanner()
+	if !strings.Contains(got, "SLO burn") || !strings.Contains(got, "gastown: 7/20 merges failed (35% > 20%) in the last 1h") {
+		t.Errorf("banner = %q", got)
+	}
+	if strings.Contains(got, "escalated") {
+		t.Errorf("banner lists an SLO within target: %q", got)
+	}
+}
+
+func TestEscalateSLOAlerts(t *testing.T) {
+	now := time.Now()
+	m := &Model{
+		alertRules: []alertRule{{condition: sloBurnCondition, ladder: []alertStep{
+			{notify: "desktop"}, {after: 30 * time.Minute, notify: "slack"},
+		}}},
+		rigSLOs: map[string]*feed.RigSLO{"gastown": burningSLO()},
+	}
+
+	due := m.escalateSLOAlerts(now)
+	if len(due) != 1 || due[0].notify != "desktop" || due[0].agent != "gastown" ||
+		!strings.HasPrefix(due[0].summary, "SLO BURN: gastown:") {
+		t.Fatalf("first poll = %+v, want the desktop step for gastown", due)
+	}
+	if due := m.escalateSLOAlerts(now.Add(time.Minute)); len(due) != 0 {
+		t.Errorf("second poll re-sent %+v", due)
+	}
+	if due := m.escalateSLOAlerts(now.Add(31 * time.Minute)); len(due) != 1 || due[0].notify != "slack" {
+		t.Errorf("after 31m = %+v, want the slack step", due)
+	}
+
+	// Recovery re-arms the ladder.
+	m.rigSLOs["gastown"].Checks[0].Failures = 1
+	if due := m.escalateSLOAlerts(now.Add(time.Hour)); len(due) != 0 || len(m.sloAlerts) != 0 {
+		t.Errorf("recovered: due %+v, state %+v", due, m.sloAlerts)
+	}
+	m.rigSLOs["gastown"].Checks[0].Failures = 7
+	if due := m.escalateSLOAlerts(now.Add(2 * time.Hour)); len(due) != 1 || due[0].notify != "desktop" {
+		t.Errorf("burning again = %+v, want the ladder to restart", due)
+	}
+}
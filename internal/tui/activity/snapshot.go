@@ -0,0 +1,109 @@
+package activity
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// pollInputs is what a poll needs from the Model, captured on the UI
+// goroutine when the poll command is made, so the poll goroutine never reads
+// the Model: the session filter and capture depths are copies. The
+// collector pool and the container, pod and OpenCode sources are shared by
+// pointer; the pool locks its own state (collector.go), and the sources'
+// mutable state is only touched by the poll, one poll at a time.
+type pollInputs struct {
+	filter     sessionFilter
+	depths     map[string]int
+	collectors *collectorPool
+	docker     *dockerSource
+	kubernetes *kubernetesSource
+	openCode   *openCodeSource
+}
+
+// pollInputs captures the poll's inputs.
+func (m *Model) pollInputs() pollInputs {
+	if m.collectors == nil {
+		m.collectors = newCollectorPool()
+	}
+	return pollInputs{
+		filter:     sessionFilter{include: slices.Clone(m.filter.include), exclude: slices.Clone(m.filter.exclude)},
+		depths:     m.captureDepths(),
+		collectors: m.collectors,
+		docker:     m.docker,
+		kubernetes: m.kubernetes,
+		openCode:   m.openCode,
+	}
+}
+
+// poll lists the sessions and captures their panes, off the UI goroutine.
+func (in pollInputs) poll() tea.Msg {
+	start := time.Now()
+	sessions, err := listSessions()
+	sources := map[string]error{"tmux": err}
+	if err != nil {
+		// Without a tmux server a town can still run agents in
+		// containers; a hung tmux is reported either way.
+		if (in.docker == nil && in.kubernetes == nil) || errors.Is(err, tmux.ErrCommandTimeout) {
+			return sessionsMsg{sessions: nil, elapsed: time.Since(start), err: err, sources: sources}
+		}
+		sessions = nil
+	}
+	sessions = in.filter.filterSessions(sessions)
+	clients := listClientTouches()
+	for i := range sessions {
+		sessions[i].touch = sessionTouch(sessions[i].lastAttached, clients[sessions[i].name])
+	}
+
+	// Each session's pane is captured by its own collector goroutine,
+	// so one wedged session can't stall the poll (collector.go).
+	for i := range sessions {
+		sessions[i].captureDepth = in.depths[sessions[i].name]
+	}
+	resolveAgentPanes(sessions)
+	in.collectors.collect(sessions, collectWait)
+
+	if in.openCode != nil {
+		api := in.openCode.fetch(sessions, time.Now())
+		for i := range sessions {
+			sessions[i].openCode = api[sessions[i].name]
+		}
+	}
+
+	// Container and pod agents fill in around tmux; a session name
+	// is shown once, from the first source that has it.
+	skip := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		skip[s.name] = true
+	}
+	if in.docker != nil {
+		sessions = append(sessions, in.filter.filterSessions(in.docker.poll(skip, in.depths, time.Now()))...)
+		sources["docker"] = in.docker.err
+	}
+	if in.kubernetes != nil {
+		sessions = append(sessions, in.filter.filterSessions(in.kubernetes.poll(skip, in.depths, time.Now()))...)
+		sources["kubernetes"] = in.kubernetes.err
+	}
+
+	return sessionsMsg{sessions: sessions, elapsed: time.Since(start), sources: sources}
+}
+
+// clone returns a copy of the status sharing nothing with s.
+func (s Status) clone() Status {
+	c := s
+	if s.HealthScore != nil {
+		score := *s.HealthScore
+		c.HealthScore = &score
+	}
+	c.Agents = slices.Clone(s.Agents)
+	for i, a := range c.Agents {
+		if a.Progress != nil {
+			pct := *a.Progress
+			c.Agents[i].Progress = &pct
+		}
+	}
+	return c
+}
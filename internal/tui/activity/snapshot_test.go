@@ -0,0 +1,86 @@
+package activity
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// These tests share state across goroutines the way gt top does; under
+// -race (as CI runs them) they fail if a snapshot aliases the Model.
+
+func TestPollInputs_Snapshot(t *testing.T) {
+	m := &Model{filter: sessionFilter{include: []string{"gt-*"}, exclude: []string{"gt-scratch*"}}}
+	in := m.pollInputs()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() { // the poll goroutine
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = in.filter.allows("gt-gastown-Toast")
+		}
+	}()
+	for i := 0; i < 100; i++ { // the UI goroutine
+		m.filter.include[0] = "bd-*"
+		m.filter.exclude[0] = "bd-scratch*"
+	}
+	wg.Wait()
+
+	if !in.filter.allows("gt-gastown-Toast") || in.filter.allows("gt-scratch") {
+		t.Errorf("poll filter %+v changed with the Model's", in.filter)
+	}
+	if in.collectors == nil || in.collectors != m.collectors {
+		t.Error("poll inputs don't carry the Model's collector pool")
+	}
+}
+
+// mutatingSink rewrites the status it's given, as a careless sink might.
+type mutatingSink struct{}
+
+func (mutatingSink) Name() string { return "mutating" }
+func (mutatingSink) Publish(st Status) error {
+	for i := range st.Agents {
+		st.Agents[i].Level = "scribbled"
+	}
+	if st.HealthScore != nil {
+		*st.HealthScore = -1
+	}
+	return nil
+}
+func (mutatingSink) Close() error { return nil }
+
+func TestPublishSinks_OwnCopies(t *testing.T) {
+	web := &httpSink{}
+	m := &Model{scored: true, healthScore: 90, agents: []*AgentLight{{SessionName: "gt-gastown-Toast", Level: LevelActive}}}
+	m.AddSink(web)
+	m.AddSink(mutatingSink{})
+	m.publishSinks(time.Now())
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() { // an HTTP handler serving the web sink's status
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				_ = json.NewEncoder(io.Discard).Encode(web.latest())
+			}
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		m.publishSinks(time.Now())
+	}
+	close(stop)
+	wg.Wait()
+
+	st := web.latest()
+	if st.Agents[0].Level != "active" || *st.HealthScore != 90 {
+		t.Errorf("web sink status = %+v (score %d), changed by another sink", st.Agents[0], *st.HealthScore)
+	}
+}
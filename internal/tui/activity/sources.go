@@ -0,0 +1,114 @@
+package activity
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Degraded sources: gt top reads tmux, pane captures, the events file, the
+// rigs' beads and, when enabled, Docker and Kubernetes. When some of them
+// fail it keeps showing what the rest say, so a persistent footer lists each
+// failing source, why, and when it was last read successfully, rather than
+// passing stale or partial data off as current.
+
+// sourceHealth is one data source's latest outcome.
+type sourceHealth struct {
+	lastOK   time.Time // last successful read; zero if never
+	err      string    // latest failure, "" while reads succeed
+	failedAt time.Time // when the current run of failures started
+}
+
+// noteSource records a read of a source: err nil for success. A missing
+// file isn't a failure; a town that hasn't logged events yet is healthy.
+func (m *Model) noteSource(name string, err error, now time.Time) {
+	if m.sources == nil {
+		m.sources = make(map[string]*sourceHealth)
+	}
+	h := m.sources[name]
+	if h == nil {
+		h = &sourceHealth{}
+		m.sources[name] = h
+	}
+	if err == nil || errors.Is(err, fs.ErrNotExist) {
+		h.lastOK, h.err, h.failedAt = now, "", time.Time{}
+		return
+	}
+	if h.err == "" {
+		h.failedAt = now
+	}
+	h.err = err.Error()
+}
+
+// noteSources records the sources a poll read, from the poll's goroutine.
+func (m *Model) noteSources(results map[string]error, now time.Time) {
+	for name, err := range results {
+		m.noteSource(name, err, now)
+	}
+}
+
+// degradedSources describes each failing source, sorted by name, e.g.
+// "docker: Cannot connect to the Docker daemon (last ok 3m ago)". Stale
+// pane captures count as one source. tmux is left out while the tmux stall
+// banner already says it isn't answering.
+func (m *Model) degradedSources(now time.Time) []string {
+	var names []string
+	for name, h := range m.sources {
+		if h.err != "" && !(name == "tmux" && m.tmuxStall != nil) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var out []string
+	for _, name := range names {
+		h := m.sources[name]
+		last := "never read"
+		if !h.lastOK.IsZero() {
+			last = "last ok " + sinceLabel(now.Sub(h.lastOK))
+		}
+		msg := strings.TrimSpace(strings.SplitN(h.err, "\n", 2)[0])
+		out = append(out, fmt.Sprintf("%s: %s (%s)", name, truncateWidth(msg, 60, "…"), last))
+	}
+
+	stale, oldest := 0, time.Time{}
+	for _, a := range m.agents {
+		if a.CaptureStaleSince.IsZero() {
+			continue
+		}
+		stale++
+		if oldest.IsZero() || a.CaptureStaleSince.Before(oldest) {
+			oldest = a.CaptureStaleSince
+		}
+	}
+	if stale > 0 {
+		out = append(out, fmt.Sprintf("pane captures: %d of %d stale (oldest from %s)", stale, len(m.agents), sinceLabel(now.Sub(oldest))))
+	}
+	return out
+}
+
+// sinceLabel renders how long ago something was, e.g. "3m 10s ago", or
+// "just now".
+func sinceLabel(d time.Duration) string {
+	if s := formatElapsed(d); s != "" {
+		return s + " ago"
+	}
+	return "just now"
+}
+
+// renderDegradedFooter renders the footer listing degraded sources, above
+// the help line, or "" while every source reads fine.
+func (m *Model) renderDegradedFooter() string {
+	parts := m.degradedSources(time.Now())
+	if len(parts) == 0 {
+		return ""
+	}
+	style := degradedBannerStyle
+	if m.width > 4 {
+		style = style.Width(m.width - 4)
+	}
+	return style.Render("⚠ degraded, showing what's left: " + strings.Join(parts, " · "))
+}
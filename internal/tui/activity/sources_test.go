@@ -0,0 +1,59 @@
+package activity
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestDegradedSources(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	m := &Model{width: 200}
+	m.noteSource("events file", fs.ErrNotExist, now) // not logged yet: fine
+	m.noteSource("docker", nil, now.Add(-3*time.Minute))
+	m.noteSource("docker", errors.New("Cannot connect to the Docker daemon\nIs the daemon running?"), now.Add(-2*time.Minute))
+	m.noteSource("docker", errors.New("Cannot connect to the Docker daemon"), now)
+	m.noteSource("beads", errors.New("1 of 3 rigs unreadable: bd: timeout"), now)
+	if got := m.degradedSources(now); len(got) != 2 ||
+		got[0] != "beads: 1 of 3 rigs unreadable: bd: timeout (never read)" ||
+		got[1] != "docker: Cannot connect to the Docker daemon (last ok 3m ago)" {
+		t.Fatalf("degraded = %q", got)
+	}
+	if h := m.sources["docker"]; !h.failedAt.Equal(now.Add(-2 * time.Minute)) {
+		t.Errorf("failedAt = %v, want the first failure", h.failedAt)
+	}
+
+	// Recovery clears a source; stale captures are listed too.
+	m.noteSource("docker", nil, now)
+	m.noteSource("beads", nil, now)
+	m.agents = []*AgentLight{
+		{SessionName: "gt-gastown-Toast", CaptureStaleSince: now.Add(-90 * time.Second)},
+		{SessionName: "gt-gastown-Nux"},
+	}
+	if got := m.degradedSources(now); len(got) != 1 || got[0] != "pane captures: 1 of 2 stale (oldest from 1m 30s ago)" {
+		t.Errorf("degraded = %q", got)
+	}
+	if out := ansi.Strip(m.renderDegradedFooter()); !strings.Contains(out, "⚠ degraded") || !strings.Contains(out, "pane captures") {
+		t.Errorf("footer = %q", out)
+	}
+}
+
+func TestDegradedSourcesTmuxStall(t *testing.T) {
+	now := time.Now()
+	m := &Model{}
+	m.noteSources(map[string]error{"tmux": errors.New("tmux: command timed out"), "docker": nil}, now)
+	if got := m.degradedSources(now); len(got) != 1 {
+		t.Fatalf("degraded = %q, want tmux listed", got)
+	}
+	m.tmuxStall = errors.New("tmux: command timed out")
+	if got := m.degradedSources(now); len(got) != 0 {
+		t.Errorf("degraded = %q, want tmux left to the stall banner", got)
+	}
+	if m.renderDegradedFooter() != "" {
+		t.Error("footer shown with nothing degraded")
+	}
+}
@@ -0,0 +1,108 @@
+package activity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// spendWindow is the sliding window the tokens/minute rate is averaged over.
+const spendWindow = 5 * time.Minute
+
+// spendSample is the number of tokens an agent produced between two polls.
+type spendSample struct {
+	at     time.Time
+	tokens int
+}
+
+// parseTurnTokens extracts the running token counter from Claude's status
+// stats, e.g. "9m 20s · ↓ 6.8k tokens · thought for 6s" → 6800.
+// Returns 0 when no counter is shown (no turn in progress).
+func parseTurnTokens(status string) int {
+	idx := strings.Index(status, " tokens")
+	if idx <= 0 {
+		return 0
+	}
+	num := status[:idx]
+	mult := 1.0
+	switch num[len(num)-1] {
+	case 'k', 'K':
+		mult = 1e3
+		num = num[:len(num)-1]
+	case 'm', 'M':
+		mult = 1e6
+		num = num[:len(num)-1]
+	}
+	start := len(num)
+	for start > 0 && (num[start-1] >= '0' && num[start-1] <= '9' || num[start-1] == '.' || num[start-1] == ',') {
+		start--
+	}
+	v, err := strconv.ParseFloat(strings.ReplaceAll(num[start:], ",", ""), 64)
+	if err != nil || v <= 0 {
+		return 0
+	}
+	return int(v * mult)
+}
+
+// trackSpend folds this poll's turn counter into the agent's spend rate.
+// The counter grows within a turn and restarts with the next one, so a
+// drop (or the counter disappearing between turns) starts a new baseline.
+func (a *AgentLight) trackSpend(now time.Time) {
+	if a.spendSince.IsZero() {
+		a.spendSince = now
+	}
+
+	cur := parseTurnTokens(a.StatusText)
+	delta := cur - a.turnTokens
+	if cur < a.turnTokens {
+		delta = cur // new turn: everything shown so far is new
+	}
+	a.turnTokens = cur
+	if delta > 0 {
+		a.spendSamples = append(a.spendSamples, spendSample{at: now, tokens: delta})
+	}
+
+	cutoff := now.Add(-spendWindow)
+	kept := a.spendSamples[:0]
+	total := 0
+	for _, s := range a.spendSamples {
+		if s.at.After(cutoff) {
+			kept = append(kept, s)
+			total += s.tokens
+		}
+	}
+	a.spendSamples = kept
+
+	// Average over the window, or over how long we've watched if shorter,
+	// but never less than a minute so one burst doesn't read as a spike.
+	span := now.Sub(a.spendSince)
+	if span > spendWindow {
+		span = spendWindow
+	}
+	if span < time.Minute {
+		span = time.Minute
+	}
+	a.TokensPerMin = int(float64(total) / span.Minutes())
+}
+
+// trackSpend updates every agent's rate and the town aggregate.
+func (m *Model) trackSpend(now time.Time) {
+	m.tokensPerMin = 0
+	for _, a := range m.agents {
+		a.trackSpend(now)
+		m.tokensPerMin += a.TokensPerMin
+	}
+}
+
+// formatTokenRate renders a tokens/minute rate compactly, e.g. "6.8k tok/m".
+func formatTokenRate(perMin int) string {
+	switch {
+	case perMin >= 100_000:
+		return fmt.Sprintf("%.0fk tok/m", float64(perMin)/1000)
+	case perMin >= 1000:
+		return fmt.Sprintf("%.1fk tok/m", float64(perMin)/1000)
+	default:
+		return fmt.Sprintf("%d tok/m", perMin)
+	}
+}
@@ -0,0 +1,84 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTurnTokens(t *testing.T) {
+	tests := []struct {
+		status string
+		want   int
+	}{
+		{"9m 20s · ↓ 6.8k tokens · thought for 6s", 6800},
+		{"12s · ↓ 512 tokens", 512},
+		{"1m 2s · ↑ 1,204 tokens · esc to interrupt", 1204},
+		{"2h 1m · ↓ 1.2M tokens", 1200000},
+		{"COMPACTING · 4s · ↓ 20k tokens", 20000},
+		{"Newspapering…", 0},
+		{"tokens", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := parseTurnTokens(tt.status); got != tt.want {
+			t.Errorf("parseTurnTokens(%q) = %d, want %d", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestTrackSpend_AcrossTurns(t *testing.T) {
+	start := time.Now()
+	a := &AgentLight{}
+	poll := func(after time.Duration, status string) {
+		a.StatusText = status
+		a.trackSpend(start.Add(after))
+	}
+
+	poll(0, "1s · ↓ 1k tokens")
+	poll(30*time.Second, "31s · ↓ 3k tokens") // +2k within the turn
+	poll(45*time.Second, "")                  // turn ended
+	poll(60*time.Second, "2s · ↓ 4k tokens")  // new turn: +4k, not +1k
+	// 1k + 2k + 4k over the one-minute warm-up floor.
+	if a.TokensPerMin != 7000 {
+		t.Errorf("TokensPerMin = %d, want 7000", a.TokensPerMin)
+	}
+
+	// Same counter next poll adds nothing; after the window everything ages out.
+	poll(2*time.Minute, "1m 2s · ↓ 4k tokens")
+	if a.TokensPerMin != 3500 {
+		t.Errorf("TokensPerMin after 2m = %d, want 3500", a.TokensPerMin)
+	}
+	poll(10*time.Minute, "")
+	if a.TokensPerMin != 0 || len(a.spendSamples) != 0 {
+		t.Errorf("rate should decay to 0 after the window, got %d (%d samples)", a.TokensPerMin, len(a.spendSamples))
+	}
+}
+
+func TestRenderStats_TownSpendRate(t *testing.T) {
+	now := time.Now()
+	a := &AgentLight{StatusText: "↓ 3k tokens"}
+	b := &AgentLight{StatusText: "↓ 1.5k tokens"}
+	m := &Model{agents: []*AgentLight{a, b}, totalAgents: 2, activeCount: 2}
+	m.trackSpend(now)
+
+	if m.tokensPerMin != 4500 {
+		t.Fatalf("town rate = %d, want 4500", m.tokensPerMin)
+	}
+	if got := m.renderStats(); !strings.Contains(got, "~4.5k tok/m") {
+		t.Errorf("stats bar missing spend dial: %q", got)
+	}
+}
+
+func TestFormatTokenRate(t *testing.T) {
+	tests := map[int]string{
+		850:    "850 tok/m",
+		6800:   "6.8k tok/m",
+		250000: "250k tok/m",
+	}
+	for in, want := range tests {
+		if got := formatTokenRate(in); got != want {
+			t.Errorf("formatTokenRate(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package activity
+
+import (
+	"strings"
+	"time"
+)
+
+// startupWindow is how long after session creation an agent can be shown as
+// starting. Past it, a quiet agent is simply idle.
+const startupWindow = 2 * time.Minute
+
+// Starting glyphs alternate with the blink so booting agents read as "in
+// progress" rather than active or cold.
+const (
+	dotStartingA = "◐"
+	dotStartingB = "◑"
+)
+
+// bootSignals are pane strings shown while an agent is still booting, with
+// the phase label displayed for each. Matched case-insensitively.
+var bootSignals = []struct {
+	pattern string
+	phase   string
+}{
+	{"loading project context", "loading project context"},
+	{"mcp server", "loading MCP servers"},
+	{"logging in", "logging in"},
+	{"initializing", "initializing"},
+	{"welcome to claude", "booting"},
+}
+
+// detectStartup sets a.StartupPhase when a young session shows boot output
+// (or nothing at all yet) and no sign of real work. Must run after the pane
+// parser so StatusText and CurrentTool are current.
+func detectStartup(a *AgentLight, lines []string, now time.Time) {
+	a.StartupPhase = ""
+	if a.SessionCreated.IsZero() || now.Sub(a.SessionCreated) >= startupWindow {
+		return
+	}
+	if a.StatusText != "" || a.CurrentTool != "" || a.WaitingForHuman || a.HitLimit {
+		return
+	}
+	a.StartupPhase = bootPhase(lines)
+}
+
+// bootPhase returns the boot phase shown in the pane, "booting" for a pane
+// with no content yet, or "" if the pane shows anything else.
+func bootPhase(lines []string) string {
+	content := false
+	for i := len(lines) - 1; i >= 0; i-- {
+		lower := strings.ToLower(lines[i])
+		for _, s := range bootSignals {
+			if strings.Contains(lower, s.pattern) {
+				return s.phase
+			}
+		}
+		if !isChromeLine(lines[i]) {
+			content = true
+		}
+	}
+	if !content {
+		return "booting"
+	}
+	return ""
+}
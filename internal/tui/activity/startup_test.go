@@ -0,0 +1,61 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectStartup(t *testing.T) {
+	now := time.Now()
+	young := now.Add(-20 * time.Second)
+	tests := []struct {
+		name    string
+		created time.Time
+		status  string
+		lines   []string
+		want    string
+	}{
+		{"mcp loading", young, "", []string{"", "  Connecting to MCP servers…", ""}, "loading MCP servers"},
+		{"project context", young, "", []string{"Loading project context…"}, "loading project context"},
+		{"welcome banner", young, "", []string{"│ ✻ Welcome to Claude Code! │", "│ /help for help │", "❯ "}, "booting"},
+		{"empty pane", young, "", []string{"", "❯ ", ""}, "booting"},
+		{"already working", young, "3s · ↓ 200 tokens", []string{"│ ✻ Welcome to Claude Code! │"}, ""},
+		{"real output", young, "", []string{"Running tests...", "❯ "}, ""},
+		{"old session", now.Add(-10 * time.Minute), "", []string{"Loading project context…"}, ""},
+		{"unknown age", time.Time{}, "", nil, ""},
+	}
+	for _, tt := range tests {
+		a := &AgentLight{SessionCreated: tt.created, StatusText: tt.status}
+		detectStartup(a, tt.lines, now)
+		if a.StartupPhase != tt.want {
+			t.Errorf("%s: StartupPhase = %q, want %q", tt.name, a.StartupPhase, tt.want)
+		}
+	}
+}
+
+func TestUpdateAgents_StartingLevel(t *testing.T) {
+	now := time.Now()
+	m := &Model{}
+	m.updateAgents([]sessionInfo{{
+		name:      "hq-mayor",
+		activity:  now.Add(-10 * time.Minute).Unix(), // would read as cold
+		created:   now.Add(-30 * time.Second).Unix(),
+		paneLines: []string{"Starting MCP server: beads", ""},
+	}})
+
+	if len(m.agents) != 1 {
+		t.Fatalf("got %d agents", len(m.agents))
+	}
+	a := m.agents[0]
+	if a.Level != LevelStarting || m.startingCount != 1 || m.stuckCount != 0 {
+		t.Errorf("level = %v (starting=%d stuck=%d), want starting", a.Level, m.startingCount, m.stuckCount)
+	}
+	m.width = 120
+	if line := m.renderLight(a); !strings.Contains(line, "starting · loading MCP servers") {
+		t.Errorf("agent line = %q", line)
+	}
+	if stats := m.renderStats(); !strings.Contains(stats, "1 starting") {
+		t.Errorf("stats = %q", stats)
+	}
+}
@@ -0,0 +1,220 @@
+package activity
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// StatusFileName is the file under <town>/.runtime/ where gt top publishes
+// its per-agent levels every poll, so witness patrols and scripts can read
+// the monitor's view instead of re-deriving activity from tmux.
+const StatusFileName = "top-status.json"
+
+// StatusMaxAge is how old a status file may be before readers treat it as
+// left behind by a gt top that has exited, and fall back to tmux.
+const StatusMaxAge = time.Minute
+
+// AgentStatus is one agent's entry in the status file.
+type AgentStatus struct {
+	Session       string    `json:"session"`
+	AgentID       string    `json:"agent_id,omitempty"`
+	Name          string    `json:"name,omitempty"` // short name shown on the light, e.g. "Toast"
+	Rig           string    `json:"rig,omitempty"`
+	Role          string    `json:"role,omitempty"`
+	Level         string    `json:"level,omitempty"` // empty when read straight from tmux
+	LastActivity  time.Time `json:"last_activity"`
+	WorkBead      string    `json:"work_bead,omitempty"`
+	Muted         bool      `json:"muted,omitempty"`           // muted in gt top: don't escalate
+	ScheduledOff  bool      `json:"scheduled_off,omitempty"`   // in a top.offline window: expected down
+	MergeQueuePos int       `json:"merge_queue_pos,omitempty"` // position of its branch in the rig's merge queue
+
+	AgentType     string    `json:"agent_type,omitempty"`        // e.g. "claude", "codex"
+	Repo          string    `json:"repo,omitempty"`              // git repo the agent's pane is in
+	WaitingReason string    `json:"waiting_reason,omitempty"`    // what it waits on a human for, e.g. "permission"
+	Tool          string    `json:"tool,omitempty"`              // tool running now, e.g. "Bash(go test ./...)"
+	Limit         string    `json:"limit,omitempty"`             // "rate_limit" or "usage_limit" when blocked on one
+	LimitReset    string    `json:"limit_reset,omitempty"`       // when the limit resets, as the agent put it
+	ContextUsed   int       `json:"context_used_pct,omitempty"`  // context window used, 0 when unknown
+	SessionLimit  int       `json:"session_limit_pct,omitempty"` // session usage limit used, 0 when unknown
+	Progress      *int      `json:"progress_pct,omitempty"`      // percent done the agent reported, absent when none
+	ProgressLabel string    `json:"progress_label,omitempty"`    // what it reported progress through
+	StartedAt     time.Time `json:"started_at,omitzero"`         // when the tmux session was created
+}
+
+// Status is the status file written by gt top.
+type Status struct {
+	UpdatedAt   time.Time     `json:"updated_at"`
+	HealthScore *int          `json:"health_score,omitempty"` // town score, 0-100; absent with no agents
+	Agents      []AgentStatus `json:"agents"`
+}
+
+// String returns the level name used in the status file.
+func (l ActivityLevel) String() string {
+	switch l {
+	case LevelActive:
+		return "active"
+	case LevelRecent:
+		return "recent"
+	case LevelWarm:
+		return "warm"
+	case LevelCool:
+		return "cool"
+	case LevelCold:
+		return "cold"
+	case LevelRateLimited:
+		return "rate_limited"
+	case LevelHitLimit:
+		return "hit_limit"
+	case LevelWaitingForHuman:
+		return "waiting"
+	case LevelStarting:
+		return "starting"
+	case LevelReady:
+		return "ready"
+	case LevelDead:
+		return "dead"
+	default:
+		return "unknown"
+	}
+}
+
+// LevelNames returns the level names used in the status file, in level
+// order.
+func LevelNames() []string {
+	var names []string
+	for l := LevelActive; l <= LevelDead; l++ {
+		names = append(names, l.String())
+	}
+	return names
+}
+
+// StatusPath returns the status file path for a town.
+func StatusPath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), StatusFileName)
+}
+
+// ReadStatus reads the status file for a town.
+func ReadStatus(townRoot string) (*Status, error) {
+	data, err := os.ReadFile(StatusPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	var s Status
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// TmuxStatus builds a Status straight from tmux session activity, for when
+// no gt top is running. Levels are left empty: without pane parsing there is
+// no way to tell a blocked agent from an idle one.
+func TmuxStatus() (*Status, error) {
+	sessions, err := listSessions()
+	if err != nil {
+		return nil, err
+	}
+	s := &Status{UpdatedAt: time.Now()}
+	for _, si := range sessions {
+		a := &AgentLight{SessionName: si.name, CurActivity: si.activity}
+		if si.created > 0 {
+			a.SessionCreated = time.Unix(si.created, 0)
+		}
+		if si.workDir != "" {
+			a.Repo = repoName(si.workDir)
+		}
+		parseSessionName(a)
+		resolveAgentID(a)
+		s.Agents = append(s.Agents, a.status())
+	}
+	return s, nil
+}
+
+// status snapshots the agent for the status file.
+func (a *AgentLight) status() AgentStatus {
+	st := AgentStatus{
+		Session:  a.SessionName,
+		AgentID:  a.AgentID,
+		Name:     a.Name,
+		Rig:      a.Rig,
+		Role:     a.Role,
+		WorkBead: a.WorkBeadID,
+
+		AgentType:     a.AgentType,
+		Repo:          a.Repo,
+		WaitingReason: a.WaitingReason,
+		Tool:          a.CurrentTool,
+		Limit:         limitKind(a),
+		LimitReset:    a.LimitResetInfo,
+		StartedAt:     a.SessionCreated,
+	}
+	if a.CurActivity > 0 {
+		st.LastActivity = time.Unix(a.CurActivity, 0)
+	}
+	if a.ContextPercent > 0 {
+		st.ContextUsed = 100 - a.ContextPercent
+	}
+	st.SessionLimit = a.SessionLimitPct
+	if a.progressOn {
+		pct := a.Progress
+		st.Progress = &pct
+		st.ProgressLabel = a.ProgressLabel
+	}
+	return st
+}
+
+// writeStatus publishes the current agent levels to the town's status file.
+// Best-effort: tmux-only mode has no town, and write errors are ignored.
+func (m *Model) writeStatus(now time.Time) {
+	if m.townRoot == "" {
+		return
+	}
+	_ = util.EnsureDirAndWriteJSON(StatusPath(m.townRoot), m.Snapshot(now))
+}
+
+// Snapshot returns the agents and town score as of the last poll, in the
+// status file's form.
+func (m *Model) Snapshot(now time.Time) Status {
+	s := Status{UpdatedAt: now, Agents: make([]AgentStatus, 0, len(m.agents))}
+	if m.scored {
+		score := m.healthScore
+		s.HealthScore = &score
+	}
+	for _, a := range m.agents {
+		st := a.status()
+		st.Level = a.Level.String()
+		st.Muted = m.muted(a)
+		st.ScheduledOff = a.ScheduledOff
+		st.MergeQueuePos = a.MergeQueuePos
+		s.Agents = append(s.Agents, st)
+	}
+	return s
+}
+
+// Stale returns the agents idle for at least threshold, longest idle first.
+// Agents blocked on a human or a usage limit are left out: a nudge can't
+// unblock them, so they aren't the patrol's to chase. Neither are muted
+// agents, left broken on purpose, agents scheduled off, nor agents whose
+// work waits in the merge queue.
+func (s *Status) Stale(threshold time.Duration, now time.Time) []AgentStatus {
+	var stale []AgentStatus
+	for _, a := range s.Agents {
+		if a.LastActivity.IsZero() || now.Sub(a.LastActivity) < threshold {
+			continue
+		}
+		if a.Level == LevelWaitingForHuman.String() || a.Level == LevelHitLimit.String() || a.Muted || a.ScheduledOff || a.MergeQueuePos > 0 {
+			continue
+		}
+		stale = append(stale, a)
+	}
+	sort.SliceStable(stale, func(i, j int) bool {
+		return stale[i].LastActivity.Before(stale[j].LastActivity)
+	})
+	return stale
+}
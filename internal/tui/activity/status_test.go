@@ -0,0 +1,86 @@
+package activity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWriteStatus_RoundTrip(t *testing.T) {
+	town := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+	m := &Model{townRoot: town, agents: []*AgentLight{{
+		SessionName: "gt-gastown-Toast", AgentID: "gastown/polecats/Toast",
+		Rig: "gastown", Role: "polecat", Level: LevelCold,
+		CurActivity: now.Add(-20 * time.Minute).Unix(), WorkBeadID: "gt-abc",
+	}}}
+	m.writeStatus(now)
+
+	s, err := ReadStatus(town)
+	if err != nil {
+		t.Fatalf("ReadStatus: %v", err)
+	}
+	if !s.UpdatedAt.Equal(now) || len(s.Agents) != 1 {
+		t.Fatalf("status = %+v", s)
+	}
+	got := s.Agents[0]
+	if got.AgentID != "gastown/polecats/Toast" || got.Level != "cold" || got.WorkBead != "gt-abc" ||
+		!got.LastActivity.Equal(now.Add(-20*time.Minute)) {
+		t.Errorf("agent status = %+v", got)
+	}
+}
+
+func TestWriteStatus_NoTownIsNoop(t *testing.T) {
+	m := &Model{agents: []*AgentLight{{SessionName: "hq-mayor"}}}
+	m.writeStatus(time.Now()) // must not panic or write relative to cwd
+}
+
+func TestStatusStale(t *testing.T) {
+	now := time.Now()
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+	s := &Status{Agents: []AgentStatus{
+		{Session: "busy", Level: "active", LastActivity: ago(time.Second)},
+		{Session: "cold", Level: "cold", LastActivity: ago(15 * time.Minute)},
+		{Session: "colder", Level: "cold", LastActivity: ago(40 * time.Minute)},
+		{Session: "human", Level: "waiting", LastActivity: ago(time.Hour)},
+		{Session: "capped", Level: "hit_limit", LastActivity: ago(time.Hour)},
+		{Session: "tmux-only", LastActivity: ago(11 * time.Minute)},
+		{Session: "unknown"},
+	}}
+
+	var got []string
+	for _, a := range s.Stale(10*time.Minute, now) {
+		got = append(got, a.Session)
+	}
+	want := []string{"colder", "cold", "tmux-only"}
+	if len(got) != len(want) {
+		t.Fatalf("Stale = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Stale = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestViewSnapshot(t *testing.T) {
+	m := &Model{agents: []*AgentLight{
+		{SessionName: "gt-gastown-Toast", Name: "Toast", Rig: "gastown", Role: "polecat", Level: LevelActive,
+			CurrentTool: "Bash(make)", ContextPercent: 30, SessionLimitPct: 85},
+		{SessionName: "bd-beads-crew-max", Name: "max", Rig: "beads", Role: "crew", Level: LevelCold},
+	}}
+	s := m.ViewSnapshot(time.Now())
+	if len(s.Agents) != 2 {
+		t.Fatalf("unfiltered snapshot has %d agents, want 2", len(s.Agents))
+	}
+	toast := s.Agents[0]
+	if toast.Name != "Toast" || toast.Tool != "Bash(make)" || toast.ContextUsed != 70 || toast.SessionLimit != 85 {
+		t.Errorf("Toast = %+v", toast)
+	}
+
+	if err := m.SetAgentFilter("beads", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if s := m.ViewSnapshot(time.Now()); len(s.Agents) != 1 || s.Agents[0].Name != "max" {
+		t.Errorf("--rig beads snapshot = %+v, want max only", s.Agents)
+	}
+}
@@ -0,0 +1,86 @@
+package activity
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Default sticky TTLs, overridable via town settings (top.context_ttl,
+// top.session_limit_ttl). Claude only prints the auto-compact and session
+// limit warnings occasionally, so values are kept between sightings, but not
+// forever: an agent that restarted in place would otherwise show stale
+// warnings indefinitely.
+const (
+	defaultContextTTL      = 15 * time.Minute
+	defaultSessionLimitTTL = 2 * time.Hour
+)
+
+// observeContext records a context-remaining percentage parsed from the pane.
+func (a *AgentLight) observeContext(pct int) {
+	a.ContextPercent = pct
+	a.contextSeen = time.Now()
+}
+
+// observeSessionLimit records a session-limit warning parsed from the pane.
+// An empty reset keeps the previously parsed reset time.
+func (a *AgentLight) observeSessionLimit(pct int, reset string) {
+	a.SessionLimitPct = pct
+	if reset != "" {
+		a.SessionLimitReset = reset
+	}
+	a.sessionLimitSeen = time.Now()
+}
+
+// expireSticky clears sticky values not re-observed within their TTL.
+// Values with no observation time (set outside the parsers) are left alone.
+func (m *Model) expireSticky(a *AgentLight, now time.Time) {
+	if m.contextTTL > 0 && a.ContextPercent > 0 && !a.contextSeen.IsZero() &&
+		now.Sub(a.contextSeen) > m.contextTTL {
+		a.ContextPercent = 0
+		a.contextSeen = time.Time{}
+	}
+	if m.sessionLimitTTL > 0 && a.SessionLimitPct > 0 && !a.sessionLimitSeen.IsZero() &&
+		now.Sub(a.sessionLimitSeen) > m.sessionLimitTTL {
+		a.SessionLimitPct = 0
+		a.SessionLimitReset = ""
+		a.sessionLimitSeen = time.Time{}
+	}
+}
+
+// applyRestartEvents drops sticky state for agents that announced an in-place
+// restart (agent_restarted), which session_created can't detect because the
+// tmux session survives. Each event is applied once: it stays in the recent
+// window for several polls, so the last applied timestamp is remembered.
+// Restarts from before gt top's first poll are history: the state it parsed
+// since is already the new agent's.
+func (m *Model) applyRestartEvents() {
+	for _, evt := range m.recentToolEvents {
+		if evt.EventType != events.TypeAgentRestarted || evt.Timestamp.Before(m.firstPoll) {
+			continue
+		}
+		a := m.agentForToolEvent(evt)
+		if a == nil || !evt.Timestamp.After(a.restartSeen) {
+			continue
+		}
+		a.resetSticky()
+		a.restartSeen = evt.Timestamp
+	}
+}
+
+// agentForToolEvent matches a plugin event to any agent (Claude or not) by
+// stable agent ID, then tmux session name, following moves (relaunch.go).
+func (m *Model) agentForToolEvent(evt toolEvent) *AgentLight {
+	for _, a := range m.agents {
+		if evt.AgentID != "" && a.AgentID == evt.AgentID {
+			return a
+		}
+	}
+	session := m.movedSession(evt.Session)
+	for _, a := range m.agents {
+		if session != "" && a.SessionName == session {
+			return a
+		}
+	}
+	return nil
+}
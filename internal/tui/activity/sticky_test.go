@@ -0,0 +1,105 @@
+package activity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestExpireSticky(t *testing.T) {
+	now := time.Now()
+	m := &Model{contextTTL: 15 * time.Minute, sessionLimitTTL: 2 * time.Hour}
+	a := &AgentLight{}
+	a.observeContext(20)
+	a.observeSessionLimit(95, "resets 8pm")
+
+	m.expireSticky(a, now.Add(10*time.Minute))
+	if a.ContextPercent != 20 || a.SessionLimitPct != 95 {
+		t.Fatalf("values expired early: ctx=%d limit=%d", a.ContextPercent, a.SessionLimitPct)
+	}
+
+	m.expireSticky(a, now.Add(20*time.Minute))
+	if a.ContextPercent != 0 {
+		t.Errorf("ContextPercent = %d after TTL, want 0", a.ContextPercent)
+	}
+	if a.SessionLimitPct != 95 {
+		t.Errorf("SessionLimitPct expired with context TTL")
+	}
+
+	m.expireSticky(a, now.Add(3*time.Hour))
+	if a.SessionLimitPct != 0 || a.SessionLimitReset != "" {
+		t.Errorf("session limit not expired: %d %q", a.SessionLimitPct, a.SessionLimitReset)
+	}
+}
+
+func TestExpireSticky_ReobservedStays(t *testing.T) {
+	m := &Model{contextTTL: time.Minute}
+	a := &AgentLight{}
+	a.observeContext(30)
+	a.contextSeen = time.Now().Add(-2 * time.Minute)
+	a.observeContext(25) // pane shows it again
+	m.expireSticky(a, time.Now())
+	if a.ContextPercent != 25 {
+		t.Errorf("ContextPercent = %d, want 25 (re-observed)", a.ContextPercent)
+	}
+}
+
+func TestExpireSticky_NoTTLOrUnknownSighting(t *testing.T) {
+	a := &AgentLight{ContextPercent: 40} // not set by a parser
+	(&Model{contextTTL: time.Minute}).expireSticky(a, time.Now().Add(time.Hour))
+	if a.ContextPercent != 40 {
+		t.Error("values without an observation time should not expire")
+	}
+
+	a.observeContext(40)
+	(&Model{}).expireSticky(a, time.Now().Add(time.Hour))
+	if a.ContextPercent != 40 {
+		t.Error("zero TTL should never expire")
+	}
+}
+
+func TestApplyRestartEvents(t *testing.T) {
+	a := &AgentLight{SessionName: "gt-gastown-Toast", AgentID: "gastown/polecats/Toast", ContextPercent: 12, HitLimit: true}
+	other := &AgentLight{SessionName: "gt-gastown-Nux", ContextPercent: 50}
+	ts := time.Now().Truncate(time.Second)
+	m := &Model{
+		agents: []*AgentLight{a, other},
+		recentToolEvents: []toolEvent{
+			{Timestamp: ts, AgentID: "gastown/polecats/Toast", EventType: events.TypeAgentRestarted},
+			{Timestamp: ts, Session: "gt-gastown-Nux", EventType: events.TypeToolStarted},
+		},
+	}
+
+	m.applyRestartEvents()
+	if a.ContextPercent != 0 || a.HitLimit {
+		t.Errorf("restarted agent kept sticky state: ctx=%d hitLimit=%v", a.ContextPercent, a.HitLimit)
+	}
+	if other.ContextPercent != 50 {
+		t.Error("unrelated agent should be untouched")
+	}
+
+	// The event stays in the recent window; it must not wipe fresh state again.
+	a.ContextPercent = 30
+	m.applyRestartEvents()
+	if a.ContextPercent != 30 {
+		t.Error("restart event applied twice")
+	}
+
+	// A restart from before the first poll is history.
+	other.ContextPercent = 50
+	m.firstPoll = ts.Add(time.Second)
+	m.recentToolEvents = []toolEvent{{Timestamp: ts, Session: "gt-gastown-Nux", EventType: events.TypeAgentRestarted}}
+	m.applyRestartEvents()
+	if other.ContextPercent != 50 {
+		t.Error("restart from before gt top started was applied")
+	}
+}
+
+func TestResetStickyKeepsReportedLimit(t *testing.T) {
+	a := &AgentLight{HitLimit: true, limitReported: limitKindUsage}
+	a.resetSticky()
+	if a.HitLimit || a.limitReported != limitKindUsage {
+		t.Errorf("after reset: hitLimit=%v limitReported=%q, want the limit cleared but still reported", a.HitLimit, a.limitReported)
+	}
+}
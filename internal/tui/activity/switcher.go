@@ -0,0 +1,195 @@
+package activity
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// switcherRows is how many matches the quick switcher lists.
+const switcherRows = 10
+
+var switcherSelectedStyle = lipgloss.NewStyle().Foreground(colorTitle).Bold(true).Reverse(true)
+
+// switcher is the quick-switcher overlay (ctrl+f): a fuzzy finder over
+// agent names, sessions, agent IDs and work beads that moves the selection
+// to the chosen agent, so big towns needn't be scanned by eye.
+type switcher struct {
+	query   string
+	matches []*AgentLight // best first
+	sel     int
+}
+
+// openSwitcher opens the quick switcher with every agent listed.
+func (m *Model) openSwitcher() {
+	m.switcher = &switcher{}
+	m.refreshSwitcher()
+}
+
+// refreshSwitcher re-ranks the agents against the query. The selection
+// resets to the best match.
+func (m *Model) refreshSwitcher() {
+	s := m.switcher
+	type ranked struct {
+		agent *AgentLight
+		score int
+	}
+	var hits []ranked
+	for _, a := range m.agents {
+		if score, ok := matchAgent(s.query, m.switcherFields(a)); ok {
+			hits = append(hits, ranked{a, score})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+	s.matches = s.matches[:0]
+	for _, h := range hits {
+		s.matches = append(s.matches, h.agent)
+	}
+	s.sel = 0
+}
+
+// switcherFields is what the switcher matches an agent on. Bead titles are
+// left out in redacted mode, so typing can't probe for them.
+func (m *Model) switcherFields(a *AgentLight) []string {
+	fields := []string{a.Name, a.SessionName, a.AgentID, a.WorkBeadID}
+	if !m.redact {
+		fields = append(fields, a.WorkBeadTitle)
+	}
+	return fields
+}
+
+// handleSwitcherKey handles a key while the switcher is open.
+func (m *Model) handleSwitcherKey(key string) {
+	s := m.switcher
+	switch key {
+	case "esc":
+		m.switcher = nil
+	case "enter", "ctrl+o":
+		m.switcher = nil
+		if len(s.matches) == 0 {
+			return
+		}
+		a := s.matches[s.sel]
+		m.selectAgent(a)
+		if key == "ctrl+o" {
+			m.attachTo(a)
+		}
+	case "up", "ctrl+p":
+		if s.sel > 0 {
+			s.sel--
+		}
+	case "down", "ctrl+n", "tab":
+		if s.sel < min(len(s.matches), switcherRows)-1 {
+			s.sel++
+		}
+	case "backspace":
+		if r := []rune(s.query); len(r) > 0 {
+			s.query = string(r[:len(r)-1])
+			m.refreshSwitcher()
+		}
+	case "ctrl+u":
+		s.query = ""
+		m.refreshSwitcher()
+	default:
+		if r := []rune(key); len(r) == 1 && unicode.IsPrint(r[0]) {
+			s.query += key
+			m.refreshSwitcher()
+		}
+	}
+}
+
+// selectAgent makes an agent the selection, as if hovered, and highlights
+// its row.
+func (m *Model) selectAgent(a *AgentLight) {
+	m.hoveredAgent = a
+	m.keySelected = true
+	m.fetchAgentDetails(a)
+	m.jumpTo(a)
+}
+
+// matchAgent fuzzy-matches a query against an agent's fields. Each
+// space-separated term must match some field; the score is the sum of each
+// term's best field score. An empty query matches everything.
+func matchAgent(query string, fields []string) (int, bool) {
+	total := 0
+	for _, term := range strings.Fields(query) {
+		best, found := 0, false
+		for _, f := range fields {
+			if score, ok := fuzzyScore(term, f); ok && (!found || score > best) {
+				best, found = score, true
+			}
+		}
+		if !found {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+// fuzzyScore reports whether the pattern's characters appear in order in s,
+// case-insensitively, scoring runs of consecutive characters and matches at
+// the start of a word ("pa" in "gt-parser") above scattered ones.
+func fuzzyScore(pattern, s string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	if len(p) == 0 {
+		return 0, true
+	}
+	text := []rune(strings.ToLower(s))
+	score, pi, run := 0, 0, 0
+	for i, r := range text {
+		if pi == len(p) {
+			break
+		}
+		if r != p[pi] {
+			run = 0
+			continue
+		}
+		score++
+		run++
+		score += run - 1 // consecutive characters
+		if i == 0 || !unicode.IsLetter(text[i-1]) && !unicode.IsDigit(text[i-1]) {
+			score += 3 // start of a word
+		}
+		pi++
+	}
+	if pi < len(p) {
+		return 0, false
+	}
+	return score, true
+}
+
+// renderSwitcher renders the switcher overlay shown in place of the panels.
+func (m *Model) renderSwitcher() string {
+	s := m.switcher
+	lines := []string{
+		titleStyle.Render("find agent") + "  " + s.query + "▏",
+		"",
+	}
+	if len(s.matches) == 0 {
+		lines = append(lines, subtitleStyle.Render("no matching agents"))
+	}
+	for i, agent := range s.matches {
+		if i == switcherRows {
+			lines = append(lines, subtitleStyle.Render("… and more; keep typing to narrow"))
+			break
+		}
+		a := m.display(agent)
+		line := padWidth(truncateWidth(a.Name, 16, "~"), 16) + " " + padWidth(truncateWidth(a.SessionName, 28, "~"), 28)
+		if a.WorkBeadID != "" {
+			line += " " + a.WorkBeadID
+			if a.WorkBeadTitle != "" {
+				line += " " + a.WorkBeadTitle
+			}
+		}
+		line = truncateWidth(line, max(m.width-12, 40), "…")
+		if i == s.sel {
+			line = switcherSelectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", helpStyle.Render("enter: select · ctrl+o: select and attach · ↑/↓: move · esc: close"))
+	return probeBoxStyle.Render(strings.Join(lines, "\n"))
+}
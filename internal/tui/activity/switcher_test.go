@@ -0,0 +1,109 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	if _, ok := fuzzyScore("psr", "gt-parser-fix"); !ok {
+		t.Error("psr should match gt-parser-fix")
+	}
+	if _, ok := fuzzyScore("rsp", "gt-parser-fix"); ok {
+		t.Error("rsp matched out of order")
+	}
+	word, _ := fuzzyScore("par", "Fix the parser")
+	scattered, _ := fuzzyScore("par", "spa crater")
+	if word <= scattered {
+		t.Errorf("word-start run scored %d, scattered %d", word, scattered)
+	}
+}
+
+// switcherAgents are two polecats on work beads and a crew member without one.
+func switcherAgents() []*AgentLight {
+	return []*AgentLight{
+		{SessionName: "gt-gastown-Toast", Name: "Toast", AgentID: "gastown/polecats/Toast", WorkBeadID: "gt-12", WorkBeadTitle: "Rewrite the config parser"},
+		{SessionName: "gt-gastown-Nux", Name: "Nux", AgentID: "gastown/polecats/Nux", WorkBeadID: "gt-13", WorkBeadTitle: "Dashboard colors"},
+		{SessionName: "gt-beads-crew-max", Name: "max", AgentID: "beads/crew/max"},
+	}
+}
+
+func typeKeys(m *Model, keys ...string) {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "ctrl+f":
+			msg = tea.KeyMsg{Type: tea.KeyCtrlF}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		case "backspace":
+			msg = tea.KeyMsg{Type: tea.KeyBackspace}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case "ctrl+u":
+			msg = tea.KeyMsg{Type: tea.KeyCtrlU}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		m.Update(msg)
+	}
+}
+
+func TestSwitcher_JumpsToMatch(t *testing.T) {
+	m := testModel(switcherAgents())
+	typeKeys(m, "ctrl+f")
+	if m.switcher == nil || len(m.switcher.matches) != 3 {
+		t.Fatalf("ctrl+f: switcher = %+v, want all agents listed", m.switcher)
+	}
+
+	// "q" types into the query rather than quitting; the bead title matches.
+	typeKeys(m, "p", "a", "r", "s", "e", "q")
+	if len(m.switcher.matches) != 0 {
+		t.Errorf("parseq matched %d agents", len(m.switcher.matches))
+	}
+	typeKeys(m, "backspace", " ", "t", "o")
+	if len(m.switcher.matches) != 1 || m.switcher.matches[0].Name != "Toast" {
+		t.Fatalf("\"parse to\" matched %v", m.switcher.matches)
+	}
+	if out := m.renderSwitcher(); !strings.Contains(out, "gt-12 Rewrite the config parser") {
+		t.Errorf("switcher overlay:\n%s", out)
+	}
+
+	typeKeys(m, "enter")
+	if m.switcher != nil || m.hoveredAgent == nil || m.hoveredAgent.Name != "Toast" || !m.isJumpTarget(m.hoveredAgent) {
+		t.Errorf("enter: switcher %v, selected %v", m.switcher, m.hoveredAgent)
+	}
+}
+
+func TestSwitcher_MovesAndCloses(t *testing.T) {
+	m := testModel(switcherAgents())
+	typeKeys(m, "ctrl+f", "g", "t", "-", "g", "a", "s", "down", "down")
+	if m.switcher.sel != 1 {
+		t.Errorf("selection = %d, want 1 of two gastown agents", m.switcher.sel)
+	}
+	typeKeys(m, "esc")
+	if m.switcher != nil || m.hoveredAgent != nil {
+		t.Errorf("esc: switcher %v, selected %v", m.switcher, m.hoveredAgent)
+	}
+}
+
+func TestSwitcher_RedactedSkipsTitles(t *testing.T) {
+	m := testModel(switcherAgents())
+	m.SetRedact(true)
+	typeKeys(m, "ctrl+f", "p", "a", "r", "s", "e", "r")
+	if len(m.switcher.matches) != 0 {
+		t.Errorf("redacted switcher matched bead titles: %v", m.switcher.matches)
+	}
+	typeKeys(m, "esc", "ctrl+f", "g", "t", "-", "1", "3")
+	if out := m.renderSwitcher(); strings.Contains(out, "Dashboard") || !strings.Contains(out, "Nux") {
+		t.Errorf("redacted overlay:\n%s", out)
+	}
+}
@@ -0,0 +1,86 @@
+package activity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultTheme is the LED theme used when none is configured.
+const defaultTheme = "circles"
+
+// ledTheme is the set of glyphs the LED bar draws for each state. Colors
+// stay the same across themes; only the shapes change.
+type ledTheme struct {
+	active string
+	recent string
+	warm   string
+	cool   string
+	cold   string
+	alarm  string // blink glyph for needs-human and hit-limit
+}
+
+// ledThemes are the built-in themes (gt top --theme, town settings top.theme).
+// Circles is the original look. Blocks and braille give warm and cool
+// different shapes as well as colors, for terminals where the amber/gray
+// contrast is weak; hearts are double-width emoji.
+var ledThemes = map[string]ledTheme{
+	"circles": {active: dotActive, recent: dotActive, warm: dotIdle, cool: dotIdle, cold: dotCold, alarm: "‼"},
+	"blocks":  {active: "█", recent: "▓", warm: "▒", cool: "░", cold: "·", alarm: "‼"},
+	"braille": {active: brailleActive, recent: brailleRecent, warm: brailleWarm, cool: brailleCool, cold: brailleCold, alarm: "‼"},
+	"hearts":  {active: "💚", recent: "💙", warm: "💛", cool: "🤍", cold: "🖤", alarm: "🚨"},
+}
+
+// themeNames returns the built-in theme names, sorted.
+func themeNames() []string {
+	names := make([]string, 0, len(ledThemes))
+	for n := range ledThemes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetTheme selects a built-in LED theme ("" keeps the current one) and
+// applies per-state glyph overrides, keyed active, recent, warm, cool, cold,
+// or alarm (town settings top.glyphs).
+func (m *Model) SetTheme(name string, glyphs map[string]string) error {
+	t := m.led()
+	if name != "" {
+		var ok bool
+		if t, ok = ledThemes[name]; !ok {
+			return fmt.Errorf("unknown theme %q (want %s)", name, strings.Join(themeNames(), ", "))
+		}
+	}
+	for state, g := range glyphs {
+		if g == "" {
+			continue
+		}
+		switch state {
+		case "active":
+			t.active = g
+		case "recent":
+			t.recent = g
+		case "warm":
+			t.warm = g
+		case "cool":
+			t.cool = g
+		case "cold":
+			t.cold = g
+		case "alarm":
+			t.alarm = g
+		default:
+			return fmt.Errorf("unknown glyph state %q (want active, recent, warm, cool, cold, alarm)", state)
+		}
+	}
+	m.theme = t
+	return nil
+}
+
+// led returns the LED theme in use.
+func (m *Model) led() ledTheme {
+	if m.theme.active == "" {
+		return ledThemes[defaultTheme]
+	}
+	return m.theme
+}
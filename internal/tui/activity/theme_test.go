@@ -0,0 +1,54 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetTheme(t *testing.T) {
+	m := &Model{}
+	if got := m.led(); got != ledThemes["circles"] {
+		t.Errorf("zero model should use circles, got %+v", got)
+	}
+
+	if err := m.SetTheme("blocks", map[string]string{"cool": "◌"}); err != nil {
+		t.Fatalf("SetTheme: %v", err)
+	}
+	if m.led().warm != "▒" || m.led().cool != "◌" {
+		t.Errorf("theme = %+v", m.led())
+	}
+
+	// An empty name keeps the current theme; overrides stack on it.
+	if err := m.SetTheme("", map[string]string{"warm": "◍"}); err != nil {
+		t.Fatal(err)
+	}
+	if m.led().active != "█" || m.led().warm != "◍" {
+		t.Errorf("theme = %+v", m.led())
+	}
+
+	if err := m.SetTheme("neon", nil); err == nil || !strings.Contains(err.Error(), "blocks") {
+		t.Errorf("unknown theme error = %v", err)
+	}
+	if err := m.SetTheme("", map[string]string{"lukewarm": "x"}); err == nil {
+		t.Error("unknown glyph state should be rejected")
+	}
+}
+
+func TestRenderBar_Theme(t *testing.T) {
+	m := &Model{}
+	_ = m.SetTheme("blocks", nil)
+	for level, want := range map[ActivityLevel]string{
+		LevelRecent: "▓",
+		LevelWarm:   "▒",
+		LevelCool:   "░",
+		LevelCold:   "·",
+	} {
+		if got := m.renderBar(&AgentLight{Level: level}); !strings.Contains(got, want) {
+			t.Errorf("%s bar = %q, want %q", level, got, want)
+		}
+	}
+	m.blinkOn = true
+	if got := m.renderBar(&AgentLight{Level: LevelWaitingForHuman}); !strings.Contains(got, "‼") {
+		t.Errorf("waiting bar = %q", got)
+	}
+}
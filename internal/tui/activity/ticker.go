@@ -0,0 +1,158 @@
+package activity
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+const (
+	// tickerWindow is how far back the ticker reaches into the events log.
+	tickerWindow = 15 * time.Minute
+	// tickerMax caps how many events the ticker cycles through.
+	tickerMax = 40
+	// tickerStep is how often the ticker scrolls by one column.
+	tickerStep = 200 * time.Millisecond
+	// tickerSep separates events on the ticker.
+	tickerSep = "   ·   "
+)
+
+var tickerStyle = lipgloss.NewStyle().Foreground(colorTitle)
+
+// tickerMsg scrolls the ticker.
+type tickerMsg struct{}
+
+// ticker is the event ticker under the panels (gt top --ticker): the town's
+// recent feed events, one per second at most, scrolling right to left like
+// a stock ticker.
+type ticker struct {
+	items  []tickerEntry // oldest first
+	offset int           // columns scrolled
+}
+
+// tickerEntry is one event on the ticker. Custom event types (town settings
+// event_types) carry their own color.
+type tickerEntry struct {
+	text  string
+	color string // lipgloss color; empty for the ticker's own
+}
+
+// EnableTicker adds the event ticker under the agent panels.
+func (m *Model) EnableTicker() {
+	if m.ticker == nil {
+		m.ticker = &ticker{}
+	}
+}
+
+// tickerTick schedules the next scroll step.
+func tickerTick() tea.Cmd {
+	return tea.Tick(tickerStep, func(time.Time) tea.Msg { return tickerMsg{} })
+}
+
+// scrollTicker advances the ticker one column. It holds still while frames
+// are slow, like the blinking lights.
+func (m *Model) scrollTicker() tea.Cmd {
+	if m.ticker == nil {
+		return nil
+	}
+	if !m.frames.slow {
+		m.ticker.offset++
+	}
+	return tickerTick()
+}
+
+// readTicker collects the ticker's events from the events tail: feed-visible
+// ones within tickerWindow, keeping the latest of each second.
+func (m *Model) readTicker(now time.Time) {
+	if m.ticker == nil {
+		return
+	}
+	cutoff := now.Add(-tickerWindow)
+	var items []tickerEntry
+	var last time.Time
+	for _, evt := range m.tail.recent {
+		if evt.Visibility != events.VisibilityFeed && evt.Visibility != events.VisibilityBoth {
+			continue
+		}
+		if evt.At.Before(cutoff) {
+			continue
+		}
+		item := m.tickerItem(evt.Event, evt.At)
+		if len(items) > 0 && evt.At.Equal(last) {
+			items[len(items)-1] = item // the most recent event of the second
+			continue
+		}
+		items = append(items, item)
+		last = evt.At
+	}
+	if len(items) > tickerMax {
+		items = items[len(items)-tickerMax:]
+	}
+	m.ticker.items = items
+}
+
+// tickerItem renders one event for the ticker, e.g.
+// "14:03:22 gastown/polecats/Toast done gt-abc". Only the actor, type and
+// bead are shown, so nothing needs masking in redacted mode. A custom type's
+// icon goes before its name.
+func (m *Model) tickerItem(evt events.Event, ts time.Time) tickerEntry {
+	custom, _ := m.eventTypes.Custom(evt.Type)
+	parts := []string{ts.Local().Format("15:04:05")}
+	if evt.Actor != "" {
+		parts = append(parts, evt.Actor)
+	}
+	if custom.Icon != "" {
+		parts = append(parts, custom.Icon)
+	}
+	parts = append(parts, strings.ReplaceAll(evt.Type, "_", " "))
+	if bead, ok := evt.Payload["bead"].(string); ok && bead != "" {
+		parts = append(parts, bead)
+	}
+	return tickerEntry{text: strings.Join(parts, " "), color: custom.Color}
+}
+
+// renderTicker renders the ticker line, width columns wide, scrolled to its
+// offset. The events repeat end to end, so the line is always full.
+func (m *Model) renderTicker(width int) string {
+	t := m.ticker
+	if len(t.items) == 0 {
+		return subtitleStyle.Render("  no recent events")
+	}
+	// The loop's runes, each with the index of its entry (-1 between them),
+	// so a colored entry keeps its color as it scrolls.
+	var loop []rune
+	var owner []int
+	for i, item := range t.items {
+		for _, r := range item.text {
+			loop = append(loop, r)
+			owner = append(owner, i)
+		}
+		for _, r := range tickerSep {
+			loop = append(loop, r)
+			owner = append(owner, -1)
+		}
+	}
+	start := t.offset % len(loop)
+	var b strings.Builder
+	for col := 0; col < width; {
+		i := (start + col) % len(loop)
+		run := []rune{loop[i]}
+		for col+len(run) < width {
+			next := (i + len(run)) % len(loop)
+			if owner[next] != owner[i] {
+				break
+			}
+			run = append(run, loop[next])
+		}
+		style := tickerStyle
+		if owner[i] >= 0 && t.items[owner[i]].color != "" {
+			style = style.Foreground(lipgloss.Color(t.items[owner[i]].color))
+		}
+		b.WriteString(style.Render(string(run)))
+		col += len(run)
+	}
+	return "  " + truncateWidth(b.String(), width, "")
+}
@@ -0,0 +1,96 @@
+package activity
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestReadTicker(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+	evt := func(at time.Time, typ, actor, bead, visibility string) events.Event {
+		return events.Event{
+			Timestamp:  at.UTC().Format(time.RFC3339),
+			Source:     "gt",
+			Type:       typ,
+			Actor:      actor,
+			Payload:    map[string]interface{}{"bead": bead},
+			Visibility: visibility,
+		}
+	}
+	var lines []string
+	for _, e := range []events.Event{
+		evt(now.Add(-time.Hour), events.TypeDone, "gastown/polecats/Toast", "gt-old", events.VisibilityFeed), // before the window
+		evt(now.Add(-time.Minute), events.TypeSling, "mayor", "gt-1", events.VisibilityFeed),
+		evt(now.Add(-30*time.Second), events.TypeDone, "gastown/polecats/Toast", "gt-2", events.VisibilityFeed),
+		evt(now.Add(-30*time.Second), events.TypeDone, "gastown/polecats/Nux", "gt-3", events.VisibilityFeed), // same second: replaces gt-2
+		evt(now.Add(-10*time.Second), events.TypeMail, "mayor", "", events.VisibilityAudit),
+	} {
+		data, _ := json.Marshal(e)
+		lines = append(lines, string(data))
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Model{townRoot: townRoot}
+	m.EnableTicker()
+	m.readEvents(now)
+	m.readTicker(now)
+	items := m.ticker.items
+	if len(items) != 2 {
+		t.Fatalf("items = %q, want the sling and Nux's done", items)
+	}
+	if !strings.HasSuffix(items[0].text, "mayor sling gt-1") || !strings.HasSuffix(items[1].text, "gastown/polecats/Nux done gt-3") {
+		t.Errorf("items = %q", items)
+	}
+}
+
+func TestTickerCustomEventType(t *testing.T) {
+	townRoot := t.TempDir()
+	ts := config.NewTownSettings()
+	ts.EventTypes = map[string]*config.EventTypeConfig{"deploy": {Icon: "🚢", Color: "39"}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), ts); err != nil {
+		t.Fatal(err)
+	}
+	m := &Model{townRoot: townRoot, eventTypes: events.LoadRegistry(townRoot)}
+	got := m.tickerItem(events.Event{Type: "deploy", Actor: "mayor"}, time.Now())
+	if !strings.HasSuffix(got.text, "mayor 🚢 deploy") || got.color != "39" {
+		t.Errorf("deploy entry = %+v, want the icon before the type and color 39", got)
+	}
+	if got := m.tickerItem(events.Event{Type: events.TypeDone}, time.Now()); got.color != "" {
+		t.Errorf("built-in entry colored %q", got.color)
+	}
+}
+
+func TestRenderTickerScrolls(t *testing.T) {
+	m := &Model{}
+	m.EnableTicker()
+	if out := m.renderTicker(40); !strings.Contains(out, "no recent events") {
+		t.Errorf("empty ticker = %q", out)
+	}
+
+	m.ticker.items = []tickerEntry{{text: "12:00:00 mayor sling gt-1"}}
+	first := m.renderTicker(40)
+	if !strings.Contains(first, "12:00:00 mayor sling gt-1") {
+		t.Errorf("ticker = %q, want the event", first)
+	}
+	m.scrollTicker()
+	if second := m.renderTicker(40); !strings.Contains(second, "2:00:00 mayor sling gt-1") || second == first {
+		t.Errorf("after a step ticker = %q, want it shifted one column", second)
+	}
+
+	m.frames.slow = true
+	offset := m.ticker.offset
+	m.scrollTicker()
+	if m.ticker.offset != offset {
+		t.Error("ticker scrolled while frames are slow")
+	}
+}
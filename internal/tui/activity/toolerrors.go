@@ -0,0 +1,126 @@
+package activity
+
+import (
+	"strconv"
+	"strings"
+)
+
+// toolErrorMarkers are prefixes (after the ⎿ result connector) that Claude Code
+// uses when a tool call fails. Matching is case-sensitive — these are emitted
+// verbatim by the tool runner, and lowercase "error" in ordinary output would
+// produce false positives.
+var toolErrorMarkers = []string{
+	"Error:",
+	"Error ",
+	"Exit code ",
+	"Command failed",
+	"Tool execution failed",
+}
+
+// extractToolError returns the error summary if the line is a failed tool
+// result, e.g. "⎿  Error: Exit code 1" → "Exit code 1".
+// Only lines carrying the ⎿ result connector are considered so that error
+// text inside file contents or diffs doesn't trip the badge.
+func extractToolError(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "⎿") {
+		return "", false
+	}
+	rest := strings.TrimSpace(strings.TrimPrefix(trimmed, "⎿"))
+	for _, marker := range toolErrorMarkers {
+		if strings.HasPrefix(rest, marker) {
+			msg := strings.TrimSpace(strings.TrimPrefix(rest, "Error:"))
+			if msg == "" {
+				msg = rest
+			}
+			if zeroExit(msg) {
+				return "", false
+			}
+			return truncateStatus(msg), true
+		}
+	}
+	// Non-zero exit summaries without an "Error" prefix: "⎿  exit status 2"
+	if strings.HasPrefix(strings.ToLower(rest), "exit status ") && !zeroExit(rest) {
+		return truncateStatus(rest), true
+	}
+	return "", false
+}
+
+// zeroExit reports whether msg reports a successful exit: "Exit code 0" or
+// "exit status 0", which some runners print for commands that succeeded.
+func zeroExit(msg string) bool {
+	lower := strings.ToLower(msg)
+	for _, prefix := range []string{"exit code ", "exit status "} {
+		if rest, ok := strings.CutPrefix(lower, prefix); ok {
+			code, _, _ := strings.Cut(rest, " ")
+			n, err := strconv.Atoi(code)
+			return err == nil && n == 0
+		}
+	}
+	return false
+}
+
+// lastToolResult finds the bottom-most completed tool call in the pane: the
+// most recent "⏺ Tool(args)" line with a "⎿" result under it. Returns a
+// signature for the call/result pair, the error summary if it failed, and
+// run, how many completed calls in a row at the bottom of the pane have that
+// same signature: an agent retrying the same failing command leaves one
+// block per retry. run is 0 when the pane shows no completed call.
+func lastToolResult(lines []string) (sig, errMsg string, run int) {
+	type block struct{ sig, errMsg string }
+	var blocks []block
+	tool := ""
+	for _, line := range lines {
+		if t := extractCurrentTool(line); t != "" {
+			tool = t
+			continue
+		}
+		if tool == "" || !strings.HasPrefix(strings.TrimSpace(line), "⎿") {
+			continue
+		}
+		// The first result line under the tool carries the error marker
+		// when the call failed; the rest of the result is ignored.
+		result := strings.TrimSpace(line)
+		msg, _ := extractToolError(result)
+		blocks = append(blocks, block{tool + "\x00" + result, msg})
+		tool = ""
+	}
+	if len(blocks) == 0 {
+		return "", "", 0
+	}
+	last := blocks[len(blocks)-1]
+	for i := len(blocks) - 1; i >= 0 && blocks[i].sig == last.sig; i-- {
+		run++
+	}
+	return last.sig, last.errMsg, run
+}
+
+// trackToolErrors updates the recent-error badge from pane content.
+// A new failed result increments ToolErrorCount; a new successful result
+// clears it. The same results re-captured on later polls are ignored, but
+// a retry that fails the same way adds a block to the run at the bottom of
+// the pane and counts, so an agent stuck retrying shows its count climb. (A
+// retry in the same poll as the oldest of the run scrolling off the top of
+// the capture looks like no change, and isn't counted.)
+func trackToolErrors(a *AgentLight, lines []string) {
+	sig, errMsg, run := lastToolResult(lines)
+	if run == 0 {
+		return
+	}
+	added := run
+	if sig == a.lastToolResultSig {
+		// Fewer than last time: the older ones scrolled off.
+		added = max(run-a.lastToolResultRun, 0)
+	}
+	a.lastToolResultSig, a.lastToolResultRun = sig, run
+	if added == 0 {
+		return
+	}
+	if errMsg != "" {
+		a.ToolErrorCount += added
+		a.LastToolError = errMsg
+		return
+	}
+	a.ToolErrorCount = 0
+	a.LastToolError = ""
+}
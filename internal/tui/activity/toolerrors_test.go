@@ -0,0 +1,102 @@
+package activity
+
+import "testing"
+
+func TestExtractToolError(t *testing.T) {
+	tests := []struct {
+		line    string
+		wantMsg string
+		wantErr bool
+	}{
+		{"  ⎿  Error: Exit code 1", "Exit code 1", true},
+		{"  ⎿  Error: File does not exist.", "File does not exist.", true},
+		{"  ⎿  exit status 2", "exit status 2", true},
+		{"  ⎿  exit status 0", "", false},
+		{"  ⎿  Exit code 0", "", false},
+		{"  ⎿  Error: Exit code 0", "", false},
+		{"  ⎿  Exit code 10", "Exit code 10", true},
+		{"  ⎿  exit status 02", "exit status 02", true},
+		{"  ⎿  Read 42 lines", "", false},
+		{"Error: not a tool result", "", false},
+	}
+	for _, tt := range tests {
+		msg, isErr := extractToolError(tt.line)
+		if isErr != tt.wantErr || msg != tt.wantMsg {
+			t.Errorf("extractToolError(%q) = (%q, %v), want (%q, %v)", tt.line, msg, isErr, tt.wantMsg, tt.wantErr)
+		}
+	}
+}
+
+func TestTrackToolErrors(t *testing.T) {
+	a := &AgentLight{}
+
+	failed := []string{
+		"⏺ Bash(go test ./...)",
+		"  ⎿  Error: Exit code 1",
+		"     FAIL foo",
+	}
+	trackToolErrors(a, failed)
+	if a.ToolErrorCount != 1 || a.LastToolError != "Exit code 1" {
+		t.Fatalf("after first failure: count=%d last=%q", a.ToolErrorCount, a.LastToolError)
+	}
+
+	// Same result re-captured on the next poll must not be counted again.
+	trackToolErrors(a, failed)
+	if a.ToolErrorCount != 1 {
+		t.Fatalf("re-captured failure counted twice: count=%d", a.ToolErrorCount)
+	}
+
+	// A different failing call increments the count.
+	trackToolErrors(a, []string{
+		"⏺ Bash(go test ./internal/...)",
+		"  ⎿  Error: Exit code 2",
+	})
+	if a.ToolErrorCount != 2 || a.LastToolError != "Exit code 2" {
+		t.Fatalf("after second failure: count=%d last=%q", a.ToolErrorCount, a.LastToolError)
+	}
+
+	// A successful result clears the badge.
+	trackToolErrors(a, []string{
+		"⏺ Read(model.go)",
+		"  ⎿  Read 120 lines",
+	})
+	if a.ToolErrorCount != 0 || a.LastToolError != "" {
+		t.Fatalf("after success: count=%d last=%q", a.ToolErrorCount, a.LastToolError)
+	}
+}
+
+func TestTrackToolErrors_SameCommandRetried(t *testing.T) {
+	a := &AgentLight{}
+	attempt := []string{
+		"⏺ Bash(make build)",
+		"  ⎿  Error: Exit code 2",
+		"",
+		"⏺ The build failed again; retrying.",
+	}
+	pane := append([]string{"⏺ Read(Makefile)", "  ⎿  Read 40 lines"}, attempt...)
+	trackToolErrors(a, pane)
+	if a.ToolErrorCount != 1 {
+		t.Fatalf("first failure: count=%d", a.ToolErrorCount)
+	}
+
+	// The same command fails again: a second identical block.
+	pane = append(pane, attempt...)
+	trackToolErrors(a, pane)
+	trackToolErrors(a, pane) // re-captured
+	if a.ToolErrorCount != 2 {
+		t.Fatalf("retry of the same failing command: count=%d, want 2", a.ToolErrorCount)
+	}
+
+	// A third.
+	pane = append(pane, attempt...)
+	trackToolErrors(a, pane)
+	if a.ToolErrorCount != 3 {
+		t.Fatalf("third retry: count=%d, want 3", a.ToolErrorCount)
+	}
+
+	// Scrolling off without a new attempt doesn't count or reset.
+	trackToolErrors(a, pane[6:])
+	if a.ToolErrorCount != 3 {
+		t.Fatalf("after scrolling: count=%d, want 3", a.ToolErrorCount)
+	}
+}
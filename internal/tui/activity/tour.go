@@ -0,0 +1,268 @@
+package activity
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/steveyegge/gastown/internal/state"
+)
+
+// tourMarkerFile is the per-user file under the gastown state directory
+// recording that the first-run tour was shown, so it isn't shown again.
+const tourMarkerFile = "top-tour-seen"
+
+// tourTarget is the part of the screen a tour step highlights.
+type tourTarget int
+
+const (
+	tourNone    tourTarget = iota
+	tourHeader             // title and minimap
+	tourPanel              // the first rig panel
+	tourAgent              // the first agent line
+	tourStats              // the stats bar
+	tourHelpBar            // the help line
+)
+
+// tourStep is one callout of the tour. Body is rendered from the live model
+// so the tour talks about this town's rigs and agents.
+type tourStep struct {
+	target tourTarget
+	title  string
+	body   func(m *Model) []string
+}
+
+var (
+	tourBoxStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(colorTitle).
+			Padding(0, 1)
+	tourHighlightStyle = lipgloss.NewStyle().Foreground(colorTitle).Bold(true).Reverse(true)
+)
+
+// tourSteps is the guided tour, in order. Steps about panels and agents are
+// skipped while there are no agents to point at.
+var tourSteps = []tourStep{
+	{tourNone, "Welcome to gt top", func(m *Model) []string {
+		return []string{
+			fmt.Sprintf("gt top watches every agent session in %s: %s.", m.townTitle(), m.tourCounts()),
+			"Each agent is a light that blinks while it works and fades as it idles.",
+		}
+	}},
+	{tourHeader, "Header and minimap", func(m *Model) []string {
+		return []string{
+			"The header names the town and counts its agents.",
+			"Below it, the minimap has one dot per agent, rigs separated by a space.",
+			"Click a dot to find that agent's line in the panels.",
+		}
+	}},
+	{tourPanel, "Rig panels", func(m *Model) []string {
+		if m.groupByConvoy {
+			return []string{
+				"Agents are grouped into a panel per convoy or epic, with its progress (g: back to rigs).",
+				"The border takes the color of its most urgent agent: red when one needs a human.",
+			}
+		}
+		rig := m.tourPanelRig()
+		return []string{
+			fmt.Sprintf("Each rig gets a panel; %s has %d agent(s).", rig, len(m.agentsForRig(rig))),
+			"The border takes the color of its most urgent agent: red when one needs a human.",
+			"SLA and SLO badges from town settings sit beside the rig name.",
+		}
+	}},
+	{tourAgent, "Agent lines", func(m *Model) []string {
+		a := m.tourAgentLight()
+		status, _ := agentStatus(a)
+		if status == "" {
+			status = a.Level.String()
+		}
+		return []string{
+			fmt.Sprintf("One line per agent: %s %s is %q.", a.Icon, a.Name, status),
+			"Right of the status: time since its last change, limits and context left.",
+			"Hover a line for its work bead, convoy and recent output; double-click opens it.",
+		}
+	}},
+	{tourNone, "Lights", func(m *Model) []string {
+		led := m.led()
+		legend := []string{
+			barActiveStyle.Render(led.active) + " working",
+			barRecentStyle.Render(led.recent) + " just stopped",
+			barWarmStyle.Render(led.warm) + " idle",
+			barCoolStyle.Render(led.cool) + " idle a while",
+			barColdStyle.Render(led.cold) + " stalled",
+			barWaitingStyle.Render(led.alarm) + " needs a human",
+			barRateLimitedStyle.Render("↻") + " looping",
+		}
+		return []string{strings.Join(legend, "   "), fmt.Sprintf("Needing a human right now: %d.", m.waitingCount)}
+	}},
+	{tourStats, "Stats bar", func(m *Model) []string {
+		return []string{
+			"The stats bar totals agents by state, with the town's token burn rate.",
+			"Filters (--rig, --role, --level, --convoy) narrow both the panels and these counts.",
+		}
+	}},
+	{tourHelpBar, "Keys", func(m *Model) []string {
+		return []string{
+			"↑/↓ or k/j select an agent without the mouse; it acts as hovered · enter attaches · esc clears",
+			"q quit · f pin hovered · a acknowledge hovered · R restart hovered",
+			"r/F5 refresh hovered · $ /cost · s /status · g group by convoy · b burn-down",
+			"v on two agents in turn: compare their last hour side by side",
+			"ctrl+f: find an agent by name, session or bead; enter selects, ctrl+o also attaches",
+			"/: filter by rig, role or level as you type, e.g. \"gastown polecat waiting\"",
+			"i: the attention inbox, only agents needing you, most urgent first, with one-key actions",
+			"M: run one of the town's macros (top.macros) on the hovered agent",
+			"T: the town control screen, declared roles against running sessions, with start, stop and reconcile",
+			"y copy session name · Y copy attach command · c copy work bead ID",
+			"N/A/K nudge, acknowledge or kill every agent the filter shows",
+			"The help line at the bottom lists these; F1 brings this tour back.",
+		}
+	}},
+}
+
+// tourState is the tour in progress.
+type tourState struct {
+	step int // index into tourSteps
+}
+
+// tourMarkerPath returns the first-run marker path.
+func tourMarkerPath() string {
+	return filepath.Join(state.StateDir(), tourMarkerFile)
+}
+
+// StartTourOnFirstRun opens the tour when this user has never seen it, and
+// records that they have.
+func (m *Model) StartTourOnFirstRun() {
+	path := tourMarkerPath()
+	if _, err := os.Stat(path); err == nil {
+		return
+	}
+	m.startTour()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+		_ = os.WriteFile(path, nil, 0644)
+	}
+}
+
+// startTour opens the tour at its first step.
+func (m *Model) startTour() {
+	m.tour = &tourState{}
+	m.flashMessage = ""
+}
+
+// handleTourKey steps through the tour; any other key leaves it. ctrl+c is
+// handled by the caller.
+func (m *Model) handleTourKey(key string) {
+	switch key {
+	case "right", "l", "n", " ", "enter", "tab":
+		if next := m.tourStepFrom(m.tour.step+1, 1); next >= 0 {
+			m.tour.step = next
+		} else {
+			m.tour = nil
+		}
+	case "left", "h", "p", "backspace", "shift+tab":
+		if prev := m.tourStepFrom(m.tour.step-1, -1); prev >= 0 {
+			m.tour.step = prev
+		}
+	default:
+		m.tour = nil
+	}
+}
+
+// tourStepFrom returns the first step from i in direction dir that has
+// something to point at, or -1.
+func (m *Model) tourStepFrom(i, dir int) int {
+	for ; i >= 0 && i < len(tourSteps); i += dir {
+		switch tourSteps[i].target {
+		case tourPanel, tourAgent:
+			if m.tourAgentLight() == nil {
+				continue
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+// tourTargets reports whether the current tour step highlights t.
+func (m *Model) tourTargets(t tourTarget) bool {
+	return m.tour != nil && tourSteps[m.tour.step].target == t
+}
+
+// tourPanelRig returns the rig whose panel the tour points at: the first
+// one shown.
+func (m *Model) tourPanelRig() string {
+	for _, rig := range m.rigs {
+		if len(m.unpinnedAgents(m.agentsForRig(rig))) > 0 {
+			return rig
+		}
+	}
+	return ""
+}
+
+// tourAgentLight returns the agent the tour points at: the first one in the
+// first panel shown, or nil when no panel is shown.
+func (m *Model) tourAgentLight() *AgentLight {
+	var agents []*AgentLight
+	if m.groupByConvoy {
+		for _, id := range m.groupOrder() {
+			if agents = m.unpinnedAgents(m.agentsForGroup(id)); len(agents) > 0 {
+				break
+			}
+		}
+	} else {
+		agents = m.unpinnedAgents(m.agentsForRig(m.tourPanelRig()))
+	}
+	if len(agents) > 0 {
+		return agents[0]
+	}
+	return nil
+}
+
+// tourHighlight highlights a rendered section the tour points at.
+func (m *Model) tourHighlight(t tourTarget, s string) string {
+	if !m.tourTargets(t) {
+		return s
+	}
+	return tourHighlightStyle.Render(ansi.Strip(s))
+}
+
+// tourCounts summarizes the town for the welcome step.
+func (m *Model) tourCounts() string {
+	if m.totalAgents == 0 {
+		return "no agent sessions are running yet"
+	}
+	return fmt.Sprintf("%d agent(s) across %d rig(s)", m.totalAgents, len(m.rigs))
+}
+
+// renderTour renders the current step's callout, shown under the help line.
+// Steps that point at a part of the screen above say so.
+func (m *Model) renderTour() string {
+	// The agent a step pointed at may have gone since the last key.
+	if i := m.tourStepFrom(m.tour.step, 1); i != m.tour.step {
+		m.tour.step = max(i, 0)
+	}
+	step := tourSteps[m.tour.step]
+	n, total := 0, 0
+	for i := range tourSteps {
+		if m.tourStepFrom(i, 1) == i {
+			total++
+			if i <= m.tour.step {
+				n++
+			}
+		}
+	}
+	title := step.title
+	if step.target != tourNone {
+		title = "↑ " + title
+	}
+	body := []string{titleStyle.Render(title) + subtitleStyle.Render(fmt.Sprintf("  %d/%d", n, total)), ""}
+	body = append(body, step.body(m)...)
+	body = append(body, "", helpStyle.Render("→/space: next  •  ←: back  •  any other key: close  •  F1: tour again"))
+	style := tourBoxStyle
+	if m.width > 6 {
+		style = style.MaxWidth(m.width - 4)
+	}
+	return style.Render(strings.Join(body, "\n"))
+}
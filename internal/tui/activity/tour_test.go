@@ -0,0 +1,97 @@
+package activity
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// tourAgents is a crew member waiting on a human, for the tour to point at.
+func tourAgents() []*AgentLight {
+	return []*AgentLight{{Name: "max", Icon: "👷", Rig: "gastown", SessionName: "gt-gastown-crew-max", Level: LevelWaitingForHuman}}
+}
+
+func TestTourSteps(t *testing.T) {
+	m := testModel(tourAgents())
+	m.startTour()
+	seen := []int{m.tour.step}
+	for m.tour != nil {
+		if view := m.View(); !strings.Contains(view, tourSteps[m.tour.step].title) {
+			t.Errorf("view at step %d lacks its callout", m.tour.step)
+		}
+		m.handleTourKey("right")
+		if m.tour != nil {
+			seen = append(seen, m.tour.step)
+		}
+	}
+	if len(seen) != len(tourSteps) {
+		t.Errorf("walked %d steps, want all %d", len(seen), len(tourSteps))
+	}
+
+	m.startTour()
+	m.handleTourKey("left")
+	if m.tour == nil || m.tour.step != 0 {
+		t.Errorf("back from the first step: %+v, want to stay on it", m.tour)
+	}
+	m.handleTourKey("x")
+	if m.tour != nil {
+		t.Error("any other key should close the tour")
+	}
+}
+
+func TestTourSkipsAgentStepsWithoutAgents(t *testing.T) {
+	m := &Model{width: 120}
+	m.startTour()
+	for m.tour != nil {
+		if target := tourSteps[m.tour.step].target; target == tourPanel || target == tourAgent {
+			t.Fatalf("step %d points at a panel with no agents", m.tour.step)
+		}
+		if got := m.renderTour(); !strings.Contains(got, tourSteps[m.tour.step].title) {
+			t.Errorf("callout %q lacks its title", got)
+		}
+		m.handleTourKey("right")
+	}
+}
+
+func TestRenderTour_DescribesTown(t *testing.T) {
+	m := testModel(tourAgents())
+	m.startTour()
+	if got := m.renderTour(); !strings.Contains(got, "1 agent(s) across 1 rig(s)") {
+		t.Errorf("welcome = %q", got)
+	}
+	for m.tour != nil && tourSteps[m.tour.step].target != tourAgent {
+		m.handleTourKey("right")
+	}
+	got := m.renderTour()
+	if !strings.Contains(got, "↑ Agent lines") || !strings.Contains(got, "max") {
+		t.Errorf("agent step = %q", got)
+	}
+	if !m.tourTargets(tourAgent) || m.tourAgentLight() != m.agents[0] {
+		t.Error("agent step should point at the first agent")
+	}
+
+	// The agent leaving mid-tour moves the callout to a step that still applies.
+	m.agents, m.rigs, m.totalAgents = nil, nil, 0
+	if got := m.renderTour(); strings.Contains(got, "Agent lines") {
+		t.Errorf("callout still about a gone agent: %q", got)
+	}
+}
+
+func TestStartTourOnFirstRun(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	m := &Model{}
+	m.StartTourOnFirstRun()
+	if m.tour == nil {
+		t.Fatal("first run should open the tour")
+	}
+	if _, err := os.Stat(tourMarkerPath()); err != nil {
+		t.Errorf("marker not written: %v", err)
+	}
+
+	m = &Model{}
+	m.StartTourOnFirstRun()
+	if m.tour != nil {
+		t.Error("the tour should open only on the first run")
+	}
+}
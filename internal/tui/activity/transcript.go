@@ -0,0 +1,287 @@
+package activity
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// transcriptTailMax bounds how much of an existing transcript is read when
+// gt top first opens it. Older turns can't affect the current status.
+const transcriptTailMax = 2 << 20
+
+// transcriptInfo is what a Claude Code session transcript says about the
+// agent's current turn.
+type transcriptInfo struct {
+	task   string    // in-progress todo (TodoWrite activeForm), "" when none
+	tool   string    // tool call awaiting its result, e.g. "Bash(git status)"
+	tokens int       // context size as of the last assistant turn
+	at     time.Time // timestamp of the last entry read
+}
+
+// transcriptTail is the read position in one agent's transcript.
+type transcriptTail struct {
+	workDir string // pane working directory, resolved once per session
+	path    string // transcript currently being tailed
+	offset  int64
+	toolID  string // tool_use id of info.tool
+	info    transcriptInfo
+}
+
+// transcriptReader is the adapter that reads agent status from Claude Code
+// transcripts (<claude config>/projects/<cwd hash>/<uuid>.jsonl) instead of
+// the pane. It only adds what it can read reliably; everything else, and
+// every agent without a transcript, keeps the pane parser's values.
+type transcriptReader struct {
+	projectsDir string
+	workDir     func(session string) (string, error)
+	tails       map[string]*transcriptTail // session name -> tail
+}
+
+// newTranscriptReader returns a reader for the current user's Claude config
+// dir (respecting CLAUDE_CONFIG_DIR), or nil if it can't be located.
+func newTranscriptReader() *transcriptReader {
+	dir, err := config.ClaudeConfigDir()
+	if err != nil {
+		return nil
+	}
+	return &transcriptReader{
+		projectsDir: filepath.Join(dir, "projects"),
+		workDir:     tmux.NewTmux().GetPaneWorkDir,
+		tails:       make(map[string]*transcriptTail),
+	}
+}
+
+// EnableTranscripts turns on reading Claude Code transcripts for richer
+// status (gt top --transcripts, or town settings top.transcripts).
+func (m *Model) EnableTranscripts() {
+	if m.transcripts == nil {
+		m.transcripts = newTranscriptReader()
+	}
+}
+
+// claudeProjectName converts a working directory to Claude Code's project
+// directory name, which replaces every non-alphanumeric character with '-'
+// (e.g. /home/me/gt/gastown_pm → -home-me-gt-gastown-pm).
+func claudeProjectName(workDir string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '-'
+	}, filepath.ToSlash(workDir))
+}
+
+// apply overlays transcript status on the pane-parsed values. Must run after
+// the pane parser.
+func (r *transcriptReader) apply(a *AgentLight) {
+	info, ok := r.read(a)
+	if !ok {
+		return
+	}
+	a.CurrentTask = info.task
+	if info.tool != "" {
+		a.CurrentTool = info.tool
+	}
+	if info.tokens > 0 {
+		a.TokenCount = info.tokens
+	}
+}
+
+// read returns the agent's transcript status, or false when no transcript
+// written since the session started can be found.
+func (r *transcriptReader) read(a *AgentLight) (transcriptInfo, bool) {
+	t := r.tails[a.SessionName]
+	if t == nil {
+		dir, err := r.workDir(a.SessionName)
+		if err != nil || dir == "" {
+			return transcriptInfo{}, false
+		}
+		t = &transcriptTail{workDir: dir}
+		r.tails[a.SessionName] = t
+	}
+
+	path, ok := newestTranscript(filepath.Join(r.projectsDir, claudeProjectName(t.workDir)), a.SessionCreated)
+	if !ok {
+		return transcriptInfo{}, false
+	}
+	if path != t.path {
+		// New Claude session in the same pane: start over.
+		*t = transcriptTail{workDir: t.workDir, path: path}
+	}
+	t.readNew()
+	return t.info, true
+}
+
+// forget drops tails for sessions that are gone or restarted.
+func (r *transcriptReader) forget(session string) {
+	delete(r.tails, session)
+}
+
+// newestTranscript returns the most recently modified .jsonl in dir that was
+// written at or after since (zero since accepts any).
+func newestTranscript(dir string, since time.Time) (string, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", false
+	}
+	var best string
+	var bestTime time.Time
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil || (!since.IsZero() && fi.ModTime().Before(since)) {
+			continue
+		}
+		if best == "" || fi.ModTime().After(bestTime) {
+			best, bestTime = filepath.Join(dir, e.Name()), fi.ModTime()
+		}
+	}
+	return best, best != ""
+}
+
+// readNew parses complete lines appended since the last read. A trailing
+// partial line is left for the next poll.
+func (t *transcriptTail) readNew() {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	skipFirst := false
+	if t.offset == 0 {
+		if fi, err := f.Stat(); err == nil && fi.Size() > transcriptTailMax {
+			t.offset = fi.Size() - transcriptTailMax
+			skipFirst = true // starts mid-line
+		}
+	}
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return
+	}
+
+	reader := bufio.NewReaderSize(f, 64*1024)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return // EOF (possibly mid-line): retry the partial line next poll
+		}
+		t.offset += int64(len(line))
+		if skipFirst {
+			skipFirst = false
+			continue
+		}
+		t.apply(line)
+	}
+}
+
+// Claude Code transcript entries, reduced to the fields gt top reads.
+type (
+	transcriptEntry struct {
+		Type      string             `json:"type"`
+		Timestamp time.Time          `json:"timestamp"`
+		Message   *transcriptMessage `json:"message"`
+	}
+	transcriptMessage struct {
+		Content json.RawMessage `json:"content"` // string for plain prompts, else blocks
+		Usage   *struct {
+			InputTokens              int `json:"input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		} `json:"usage"`
+	}
+	transcriptBlock struct {
+		Type      string          `json:"type"`
+		ID        string          `json:"id"`          // tool_use
+		Name      string          `json:"name"`        // tool_use
+		Input     json.RawMessage `json:"input"`       // tool_use
+		ToolUseID string          `json:"tool_use_id"` // tool_result
+	}
+)
+
+// apply folds one transcript line into the tail's status.
+func (t *transcriptTail) apply(line string) {
+	var e transcriptEntry
+	if err := json.Unmarshal([]byte(line), &e); err != nil || e.Message == nil {
+		return
+	}
+	if e.Type != "assistant" && e.Type != "user" {
+		return
+	}
+	if !e.Timestamp.IsZero() {
+		t.info.at = e.Timestamp
+	}
+
+	var blocks []transcriptBlock
+	_ = json.Unmarshal(e.Message.Content, &blocks) // a plain string prompt has no blocks
+	for _, b := range blocks {
+		switch b.Type {
+		case "tool_use":
+			t.toolID = b.ID
+			t.info.tool = formatTranscriptTool(b.Name, b.Input)
+			if b.Name == "TodoWrite" {
+				t.info.task = inProgressTodo(b.Input)
+			}
+		case "tool_result":
+			if b.ToolUseID == t.toolID {
+				t.toolID, t.info.tool = "", ""
+			}
+		}
+	}
+
+	if u := e.Message.Usage; e.Type == "assistant" && u != nil {
+		if n := u.InputTokens + u.CacheReadInputTokens + u.CacheCreationInputTokens + u.OutputTokens; n > 0 {
+			t.info.tokens = n
+		}
+	}
+}
+
+// formatTranscriptTool renders a tool call the way Claude's pane shows it,
+// e.g. "Bash(git status)" or "Read(internal/cmd/top.go)".
+func formatTranscriptTool(name string, input json.RawMessage) string {
+	var args map[string]any
+	_ = json.Unmarshal(input, &args)
+	for _, key := range []string{"command", "file_path", "filePath", "pattern", "url", "description"} {
+		if s, ok := args[key].(string); ok && s != "" {
+			if i := strings.IndexByte(s, '\n'); i >= 0 {
+				s = s[:i] + "…"
+			}
+			return name + "(" + s + ")"
+		}
+	}
+	return name
+}
+
+// inProgressTodo returns the in-progress item of a TodoWrite call, preferring
+// its present-tense form ("Running tests" over "Run tests").
+func inProgressTodo(input json.RawMessage) string {
+	var in struct {
+		Todos []struct {
+			Content    string `json:"content"`
+			Status     string `json:"status"`
+			ActiveForm string `json:"activeForm"`
+		} `json:"todos"`
+	}
+	if json.Unmarshal(input, &in) != nil {
+		return ""
+	}
+	for _, td := range in.Todos {
+		if td.Status == "in_progress" {
+			if td.ActiveForm != "" {
+				return td.ActiveForm
+			}
+			return td.Content
+		}
+	}
+	return ""
+}
@@ -0,0 +1,147 @@
+package activity
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClaudeProjectName(t *testing.T) {
+	if got := claudeProjectName("/home/me/gt/gastown_pm/crew/max.dev"); got != "-home-me-gt-gastown-pm-crew-max-dev" {
+		t.Errorf("claudeProjectName = %q", got)
+	}
+}
+
+func TestFormatTranscriptTool(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"Bash", `{"command":"git status","description":"Show status"}`, "Bash(git status)"},
+		{"Read", `{"file_path":"internal/cmd/top.go"}`, "Read(internal/cmd/top.go)"},
+		{"Bash", `{"command":"cat <<EOF\nhi\nEOF"}`, "Bash(cat <<EOF…)"},
+		{"TodoWrite", `{"todos":[]}`, "TodoWrite"},
+	}
+	for _, tt := range tests {
+		if got := formatTranscriptTool(tt.name, []byte(tt.input)); got != tt.want {
+			t.Errorf("formatTranscriptTool(%s, %s) = %q, want %q", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+const (
+	tsTodo    = `{"type":"assistant","timestamp":"2026-10-15T10:00:00Z","message":{"content":[{"type":"tool_use","id":"t1","name":"TodoWrite","input":{"todos":[{"content":"Run tests","status":"in_progress","activeForm":"Running tests"},{"content":"Fix lint","status":"pending","activeForm":"Fixing lint"}]}}]}}`
+	tsTodoRes = `{"type":"user","timestamp":"2026-10-15T10:00:01Z","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":[{"type":"text","text":"ok"}]}]}}`
+	tsBash    = `{"type":"assistant","timestamp":"2026-10-15T10:00:02Z","message":{"content":[{"type":"text","text":"Running."},{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go test ./..."}}],"usage":{"input_tokens":10,"cache_read_input_tokens":40000,"cache_creation_input_tokens":500,"output_tokens":200}}}`
+	tsBashRes = `{"type":"user","timestamp":"2026-10-15T10:00:09Z","message":{"content":[{"type":"tool_result","tool_use_id":"t2","content":"PASS"}]}}`
+)
+
+func writeTranscript(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, l := range lines {
+		if _, err := f.WriteString(l + "\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func testTranscriptReader(t *testing.T, workDir string) (*transcriptReader, string) {
+	projects := t.TempDir()
+	dir := filepath.Join(projects, claudeProjectName(workDir))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	r := &transcriptReader{
+		projectsDir: projects,
+		workDir:     func(string) (string, error) { return workDir, nil },
+		tails:       make(map[string]*transcriptTail),
+	}
+	return r, dir
+}
+
+func TestTranscriptReader_TracksTurn(t *testing.T) {
+	r, dir := testTranscriptReader(t, "/town/gastown/crew/max")
+	path := filepath.Join(dir, "abc.jsonl")
+	writeTranscript(t, path, tsTodo, tsTodoRes, tsBash)
+
+	a := &AgentLight{SessionName: "gt-crew-max", CurrentTool: "pane tool"}
+	r.apply(a)
+	if a.CurrentTask != "Running tests" {
+		t.Errorf("CurrentTask = %q", a.CurrentTask)
+	}
+	if a.CurrentTool != "Bash(go test ./...)" {
+		t.Errorf("CurrentTool = %q", a.CurrentTool)
+	}
+	if a.TokenCount != 40710 {
+		t.Errorf("TokenCount = %d, want 40710", a.TokenCount)
+	}
+
+	// The result arrives: the pane's view of the tool wins again.
+	writeTranscript(t, path, tsBashRes)
+	a.CurrentTool = ""
+	r.apply(a)
+	if a.CurrentTool != "" {
+		t.Errorf("CurrentTool after result = %q, want pane value", a.CurrentTool)
+	}
+	if a.CurrentTask != "Running tests" {
+		t.Errorf("task should persist until the todo list changes, got %q", a.CurrentTask)
+	}
+}
+
+func TestTranscriptReader_PartialLine(t *testing.T) {
+	r, dir := testTranscriptReader(t, "/w")
+	path := filepath.Join(dir, "abc.jsonl")
+	writeTranscript(t, path, tsTodo)
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	_, _ = f.WriteString(tsBash[:40])
+	f.Close()
+
+	a := &AgentLight{SessionName: "s"}
+	r.apply(a)
+	if a.CurrentTool != "TodoWrite" {
+		t.Fatalf("CurrentTool = %q, partial line must not be parsed", a.CurrentTool)
+	}
+
+	f, _ = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	_, _ = f.WriteString(tsBash[40:] + "\n")
+	f.Close()
+	r.apply(a)
+	if a.CurrentTool != "Bash(go test ./...)" {
+		t.Errorf("CurrentTool = %q once the line completes", a.CurrentTool)
+	}
+}
+
+func TestTranscriptReader_Fallback(t *testing.T) {
+	r, dir := testTranscriptReader(t, "/w")
+
+	// No transcript: pane values are untouched.
+	a := &AgentLight{SessionName: "s", CurrentTool: "Edit(x.go)", TokenCount: 99}
+	r.apply(a)
+	if a.CurrentTool != "Edit(x.go)" || a.TokenCount != 99 {
+		t.Error("pane values should be kept without a transcript")
+	}
+
+	// A transcript from before the session started belongs to someone else.
+	old := filepath.Join(dir, "old.jsonl")
+	writeTranscript(t, old, tsBash)
+	past := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(old, past, past)
+	a.SessionCreated = time.Now().Add(-time.Minute)
+	r.apply(a)
+	if a.CurrentTool != "Edit(x.go)" {
+		t.Errorf("CurrentTool = %q from a stale transcript", a.CurrentTool)
+	}
+
+	// A new Claude session in the same directory replaces the tail.
+	writeTranscript(t, filepath.Join(dir, "new.jsonl"), tsTodo)
+	r.apply(a)
+	if a.CurrentTask != "Running tests" || !strings.HasSuffix(r.tails["s"].path, "new.jsonl") {
+		t.Errorf("task = %q, path = %q", a.CurrentTask, r.tails["s"].path)
+	}
+}
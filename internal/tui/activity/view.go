@@ -9,7 +9,7 @@ import (
 	"github.com/steveyegge/gastown/internal/ui"
 )
 
-// Dot indicators for agent activity (replacing LED block bars)
+// Dot indicators for agent activity: the default "circles" LED theme (see theme.go)
 const (
 	dotActive = "●" // filled circle — active
 	dotIdle   = "○" // open circle — idle/warm
@@ -159,30 +159,119 @@ func (m *Model) render() string {
 	// Without an outer border, the header is at screen Y=0.
 	// Rig content follows immediately after the header.
 	currentY := 0 // header line
+	for _, a := range m.agents {
+		a.renderY = 0 // agents hidden by the filter must not stay hoverable
+	}
 
 	var sections []string
 
 	// Header
-	sections = append(sections, m.renderHeader())
+	sections = append(sections, m.tourHighlight(tourHeader, m.renderHeader()))
+	if m.totalAgents > 0 {
+		currentY++
+		sections = append(sections, m.renderMinimap(currentY))
+	} else {
+		m.minimapCells = m.minimapCells[:0]
+	}
+	if m.filterBar != nil {
+		sections = append(sections, m.renderFilterBar())
+		currentY += 2
+	} else if filter := m.renderFilterLine(); filter != "" {
+		sections = append(sections, filter)
+		currentY++
+	}
+	if banner := m.renderDegradedBanner(); banner != "" {
+		sections = append(sections, banner)
+		currentY++
+	}
+	if banner := m.renderTmuxStallBanner(); banner != "" {
+		sections = append(sections, banner)
+		currentY++
+	}
+	if banner := m.renderSlowBanner(); banner != "" {
+		sections = append(sections, banner)
+		currentY++
+	}
+	if banner := m.renderSLOBanner(); banner != "" {
+		sections = append(sections, banner)
+		currentY++
+	}
+	if banner := m.renderOutageBanner(); banner != "" {
+		sections = append(sections, banner)
+		currentY++
+	}
 
-	if m.totalAgents == 0 {
+	if m.probe != nil {
+		sections = append(sections, "", m.renderProbe())
+	} else if m.compare != nil {
+		sections = append(sections, "", m.renderCompare())
+	} else if m.switcher != nil {
+		sections = append(sections, "", m.renderSwitcher())
+	} else if m.inbox != nil {
+		sections = append(sections, "", m.renderInbox())
+	} else if m.macroMenu != nil {
+		sections = append(sections, "", m.renderMacroMenu())
+	} else if m.control != nil {
+		sections = append(sections, "", m.renderControl())
+	} else if m.totalAgents == 0 && len(m.placeholders) == 0 {
 		sections = append(sections, "")
 		sections = append(sections, subtitleStyle.Render("  No agent sessions running."))
 		sections = append(sections, subtitleStyle.Render("  Start agents with: gt mayor start"))
 	} else {
-		// Rig panels
-		for _, rig := range m.rigs {
-			rigContent := m.renderRigWithPositions(rig, &currentY)
-			sections = append(sections, rigContent)
+		// The burn-down and pinned agents first — they never scroll off the
+		// top — then rigs.
+		// Panels with no agents (every agent pinned) are skipped.
+		var panels []panel
+		if m.burndownID != "" {
+			panels = append(panels, panel{height: m.burndownHeight(), render: m.renderBurndown})
+		}
+		if n := len(m.pinnedAgents()); n > 0 {
+			panels = append(panels, panel{height: n + panelChrome, render: m.renderPinnedWithPositions})
+		}
+		if m.groupByConvoy {
+			for _, id := range m.groupOrder() {
+				id := id
+				if n := len(m.unpinnedAgents(m.agentsForGroup(id))); n > 0 {
+					panels = append(panels, panel{height: n + panelChrome, render: func(y *int) string {
+						return m.renderGroupWithPositions(id, y)
+					}})
+				}
+			}
+		} else {
+			for _, rig := range m.rigs {
+				rig := rig
+				if n := len(m.panelAgents(rig)); n > 0 {
+					panels = append(panels, panel{height: n + panelChrome + m.rigHistoryHeight(rig) + m.repoFooterHeight(rig), render: func(y *int) string {
+						return m.renderRigWithPositions(rig, y)
+					}})
+				}
+			}
 		}
+		sections = append(sections, m.renderPanels(panels, &currentY))
+	}
+
+	// Event ticker under the panels (--ticker)
+	if m.ticker != nil {
+		sections = append(sections, "", m.renderTicker(max(m.width-6, 20)))
 	}
 
 	// Stats bar
 	sections = append(sections, "")
-	sections = append(sections, m.renderStats())
+	sections = append(sections, m.tourHighlight(tourStats, m.renderStats()))
 
-	// Help or hover detail (replaces help line when hovering)
-	if m.hoveredAgent != nil {
+	if footer := m.renderDegradedFooter(); footer != "" {
+		sections = append(sections, footer)
+	}
+
+	// Help or hover detail (replaces help line when hovering);
+	// a pending bulk confirmation takes precedence over both, and the tour
+	// over everything.
+	if m.tour != nil {
+		// The tour's callout sits under the help line, below every hover row.
+		sections = append(sections, m.tourHighlight(tourHelpBar, m.renderHelp()), m.renderTour())
+	} else if m.pendingBulk != nil {
+		sections = append(sections, m.renderBulkPrompt())
+	} else if m.hoveredAgent != nil {
 		sections = append(sections, m.renderHoverDetail())
 	} else if flash := m.activeFlash(); flash != "" {
 		sections = append(sections, m.renderFlash(flash))
@@ -208,12 +297,19 @@ func (m *Model) renderHeader() string {
 
 	title := titleStyle.Render(m.townTitle())
 	sub := subtitleStyle.Render("agent monitor")
+	if m.redact {
+		sub = subtitleStyle.Render("agent monitor · redacted")
+	}
 
 	agentCount := ""
 	if m.totalAgents > 0 {
 		agentCount = subtitleStyle.Render(fmt.Sprintf("%d agents", m.totalAgents))
 	}
 
+	if score := m.renderScore(); score != "" {
+		agentCount = score + "  " + agentCount
+	}
+
 	left := sparkleStyle.Render(sparkle) + " " + title + "  " + sub
 	gap := m.width - lipgloss.Width(left) - lipgloss.Width(agentCount) - 8
 	if gap < 2 {
@@ -233,7 +329,8 @@ func (m *Model) renderAgentRow(agents []*AgentLight) string {
 }
 
 // renderLight renders a single agent line: icon name bar status elapsed
-func (m *Model) renderLight(a *AgentLight) string {
+func (m *Model) renderLight(agent *AgentLight) string {
+	a := m.display(agent)
 	elapsed := time.Since(a.LastChangeTime)
 
 	// Name styling based on activity level
@@ -255,6 +352,8 @@ func (m *Model) renderLight(a *AgentLight) string {
 		nameStyle = nameRateLimitedStyle // orange family, same as rate-limited
 	case LevelWaitingForHuman:
 		nameStyle = nameWaitingStyle
+	case LevelStarting, LevelReady:
+		nameStyle = nameRecentStyle
 	}
 
 	// Compacting overrides level-based name style — it's a transient
@@ -262,112 +361,33 @@ func (m *Model) renderLight(a *AgentLight) string {
 	if a.IsCompacting {
 		nameStyle = nameCompactingStyle
 	}
-
-	// Truncate long names
-	displayName := a.Name
-	if len(displayName) > 10 {
-		displayName = displayName[:9] + "~"
+	if m.muted(agent) || a.showsScheduledOff() {
+		nameStyle = nameColdStyle
 	}
-	// Pad name to fixed width for alignment
-	displayName = fmt.Sprintf("%-10s", displayName)
+	if m.isJumpTarget(agent) || m.isKeySelected(agent) || (m.tourTargets(tourAgent) && agent == m.tourAgentLight()) {
+		nameStyle = nameStyle.Reverse(true)
+	}
+
+	// Truncate long names and pad to a fixed width for alignment, in
+	// columns: CJK and emoji names are two columns per character.
+	displayName := padWidth(truncateWidth(a.Name, 10, "~"), 10)
 
 	// Bar visualization - the actual "blinkenlights"
 	bar := m.renderBar(a)
 
 	// Status text + elapsed time
-	var statusStr string
-	var stStyle lipgloss.Style
-
-	// Build work bead context — this is the primary info for the agent line.
-	// Shows bead ID, title, and molecule step progress when available.
-	var beadCtx string
-	if a.WorkBeadID != "" {
-		beadCtx = a.WorkBeadID
-		if a.WorkBeadTitle != "" {
-			beadCtx += ": " + a.WorkBeadTitle
-		}
-		if a.StepsTotal > 0 {
-			beadCtx += fmt.Sprintf(" [%d/%d]", a.StepsDone, a.StepsTotal)
-			if a.StepCurrent != "" {
-				beadCtx += " " + a.StepCurrent
-			}
-		}
+	statusStr, stStyle := agentStatus(a)
+	if label := m.repoLabel(agent); label != "" && statusStr != "" {
+		statusStr = label + statusStr
 	}
-
-	// Priority order:
-	//   1. Warning states (HIT LIMIT, NEEDS HUMAN) — always override
-	//   2. Compacting — transient maintenance, overrides normal work display
-	//   3. Bead context as primary content (tool appended if active)
-	//   4. Active tool alone (no bead info available)
-	//   5. Fallback to StatusText or level-based defaults
-	switch {
-	case a.Level == LevelHitLimit:
-		statusStr = "⚠ HIT LIMIT"
-		if a.LimitResetInfo != "" {
-			statusStr += " · " + a.LimitResetInfo
-		}
-		stStyle = statRateLimitedStyle
-	case a.Level == LevelWaitingForHuman:
-		statusStr = "⚠ NEEDS HUMAN"
-		if a.WaitingReason != "" {
-			statusStr += " · " + a.WaitingReason
-		}
-		stStyle = statusWaitingStyle
-	case a.IsCompacting:
-		statusStr = "COMPACTING"
-		stStyle = statusCompactingStyle
-	case beadCtx != "":
-		switch a.Level {
-		case LevelCold:
-			statusStr = "stalled · " + beadCtx
-			stStyle = lipgloss.NewStyle().Foreground(colorCold)
-		case LevelRateLimited:
-			statusStr = "rate limited · " + beadCtx
-			stStyle = lipgloss.NewStyle().Foreground(colorRateLimited)
-		default:
-			statusStr = beadCtx
-			if a.CurrentTool != "" {
-				statusStr += " · ⏺ " + a.CurrentTool
-			}
-			stStyle = statusDimStyle
-		}
-	case a.CurrentTool != "":
-		statusStr = "⏺ " + a.CurrentTool
-		stStyle = statusDimStyle
-	default:
-		// No bead info — fall back to patrol status, then level/status text
-		switch a.Level {
-		case LevelActive, LevelRecent:
-			if a.StatusText != "" {
-				statusStr = a.StatusText
-			} else if a.LastPatrol != "" {
-				statusStr = a.LastPatrol
-			}
-			stStyle = statusDimStyle
-		case LevelWarm, LevelCool:
-			if a.StatusText != "" {
-				statusStr = a.StatusText
-			} else if a.LastPatrol != "" {
-				statusStr = a.LastPatrol
-			} else {
-				statusStr = "idle"
-			}
-			stStyle = statusDimStyle
-		case LevelCold:
-			if a.LastPatrol != "" {
-				statusStr = "stalled · " + a.LastPatrol
-			} else {
-				statusStr = "stalled"
-			}
-			stStyle = lipgloss.NewStyle().Foreground(colorCold)
-		case LevelRateLimited:
-			if a.LastPatrol != "" {
-				statusStr = "rate limited · " + a.LastPatrol
-			} else {
-				statusStr = "rate limited"
-			}
-			stStyle = lipgloss.NewStyle().Foreground(colorRateLimited)
-		}
+	if a.waitsOnMerge() {
+		statusStr, stStyle = m.repoLabel(agent)+a.mergeQueueStatus(), statusDimStyle
+	}
+	if a.showsScheduledOff() {
+		statusStr, stStyle = "scheduled off", statusDimStyle
+	}
+	if m.muted(agent) {
+		statusStr, stStyle = "muted · "+statusStr, statusDimStyle
 	}
 
 	// Elapsed time — shown right-justified alongside context/compaction info
@@ -381,7 +401,43 @@ func (m *Model) renderLight(a *AgentLight) string {
 	// Build right-side string (full version first)
 	buildRightSide := func(compact bool) string {
 		var rs string
+		if progress := renderProgressIndicator(a, compact); progress != "" {
+			rs += progress
+		}
+		if push := renderPushBadge(a); push != "" {
+			if rs != "" {
+				rs += "  "
+			}
+			rs += push
+		}
+		if a.Panes > 1 {
+			if rs != "" {
+				rs += "  "
+			}
+			rs += statusDimStyle.Render(fmt.Sprintf("⧉%d", a.Panes))
+		}
+		if !a.CaptureStaleSince.IsZero() {
+			if rs != "" {
+				rs += "  "
+			}
+			rs += statRateLimitedStyle.Render("⌛")
+		}
+		if a.ToolErrorCount > 0 {
+			if rs != "" {
+				rs += "  "
+			}
+			rs += renderToolErrorBadge(a.ToolErrorCount, compact)
+		}
+		if a.TokensPerMin > 0 && !compact {
+			if rs != "" {
+				rs += "  "
+			}
+			rs += statusDimStyle.Render(formatTokenRate(a.TokensPerMin))
+		}
 		if showElapsed {
+			if rs != "" {
+				rs += "  "
+			}
 			rs += statusDimStyle.Render(elapsedStr)
 		}
 		if a.SessionLimitPct > 0 {
@@ -413,7 +469,7 @@ func (m *Model) renderLight(a *AgentLight) string {
 	prefixWidth := lipgloss.Width(prefix)
 
 	// Content width inside rig panel: rig border(4) + outer padding(2) + safety(2)
-	contentWidth := m.width - 8
+	contentWidth := m.layoutWidth() - 8
 	leftBudget := contentWidth - prefixWidth - rightFullWidth
 	if leftBudget < 10 {
 		leftBudget = 10
@@ -484,56 +540,205 @@ func (m *Model) renderLight(a *AgentLight) string {
 	return line
 }
 
+// agentStatus returns the status shown on an agent's line, and its style:
+// warning states first, then compaction and startup, then the work bead
+// and tool, then the agent's own status text.
+func agentStatus(a *AgentLight) (string, lipgloss.Style) {
+	var statusStr string
+	var stStyle lipgloss.Style
+
+	// Build work bead context — this is the primary info for the agent line.
+	// Shows bead ID, title, and molecule step progress when available.
+	var beadCtx string
+	if a.WorkBeadID != "" {
+		beadCtx = a.WorkBeadID
+		if a.WorkBeadTitle != "" {
+			beadCtx += ": " + a.WorkBeadTitle
+		}
+		if a.StepsTotal > 0 {
+			beadCtx += fmt.Sprintf(" [%d/%d]", a.StepsDone, a.StepsTotal)
+			if a.StepCurrent != "" {
+				beadCtx += " " + a.StepCurrent
+			}
+		}
+	}
+
+	// Priority order:
+	//   1. Warning states (HIT LIMIT, NEEDS HUMAN, LOOPING) — always override
+	//   2. Compacting — transient maintenance, overrides normal work display
+	//   3. Bead context as primary content (tool appended if active)
+	//   4. Active tool alone (no bead info available)
+	//   5. Fallback to StatusText or level-based defaults
+	switch {
+	case a.Level == LevelHitLimit:
+		statusStr = "⚠ HIT LIMIT"
+		if a.LimitResetInfo != "" {
+			statusStr += " · " + resetLabel(a.limitReset, time.Now())
+		}
+		stStyle = statRateLimitedStyle
+	case a.Level == LevelWaitingForHuman:
+		statusStr = "⚠ NEEDS HUMAN"
+		if a.WaitingReason != "" {
+			statusStr += " · " + a.WaitingReason
+		}
+		stStyle = statusWaitingStyle
+	case a.Looping:
+		statusStr = fmt.Sprintf("↻ LOOPING ×%d · %s", a.LoopCount, a.LoopTool)
+		stStyle = statRateLimitedStyle
+	case a.IsCompacting:
+		statusStr = "COMPACTING"
+		stStyle = statusCompactingStyle
+	case a.Level == LevelStarting:
+		statusStr = "starting · " + a.StartupPhase
+		stStyle = statusDimStyle
+	case a.Level == LevelReady:
+		statusStr = "✓ ready for work"
+		if beadCtx != "" {
+			statusStr += " · " + beadCtx
+		}
+		stStyle = lipgloss.NewStyle().Foreground(colorRecent)
+	case beadCtx != "":
+		switch a.Level {
+		case LevelCold:
+			statusStr = "stalled · " + beadCtx
+			stStyle = lipgloss.NewStyle().Foreground(colorCold)
+		case LevelRateLimited:
+			statusStr = "rate limited · " + beadCtx
+			stStyle = lipgloss.NewStyle().Foreground(colorRateLimited)
+		default:
+			statusStr = beadCtx
+			if a.CurrentTool != "" {
+				statusStr += " · ⏺ " + a.CurrentTool
+			}
+			stStyle = statusDimStyle
+		}
+	case a.CurrentTool != "":
+		statusStr = "⏺ " + a.CurrentTool
+		if a.CurrentTask != "" {
+			statusStr = a.CurrentTask + " · " + statusStr
+		}
+		stStyle = statusDimStyle
+	case a.CurrentTask != "" && a.Level != LevelCold:
+		statusStr = a.CurrentTask
+		stStyle = statusDimStyle
+	default:
+		// No bead info — fall back to patrol status, then level/status text
+		switch a.Level {
+		case LevelActive, LevelRecent:
+			if a.StatusText != "" {
+				statusStr = a.StatusText
+			} else if a.LastPatrol != "" {
+				statusStr = a.LastPatrol
+			}
+			stStyle = statusDimStyle
+		case LevelWarm, LevelCool:
+			if a.StatusText != "" {
+				statusStr = a.StatusText
+			} else if a.LastPatrol != "" {
+				statusStr = a.LastPatrol
+			} else {
+				statusStr = "idle"
+			}
+			stStyle = statusDimStyle
+		case LevelCold:
+			if a.LastPatrol != "" {
+				statusStr = "stalled · " + a.LastPatrol
+			} else {
+				statusStr = "stalled"
+			}
+			stStyle = lipgloss.NewStyle().Foreground(colorCold)
+		case LevelRateLimited:
+			if a.LastPatrol != "" {
+				statusStr = "rate limited · " + a.LastPatrol
+			} else {
+				statusStr = "rate limited"
+			}
+			stStyle = lipgloss.NewStyle().Foreground(colorRateLimited)
+		}
+	}
+	return statusStr, stStyle
+}
+
 // renderBar renders the activity dot indicator for an agent.
 func (m *Model) renderBar(a *AgentLight) string {
+	led := m.led()
+
+	// Muted or scheduled off: a steady dim light, nothing calling for attention
+	if m.muted(a) || a.showsScheduledOff() {
+		return barColdStyle.Render(led.cold)
+	}
+
 	// Compacting overrides level-based bar — steady purple dot
 	if a.IsCompacting {
-		return barCompactingStyle.Render(dotActive)
+		return barCompactingStyle.Render(led.active)
+	}
+
+	// Looping agent: blinking retry glyph unless a more urgent level applies
+	if a.Looping && a.Level != LevelWaitingForHuman && a.Level != LevelHitLimit {
+		if m.lit(false) {
+			return barRateLimitedStyle.Render("↻")
+		}
+		return barRateLimitedStyle.Render(led.warm)
+	}
+
+	// Coloring by rig: the rig's hue, brightness by level
+	if bar, ok := m.renderRigLight(a, m.levelGlyph(a.Level)); ok {
+		return bar
 	}
 
 	switch a.Level {
 	case LevelActive:
 		// Blink between bright and dim for active agents
-		if m.blinkOn {
-			return barActiveStyle.Render(dotActive)
+		if m.lit(false) {
+			return barActiveStyle.Render(led.active)
 		}
-		return barActiveDimStyle.Render(dotActive)
+		return barActiveDimStyle.Render(led.active)
 
 	case LevelRecent:
-		return barRecentStyle.Render(dotActive)
+		return barRecentStyle.Render(led.recent)
 
 	case LevelWarm:
-		return barWarmStyle.Render(dotIdle)
+		return barWarmStyle.Render(led.warm)
 
 	case LevelCool:
-		return barCoolStyle.Render(dotIdle)
+		return barCoolStyle.Render(led.cool)
 
 	case LevelCold:
-		return barColdStyle.Render(dotCold)
+		return barColdStyle.Render(led.cold)
 
 	case LevelRateLimited:
 		// Blink for rate-limited
-		if m.blinkOn {
-			return barRateLimitedStyle.Render(dotActive)
+		if m.lit(false) {
+			return barRateLimitedStyle.Render(led.active)
 		}
-		return barRateLimitedStyle.Render(dotIdle)
+		return barRateLimitedStyle.Render(led.warm)
 
 	case LevelHitLimit:
 		// Alarm blink — agent is dead until limit resets
-		if m.blinkOn {
-			return barRateLimitedStyle.Render("‼")
+		if m.lit(true) {
+			return barRateLimitedStyle.Render(led.alarm)
+		}
+		return barColdStyle.Render(led.cold)
+
+	case LevelStarting:
+		if m.lit(false) {
+			return barRecentStyle.Render(dotStartingA)
 		}
-		return barColdStyle.Render(dotCold)
+		return barRecentStyle.Render(dotStartingB)
+
+	case LevelReady:
+		// Steady: nothing is happening, and nothing is wrong
+		return barRecentStyle.Render(led.warm)
 
 	case LevelWaitingForHuman:
 		// RED alarm blink — this agent needs you
-		if m.blinkOn {
-			return barWaitingStyle.Render("‼")
+		if m.lit(true) {
+			return barWaitingStyle.Render(led.alarm)
 		}
-		return barWaitingDimStyle.Render(dotIdle)
+		return barWaitingDimStyle.Render(led.warm)
 
 	default:
-		return barColdStyle.Render(dotCold)
+		return barColdStyle.Render(led.cold)
 	}
 }
 
@@ -553,8 +758,22 @@ func (m *Model) renderStats() string {
 	// Hit-limit count - second most important (agents are dead)
 	if m.hitLimitCount > 0 {
 		label := fmt.Sprintf("⚠ %d HIT LIMIT", m.hitLimitCount)
+		if next := m.renderNextResets(3); next != "" {
+			label += " · " + next
+		}
 		parts = append(parts, statRateLimitedStyle.Render(label))
 	}
+	// Looping agents are burning tokens without progress
+	if m.loopingCount > 0 {
+		label := fmt.Sprintf("↻ %d looping", m.loopingCount)
+		parts = append(parts, statRateLimitedStyle.Render(label))
+	}
+	if m.startingCount > 0 {
+		parts = append(parts, statRecentStyle.Render(fmt.Sprintf("%d starting", m.startingCount)))
+	}
+	if m.readyCount > 0 {
+		parts = append(parts, statRecentStyle.Render(fmt.Sprintf("%d ready", m.readyCount)))
+	}
 	if m.activeCount > 0 {
 		parts = append(parts, statActiveStyle.Render(fmt.Sprintf("%d active", m.activeCount)))
 	}
@@ -570,20 +789,53 @@ func (m *Model) renderStats() string {
 	if m.stuckCount > 0 {
 		parts = append(parts, statColdStyle.Render(fmt.Sprintf("%d stuck", m.stuckCount)))
 	}
+	if nudges := m.nudgeSummary(); nudges != "" {
+		parts = append(parts, statusDimStyle.Render(nudges))
+	}
+	// Live spend dial: summed per-agent token rates
+	if m.tokensPerMin > 0 {
+		parts = append(parts, statusDimStyle.Render("~"+formatTokenRate(m.tokensPerMin)))
+	}
 
 	return "  " + strings.Join(parts, "  •  ")
 }
 
 // renderRigWithPositions renders a rig and tracks agent Y positions for hover detection.
 // Each agent gets its own line to show status text and elapsed time.
+// Pinned agents are shown in the pinned section instead.
 func (m *Model) renderRigWithPositions(rig string, currentY *int) string {
-	agents := m.agentsForRig(rig)
+	header := m.renderRigHeader(rig)
+	if badges := renderSLABadges(m.rigSLAs[rig]); badges != "" {
+		header += "  " + badges
+	}
+	if badges := renderSLOBadges(m.rigSLOs[rig]); badges != "" {
+		header += "  " + badges
+	}
+	if rig == "hq" {
+		if badges := m.renderHQBadges(time.Now()); badges != "" {
+			header += "  " + badges
+		}
+	}
+	if chart := m.renderRigHistory(rig, time.Now()); chart != "" {
+		header += "\n" + chart
+	}
+	out := m.renderPanelWithPositions(header, m.panelAgents(rig), currentY)
+	if footer := m.renderRepoFooter(rig); footer != "" && out != "" {
+		out += "\n" + footer
+		*currentY++
+	}
+	return out
+}
+
+// renderPanelWithPositions renders a bordered panel of agent lines under a
+// header, recording each agent's screen position for hover detection.
+func (m *Model) renderPanelWithPositions(header string, agents []*AgentLight, currentY *int) string {
 	if len(agents) == 0 {
 		return ""
 	}
 
-	// Header takes 1 line
-	*currentY++
+	// Header takes 1 line, more with a rig history chart under it
+	*currentY += lipgloss.Height(header)
 
 	var lines []string
 	*currentY++ // Border top line (╭──...──╮); first agent is next row
@@ -592,14 +844,17 @@ func (m *Model) renderRigWithPositions(rig string, currentY *int) string {
 		*currentY++
 		a.renderY = *currentY
 		a.renderHeight = 1
+		a.renderX = m.colX
+		a.renderWidth = m.colW
+		if a.placeholder {
+			lines = append(lines, m.renderPlaceholder(a))
+			continue
+		}
 		lines = append(lines, m.renderLight(a))
 	}
 
 	content := strings.Join(lines, "\n")
 
-	// Rig header
-	header := rigHeaderStyle.Render(rig)
-
 	// Determine border color based on most active agent
 	bestLevel := LevelCold
 	for _, a := range agents {
@@ -644,8 +899,11 @@ func (m *Model) renderRigWithPositions(rig string, currentY *int) string {
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(borderColor).
 		Padding(0, 1)
+	if m.tourTargets(tourPanel) && agents[0] == m.tourAgentLight() {
+		style = style.Border(lipgloss.ThickBorder()).BorderForeground(colorTitle)
+	}
 
-	maxW := m.width - 6
+	maxW := m.layoutWidth() - 6
 	if maxW < 25 {
 		maxW = 25
 	}
@@ -658,17 +916,35 @@ func (m *Model) renderRigWithPositions(rig string, currentY *int) string {
 // place of the help text. Only shows info NOT already visible on the agent line
 // (which already shows: status/tool, elapsed, context %, session limit %, critical states).
 func (m *Model) renderHoverDetail() string {
-	a := m.hoveredAgent
+	a := m.display(m.hoveredAgent)
 	if a == nil {
 		return m.renderHelp()
 	}
+	if a.placeholder {
+		return m.placeholderDetail(a)
+	}
 
 	var parts []string
 	parts = append(parts, lipgloss.NewStyle().Bold(true).Render(a.Icon+" "+a.SessionName))
 
 	// Show agent type for non-Claude agents (Claude is the default, so showing it is noise)
 	if a.AgentType != "" && a.AgentType != "claude" {
-		parts = append(parts, statusDimStyle.Render("agent: "+a.AgentType))
+		parts = append(parts, statusDimStyle.Render(strings.TrimSpace("agent: "+a.AgentType+" "+a.AgentVersion)))
+	}
+
+	// Multi-pane session: which pane the status comes from
+	if s := a.paneSummary(); s != "" {
+		parts = append(parts, statusDimStyle.Render(s))
+	}
+
+	// Container agent: attach and peek go through docker
+	if s := a.containerSummary(); s != "" {
+		parts = append(parts, statusDimStyle.Render(s))
+	}
+
+	// Wedged session: its status is from the last capture that came back
+	if s := a.captureSummary(time.Now()); s != "" {
+		parts = append(parts, statRateLimitedStyle.Render(s))
 	}
 
 	// Work assignment from beads DB
@@ -687,9 +963,26 @@ func (m *Model) renderHoverDetail() string {
 		parts = append(parts, workInfo)
 	}
 
+	// Convoy or epic the work belongs to, with its progress
+	if id := a.groupID(); id != "" {
+		if g := m.groups[id]; g != nil {
+			parts = append(parts, statusDimStyle.Render(m.workHeader(g)))
+		} else {
+			parts = append(parts, statusDimStyle.Render("convoy "+id))
+		}
+	}
+
+	// Current todo from the transcript — the agent line drops it behind bead context
+	if a.CurrentTask != "" && a.WorkBeadID != "" {
+		parts = append(parts, statusDimStyle.Render("task: "+a.CurrentTask))
+	}
+
 	// Session limit reset time — the % is on the agent line, but reset info is only here
 	if a.SessionLimitPct > 0 && a.SessionLimitReset != "" {
-		limitInfo := fmt.Sprintf("limit resets %s", a.SessionLimitReset)
+		limitInfo := "limit " + resetLabel(a.sessionReset, time.Now())
+		if !a.sessionReset.at.IsZero() {
+			limitInfo += " · " + strings.TrimPrefix(a.SessionLimitReset, "resets ")
+		}
 		var limitStyle lipgloss.Style
 		if a.SessionLimitPct >= 90 {
 			limitStyle = lipgloss.NewStyle().Foreground(colorWaiting)
@@ -699,6 +992,66 @@ func (m *Model) renderHoverDetail() string {
 		parts = append(parts, limitStyle.Render(limitInfo))
 	}
 
+	// Usage cap: the agent line shows the countdown, this shows the raw message
+	if a.Level == LevelHitLimit && a.LimitResetInfo != "" {
+		parts = append(parts, lipgloss.NewStyle().Foreground(colorRateLimited).Render("cap "+a.LimitResetInfo))
+	}
+
+	// Spend rate — the agent line drops it when space is tight
+	if a.TokensPerMin > 0 {
+		parts = append(parts, statusDimStyle.Render("spend ~"+formatTokenRate(a.TokensPerMin)))
+	}
+
+	// Deacon heartbeat or scheduler backlog (hq agents only)
+	if detail := m.hqDetail(a, time.Now()); detail != "" {
+		parts = append(parts, statusDimStyle.Render(detail))
+	}
+
+	// Alert ladder progress (top.alerts)
+	if stage := m.alertStage(a); stage != "" {
+		parts = append(parts, lipgloss.NewStyle().Foreground(colorWaiting).Render(stage))
+	}
+
+	// Most recent tool failure — the badge on the agent line only shows the count
+	if a.ToolErrorCount > 0 && a.LastToolError != "" {
+		parts = append(parts, lipgloss.NewStyle().Foreground(colorWaiting).Render("last error: "+a.LastToolError))
+	}
+
+	// Who this agent messaged (or was messaged by) in the last commWindow
+	if edges := m.commEdgesFor(a); len(edges) > 0 {
+		parts = append(parts, renderCommEdges(edges))
+	}
+
+	// Witness nudges today, and whether the agent answered them
+	if nudges := m.nudgeDetail(a); nudges != "" {
+		parts = append(parts, statusDimStyle.Render(nudges))
+	}
+
+	// Last push and PR, for work ready for review
+	if push := pushDetail(a); push != "" {
+		parts = append(parts, statusDimStyle.Render(push))
+	}
+
+	// Branch waiting in the rig's merge queue
+	if queued := mergeQueueDetail(a, time.Now()); queued != "" {
+		parts = append(parts, statusDimStyle.Render(queued))
+	}
+
+	// Mute, and who set it
+	if mute := m.muteDetail(a, time.Now()); mute != "" {
+		parts = append(parts, statusDimStyle.Render(mute))
+	}
+
+	// Open top.offline window, and when it closes
+	if sched := m.scheduleDetail(a, time.Now()); sched != "" {
+		parts = append(parts, statusDimStyle.Render(sched))
+	}
+
+	// Last human attach or input — who has looked at this agent lately
+	if touch := m.humanTouchLabel(a, time.Now()); touch != "" {
+		parts = append(parts, statusDimStyle.Render(touch))
+	}
+
 	// Session uptime — helps spot spontaneous restarts
 	if !a.SessionCreated.IsZero() {
 		uptime := time.Since(a.SessionCreated)
@@ -761,6 +1114,23 @@ func renderContextIndicator(percent int, compact bool, tokenCount int, sessionCr
 	return style.Render(text)
 }
 
+// renderToolErrorBadge returns the recent-error badge for agents whose last
+// tool calls failed. A single failure is common and self-corrected; repeated
+// failures usually mean the agent is stuck retrying, so those get the red style.
+func renderToolErrorBadge(count int, compact bool) string {
+	if count <= 0 {
+		return ""
+	}
+	text := fmt.Sprintf("✗%d", count)
+	if !compact {
+		text += " err"
+	}
+	if count >= 3 {
+		return statusWaitingStyle.Render(text)
+	}
+	return lipgloss.NewStyle().Foreground(colorRateLimited).Render(text)
+}
+
 // renderSessionLimitIndicator returns a compact text indicator for session usage limit.
 func renderSessionLimitIndicator(pct int, resetInfo string) string {
 	if pct <= 0 {
@@ -809,7 +1179,7 @@ func formatElapsed(d time.Duration) string {
 
 // renderHelp renders the help bar.
 func (m *Model) renderHelp() string {
-	return helpStyle.Render("  q: quit  •  double-click: attach  •  ⚠ = needs human")
+	return helpStyle.Render("  q: quit  •  F1: tour  •  ↑/↓ j/k: select agent (enter: attach, esc: clear)  •  f: pin hovered  •  a: ack hovered  •  m: mute hovered  •  R: restart hovered  •  S: start placeholder  •  H: color by rig  •  g: group by convoy  •  b: burn-down  •  v: compare two  •  ctrl+f: find agent  •  /: filter  •  i: attention inbox  •  M: macros  •  T: town control  •  y/Y/c: copy session, attach, bead  •  r/F5: refresh hovered  •  $/s: /cost, /status  •  double-click: attach (alt: always)  •  ⚠ = needs human  •  ↻ = looping")
 }
 
 // activeFlash returns the current flash message if it's still within its display window (3s).
@@ -0,0 +1,111 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// WakeChannel is the tmux wait-for channel gt top listens on. Signalling it
+// (tmux wait-for -S gt-top-wake) makes every running gt top re-poll at once
+// instead of waiting for the next tick.
+const WakeChannel = "gt-top-wake"
+
+// wakeDebounce is the minimum gap between wake-triggered polls. Agents emit
+// tool events in bursts; one poll per burst is enough to light the LED.
+const wakeDebounce = 250 * time.Millisecond
+
+// wakeWaitTimeout bounds each blocking wait-for so the tmux client never
+// outlives gt top by more than this; the wait is simply re-armed on timeout.
+const wakeWaitTimeout = 30 * time.Second
+
+// idlePollMultiplier stretches the poll interval while nothing is active and
+// wake signals are available, since activity will wake the poller anyway.
+const idlePollMultiplier = 3
+
+// wakingAgentTypes are the agent types that signal WakeChannel when they
+// start working: the OpenCode plugin reports through gt top emit. Claude
+// Code's hooks don't, so while a Claude agent runs the poll keeps its base
+// interval, or the agent going active would show up to a stretched tick
+// late.
+var wakingAgentTypes = map[string]bool{"opencode": true}
+
+// wakeMsg is delivered when the wake channel fires or the wait times out.
+type wakeMsg struct {
+	signalled bool // false on timeout
+	err       error
+}
+
+// SignalWake wakes any gt top instances waiting on WakeChannel.
+// Best-effort: errors (no tmux server, old tmux) are ignored.
+func SignalWake() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = tmux.BuildCommandContext(ctx, "wait-for", "-S", WakeChannel).Run()
+}
+
+// waitForWake blocks on the wake channel in a background command.
+func waitForWake() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), wakeWaitTimeout)
+		defer cancel()
+		err := tmux.BuildCommandContext(ctx, "wait-for", WakeChannel).Run()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return wakeMsg{}
+		}
+		if err != nil {
+			return wakeMsg{err: err}
+		}
+		return wakeMsg{signalled: true}
+	}
+}
+
+// handleWake re-arms the wait and schedules a poll if the wake arrived
+// between polls. A wake during an in-flight poll is remembered and served
+// right after that poll completes.
+func (m *Model) handleWake(msg wakeMsg) tea.Cmd {
+	if msg.err != nil {
+		// tmux server gone or wait-for unsupported: fall back to plain ticks.
+		m.wakeLive = false
+		return nil
+	}
+	m.wakeLive = true
+	rearm := waitForWake()
+	if !msg.signalled || m.frames.slow {
+		// Over the frame budget, wakes would undo the poll backoff; the next
+		// regular tick picks the activity up.
+		return rearm
+	}
+	if m.polling {
+		m.wakePending = true
+		return rearm
+	}
+	return tea.Batch(rearm, m.pollTickAfter(wakeDebounce-time.Since(m.lastPoll)))
+}
+
+// nextPollDelay returns how long to wait before the next regular poll,
+// stretched while poll+render cycles run over budget.
+func (m *Model) nextPollDelay() time.Duration {
+	if m.wakePending {
+		m.wakePending = false
+		return wakeDebounce - time.Since(m.lastPoll)
+	}
+	if m.wakeLive && m.activeCount == 0 && m.recentCount == 0 && m.agentsSignalWake() {
+		return m.pollInterval * idlePollMultiplier * m.pollBackoff()
+	}
+	return m.pollInterval * m.pollBackoff()
+}
+
+// agentsSignalWake reports whether every running agent wakes gt top when it
+// starts working, so an idle town can be polled less often.
+func (m *Model) agentsSignalWake() bool {
+	for _, a := range m.agents {
+		if !wakingAgentTypes[a.AgentType] {
+			return false
+		}
+	}
+	return true
+}
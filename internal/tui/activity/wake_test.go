@@ -0,0 +1,99 @@
+package activity
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHandleWake_PollsWhenIdle(t *testing.T) {
+	m := &Model{pollInterval: 3 * time.Second, lastPoll: time.Now().Add(-time.Second)}
+	seq := m.pollSeq
+	if cmd := m.handleWake(wakeMsg{signalled: true}); cmd == nil {
+		t.Fatal("expected re-arm and poll commands")
+	}
+	if !m.wakeLive {
+		t.Error("wakeLive should be set after a successful wait")
+	}
+	if m.pollSeq != seq+1 {
+		t.Errorf("pollSeq = %d, want %d (wake should supersede the pending tick)", m.pollSeq, seq+1)
+	}
+}
+
+func TestHandleWake_DefersDuringPoll(t *testing.T) {
+	m := &Model{pollInterval: 3 * time.Second, polling: true}
+	seq := m.pollSeq
+	m.handleWake(wakeMsg{signalled: true})
+	if !m.wakePending {
+		t.Error("wake during an in-flight poll should be remembered")
+	}
+	if m.pollSeq != seq {
+		t.Error("no tick should be scheduled while a poll is in flight")
+	}
+
+	m.lastPoll = time.Now()
+	if d := m.nextPollDelay(); d > wakeDebounce {
+		t.Errorf("nextPollDelay after pending wake = %v, want <= %v", d, wakeDebounce)
+	}
+	if m.wakePending {
+		t.Error("wakePending should be consumed by nextPollDelay")
+	}
+}
+
+func TestHandleWake_TimeoutRearmsWithoutPolling(t *testing.T) {
+	m := &Model{pollInterval: 3 * time.Second}
+	seq := m.pollSeq
+	if cmd := m.handleWake(wakeMsg{}); cmd == nil {
+		t.Fatal("timeout should re-arm the wait")
+	}
+	if m.pollSeq != seq {
+		t.Error("timeout should not schedule a poll")
+	}
+}
+
+func TestHandleWake_ErrorFallsBackToTicks(t *testing.T) {
+	m := &Model{pollInterval: 3 * time.Second, wakeLive: true}
+	if cmd := m.handleWake(wakeMsg{err: errors.New("no server running")}); cmd != nil {
+		t.Error("a failing wait-for should not be re-armed")
+	}
+	if m.wakeLive {
+		t.Error("wakeLive should be cleared on error")
+	}
+	if d := m.nextPollDelay(); d != m.pollInterval {
+		t.Errorf("nextPollDelay without wake = %v, want %v", d, m.pollInterval)
+	}
+}
+
+func TestNextPollDelay_SlowsWhenIdle(t *testing.T) {
+	m := &Model{pollInterval: time.Second, wakeLive: true}
+	if d := m.nextPollDelay(); d != idlePollMultiplier*time.Second {
+		t.Errorf("idle delay = %v, want %v", d, idlePollMultiplier*time.Second)
+	}
+	m.agents = []*AgentLight{{SessionName: "gt-gastown-Toast", AgentType: "opencode"}}
+	if d := m.nextPollDelay(); d != idlePollMultiplier*time.Second {
+		t.Errorf("idle OpenCode delay = %v, want %v", d, idlePollMultiplier*time.Second)
+	}
+	m.activeCount = 1
+	if d := m.nextPollDelay(); d != time.Second {
+		t.Errorf("active delay = %v, want 1s", d)
+	}
+
+	// Claude Code never signals a wake: idle or not, keep the base interval.
+	m.activeCount = 0
+	m.agents = append(m.agents, &AgentLight{SessionName: "gt-gastown-crew-joe", AgentType: "claude"})
+	if d := m.nextPollDelay(); d != time.Second {
+		t.Errorf("idle delay with a Claude agent = %v, want 1s", d)
+	}
+}
+
+func TestUpdate_DropsStalePollTick(t *testing.T) {
+	m := &Model{pollInterval: time.Second}
+	m.pollTickAfter(time.Second)
+	m.pollTickAfter(0) // wake supersedes
+	if _, cmd := m.Update(pollMsg{seq: m.pollSeq - 1}); cmd != nil {
+		t.Error("stale poll tick should be ignored")
+	}
+	if m.polling {
+		t.Error("stale tick must not start a poll")
+	}
+}
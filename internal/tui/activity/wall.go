@@ -0,0 +1,277 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Wall mode (gt wall): gt top for a display on the wall, read from across
+// the room and never touched. Each page is one rig's agents as a grid of
+// large lights, scaled up to fill the screen, with the agent's name and
+// state under each; the pages rotate every few seconds. There's no help
+// line, hover or mouse, and no screensaver or idle exit. The only keys
+// are ←/→ to turn the page and q to quit. gt wall restarts the display on
+// any error rather than exit (internal/cmd/wall.go).
+
+// Wall tile geometry at scale 1: the light is wallLightW×wallLightH cells,
+// and a tile adds a name line, a state line and a gap.
+const (
+	wallLightW = 6
+	wallLightH = 2
+	wallGapW   = 4
+	wallTextH  = 3
+	wallScales = 3 // largest scale tried
+)
+
+var (
+	wallNameStyle  = lipgloss.NewStyle().Bold(true)
+	wallStateStyle = lipgloss.NewStyle().Foreground(colorDim)
+	wallDotStyle   = lipgloss.NewStyle().Foreground(colorTitle)
+)
+
+// wallState is the wall display's page and rotation.
+type wallState struct {
+	rotate time.Duration // 0 = pages only turn on a key
+	page   int           // index into wallPages, modulo their count
+	seq    int           // rotation ticks with another seq are stale
+}
+
+// wallRotateMsg turns the wall to its next page.
+type wallRotateMsg struct{ seq int }
+
+// wallPage is one page of the wall: a rig's agents, or part of them when
+// they don't fit one screen.
+type wallPage struct {
+	rig         string
+	agents      []*AgentLight
+	part, parts int
+}
+
+// EnableWall switches the model to wall mode, rotating between rig pages
+// every rotate (0: only on a key). Input never comes, so the screensaver
+// and idle exit are off.
+func (m *Model) EnableWall(rotate time.Duration) {
+	m.wall = &wallState{rotate: rotate}
+	m.idle = idleConfig{}
+}
+
+// wallTick schedules the next page turn, or nil when pages don't rotate.
+func (m *Model) wallTick() tea.Cmd {
+	if m.wall.rotate <= 0 {
+		return nil
+	}
+	seq := m.wall.seq
+	return tea.Tick(m.wall.rotate, func(time.Time) tea.Msg { return wallRotateMsg{seq} })
+}
+
+// rotateWall turns to the next page on the rotation tick.
+func (m *Model) rotateWall(msg wallRotateMsg) tea.Cmd {
+	if msg.seq != m.wall.seq {
+		return nil // a key turned the page since; its tick is running
+	}
+	m.wall.page++
+	return m.wallTick()
+}
+
+// handleWallKey handles a key in wall mode. Turning the page by hand
+// restarts the rotation timer.
+func (m *Model) handleWallKey(key string) tea.Cmd {
+	switch key {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "right", "l", "n", " ":
+		m.wall.page++
+	case "left", "h", "p":
+		m.wall.page--
+	default:
+		return nil
+	}
+	m.wall.seq++
+	return m.wallTick()
+}
+
+// wallTile returns a tile's width and height at a scale.
+func wallTile(scale int) (w, h int) {
+	return wallLightW*scale + wallGapW, wallLightH*scale + wallTextH
+}
+
+// wallCapacity returns how many tiles fit the wall's body at a scale.
+func (m *Model) wallCapacity(scale, bodyH int) int {
+	w, h := wallTile(scale)
+	return (m.width / w) * (bodyH / h)
+}
+
+// wallPages splits the rigs' agents and placeholders into pages, in rig
+// order, a rig over several pages when it doesn't fit one at scale 1.
+func (m *Model) wallPages(bodyH int) []wallPage {
+	per := max(m.wallCapacity(1, bodyH), 1)
+	var pages []wallPage
+	for _, rig := range m.rigs {
+		agents := append(m.agentsForRig(rig), m.placeholdersForRig(rig)...)
+		parts := (len(agents) + per - 1) / per
+		for i := 0; i < parts; i++ {
+			pages = append(pages, wallPage{rig: rig, agents: agents[i*per : min((i+1)*per, len(agents))], part: i + 1, parts: parts})
+		}
+	}
+	return pages
+}
+
+// renderWall renders the wall: a header, the current page's tiles centered
+// at the largest scale that fits, and a footer with any warnings and the
+// page dots.
+func (m *Model) renderWall(now time.Time) string {
+	if m.width == 0 || m.height == 0 {
+		return ""
+	}
+	var footer []string
+	for _, banner := range []string{m.renderTmuxStallBanner(), m.renderDegradedBanner(), m.renderDegradedFooter()} {
+		if banner != "" {
+			footer = append(footer, banner)
+		}
+	}
+	bodyH := max(m.height-2-len(footer)-1, 1) // header and gap above, dots below
+
+	pages := m.wallPages(bodyH)
+	var body, title string
+	if len(pages) == 0 {
+		body = wallStateStyle.Render("no agents running")
+	} else {
+		m.wall.page = (m.wall.page%len(pages) + len(pages)) % len(pages)
+		page := pages[m.wall.page]
+		title = page.rig
+		if page.parts > 1 {
+			title += fmt.Sprintf(" (%d/%d)", page.part, page.parts)
+		}
+		body = m.renderWallPage(page, bodyH)
+	}
+
+	header := titleStyle.Render(m.townTitle())
+	if title != "" {
+		header += "  " + m.renderRigHeader(title)
+	}
+	right := statusDimStyle.Render(now.Format("15:04"))
+	if score := m.renderScore(); score != "" {
+		right = score + "  " + right
+	}
+	if gap := m.width - lipgloss.Width(header) - lipgloss.Width(right) - 2; gap > 0 {
+		header = " " + header + strings.Repeat(" ", gap) + right
+	}
+
+	lines := []string{header, "", lipgloss.Place(m.width, bodyH, lipgloss.Center, lipgloss.Center, body)}
+	lines = append(lines, footer...)
+	lines = append(lines, lipgloss.PlaceHorizontal(m.width, lipgloss.Center, m.renderWallDots(len(pages))))
+	return strings.Join(lines, "\n")
+}
+
+// renderWallPage lays a page's tiles out in a grid at the largest scale
+// that fits them.
+func (m *Model) renderWallPage(page wallPage, bodyH int) string {
+	scale := 1
+	for s := wallScales; s > 1; s-- {
+		if m.wallCapacity(s, bodyH) >= len(page.agents) {
+			scale = s
+			break
+		}
+	}
+	tileW, _ := wallTile(scale)
+	cols := max(min(m.width/tileW, len(page.agents)), 1)
+	var rows []string
+	for i := 0; i < len(page.agents); i += cols {
+		var tiles []string
+		for _, a := range page.agents[i:min(i+cols, len(page.agents))] {
+			tiles = append(tiles, m.renderWallTile(a, scale))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, tiles...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// renderWallTile renders one agent as a large light over its name and
+// state.
+func (m *Model) renderWallTile(a *AgentLight, scale int) string {
+	color, blinks, alarm := wallLight(m, a)
+	glyph := "█"
+	if blinks && !m.lit(alarm) {
+		glyph = "▒"
+	}
+	lightW := wallLightW * scale
+	row := lipgloss.NewStyle().Foreground(color).Render(strings.Repeat(glyph, lightW))
+	lines := make([]string, 0, wallLightH*scale+2)
+	for i := 0; i < wallLightH*scale; i++ {
+		lines = append(lines, row)
+	}
+	lines = append(lines,
+		wallNameStyle.Render(truncateWidth(a.Name, lightW, "~")),
+		wallStateStyle.Render(truncateWidth(wallStateLabel(m, a), lightW+wallGapW-1, "…")))
+	tileW, tileH := wallTile(scale)
+	return lipgloss.NewStyle().Width(tileW).Height(tileH).Render(strings.Join(lines, "\n"))
+}
+
+// wallLight returns the color of an agent's wall light, whether it blinks,
+// and whether it blinks as an alarm (even when calm).
+func wallLight(m *Model, a *AgentLight) (color lipgloss.AdaptiveColor, blinks, alarm bool) {
+	switch {
+	case a.placeholder, m.muted(a), a.showsScheduledOff():
+		return colorCold, false, false
+	case a.IsCompacting:
+		return colorCompacting, false, false
+	}
+	switch a.Level {
+	case LevelWaitingForHuman:
+		return colorWaiting, true, true
+	case LevelHitLimit:
+		return colorRateLimited, true, true
+	case LevelRateLimited:
+		return colorRateLimited, true, false
+	case LevelActive:
+		return colorActive, true, false
+	case LevelRecent, LevelStarting, LevelReady:
+		return colorRecent, false, false
+	case LevelWarm:
+		return colorWarm, false, false
+	case LevelCool:
+		return colorCool, false, false
+	}
+	return colorCold, false, false
+}
+
+// wallStateLabel returns the short state shown under a wall light.
+func wallStateLabel(m *Model, a *AgentLight) string {
+	switch {
+	case a.placeholder:
+		return "not running"
+	case m.muted(a):
+		return "muted"
+	case a.showsScheduledOff():
+		return "scheduled off"
+	case a.Level == LevelWaitingForHuman && a.WaitingReason != "":
+		return "⚠ " + a.WaitingReason
+	case a.Level == LevelWaitingForHuman:
+		return "⚠ needs a human"
+	case a.IsCompacting:
+		return "compacting"
+	case a.progressOn:
+		return fmt.Sprintf("%d%% done", a.Progress)
+	}
+	return strings.ReplaceAll(a.Level.String(), "_", " ")
+}
+
+// renderWallDots renders one dot per page, the current one filled, or ""
+// for a single page.
+func (m *Model) renderWallDots(pages int) string {
+	if pages < 2 {
+		return ""
+	}
+	dots := make([]string, pages)
+	for i := range dots {
+		dots[i] = "○"
+		if i == m.wall.page {
+			dots[i] = "●"
+		}
+	}
+	return wallDotStyle.Render(strings.Join(dots, " "))
+}
@@ -0,0 +1,133 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// wallAgents are a rig with a busy crew member and a polecat waiting on a
+// human, and a rig with a cold polecat.
+func wallAgents() []*AgentLight {
+	return []*AgentLight{
+		{SessionName: "gt-gastown-crew-max", Name: "max", Rig: "gastown", Role: "crew", Level: LevelActive},
+		{SessionName: "gt-gastown-Toast", Name: "Toast", Rig: "gastown", Role: "polecat", Level: LevelWaitingForHuman, WaitingReason: "permission"},
+		{SessionName: "gt-beads-Nux", Name: "Nux", Rig: "beads", Role: "polecat", Level: LevelCold},
+	}
+}
+
+// wallOn turns the wall on, the blink in its lit phase.
+func wallOn(m *Model) {
+	m.blinkOn = true
+	m.EnableWall(15 * time.Second)
+}
+
+func TestWallPages(t *testing.T) {
+	m := testModel(wallAgents(), wallOn)
+	pages := m.wallPages(36)
+	if len(pages) != 2 || pages[0].rig != "gastown" || len(pages[0].agents) != 2 || pages[1].rig != "beads" {
+		t.Fatalf("pages = %+v, want gastown (2) then beads (1)", pages)
+	}
+
+	// A rig too big for the screen gets several pages.
+	m.width = 40
+	for i := 0; i < 20; i++ {
+		m.agents = append(m.agents, &AgentLight{Name: fmt.Sprintf("p%02d", i), Rig: "beads", Role: "polecat"})
+	}
+	per := m.wallCapacity(1, 10)
+	pages = m.wallPages(10)
+	if per >= 21 || len(pages) != 1+(21+per-1)/per {
+		t.Fatalf("%d pages at %d a page, want beads split", len(pages), per)
+	}
+	last := pages[len(pages)-1]
+	if last.rig != "beads" || last.part != last.parts || last.parts < 2 {
+		t.Errorf("last page = %s %d/%d", last.rig, last.part, last.parts)
+	}
+}
+
+func TestWallRender(t *testing.T) {
+	m := testModel(wallAgents(), wallOn)
+	out := ansi.Strip(m.View())
+	if lines := strings.Split(out, "\n"); len(lines) != 40 {
+		t.Errorf("wall is %d lines, want the full 40", len(lines))
+	}
+	for _, want := range []string{"gastown", "max", "Toast", "⚠ permission", "██████████████████", "● ○"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("wall missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Nux") || strings.Contains(out, "quit") {
+		t.Errorf("wall shows another rig's agents or help:\n%s", out)
+	}
+
+	// Small screen: scale 1 lights.
+	m.width, m.height = 30, 12
+	if out := ansi.Strip(m.View()); strings.Contains(out, "████████████") || !strings.Contains(out, "██████") {
+		t.Errorf("small wall not drawn at scale 1:\n%s", out)
+	}
+
+	// Off phase of the blink: working and waiting lights dim.
+	m.blinkOn = false
+	if out := ansi.Strip(m.View()); !strings.Contains(out, "▒▒▒▒▒▒") {
+		t.Errorf("blinking lights didn't dim:\n%s", out)
+	}
+}
+
+func TestWallRotation(t *testing.T) {
+	m := testModel(wallAgents(), wallOn)
+	m.View()
+	if cmd := m.rotateWall(wallRotateMsg{seq: 0}); cmd == nil || m.wall.page != 1 {
+		t.Fatalf("rotation tick: page %d, next tick %v", m.wall.page, cmd != nil)
+	}
+	if out := ansi.Strip(m.View()); !strings.Contains(out, "Nux") {
+		t.Errorf("second page doesn't show beads:\n%s", out)
+	}
+	m.View()
+	m.rotateWall(wallRotateMsg{seq: 0})
+	m.View()
+	if m.wall.page != 0 {
+		t.Errorf("page = %d after the last, want back to 0", m.wall.page)
+	}
+
+	// A key turns the page and restarts the timer: the old tick is stale.
+	m.handleWallKey("left")
+	m.View()
+	if m.wall.page != 1 {
+		t.Errorf("← from the first page = %d, want the last", m.wall.page)
+	}
+	if cmd := m.rotateWall(wallRotateMsg{seq: 0}); cmd != nil || m.wall.page != 1 {
+		t.Errorf("stale tick turned the page to %d", m.wall.page)
+	}
+}
+
+func TestWallKeys(t *testing.T) {
+	m := testModel(wallAgents(), wallOn)
+	m.embedded = false
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyEsc}, {Type: tea.KeyRunes, Runes: []rune("?")}, {Type: tea.KeyEnter}} {
+		if _, cmd := m.Update(key); cmd != nil {
+			t.Errorf("%q in wall mode returned a command", key.String())
+		}
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if cmd == nil {
+		t.Fatal("q didn't quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("q didn't quit")
+	}
+}
+
+func TestWallNoAgents(t *testing.T) {
+	m := &Model{width: 80, height: 20}
+	m.EnableWall(0)
+	if cmd := m.wallTick(); cmd != nil {
+		t.Error("--rotate 0 still rotates")
+	}
+	if out := ansi.Strip(m.View()); !strings.Contains(out, "no agents running") {
+		t.Errorf("empty wall:\n%s", out)
+	}
+}
@@ -0,0 +1,43 @@
+package activity
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Width math for names, statuses and pane lines. Terminal columns, not bytes
+// or runes: CJK characters and most emoji take two columns, and a byte
+// index can split a multi-byte character.
+
+// truncateWidth shortens s to at most w columns, ending it with tail when
+// anything was cut. Escape sequences don't count toward the width.
+func truncateWidth(s string, w int, tail string) string {
+	if lipgloss.Width(s) <= w {
+		return s
+	}
+	return ansi.Truncate(s, w, tail)
+}
+
+// padWidth right-pads s with spaces to w columns.
+func padWidth(s string, w int) string {
+	if n := w - lipgloss.Width(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// columnOffset returns the byte offset in s of the character at display
+// column col, or len(s) when s is narrower. A wide character straddling col
+// is left out, so s[:columnOffset(s, col)] never exceeds col columns.
+func columnOffset(s string, col int) int {
+	w := 0
+	for i, r := range s {
+		w += ansi.StringWidth(string(r))
+		if w > col {
+			return i
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,110 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		w    int
+		want string
+	}{
+		{"Toast", 10, "Toast"},
+		{"furiosa-nux-the-great", 10, "furiosa-n~"},
+		{"東京タワー建設計画", 10, "東京タワ~"}, // 2 columns each: 4 fit beside the tail
+		{"🚀🚀🚀🚀🚀🚀", 10, "🚀🚀🚀🚀~"},
+	}
+	for _, tt := range tests {
+		got := truncateWidth(tt.in, tt.w, "~")
+		if got != tt.want {
+			t.Errorf("truncateWidth(%q, %d) = %q, want %q", tt.in, tt.w, got, tt.want)
+		}
+		if w := lipgloss.Width(got); w > tt.w {
+			t.Errorf("truncateWidth(%q, %d) is %d columns", tt.in, tt.w, w)
+		}
+	}
+}
+
+func TestPadWidth(t *testing.T) {
+	for _, name := range []string{"Toast", "東京", "🚀x", ""} {
+		if w := lipgloss.Width(padWidth(name, 10)); w != 10 {
+			t.Errorf("padWidth(%q, 10) is %d columns, want 10", name, w)
+		}
+	}
+	if got := padWidth("already-too-long", 4); got != "already-too-long" {
+		t.Errorf("padWidth shortened its input: %q", got)
+	}
+}
+
+func TestColumnOffset(t *testing.T) {
+	s := "ab東京cd"
+	tests := []struct{ col, want int }{
+		{0, 0},
+		{2, 2},
+		{3, 2}, // 東 spans columns 2-3: left out
+		{4, 5},
+		{6, 8},
+		{20, len(s)},
+	}
+	for _, tt := range tests {
+		if got := columnOffset(s, tt.col); got != tt.want {
+			t.Errorf("columnOffset(%q, %d) = %d, want %d", s, tt.col, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateStatus_KeepsUTF8Valid(t *testing.T) {
+	got := truncateStatus(strings.Repeat("修正", 150))
+	if !utf8.ValidString(got) || lipgloss.Width(got) > 200 || !strings.HasSuffix(got, "...") {
+		t.Errorf("truncateStatus = %q (%d columns)", got, lipgloss.Width(got))
+	}
+}
+
+func TestRenderLight_AlignsWideNames(t *testing.T) {
+	m := &Model{width: 120}
+	var widths []int
+	for _, name := range []string{"Toast", "東京", "🚀rocket", "ジェネレーター作成者"} {
+		a := &AgentLight{SessionName: "gt-gastown-" + name, Name: name, Icon: "😺", Level: LevelCold, LastChangeTime: time.Now()}
+		line := m.renderLight(a)
+		// The status starts right after the fixed-width name column.
+		status, _ := agentStatus(a)
+		idx := strings.Index(line, status)
+		if idx < 0 {
+			t.Fatalf("agent line for %q has no status: %q", name, line)
+		}
+		widths = append(widths, lipgloss.Width(line[:idx]))
+	}
+	for i, w := range widths {
+		if w != widths[0] {
+			t.Errorf("status column = %v, want all equal (name %d misaligned)", widths, i)
+			break
+		}
+	}
+}
+
+func TestExtractAndStripSidebar_WideMainContent(t *testing.T) {
+	// The main content of the first line holds CJK text, which shifts the
+	// sidebar's byte offset but not its column.
+	main := func(s string) string { return padWidth(s, 110) }
+	lines := []string{
+		main("● 東京タワーの修正を実装") + "Context",
+		main("● Working on it") + "[•] Write tests",
+		main("") + "[ ] Ship it",
+	}
+	out, info := extractAndStripSidebar(lines)
+	if info.inProgressTodo != "Write tests" {
+		t.Errorf("in-progress todo = %q, want %q", info.inProgressTodo, "Write tests")
+	}
+	if len(info.pendingTodos) != 1 || info.pendingTodos[0] != "Ship it" {
+		t.Errorf("pending todos = %q", info.pendingTodos)
+	}
+	if out[0] != "● 東京タワーの修正を実装" || out[1] != "● Working on it" {
+		t.Errorf("stripped lines = %q", out)
+	}
+}
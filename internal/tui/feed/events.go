@@ -14,6 +14,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -220,6 +221,8 @@ type GtEventsSource struct {
 	file   *os.File
 	events chan Event
 	cancel context.CancelFunc
+	dedup  events.Dedup     // drops plugin retries that share an idempotency key
+	verify *events.Verifier // drops forged or altered events when the town signs them
 }
 
 // GtEvent is the structure of events in .events.jsonl
@@ -230,6 +233,7 @@ type GtEvent struct {
 	Actor      string                 `json:"actor"`
 	Payload    map[string]interface{} `json:"payload"`
 	Visibility string                 `json:"visibility"`
+	Key        string                 `json:"idempotency_key"`
 }
 
 // NewGtEventsSource creates a source that tails ~/gt/.events.jsonl
@@ -246,6 +250,7 @@ func NewGtEventsSource(townRoot string) (*GtEventsSource, error) {
 		file:   file,
 		events: make(chan Event, 200),
 		cancel: cancel,
+		verify: events.NewVerifier(townRoot),
 	}
 
 	go source.tail(ctx)
@@ -265,7 +270,7 @@ func (s *GtEventsSource) tail(ctx context.Context) {
 	_, _ = s.file.Seek(0, 2)
 
 	// Now tail for new events
-	scanner := bufio.NewScanner(s.file)
+	scanner := events.NewScanner(s.file)
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
@@ -276,7 +281,7 @@ func (s *GtEventsSource) tail(ctx context.Context) {
 		case <-ticker.C:
 			for scanner.Scan() {
 				line := scanner.Text()
-				if event := parseGtEventLine(line); event != nil {
+				if event := parseGtEventLine(line); event != nil && s.verify.Valid([]byte(line)) && !s.dedup.Seen(event.Key) {
 					select {
 					case s.events <- *event:
 					default:
@@ -301,8 +306,7 @@ func (s *GtEventsSource) loadRecentEvents() {
 	idx := 0
 	count := 0
 
-	scanner := bufio.NewScanner(s.file)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	scanner := events.NewScanner(s.file)
 	for scanner.Scan() {
 		ring[idx%maxLines] = scanner.Text()
 		idx++
@@ -322,7 +326,7 @@ func (s *GtEventsSource) loadRecentEvents() {
 	start := idx - n
 	for i := start; i < idx; i++ {
 		line := ring[i%maxLines]
-		if event := parseGtEventLine(line); event != nil {
+		if event := parseGtEventLine(line); event != nil && s.verify.Valid([]byte(line)) && !s.dedup.Seen(event.Key) {
 			select {
 			case s.events <- *event:
 			default:
@@ -342,6 +346,12 @@ func (s *GtEventsSource) Close() error {
 	return s.file.Close()
 }
 
+// newEventDedup returns a reader-side dedup for events with idempotency keys.
+// (PrintGtEvents can't name events.Dedup: its local slice shadows the package.)
+func newEventDedup() *events.Dedup {
+	return &events.Dedup{}
+}
+
 // parseGtEventLine parses a line from .events.jsonl
 func parseGtEventLine(line string) *Event {
 	if strings.TrimSpace(line) == "" {
@@ -416,6 +426,7 @@ func parseGtEventLine(line string) *Event {
 		Rig:     rig,
 		Role:    role,
 		Raw:     line,
+		Key:     ge.Key,
 	}
 }
 
@@ -476,6 +487,35 @@ func buildEventMessage(eventType string, payload map[string]interface{}) string
 		}
 		return "escalation sent"
 
+	case "loop_detected":
+		session := getPayloadString(payload, "session")
+		tool := getPayloadString(payload, "tool")
+		count := getPayloadInt(payload, "count")
+		if session != "" && tool != "" {
+			return fmt.Sprintf("%s looping on %s (×%d)", session, tool, count)
+		}
+		return "agent looping"
+
+	case "limit_hit":
+		session := getPayloadString(payload, "session")
+		kind := strings.ReplaceAll(getPayloadString(payload, "kind"), "_", " ")
+		reset := getPayloadString(payload, "reset")
+		if session != "" && reset != "" {
+			return fmt.Sprintf("%s hit %s (%s)", session, kind, reset)
+		}
+		if session != "" {
+			return fmt.Sprintf("%s hit %s", session, kind)
+		}
+		return "agent hit limit"
+
+	case "provider_outage":
+		provider := getPayloadString(payload, "provider")
+		agents := getPayloadInt(payload, "agents")
+		if getPayloadString(payload, "status") == "resolved" {
+			return fmt.Sprintf("%s API recovered", provider)
+		}
+		return fmt.Sprintf("%s API degraded: %d agents affected", provider, agents)
+
 	case "sling":
 		bead := getPayloadString(payload, "bead")
 		target := getPayloadString(payload, "target")
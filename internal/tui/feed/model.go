@@ -11,6 +11,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
 )
@@ -50,6 +51,7 @@ type Event struct {
 	Rig     string // which rig
 	Role    string // actor's role
 	Raw     string // raw line for fallback display
+	Key     string // idempotency key from .events.jsonl; empty for most events
 }
 
 // Agent represents an agent in the tree
@@ -88,6 +90,7 @@ type Model struct {
 	events      []Event
 	convoyState *ConvoyState
 	townRoot    string
+	eventTypes  *events.Registry // custom event types from town settings, nil if none
 
 	// UI state
 	keys     KeyMap
@@ -113,7 +116,7 @@ type Model struct {
 	closeOnce sync.Once
 
 	// mu protects all fields read by View() from concurrent access:
-	// events, rigs, convoyState, eventChan, townRoot, width, height,
+	// events, rigs, convoyState, eventChan, townRoot, eventTypes, width, height,
 	// focusedPanel, showHelp, help, filter, viewMode, problemAgents,
 	// selectedProblem, selectedBeadID, problemsError, lastProblemsCheck,
 	// and all viewports. Write lock is held during Update/handleKey
@@ -156,8 +159,10 @@ func NewModelWithProblemsView(bd *beads.Beads) *Model {
 // SetTownRoot sets the town root for convoy fetching.
 // Safe to call concurrently with the Bubble Tea event loop.
 func (m *Model) SetTownRoot(townRoot string) {
+	eventTypes := events.LoadRegistry(townRoot)
 	m.mu.Lock()
 	m.townRoot = townRoot
+	m.eventTypes = eventTypes
 	m.mu.Unlock()
 }
 
@@ -1,7 +1,6 @@
 package feed
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"os"
@@ -10,6 +9,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
 )
 
 // PrintOptions controls filtering and behavior for PrintGtEvents.
@@ -27,7 +28,7 @@ type PrintOptions struct {
 // When opts.Follow is true, it tails the file for new events after printing
 // the initial batch, polling every 200ms. Canceled via opts.Ctx or SIGINT.
 func PrintGtEvents(townRoot string, opts PrintOptions) error {
-	eventsPath := filepath.Join(townRoot, ".events.jsonl")
+	eventsPath := filepath.Join(townRoot, events.EventsFile)
 	file, err := os.Open(eventsPath)
 	if err != nil {
 		return fmt.Errorf("no events file found at %s: %w", eventsPath, err)
@@ -44,15 +45,16 @@ func PrintGtEvents(townRoot string, opts PrintOptions) error {
 		sinceTime = time.Now().Add(-dur)
 	}
 
-	var events []Event
-	scanner := bufio.NewScanner(file)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	var found []Event
+	dedup := newEventDedup()
+	verify := events.NewVerifier(townRoot)
+	scanner := events.NewScanner(file)
 
 	for scanner.Scan() {
 		line := scanner.Text()
-		if event := parseGtEventLine(line); event != nil {
+		if event := parseGtEventLine(line); event != nil && verify.Valid([]byte(line)) && !dedup.Seen(event.Key) {
 			if matchesFilters(event, sinceTime, opts.Mol, opts.Type, opts.Rig) {
-				events = append(events, *event)
+				found = append(found, *event)
 			}
 		}
 	}
@@ -62,26 +64,26 @@ func PrintGtEvents(townRoot string, opts PrintOptions) error {
 	}
 
 	// Sort by time descending (most recent first)
-	sort.Slice(events, func(i, j int) bool {
-		return events[i].Time.After(events[j].Time)
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Time.After(found[j].Time)
 	})
 
 	// Apply limit
-	if opts.Limit > 0 && len(events) > opts.Limit {
-		events = events[:opts.Limit]
+	if opts.Limit > 0 && len(found) > opts.Limit {
+		found = found[:opts.Limit]
 	}
 
 	// Reverse to show oldest first (chronological)
-	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
-		events[i], events[j] = events[j], events[i]
+	for i, j := 0, len(found)-1; i < j; i, j = i+1, j-1 {
+		found[i], found[j] = found[j], found[i]
 	}
 
-	if len(events) == 0 && !opts.Follow {
+	if len(found) == 0 && !opts.Follow {
 		fmt.Println("No events found in .events.jsonl")
 		return nil
 	}
 
-	for _, event := range events {
+	for _, event := range found {
 		printEvent(event)
 	}
 
@@ -108,11 +110,10 @@ func PrintGtEvents(townRoot string, opts PrintOptions) error {
 		case <-ctx.Done():
 			return nil
 		case <-ticker.C:
-			s := bufio.NewScanner(file)
-			s.Buffer(make([]byte, 1024*1024), 1024*1024)
+			s := events.NewScanner(file)
 			for s.Scan() {
 				line := s.Text()
-				if event := parseGtEventLine(line); event != nil {
+				if event := parseGtEventLine(line); event != nil && verify.Valid([]byte(line)) && !dedup.Seen(event.Key) {
 					if matchesFilters(event, sinceTime, opts.Mol, opts.Type, opts.Rig) {
 						printEvent(*event)
 					}
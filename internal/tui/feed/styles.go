@@ -176,6 +176,10 @@ var (
 		"polecat_checked": "·",
 		"polecat_nudged":  "⚡",
 		"escalation_sent": "⬆",
+		// Agent health events (from gt top)
+		"loop_detected":   "↻",
+		"limit_hit":       "⏸",
+		"provider_outage": "⚠",
 		// Merge events
 		"merge_started": "⚙",
 		"merged":        "✓",
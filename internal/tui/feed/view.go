@@ -443,7 +443,7 @@ func (m *Model) renderEvent(e Event) string {
 		symbolStyle = EventMergeSkippedStyle
 	case "patrol_started", "polecat_checked":
 		symbolStyle = EventUpdateStyle
-	case "polecat_nudged", "escalation_sent", "nudge":
+	case "polecat_nudged", "escalation_sent", "nudge", "loop_detected", "limit_hit", "provider_outage":
 		symbolStyle = EventFailStyle // Use red/warning style for nudges and escalations
 	case "sling", "hook", "spawn", "boot":
 		symbolStyle = EventCreateStyle
@@ -452,6 +452,14 @@ func (m *Model) renderEvent(e Event) string {
 	default:
 		symbolStyle = EventUpdateStyle
 	}
+	if custom, ok := m.eventTypes.Custom(e.Type); ok {
+		if custom.Icon != "" {
+			symbol = custom.Icon
+		}
+		if custom.Color != "" {
+			symbolStyle = symbolStyle.Foreground(lipgloss.Color(custom.Color))
+		}
+	}
 
 	styledSymbol := symbolStyle.Render(symbol)
 
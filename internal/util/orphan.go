@@ -4,6 +4,7 @@ package util
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -89,7 +90,7 @@ func getTmuxSessionPIDs() map[int]bool {
 
 	// Also query the current town's socket via BuildCommand as a fallback.
 	// This handles non-standard socket locations (e.g. GT_TMUX_SOCKET override).
-	out, err := tmux.BuildCommand("list-panes", "-a", "-F", "#{pane_pid}").Output()
+	out, err := tmux.Output(0, "list-panes", "-a", "-F", "#{pane_pid}")
 	if err == nil {
 		for _, pidStr := range strings.Split(strings.TrimSpace(string(out)), "\n") {
 			if pid, err := strconv.Atoi(pidStr); err == nil && pid > 0 {
@@ -105,7 +106,9 @@ func getTmuxSessionPIDs() map[int]bool {
 // collectPanePIDs queries a single tmux socket for all pane PIDs and adds them
 // (plus their descendant processes) to the protection set.
 func collectPanePIDs(socketPath string, childMap map[int][]int, pids map[int]bool) {
-	out, err := exec.Command("tmux", "-S", socketPath, "list-panes", "-a", "-F", "#{pane_pid}").Output()
+	ctx, cancel := context.WithTimeout(context.Background(), tmux.CommandTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "tmux", "-S", socketPath, "list-panes", "-a", "-F", "#{pane_pid}").Output()
 	if err != nil {
 		return
 	}
@@ -551,7 +554,7 @@ func FindZombieClaudeProcesses() ([]ZombieProcess, error) {
 	// Returning empty is safer than marking all Claude processes as zombies.
 	if len(validPIDs) == 0 {
 		// Check if tmux is even running
-		if err := tmux.BuildCommand("list-sessions").Run(); err != nil {
+		if err := tmux.Run(0, "list-sessions"); err != nil {
 			return nil, fmt.Errorf("tmux not available: %w", err)
 		}
 		// tmux is running but no gt-*/hq-* sessions - that's a valid state,
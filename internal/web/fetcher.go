@@ -18,6 +18,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -1754,9 +1755,10 @@ func (f *LiveConvoyFetcher) FetchIssues() ([]IssueRow, error) {
 	return rows, nil
 }
 
-// FetchActivity returns recent activity from the event log.
+// FetchActivity returns recent activity from the event log. Events that
+// fail signature verification (town settings event_signing) are left out.
 func (f *LiveConvoyFetcher) FetchActivity() ([]ActivityRow, error) {
-	eventsPath := filepath.Join(f.townRoot, ".events.jsonl")
+	eventsPath := filepath.Join(f.townRoot, events.EventsFile)
 
 	// Read events file
 	data, err := os.ReadFile(eventsPath)
@@ -1775,10 +1777,11 @@ func (f *LiveConvoyFetcher) FetchActivity() ([]ActivityRow, error) {
 		start = len(lines) - 50
 	}
 
+	verify := events.NewVerifier(f.townRoot)
 	var rows []ActivityRow
 	for i := len(lines) - 1; i >= start; i-- {
 		line := lines[i]
-		if line == "" {
+		if line == "" || !verify.Valid([]byte(line)) {
 			continue
 		}
 
@@ -1826,7 +1829,8 @@ func eventCategory(eventType string) string {
 	switch eventType {
 	case "spawn", "kill", "session_start", "session_end", "session_death", "mass_death", "nudge", "handoff":
 		return "agent"
-	case "sling", "hook", "unhook", "done", "merge_started", "merged", "merge_failed":
+	case "sling", "hook", "unhook", "done", "merge_started", "merged", "merge_failed",
+		"bead_created", "bead_in_progress", "bead_closed":
 		return "work"
 	case "mail", "escalation_sent", "escalation_acked", "escalation_closed":
 		return "comms"
@@ -1875,6 +1879,9 @@ func eventIcon(eventType string) string {
 		"merge_failed":      "❌",
 		"boot":              "🚀",
 		"halt":              "🛑",
+		"bead_created":      "🆕",
+		"bead_in_progress":  "🔨",
+		"bead_closed":       "📕",
 	}
 	if icon, ok := icons[eventType]; ok {
 		return icon
@@ -1894,6 +1901,10 @@ func eventSummary(eventType, actor string, payload map[string]interface{}) strin
 	case "done":
 		bead, _ := payload["bead"].(string)
 		return fmt.Sprintf("%s completed %s", shortActor, bead)
+	case "bead_created", "bead_in_progress", "bead_closed":
+		bead, _ := payload["bead"].(string)
+		verb := map[string]string{"bead_created": "opened", "bead_in_progress": "started", "bead_closed": "closed"}[eventType]
+		return fmt.Sprintf("%s %s %s", shortActor, verb, bead)
 	case "mail":
 		to, _ := payload["to"].(string)
 		subject, _ := payload["subject"].(string)
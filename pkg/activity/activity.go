@@ -0,0 +1,142 @@
+// Package activity embeds the Gas Town agent monitor (gt top) in other Go
+// programs. A Collector reads agent levels without a UI; Model is a
+// bubbletea component that draws the monitor's panels inside a host TUI.
+//
+// This package is the supported API: its names and the Status shape stay
+// compatible. The monitor itself lives under internal/ and may change.
+package activity
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/steveyegge/gastown/internal/tui/activity"
+)
+
+// AgentStatus is one agent as the monitor sees it. It has the same shape as
+// an entry in the town's status file (<town>/.runtime/top-status.json).
+type AgentStatus = activity.AgentStatus
+
+// Status is a snapshot of every agent and the town's health score.
+type Status = activity.Status
+
+// Sink receives the status after every poll, alongside the monitor's own
+// view: a program can serve or forward it without polling tmux again.
+type Sink = activity.Sink
+
+// LevelNames returns the names AgentStatus.Level can take, from most to
+// least active: "active" through "cold", then "rate_limited", "hit_limit",
+// "waiting" (needs a human), "starting" and "dead".
+func LevelNames() []string {
+	return activity.LevelNames()
+}
+
+// Collector polls a town's agents on demand, the way gt top does on each
+// tick. It is not safe for concurrent use.
+type Collector struct {
+	m *activity.Model
+}
+
+// NewCollector returns a Collector for the town at townRoot. An empty
+// townRoot is found the way gt top finds it: from the working directory,
+// then GT_TOWN_ROOT.
+func NewCollector(townRoot string) *Collector {
+	m := activity.NewModel(0, townRoot)
+	m.SetEmbedded()
+	return &Collector{m: m}
+}
+
+// AddSink feeds s the status after every Collect.
+func (c *Collector) AddSink(s Sink) {
+	c.m.AddSink(s)
+}
+
+// Collect polls tmux and the town and returns every agent's status. Like
+// gt top, it also publishes the town's status file. On a tmux error the
+// agents from the last successful poll are returned with the error.
+func (c *Collector) Collect() (*Status, error) {
+	err := c.m.Poll()
+	s := c.m.Snapshot(time.Now())
+	return &s, err
+}
+
+// Options configures an embedded Model.
+type Options struct {
+	Interval time.Duration // poll interval; zero for gt top's default
+	Rig      string        // show only this rig's agents
+	Role     string        // show only agents in this role
+	Levels   []string      // show only agents at these levels (see LevelNames)
+	Redact   bool          // mask titles, paths and arguments
+	Ticker   bool          // scroll recent town events under the panels
+	Sinks    []Sink        // fed the status after every poll
+}
+
+// Model is the gt top monitor as a bubbletea component. The host forwards
+// messages to Update and places View's output at the bounds it last passed
+// to SetBounds. Quit keys are ignored: the host owns the program.
+type Model struct {
+	m                   *activity.Model
+	x, y, width, height int
+}
+
+// New returns an embeddable monitor for the town at townRoot, found as for
+// NewCollector when empty. It fails on an unknown level in opts.Levels.
+func New(townRoot string, opts Options) (*Model, error) {
+	m := activity.NewModel(opts.Interval, townRoot)
+	m.SetEmbedded()
+	if err := m.SetAgentFilter(opts.Rig, opts.Role, strings.Join(opts.Levels, ",")); err != nil {
+		return nil, err
+	}
+	m.SetRedact(opts.Redact)
+	if opts.Ticker {
+		m.EnableTicker()
+	}
+	for _, s := range opts.Sinks {
+		m.AddSink(s)
+	}
+	return &Model{m: m}, nil
+}
+
+// SetBounds places the monitor in the host's screen: mouse events are
+// translated to its top-left corner at (x, y), and it renders width by
+// height cells. Call it before the first View and on every resize.
+func (c *Model) SetBounds(x, y, width, height int) {
+	c.x, c.y, c.width, c.height = x, y, width, height
+	c.m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+}
+
+// Init starts polling.
+func (c *Model) Init() tea.Cmd {
+	return c.m.Init()
+}
+
+// Update handles a message. Mouse events outside the bounds are dropped, and
+// window size messages are ignored in favor of SetBounds.
+func (c *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		return c, nil
+	case tea.MouseMsg:
+		msg.X -= c.x
+		msg.Y -= c.y
+		if msg.X < 0 || msg.Y < 0 || msg.X >= c.width || msg.Y >= c.height {
+			return c, nil
+		}
+		_, cmd := c.m.Update(msg)
+		return c, cmd
+	}
+	_, cmd := c.m.Update(msg)
+	return c, cmd
+}
+
+// View renders the monitor.
+func (c *Model) View() string {
+	return c.m.View()
+}
+
+// Status returns every agent's status as of the last poll.
+func (c *Model) Status() Status {
+	return c.m.Snapshot(time.Now())
+}
@@ -0,0 +1,51 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestNewRejectsUnknownLevel(t *testing.T) {
+	if _, err := New(t.TempDir(), Options{Levels: []string{"cold", "tepid"}}); err == nil {
+		t.Error("New accepted level \"tepid\"")
+	}
+	if _, err := New(t.TempDir(), Options{Levels: []string{"cold", "waiting"}}); err != nil {
+		t.Errorf("New: %v", err)
+	}
+}
+
+func TestEmbeddedModel(t *testing.T) {
+	c, err := New(t.TempDir(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetBounds(10, 5, 60, 20)
+
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("q")},
+		{Type: tea.KeyEsc},
+		{Type: tea.KeyCtrlC},
+	} {
+		if _, cmd := c.Update(key); cmd != nil {
+			t.Errorf("%s returned a command; quit keys must not reach the host", key)
+		}
+	}
+
+	// The host's own size doesn't override the bounds.
+	c.Update(tea.WindowSizeMsg{Width: 200, Height: 50})
+	for i, line := range strings.Split(c.View(), "\n") {
+		if w := lipgloss.Width(line); w > 60 {
+			t.Errorf("line %d is %d cells wide, want at most 60", i, w)
+		}
+	}
+}
+
+func TestLevelNames(t *testing.T) {
+	names := LevelNames()
+	if names[0] != "active" || names[len(names)-1] != "dead" {
+		t.Errorf("LevelNames() = %v", names)
+	}
+}
@@ -0,0 +1,28 @@
+#!/usr/bin/env bash
+# feed-digest/run.sh — Mail a per-rig summary of the event feed.
+
+set -euo pipefail
+
+SINCE="${GT_DIGEST_SINCE:-24h}"
+FORMAT="${GT_DIGEST_FORMAT:-md}"
+TO="${GT_DIGEST_TO:-overseer}"
+
+log() { echo "[feed-digest] $*"; }
+
+DIGEST=$(gt digest --since "$SINCE" --format "$FORMAT" 2>/dev/null) || {
+  log "SKIP: gt digest failed"
+  exit 0
+}
+
+SUBJECT="Town digest: $(date +%Y-%m-%d)"
+if printf '%s\n' "$DIGEST" | gt mail send "$TO" -s "$SUBJECT" --stdin; then
+  log "Sent digest to $TO"
+  RESULT=success
+else
+  log "Failed to send digest to $TO"
+  RESULT=failure
+fi
+
+bd create "feed-digest: ${SINCE} digest to ${TO}: ${RESULT}" -t chore --ephemeral \
+  -l type:plugin-run,plugin:feed-digest,result:${RESULT} \
+  --silent 2>/dev/null || true